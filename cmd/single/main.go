@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/ZupIT/ritchie-cli/pkg/formula/detach"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/history"
 	"github.com/ZupIT/ritchie-cli/pkg/formula/repo"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runlog"
 	"github.com/ZupIT/ritchie-cli/pkg/formula/runner"
 	"github.com/ZupIT/ritchie-cli/pkg/formula/tree"
 
@@ -23,6 +27,9 @@ import (
 	"github.com/ZupIT/ritchie-cli/pkg/api"
 	"github.com/ZupIT/ritchie-cli/pkg/autocomplete"
 	"github.com/ZupIT/ritchie-cli/pkg/cmd"
+	"github.com/ZupIT/ritchie-cli/pkg/config"
+	"github.com/ZupIT/ritchie-cli/pkg/credential"
+	"github.com/ZupIT/ritchie-cli/pkg/credential/credbackend"
 	"github.com/ZupIT/ritchie-cli/pkg/credential/credsingle"
 	"github.com/ZupIT/ritchie-cli/pkg/env"
 	"github.com/ZupIT/ritchie-cli/pkg/env/envcredential"
@@ -30,6 +37,7 @@ import (
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
 	"github.com/ZupIT/ritchie-cli/pkg/formula/watcher"
 	fworkspace "github.com/ZupIT/ritchie-cli/pkg/formula/workspace"
+	"github.com/ZupIT/ritchie-cli/pkg/http/httpclient"
 	"github.com/ZupIT/ritchie-cli/pkg/prompt"
 	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
 	"github.com/ZupIT/ritchie-cli/pkg/security/secsingle"
@@ -43,14 +51,46 @@ func main() {
 	rootCmd := buildCommands()
 	if err := rootCmd.Execute(); err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Error: %+v\n", err)
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
 
 func buildCommands() *cobra.Command {
 	userHomeDir := api.UserHomeDir()
+	if home := cmd.HomeArg(os.Args[1:]); home != "" {
+		_ = os.Setenv(api.RitchieHomeEnv, home)
+	}
 	ritchieHomeDir := api.RitchieHomeDir()
 
+	// config
+	configFinder := config.NewFinder(ritchieHomeDir)
+	configSetter := config.NewSetter(ritchieHomeDir, configFinder)
+	cfg, _ := configFinder.Find()
+	prompt.SetPlain(prompt.DetectPlain(cfg.Bool(config.PlainKey)))
+	prompt.SetLocale(prompt.DetectLocale(cfg.Get(config.InputsLocaleKey)))
+	if err := prompt.SetTheme(cfg.Get(config.ThemeKey), cfg.List(config.ThemeColorsKey), cfg.List(config.ThemeSymbolsKey)); err != nil {
+		prompt.Warning(err.Error())
+	}
+	if level, err := prompt.ParseLevel(cfg.Get(config.LogLevelKey)); err == nil {
+		cmd.SetDefaultLogLevel(level)
+	}
+	runLogs := runlog.NewManager(ritchieHomeDir, cfg.Bool(config.RunLogsKey))
+	runner.RegisterExecutables(filepath.Join(ritchieHomeDir, runner.PluginsDir))
+	for _, name := range runner.Plugins() {
+		config.AddRunner(name)
+	}
+	formulaRunner := cfg.Get(config.FormulaRunnerKey)
+	if err := runner.ValidRunner(formulaRunner); err != nil {
+		prompt.Warning(err.Error())
+		formulaRunner = ""
+	}
+
+	// http
+	network := cmd.NetworkArgs(os.Args[1:], cfg)
+	tlsClient := makeHttpClient(cmd.TLSArgs(os.Args[1:], cfg))
+	httpClient := network.Client(tlsClient, 0)
+	versionHttpClient := network.Client(tlsClient, 1*time.Second)
+
 	// prompt
 	inputText := prompt.NewSurveyText()
 	inputTextValidator := prompt.NewSurveyTextValidator()
@@ -58,6 +98,9 @@ func buildCommands() *cobra.Command {
 	inputBool := prompt.NewSurveyBool()
 	inputPassword := prompt.NewSurveyPassword()
 	inputList := prompt.NewSurveyList()
+	inputMultiselect := prompt.NewSurveyMultiselect()
+	inputPath := prompt.NewSurveyPath()
+	inputEditor := prompt.NewSurveyEditor()
 	inputURL := prompt.NewSurveyURL()
 
 	// deps
@@ -71,30 +114,50 @@ func buildCommands() *cobra.Command {
 	ctxRemover := rcontext.NewRemover(ritchieHomeDir, ctxFinder)
 	ctxFindSetter := rcontext.NewFindSetter(ritchieHomeDir, ctxFinder, ctxSetter)
 	ctxFindRemover := rcontext.NewFindRemover(ritchieHomeDir, ctxFinder, ctxRemover)
-	repoManager := repo.NewSingleRepoManager(ritchieHomeDir, http.DefaultClient, sessionManager)
+	ctxExportManager := rcontext.NewExportManager(ritchieHomeDir, ctxFinder, credsingle.NewLister(ritchieHomeDir))
+	repoManager := repo.NewSingleRepoManager(ritchieHomeDir, httpClient, sessionManager)
 	repoLoader := repo.NewSingleLoader(cmd.CommonsRepoURL, repoManager)
+	runHistory := history.NewManager(ritchieHomeDir, cfg.Bool(config.HistoryKey), repoManager)
+	runner.SetHistory(runHistory)
+	detachManager := detach.NewManager(ritchieHomeDir)
 	sessionValidator := sesssingle.NewValidator(sessionManager)
 	passphraseManager := secsingle.NewPassphraseManager(sessionManager)
 	credSetter := credsingle.NewSetter(ritchieHomeDir, ctxFinder, sessionManager)
 	credFinder := credsingle.NewFinder(ritchieHomeDir, ctxFinder, sessionManager)
 	credSettings := credsingle.NewSingleSettings(fileManager)
-	treeManager := tree.NewTreeManager(ritchieHomeDir, repoManager, api.SingleCoreCmds)
+	treeManager := tree.NewTreeManager(ritchieHomeDir, repoManager, api.SingleCoreCmds, ctxFinder)
 
 	autocompleteGen := autocomplete.NewGenerator(treeManager)
-	credResolver := envcredential.NewResolver(credFinder)
+	credResolver := envcredential.NewResolver(credFinder, credFinder, loadCredBackends(ritchieHomeDir)...)
 	envResolvers := make(env.Resolvers)
 	envResolvers[env.Credential] = credResolver
 
-	inputManager := runner.NewInputManager(envResolvers, inputList, inputText, inputBool, inputPassword)
-	formulaSetup := runner.NewDefaultSingleSetup(ritchieHomeDir, http.DefaultClient)
+	inputManager := runner.NewInputManager(envResolvers, inputList, inputMultiselect, inputPath, inputEditor, inputText, inputBool, inputPassword, cfg.Bool(config.InputsRequiredFirstKey))
+	formulaSetup := runner.NewDefaultSingleSetup(ritchieHomeDir, httpClient)
 
 	defaultPreRunner := runner.NewDefaultPreRunner(formulaSetup)
 	dockerPreRunner := runner.NewDockerPreRunner(formulaSetup)
 
 	postRunner := runner.NewPostRunner()
 
-	defaultRunner := runner.NewDefaultRunner(defaultPreRunner, postRunner, inputManager)
-	dockerRunner := runner.NewDockerRunner(dockerPreRunner, postRunner, inputManager, ctxFinder)
+	defaultRunner := runner.NewDefaultRunner(defaultPreRunner, postRunner, inputManager, runLogs)
+	dockerRunner := runner.NewDockerRunner(dockerPreRunner, postRunner, inputManager, ctxFinder, runLogs, cfg.List(config.FormulaVolumesKey))
+	pluginParts := runner.Parts{
+		PreRunner:       defaultPreRunner,
+		DockerPreRunner: dockerPreRunner,
+		PostRunner:      postRunner,
+		Inputs:          inputManager,
+		Logs:            runLogs,
+	}
+	sshRunner := runner.NewSSHRunner(defaultPreRunner, postRunner, inputManager, runLogs)
+	kubeRunner := runner.NewKubernetesRunner(dockerPreRunner, postRunner, inputManager, runner.Kubernetes{
+		Namespace:      cfg.Get(config.KubernetesNamespaceKey),
+		ServiceAccount: cfg.Get(config.KubernetesServiceAccountKey),
+		Kubeconfig:     cfg.Get(config.KubernetesKubeconfigKey),
+		Registry:       cfg.Get(config.KubernetesRegistryKey),
+	}, runLogs)
+	dockerPuller := runner.NewDockerPuller(formulaSetup, postRunner)
+	dockerCache := runner.NewDockerCache(formulaSetup, postRunner, ritchieHomeDir, runner.Engine(formulaRunner))
 
 	formulaCreator := creator.NewCreator(treeManager, dirManager, fileManager)
 	formulaWorkspace := fworkspace.New(ritchieHomeDir, fileManager)
@@ -102,14 +165,14 @@ func buildCommands() *cobra.Command {
 	watchManager := watcher.New(formulaBuilder, dirManager)
 	createBuilder := formula.NewCreateBuilder(formulaCreator, formulaBuilder)
 
-	upgradeManager := upgrade.DefaultManager{Updater: upgrade.DefaultUpdater{}}
+	upgradeManager := upgrade.DefaultManager{Updater: upgrade.DefaultUpdater{}, HttpClient: httpClient}
 	defaultUpgradeResolver := version.DefaultVersionResolver{
 		StableVersionUrl: cmd.StableVersionUrl,
 		FileUtilService:  fileutil.DefaultService{},
-		HttpClient:       &http.Client{Timeout: 1 * time.Second},
+		HttpClient:       versionHttpClient,
 	}
 	defaultUrlFinder := upgrade.DefaultUrlFinder{}
-	rootCmd := cmd.NewSingleRootCmd(workspaceManager, sessionValidator)
+	rootCmd := cmd.NewSingleRootCmd(defaultUpgradeResolver)
 
 	// level 1
 	autocompleteCmd := cmd.NewAutocompleteCmd()
@@ -117,13 +180,28 @@ func buildCommands() *cobra.Command {
 	createCmd := cmd.NewCreateCmd()
 	deleteCmd := cmd.NewDeleteCmd()
 	cleanCmd := cmd.NewCleanCmd()
-	initCmd := cmd.NewSingleInitCmd(inputPassword, passphraseManager, repoLoader)
+	initCmd := cmd.NewSingleInitCmd(
+		ritchieHomeDir,
+		inputPassword,
+		passphraseManager,
+		repoLoader,
+		sessionValidator,
+		repoManager,
+		repoManager,
+		ctxSetter,
+		configSetter)
 	listCmd := cmd.NewListCmd()
 	setCmd := cmd.NewSetCmd()
 	showCmd := cmd.NewShowCmd()
 	updateCmd := cmd.NewUpdateCmd()
+	diffCmd := cmd.NewDiffCmd()
+	repoCmd := cmd.NewRepoCmd()
+	contextCmd := cmd.NewContextCmd()
 	buildCmd := cmd.NewBuildCmd()
+	runCmd := cmd.NewRunCmd(inputList)
+	runCmd.AddCommand(cmd.NewRunBatchCmd(), cmd.NewRunPipelineCmd())
 	upgradeCmd := cmd.NewUpgradeCmd(api.Single, defaultUpgradeResolver, upgradeManager, defaultUrlFinder)
+	versionCmd := cmd.NewVersionCmd(api.Single, defaultUpgradeResolver)
 
 	// level 2
 	setCredentialCmd := cmd.NewSingleSetCredentialCmd(
@@ -137,10 +215,45 @@ func buildCommands() *cobra.Command {
 	deleteCtxCmd := cmd.NewDeleteContextCmd(ctxFindRemover, inputBool, inputList)
 	setCtxCmd := cmd.NewSetContextCmd(ctxFindSetter, inputText, inputList)
 	showCtxCmd := cmd.NewShowContextCmd(ctxFinder)
-	addRepoCmd := cmd.NewAddRepoCmd(repoManager, inputText, inputURL, inputInt, inputBool)
-	deleteRepoCmd := cmd.NewDeleteRepoCmd(repoManager, inputList, inputBool)
-	listRepoCmd := cmd.NewListRepoCmd(repoManager)
-	updateRepoCmd := cmd.NewUpdateRepoCmd(repoManager)
+	listContextCmd := cmd.NewListContextCmd(ctxFinder)
+	useContextCmd := cmd.NewUseContextCmd(ctxFindSetter)
+	contextShowCmd := cmd.NewContextShowCmd(ctxFinder)
+	exportContextCmd := cmd.NewExportContextCmd(ctxExportManager, ctxFinder)
+	importContextCmd := cmd.NewImportContextCmd(ctxExportManager)
+	setConfigCmd := cmd.NewSetConfigCmd(configSetter, inputText, inputList)
+	showConfigCmd := cmd.NewShowConfigCmd(configFinder)
+	showFormulaCmd := cmd.NewShowFormulaCmd(treeManager)
+	logsCmd := cmd.NewLogsCmd(runLogs)
+	psCmd := cmd.NewPsCmd(detachManager, runLogs)
+	stopCmd := cmd.NewStopCmd(detachManager)
+	historyCmd := cmd.NewHistoryCmd(runHistory)
+	rerunCmd := cmd.NewRerunCmd(runHistory)
+	prepullCmd := cmd.NewPrepullCmd(treeManager, dockerCache)
+	selfTestCmd := cmd.NewSelfTestCmd(runner.NewSelfTester())
+	formulaCmd := cmd.NewFormulaCommand(
+		api.SingleCoreCmds,
+		treeManager,
+		runner.NewPluginRunner(defaultRunner, pluginParts),
+		runner.NewPluginRunner(dockerRunner, pluginParts),
+		runner.NewPluginRunner(sshRunner, pluginParts),
+		runner.NewPluginRunner(kubeRunner, pluginParts),
+		dockerPuller,
+		dockerRunner,
+		formulaSetup,
+		detachManager,
+		cmd.RunDefaults{Runner: formulaRunner, Timeout: cfg.Duration(config.FormulaTimeoutKey)})
+	treeCmd := cmd.NewTreeCmd(ritchieHomeDir, formulaCmd)
+	addRepoCmd := cmd.NewAddRepoCmd(repoManager, repoManager, inputText, inputURL, inputInt, inputBool)
+	deleteRepoCmd := cmd.NewDeleteRepoCmd(repoManager, repoManager, inputList, inputText)
+	listRepoCmd := cmd.NewListRepoCmd(repoManager, ctxFinder)
+	updateRepoCmd := cmd.NewUpdateRepoCmd(repoManager, repoManager)
+	updateFormulaCmd := cmd.NewUpdateFormulaCmd(formulaCmd, formulaSetup, repoManager)
+	lockCmd := cmd.NewLockCmd(repoManager)
+	syncCmd := cmd.NewSyncCmd(repoManager, formulaSetup, formulaSetup)
+	diffRepoCmd := cmd.NewDiffRepoCmd(repoManager, repoManager)
+	repoLockCmd := cmd.NewRepoLockCmd(repoManager, repoManager)
+	repoUnlockCmd := cmd.NewRepoUnlockCmd(repoManager, repoManager)
+	repoVerifyCmd := cmd.NewRepoVerifyCmd(repoManager, repoManager)
 	autocompleteZsh := cmd.NewAutocompleteZsh(autocompleteGen)
 	autocompleteBash := cmd.NewAutocompleteBash(autocompleteGen)
 	autocompleteFish := cmd.NewAutocompleteFish(autocompleteGen)
@@ -149,21 +262,28 @@ func buildCommands() *cobra.Command {
 	createFormulaCmd := cmd.NewCreateFormulaCmd(userHomeDir, createBuilder, formulaWorkspace, inputText, inputTextValidator, inputList)
 	buildFormulaCmd := cmd.NewBuildFormulaCmd(userHomeDir, formulaBuilder, formulaWorkspace, watchManager, dirManager, inputText, inputList)
 	cleanFormulasCmd := cmd.NewCleanFormulasCmd()
+	cleanImagesCmd := cmd.NewCleanImagesCmd(treeManager, dockerCache, inputBool)
+	cleanInputsCmd := cmd.NewCleanInputsCmd(ritchieHomeDir, inputBool)
 
 	autocompleteCmd.AddCommand(autocompleteZsh, autocompleteBash, autocompleteFish, autocompletePowerShell)
 	addCmd.AddCommand(addRepoCmd)
 	createCmd.AddCommand(createFormulaCmd)
 	deleteCmd.AddCommand(deleteRepoCmd, deleteCtxCmd)
-	cleanCmd.AddCommand(cleanFormulasCmd)
+	cleanCmd.AddCommand(cleanFormulasCmd, cleanImagesCmd, cleanInputsCmd)
 	listCmd.AddCommand(listRepoCmd)
-	setCmd.AddCommand(setCredentialCmd, setCtxCmd)
-	showCmd.AddCommand(showCtxCmd)
-	updateCmd.AddCommand(updateRepoCmd)
+	setCmd.AddCommand(setCredentialCmd, setCtxCmd, setConfigCmd)
+	showCmd.AddCommand(showCtxCmd, showConfigCmd, showFormulaCmd)
+	updateCmd.AddCommand(updateRepoCmd, updateFormulaCmd)
+	diffCmd.AddCommand(diffRepoCmd)
+	repoCmd.AddCommand(repoLockCmd, repoUnlockCmd, repoVerifyCmd)
+	contextCmd.AddCommand(listContextCmd, useContextCmd, contextShowCmd, exportContextCmd, importContextCmd)
 	buildCmd.AddCommand(buildFormulaCmd)
 
-	formulaCmd := cmd.NewFormulaCommand(api.SingleCoreCmds, treeManager, defaultRunner, dockerRunner)
-	if err := formulaCmd.Add(rootCmd); err != nil {
-		panic(err)
+	if cmd.NeedsFormulas(rootCmd, os.Args[1:], api.SingleCoreCmds) {
+		if err := formulaCmd.Add(rootCmd); err != nil {
+			panic(err)
+		}
+		formulaCmd.AddInputFlags(rootCmd, os.Args[1:])
 	}
 
 	groups := templates.CommandGroups{
@@ -172,16 +292,31 @@ func buildCommands() *cobra.Command {
 			Commands: []*cobra.Command{
 				addCmd,
 				autocompleteCmd,
+				contextCmd,
 				createCmd,
 				deleteCmd,
+				diffCmd,
+				repoCmd,
 				cleanCmd,
 				initCmd,
 				listCmd,
+				lockCmd,
 				setCmd,
 				showCmd,
+				syncCmd,
 				updateCmd,
 				buildCmd,
 				upgradeCmd,
+				versionCmd,
+				runCmd,
+				logsCmd,
+				psCmd,
+				stopCmd,
+				historyCmd,
+				rerunCmd,
+				prepullCmd,
+				selfTestCmd,
+				treeCmd,
 			},
 		},
 	}
@@ -210,5 +345,30 @@ func buildCommands() *cobra.Command {
 	groups.Add(rootCmd)
 	templates.ActsAsRootCommand(rootCmd, nil, groups...)
 
+	chain := cmd.NewSingleChain(workspaceManager, sessionValidator, defaultUpgradeResolver)
+	chain.Apply(rootCmd)
+
 	return rootCmd
 }
+
+// makeHttpClient creates the client presenting the TLS files, when they can't be
+// loaded it warns and falls back to a plain client, so that rit set config still works
+func makeHttpClient(files httpclient.TLS) *http.Client {
+	client, err := httpclient.New(files, 0)
+	if err != nil {
+		prompt.Warning(err.Error())
+		return &http.Client{}
+	}
+	return client
+}
+
+// loadCredBackends loads the external credential backends, when they are
+// misconfigured it warns and resolves the credentials from the local store only
+func loadCredBackends(homePath string) []credential.Backend {
+	backends, err := credbackend.Load(homePath)
+	if err != nil {
+		prompt.Warning(err.Error())
+		return nil
+	}
+	return backends
+}
@@ -10,9 +10,13 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/ZupIT/ritchie-cli/pkg/formula/detach"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/history"
 	"github.com/ZupIT/ritchie-cli/pkg/formula/repo"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runlog"
 	"github.com/ZupIT/ritchie-cli/pkg/formula/runner"
 	"github.com/ZupIT/ritchie-cli/pkg/formula/tree"
 	"github.com/ZupIT/ritchie-cli/pkg/security/otp"
@@ -28,6 +32,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ZupIT/ritchie-cli/pkg/formula/watcher"
+	"github.com/ZupIT/ritchie-cli/pkg/http/httpclient"
 	"github.com/ZupIT/ritchie-cli/pkg/prompt"
 	"github.com/ZupIT/ritchie-cli/pkg/server"
 	"github.com/ZupIT/ritchie-cli/pkg/stream"
@@ -35,6 +40,9 @@ import (
 	"github.com/ZupIT/ritchie-cli/pkg/api"
 	"github.com/ZupIT/ritchie-cli/pkg/autocomplete"
 	"github.com/ZupIT/ritchie-cli/pkg/cmd"
+	"github.com/ZupIT/ritchie-cli/pkg/config"
+	"github.com/ZupIT/ritchie-cli/pkg/credential"
+	"github.com/ZupIT/ritchie-cli/pkg/credential/credbackend"
 	"github.com/ZupIT/ritchie-cli/pkg/credential/credteam"
 	"github.com/ZupIT/ritchie-cli/pkg/env"
 	"github.com/ZupIT/ritchie-cli/pkg/env/envcredential"
@@ -53,14 +61,42 @@ func main() {
 	rootCmd := buildCommands()
 	if err := rootCmd.Execute(); err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Error: %+v\n", err)
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
 
 func buildCommands() *cobra.Command {
 	userHomeDir := api.UserHomeDir()
+	if home := cmd.HomeArg(os.Args[1:]); home != "" {
+		_ = os.Setenv(api.RitchieHomeEnv, home)
+	}
 	ritchieHomeDir := api.RitchieHomeDir()
 
+	// config
+	configFinder := config.NewFinder(ritchieHomeDir)
+	configSetter := config.NewSetter(ritchieHomeDir, configFinder)
+	cfg, _ := configFinder.Find()
+	prompt.SetPlain(prompt.DetectPlain(cfg.Bool(config.PlainKey)))
+	prompt.SetLocale(prompt.DetectLocale(cfg.Get(config.InputsLocaleKey)))
+	if err := prompt.SetTheme(cfg.Get(config.ThemeKey), cfg.List(config.ThemeColorsKey), cfg.List(config.ThemeSymbolsKey)); err != nil {
+		prompt.Warning(err.Error())
+	}
+	if level, err := prompt.ParseLevel(cfg.Get(config.LogLevelKey)); err == nil {
+		cmd.SetDefaultLogLevel(level)
+	}
+	runLogs := runlog.NewManager(ritchieHomeDir, cfg.Bool(config.RunLogsKey))
+	runner.RegisterExecutables(filepath.Join(ritchieHomeDir, runner.PluginsDir))
+	for _, name := range runner.Plugins() {
+		config.AddRunner(name)
+	}
+	formulaRunner := cfg.Get(config.FormulaRunnerKey)
+	if err := runner.ValidRunner(formulaRunner); err != nil {
+		prompt.Warning(err.Error())
+		formulaRunner = ""
+	}
+	tlsConfig := makeTLSConfig(cmd.TLSArgs(os.Args[1:], cfg))
+	network := cmd.NetworkArgs(os.Args[1:], cfg)
+
 	// prompt
 	inputText := prompt.NewSurveyText()
 	inputTextValidator := prompt.NewSurveyTextValidator()
@@ -68,6 +104,9 @@ func buildCommands() *cobra.Command {
 	inputBool := prompt.NewSurveyBool()
 	inputPassword := prompt.NewSurveyPassword()
 	inputList := prompt.NewSurveyList()
+	inputMultiselect := prompt.NewSurveyMultiselect()
+	inputPath := prompt.NewSurveyPath()
+	inputEditor := prompt.NewSurveyEditor()
 	inputURL := prompt.NewSurveyURL()
 	inputMultiline := prompt.NewSurveyMultiline()
 
@@ -79,13 +118,18 @@ func buildCommands() *cobra.Command {
 	ctxRemover := rcontext.NewRemover(ritchieHomeDir, ctxFinder)
 	ctxFindSetter := rcontext.NewFindSetter(ritchieHomeDir, ctxFinder, ctxSetter)
 	ctxFindRemover := rcontext.NewFindRemover(ritchieHomeDir, ctxFinder, ctxRemover)
+	// the team credentials live on the server, the exports don't reference them
+	ctxExportManager := rcontext.NewExportManager(ritchieHomeDir, ctxFinder, nil)
 	serverFinder := server.NewFinder(ritchieHomeDir)
-	serverSetter := server.NewSetter(ritchieHomeDir, makeHttpClientIgnoreSsl())
+	serverSetter := server.NewSetter(ritchieHomeDir, makeHttpClientIgnoreSsl(tlsConfig))
 	serverFindSetter := server.NewFindSetter(serverFinder, serverSetter)
 
-	httpClient := makeHttpClient(serverFinder)
+	httpClient := network.Client(makeHttpClient(serverFinder, tlsConfig), 0)
 	repoManager := repo.NewTeamRepoManager(ritchieHomeDir, serverFinder, httpClient, sessionManager)
 	repoLoader := repo.NewTeamLoader(serverFinder, httpClient, sessionManager, repoManager)
+	runHistory := history.NewManager(ritchieHomeDir, cfg.Bool(config.HistoryKey), repoManager)
+	runner.SetHistory(runHistory)
+	detachManager := detach.NewManager(ritchieHomeDir)
 	sessionValidator := sessteam.NewValidator(sessionManager)
 	loginManager := secteam.NewLoginManager(
 		serverFinder,
@@ -95,21 +139,37 @@ func buildCommands() *cobra.Command {
 	credSetter := credteam.NewSetter(serverFinder, httpClient, sessionManager, ctxFinder)
 	credFinder := credteam.NewFinder(serverFinder, httpClient, sessionManager, ctxFinder)
 	credSettings := credteam.NewSettings(serverFinder, httpClient, sessionManager, ctxFinder)
-	treeManager := tree.NewTreeManager(ritchieHomeDir, repoManager, api.TeamCoreCmds)
+	treeManager := tree.NewTreeManager(ritchieHomeDir, repoManager, api.TeamCoreCmds, ctxFinder)
 	autocompleteGen := autocomplete.NewGenerator(treeManager)
-	credResolver := envcredential.NewResolver(credFinder)
+	credResolver := envcredential.NewResolver(credFinder, credSettings, loadCredBackends(ritchieHomeDir)...)
 	envResolvers := make(env.Resolvers)
 	envResolvers[env.Credential] = credResolver
 
-	inputManager := runner.NewInputManager(envResolvers, inputList, inputText, inputBool, inputPassword)
+	inputManager := runner.NewInputManager(envResolvers, inputList, inputMultiselect, inputPath, inputEditor, inputText, inputBool, inputPassword, cfg.Bool(config.InputsRequiredFirstKey))
 	formulaSetup := runner.NewDefaultTeamSetup(ritchieHomeDir, httpClient, sessionManager)
 
 	defaultPreRunner := runner.NewDefaultPreRunner(formulaSetup)
 	dockerPreRunner := runner.NewDockerPreRunner(formulaSetup)
 	postRunner := runner.NewPostRunner()
 
-	defaultRunner := runner.NewDefaultRunner(defaultPreRunner, postRunner, inputManager)
-	dockerRunner := runner.NewDockerRunner(dockerPreRunner, postRunner, inputManager, ctxFinder)
+	defaultRunner := runner.NewDefaultRunner(defaultPreRunner, postRunner, inputManager, runLogs)
+	dockerRunner := runner.NewDockerRunner(dockerPreRunner, postRunner, inputManager, ctxFinder, runLogs, cfg.List(config.FormulaVolumesKey))
+	pluginParts := runner.Parts{
+		PreRunner:       defaultPreRunner,
+		DockerPreRunner: dockerPreRunner,
+		PostRunner:      postRunner,
+		Inputs:          inputManager,
+		Logs:            runLogs,
+	}
+	sshRunner := runner.NewSSHRunner(defaultPreRunner, postRunner, inputManager, runLogs)
+	kubeRunner := runner.NewKubernetesRunner(dockerPreRunner, postRunner, inputManager, runner.Kubernetes{
+		Namespace:      cfg.Get(config.KubernetesNamespaceKey),
+		ServiceAccount: cfg.Get(config.KubernetesServiceAccountKey),
+		Kubeconfig:     cfg.Get(config.KubernetesKubeconfigKey),
+		Registry:       cfg.Get(config.KubernetesRegistryKey),
+	}, runLogs)
+	dockerPuller := runner.NewDockerPuller(formulaSetup, postRunner)
+	dockerCache := runner.NewDockerCache(formulaSetup, postRunner, ritchieHomeDir, runner.Engine(formulaRunner))
 
 	fileManager := stream.NewFileManager()
 	dirManager := stream.NewDirManager(fileManager)
@@ -120,9 +180,8 @@ func buildCommands() *cobra.Command {
 	watchManager := watcher.New(formulaBuilder, dirManager)
 	createBuilder := formula.NewCreateBuilder(formulaCreator, formulaBuilder)
 
-	upgradeManager := upgrade.DefaultManager{Updater: upgrade.DefaultUpdater{}}
-	uhc := makeHttpClient(serverFinder)
-	uhc.Timeout = 1 * time.Second
+	upgradeManager := upgrade.DefaultManager{Updater: upgrade.DefaultUpdater{}, HttpClient: httpClient}
+	uhc := network.Client(makeHttpClient(serverFinder, tlsConfig), 1*time.Second)
 	defaultUpgradeResolver := version.DefaultVersionResolver{
 		StableVersionUrl: cmd.StableVersionUrl,
 		FileUtilService:  fileutil.DefaultService{},
@@ -133,7 +192,7 @@ func buildCommands() *cobra.Command {
 	otpResolver := otp.NewOtpResolver(httpClient)
 
 	// commands
-	rootCmd := cmd.NewTeamRootCmd(workspaceManager, serverFinder, sessionValidator)
+	rootCmd := cmd.NewTeamRootCmd(defaultUpgradeResolver)
 
 	// level 1
 	autocompleteCmd := cmd.NewAutocompleteCmd()
@@ -157,8 +216,14 @@ func buildCommands() *cobra.Command {
 	setCmd := cmd.NewSetCmd()
 	showCmd := cmd.NewShowCmd()
 	updateCmd := cmd.NewUpdateCmd()
+	diffCmd := cmd.NewDiffCmd()
+	repoCmd := cmd.NewRepoCmd()
+	contextCmd := cmd.NewContextCmd()
 	buildCmd := cmd.NewBuildCmd()
+	runCmd := cmd.NewRunCmd(inputList)
+	runCmd.AddCommand(cmd.NewRunBatchCmd(), cmd.NewRunPipelineCmd())
 	upgradeCmd := cmd.NewUpgradeCmd(api.Team, defaultUpgradeResolver, upgradeManager, defaultUrlFinder)
+	versionCmd := cmd.NewVersionCmd(api.Team, defaultUpgradeResolver)
 
 	// level 2
 	setCredentialCmd := cmd.NewTeamSetCredentialCmd(
@@ -172,10 +237,45 @@ func buildCommands() *cobra.Command {
 	deleteCtxCmd := cmd.NewDeleteContextCmd(ctxFindRemover, inputBool, inputList)
 	setCtxCmd := cmd.NewSetContextCmd(ctxFindSetter, inputText, inputList)
 	showCtxCmd := cmd.NewShowContextCmd(ctxFinder)
-	addRepoCmd := cmd.NewAddRepoCmd(repoManager, inputText, inputURL, inputInt, inputBool)
-	deleteRepoCmd := cmd.NewDeleteRepoCmd(repoManager, inputList, inputBool)
-	listRepoCmd := cmd.NewListRepoCmd(repoManager)
-	updateRepoCmd := cmd.NewUpdateRepoCmd(repoManager)
+	listContextCmd := cmd.NewListContextCmd(ctxFinder)
+	useContextCmd := cmd.NewUseContextCmd(ctxFindSetter)
+	contextShowCmd := cmd.NewContextShowCmd(ctxFinder)
+	exportContextCmd := cmd.NewExportContextCmd(ctxExportManager, ctxFinder)
+	importContextCmd := cmd.NewImportContextCmd(ctxExportManager)
+	setConfigCmd := cmd.NewSetConfigCmd(configSetter, inputText, inputList)
+	showConfigCmd := cmd.NewShowConfigCmd(configFinder)
+	showFormulaCmd := cmd.NewShowFormulaCmd(treeManager)
+	logsCmd := cmd.NewLogsCmd(runLogs)
+	psCmd := cmd.NewPsCmd(detachManager, runLogs)
+	stopCmd := cmd.NewStopCmd(detachManager)
+	historyCmd := cmd.NewHistoryCmd(runHistory)
+	rerunCmd := cmd.NewRerunCmd(runHistory)
+	prepullCmd := cmd.NewPrepullCmd(treeManager, dockerCache)
+	selfTestCmd := cmd.NewSelfTestCmd(runner.NewSelfTester())
+	formulaCmd := cmd.NewFormulaCommand(
+		api.TeamCoreCmds,
+		treeManager,
+		runner.NewPluginRunner(defaultRunner, pluginParts),
+		runner.NewPluginRunner(dockerRunner, pluginParts),
+		runner.NewPluginRunner(sshRunner, pluginParts),
+		runner.NewPluginRunner(kubeRunner, pluginParts),
+		dockerPuller,
+		dockerRunner,
+		formulaSetup,
+		detachManager,
+		cmd.RunDefaults{Runner: formulaRunner, Timeout: cfg.Duration(config.FormulaTimeoutKey)})
+	treeCmd := cmd.NewTreeCmd(ritchieHomeDir, formulaCmd)
+	addRepoCmd := cmd.NewAddRepoCmd(repoManager, repoManager, inputText, inputURL, inputInt, inputBool)
+	deleteRepoCmd := cmd.NewDeleteRepoCmd(repoManager, repoManager, inputList, inputText)
+	listRepoCmd := cmd.NewListRepoCmd(repoManager, ctxFinder)
+	updateRepoCmd := cmd.NewUpdateRepoCmd(repoManager, repoManager)
+	updateFormulaCmd := cmd.NewUpdateFormulaCmd(formulaCmd, formulaSetup, repoManager)
+	lockCmd := cmd.NewLockCmd(repoManager)
+	syncCmd := cmd.NewSyncCmd(repoManager, formulaSetup, formulaSetup)
+	diffRepoCmd := cmd.NewDiffRepoCmd(repoManager, repoManager)
+	repoLockCmd := cmd.NewRepoLockCmd(repoManager, repoManager)
+	repoUnlockCmd := cmd.NewRepoUnlockCmd(repoManager, repoManager)
+	repoVerifyCmd := cmd.NewRepoVerifyCmd(repoManager, repoManager)
 	autocompleteZsh := cmd.NewAutocompleteZsh(autocompleteGen)
 	autocompleteBash := cmd.NewAutocompleteBash(autocompleteGen)
 	autocompleteFish := cmd.NewAutocompleteFish(autocompleteGen)
@@ -184,21 +284,28 @@ func buildCommands() *cobra.Command {
 	createFormulaCmd := cmd.NewCreateFormulaCmd(userHomeDir, createBuilder, formulaWorkspace, inputText, inputTextValidator, inputList)
 	buildFormulaCmd := cmd.NewBuildFormulaCmd(userHomeDir, formulaBuilder, formulaWorkspace, watchManager, dirManager, inputText, inputList)
 	cleanFormulasCmd := cmd.NewCleanFormulasCmd()
+	cleanImagesCmd := cmd.NewCleanImagesCmd(treeManager, dockerCache, inputBool)
+	cleanInputsCmd := cmd.NewCleanInputsCmd(ritchieHomeDir, inputBool)
 
 	autocompleteCmd.AddCommand(autocompleteZsh, autocompleteBash, autocompleteFish, autocompletePowerShell)
 	addCmd.AddCommand(addRepoCmd)
 	createCmd.AddCommand(createFormulaCmd)
 	deleteCmd.AddCommand(deleteRepoCmd, deleteCtxCmd)
-	cleanCmd.AddCommand(cleanFormulasCmd)
+	cleanCmd.AddCommand(cleanFormulasCmd, cleanImagesCmd, cleanInputsCmd)
 	listCmd.AddCommand(listRepoCmd)
-	setCmd.AddCommand(setCredentialCmd, setCtxCmd)
-	showCmd.AddCommand(showCtxCmd)
-	updateCmd.AddCommand(updateRepoCmd)
+	setCmd.AddCommand(setCredentialCmd, setCtxCmd, setConfigCmd)
+	showCmd.AddCommand(showCtxCmd, showConfigCmd, showFormulaCmd)
+	updateCmd.AddCommand(updateRepoCmd, updateFormulaCmd)
+	diffCmd.AddCommand(diffRepoCmd)
+	repoCmd.AddCommand(repoLockCmd, repoUnlockCmd, repoVerifyCmd)
+	contextCmd.AddCommand(listContextCmd, useContextCmd, contextShowCmd, exportContextCmd, importContextCmd)
 	buildCmd.AddCommand(buildFormulaCmd)
 
-	formulaCmd := cmd.NewFormulaCommand(api.TeamCoreCmds, treeManager, defaultRunner, dockerRunner)
-	if err := formulaCmd.Add(rootCmd); err != nil {
-		panic(err)
+	if cmd.NeedsFormulas(rootCmd, os.Args[1:], api.TeamCoreCmds) {
+		if err := formulaCmd.Add(rootCmd); err != nil {
+			panic(err)
+		}
+		formulaCmd.AddInputFlags(rootCmd, os.Args[1:])
 	}
 
 	groups := templates.CommandGroups{
@@ -207,18 +314,33 @@ func buildCommands() *cobra.Command {
 			Commands: []*cobra.Command{
 				addCmd,
 				autocompleteCmd,
+				contextCmd,
 				createCmd,
 				deleteCmd,
+				diffCmd,
+				repoCmd,
 				cleanCmd,
 				initCmd,
 				listCmd,
+				lockCmd,
 				loginCmd,
 				logoutCmd,
 				setCmd,
 				showCmd,
+				syncCmd,
 				buildCmd,
 				updateCmd,
 				upgradeCmd,
+				versionCmd,
+				runCmd,
+				logsCmd,
+				psCmd,
+				stopCmd,
+				historyCmd,
+				rerunCmd,
+				prepullCmd,
+				selfTestCmd,
+				treeCmd,
 			},
 		},
 	}
@@ -247,36 +369,55 @@ func buildCommands() *cobra.Command {
 	groups.Add(rootCmd)
 	templates.ActsAsRootCommand(rootCmd, nil, groups...)
 
-	sendMetrics(sessionManager, serverFinder)
+	chain := cmd.NewTeamChain(workspaceManager, serverFinder, sessionValidator, defaultUpgradeResolver)
+	chain.Apply(rootCmd)
+
+	sendMetrics(sessionManager, serverFinder, tlsConfig, network)
 
 	return rootCmd
 }
 
-func sendMetrics(sm session.DefaultManager, sf server.Finder) {
-	hc := makeHttpClient(sf)
-	hc.Timeout = 2 * time.Second
+func sendMetrics(sm session.DefaultManager, sf server.Finder, tlsConfig *tls.Config, network httpclient.Network) {
+	hc := network.Client(makeHttpClient(sf, tlsConfig), 2*time.Second)
 	metricsManager := metrics.NewSender(hc, sf, sm)
 	go metricsManager.SendCommand()
 }
 
-func makeHttpClient(finder server.Finder) *http.Client {
+// makeTLSConfig loads the TLS files presented to the server, when they can't be
+// loaded it warns and falls back to an empty config, so that rit set config still works
+func makeTLSConfig(files httpclient.TLS) *tls.Config {
+	c, err := files.Config()
+	if err != nil {
+		prompt.Warning(err.Error())
+	}
+	if c == nil {
+		c = &tls.Config{}
+	}
+	return c
+}
+
+func makeHttpClient(finder server.Finder, tlsConfig *tls.Config) *http.Client {
 	c, err := finder.Find()
 	if err != nil {
 		fmt.Println(prompt.NewError("error load cli config, try run \"rit init\""))
 		os.Exit(1)
 	}
 	client := &http.Client{}
+	// no proxy, the key pinning of the dialer only runs on the direct connections
 	client.Transport = &http.Transport{
-		DialTLSContext: makeDialer(c.PinningKey, c.PinningAddr, true),
+		DialTLSContext: makeDialer(c.PinningKey, c.PinningAddr, true, tlsConfig),
 	}
 	return client
 }
 
 type Dialer func(ctx context.Context, network, addr string) (net.Conn, error)
+
 /* #nosec */
-func makeDialer(pKey, pAddr string, skipCAVerification bool) Dialer {
+func makeDialer(pKey, pAddr string, skipCAVerification bool, tlsConfig *tls.Config) Dialer {
 	return func(ctx context.Context, network, addr string) (net.Conn, error) {
-		c, err := tls.Dial(network, addr, &tls.Config{InsecureSkipVerify: skipCAVerification}) //#	nosec
+		dialConfig := tlsConfig.Clone()
+		dialConfig.InsecureSkipVerify = skipCAVerification //#nosec
+		c, err := tls.Dial(network, addr, dialConfig)
 		if err != nil {
 			return c, err
 		}
@@ -300,11 +441,26 @@ func makeDialer(pKey, pAddr string, skipCAVerification bool) Dialer {
 		return c, nil
 	}
 }
+
 /* #nosec */
-func makeHttpClientIgnoreSsl() *http.Client {
+func makeHttpClientIgnoreSsl(tlsConfig *tls.Config) *http.Client {
+	ignoreSsl := tlsConfig.Clone()
+	ignoreSsl.InsecureSkipVerify = true //#nosec
 	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //#nosec
+		Proxy:           httpclient.Proxy,
+		TLSClientConfig: ignoreSsl,
 	}
 	client := &http.Client{Transport: tr}
 	return client
 }
+
+// loadCredBackends loads the external credential backends, when they are
+// misconfigured it warns and resolves the credentials from the local store only
+func loadCredBackends(homePath string) []credential.Backend {
+	backends, err := credbackend.Load(homePath)
+	if err != nil {
+		prompt.Warning(err.Error())
+		return nil
+	}
+	return backends
+}
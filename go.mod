@@ -21,4 +21,5 @@ require (
 	github.com/spf13/cobra v1.0.0
 	github.com/thoas/go-funk v0.6.0
 	k8s.io/kubectl v0.18.4
+	sigs.k8s.io/yaml v1.2.0
 )
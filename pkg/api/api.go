@@ -1,13 +1,19 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"os/user"
 	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/json/jsonutil"
 )
 
 const (
 	ritchieHomePattern = "%s/.rit"
+	// RitchieHomeEnv overrides the home dir of the ritchie
+	RitchieHomeEnv = "RITCHIE_HOME"
 	// Team version
 	Team = Edition("team")
 	// Single version
@@ -21,6 +27,12 @@ var (
 		{Parent: "root", Usage: "add"},
 		{Parent: "root_add", Usage: "repo"},
 		{Parent: "root", Usage: "completion"},
+		{Parent: "root", Usage: "context"},
+		{Parent: "root_context", Usage: "export"},
+		{Parent: "root_context", Usage: "import"},
+		{Parent: "root_context", Usage: "list"},
+		{Parent: "root_context", Usage: "show"},
+		{Parent: "root_context", Usage: "use"},
 		{Parent: "root_completion", Usage: "bash"},
 		{Parent: "root_completion", Usage: "zsh"},
 		{Parent: "root_completion", Usage: "fish"},
@@ -28,24 +40,50 @@ var (
 		{Parent: "root", Usage: "delete"},
 		{Parent: "root_delete", Usage: "context"},
 		{Parent: "root_delete", Usage: "repo"},
+		{Parent: "root", Usage: "diff"},
+		{Parent: "root_diff", Usage: "repo"},
 		{Parent: "root", Usage: "help"},
 		{Parent: "root", Usage: "init"},
 		{Parent: "root", Usage: "list"},
 		{Parent: "root_list", Usage: "repo"},
+		{Parent: "root", Usage: "repo"},
+		{Parent: "root_repo", Usage: "lock"},
+		{Parent: "root_repo", Usage: "unlock"},
+		{Parent: "root_repo", Usage: "verify"},
 		{Parent: "root", Usage: "set"},
+		{Parent: "root_set", Usage: "config"},
 		{Parent: "root_set", Usage: "context"},
 		{Parent: "root_set", Usage: "credential"},
 		{Parent: "root", Usage: "show"},
+		{Parent: "root_show", Usage: "config"},
 		{Parent: "root_show", Usage: "context"},
+		{Parent: "root_show", Usage: "formula"},
 		{Parent: "root", Usage: "create"},
 		{Parent: "root_create", Usage: "formula"},
+		{Parent: "root", Usage: "lock"},
+		{Parent: "root", Usage: "sync"},
 		{Parent: "root", Usage: "update"},
+		{Parent: "root_update", Usage: "formula"},
 		{Parent: "root_update", Usage: "repo"},
 		{Parent: "root", Usage: "build"},
 		{Parent: "root_build", Usage: "formula"},
 		{Parent: "root", Usage: "upgrade"},
+		{Parent: "root", Usage: "version"},
 		{Parent: "root", Usage: "clean"},
 		{Parent: "root_clean", Usage: "formulas"},
+		{Parent: "root_clean", Usage: "images"},
+		{Parent: "root_clean", Usage: "inputs"},
+		{Parent: "root", Usage: "run"},
+		{Parent: "root_run", Usage: "batch"},
+		{Parent: "root_run", Usage: "pipeline"},
+		{Parent: "root", Usage: "logs"},
+		{Parent: "root", Usage: "ps"},
+		{Parent: "root", Usage: "stop"},
+		{Parent: "root", Usage: "history"},
+		{Parent: "root", Usage: "rerun"},
+		{Parent: "root", Usage: "prepull"},
+		{Parent: "root", Usage: "self-test"},
+		{Parent: "root", Usage: "tree"},
 	}
 
 	SingleCoreCmds = CoreCmds
@@ -61,17 +99,44 @@ var (
 	)
 )
 
-// Command type
+// Command type, Help is the short description of the lists and LongHelp and Examples,
+// copied from the formula config by rit build formula, make up the command help.
+// Extra keeps the fields unknown to this version so they survive a rewrite
 type Command struct {
-	Parent  string  `json:"parent"`
-	Usage   string  `json:"usage"`
-	Help    string  `json:"help"`
-	Formula *Formula `json:"formula,omitempty"`
-	Repo    string  `json:"Repo,omitempty"`
+	Parent      string                     `json:"parent"`
+	Usage       string                     `json:"usage"`
+	Help        string                     `json:"help"`
+	LongHelp    string                     `json:"longHelp,omitempty"`
+	Examples    []Example                  `json:"examples,omitempty"`
+	Formula     *Formula                   `json:"formula,omitempty"`
+	Deprecation *Deprecation               `json:"deprecation,omitempty"`
+	Repo        string                     `json:"Repo,omitempty"`
+	Extra       map[string]json.RawMessage `json:"-"`
+}
+
+// Example is a usage example of a formula, shown on its help and by rit show formula
+type Example struct {
+	Description string `json:"description,omitempty"`
+	Command     string `json:"command"`
 }
 
 type Commands []Command
 
+func (c *Command) UnmarshalJSON(b []byte) error {
+	type command Command
+	extra, err := jsonutil.UnmarshalExtra(b, (*command)(c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	return nil
+}
+
+func (c Command) MarshalJSON() ([]byte, error) {
+	type command Command
+	return jsonutil.MarshalExtra(command(c), c.Extra)
+}
+
 // Formula type
 type Formula struct {
 	Path    string `json:"path,omitempty"`
@@ -84,6 +149,13 @@ type Formula struct {
 	RepoURL string `json:"repoUrl,omitempty"`
 }
 
+// Deprecation type that represents the deprecation notice of a formula
+type Deprecation struct {
+	Message     string `json:"message"`
+	Replacement string `json:"replacement,omitempty"`
+	Sunset      string `json:"sunset,omitempty"`
+}
+
 // Edition type that represents Single or Team.
 type Edition string
 
@@ -115,7 +187,10 @@ func UserHomeDir() string {
 	return usr.HomeDir
 }
 
-// RitchieHomeDir returns the home dir of the ritchie
+// RitchieHomeDir returns the home dir of the ritchie, RITCHIE_HOME when it is set
 func RitchieHomeDir() string {
+	if home := os.Getenv(RitchieHomeEnv); home != "" {
+		return home
+	}
 	return fmt.Sprintf(ritchieHomePattern, UserHomeDir())
 }
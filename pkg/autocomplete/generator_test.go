@@ -25,7 +25,7 @@ func TestGenerate(t *testing.T) {
 		err error
 	}
 
-	treeMan := tree.NewTreeManager("../../testdata", repoListerMock{}, api.SingleCoreCmds)
+	treeMan := tree.NewTreeManager("../../testdata", repoListerMock{}, api.SingleCoreCmds, nil)
 	autocomplete := NewGenerator(treeMan)
 
 	tests := []struct {
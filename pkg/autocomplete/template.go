@@ -60,21 +60,46 @@ __{{BinaryName}}_handle_command()
 
 __{{BinaryName}}_handle_reply()
 {
+    if [[ ${#commands[@]} -eq 0 ]]; then
+        __{{BinaryName}}_handle_args
+        return
+    fi
+
     local completions
     completions=("${commands[@]}")
     COMPREPLY=( $(compgen -W "${completions[*]}" -- "$cur") )
 
 }
 
+# __{{BinaryName}}_handle_args completes the args of a command, e.g. the config keys,
+# asking them to {{BinaryName}} __completeNoDesc. Its last line is the cobra directive:
+# 1 is an error and 4 disables the file completion.
+__{{BinaryName}}_handle_args()
+{
+    local out directive
+    out=$("${words[0]}" __completeNoDesc "${words[@]:1:$((cword-1))}" "$cur" 2>/dev/null)
+    directive=${out##*:}
+    out=${out%:*}
+    [[ -z ${directive} || ${directive} == *[!0-9]* ]] && return
+    (( directive & 1 )) && return
+
+    COMPREPLY=( $(compgen -W "${out}" -- "$cur") )
+    if [[ ${#COMPREPLY[@]} -eq 0 ]] && (( (directive & 4) == 0 )); then
+        COMPREPLY=( $(compgen -f -- "$cur") )
+    fi
+}
+
 __{{BinaryName}}_handle_word()
 {
     if [[ $c -ge $cword ]]; then
         __{{BinaryName}}_handle_reply
         return
     fi
-    if __{{BinaryName}}_contains_word "${words[c]}" "${commands[@]}"; then
-        __{{BinaryName}}_handle_command
-        fi
+    if ! __{{BinaryName}}_contains_word "${words[c]}" "${commands[@]}"; then
+        __{{BinaryName}}_handle_args
+        return
+    fi
+    __{{BinaryName}}_handle_command
 
     __{{BinaryName}}_handle_word
 }
@@ -12,9 +12,12 @@ import (
 	"github.com/ZupIT/ritchie-cli/pkg/stdin"
 )
 
+const contextsFlag = "contexts"
+
 // addRepoCmd type for add repo command
 type addRepoCmd struct {
 	formula.RepoAddLister
+	planner formula.RepoPlanner
 	prompt.InputText
 	prompt.InputURL
 	prompt.InputInt
@@ -24,12 +27,14 @@ type addRepoCmd struct {
 // NewAddRepoCmd creates a new cmd instance
 func NewAddRepoCmd(
 	adl formula.RepoAddLister,
+	rp formula.RepoPlanner,
 	it prompt.InputText,
 	iu prompt.InputURL,
 	ii prompt.InputInt,
 	ib prompt.InputBool) *cobra.Command {
 	a := &addRepoCmd{
 		adl,
+		rp,
 		it,
 		iu,
 		ii,
@@ -39,16 +44,22 @@ func NewAddRepoCmd(
 	cmd := &cobra.Command{
 		Use:     "repo",
 		Short:   "Add a repository.",
-		Example: "rit add repo ",
-		RunE: RunFuncE(a.runStdin(), a.runPrompt()),
+		Example: "rit add repo\nrit add repo --dry-run --output json\nrit add repo --stdin --dry-run --json < repo.json",
+		RunE:    RunFuncE(a.runStdin(), a.runPrompt()),
 	}
-	cmd.LocalFlags()
+	cmd.Flags().StringSlice(contextsFlag, nil, "Contexts where the repository formulas are available, all contexts when empty")
+	addDryRunFlags(cmd)
 
 	return cmd
 }
 
 func (a addRepoCmd) runPrompt() CommandRunnerFunc {
 	return func(cmd *cobra.Command, args []string) error {
+		dry, err := dryRun(cmd)
+		if err != nil {
+			return err
+		}
+
 		rn, err := a.Text("Name of the repository: ", true)
 		if err != nil {
 			return err
@@ -59,7 +70,7 @@ func (a addRepoCmd) runPrompt() CommandRunnerFunc {
 			return err
 		}
 		for _, repo := range repos {
-			if rn == repo.Name {
+			if rn == repo.Name && !dry {
 				prompt.Warning(fmt.Sprintf("Your repository %q is gonna be overwritten.", repo.Name))
 				choice, _ := a.Bool("Want to proceed?", []string{"yes", "no"})
 				if !choice {
@@ -79,17 +90,19 @@ func (a addRepoCmd) runPrompt() CommandRunnerFunc {
 			return err
 		}
 
+		contexts, err := cmd.Flags().GetStringSlice(contextsFlag)
+		if err != nil {
+			return err
+		}
+
 		r := formula.Repository{
 			Priority: int(pr),
 			Name:     rn,
 			TreePath: ur,
+			Contexts: contexts,
 		}
 
-		if err = a.Add(r); err != nil {
-			return err
-		}
-		prompt.Success("Repository added")
-		return err
+		return a.add(cmd, r, dry)
 	}
 }
 
@@ -98,16 +111,33 @@ func (a addRepoCmd) runStdin() CommandRunnerFunc {
 
 		r := formula.Repository{}
 
-		err := stdin.ReadJson(os.Stdin, &r)
+		dry, err := dryRun(cmd)
 		if err != nil {
+			return err
+		}
+
+		if err := stdin.ReadJson(os.Stdin, &r); err != nil {
 			prompt.Error(stdin.MsgInvalidInput)
 			return err
 		}
 
-		if err := a.Add(r); err != nil {
+		return a.add(cmd, r, dry)
+	}
+}
+
+// add adds the repository, or only reports the changes of adding it on a dry run
+func (a addRepoCmd) add(cmd *cobra.Command, r formula.Repository, dry bool) error {
+	if dry {
+		plan, err := a.planner.PlanAdd(r)
+		if err != nil {
 			return err
 		}
-		prompt.Success("Repository added")
-		return nil
+		return printRepoPlans(cmd, plan)
+	}
+
+	if err := a.Add(r); err != nil {
+		return err
 	}
+	prompt.Success("Repository added")
+	return nil
 }
@@ -6,7 +6,6 @@ import (
 	"path"
 	"time"
 
-	"github.com/kaduartur/go-cli-spinner/pkg/spinner"
 	"github.com/spf13/cobra"
 
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
@@ -113,7 +112,7 @@ func (b buildFormulaCmd) runFunc() CommandRunnerFunc {
 
 func (b buildFormulaCmd) build(workspacePath, formulaPath string) {
 	buildInfo := prompt.Red("Building formula...")
-	s := spinner.StartNew(buildInfo)
+	s := prompt.StartSpinner(buildInfo)
 	time.Sleep(2 * time.Second)
 
 	if err := b.formula.Build(workspacePath, formulaPath); err != nil {
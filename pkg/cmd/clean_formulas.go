@@ -48,7 +48,7 @@ func (cleanFormula cleanFormulasCmd) runPrompt() CommandRunnerFunc {
 			[]string{"yes", "no"},
 		)
 		if !choice {
-			fmt.Println("Operation cancelled")
+			prompt.Print("Operation cancelled")
 			return nil
 		}
 
@@ -74,7 +74,7 @@ func (cleanFormula cleanFormulasCmd) runStdin() CommandRunnerFunc {
 		}
 
 		if !stdinArgs.Confirm {
-			fmt.Println("Operation cancelled")
+			prompt.Print("Operation cancelled")
 			return nil
 		}
 
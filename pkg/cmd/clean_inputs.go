@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runner"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	msgCleanedInputs    = "%d input caches removed"
+	descCleanInputsLong = `Remove the values of the formula inputs cached by their previous runs, the
+inputs with an active cache on their config.json prompt their declared items again.`
+)
+
+type cleanInputsCmd struct {
+	ritchieHome string
+	prompt.InputBool
+}
+
+// NewCleanInputsCmd creates the clean inputs command, it purges the input cache of the formulas
+func NewCleanInputsCmd(ritchieHome string, ib prompt.InputBool) *cobra.Command {
+	c := cleanInputsCmd{ritchieHome, ib}
+
+	return &cobra.Command{
+		Use:     "inputs",
+		Short:   "Remove the cached input values of the formulas",
+		Long:    descCleanInputsLong,
+		Example: "rit clean inputs\nrit clean inputs --yes",
+		Args:    cobra.NoArgs,
+		RunE:    c.runFunc(),
+	}
+}
+
+func (c cleanInputsCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		choice, err := newConfirmer(cmd, c.InputBool, nil).Confirm("the cached input values of all the formulas")
+		if err != nil {
+			return err
+		}
+		if !choice {
+			prompt.Print("Operation cancelled")
+			return nil
+		}
+
+		removed, err := runner.CleanInputCache(c.ritchieHome)
+		if err != nil {
+			return err
+		}
+		prompt.Success(fmt.Sprintf(msgCleanedInputs, removed))
+		return nil
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+func TestCleanInputsCmd(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		inputBool   prompt.InputBool
+		wantRemoved bool
+	}{
+		{name: "confirmed", inputBool: inputTrueMock{}, wantRemoved: true},
+		{name: "--yes", args: []string{"--yes"}, inputBool: inputFalseMock{}, wantRemoved: true},
+		{name: "cancelled", inputBool: inputFalseMock{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home, err := ioutil.TempDir("", "rit-home")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(home)
+			cache := filepath.Join(home, "formulas", "aws", "terraform", ".REGION.cache")
+			_ = os.MkdirAll(filepath.Dir(cache), os.ModePerm)
+			if err := ioutil.WriteFile(cache, []byte(`["sa-east-1"]`), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			root := &cobra.Command{Use: cmdUse, SilenceErrors: true, SilenceUsage: true}
+			addConfirmFlags(root)
+			root.AddCommand(NewCleanInputsCmd(home, tt.inputBool))
+			root.SetArgs(append([]string{"inputs"}, tt.args...))
+
+			if err := root.Execute(); err != nil {
+				t.Fatalf("clean inputs error = %v", err)
+			}
+			_, err = os.Stat(cache)
+			if removed := os.IsNotExist(err); removed != tt.wantRemoved {
+				t.Errorf("clean inputs removed the cache %v, want %v", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
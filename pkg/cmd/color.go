@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const colorFlag = "color"
+
+// addColorFlag adds the persistent flag that controls the output colors
+func addColorFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().String(colorFlag, prompt.ColorAuto, "When to use colors [auto|always|never], auto honors NO_COLOR")
+}
+
+// setColor applies the color mode of the flag, an undefined flag is auto
+func setColor(cmd *cobra.Command) error {
+	mode := prompt.ColorAuto
+	if f := cmd.Flags().Lookup(colorFlag); f != nil {
+		mode = f.Value.String()
+	}
+	return prompt.SetColor(mode)
+}
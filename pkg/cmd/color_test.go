@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+func TestSetColor(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want error
+	}{
+		{name: "default auto", args: []string{}},
+		{name: "never", args: []string{"--color", "never"}},
+		{name: "always", args: []string{"--color", "always"}},
+		{name: "invalid", args: []string{"--color", "blue"}, want: prompt.ErrInvalidColorMode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{
+				Use:  "rit",
+				RunE: func(cmd *cobra.Command, args []string) error { return setColor(cmd) },
+			}
+			addColorFlag(cmd)
+			cmd.SetArgs(tt.args)
+
+			if got := cmd.Execute(); got != tt.want {
+				t.Errorf("setColor(%s) got %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+	_ = prompt.SetColor(prompt.ColorAuto)
+}
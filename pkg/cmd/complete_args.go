@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/config"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
+)
+
+// validArgsFunc completes the args of a command on rit __complete, it runs
+// before rit init too, so the values are read without writing on the rit home
+type validArgsFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// completeConfig completes the config key and, for the keys with known values, its value.
+// The other values, e.g. the file paths of the tls keys, get the shell file completion.
+func completeConfig(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return withPrefix(config.KeyNames(), toComplete), cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		values := config.Keys[args[0]].Values
+		if len(values) == 0 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+		return withPrefix(values, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeContexts completes the name of an existing context
+func completeContexts(f rcontext.Finder) validArgsFunc {
+	return func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		ctxHolder, err := f.Find()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return withPrefix(ctxHolder.All, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeRepos completes the name of a repository, there is none before rit init
+func completeRepos(l formula.RepoLister) validArgsFunc {
+	return func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		repos, err := l.List()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return withPrefix(rNameList(repos), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// withPrefix returns the sorted values starting with prefix
+func withPrefix(values []string, prefix string) []string {
+	var matches []string
+	for _, v := range values {
+		if strings.HasPrefix(v, prefix) {
+			matches = append(matches, v)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
+)
+
+func TestCompleteArgs(t *testing.T) {
+	ctx := ctxFinderCustomMock{holder: rcontext.ContextHolder{Current: "dev", All: []string{"qa", "dev", "prod"}}}
+	ctxFindRemover := ctxFindRemoverCustomMock{
+		find:   ctx.Find,
+		remove: func(string) (rcontext.ContextHolder, error) { return rcontext.ContextHolder{}, nil },
+	}
+	repos := repoDelListerCustomMock{repos: []formula.Repository{{Name: "commons"}, {Name: "acme"}}}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "config keys", args: []string{"set", "config", "tls."}, want: "tls.ca\ntls.client-cert\ntls.client-key\n:4\n"},
+		{name: "config bool values", args: []string{"set", "config", "logs.enabled", ""}, want: "false\ntrue\n:4\n"},
+		{name: "config file values", args: []string{"set", "config", "tls.ca", ""}, want: ":0\n"},
+		{name: "contexts to set", args: []string{"set", "context", ""}, want: "dev\nprod\nqa\n:4\n"},
+		{name: "contexts to delete", args: []string{"delete", "context", "p"}, want: "prod\n:4\n"},
+		{name: "repositories to delete", args: []string{"delete", "repo", ""}, want: "acme\ncommons\n:4\n"},
+		{name: "repositories to lock", args: []string{"repo", "lock", "co"}, want: "commons\n:4\n"},
+		{name: "a single repository", args: []string{"repo", "unlock", "acme", ""}, want: ":4\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setCmd := NewSetCmd()
+			setCmd.AddCommand(
+				NewSetConfigCmd(&configSetterMock{}, inputTextMock{}, inputListMock{}),
+				NewSetContextCmd(ctxFindSetterCustomMock{ctx}, inputTextMock{}, inputListMock{}),
+			)
+			deleteCmd := NewDeleteCmd()
+			deleteCmd.AddCommand(
+				NewDeleteContextCmd(ctxFindRemover, inputTrueMock{}, inputListMock{}),
+				NewDeleteRepoCmd(repos, repoPlannerMock{}, inputListMock{}, inputTextMock{}),
+			)
+			repoCmd := NewRepoCmd()
+			repoCmd.AddCommand(NewRepoLockCmd(repoLockerMock{}, repos), NewRepoUnlockCmd(repoLockerMock{}, repos))
+
+			rootCmd := &cobra.Command{Use: "rit"}
+			rootCmd.AddCommand(setCmd, deleteCmd, repoCmd)
+			var out bytes.Buffer
+			rootCmd.SetOut(&out)
+			rootCmd.SetArgs(append([]string{cobra.ShellCompNoDescRequestCmd}, tt.args...))
+
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("%v = %v, want nil", tt.args, err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("%v completed %q, want %q", tt.args, out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCompleteReposBeforeInit(t *testing.T) {
+	// before rit init there is no repositories file and the lister fails
+	complete := completeRepos(repoListerErrorMock{})
+	if got, directive := complete(nil, nil, ""); got != nil || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("completeRepos got %v, %v, want no completions", got, directive)
+	}
+}
+
+type repoListerErrorMock struct{}
+
+func (repoListerErrorMock) List() ([]formula.Repository, error) {
+	return nil, errors.New("no repositories to show")
+}
+
+type ctxFindSetterCustomMock struct {
+	ctxFinderCustomMock
+}
+
+func (ctxFindSetterCustomMock) Set(ctx string) (rcontext.ContextHolder, error) {
+	return rcontext.ContextHolder{Current: ctx}, nil
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	yesFlag            = "yes"
+	nonInteractiveFlag = "non-interactive"
+)
+
+// addConfirmFlags adds the persistent flags used by the confirmation prompts
+func addConfirmFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolP(yesFlag, "y", false, "Automatically confirm destructive operations")
+	cmd.PersistentFlags().Bool(nonInteractiveFlag, false, "Fail instead of prompting for confirmations")
+}
+
+// newConfirmer creates the confirmation helper from the command flags
+func newConfirmer(cmd *cobra.Command, ib prompt.InputBool, it prompt.InputText) prompt.Confirmer {
+	return prompt.NewConfirmer(ib, it, boolFlag(cmd, yesFlag), boolFlag(cmd, nonInteractiveFlag))
+}
+
+// boolFlag reads a bool flag that may not be defined, an undefined flag is false
+func boolFlag(cmd *cobra.Command, name string) bool {
+	f := cmd.Flags().Lookup(name)
+	if f == nil {
+		return false
+	}
+	b, err := strconv.ParseBool(f.Value.String())
+	return err == nil && b
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
+)
+
+const descContextLong = `
+This command consists of multiple subcommands to manage the contexts.
+
+It can be used to list, use and show the contexts, and to export them to
+another machine and import them. The credentials of a context are exported
+by their names only, set them again after the import with rit set credential.
+`
+
+// NewContextCmd create a new context instance
+func NewContextCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "context SUBCOMMAND",
+		Short: "Manage the contexts",
+		Long:  descContextLong,
+	}
+}
+
+// NewContextShowCmd creates the rit context show command, the same as rit show context
+func NewContextShowCmd(f rcontext.Finder) *cobra.Command {
+	cmd := NewShowContextCmd(f)
+	cmd.Use = "show"
+	cmd.Example = "rit context show\nrit context show --output yaml"
+	return cmd
+}
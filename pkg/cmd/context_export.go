@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
+)
+
+const (
+	overwriteFlag         = "overwrite"
+	msgContextsImported   = "%d contexts imported"
+	msgMissingCredentials = "The context %q references credentials that aren't set on it: %s, set them with rit context use %s and rit set credential"
+	descContextExportLong = "Export the contexts as JSON, or YAML with --output yaml, to be imported on another machine.\n" +
+		"The credentials of a context are exported by their names only, their values are never exported."
+)
+
+// exportContextCmd type for context export command
+type exportContextCmd struct {
+	rcontext.Exporter
+}
+
+// importContextCmd type for context import command
+type importContextCmd struct {
+	rcontext.Importer
+}
+
+// NewExportContextCmd creates a new cmd instance
+func NewExportContextCmd(e rcontext.Exporter, f rcontext.Finder) *cobra.Command {
+	ex := exportContextCmd{e}
+
+	cmd := &cobra.Command{
+		Use:     "export [NAME...]",
+		Short:   "Export the contexts, without the credential values",
+		Long:    descContextExportLong,
+		Example: "rit context export > contexts.json\nrit context export prod qa --output yaml",
+		RunE:    ex.runFunc(),
+
+		ValidArgsFunction: completeContexts(f),
+	}
+	addOutputFlag(cmd, "exported contexts")
+
+	return cmd
+}
+
+func (e exportContextCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		output, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		f, err := e.Export(args...)
+		if err != nil {
+			return err
+		}
+
+		if output == "" {
+			output = outputJSON
+		}
+		return printOutput(output, f)
+	}
+}
+
+// NewImportContextCmd creates a new cmd instance
+func NewImportContextCmd(i rcontext.Importer) *cobra.Command {
+	im := importContextCmd{i}
+
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Import the contexts exported by rit context export",
+		Long: "Import the contexts of a file exported by rit context export, or of the stdin with -.\n" +
+			"The existing contexts are only imported again with --overwrite, the current context is kept.",
+		Example: "rit context import contexts.json\nrit context import - --overwrite < contexts.yaml",
+		Args:    cobra.ExactArgs(1),
+		RunE:    im.runFunc(),
+	}
+	cmd.Flags().Bool(overwriteFlag, false, "Import the contexts that already exist")
+
+	return cmd
+}
+
+func (i importContextCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		var b []byte
+		var err error
+		if args[0] == "-" {
+			b, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			b, err = ioutil.ReadFile(args[0])
+		}
+		if err != nil {
+			return err
+		}
+
+		// the YAML parser reads the JSON exports too
+		var f rcontext.ExportFile
+		if err := yaml.Unmarshal(b, &f); err != nil {
+			return err
+		}
+
+		missing, err := i.Import(f, boolFlag(cmd, overwriteFlag))
+		if err != nil {
+			return err
+		}
+
+		for _, c := range f.Contexts {
+			if creds, ok := missing[c.Name]; ok {
+				prompt.Warning(fmt.Sprintf(msgMissingCredentials, c.Name, strings.Join(creds, ", "), c.Name))
+			}
+		}
+		prompt.Success(fmt.Sprintf(msgContextsImported, len(f.Contexts)))
+		return nil
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
+)
+
+// listContextCmd type for context list command
+type listContextCmd struct {
+	rcontext.Finder
+}
+
+// NewListContextCmd creates a new cmd instance
+func NewListContextCmd(f rcontext.Finder) *cobra.Command {
+	l := listContextCmd{f}
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List the contexts, the current one is marked",
+		Example: "rit context list\nrit context list --output json",
+		Args:    cobra.NoArgs,
+		RunE:    l.runFunc(),
+	}
+	addOutputFlag(cmd, "contexts")
+
+	return cmd
+}
+
+func (l listContextCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		output, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		ctxHolder, err := l.Find()
+		if err != nil {
+			return err
+		}
+
+		if ctxHolder.Current == "" {
+			ctxHolder.Current = rcontext.DefaultCtx
+		}
+		all := append([]string{rcontext.DefaultCtx}, ctxHolder.All...)
+
+		if output != "" {
+			return printOutput(output, contextOutput{Current: ctxHolder.Current, Contexts: all})
+		}
+
+		// the contexts are the output asked for, they are printed even with --quiet
+		for _, ctx := range all {
+			if ctx == ctxHolder.Current {
+				fmt.Fprintln(prompt.Stdout, prompt.Bold("* "+ctx))
+				continue
+			}
+			fmt.Fprintln(prompt.Stdout, "  "+ctx)
+		}
+		return nil
+	}
+}
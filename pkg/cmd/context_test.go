@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
+)
+
+type ctxCredListerMock map[string][]string
+
+func (c ctxCredListerMock) Services(ctx string) ([]string, error) {
+	return c[ctx], nil
+}
+
+func TestContextCmd(t *testing.T) {
+	home, err := ioutil.TempDir("", "rit-context-cmd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	finder := rcontext.NewFinder(home)
+	findSetter := rcontext.NewFindSetter(home, finder, rcontext.NewSetter(home, finder))
+	if _, err := findSetter.Set("dev"); err != nil {
+		t.Fatal(err)
+	}
+	exports := rcontext.NewExportManager(home, finder, ctxCredListerMock{"dev": {"github"}})
+
+	exportFile := filepath.Join(home, "contexts.yaml")
+	yamlExport := "contexts:\n- name: prod\n  credentials: [aws]\n"
+	if err := ioutil.WriteFile(exportFile, []byte(yamlExport), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr error
+	}{
+		{name: "list", args: []string{"list"}, want: []string{"  default", "* dev"}},
+		{name: "use unknown", args: []string{"use", "prod"}, wantErr: errors.New(`context "prod" not found`)},
+		{name: "export", args: []string{"export", "dev"}, want: []string{`"name": "dev"`, `"credentials": [`, `"github"`}},
+		{name: "import", args: []string{"import", exportFile}, want: []string{`The context "prod" references credentials that aren't set on it: aws`}},
+		{name: "import again", args: []string{"import", exportFile}, wantErr: rcontext.ErrContextExists},
+		{name: "import overwrite", args: []string{"import", exportFile, "--overwrite"}, want: []string{"1 contexts imported"}},
+		{name: "use", args: []string{"use", "prod"}, want: []string{`Now using the context "prod"`}},
+		{name: "show", args: []string{"show", "--output", "json"}, want: []string{`"current": "prod"`, `"contexts": [`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contextCmd := NewContextCmd()
+			contextCmd.SilenceErrors = true
+			contextCmd.SilenceUsage = true
+			contextCmd.AddCommand(
+				NewListContextCmd(finder),
+				NewUseContextCmd(findSetter),
+				NewContextShowCmd(finder),
+				NewExportContextCmd(exports, finder),
+				NewImportContextCmd(exports),
+			)
+			contextCmd.SetArgs(tt.args)
+
+			var err error
+			out := captureStdout(func() { err = contextCmd.Execute() })
+			if tt.wantErr != nil {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr.Error()) {
+					t.Fatalf("rit context %s error = %v, want %v", tt.name, err, tt.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("rit context %s error = %v", tt.name, err)
+			}
+
+			for _, w := range tt.want {
+				if !strings.Contains(out, w) {
+					t.Errorf("rit context %s printed %q, want %q", tt.name, out, w)
+				}
+			}
+		})
+	}
+}
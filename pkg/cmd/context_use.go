@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
+	"github.com/ZupIT/ritchie-cli/pkg/slice/sliceutil"
+)
+
+const msgContextUsed = "Now using the context %q"
+
+// useContextCmd type for context use command
+type useContextCmd struct {
+	rcontext.FindSetter
+}
+
+// NewUseContextCmd creates a new cmd instance
+func NewUseContextCmd(fs rcontext.FindSetter) *cobra.Command {
+	u := useContextCmd{fs}
+
+	return &cobra.Command{
+		Use:   "use NAME",
+		Short: "Switch to an existing context",
+		Long: "Switch to an existing context, the formulas run with its credentials and repositories.\n" +
+			"Create a context with rit set context.",
+		Example: "rit context use prod\nrit context use default",
+		Args:    cobra.ExactArgs(1),
+		RunE:    u.runFunc(),
+
+		ValidArgsFunction: completeContexts(fs),
+	}
+}
+
+func (u useContextCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		ctxHolder, err := u.Find()
+		if err != nil {
+			return err
+		}
+
+		ctx := args[0]
+		if ctx != rcontext.DefaultCtx && !sliceutil.Contains(ctxHolder.All, ctx) {
+			return prompt.NewError(fmt.Sprintf(msgContextNotFound, ctx))
+		}
+
+		if _, err := u.Set(ctx); err != nil {
+			return err
+		}
+
+		prompt.Success(fmt.Sprintf(msgContextUsed, ctx))
+		return nil
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/kaduartur/go-cli-spinner/pkg/spinner"
 	"github.com/spf13/cobra"
 
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
@@ -144,7 +143,7 @@ func (c createFormulaCmd) runStdin() CommandRunnerFunc {
 
 func (c createFormulaCmd) create(cf formula.Create, workspacePath, formulaPath string) {
 	buildInfo := prompt.Bold("Creating and building formula...")
-	s := spinner.StartNew(buildInfo)
+	s := prompt.StartSpinner(buildInfo)
 	time.Sleep(2 * time.Second)
 
 	if err := c.formula.Create(cf); err != nil {
@@ -164,7 +163,7 @@ func (c createFormulaCmd) create(cf formula.Create, workspacePath, formulaPath s
 	buildSuccess(formulaPath, cf.FormulaCmd)
 }
 
-func createSuccess(s *spinner.Spinner, lang string) {
+func createSuccess(s prompt.Spinner, lang string) {
 	msg := fmt.Sprintf("✔ %s formula successfully created!", lang)
 	success := prompt.Green(msg)
 	s.Success(success)
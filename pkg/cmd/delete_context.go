@@ -8,9 +8,12 @@ import (
 
 	"github.com/ZupIT/ritchie-cli/pkg/prompt"
 	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
+	"github.com/ZupIT/ritchie-cli/pkg/slice/sliceutil"
 	"github.com/ZupIT/ritchie-cli/pkg/stdin"
 )
 
+const msgContextNotFound = "context %q not found"
+
 // deleteContextCmd type for clean repo command
 type deleteContextCmd struct {
 	rcontext.FindRemover
@@ -30,10 +33,13 @@ func NewDeleteContextCmd(
 	d := deleteContextCmd{fr, ib, il}
 
 	cmd := &cobra.Command{
-		Use:     "context",
+		Use:     "context [NAME]",
 		Short:   "Delete context for ritchie-cli",
-		Example: "rit delete context",
+		Example: "rit delete context\nrit delete context qa",
+		Args:    cobra.MaximumNArgs(1),
 		RunE:    RunFuncE(d.runStdin(), d.runPrompt()),
+
+		ValidArgsFunction: completeContexts(fr),
 	}
 
 	cmd.LocalFlags()
@@ -53,18 +59,17 @@ func (d deleteContextCmd) runPrompt() CommandRunnerFunc {
 			return nil
 		}
 
-		for i := range ctxHolder.All {
-			if ctxHolder.All[i] == ctxHolder.Current {
-				ctxHolder.All[i] = fmt.Sprintf("%s%s", rcontext.CurrentCtx, ctxHolder.Current)
+		var ctx string
+		if len(args) == 1 {
+			if ctx = args[0]; !sliceutil.Contains(ctxHolder.All, ctx) {
+				return prompt.NewError(fmt.Sprintf(msgContextNotFound, ctx))
 			}
-		}
-
-		ctx, err := d.List("Contexts:", ctxHolder.All)
-		if err != nil {
+		} else if ctx, err = d.chooseContext(ctxHolder); err != nil {
 			return err
 		}
 
-		if b, err := d.Bool("Are you sure want to delete this context?", []string{"yes", "no"}); err != nil {
+		confirm := newConfirmer(cmd, d.InputBool, nil)
+		if b, err := confirm.Confirm(fmt.Sprintf("the context %q", ctx)); err != nil {
 			return err
 		} else if !b {
 			return nil
@@ -79,6 +84,17 @@ func (d deleteContextCmd) runPrompt() CommandRunnerFunc {
 	}
 }
 
+// chooseContext lists the contexts to delete, the current one is marked
+func (d deleteContextCmd) chooseContext(ctxHolder rcontext.ContextHolder) (string, error) {
+	for i := range ctxHolder.All {
+		if ctxHolder.All[i] == ctxHolder.Current {
+			ctxHolder.All[i] = fmt.Sprintf("%s%s", rcontext.CurrentCtx, ctxHolder.Current)
+		}
+	}
+
+	return d.List("Contexts:", ctxHolder.All)
+}
+
 func (d deleteContextCmd) runStdin() CommandRunnerFunc {
 	return func(cmd *cobra.Command, args []string) error {
 		ctxHolder, err := d.Find()
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
 )
 
 func TestNewDeleteContextCmd(t *testing.T) {
@@ -16,3 +18,44 @@ func TestNewDeleteContextCmd(t *testing.T) {
 		t.Errorf("%s = %v, want %v", cmd.Use, err, nil)
 	}
 }
+
+func TestDeleteContextConfirmation(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantAsked   bool
+		wantRemoved bool
+		wantErr     bool
+	}{
+		{name: "prompt asks for confirmation", args: []string{}, wantAsked: true, wantRemoved: true},
+		{name: "yes skips the confirmation", args: []string{"-y"}, wantRemoved: true},
+		{name: "non interactive fails without yes", args: []string{"--non-interactive"}, wantErr: true},
+		{name: "context name informed", args: []string{"qa", "-y"}, wantRemoved: true},
+		{name: "unknown context name", args: []string{"prod", "-y"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asked, removed := false, false
+			fr := ctxFindRemoverCustomMock{
+				find: func() (rcontext.ContextHolder, error) {
+					return rcontext.ContextHolder{Current: "dev", All: []string{"dev", "qa"}}, nil
+				},
+				remove: func(string) (rcontext.ContextHolder, error) { removed = true; return rcontext.ContextHolder{}, nil },
+			}
+			confirm := inputBoolCustomMock{bool: func(string, []string) (bool, error) { asked = true; return true, nil }}
+
+			cmd := NewDeleteContextCmd(fr, confirm, inputListMock{})
+			cmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			addConfirmFlags(cmd)
+			cmd.SetArgs(tt.args)
+
+			if err := cmd.Execute(); (err != nil) != tt.wantErr {
+				t.Errorf("%s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if asked != tt.wantAsked || removed != tt.wantRemoved {
+				t.Errorf("%s asked = %v removed = %v, want %v and %v", tt.name, asked, removed, tt.wantAsked, tt.wantRemoved)
+			}
+		})
+	}
+}
@@ -15,9 +15,10 @@ import (
 
 // deleteRepoCmd type for delete repo command
 type deleteRepoCmd struct {
-	repo formula.RepoDelLister
+	repo    formula.RepoDelLister
+	planner formula.RepoPlanner
 	prompt.InputList
-	prompt.InputBool
+	prompt.InputText
 }
 
 // deleteRepo type for stdin json decoder
@@ -26,21 +27,25 @@ type deleteRepo struct {
 }
 
 // NewDeleteRepoCmd delete repository instance
-func NewDeleteRepoCmd(dl formula.RepoDelLister, il prompt.InputList, ib prompt.InputBool) *cobra.Command {
+func NewDeleteRepoCmd(dl formula.RepoDelLister, rp formula.RepoPlanner, il prompt.InputList, it prompt.InputText) *cobra.Command {
 	d := &deleteRepoCmd{
 		dl,
+		rp,
 		il,
-		ib,
+		it,
 	}
 
 	cmd := &cobra.Command{
 		Use:     "repo [NAME_REPOSITORY]",
 		Short:   "Delete a repository",
-		Example: "rit delete repo [NAME_REPOSITORY]",
+		Example: "rit delete repo [NAME_REPOSITORY]\nrit delete repo --dry-run",
+		Args:    cobra.MaximumNArgs(1),
 		RunE:    RunFuncE(d.runStdin(), d.runPrompt()),
+
+		ValidArgsFunction: completeRepos(dl),
 	}
 
-	cmd.LocalFlags()
+	addDryRunFlags(cmd)
 
 	return cmd
 }
@@ -56,6 +61,10 @@ func rNameList(r []formula.Repository) []string {
 
 func (d deleteRepoCmd) runPrompt() CommandRunnerFunc {
 	return func(cmd *cobra.Command, args []string) error {
+		dry, err := dryRun(cmd)
+		if err != nil {
+			return err
+		}
 
 		repos, err := d.repo.List()
 		if err != nil {
@@ -69,14 +78,25 @@ func (d deleteRepoCmd) runPrompt() CommandRunnerFunc {
 
 		options := rNameList(repos)
 
-		rn, err := d.List("Choose a repository to delete:", options)
-		if err != nil {
+		// an unknown repository name is reported by the plan or the delete
+		var rn string
+		if len(args) == 1 {
+			rn = args[0]
+		} else if rn, err = d.List("Choose a repository to delete:", options); err != nil {
 			return err
 		}
 
-		choice, _ := d.Bool(fmt.Sprintf("Want to delete %s?", rn), []string{"yes", "no"})
+		if dry {
+			return d.plan(cmd, rn)
+		}
+
+		// the formulas of the repository go with it, so the name is typed to confirm
+		choice, err := newConfirmer(cmd, nil, d.InputText).ConfirmStrict(fmt.Sprintf("the repository %q and its formulas", rn), rn)
+		if err != nil {
+			return err
+		}
 		if !choice {
-			fmt.Println("Operation cancelled")
+			prompt.Print("Operation cancelled")
 			return nil
 		}
 
@@ -94,12 +114,20 @@ func (d deleteRepoCmd) runStdin() CommandRunnerFunc {
 
 		dr := deleteRepo{}
 
-		err := stdin.ReadJson(os.Stdin, &dr)
+		dry, err := dryRun(cmd)
 		if err != nil {
+			return err
+		}
+
+		if err := stdin.ReadJson(os.Stdin, &dr); err != nil {
 			prompt.Error(stdin.MsgInvalidInput)
 			return err
 		}
 
+		if dry {
+			return d.plan(cmd, dr.Name)
+		}
+
 		if err = d.repo.Delete(dr.Name); err != nil {
 			return err
 		}
@@ -108,3 +136,12 @@ func (d deleteRepoCmd) runStdin() CommandRunnerFunc {
 		return nil
 	}
 }
+
+// plan reports the changes of deleting the repository without deleting it
+func (d deleteRepoCmd) plan(cmd *cobra.Command, name string) error {
+	plan, err := d.planner.PlanDelete(name)
+	if err != nil {
+		return err
+	}
+	return printRepoPlans(cmd, plan)
+}
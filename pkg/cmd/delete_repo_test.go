@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
 )
 
 func TestNewDeleteRepoCmd(t *testing.T) {
-	cmd := NewDeleteRepoCmd(repoDeleterMock{}, inputListMock{}, inputTrueMock{})
+	cmd := NewDeleteRepoCmd(repoDeleterMock{}, repoPlannerMock{}, inputListMock{}, inputTextMock{})
 	cmd.PersistentFlags().Bool("stdin", false, "input by stdin")
 	if cmd == nil {
 		t.Errorf("NewDeleteRepoCmd got %v", cmd)
@@ -16,3 +18,50 @@ func TestNewDeleteRepoCmd(t *testing.T) {
 		t.Errorf("%s = %v, want %v", cmd.Use, err, nil)
 	}
 }
+
+func TestDeleteRepoConfirmation(t *testing.T) {
+	repos := repoDelListerCustomMock{repos: []formula.Repository{{Name: "commons"}}}
+
+	tests := []struct {
+		name        string
+		args        []string
+		typed       string
+		wantAsked   bool
+		wantDeleted bool
+		wantErr     bool
+	}{
+		{name: "prompt asks for confirmation", args: []string{}, wantAsked: true, wantDeleted: true},
+		{name: "a mistyped name cancels", args: []string{}, typed: "common", wantAsked: true, wantErr: true},
+		{name: "yes skips the confirmation", args: []string{"--yes"}, wantDeleted: true},
+		{name: "y skips the confirmation", args: []string{"-y"}, wantDeleted: true},
+		{name: "non interactive fails without yes", args: []string{"--non-interactive"}, wantErr: true},
+		{name: "dry run only reports", args: []string{"--dry-run"}},
+		{name: "dry run with an invalid output", args: []string{"--dry-run", "-o", "xml"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asked, deleted := false, false
+			repos.delete = func(string) error { deleted = true; return nil }
+			confirm := inputTextCustomMock{text: func(string, bool) (string, error) {
+				asked = true
+				if tt.typed != "" {
+					return tt.typed, nil
+				}
+				return "item-mocked", nil
+			}}
+
+			cmd := NewDeleteRepoCmd(repos, repoPlannerMock{}, inputListMock{}, confirm)
+			cmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			addConfirmFlags(cmd)
+			cmd.SetArgs(tt.args)
+
+			if err := cmd.Execute(); (err != nil) != tt.wantErr {
+				t.Errorf("%s error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if asked != tt.wantAsked || deleted != tt.wantDeleted {
+				t.Errorf("%s asked = %v deleted = %v, want %v and %v", tt.name, asked, deleted, tt.wantAsked, tt.wantDeleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/detach"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	detachFlag      = "detach"
+	msgDetached     = "%s is running in the background with the ID %s"
+	msgDetachedHint = "Follow its output with rit logs %[1]s --follow and stop it with rit stop %[1]s"
+)
+
+var (
+	ErrDetachNeedsStdin = errors.New("--detach needs the inputs on --stdin, as the run in the background can't prompt them")
+	ErrDetachCount      = errors.New("--detach can't run the formula --count times, detach a rit run batch instead")
+)
+
+// runDetached starts the formula on a child rit in the background, with the flags informed
+// to it but --detach and the --stdin inputs, and prints the ID of the run
+func runDetached(detacher detach.Starter, cmd *cobra.Command, formulaArgs []string) error {
+	if !boolFlag(cmd, api.Stdin.ToLower()) {
+		return ErrDetachNeedsStdin
+	}
+	if count, _ := cmd.Flags().GetInt(countFlag); count > 1 {
+		return ErrDetachCount
+	}
+
+	stdin, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	run, err := detacher.Start(cmd.CommandPath(), childRunArgs(cmd, formulaArgs, detachFlag), stdin)
+	if err != nil {
+		return err
+	}
+
+	prompt.Success(fmt.Sprintf(msgDetached, run.Command, run.ID))
+	prompt.Info(fmt.Sprintf(msgDetachedHint, run.ID))
+	return nil
+}
+
+// detachedLogID returns the run log ID of the child rit of a --detach run, empty on the other
+// runs. The env var is unset, so the rit runs of the formula don't log with the same ID.
+func detachedLogID() string {
+	id := os.Getenv(detach.IDEnv)
+	_ = os.Unsetenv(detach.IDEnv)
+	return id
+}
@@ -0,0 +1,12 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+// NewDiffCmd create a new diff instance
+func NewDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff SUBCOMMAND",
+		Short: "Show local changes (repositories)",
+		Long:  `Show the changes made on the rit home to objects like repo`,
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const msgNoLocalChanges = "The formulas of the %q repository have no local changes"
+
+// fileChangeMarks are the marks of the changes printed by rit diff repo, as on git status --short
+var fileChangeMarks = map[string]string{
+	formula.FileAdded:    "A",
+	formula.FileModified: "M",
+	formula.FileDeleted:  "D",
+}
+
+// diffRepoCmd type for diff repo command
+type diffRepoCmd struct {
+	formula.RepoDiffer
+}
+
+// NewDiffRepoCmd creates a new cmd instance
+func NewDiffRepoCmd(rd formula.RepoDiffer, rl formula.RepoLister) *cobra.Command {
+	d := &diffRepoCmd{rd}
+
+	cmd := &cobra.Command{
+		Use:   "repo NAME",
+		Short: "Show the formula files of a repository changed locally",
+		Long: "Show the formula files of a repository added (A), modified (M) or deleted (D) on the rit home\n" +
+			"since they were downloaded, e.g. by rit build formula. Only the formulas downloaded by a\n" +
+			"run are compared, their checksums are saved when they are downloaded.",
+		Example: "rit diff repo commons",
+		Args:    cobra.ExactArgs(1),
+		RunE:    d.runFunc(),
+
+		ValidArgsFunction: completeRepos(rl),
+	}
+
+	return cmd
+}
+
+func (d diffRepoCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		changes, err := d.Diff(args[0])
+		if err != nil {
+			return err
+		}
+
+		if len(changes) == 0 {
+			prompt.Info(fmt.Sprintf(msgNoLocalChanges, args[0]))
+			return nil
+		}
+
+		for _, c := range changes {
+			fmt.Printf("%s %s\n", fileChangeMarks[c.Status], c.Path)
+		}
+		return nil
+	}
+}
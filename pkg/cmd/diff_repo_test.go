@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestNewDiffRepoCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		differ  repoDifferMock
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "prints the changed files",
+			differ: repoDifferMock{changes: []formula.FileChange{
+				{Status: formula.FileAdded, Path: "aws/create/bin/new.sh"},
+				{Status: formula.FileModified, Path: "aws/create/config.json"},
+			}},
+			args: []string{"commons"},
+			want: "A aws/create/bin/new.sh\nM aws/create/config.json\n",
+		},
+		{
+			name:    "unknown repository",
+			differ:  repoDifferMock{err: errors.New("repository \"missing\" not found")},
+			args:    []string{"missing"},
+			wantErr: true,
+		},
+		{
+			name:    "repository name is required",
+			args:    []string{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewDiffRepoCmd(tt.differ, repoListerMock{})
+			cmd.SetArgs(tt.args)
+
+			var err error
+			out := captureStdout(func() { err = cmd.Execute() })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s = %v, wantErr %v", cmd.Use, err, tt.wantErr)
+			}
+			if tt.want != "" && out != tt.want {
+				t.Errorf("%s printed %q, want %q", cmd.Use, out, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	msgNoImagesPull  = "No docker image to pull, the formulas of the repositories run only locally"
+	msgPrepulled     = "%d of %d docker images pulled, the first docker runs of their formulas will start faster"
+	msgNoUnused      = "No unused docker images found"
+	msgRemovedImages = "%d docker images removed"
+	descPrepullLong  = `Pull the docker images the installed formulas are built from, so their first
+docker run doesn't download them, e.g. before going offline or on a CI machine.
+
+The images are read from the FROM instructions of the formula Dockerfiles and from the
+dockerImageBuilder of their config.json, each image is pulled once, a few at a time.
+The pulled images are removed by rit clean images once no formula is built from them.`
+	descCleanImagesLong = `Remove the docker images rit left behind: the images built by the docker
+runs, unless a container uses them, and the images pulled by rit prepull that none of
+the installed formulas is built from anymore.`
+)
+
+var ErrPrepullFailed = errors.New("some docker images couldn't be pulled")
+
+// formulaDefinitions returns the formulas of the tree, only the ones of the repo when it is set
+func formulaDefinitions(tm formula.TreeManager, repo string) []formula.Definition {
+	var defs []formula.Definition
+	for _, c := range tm.MergedTree(false).Commands {
+		if c.Formula == nil || c.Formula.Path == "" || (repo != "" && c.Repo != repo) {
+			continue
+		}
+		defs = append(defs, definition(formula.CommandPath(c), c.Repo, *c.Formula))
+	}
+	return defs
+}
+
+type prepullCmd struct {
+	formula.TreeManager
+	formula.ImageCache
+}
+
+// NewPrepullCmd creates the prepull command, it pulls the images of the installed formulas
+func NewPrepullCmd(tm formula.TreeManager, ic formula.ImageCache) *cobra.Command {
+	p := prepullCmd{tm, ic}
+
+	cmd := &cobra.Command{
+		Use:     "prepull",
+		Short:   "Pull the docker images of the installed formulas",
+		Long:    descPrepullLong,
+		Example: "rit prepull\nrit prepull --repo commons --parallelism 2",
+		Args:    cobra.NoArgs,
+		RunE:    p.runFunc(),
+	}
+
+	flags := cmd.Flags()
+	flags.String(repoFlag, "", "Only the images of the formulas of the repo")
+	flags.Int(parallelismFlag, 4, "Number of images pulled at the same time")
+
+	return cmd
+}
+
+func (p prepullCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		repo, err := cmd.Flags().GetString(repoFlag)
+		if err != nil {
+			return err
+		}
+		parallelism, err := cmd.Flags().GetInt(parallelismFlag)
+		if err != nil {
+			return err
+		} else if parallelism < 1 {
+			return ErrInvalidParallelism
+		}
+
+		pulls, err := p.PullAll(formulaDefinitions(p.TreeManager, repo), parallelism)
+		if err != nil {
+			return err
+		}
+		if len(pulls) == 0 {
+			prompt.Info(msgNoImagesPull)
+			return nil
+		}
+
+		table := uitable.New()
+		table.AddRow("IMAGE", "FORMULAS", "STATUS")
+		var pulled int
+		for _, pl := range pulls {
+			status := prompt.Green("pulled")
+			if pl.Err != nil {
+				status = prompt.Red(pl.Err.Error())
+			} else {
+				pulled++
+			}
+			table.AddRow(pl.Image, strings.Join(pl.Formulas, ", "), status)
+		}
+		fmt.Println(table)
+
+		if pulled < len(pulls) {
+			return ErrPrepullFailed
+		}
+		prompt.Success(fmt.Sprintf(msgPrepulled, pulled, len(pulls)))
+		return nil
+	}
+}
+
+type cleanImagesCmd struct {
+	formula.TreeManager
+	formula.ImageCache
+	prompt.InputBool
+}
+
+// NewCleanImagesCmd creates the clean images command, it removes the docker images rit left behind
+func NewCleanImagesCmd(tm formula.TreeManager, ic formula.ImageCache, ib prompt.InputBool) *cobra.Command {
+	c := cleanImagesCmd{tm, ic, ib}
+
+	cmd := &cobra.Command{
+		Use:     "images",
+		Short:   "Remove the unused docker images of the formulas",
+		Long:    descCleanImagesLong,
+		Example: "rit clean images\nrit clean images --dry-run",
+		Args:    cobra.NoArgs,
+		RunE:    c.runFunc(),
+	}
+	cmd.Flags().Bool(dryRunFlag, false, "List the unused images without removing them")
+
+	return cmd
+}
+
+func (c cleanImagesCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		unused, err := c.Unused(formulaDefinitions(c.TreeManager, ""))
+		if err != nil {
+			return err
+		}
+		if len(unused) == 0 {
+			prompt.Info(msgNoUnused)
+			return nil
+		}
+
+		for _, img := range unused {
+			fmt.Println(img)
+		}
+		if boolFlag(cmd, dryRunFlag) {
+			return nil
+		}
+
+		choice, err := newConfirmer(cmd, c.InputBool, nil).Confirm(fmt.Sprintf("the %d docker images above", len(unused)))
+		if err != nil {
+			return err
+		}
+		if !choice {
+			prompt.Print("Operation cancelled")
+			return nil
+		}
+
+		if err := c.Remove(unused); err != nil {
+			return err
+		}
+		prompt.Success(fmt.Sprintf(msgRemovedImages, len(unused)))
+		return nil
+	}
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+type imageCacheMock struct {
+	pulls       []formula.ImagePull
+	unused      []string
+	parallelism *int
+	defs        *[]string
+	removed     *[]string
+}
+
+func (m imageCacheMock) PullAll(defs []formula.Definition, parallelism int) ([]formula.ImagePull, error) {
+	*m.parallelism = parallelism
+	for _, d := range defs {
+		*m.defs = append(*m.defs, d.Command)
+	}
+	return m.pulls, nil
+}
+
+func (m imageCacheMock) Unused([]formula.Definition) ([]string, error) {
+	return m.unused, nil
+}
+
+func (m imageCacheMock) Remove(images []string) error {
+	*m.removed = append(*m.removed, images...)
+	return nil
+}
+
+var imagesTree = treeMock{tree: formula.Tree{Commands: api.Commands{
+	{Parent: "root", Usage: "aws", Help: "aws commands"},
+	{Parent: "root_aws", Usage: "create", Formula: &api.Formula{Path: "aws/create"}, Repo: "commons"},
+	{Parent: "root", Usage: "deploy", Formula: &api.Formula{Path: "deploy"}, Repo: "team"},
+}}}
+
+func TestPrepullCmd(t *testing.T) {
+	failed := formula.ImagePull{Image: "node:14", Formulas: []string{"rit deploy"}, Err: errors.New("manifest unknown")}
+	pulled := formula.ImagePull{Image: "alpine:3.12", Formulas: []string{"rit aws create", "rit deploy"}}
+
+	tests := []struct {
+		name            string
+		args            []string
+		pulls           []formula.ImagePull
+		wantDefs        []string
+		wantParallelism int
+		wantOut         string
+		wantErr         error
+	}{
+		{
+			name:            "all formulas",
+			pulls:           []formula.ImagePull{pulled},
+			wantDefs:        []string{"rit aws create", "rit deploy"},
+			wantParallelism: 4,
+			wantOut:         "rit aws create, rit deploy",
+		},
+		{
+			name:            "formulas of the repo",
+			args:            []string{"--repo", "team", "--parallelism", "1"},
+			pulls:           []formula.ImagePull{pulled, failed},
+			wantDefs:        []string{"rit deploy"},
+			wantParallelism: 1,
+			wantOut:         "manifest unknown",
+			wantErr:         ErrPrepullFailed,
+		},
+		{name: "invalid parallelism", args: []string{"--parallelism", "0"}, wantErr: ErrInvalidParallelism},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parallelism int
+			var defs []string
+			cmd := NewPrepullCmd(imagesTree, imageCacheMock{pulls: tt.pulls, parallelism: &parallelism, defs: &defs})
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			cmd.SetArgs(tt.args)
+
+			var err error
+			out := captureStdout(func() { err = cmd.Execute() })
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("prepull error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == ErrInvalidParallelism {
+				return
+			}
+			if !reflect.DeepEqual(defs, tt.wantDefs) || parallelism != tt.wantParallelism {
+				t.Errorf("prepull pulled %v with %d, want %v with %d", defs, parallelism, tt.wantDefs, tt.wantParallelism)
+			}
+			if !strings.Contains(out, tt.wantOut) {
+				t.Errorf("prepull printed %q, want %q", out, tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestCleanImagesCmd(t *testing.T) {
+	unused := []string{"3f9b7a2c-1d2e-4f5a-8b6c-7d8e9f0a1b2c", "golang:1.14"}
+
+	tests := []struct {
+		name        string
+		args        []string
+		inputBool   prompt.InputBool
+		unused      []string
+		wantRemoved []string
+	}{
+		{name: "confirmed", inputBool: inputTrueMock{}, unused: unused, wantRemoved: unused},
+		{name: "--yes", args: []string{"--yes"}, inputBool: inputFalseMock{}, unused: unused, wantRemoved: unused},
+		{name: "cancelled", inputBool: inputFalseMock{}, unused: unused},
+		{name: "--dry-run", args: []string{"--dry-run"}, inputBool: inputTrueMock{}, unused: unused},
+		{name: "nothing unused", inputBool: inputTrueMock{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var removed []string
+			root := &cobra.Command{Use: cmdUse, SilenceErrors: true, SilenceUsage: true}
+			addConfirmFlags(root)
+			root.AddCommand(NewCleanImagesCmd(imagesTree, imageCacheMock{unused: tt.unused, removed: &removed}, tt.inputBool))
+			root.SetArgs(append([]string{"images"}, tt.args...))
+
+			var err error
+			out := captureStdout(func() { err = root.Execute() })
+			if err != nil {
+				t.Fatalf("clean images error = %v", err)
+			}
+			if !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("clean images removed %v, want %v", removed, tt.wantRemoved)
+			}
+			for _, img := range tt.unused {
+				if !strings.Contains(out, img) {
+					t.Errorf("clean images printed %q, want %q", out, img)
+				}
+			}
+		})
+	}
+}
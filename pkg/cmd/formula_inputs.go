@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runner"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	// inputFlagAnnotation marks the flags of the formula inputs, its value is the input name
+	inputFlagAnnotation = "rit_input"
+	msgInputFlagsConfig = "The input flags of %s can't be added: %v"
+)
+
+// AddInputFlags adds the flags of the inputs, see addInputFlags, to the formula command invoked
+// by args, without the binary name, before its flags are parsed. Only the config.json of that
+// formula is read. A config that can't be read is warned and adds no flag, the run reports why.
+func (f FormulaCommand) AddInputFlags(rootCmd *cobra.Command, args []string) {
+	cmd, _, err := rootCmd.Find(args)
+	if err != nil {
+		return
+	}
+	c, ok := f.formulas[cmd.CommandPath()]
+	if !ok || c.Formula == nil {
+		return
+	}
+
+	config, err := f.configs.LoadConfig(definition(cmd.CommandPath(), c.Repo, *c.Formula))
+	if err != nil {
+		prompt.Warning(fmt.Sprintf(msgInputFlagsConfig, cmd.CommandPath(), err))
+		return
+	}
+	addInputFlags(cmd, config.Inputs)
+}
+
+// addInputFlags adds a --name flag for each input informed by env, see runner.InputEnvPrefix,
+// a bool, int or float flag, with the input default, for the bool, int and float inputs and a
+// string one for the others. The inputs named as a flag of rit have no flag, they are informed
+// by their env var, and the secret ones neither, so that they aren't kept on the shell history.
+func addInputFlags(cmd *cobra.Command, inputs []formula.Input) {
+	flags := cmd.Flags()
+	for _, in := range inputs {
+		switch in.Type {
+		case "text", "bool", formula.IntType, formula.FloatType, "password", formula.EditorType, formula.CredentialType, formula.MultiselectType, formula.PathType:
+		default:
+			continue
+		}
+
+		if flags.Lookup(in.Name) != nil || cmd.InheritedFlags().Lookup(in.Name) != nil {
+			continue
+		}
+
+		switch in.Type {
+		case "bool":
+			v, _ := strconv.ParseBool(in.Default)
+			flags.Bool(in.Name, v, in.Label)
+		case formula.IntType:
+			v, _ := strconv.ParseInt(in.Default, 10, 64)
+			flags.Int64(in.Name, v, in.Label)
+		case formula.FloatType:
+			v, _ := strconv.ParseFloat(in.Default, 64)
+			flags.Float64(in.Name, v, in.Label)
+		default:
+			flags.String(in.Name, "", in.Label)
+		}
+		_ = flags.SetAnnotation(in.Name, inputFlagAnnotation, []string{in.Name})
+	}
+}
+
+// inputFlagsEnv returns the env vars informing the inputs of the flags set, e.g. RIT_INPUT_REGION,
+// so that they aren't prompted, with --stdin the inputs of the JSON take precedence
+func inputFlagsEnv(cmd *cobra.Command) []string {
+	var env []string
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		name, ok := f.Annotations[inputFlagAnnotation]
+		if !ok || len(name) == 0 {
+			return
+		}
+		env = append(env, fmt.Sprintf(formula.EnvPattern, runner.InputEnvName(name[0]), f.Value.String()))
+	})
+	return env
+}
+
+// inputsFileEnv returns the env vars informing the inputs of the --inputs-file, see formula.ReadInputsFile
+func inputsFileEnv(file string) ([]string, error) {
+	values, err := formula.ReadInputsFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return valuesEnv(values), nil
+}
+
+// valuesEnv returns the env vars informing the input values by name, sorted by name
+func valuesEnv(values map[string]string) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	env := make([]string, 0, len(names))
+	for _, name := range names {
+		env = append(env, fmt.Sprintf(formula.EnvPattern, runner.InputEnvName(name), values[name]))
+	}
+	return env
+}
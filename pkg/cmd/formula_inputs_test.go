@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestFormulaCommand_InputFlags(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+	config := formula.Config{Inputs: []formula.Input{
+		{Name: "region", Type: "text", Label: "AWS region"},
+		{Name: "public", Type: "bool", Label: "Public bucket?"},
+		{Name: "timeout", Type: "text", Label: "Lambda timeout"},
+		{Name: "token", Type: "CREDENTIAL_GITHUB_TOKEN"},
+		{Name: "apiKey", Type: formula.SecretType},
+		{Name: "replicas", Type: formula.IntType, Default: "2"},
+		{Name: "ratio", Type: formula.FloatType},
+	}}
+
+	dir, err := ioutil.TempDir("", "rit-inputs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	inputsFile := filepath.Join(dir, "prod.yaml")
+	if err := ioutil.WriteFile(inputsFile, []byte("region: us-east-1\npublic: false\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		loader      configLoaderMock
+		wantEnv     []string
+		wantArgs    []string
+		wantErr     string
+		wantTimeout bool
+	}{
+		{
+			name:    "input flags",
+			args:    []string{"mock", "test", "--region", "sa-east-1", "--public"},
+			loader:  configLoaderMock{config: config},
+			wantEnv: []string{"RIT_INPUT_PUBLIC=true", "RIT_INPUT_REGION=sa-east-1"},
+		},
+		{
+			name:     "input flags and formula args",
+			args:     []string{"mock", "test", "--region=us-east-1", "--", "--dry-run"},
+			loader:   configLoaderMock{config: config},
+			wantEnv:  []string{"RIT_INPUT_REGION=us-east-1"},
+			wantArgs: []string{"--dry-run"},
+		},
+		{
+			name:        "input named as a flag of rit",
+			args:        []string{"mock", "test", "--timeout", "5m"},
+			loader:      configLoaderMock{config: config},
+			wantTimeout: true,
+		},
+		{
+			name:    "inputs file and flags",
+			args:    []string{"mock", "test", "--inputs-file", inputsFile, "--region", "sa-east-1"},
+			loader:  configLoaderMock{config: config},
+			wantEnv: []string{"RIT_INPUT_PUBLIC=false", "RIT_INPUT_REGION=us-east-1", "RIT_INPUT_REGION=sa-east-1"},
+		},
+		{
+			name:    "typed input flags",
+			args:    []string{"mock", "test", "--replicas", "3", "--ratio", "0.5"},
+			loader:  configLoaderMock{config: config},
+			wantEnv: []string{"RIT_INPUT_RATIO=0.5", "RIT_INPUT_REPLICAS=3"},
+		},
+		{
+			name:    "invalid int input flag",
+			args:    []string{"mock", "test", "--replicas", "three"},
+			loader:  configLoaderMock{config: config},
+			wantErr: `invalid argument "three" for "--replicas" flag`,
+		},
+		{
+			name:    "missing inputs file",
+			args:    []string{"mock", "test", "--inputs-file", filepath.Join(dir, "dev.yaml")},
+			loader:  configLoaderMock{config: config},
+			wantErr: "dev.yaml",
+		},
+		{
+			name:   "help with the input flags",
+			args:   []string{"mock", "test", "--help"},
+			loader: configLoaderMock{config: config},
+		},
+		{
+			name:    "credential input",
+			args:    []string{"mock", "test", "--token", "ghp"},
+			loader:  configLoaderMock{config: config},
+			wantErr: "unknown flag: --token",
+		},
+		{
+			name:    "secret input",
+			args:    []string{"mock", "test", "--apiKey", "s3cr3t"},
+			loader:  configLoaderMock{config: config},
+			wantErr: "unknown flag: --apiKey",
+		},
+		{
+			name:    "config not loaded",
+			args:    []string{"mock", "test", "--region", "sa-east-1"},
+			loader:  configLoaderMock{err: errors.New("config not found")},
+			wantErr: "unknown flag: --region",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			var def formula.Definition
+			spy := runnerSpyMock{def: &def}
+			formulaCmd := NewFormulaCommand(api.CoreCmds, treeMock, spy, spy, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, tt.loader, nil, RunDefaults{})
+			if err := formulaCmd.Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			formulaCmd.AddInputFlags(rootCmd, tt.args)
+			rootCmd.SetArgs(tt.args)
+
+			err := rootCmd.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("%s = %v, want %q", rootCmd.Use, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s = %v, want nil", rootCmd.Use, err)
+			}
+			if !reflect.DeepEqual(def.Env, tt.wantEnv) {
+				t.Errorf("env = %q, want %q", def.Env, tt.wantEnv)
+			}
+			if !reflect.DeepEqual(def.Args, tt.wantArgs) {
+				t.Errorf("args = %q, want %q", def.Args, tt.wantArgs)
+			}
+			if got := def.Timeout > 0; got != tt.wantTimeout {
+				t.Errorf("timeout = %v, want the --timeout of rit %v", def.Timeout, tt.wantTimeout)
+			}
+		})
+	}
+}
@@ -1,23 +1,124 @@
 package cmd
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
 
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/detach"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runner"
 	"github.com/ZupIT/ritchie-cli/pkg/slice/sliceutil"
 )
 
 const (
-	subCommand  = " SUBCOMMAND"
-	Group       = "group"
-	dockerFlag  = "docker"
-	verboseFlag = "verbose"
-	RootCmd     = "root"
+	subCommand           = " SUBCOMMAND"
+	formulaArgs          = " [-- ARGS]"
+	Group                = "group"
+	dockerFlag           = "docker"
+	verboseFlag          = "verbose"
+	quietFlag            = "quiet"
+	allowDeprecatedFlag  = "allow-deprecated"
+	prePullFlag          = "pre-pull"
+	onFailureFlag        = "on-failure"
+	maxRetriesFlag       = "max-retries"
+	retryDelayFlag       = "retry-delay"
+	retryBackoffFlag     = "retry-backoff"
+	retryOnFlag          = "retry-on"
+	sessionFlag          = "session"
+	sessionStopFlag      = "session-stop"
+	isolateFlag          = "isolate"
+	timeoutFlag          = "timeout"
+	killGraceFlag        = "timeout-kill-grace"
+	labelFlag            = "label"
+	captureMetricsFlag   = "capture-metrics"
+	profileFlag          = "profile"
+	profileJSONFlag      = "profile-json"
+	requiredFirstFlag    = "required-first"
+	inputTimeoutFlag     = "input-timeout-default"
+	inputTimeoutFailFlag = "input-timeout-fail"
+	defaultFlag          = "default"
+	entrypointFlag       = "entrypoint"
+	commandFlag          = "command"
+	printCommandFlag     = "print-command"
+	runnerFlag           = "runner"
+	sshFlag              = "ssh"
+	kubernetesFlag       = "kubernetes"
+	envFileFlag          = "env-file"
+	envPassthroughFlag   = "env-passthrough"
+	inputsFileFlag       = "inputs-file"
+	timestampsFlag       = "timestamps"
+	cpusFlag             = "cpus"
+	memoryFlag           = "memory"
+	volumeFlag           = "volume"
+	sandboxFlag          = "sandbox"
+	sandboxNetworkFlag   = "sandbox-network"
+	deprecatedSuffix     = " (deprecated)"
+	RootCmd              = "root"
+	msgDockerFallback    = "Docker is installed but the daemon isn't running, running the formula locally instead"
+	msgFormulaSunset     = "%s\nThis formula has reached its sunset date, use --%s to run it anyway"
+	msgOnFailure         = "%s failed, running %s"
+	msgOnFailureError    = "The on-failure formula %q failed: %v"
+	msgRetry             = "Attempt %d/%d failed with exit code %d, retrying in %v"
+	msgRetrySucceeded    = "%s succeeded on attempt %d/%d"
+	msgRetriesExhausted  = "%s failed on all the %d attempts"
+	msgInvalidRetry      = "the retry of the config.json of %s needs a maxRetries not negative and a backoff duration, e.g. 10s"
+	maxRetryBackoff      = 5 * time.Minute
+	msgSessionStopped    = "The session of %s was stopped"
+	msgOverride          = "%s runs %q instead of the formula, bypassing what it is meant to do"
+	msgFormulaArgs       = "%s\n\nThe ARGS after -- are passed verbatim to the formula: as its arguments when it runs locally,\n" +
+		"and shell quoted on the FORMULA_ARGS env var, e.g. eval set -- \"$FORMULA_ARGS\"\n\n" +
+		"With --session the formula runs inside a docker container kept between the runs, skipping the image\n" +
+		"build and the container start. The container sleeps while idle, holding the memory of the processes\n" +
+		"the runs left behind, until --session-stop removes it with its image or docker removes it an hour\n" +
+		"after it started. Stop the session to run the formula changes made since it started.\n\n" +
+		"The container runs use docker, or podman with the formula.runner config or --runner podman,\n" +
+		"e.g. where the docker daemon isn't allowed. --runner implies --docker.\n\n" +
+		"Each input of the formula config.json has a flag of its name, e.g. --region sa-east-1, so the formula runs\n" +
+		"without prompts. The inputs named as a flag of rit are informed by their RIT_INPUT_ env var instead.\n" +
+		"--inputs-file reads the values of the inputs from a YAML or JSON file, e.g. envs/prod.yaml kept on git.\n\n" +
+		"With --ssh the formula runs on a remote host with its inputs asked here. The build of the formula\n" +
+		"for this OS is copied to the host, which must run the same OS, and the files it writes aren't copied back.\n\n" +
+		"With --kubernetes the formula image runs as a Kubernetes Job, on the cluster and namespace of the kubernetes\n" +
+		"configs. The image is pushed to the kubernetes.registry config, the inputs asked here are sent on a Secret\n" +
+		"removed after the run and the job logs are streamed. The formula has no stdin nor the files of this dir.\n\n" +
+		"The outputs a formula declares on its config.json are written as NAME=VALUE lines on the file of the\n" +
+		"RIT_OUTPUTS_FILE env var and printed after the run. With --output json or yaml they are the only stdout\n" +
+		"of rit, the formula stdout and the messages of rit go to stderr."
+)
+
+var (
+	ErrNegativeRetries       = errors.New("--max-retries must not be negative")
+	ErrNegativeBackoff       = errors.New("--retry-backoff must not be negative")
+	ErrRetryDelayAndBackoff  = errors.New("--retry-delay waits the same on every retry, don't use it with --retry-backoff")
+	ErrNegativeTimeout       = errors.New("--timeout must not be negative")
+	ErrNegativeKillGrace     = errors.New("--timeout-kill-grace must not be negative")
+	ErrNegativeInputTimeout  = errors.New("--input-timeout-default must not be negative")
+	ErrInvalidProfile        = errors.New("--profile must be cpu or mem")
+	ErrProfileJSONNoProfile  = errors.New("--profile-json formats the --profile report, use it with --profile")
+	ErrEntrypointNeedsDocker = errors.New("--entrypoint overrides the formula image, use it with --docker and without --session")
+	ErrCommandNeedsLocal     = errors.New("--command overrides the local run, use --entrypoint to run on docker")
+	ErrSSHNeedsLocal         = errors.New("--ssh runs the local build of the formula on the host, don't use it with --docker, --runner, --session or --command")
+	ErrKubernetesFlags       = errors.New("--kubernetes runs the formula image as a job, don't use it with --session, --isolate, --ssh, --entrypoint or --command")
+	ErrOutputNeedsLocal      = errors.New("--output prints the outputs of a local or docker run, don't use it with --session, --ssh or --kubernetes")
+	ErrResourcesNeedDocker   = errors.New("--cpus and --memory limit the container of a docker run, use them with --docker or --session")
+	ErrVolumeNeedsDocker     = errors.New("--volume mounts a host path on the container of a docker run, use it with --docker or --session")
+	ErrSandboxNeedsLocal     = errors.New("--sandbox runs a local formula, a container is already a sandbox, don't use it with --docker, --runner, --session, --ssh or --kubernetes")
+	ErrNetworkNeedsSandbox   = errors.New("--sandbox-network keeps the network of a --sandbox run, use it with --sandbox")
 )
 
 type FormulaCommand struct {
@@ -25,18 +126,49 @@ type FormulaCommand struct {
 	treeManager   formula.TreeManager
 	defaultRunner formula.Runner
 	dockerRunner  formula.Runner
+	remoteRunner  formula.Runner
+	kubeRunner    formula.Runner
+	dockerPuller  formula.Puller
+	sessions      formula.SessionStopper
+	configs       formula.ConfigLoader
+	detacher      detach.Starter
+	defaults      RunDefaults
+	formulas      map[string]api.Command
+}
+
+// RunDefaults are the defaults of the formula runs from the config, the flags override them
+type RunDefaults struct {
+	// Runner is the formula.runner config, see formula.Definition.Runner
+	Runner string
+	// Timeout is the formula.timeout config, zero meaning the formulas run until they exit
+	Timeout time.Duration
 }
 
 func NewFormulaCommand(
 	coreCmds api.Commands,
 	treeManager formula.TreeManager,
 	defaultRunner formula.Runner,
-	dockerRunner formula.Runner) *FormulaCommand {
+	dockerRunner formula.Runner,
+	remoteRunner formula.Runner,
+	kubeRunner formula.Runner,
+	dockerPuller formula.Puller,
+	sessions formula.SessionStopper,
+	configs formula.ConfigLoader,
+	detacher detach.Starter,
+	defaults RunDefaults) *FormulaCommand {
 	return &FormulaCommand{
 		coreCmds:      coreCmds,
 		treeManager:   treeManager,
 		defaultRunner: defaultRunner,
 		dockerRunner:  dockerRunner,
+		remoteRunner:  remoteRunner,
+		kubeRunner:    kubeRunner,
+		dockerPuller:  dockerPuller,
+		sessions:      sessions,
+		configs:       configs,
+		detacher:      detacher,
+		defaults:      defaults,
+		formulas:      make(map[string]api.Command),
 	}
 }
 
@@ -51,6 +183,7 @@ func (f FormulaCommand) Add(rootCmd *cobra.Command) error {
 			var newCmd *cobra.Command
 			if cmd.Formula != nil && cmd.Formula.Path != "" {
 				newCmd = f.newFormulaCmd(cmd)
+				f.formulas[formula.CommandPath(cmd)] = cmd
 			} else {
 				newCmd = newSubCmd(cmd)
 			}
@@ -65,6 +198,12 @@ func (f FormulaCommand) Add(rootCmd *cobra.Command) error {
 	return nil
 }
 
+// Formula finds the tree command of a formula added by Add
+func (f FormulaCommand) Formula(path string) (api.Command, bool) {
+	c, ok := f.formulas[path]
+	return c, ok
+}
+
 func newSubCmd(cmd api.Command) *cobra.Command {
 	var group string
 	if cmd.Parent == RootCmd {
@@ -82,30 +221,202 @@ func newSubCmd(cmd api.Command) *cobra.Command {
 }
 
 func (f FormulaCommand) newFormulaCmd(cmd api.Command) *cobra.Command {
+	long := cmd.Help
+	if cmd.LongHelp != "" {
+		long = cmd.LongHelp
+	}
+
 	formulaCmd := &cobra.Command{
-		Use:   cmd.Usage,
-		Short: cmd.Help,
-		Long:  cmd.Help,
+		Use:         cmd.Usage + formulaArgs,
+		Short:       cmd.Help,
+		Long:        long,
+		Example:     formulaExamples(cmd.Examples),
+		Annotations: map[string]string{FormulaAnnotation: cmd.Repo},
+	}
+
+	if cmd.Deprecation != nil {
+		formulaCmd.Short += deprecatedSuffix
+		formulaCmd.Long = fmt.Sprintf("%s\n\n%s", long, formula.DeprecationMsg(formula.CommandPath(cmd), *cmd.Deprecation))
 	}
+	formulaCmd.Long = fmt.Sprintf(msgFormulaArgs, formulaCmd.Long)
 
 	addFlags(formulaCmd)
-	formulaCmd.RunE = f.execFormulaFunc(cmd.Repo, *cmd.Formula)
+	formulaCmd.RunE = f.execFormulaFunc(cmd.Repo, *cmd.Formula, cmd.Deprecation)
 
 	return formulaCmd
 }
 
-func (f FormulaCommand) execFormulaFunc(repo string, form api.Formula) func(cmd *cobra.Command, args []string) error {
+// formulaExamples formats the formula examples as the cobra examples, each command
+// preceded by its description as a comment
+func formulaExamples(examples []api.Example) string {
+	lines := make([]string, 0, 2*len(examples))
+	for _, e := range examples {
+		if e.Description != "" {
+			lines = append(lines, "  # "+e.Description)
+		}
+		lines = append(lines, "  "+e.Command)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (f FormulaCommand) execFormulaFunc(repo string, form api.Formula, dep *api.Deprecation) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
-		d := formula.Definition{
-			Path:     form.Path,
-			Bin:      form.Bin,
-			LBin:     form.LBin,
-			MBin:     form.MBin,
-			WBin:     form.WBin,
-			Bundle:   form.Bundle,
-			Config:   form.Config,
-			RepoURL:  form.RepoURL,
-			RepoName: repo,
+		if err := checkDeprecation(cmd, dep); err != nil {
+			return err
+		}
+
+		d := definition(cmd.CommandPath(), repo, form)
+		d.Args = passthroughArgs(cmd, args)
+		if err := f.setRunner(cmd, &d); err != nil {
+			return err
+		}
+
+		if boolFlag(cmd, prePullFlag) {
+			return f.dockerPuller.Pull(d)
+		}
+
+		if boolFlag(cmd, sessionStopFlag) {
+			if err := f.sessions.StopSession(d); err != nil {
+				return err
+			}
+			prompt.Success(fmt.Sprintf(msgSessionStopped, d.Command))
+			return nil
+		}
+
+		if boolFlag(cmd, detachFlag) {
+			return runDetached(f.detacher, cmd, d.Args)
+		}
+
+		count, parallelism, err := countFlags(cmd)
+		if err != nil {
+			return err
+		} else if count > 1 {
+			return runCount(cmd, count, parallelism, d.Args)
+		}
+
+		d.Session = boolFlag(cmd, sessionFlag)
+		d.Isolate = boolFlag(cmd, isolateFlag)
+		d.RequiredFirst = boolFlag(cmd, requiredFirstFlag)
+		d.PrintCommand = boolFlag(cmd, printCommandFlag)
+		d.DryRun = boolFlag(cmd, dryRunFlag)
+		d.Timestamps = boolFlag(cmd, timestampsFlag)
+		d.LogID = detachedLogID()
+
+		inputTimeout, err := cmd.Flags().GetDuration(inputTimeoutFlag)
+		if err != nil {
+			return err
+		} else if inputTimeout < 0 {
+			return ErrNegativeInputTimeout
+		}
+		d.InputTimeout = inputTimeout
+		d.InputTimeoutFail = boolFlag(cmd, inputTimeoutFailFlag)
+		d.AcceptDefaults = boolFlag(cmd, defaultFlag)
+
+		// the formula.timeout config is the timeout of the runs without --timeout, --timeout 0 disables it
+		d.Timeout = f.defaults.Timeout
+		if cmd.Flags().Changed(timeoutFlag) {
+			if d.Timeout, err = cmd.Flags().GetDuration(timeoutFlag); err != nil {
+				return err
+			}
+		}
+		if d.Timeout < 0 {
+			return ErrNegativeTimeout
+		}
+
+		killGrace, err := cmd.Flags().GetDuration(killGraceFlag)
+		if err != nil {
+			return err
+		} else if killGrace < 0 {
+			return ErrNegativeKillGrace
+		}
+		d.KillGrace = killGrace
+
+		labels, err := cmd.Flags().GetStringArray(labelFlag)
+		if err != nil {
+			return err
+		}
+		if d.Labels, err = formula.ParseLabels(labels); err != nil {
+			return err
+		}
+
+		// the passed through host vars come first, the env files and the inputs replace them
+		patterns, err := cmd.Flags().GetStringArray(envPassthroughFlag)
+		if err != nil {
+			return err
+		}
+		if d.Env, err = formula.PassthroughEnv(os.Environ(), patterns); err != nil {
+			return err
+		}
+
+		// a later file replaces the vars of the previous ones, the inputs replace them all
+		envFiles, err := cmd.Flags().GetStringArray(envFileFlag)
+		if err != nil {
+			return err
+		}
+		for _, f := range envFiles {
+			env, err := formula.ReadEnvFile(f)
+			if err != nil {
+				return err
+			}
+			d.Env = append(d.Env, env...)
+		}
+		// the input flags take precedence over the inputs file
+		inputsFile, err := cmd.Flags().GetString(inputsFileFlag)
+		if err != nil {
+			return err
+		} else if inputsFile != "" {
+			env, err := inputsFileEnv(inputsFile)
+			if err != nil {
+				return err
+			}
+			d.Env = append(d.Env, env...)
+		}
+		d.Env = append(d.Env, inputFlagsEnv(cmd)...)
+
+		// the formula runs on its own working dir, so the file is resolved from the user one
+		if capture, _ := cmd.Flags().GetString(captureMetricsFlag); capture != "" {
+			if d.CaptureMetrics, err = filepath.Abs(capture); err != nil {
+				return err
+			}
+		}
+
+		if err := setProfile(cmd, &d); err != nil {
+			return err
+		}
+
+		if err := setOverride(cmd, &d); err != nil {
+			return err
+		}
+
+		if d.Remote, err = cmd.Flags().GetString(sshFlag); err != nil {
+			return err
+		} else if d.Remote != "" && (containerRun(cmd) || d.Session || d.CommandOverride != "") {
+			return ErrSSHNeedsLocal
+		}
+
+		d.Kubernetes = boolFlag(cmd, kubernetesFlag)
+		if d.Kubernetes && (d.Session || d.Isolate || d.Remote != "" || d.Entrypoint != "" || d.CommandOverride != "") {
+			return ErrKubernetesFlags
+		}
+
+		if err := setResources(cmd, &d); err != nil {
+			return err
+		}
+
+		if err := setSandbox(cmd, &d); err != nil {
+			return err
+		}
+
+		if d.OutputFormat, err = outputFormat(cmd); err != nil {
+			return err
+		} else if d.OutputFormat != "" && (d.Session || d.Remote != "" || d.Kubernetes) {
+			return ErrOutputNeedsLocal
+		}
+
+		// the stdout of rit is the outputs of the run, the messages of rit go to stderr
+		if d.OutputFormat != "" {
+			defer func(w io.Writer) { prompt.Stdout = w }(prompt.Stdout)
+			prompt.Stdout = os.Stderr
 		}
 
 		stdin, err := cmd.Flags().GetBool(api.Stdin.ToLower())
@@ -117,29 +428,517 @@ func (f FormulaCommand) execFormulaFunc(repo string, form api.Formula) func(cmd
 			inputType = api.Stdin
 		}
 
-		docker, err := cmd.Flags().GetBool(dockerFlag)
+		onFailure, err := cmd.Flags().GetString(onFailureFlag)
 		if err != nil {
 			return err
 		}
 
-		v, err := cmd.Flags().GetBool(verboseFlag)
-
+		policy, err := newRetryPolicy(cmd, d)
 		if err != nil {
 			return err
 		}
 
-		verbose := strconv.FormatBool(v)
+		// the stdin inputs are kept to be replayed to the retries and the on-failure formula
+		var stdinInputs []byte
+		if stdin && (onFailure != "" || policy.maxRetries > 0) {
+			if stdinInputs, err = ioutil.ReadAll(os.Stdin); err != nil {
+				return err
+			}
+		}
 
-		if docker {
-			return f.dockerRunner.Run(d, inputType, verbose)
+		d.Inputs = make(map[string]string)
+		runErr := f.runRetrying(cmd, d, inputType, policy, stdinInputs)
+		if err := saveCountInputs(d.Inputs); err != nil {
+			return err
+		}
+		if runErr == nil || onFailure == "" {
+			return runErr
+		}
+
+		if err := f.runOnFailure(cmd, onFailure, d, runErr, inputType, stdinInputs); err != nil {
+			prompt.Error(fmt.Sprintf(msgOnFailureError, onFailure, err))
 		}
+		return runErr
+	}
+}
+
+// setProfile sets the --profile report of the run
+func setProfile(cmd *cobra.Command, d *formula.Definition) error {
+	profile, err := cmd.Flags().GetString(profileFlag)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case profile != "" && profile != formula.ProfileCPU && profile != formula.ProfileMem:
+		return ErrInvalidProfile
+	case profile == "" && boolFlag(cmd, profileJSONFlag):
+		return ErrProfileJSONNoProfile
+	}
+	d.Profile = profile
+	d.ProfileJSON = boolFlag(cmd, profileJSONFlag)
+	return nil
+}
+
+// setRunner sets the runner of the --runner flag on the definition, the formula.runner config without it
+func (f FormulaCommand) setRunner(cmd *cobra.Command, d *formula.Definition) error {
+	name, err := cmd.Flags().GetString(runnerFlag)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = f.defaults.Runner
+	}
+	if err := runner.ValidRunner(name); err != nil {
+		return err
+	}
+	d.Runner = name
+	return nil
+}
+
+// containerRun tells whether the formula runs on a container, --runner implies --docker
+func containerRun(cmd *cobra.Command) bool {
+	return boolFlag(cmd, dockerFlag) || cmd.Flags().Changed(runnerFlag)
+}
+
+// setOverride sets the --entrypoint of a docker run or the --command of a local run,
+// warning that the formula is bypassed. The runners fail unless the formula allows it.
+func setOverride(cmd *cobra.Command, d *formula.Definition) error {
+	entrypoint, err := cmd.Flags().GetString(entrypointFlag)
+	if err != nil {
+		return err
+	}
 
-		return f.defaultRunner.Run(d, inputType, verbose)
+	command, err := cmd.Flags().GetString(commandFlag)
+	if err != nil {
+		return err
 	}
+
+	docker := containerRun(cmd) && !d.Session
+	switch {
+	case entrypoint != "" && !docker:
+		return ErrEntrypointNeedsDocker
+	case command != "" && (docker || d.Session):
+		return ErrCommandNeedsLocal
+	}
+
+	d.Entrypoint = entrypoint
+	d.CommandOverride = command
+	if override := entrypoint + command; override != "" && !boolFlag(cmd, quietFlag) {
+		prompt.Warning(fmt.Sprintf(msgOverride, d.Command, override))
+	}
+	return nil
+}
+
+// setResources sets the --cpus and --memory limits and the --volume mounts of a docker run,
+// the runner validates them with the resources and the volumes of the formula config.json
+func setResources(cmd *cobra.Command, d *formula.Definition) error {
+	cpus, err := cmd.Flags().GetString(cpusFlag)
+	if err != nil {
+		return err
+	}
+	memory, err := cmd.Flags().GetString(memoryFlag)
+	if err != nil {
+		return err
+	}
+
+	volumes, err := cmd.Flags().GetStringArray(volumeFlag)
+	if err != nil {
+		return err
+	}
+
+	docker := (containerRun(cmd) || d.Session) && d.Remote == "" && !d.Kubernetes
+	switch {
+	case cpus+memory != "" && !docker:
+		return ErrResourcesNeedDocker
+	case len(volumes) > 0 && !docker:
+		return ErrVolumeNeedsDocker
+	}
+	d.Resources = formula.Resources{CPUs: cpus, Memory: memory}
+	d.Volumes = volumes
+	return nil
+}
+
+// setSandbox sets the --sandbox of a local run, the runner prepares it for the platform
+func setSandbox(cmd *cobra.Command, d *formula.Definition) error {
+	d.Sandbox = boolFlag(cmd, sandboxFlag)
+	d.SandboxNetwork = boolFlag(cmd, sandboxNetworkFlag)
+	switch {
+	case d.SandboxNetwork && !d.Sandbox:
+		return ErrNetworkNeedsSandbox
+	case d.Sandbox && (containerRun(cmd) || d.Session || d.Remote != "" || d.Kubernetes):
+		return ErrSandboxNeedsLocal
+	}
+	return nil
+}
+
+// retrySleep waits before a retry, it is a var so the waits of the retries can be replaced on tests
+var retrySleep = time.Sleep
+
+// runRetrying runs the formula again while it exits with a retryable code, up to the max retries
+// of the policy. Each attempt runs on a fresh temp workspace, as the runners prepare one on every run.
+// The inputs are asked once, the retries get the values of the first attempt by env.
+func (f FormulaCommand) runRetrying(
+	cmd *cobra.Command,
+	d formula.Definition,
+	inputType api.TermInputType,
+	policy retryPolicy,
+	stdinInputs []byte) error {
+	quiet := boolFlag(cmd, quietFlag)
+	delay := policy.delay
+	env := d.Env
+	for attempt := 1; ; attempt++ {
+		if stdinInputs != nil {
+			d.Stdin = bytes.NewReader(stdinInputs)
+		}
+		if attempt > 1 {
+			d.Env = append(append([]string{}, env...), valuesEnv(d.Inputs)...)
+		}
+
+		err := f.run(cmd, d, inputType)
+
+		// the config.json of a formula that never ran is downloaded by its first run, so its
+		// retry is read after a failed first attempt, unless the stdin inputs weren't kept
+		if err != nil && attempt == 1 && !policy.configured && (inputType != api.Stdin || stdinInputs != nil) {
+			p, policyErr := newRetryPolicy(cmd, d)
+			if policyErr != nil {
+				return policyErr
+			}
+			policy, delay = p, p.delay
+		}
+
+		attempts := policy.maxRetries + 1
+		if policy.maxRetries == 0 {
+			return err
+		}
+
+		if err == nil {
+			if attempt > 1 && !quiet {
+				prompt.Success(fmt.Sprintf(msgRetrySucceeded, d.Command, attempt, attempts))
+			}
+			return nil
+		}
+
+		if !retryable(err, policy.retryOn) {
+			return err
+		}
+
+		if attempt == attempts {
+			prompt.Error(fmt.Sprintf(msgRetriesExhausted, d.Command, attempts))
+			return err
+		}
+
+		if !quiet {
+			prompt.Warning(fmt.Sprintf(msgRetry, attempt, attempts, ExitCode(err), delay))
+		}
+		retrySleep(delay)
+		if policy.backoff {
+			if delay *= 2; delay > maxRetryBackoff {
+				delay = maxRetryBackoff
+			}
+		}
+	}
+}
+
+// retryPolicy is the retry of a run, maxRetries retries waiting delay before the first one,
+// doubled on each retry with backoff. It is configured when read with the formula config.json.
+type retryPolicy struct {
+	maxRetries int
+	delay      time.Duration
+	backoff    bool
+	retryOn    []int
+	configured bool
+}
+
+// newRetryPolicy reads the retry of the run, the retry flags that are set replace the retry
+// of the formula config.json
+func newRetryPolicy(cmd *cobra.Command, d formula.Definition) (retryPolicy, error) {
+	flags := cmd.Flags()
+	maxRetries, err := flags.GetInt(maxRetriesFlag)
+	if err != nil {
+		return retryPolicy{}, err
+	}
+	delay, err := flags.GetDuration(retryDelayFlag)
+	if err != nil {
+		return retryPolicy{}, err
+	}
+	backoff, err := flags.GetDuration(retryBackoffFlag)
+	if err != nil {
+		return retryPolicy{}, err
+	}
+	retryOn, err := flags.GetIntSlice(retryOnFlag)
+	if err != nil {
+		return retryPolicy{}, err
+	}
+
+	switch {
+	case maxRetries < 0:
+		return retryPolicy{}, ErrNegativeRetries
+	case backoff < 0:
+		return retryPolicy{}, ErrNegativeBackoff
+	case flags.Changed(retryBackoffFlag) && flags.Changed(retryDelayFlag):
+		return retryPolicy{}, ErrRetryDelayAndBackoff
+	}
+
+	retry, configured, err := configRetry(d)
+	if err != nil {
+		return retryPolicy{}, err
+	}
+	if retry != nil {
+		if !flags.Changed(maxRetriesFlag) {
+			maxRetries = retry.MaxRetries
+		}
+		if !flags.Changed(retryOnFlag) {
+			retryOn = retry.On
+		}
+		if retry.Backoff != "" && !flags.Changed(retryBackoffFlag) && !flags.Changed(retryDelayFlag) {
+			backoff, err = time.ParseDuration(retry.Backoff)
+		}
+		if err != nil || maxRetries < 0 || backoff < 0 {
+			return retryPolicy{}, prompt.NewError(fmt.Sprintf(msgInvalidRetry, d.Command))
+		}
+	}
+
+	p := retryPolicy{maxRetries: maxRetries, delay: delay, retryOn: retryOn, configured: configured}
+	if backoff > 0 {
+		p.delay, p.backoff = backoff, true
+	}
+	return p, nil
+}
+
+// configRetry reads the retry of the formula config.json, not configured when the config
+// wasn't downloaded yet, so that no download is made
+func configRetry(d formula.Definition) (*formula.Retry, bool, error) {
+	configPath := d.ConfigPath(d.FormulaPath(api.RitchieHomeDir()), d.ConfigName())
+	if !fileutil.Exists(configPath) {
+		return nil, false, nil
+	}
+
+	b, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return nil, false, err
+	}
+
+	config, err := formula.UnmarshalConfig(b, d.RepoName)
+	if err != nil {
+		return nil, false, err
+	}
+	return config.Retry, true, nil
+}
+
+// retryable tells whether the formula exited with a nonzero code of retryOn,
+// any nonzero code is retryable when retryOn is empty. An interrupted run isn't retried.
+func retryable(err error, retryOn []int) bool {
+	var interrupted runner.InterruptedError
+	if errors.As(err, &interrupted) {
+		return false
+	}
+
+	var exitErr exitCoder
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+
+	if len(retryOn) == 0 {
+		return true
+	}
+	for _, code := range retryOn {
+		if exitErr.ExitCode() == code {
+			return true
+		}
+	}
+	return false
+}
+
+// run runs the formula locally, inside docker, on a remote host or as a Kubernetes Job, a session
+// always runs inside docker. When the docker daemon of a --docker run isn't running the formula
+// runs locally instead, with a warning, while a session or a run on the --runner or the config
+// runner fails, they asked for a runner the formula can't run on the host instead.
+func (f FormulaCommand) run(cmd *cobra.Command, d formula.Definition, inputType api.TermInputType) error {
+	docker := containerRun(cmd)
+
+	v, err := cmd.Flags().GetBool(verboseFlag)
+
+	if err != nil {
+		return err
+	}
+
+	verbose := strconv.FormatBool(v)
+
+	if d.Kubernetes {
+		return f.kubeRunner.Run(d, inputType, verbose)
+	}
+
+	if d.Remote != "" {
+		return f.remoteRunner.Run(d, inputType, verbose)
+	}
+
+	if docker || d.Session {
+		err := f.dockerRunner.Run(d, inputType, verbose)
+		// the entrypoint only exists on the image, there is no local run to fall back to
+		if err != runner.ErrDockerDaemonNotRunning || d.Entrypoint != "" || d.Session || d.Runner != "" {
+			return err
+		}
+		prompt.Warning(msgDockerFallback)
+	}
+
+	return f.defaultRunner.Run(d, inputType, verbose)
+}
+
+// runOnFailure runs the compensating formula of the --on-failure flag after the failed one.
+// It gets its inputs like the failed formula did, with --stdin the same JSON is replayed,
+// and the failure on the FAILED_COMMAND, FAILED_EXIT_CODE and FAILED_ERROR env vars.
+func (f FormulaCommand) runOnFailure(
+	cmd *cobra.Command,
+	onFailure string,
+	failed formula.Definition,
+	runErr error,
+	inputType api.TermInputType,
+	stdinInputs []byte) error {
+	path := onFailure
+	if !strings.HasPrefix(path, cmdUse+" ") {
+		path = cmdUse + " " + path
+	}
+
+	c, ok := f.Formula(path)
+	if !ok {
+		return ErrFormulaPathNotFound
+	}
+
+	d := definition(path, c.Repo, *c.Formula)
+	d.Env = []string{
+		fmt.Sprintf(formula.EnvPattern, formula.FailedCommandEnv, failed.Command),
+		fmt.Sprintf(formula.EnvPattern, formula.FailedExitCodeEnv, strconv.Itoa(ExitCode(runErr))),
+		fmt.Sprintf(formula.EnvPattern, formula.FailedErrorEnv, runErr.Error()),
+	}
+	if stdinInputs != nil {
+		d.Stdin = bytes.NewReader(stdinInputs)
+	}
+
+	if !boolFlag(cmd, quietFlag) {
+		prompt.Warning(fmt.Sprintf(msgOnFailure, failed.Command, path))
+	}
+	return f.run(cmd, d, inputType)
+}
+
+// definition builds the formula definition of the tree formula
+func definition(command, repo string, form api.Formula) formula.Definition {
+	return formula.Definition{
+		Command:  command,
+		Path:     form.Path,
+		Bin:      form.Bin,
+		LBin:     form.LBin,
+		MBin:     form.MBin,
+		WBin:     form.WBin,
+		Bundle:   form.Bundle,
+		Config:   form.Config,
+		RepoURL:  form.RepoURL,
+		RepoName: repo,
+	}
+}
+
+// exitCoder is the exit of a formula, an exec.ExitError or the runner.JobError of a Kubernetes Job
+type exitCoder interface {
+	ExitCode() int
+}
+
+// ExitCode returns the exit code of the formula that failed with err,
+// or 1 when err isn't the exit of a formula
+func ExitCode(err error) int {
+	var exitErr exitCoder
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+	return 1
+}
+
+// passthroughArgs returns the args informed after "--", e.g. rit aws s3 -- --region sa-east-1
+func passthroughArgs(cmd *cobra.Command, args []string) []string {
+	dash := cmd.ArgsLenAtDash()
+	if dash < 0 || dash >= len(args) {
+		return nil
+	}
+	return args[dash:]
+}
+
+// checkDeprecation warns the user when the formula is deprecated and refuses
+// to run it after its sunset date, unless the --allow-deprecated flag is used.
+// An invalid sunset date is reported and the formula is treated as not sunset.
+func checkDeprecation(cmd *cobra.Command, dep *api.Deprecation) error {
+	if dep == nil {
+		return nil
+	}
+
+	allow, err := cmd.Flags().GetBool(allowDeprecatedFlag)
+	if err != nil {
+		return err
+	}
+
+	quiet := boolFlag(cmd, quietFlag)
+	msg := formula.DeprecationMsg(cmd.CommandPath(), *dep)
+	sunset, err := formula.IsSunset(*dep, time.Now())
+	if err != nil && !quiet {
+		prompt.Warning(err.Error())
+	}
+
+	if sunset && !allow {
+		return prompt.NewError(fmt.Sprintf(msgFormulaSunset, msg, allowDeprecatedFlag))
+	}
+
+	if !quiet {
+		prompt.Warning(msg)
+	}
+
+	return nil
 }
 
 func addFlags(cmd *cobra.Command) {
 	formulaFlags := cmd.Flags()
 	formulaFlags.BoolP(dockerFlag, "d", false, "Use to run formulas inside a docker container")
 	formulaFlags.BoolP(verboseFlag, "a", false, "Verbose mode (All). Indicate to a formula that it should show log messages in more detail")
+	formulaFlags.Bool(allowDeprecatedFlag, false, "Run a deprecated formula even after its sunset date")
+	formulaFlags.Bool(prePullFlag, false, "Pull the formula docker images without running the formula")
+	addRunFlags(formulaFlags)
+}
+
+// addRunFlags adds the flags controlling the formula run, shared by the formula commands and rit run
+func addRunFlags(flags *pflag.FlagSet) {
+	flags.String(onFailureFlag, "", "Formula to run when this one fails, e.g. --on-failure \"aws rollback\"")
+	flags.Int(maxRetriesFlag, 0, "Run the formula again up to N times when it fails")
+	flags.Duration(retryDelayFlag, time.Second, "Time to wait between the retries, e.g. 500ms, 5s")
+	flags.Duration(retryBackoffFlag, 0, "Time to wait before the first retry, doubled on each retry up to 5m, e.g. 10s, overrides the retry.backoff config")
+	flags.IntSlice(retryOnFlag, nil, "Exit codes that are retried, any nonzero exit code when not informed")
+	flags.Bool(sessionFlag, false, "Run inside a docker container kept running to speed up the next runs")
+	flags.Bool(sessionStopFlag, false, "Stop the docker container kept by --session and remove its image")
+	flags.Bool(isolateFlag, false, "Run on a temp copy of the formula inputs, only its outputs are copied back after a successful run")
+	flags.Duration(timeoutFlag, 0, "Stop the formula and clean up its run when it runs for longer, e.g. 5m, overrides the formula.timeout config, 0 disables it")
+	flags.Duration(killGraceFlag, 5*time.Second, "Time a formula has to exit after a SIGTERM before it is killed, as docker stop -t")
+	flags.StringArray(labelFlag, nil, "Label the run on its run log and metrics, e.g. --label ci=123, can be repeated")
+	flags.Bool(requiredFirstFlag, false, "Ask the required inputs before the optional ones, as the inputs.required-first config does for every run")
+	flags.Duration(inputTimeoutFlag, 0, "Accept the input default when a prompt gets no key press for this long, e.g. 30s")
+	flags.Bool(inputTimeoutFailFlag, false, "Fail the run when a prompt without a default times out on --input-timeout-default, instead of waiting")
+	flags.Bool(defaultFlag, false, "Accept the default of every input that has one instead of asking it, only the inputs without a default are asked")
+	flags.Int(countFlag, 1, "Run the formula N times with the same inputs, asked once, reporting each run and the summary")
+	flags.Int(parallelismFlag, 1, "How many of the --count runs execute at the same time")
+	flags.String(captureMetricsFlag, "", "Append the run metrics to a local file as a JSON line, even with the metrics disabled, e.g. duration, exit code and labels")
+	flags.String(profileFlag, "", "Report on stderr the time of each run phase and the CPU time (cpu) or the peak memory (mem) of the formula")
+	flags.Bool(profileJSONFlag, false, "Report the --profile as a JSON line")
+	flags.String(entrypointFlag, "", "Run this entrypoint on the formula image instead of the formula, with its env and inputs, e.g. /bin/sh, needs --docker and a formula with allowOverride")
+	flags.String(sshFlag, "", "Run the formula on this host with ssh, as user@host or a host of ~/.ssh/config, its inputs are asked here")
+	flags.Bool(kubernetesFlag, false, "Run the formula image as a Kubernetes Job with kubectl, see the kubernetes configs, its inputs are asked here")
+	flags.String(runnerFlag, "", "Run inside a container of this engine, docker or podman, or on a runner plugin, overrides the formula.runner config")
+	flags.Bool(printCommandFlag, false, "Print on stderr the command line or the docker run the formula runs with, its secrets masked, and run it")
+	flags.Bool(dryRunFlag, false, "Resolve the inputs, credentials and env of the formula and print the command, image and volumes it would run with, its secrets masked, without running it")
+	flags.String(commandFlag, "", "Run this shell command instead of the formula binary, with its env and inputs, e.g. /bin/sh, needs a formula with allowOverride")
+	flags.Bool(timestampsFlag, false, "Prefix each line of the formula output with the time and the formula command")
+	flags.StringArray(envFileFlag, nil, "Export the NAME=VALUE vars of this dotenv file to the formula, e.g. --env-file .env, can be repeated")
+	flags.StringArray(envPassthroughFlag, nil, "Forward the host env vars matching this glob to the formula, also on --ssh and --kubernetes, e.g. \"AWS_*\", can be repeated, the RIT_ vars are never forwarded and --env-file and the inputs replace them")
+	flags.String(inputsFileFlag, "", "Read the input values of this YAML or JSON file, e.g. region: ${AWS_REGION}, the input flags replace them and the missing ones are prompted")
+	flags.String(cpusFlag, "", "Limit the container of a docker run to this number of CPUs, e.g. 1.5, overrides the resources of the formula config.json")
+	flags.String(memoryFlag, "", "Limit the memory of the container of a docker run, e.g. 512m or 2g, overrides the resources of the formula config.json")
+	flags.Bool(sandboxFlag, false, "Run a local formula without the network, on its own user namespace on Linux and a private tmp dir, to reduce what an untrusted formula reaches, it still reads your files")
+	flags.Bool(sandboxNetworkFlag, false, "Keep the network of a --sandbox run")
+	flags.StringArray(volumeFlag, nil, "Mount a host path allowed by the formula.volumes config on the container of a docker run, e.g. ~/.kube:/root/.kube:ro, can be repeated")
+	flags.StringP(outputFlag, "o", "", "Print the outputs of the formula as json or yaml, its stdout goes to stderr [json|yaml]")
+	flags.Bool(detachFlag, false, "Run the formula in the background with the --stdin inputs, list the runs with rit ps, read their output with rit logs and stop them with rit stop")
 }
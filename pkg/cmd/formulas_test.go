@@ -1,12 +1,23 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ZupIT/ritchie-cli/pkg/api"
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/detach"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runner"
 )
 
 func TestFormulaCommand_Add(t *testing.T) {
@@ -36,7 +47,7 @@ func TestFormulaCommand_Add(t *testing.T) {
 			},
 		},
 	}
-	formulaCmd := NewFormulaCommand(api.CoreCmds, treeMock, runnerMock{}, runnerMock{})
+	formulaCmd := NewFormulaCommand(api.CoreCmds, treeMock, runnerMock{}, runnerMock{}, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{})
 	rootCmd := &cobra.Command{
 		Use: "rit",
 	}
@@ -74,3 +85,1464 @@ func TestFormulaCommand_Add(t *testing.T) {
 		})
 	}
 }
+
+func TestFormulaCommand_AddDeprecated(t *testing.T) {
+	formulaMock := &api.Formula{
+		Path:    "mock/test",
+		Bin:     "test-${so}",
+		Bundle:  "${so}.zip",
+		Config:  "config.json",
+		RepoURL: "http://localhost:8882/formulas",
+	}
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{
+					Parent: "root",
+					Usage:  "mock",
+					Help:   "mock for add",
+				},
+				{
+					Parent:      "root_mock",
+					Usage:       "old",
+					Help:        "old formula",
+					Formula:     formulaMock,
+					Deprecation: &api.Deprecation{Message: "no longer maintained", Replacement: "rit mock test"},
+				},
+				{
+					Parent:      "root_mock",
+					Usage:       "removed",
+					Help:        "removed formula",
+					Formula:     formulaMock,
+					Deprecation: &api.Deprecation{Message: "no longer maintained", Sunset: "2000-01-01"},
+				},
+				{
+					Parent:      "root_mock",
+					Usage:       "baddate",
+					Help:        "formula with invalid sunset",
+					Formula:     formulaMock,
+					Deprecation: &api.Deprecation{Message: "no longer maintained", Sunset: "01/08/2020"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantErr     bool
+		wantWarning bool
+	}{
+		{
+			name:        "deprecated formula runs with warning",
+			args:        []string{"mock", "old"},
+			wantWarning: true,
+		},
+		{
+			name: "deprecated formula runs quietly",
+			args: []string{"mock", "old", "--quiet"},
+		},
+		{
+			name:    "formula after sunset is refused",
+			args:    []string{"mock", "removed"},
+			wantErr: true,
+		},
+		{
+			name:        "formula after sunset runs with override",
+			args:        []string{"mock", "removed", "--allow-deprecated"},
+			wantWarning: true,
+		},
+		{
+			name:        "formula with invalid sunset runs with warning",
+			args:        []string{"mock", "baddate"},
+			wantWarning: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formulaCmd := NewFormulaCommand(api.CoreCmds, treeMock, runnerMock{}, runnerMock{}, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{})
+			rootCmd := &cobra.Command{Use: "rit"}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			rootCmd.PersistentFlags().Bool("quiet", false, "quiet mode")
+			if err := formulaCmd.Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.SetArgs(tt.args)
+
+			var err error
+			out := captureStdout(func() { err = rootCmd.Execute() })
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("%s = %v, wantErr %v", rootCmd.Use, err, tt.wantErr)
+			}
+			if got := strings.Contains(out, "is deprecated"); got != tt.wantWarning {
+				t.Errorf("%s printed warning = %v, want %v (output %q)", tt.name, got, tt.wantWarning, out)
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_AddDeprecatedShort(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{
+					Parent:      "root_mock",
+					Usage:       "old",
+					Help:        "old formula",
+					Formula:     &api.Formula{Path: "mock/old"},
+					Deprecation: &api.Deprecation{Message: "no longer maintained"},
+				},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+	rootCmd := &cobra.Command{Use: "rit"}
+	if err := NewFormulaCommand(api.CoreCmds, treeMock, runnerMock{}, runnerMock{}, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+		t.Fatalf("Add got %v, want nil", err)
+	}
+
+	old, _, _ := rootCmd.Find([]string{"mock", "old"})
+	if want := "old formula (deprecated)"; old.Short != want {
+		t.Errorf("deprecated Short got %q, want %q", old.Short, want)
+	}
+
+	test, _, _ := rootCmd.Find([]string{"mock", "test"})
+	if want := "test formula"; test.Short != want {
+		t.Errorf("Short got %q, want %q", test.Short, want)
+	}
+}
+
+func TestFormulaCommand_DockerFallback(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		docker  runnerMock
+		local   runnerMock
+		wantErr bool
+	}{
+		{
+			name:   "falls back to local runner when the daemon is down",
+			docker: runnerMock{error: runner.ErrDockerDaemonNotRunning},
+		},
+		{
+			name:    "does not fall back on the requested runner",
+			args:    []string{"--runner", "docker"},
+			docker:  runnerMock{error: runner.ErrDockerDaemonNotRunning},
+			wantErr: true,
+		},
+		{
+			name:    "does not fall back on a session",
+			args:    []string{"--session"},
+			docker:  runnerMock{error: runner.ErrDockerDaemonNotRunning},
+			wantErr: true,
+		},
+		{
+			name:    "does not fall back when docker is not installed",
+			docker:  runnerMock{error: runner.ErrDockerNotFound},
+			wantErr: true,
+		},
+		{
+			name:    "local runner error is returned after fallback",
+			docker:  runnerMock{error: runner.ErrDockerDaemonNotRunning},
+			local:   runnerMock{error: errors.New("local error")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit"}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, tt.local, tt.docker, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.SetArgs(append([]string{"mock", "test", "--docker"}, tt.args...))
+
+			if err := rootCmd.Execute(); (err != nil) != tt.wantErr {
+				t.Errorf("%s = %v, wantErr %v", rootCmd.Use, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_PrePull(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		puller  pullerMock
+		wantErr bool
+	}{
+		{
+			name: "pulls without running the formula",
+		},
+		{
+			name:    "pull error",
+			puller:  pullerMock{error: errors.New("unable to pull")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit"}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			runnerErr := runnerMock{error: errors.New("the formula must not run")}
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, runnerErr, runnerErr, runnerMock{}, runnerMock{}, tt.puller, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.SetArgs([]string{"mock", "test", "--pre-pull"})
+
+			if err := rootCmd.Execute(); (err != nil) != tt.wantErr {
+				t.Errorf("%s = %v, wantErr %v", rootCmd.Use, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_Session(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		stopErr     error
+		wantSession bool
+		wantStopped []string
+		wantErr     bool
+	}{
+		{
+			name:        "runs on the docker session",
+			args:        []string{"mock", "test", "--session"},
+			wantSession: true,
+		},
+		{
+			name:        "runs on the docker session by rit run",
+			args:        []string{"run", "mock", "test", "--session"},
+			wantSession: true,
+		},
+		{
+			name:        "stops the session without running the formula",
+			args:        []string{"mock", "test", "--session-stop"},
+			wantStopped: []string{"mock/test"},
+		},
+		{
+			name:        "no session to stop",
+			args:        []string{"mock", "test", "--session-stop"},
+			stopErr:     runner.ErrNoSession,
+			wantStopped: []string{"mock/test"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit"}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			var docker formula.Definition
+			var stopped []string
+			sessions := sessionStopperMock{stopped: &stopped, error: tt.stopErr}
+			local := runnerMock{error: errors.New("a session must not run locally")}
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, local, runnerSpyMock{def: &docker}, runnerMock{}, runnerMock{}, pullerMock{}, sessions, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); (err != nil) != tt.wantErr {
+				t.Fatalf("%s = %v, wantErr %v", rootCmd.Use, err, tt.wantErr)
+			}
+			if docker.Session != tt.wantSession {
+				t.Errorf("docker runner got session %v, want %v", docker.Session, tt.wantSession)
+			}
+			if !reflect.DeepEqual(stopped, tt.wantStopped) {
+				t.Errorf("stopped sessions got %v, want %v", stopped, tt.wantStopped)
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_Isolate(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	for _, args := range [][]string{{"mock", "test", "--isolate"}, {"run", "mock", "test", "--isolate"}} {
+		rootCmd := &cobra.Command{Use: "rit"}
+		rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+		var def formula.Definition
+		spy := runnerSpyMock{def: &def}
+		if err := NewFormulaCommand(api.CoreCmds, treeMock, spy, spy, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+			t.Fatalf("Add got %v, want nil", err)
+		}
+		rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+		rootCmd.SetArgs(args)
+
+		if err := rootCmd.Execute(); err != nil || !def.Isolate {
+			t.Errorf("%v got %v and isolate %v, want an isolated run", args, err, def.Isolate)
+		}
+	}
+}
+
+func TestFormulaCommand_PrintCommand(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	for _, args := range [][]string{{"mock", "test", "--docker", "--print-command"}, {"run", "mock", "test", "--print-command"}} {
+		rootCmd := &cobra.Command{Use: "rit"}
+		rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+		var def formula.Definition
+		spy := runnerSpyMock{def: &def}
+		if err := NewFormulaCommand(api.CoreCmds, treeMock, spy, spy, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+			t.Fatalf("Add got %v, want nil", err)
+		}
+		rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+		rootCmd.SetArgs(args)
+
+		if err := rootCmd.Execute(); err != nil || !def.PrintCommand {
+			t.Errorf("%v got %v and print command %v, want the command printed", args, err, def.PrintCommand)
+		}
+	}
+}
+
+func TestFormulaCommand_DryRun(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		args       []string
+		wantDryRun bool
+		wantArgs   []string
+	}{
+		{args: []string{"mock", "test", "--dry-run"}, wantDryRun: true},
+		{args: []string{"mock", "test", "--docker", "--dry-run"}, wantDryRun: true},
+		{args: []string{"run", "mock", "test", "--dry-run"}, wantDryRun: true},
+		{args: []string{"mock", "test", "--", "--dry-run"}, wantArgs: []string{"--dry-run"}},
+	}
+
+	for _, tt := range tests {
+		rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+		rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+		var def formula.Definition
+		spy := runnerSpyMock{def: &def}
+		if err := NewFormulaCommand(api.CoreCmds, treeMock, spy, spy, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+			t.Fatalf("Add got %v, want nil", err)
+		}
+		rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+		rootCmd.SetArgs(tt.args)
+
+		err := rootCmd.Execute()
+		if err != nil || def.DryRun != tt.wantDryRun || !reflect.DeepEqual(def.Args, tt.wantArgs) {
+			t.Errorf("%v got %v, dry run %v and args %q, want dry run %v and args %q",
+				tt.args, err, def.DryRun, def.Args, tt.wantDryRun, tt.wantArgs)
+		}
+	}
+}
+
+func TestFormulaCommand_Runner(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		config     string
+		wantErr    error
+		wantRunner string
+		wantDocker bool
+	}{
+		{name: "local run", args: []string{"mock", "test"}},
+		{name: "podman run", args: []string{"mock", "test", "--runner", "podman"}, wantRunner: runner.EnginePodman, wantDocker: true},
+		{name: "docker run by rit run", args: []string{"run", "mock", "test", "--runner", "docker"}, wantRunner: runner.EngineDocker, wantDocker: true},
+		{name: "config runner", args: []string{"mock", "test", "--docker"}, config: runner.EnginePodman, wantRunner: runner.EnginePodman, wantDocker: true},
+		{name: "runner replacing the config", args: []string{"mock", "test", "--runner", "docker"}, config: runner.EnginePodman, wantRunner: runner.EngineDocker, wantDocker: true},
+		{name: "unknown runner", args: []string{"mock", "test", "--runner", "containerd"}, wantErr: runner.ErrInvalidEngine},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			var docker, local formula.Definition
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, runnerSpyMock{def: &local}, runnerSpyMock{def: &docker}, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{Runner: tt.config}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("%s = %v, want %v", rootCmd.Use, err, tt.wantErr)
+			}
+			if docker.Runner != tt.wantRunner {
+				t.Errorf("runner = %q, want %q", docker.Runner, tt.wantRunner)
+			}
+			if ran := docker.Command != ""; ran != tt.wantDocker {
+				t.Errorf("container run = %v, want %v", ran, tt.wantDocker)
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_SSH(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    error
+		wantRemote string
+	}{
+		{name: "runs on the host", args: []string{"mock", "test", "--ssh", "ops@jump"}, wantRemote: "ops@jump"},
+		{name: "runs on the host by rit run", args: []string{"run", "mock", "test", "--ssh", "jump"}, wantRemote: "jump"},
+		{name: "with docker", args: []string{"mock", "test", "--ssh", "jump", "--docker"}, wantErr: ErrSSHNeedsLocal},
+		{name: "with a session", args: []string{"mock", "test", "--ssh", "jump", "--session"}, wantErr: ErrSSHNeedsLocal},
+		{name: "with a command", args: []string{"mock", "test", "--ssh", "jump", "--command", "env"}, wantErr: ErrSSHNeedsLocal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			var remote formula.Definition
+			local := runnerMock{error: errors.New("the formula must not run locally")}
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, local, local, runnerSpyMock{def: &remote}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("%s = %v, want %v", rootCmd.Use, err, tt.wantErr)
+			}
+			if remote.Remote != tt.wantRemote {
+				t.Errorf("remote runner got the host %q, want %q", remote.Remote, tt.wantRemote)
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_Kubernetes(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+		wantJob bool
+	}{
+		{name: "runs as a job", args: []string{"mock", "test", "--kubernetes"}, wantJob: true},
+		{name: "runs as a job by rit run", args: []string{"run", "mock", "test", "--kubernetes"}, wantJob: true},
+		{name: "built with podman", args: []string{"mock", "test", "--kubernetes", "--runner", "podman"}, wantJob: true},
+		{name: "with a session", args: []string{"mock", "test", "--kubernetes", "--session"}, wantErr: ErrKubernetesFlags},
+		{name: "with ssh", args: []string{"mock", "test", "--kubernetes", "--ssh", "jump"}, wantErr: ErrKubernetesFlags},
+		{name: "with a command", args: []string{"mock", "test", "--kubernetes", "--command", "env"}, wantErr: ErrKubernetesFlags},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			var job formula.Definition
+			other := runnerMock{error: errors.New("the formula must run as a job")}
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, other, other, other, runnerSpyMock{def: &job}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("%s = %v, want %v", rootCmd.Use, err, tt.wantErr)
+			}
+			if job.Kubernetes != tt.wantJob {
+				t.Errorf("kubernetes runner got a job %v, want %v", job.Kubernetes, tt.wantJob)
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_KillGrace(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantGrace time.Duration
+		wantErr   error
+	}{
+		{name: "default grace", args: []string{"mock", "test"}, wantGrace: 5 * time.Second},
+		{name: "grace informed", args: []string{"run", "mock", "test", "--timeout-kill-grace", "30s"}, wantGrace: 30 * time.Second},
+		{name: "negative grace", args: []string{"mock", "test", "--timeout-kill-grace", "-1s"}, wantErr: ErrNegativeKillGrace},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			var def formula.Definition
+			spy := runnerSpyMock{def: &def}
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, spy, spy, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); err != tt.wantErr {
+				t.Fatalf("%s = %v, want %v", rootCmd.Use, err, tt.wantErr)
+			}
+			if def.KillGrace != tt.wantGrace {
+				t.Errorf("kill grace = %v, want %v", def.KillGrace, tt.wantGrace)
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_Timeout(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		config      time.Duration
+		wantTimeout time.Duration
+		wantErr     error
+	}{
+		{name: "no timeout", args: []string{"mock", "test"}},
+		{name: "timeout informed", args: []string{"run", "mock", "test", "--timeout", "5m"}, wantTimeout: 5 * time.Minute},
+		{name: "config timeout", args: []string{"mock", "test"}, config: time.Hour, wantTimeout: time.Hour},
+		{name: "timeout replacing the config", args: []string{"mock", "test", "--timeout", "1m"}, config: time.Hour, wantTimeout: time.Minute},
+		{name: "config timeout disabled", args: []string{"mock", "test", "--timeout", "0"}, config: time.Hour},
+		{name: "negative timeout", args: []string{"mock", "test", "--timeout", "-1s"}, wantErr: ErrNegativeTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			var def formula.Definition
+			spy := runnerSpyMock{def: &def}
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, spy, spy, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{Timeout: tt.config}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); err != tt.wantErr {
+				t.Fatalf("%s = %v, want %v", rootCmd.Use, err, tt.wantErr)
+			}
+			if def.Timeout != tt.wantTimeout {
+				t.Errorf("timeout = %v, want %v", def.Timeout, tt.wantTimeout)
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_InputTimeout(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantTimeout time.Duration
+		wantFail    bool
+		wantErr     error
+	}{
+		{name: "no timeout", args: []string{"mock", "test"}},
+		{name: "timeout", args: []string{"run", "mock", "test", "--input-timeout-default", "30s"}, wantTimeout: 30 * time.Second},
+		{
+			name:        "timeout failing without default",
+			args:        []string{"mock", "test", "--input-timeout-default", "1m", "--input-timeout-fail"},
+			wantTimeout: time.Minute,
+			wantFail:    true,
+		},
+		{name: "negative timeout", args: []string{"mock", "test", "--input-timeout-default", "-1s"}, wantErr: ErrNegativeInputTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			var def formula.Definition
+			spy := runnerSpyMock{def: &def}
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, spy, spy, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); err != tt.wantErr {
+				t.Fatalf("%s = %v, want %v", rootCmd.Use, err, tt.wantErr)
+			}
+			if def.InputTimeout != tt.wantTimeout || def.InputTimeoutFail != tt.wantFail {
+				t.Errorf("input timeout = %v, fail %v, want %v, fail %v", def.InputTimeout, def.InputTimeoutFail, tt.wantTimeout, tt.wantFail)
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_Labels(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantLabels map[string]string
+		wantErr    error
+	}{
+		{name: "no labels", args: []string{"mock", "test"}},
+		{
+			name:       "labels",
+			args:       []string{"run", "mock", "test", "--label", "ci=123", "--label", "ticket=OPS-1"},
+			wantLabels: map[string]string{"ci": "123", "ticket": "OPS-1"},
+		},
+		{name: "invalid label", args: []string{"mock", "test", "--label", "ci"}, wantErr: formula.ErrInvalidLabel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			var def formula.Definition
+			spy := runnerSpyMock{def: &def}
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, spy, spy, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); err != tt.wantErr {
+				t.Fatalf("%s = %v, want %v", rootCmd.Use, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(def.Labels, tt.wantLabels) {
+				t.Errorf("labels = %v, want %v", def.Labels, tt.wantLabels)
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_CaptureMetrics(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+	pwd, _ := os.Getwd()
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "not captured", args: []string{"mock", "test"}},
+		{name: "relative file", args: []string{"run", "mock", "test", "--capture-metrics", "metrics.jsonl"}, want: filepath.Join(pwd, "metrics.jsonl")},
+		{name: "absolute file", args: []string{"mock", "test", "--capture-metrics", "/tmp/metrics.jsonl"}, want: "/tmp/metrics.jsonl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			var def formula.Definition
+			spy := runnerSpyMock{def: &def}
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, spy, spy, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("%s = %v, want nil", rootCmd.Use, err)
+			}
+			if def.CaptureMetrics != tt.want {
+				t.Errorf("capture metrics = %q, want %q", def.CaptureMetrics, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_Profile(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantErr  error
+		want     string
+		wantJSON bool
+	}{
+		{name: "not profiled", args: []string{"mock", "test"}},
+		{name: "cpu", args: []string{"mock", "test", "--profile", "cpu"}, want: formula.ProfileCPU},
+		{name: "mem as JSON", args: []string{"run", "mock", "test", "--profile", "mem", "--profile-json"}, want: formula.ProfileMem, wantJSON: true},
+		{name: "invalid profile", args: []string{"mock", "test", "--profile", "io"}, wantErr: ErrInvalidProfile},
+		{name: "JSON without profile", args: []string{"mock", "test", "--profile-json"}, wantErr: ErrProfileJSONNoProfile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			var def formula.Definition
+			spy := runnerSpyMock{def: &def}
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, spy, spy, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("%s = %v, want %v", rootCmd.Use, err, tt.wantErr)
+			}
+			if def.Profile != tt.want || def.ProfileJSON != tt.wantJSON {
+				t.Errorf("profile = %q, %v, want %q, %v", def.Profile, def.ProfileJSON, tt.want, tt.wantJSON)
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_Override(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		docker         error
+		wantErr        error
+		wantEntrypoint string
+		wantCommand    string
+		wantLocal      bool
+	}{
+		{name: "docker entrypoint", args: []string{"mock", "test", "--docker", "--entrypoint", "/bin/sh"}, wantEntrypoint: "/bin/sh"},
+		{name: "local command", args: []string{"run", "mock", "test", "--command", "env"}, wantCommand: "env", wantLocal: true},
+		{name: "entrypoint without docker", args: []string{"mock", "test", "--entrypoint", "/bin/sh"}, wantErr: ErrEntrypointNeedsDocker},
+		{name: "entrypoint on a session", args: []string{"mock", "test", "--session", "--entrypoint", "/bin/sh"}, wantErr: ErrEntrypointNeedsDocker},
+		{name: "command on docker", args: []string{"mock", "test", "--docker", "--command", "env"}, wantErr: ErrCommandNeedsLocal},
+		{
+			name:           "entrypoint does not fall back to local",
+			args:           []string{"mock", "test", "--docker", "--entrypoint", "/bin/sh"},
+			docker:         runner.ErrDockerDaemonNotRunning,
+			wantErr:        runner.ErrDockerDaemonNotRunning,
+			wantEntrypoint: "/bin/sh",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			var docker, local formula.Definition
+			dockerRunner := runnerSpyMock{def: &docker, error: tt.docker}
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, runnerSpyMock{def: &local}, dockerRunner, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("%s = %v, want %v", rootCmd.Use, err, tt.wantErr)
+			}
+			got := docker
+			if tt.wantLocal {
+				got = local
+			}
+			if got.Entrypoint != tt.wantEntrypoint || got.CommandOverride != tt.wantCommand {
+				t.Errorf("override = %q, %q, want %q, %q", got.Entrypoint, got.CommandOverride, tt.wantEntrypoint, tt.wantCommand)
+			}
+			if local.Command != "" && !tt.wantLocal {
+				t.Errorf("the formula ran locally, want no local run")
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_Resources(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		want        formula.Resources
+		wantVolumes []string
+		wantErr     error
+	}{
+		{name: "docker", args: []string{"mock", "test", "--docker", "--cpus", "1.5", "--memory", "512m"}, want: formula.Resources{CPUs: "1.5", Memory: "512m"}},
+		{name: "volumes", args: []string{"mock", "test", "--docker", "--volume", "~/.kube:/root/.kube:ro", "--volume", "~/.ssh:/root/.ssh"}, wantVolumes: []string{"~/.kube:/root/.kube:ro", "~/.ssh:/root/.ssh"}},
+		{name: "local volume", args: []string{"mock", "test", "--volume", "~/.kube:/root/.kube"}, wantErr: ErrVolumeNeedsDocker},
+		{name: "session", args: []string{"mock", "test", "--session", "--memory", "2g"}, want: formula.Resources{Memory: "2g"}},
+		{name: "without limits", args: []string{"mock", "test", "--docker"}},
+		{name: "local", args: []string{"mock", "test", "--cpus", "1"}, wantErr: ErrResourcesNeedDocker},
+		{name: "kubernetes", args: []string{"mock", "test", "--kubernetes", "--memory", "1g"}, wantErr: ErrResourcesNeedDocker},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			var def formula.Definition
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, runnerMock{}, runnerSpyMock{def: &def}, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("%s = %v, want %v", rootCmd.Use, err, tt.wantErr)
+			}
+			if def.Resources != tt.want {
+				t.Errorf("resources = %+v, want %+v", def.Resources, tt.want)
+			}
+			if len(def.Volumes)+len(tt.wantVolumes) > 0 && !reflect.DeepEqual(def.Volumes, tt.wantVolumes) {
+				t.Errorf("volumes = %v, want %v", def.Volumes, tt.wantVolumes)
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_Sandbox(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantSandbox bool
+		wantNetwork bool
+		wantErr     error
+	}{
+		{name: "sandbox", args: []string{"mock", "test", "--sandbox"}, wantSandbox: true},
+		{name: "sandbox with the network", args: []string{"mock", "test", "--sandbox", "--sandbox-network"}, wantSandbox: true, wantNetwork: true},
+		{name: "network without sandbox", args: []string{"mock", "test", "--sandbox-network"}, wantErr: ErrNetworkNeedsSandbox},
+		{name: "docker", args: []string{"mock", "test", "--sandbox", "--docker"}, wantErr: ErrSandboxNeedsLocal},
+		{name: "ssh", args: []string{"mock", "test", "--sandbox", "--ssh", "build-host"}, wantErr: ErrSandboxNeedsLocal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			var def formula.Definition
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, runnerSpyMock{def: &def}, runnerMock{}, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); err != tt.wantErr {
+				t.Fatalf("%s = %v, want %v", rootCmd.Use, err, tt.wantErr)
+			}
+			if def.Sandbox != tt.wantSandbox || def.SandboxNetwork != tt.wantNetwork {
+				t.Errorf("sandbox = %v and network %v, want %v and %v", def.Sandbox, def.SandboxNetwork, tt.wantSandbox, tt.wantNetwork)
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_EnvFile(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "rit-env-file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	base, stage := filepath.Join(dir, ".env"), filepath.Join(dir, "dev.env")
+	_ = ioutil.WriteFile(base, []byte("# base\nAWS_REGION=us-east-1\nSTAGE=prod\n"), 0600)
+	_ = ioutil.WriteFile(stage, []byte("STAGE=dev\n"), 0600)
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "without env file", args: []string{"mock", "test"}},
+		{name: "env files", args: []string{"mock", "test", "--env-file", base, "--env-file", stage}, want: []string{"AWS_REGION=us-east-1", "STAGE=prod", "STAGE=dev"}},
+		{name: "missing env file", args: []string{"mock", "test", "--env-file", filepath.Join(dir, "missing.env")}, wantErr: true},
+		{
+			name: "env passthrough", args: []string{"mock", "test", "--env-passthrough", "PASSTHROUGH_*", "--env-file", stage},
+			want: []string{"PASSTHROUGH_STAGE=qa", "STAGE=dev"},
+		},
+		{name: "invalid env passthrough", args: []string{"mock", "test", "--env-passthrough", "PASSTHROUGH_["}, wantErr: true},
+	}
+	_ = os.Setenv("PASSTHROUGH_STAGE", "qa")
+	defer os.Unsetenv("PASSTHROUGH_STAGE")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			var def formula.Definition
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, runnerSpyMock{def: &def}, runnerMock{}, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); (err != nil) != tt.wantErr {
+				t.Fatalf("%s = %v, want error %v", rootCmd.Use, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(def.Env, tt.want) {
+				t.Errorf("env = %q, want %q", def.Env, tt.want)
+			}
+		})
+	}
+}
+
+type detacherMock struct {
+	args *[]string
+}
+
+func (m detacherMock) Start(command string, args []string, _ []byte) (detach.Run, error) {
+	*m.args = args
+	return detach.Run{ID: "20200720T100000.000000-rit-mock-test", Command: command}, nil
+}
+
+func TestFormulaCommand_Detach(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		env       string
+		wantStart []string
+		wantLogID string
+		wantErr   error
+	}{
+		{
+			name:      "starts the child rit",
+			args:      []string{"mock", "test", "--detach", "--stdin", "--timestamps", "--", "--port", "8080"},
+			wantStart: []string{"mock", "test", "--stdin=true", "--timestamps=true", "--", "--port", "8080"},
+		},
+		{name: "needs stdin", args: []string{"mock", "test", "--detach"}, wantErr: ErrDetachNeedsStdin},
+		{name: "with count", args: []string{"mock", "test", "--detach", "--stdin", "--count", "2"}, wantErr: ErrDetachCount},
+		{name: "child rit", args: []string{"mock", "test", "--stdin"}, env: "20200720T100000.000000-rit-mock-test", wantLogID: "20200720T100000.000000-rit-mock-test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var started []string
+			if tt.env != "" {
+				_ = os.Setenv(detach.IDEnv, tt.env)
+			}
+			defer os.Unsetenv(detach.IDEnv)
+
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			var def formula.Definition
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, runnerSpyMock{def: &def}, runnerMock{}, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, detacherMock{args: &started}, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); err != tt.wantErr {
+				t.Fatalf("%s = %v, want %v", rootCmd.Use, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(started, tt.wantStart) {
+				t.Errorf("detached run of %q, want %q", started, tt.wantStart)
+			}
+			if def.LogID != tt.wantLogID || os.Getenv(detach.IDEnv) != "" {
+				t.Errorf("log ID = %q with %s=%q, want %q and the env var unset", def.LogID, detach.IDEnv, os.Getenv(detach.IDEnv), tt.wantLogID)
+			}
+		})
+	}
+}
+
+func captureStdout(f func()) string {
+	stdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	f()
+	_ = w.Close()
+	os.Stdout = stdout
+	b, _ := ioutil.ReadAll(r)
+	return string(b)
+}
+
+func TestFormulaCommand_PassthroughArgs(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "without dash",
+			args: []string{"mock", "test", "ignored"},
+		},
+		{
+			name: "empty passthrough",
+			args: []string{"mock", "test", "--"},
+		},
+		{
+			name: "args after dash",
+			args: []string{"mock", "test", "--", "--region", "sa east", "-v"},
+			want: []string{"--region", "sa east", "-v"},
+		},
+		{
+			name: "args after dash on rit run",
+			args: []string{"run", "mock", "test", "--", "--dry-run"},
+			want: []string{"--dry-run"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var def formula.Definition
+			rootCmd := &cobra.Command{Use: "rit"}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			spy := runnerSpyMock{def: &def}
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, spy, spy, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("%s = %v, want nil", rootCmd.Use, err)
+			}
+			if !reflect.DeepEqual(def.Args, tt.want) {
+				t.Errorf("%s got args %q, want %q", tt.name, def.Args, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_OnFailure(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "aws", Help: "aws formulas"},
+				{Parent: "root_aws", Usage: "deploy", Help: "deploy formula", Formula: &api.Formula{Path: "aws/deploy"}},
+				{Parent: "root_aws", Usage: "rollback", Help: "rollback formula", Formula: &api.Formula{Path: "aws/rollback"}},
+			},
+		},
+	}
+	errDeploy := errors.New("deploy failed")
+
+	tests := []struct {
+		name     string
+		args     []string
+		fail     map[string]error
+		wantErr  error
+		wantRuns []string
+	}{
+		{
+			name:     "formula succeeds",
+			args:     []string{"aws", "deploy", "--on-failure", "aws rollback"},
+			wantRuns: []string{"rit aws deploy"},
+		},
+		{
+			name:     "formula fails",
+			args:     []string{"aws", "deploy", "--on-failure", "aws rollback"},
+			fail:     map[string]error{"rit aws deploy": errDeploy},
+			wantErr:  errDeploy,
+			wantRuns: []string{"rit aws deploy", "rit aws rollback"},
+		},
+		{
+			name:     "on-failure formula fails too",
+			args:     []string{"run", "aws", "deploy", "--on-failure", "rit aws rollback"},
+			fail:     map[string]error{"rit aws deploy": errDeploy, "rit aws rollback": errors.New("rollback failed")},
+			wantErr:  errDeploy,
+			wantRuns: []string{"rit aws deploy", "rit aws rollback"},
+		},
+		{
+			name:     "on-failure formula not found",
+			args:     []string{"aws", "deploy", "--on-failure", "aws undo"},
+			fail:     map[string]error{"rit aws deploy": errDeploy},
+			wantErr:  errDeploy,
+			wantRuns: []string{"rit aws deploy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var runs []formula.Definition
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			recorder := runnerRecorderMock{runs: &runs, fail: tt.fail}
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, recorder, recorder, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); err != tt.wantErr {
+				t.Fatalf("%s = %v, want %v", rootCmd.Use, err, tt.wantErr)
+			}
+
+			var got []string
+			for _, r := range runs {
+				got = append(got, r.Command)
+			}
+			if !reflect.DeepEqual(got, tt.wantRuns) {
+				t.Fatalf("runs = %v, want %v", got, tt.wantRuns)
+			}
+
+			if len(runs) == 2 {
+				want := []string{"FAILED_COMMAND=rit aws deploy", "FAILED_EXIT_CODE=1", "FAILED_ERROR=deploy failed"}
+				if !reflect.DeepEqual(runs[1].Env, want) {
+					t.Errorf("on-failure env = %v, want %v", runs[1].Env, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	err := exec.Command("sh", "-c", "exit 3").Run()
+	if got := ExitCode(fmt.Errorf("run: %w", err)); got != 3 {
+		t.Errorf("ExitCode(exit 3) = %d, want 3", got)
+	}
+	if got := ExitCode(fmt.Errorf("run: %w", runner.JobError{Job: "rit-1", Code: 4})); got != 4 {
+		t.Errorf("ExitCode(job exit 4) = %d, want 4", got)
+	}
+	if got := ExitCode(runner.TimeoutError{Timeout: time.Minute}); got != runner.TimeoutExitCode {
+		t.Errorf("ExitCode(timeout) = %d, want %d", got, runner.TimeoutExitCode)
+	}
+	if got := ExitCode(errors.New("any")); got != 1 {
+		t.Errorf("ExitCode(any) = %d, want 1", got)
+	}
+}
+
+func TestFormulaCommand_Retries(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "aws", Help: "aws formulas"},
+				{Parent: "root_aws", Usage: "deploy", Help: "deploy formula", Formula: &api.Formula{Path: "aws/deploy"}},
+			},
+		},
+	}
+	exit3 := exec.Command("sh", "-c", "exit 3").Run()
+	exit4 := exec.Command("sh", "-c", "exit 4").Run()
+	errSetup := errors.New("setup failed")
+
+	tests := []struct {
+		name       string
+		args       []string
+		config     string
+		errs       []error
+		wantErr    error
+		wantError  string
+		wantRuns   int
+		wantDelays []time.Duration
+		wantEnv    []string
+	}{
+		{
+			name:     "no retries by default",
+			args:     []string{"aws", "deploy"},
+			errs:     []error{exit3},
+			wantErr:  exit3,
+			wantRuns: 1,
+		},
+		{
+			name:     "succeeds on a retry",
+			args:     []string{"aws", "deploy", "--max-retries", "3", "--retry-delay", "0s"},
+			errs:     []error{exit3, exit4},
+			wantRuns: 3,
+			wantEnv:  []string{"RIT_INPUT_REGION=sa-east-1"},
+		},
+		{
+			name:     "retries exhausted",
+			args:     []string{"run", "aws", "deploy", "--max-retries", "2", "--retry-delay", "0s"},
+			errs:     []error{exit3, exit3, exit4, exit3},
+			wantErr:  exit4,
+			wantRuns: 3,
+		},
+		{
+			name:     "exit code not retried",
+			args:     []string{"run", "aws", "deploy", "--max-retries", "3", "--retry-delay", "0s", "--retry-on", "3,5"},
+			errs:     []error{exit3, exit4},
+			wantErr:  exit4,
+			wantRuns: 2,
+		},
+		{
+			name:     "error other than an exit",
+			args:     []string{"aws", "deploy", "--max-retries", "3", "--retry-delay", "0s"},
+			errs:     []error{errSetup},
+			wantErr:  errSetup,
+			wantRuns: 1,
+		},
+		{
+			name:     "interrupted run",
+			args:     []string{"aws", "deploy", "--max-retries", "3", "--retry-delay", "0s"},
+			errs:     []error{runner.InterruptedError{Signal: os.Interrupt}},
+			wantErr:  runner.InterruptedError{Signal: os.Interrupt},
+			wantRuns: 1,
+		},
+		{
+			name:    "negative retries",
+			args:    []string{"aws", "deploy", "--max-retries", "-1"},
+			wantErr: ErrNegativeRetries,
+		},
+		{
+			name:       "exponential backoff",
+			args:       []string{"aws", "deploy", "--max-retries", "4", "--retry-backoff", "2m"},
+			errs:       []error{exit3, exit3, exit3, exit3},
+			wantRuns:   5,
+			wantDelays: []time.Duration{2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute},
+		},
+		{
+			name:       "retry of config.json",
+			args:       []string{"aws", "deploy"},
+			config:     `{"maxRetries": 2, "backoff": "10s", "on": [3]}`,
+			errs:       []error{exit3, exit3, exit3},
+			wantErr:    exit3,
+			wantRuns:   3,
+			wantDelays: []time.Duration{10 * time.Second, 20 * time.Second},
+		},
+		{
+			name:       "flags replace the retry of config.json",
+			args:       []string{"aws", "deploy", "--retry-on", "4", "--retry-delay", "1s"},
+			config:     `{"maxRetries": 2, "backoff": "10s", "on": [3]}`,
+			errs:       []error{exit4, exit4},
+			wantRuns:   3,
+			wantDelays: []time.Duration{time.Second, time.Second},
+		},
+		{
+			name:      "invalid retry of config.json",
+			args:      []string{"aws", "deploy"},
+			config:    `{"maxRetries": 2, "backoff": "soon"}`,
+			wantError: "the retry of the config.json of rit aws deploy",
+		},
+		{
+			name:    "delay and backoff",
+			args:    []string{"aws", "deploy", "--max-retries", "1", "--retry-delay", "1s", "--retry-backoff", "10s"},
+			wantErr: ErrRetryDelayAndBackoff,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home, err := ioutil.TempDir("", "rit-retries")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(home)
+			if tt.config != "" {
+				dir := filepath.Join(home, "formulas", "aws", "deploy")
+				_ = os.MkdirAll(dir, 0755)
+				config := `{"command": "rit aws deploy", "retry": ` + tt.config + `}`
+				if err := ioutil.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			defer os.Unsetenv(api.RitchieHomeEnv)
+			_ = os.Setenv(api.RitchieHomeEnv, home)
+
+			var delays []time.Duration
+			defer func(s func(time.Duration)) { retrySleep = s }(retrySleep)
+			retrySleep = func(d time.Duration) { delays = append(delays, d) }
+
+			var runs int
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			var env []string
+			flaky := runnerFlakyMock{runs: &runs, errs: tt.errs, inputs: map[string]string{"region": "sa-east-1"}, env: &env}
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, flaky, flaky, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+			rootCmd.SetArgs(tt.args)
+
+			err = rootCmd.Execute()
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("%s = %v, want %q", rootCmd.Use, err, tt.wantError)
+				}
+			} else if err != tt.wantErr {
+				t.Fatalf("%s = %v, want %v", rootCmd.Use, err, tt.wantErr)
+			}
+			if runs != tt.wantRuns {
+				t.Errorf("runs = %d, want %d", runs, tt.wantRuns)
+			}
+			if tt.wantDelays != nil && !reflect.DeepEqual(delays, tt.wantDelays) {
+				t.Errorf("delays = %v, want %v", delays, tt.wantDelays)
+			}
+			if tt.wantEnv != nil && !reflect.DeepEqual(env, tt.wantEnv) {
+				t.Errorf("env of the last run = %q, want the inputs of the first one %q", env, tt.wantEnv)
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_Output(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for add"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		args       []string
+		wantOutput string
+		wantErr    error
+	}{
+		{args: []string{"mock", "test"}},
+		{args: []string{"mock", "test", "--output", "json"}, wantOutput: "json"},
+		{args: []string{"mock", "test", "--docker", "-o", "yaml"}, wantOutput: "yaml"},
+		{args: []string{"run", "mock", "test", "-o", "json"}, wantOutput: "json"},
+		{args: []string{"mock", "test", "--output", "table"}, wantErr: ErrInvalidOutput},
+		{args: []string{"mock", "test", "--session", "-o", "json"}, wantErr: ErrOutputNeedsLocal},
+		{args: []string{"mock", "test", "--ssh", "ops@host", "-o", "json"}, wantErr: ErrOutputNeedsLocal},
+		{args: []string{"mock", "test", "--kubernetes", "-o", "json"}, wantErr: ErrOutputNeedsLocal},
+	}
+
+	for _, tt := range tests {
+		rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+		rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+		var def formula.Definition
+		spy := runnerSpyMock{def: &def}
+		if err := NewFormulaCommand(api.CoreCmds, treeMock, spy, spy, spy, spy, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+			t.Fatalf("Add got %v, want nil", err)
+		}
+		rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+		rootCmd.SetArgs(tt.args)
+
+		err := rootCmd.Execute()
+		if !errors.Is(err, tt.wantErr) || def.OutputFormat != tt.wantOutput {
+			t.Errorf("%v got %v and output %q, want %v and output %q", tt.args, err, def.OutputFormat, tt.wantErr, tt.wantOutput)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula/history"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	repoFlag        = "repo"
+	failedFlag      = "failed"
+	sinceFlag       = "since"
+	limitFlag       = "limit"
+	descHistoryLong = `List the formula runs, from the oldest to the newest.
+
+Every run records its command, repo and repo version, args, labels, text and bool
+inputs, duration and exit code. The secret inputs are never recorded, they only count
+on the hash of the inputs, so the runs with the same inputs can be told apart. Run one
+again with rit rerun. The latest 1000 runs are kept, disable the history with
+rit set config history.enabled false.`
+)
+
+var (
+	ErrNoHistory     = errors.New("no formula runs found on the history")
+	ErrNegativeLimit = errors.New("--limit must not be negative")
+)
+
+type historyCmd struct {
+	history.Lister
+}
+
+// NewHistoryCmd creates a new cmd instance
+func NewHistoryCmd(l history.Lister) *cobra.Command {
+	h := historyCmd{l}
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List the formula runs",
+		Long:  descHistoryLong,
+		Example: `rit history
+rit history --formula "rit aws create" --failed
+rit history --repo commons --since 24h --output json`,
+		Args: cobra.NoArgs,
+		RunE: h.runFunc(),
+	}
+
+	flags := cmd.Flags()
+	flags.String(formulaFlag, "", "Only the runs of the formula, e.g. \"rit aws create\"")
+	flags.String(repoFlag, "", "Only the runs of the formulas of the repo")
+	flags.Bool(failedFlag, false, "Only the failed runs")
+	flags.Duration(sinceFlag, 0, "Only the runs started in this last period, e.g. 24h")
+	flags.Int(limitFlag, 20, "List only the newest N runs, 0 lists all of them")
+	addOutputFlag(cmd, "runs")
+
+	return cmd
+}
+
+func (h historyCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		output, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		form, err := cmd.Flags().GetString(formulaFlag)
+		if err != nil {
+			return err
+		}
+		repo, err := cmd.Flags().GetString(repoFlag)
+		if err != nil {
+			return err
+		}
+		since, err := cmd.Flags().GetDuration(sinceFlag)
+		if err != nil {
+			return err
+		}
+		limit, err := cmd.Flags().GetInt(limitFlag)
+		if err != nil {
+			return err
+		} else if limit < 0 {
+			return ErrNegativeLimit
+		}
+
+		ee, err := h.List()
+		if err != nil {
+			return err
+		}
+
+		var after time.Time
+		if since > 0 {
+			after = time.Now().Add(-since)
+		}
+		ee = filterHistory(ee, form, repo, boolFlag(cmd, failedFlag), after)
+		if limit > 0 && len(ee) > limit {
+			ee = ee[len(ee)-limit:]
+		}
+
+		if output != "" {
+			return printOutput(output, ee)
+		}
+		if len(ee) == 0 {
+			return ErrNoHistory
+		}
+		printHistory(ee)
+		return nil
+	}
+}
+
+// filterHistory keeps the runs of the formula and the repo, when they are set, started after
+// the time and, with failed, only the ones that failed
+func filterHistory(ee []history.Entry, form, repo string, failed bool, after time.Time) []history.Entry {
+	filtered := make([]history.Entry, 0, len(ee))
+	for _, e := range ee {
+		switch {
+		case form != "" && e.Command != form:
+		case repo != "" && e.Repo != repo:
+		case failed && e.ExitCode == 0:
+		case e.Start.Before(after):
+		default:
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+func printHistory(ee []history.Entry) {
+	table := uitable.New()
+	table.AddRow("ID", "COMMAND", "REPO", "VERSION", "START", "DURATION", "EXIT CODE")
+	for _, e := range ee {
+		code := prompt.Green(strconv.Itoa(e.ExitCode))
+		if e.ExitCode != 0 {
+			code = prompt.Red(strconv.Itoa(e.ExitCode))
+		}
+		duration := time.Duration(e.Duration * float64(time.Second)).Round(time.Millisecond)
+		table.AddRow(e.ID, e.Command, e.Repo, e.Version, e.Start.Local().Format(runLogTimeFmt), duration, code)
+	}
+	fmt.Println(table)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula/history"
+)
+
+type historyListerMock struct {
+	entries []history.Entry
+}
+
+func (h historyListerMock) List() ([]history.Entry, error) {
+	return h.entries, nil
+}
+
+func TestHistoryCmd(t *testing.T) {
+	now := time.Now().UTC()
+	entries := []history.Entry{
+		{ID: "1", Command: "rit aws create", Repo: "commons", Version: "sha256:0f3c", Start: now.Add(-48 * time.Hour)},
+		{ID: "2", Command: "rit aws delete", Repo: "commons", Start: now.Add(-time.Hour), ExitCode: 3},
+		{ID: "3", Command: "rit gcp create", Repo: "cloud", Start: now.Add(-time.Minute), InputsHash: "sha256:9a1b"},
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		notWant []string
+		wantErr error
+	}{
+		{name: "list runs", want: []string{"rit aws create", "rit aws delete", "rit gcp create", "sha256:0f3c"}},
+		{name: "formula", args: []string{"--formula", "rit aws create"}, want: []string{"rit aws create"}, notWant: []string{"rit aws delete"}},
+		{name: "repo", args: []string{"--repo", "cloud"}, want: []string{"rit gcp create"}, notWant: []string{"rit aws"}},
+		{name: "failed", args: []string{"--failed"}, want: []string{"rit aws delete"}, notWant: []string{"create"}},
+		{name: "since", args: []string{"--since", "2h"}, want: []string{"rit aws delete", "rit gcp create"}, notWant: []string{"rit aws create"}},
+		{name: "limit", args: []string{"--limit", "1"}, want: []string{"rit gcp create"}, notWant: []string{"rit aws"}},
+		{name: "json", args: []string{"--repo", "cloud", "--output", "json"}, want: []string{`"inputsHash": "sha256:9a1b"`}},
+		{name: "json without runs", args: []string{"--repo", "none", "--output", "json"}, want: []string{"[]"}},
+		{name: "no runs", args: []string{"--formula", "rit k8s apply"}, wantErr: ErrNoHistory},
+		{name: "negative limit", args: []string{"--limit", "-1"}, wantErr: ErrNegativeLimit},
+		{name: "invalid output", args: []string{"--output", "xml"}, wantErr: ErrInvalidOutput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewHistoryCmd(historyListerMock{entries: entries})
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			cmd.SetArgs(tt.args)
+
+			var err error
+			out := captureStdout(func() { err = cmd.Execute() })
+			if err != tt.wantErr {
+				t.Fatalf("history got error %v, want %v", err, tt.wantErr)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(out, w) {
+					t.Errorf("history printed %q, want %q", out, w)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(out, w) {
+					t.Errorf("history printed %q, want no %q", out, w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/workspace"
+)
+
+const (
+	homeFlag           = "home"
+	msgReadOnlyHomeCmd = "%s, only commands reading it will work"
+)
+
+// readOnlyList are the commands that work without writing on the rit home
+var readOnlyList = []string{
+	fmt.Sprint(cmdUse),
+	fmt.Sprintf("%s help", cmdUse),
+	fmt.Sprintf("%s completion zsh", cmdUse),
+	fmt.Sprintf("%s completion bash", cmdUse),
+	fmt.Sprintf("%s completion fish", cmdUse),
+	fmt.Sprintf("%s completion powershell", cmdUse),
+	fmt.Sprintf("%s context export", cmdUse),
+	fmt.Sprintf("%s context list", cmdUse),
+	fmt.Sprintf("%s context show", cmdUse),
+	fmt.Sprintf("%s list repo", cmdUse),
+	fmt.Sprintf("%s diff repo", cmdUse),
+	fmt.Sprintf("%s repo verify", cmdUse),
+	fmt.Sprintf("%s show context", cmdUse),
+	fmt.Sprintf("%s show config", cmdUse),
+	fmt.Sprintf("%s show formula", cmdUse),
+	fmt.Sprintf("%s logs", cmdUse),
+	fmt.Sprintf("%s ps", cmdUse),
+	fmt.Sprintf("%s history", cmdUse),
+	fmt.Sprintf("%s rerun", cmdUse),
+	fmt.Sprintf("%s run", cmdUse),
+	fmt.Sprintf("%s run batch", cmdUse),
+	fmt.Sprintf("%s run pipeline", cmdUse),
+	fmt.Sprintf("%s self-test", cmdUse),
+	fmt.Sprintf("%s version", cmdUse),
+	fmt.Sprintf("%s tree", cmdUse),
+}
+
+// addHomeFlag adds the persistent flag that overrides the rit home, see HomeArg
+func addHomeFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().String(homeFlag, "", "Rit home dir, overrides RITCHIE_HOME and ~/.rit")
+}
+
+// HomeArg returns the --home value of the args. It must be read before the
+// commands are built, since their dependencies are created with the rit home.
+func HomeArg(args []string) string {
+	return flagArg(args, homeFlag)
+}
+
+// flagArg returns the value of the flag on the args before they are parsed by cobra
+func flagArg(args []string, name string) string {
+	for i, a := range args {
+		switch {
+		case a == "--":
+			return ""
+		case a == "--"+name && i+1 < len(args):
+			return args[i+1]
+		case strings.HasPrefix(a, "--"+name+"="):
+			return strings.TrimPrefix(a, "--"+name+"=")
+		}
+	}
+	return ""
+}
+
+// checkWorkspace checks the rit home, a read-only home is only a warning for
+// the commands that don't write on it, formulas run staged on the system tmp dir
+func checkWorkspace(wc workspace.Checker, cmd *cobra.Command) error {
+	err := wc.Check()
+
+	var roErr workspace.ReadOnlyHomeError
+	if !errors.As(err, &roErr) {
+		return err
+	}
+
+	_, formula := cmd.Annotations[FormulaAnnotation]
+	if !formula && !isWhitelist(readOnlyList, cmd) && !isCompleteCmd(cmd) {
+		return prompt.NewError(err.Error())
+	}
+
+	if !boolFlag(cmd, quietFlag) {
+		prompt.Warning(fmt.Sprintf(msgReadOnlyHomeCmd, err))
+	}
+	return nil
+}
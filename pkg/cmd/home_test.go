@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/workspace"
+)
+
+func TestHomeArg(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"list", "repo", "--home", "/tmp/rit"}, want: "/tmp/rit"},
+		{args: []string{"--home=/tmp/rit", "init"}, want: "/tmp/rit"},
+		{args: []string{"aws", "create", "--", "--home", "/tmp/rit"}, want: ""},
+		{args: []string{"init"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := HomeArg(tt.args); got != tt.want {
+			t.Errorf("HomeArg(%v) got %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestCheckWorkspaceReadOnlyHome(t *testing.T) {
+	roErr := workspace.ReadOnlyHomeError{Path: "/home/user/.rit"}
+
+	tests := []struct {
+		name        string
+		args        []string
+		checkErr    error
+		wantErr     bool
+		wantWarning bool
+	}{
+		{name: "writable home", args: []string{"init"}},
+		{name: "read command", args: []string{"list", "repo"}, checkErr: roErr, wantWarning: true},
+		{name: "formula command", args: []string{"aws", "create"}, checkErr: roErr, wantWarning: true},
+		{name: "write command", args: []string{"init"}, checkErr: roErr, wantErr: true},
+		{name: "other check error", args: []string{"list", "repo"}, checkErr: errors.New("lock error"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var checkErr error
+			rootCmd := &cobra.Command{
+				Use: cmdUse,
+				PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+					checkErr = checkWorkspace(workspaceCheckerMock{tt.checkErr}, cmd)
+					return nil
+				},
+			}
+			noop := func(cmd *cobra.Command, args []string) {}
+			list := &cobra.Command{Use: "list"}
+			list.AddCommand(&cobra.Command{Use: "repo", Run: noop})
+			aws := &cobra.Command{Use: "aws"}
+			aws.AddCommand(&cobra.Command{Use: "create", Run: noop, Annotations: map[string]string{FormulaAnnotation: "commons"}})
+			rootCmd.AddCommand(list, aws, &cobra.Command{Use: "init", Run: noop})
+			rootCmd.SetArgs(tt.args)
+
+			out := captureStdout(func() {
+				_ = rootCmd.Execute()
+			})
+
+			if (checkErr != nil) != tt.wantErr {
+				t.Errorf("checkWorkspace(%s) got %v, wantErr %v", tt.name, checkErr, tt.wantErr)
+			}
+			if tt.wantErr && tt.checkErr == roErr && !strings.Contains(checkErr.Error(), "RITCHIE_HOME") {
+				t.Errorf("checkWorkspace(%s) got %v, want the error to suggest RITCHIE_HOME", tt.name, checkErr)
+			}
+			if got := strings.Contains(out, roErr.Path); got != tt.wantWarning {
+				t.Errorf("checkWorkspace(%s) warning printed %v, want %v", tt.name, got, tt.wantWarning)
+			}
+		})
+	}
+}
@@ -4,12 +4,17 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/ZupIT/ritchie-cli/pkg/config"
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/repo"
 	"github.com/ZupIT/ritchie-cli/pkg/security/otp"
+	"github.com/ZupIT/ritchie-cli/pkg/session"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
 	"github.com/ZupIT/ritchie-cli/pkg/security"
 	"github.com/ZupIT/ritchie-cli/pkg/server"
 	"github.com/ZupIT/ritchie-cli/pkg/stdin"
@@ -23,12 +28,37 @@ const (
 	MsgServerURL                 = "URL of the server [http(s)://host]: "
 	msgServerURLAlreadyExists    = "The server URL(%s) already exists. Do you like to override?"
 	MsgLogin                     = "You can perform login to your organization now, or later using [rit login] command. Perform now?"
+	msgInitStepDone              = "Skipping %s, already done by a previous init"
+	descSingleInitLong           = `Initialize rit configuration.
+
+Use --from-config to set up rit from a declarative YAML or JSON file with the
+commons repository URL, the repositories to add, the contexts and the settings.
+The passphrase is read from the RIT_PASSPHRASE env, the file has no secrets.`
+	forceFlag       = "force"
+	commonsRepoName = "commons"
+	homeStep        = "ritchie home"
+	commonsStep     = "commons repository"
 )
 
 type initSingleCmd struct {
+	ritchieHome string
 	prompt.InputPassword
 	security.PassphraseManager
 	formula.RepoLoader
+	session.Validator
+	formula.RepoLister
+	formula.RepoAdder
+	ctxSetter rcontext.Setter
+	cfgSetter config.Setter
+}
+
+// initStep is an idempotent step of the init. The done probe tells whether
+// a previous init already completed the step, so an interrupted init can be
+// resumed by running it again.
+type initStep struct {
+	name string
+	done func() (bool, error)
+	run  func() error
 }
 
 type initTeamCmd struct {
@@ -44,13 +74,24 @@ type initTeamCmd struct {
 
 // NewSingleInitCmd creates init command for single edition
 func NewSingleInitCmd(
+	ritchieHome string,
 	ip prompt.InputPassword,
 	pm security.PassphraseManager,
-	rl formula.RepoLoader) *cobra.Command {
-
-	o := initSingleCmd{ip, pm, rl}
-
-	return newInitCmd(o.runStdin(), o.runPrompt())
+	rl formula.RepoLoader,
+	sv session.Validator,
+	rls formula.RepoLister,
+	ra formula.RepoAdder,
+	cs rcontext.Setter,
+	cfs config.Setter) *cobra.Command {
+
+	o := initSingleCmd{ritchieHome, ip, pm, rl, sv, rls, ra, cs, cfs}
+
+	cmd := newInitCmd(o.runStdin(), o.runPrompt())
+	cmd.Long = descSingleInitLong
+	cmd.Example = "rit init\nRIT_PASSPHRASE=secret rit init --from-config setup.yaml"
+	cmd.Flags().Bool(forceFlag, false, "Run every init step, even the ones done by a previous init")
+	cmd.Flags().String(fromConfigFlag, "", "Set up rit non-interactively from a YAML or JSON setup file")
+	return cmd
 }
 
 // NewTeamInitCmd creates init command for team edition
@@ -82,22 +123,27 @@ func newInitCmd(stdinFunc, promptFunc CommandRunnerFunc) *cobra.Command {
 
 func (o initSingleCmd) runPrompt() CommandRunnerFunc {
 	return func(cmd *cobra.Command, args []string) error {
-		pass, err := o.Password(MsgPhrase)
-		if err != nil {
-			return err
+		if file, _ := cmd.Flags().GetString(fromConfigFlag); file != "" {
+			return o.runFromConfig(cmd, file)
 		}
 
-		p := security.Passphrase(pass)
-		if err := o.Save(p); err != nil {
-			return err
+		passphrase := func() error {
+			pass, err := o.Password(MsgPhrase)
+			if err != nil {
+				return err
+			}
+			return o.Save(security.Passphrase(pass))
 		}
 
-		return o.Load()
+		return runInitSteps(o.steps(passphrase), boolFlag(cmd, forceFlag))
 	}
 }
 
 func (o initSingleCmd) runStdin() CommandRunnerFunc {
 	return func(cmd *cobra.Command, args []string) error {
+		if file, _ := cmd.Flags().GetString(fromConfigFlag); file != "" {
+			return o.runFromConfig(cmd, file)
+		}
 
 		obj := struct {
 			Passphrase string `json:"passphrase"`
@@ -109,13 +155,95 @@ func (o initSingleCmd) runStdin() CommandRunnerFunc {
 			return err
 		}
 
-		p := security.Passphrase(obj.Passphrase)
-		if err := o.Save(p); err != nil {
-			return err
+		passphrase := func() error {
+			return o.Save(security.Passphrase(obj.Passphrase))
 		}
 
-		return o.Load()
+		return runInitSteps(o.steps(passphrase), boolFlag(cmd, forceFlag))
+	}
+}
+
+// runFromConfig runs the init steps declared on the setup file
+func (o initSingleCmd) runFromConfig(cmd *cobra.Command, file string) error {
+	c, err := readInitConfig(file)
+	if err != nil {
+		return err
+	}
+
+	steps, err := o.fromConfigSteps(c)
+	if err != nil {
+		return err
+	}
+	return runInitSteps(steps, boolFlag(cmd, forceFlag))
+}
+
+// steps builds the single init steps, passphrase asks and saves the user passphrase
+func (o initSingleCmd) steps(passphrase func() error) []initStep {
+	return []initStep{
+		{
+			name: homeStep,
+			done: func() (bool, error) {
+				return fileutil.Exists(o.ritchieHome), nil
+			},
+			run: func() error {
+				return fileutil.CreateDirIfNotExists(o.ritchieHome, 0755)
+			},
+		},
+		{
+			name: "passphrase",
+			done: func() (bool, error) {
+				return o.Validate() == nil, nil
+			},
+			run: passphrase,
+		},
+		{
+			name: commonsStep,
+			done: func() (bool, error) {
+				return o.repoAdded(commonsRepoName)
+			},
+			run: o.Load,
+		},
+	}
+}
+
+// repoAdded tells whether the repository was added, a repository is
+// only added after its tree is downloaded so no partial state is left behind
+func (o initSingleCmd) repoAdded(name string) (bool, error) {
+	rr, err := o.List()
+	if err == repo.ErrNoRepoToShow {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, r := range rr {
+		if r.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// runInitSteps runs the steps not done yet, or all of them when force is set
+func runInitSteps(steps []initStep, force bool) error {
+	for _, s := range steps {
+		if !force {
+			done, err := s.done()
+			if err != nil {
+				return err
+			}
+			if done {
+				prompt.Print(fmt.Sprintf(msgInitStepDone, s.name))
+				continue
+			}
+		}
+
+		if err := s.run(); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 func (o initTeamCmd) runPrompt() CommandRunnerFunc {
@@ -208,7 +336,7 @@ func (o initTeamCmd) runPrompt() CommandRunnerFunc {
 			if err := o.Load(); err != nil {
 				return err
 			}
-			fmt.Println("Login successfully!")
+			prompt.Print("Login successfully!")
 		}
 
 		return nil
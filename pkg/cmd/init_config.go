@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/ZupIT/ritchie-cli/pkg/config"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
+	"github.com/ZupIT/ritchie-cli/pkg/security"
+	"github.com/ZupIT/ritchie-cli/pkg/slice/sliceutil"
+	"github.com/ZupIT/ritchie-cli/pkg/validator"
+)
+
+const (
+	fromConfigFlag = "from-config"
+	// PassphraseEnv is the passphrase used by rit init --from-config, secrets are never read from the setup file
+	PassphraseEnv    = "RIT_PASSPHRASE"
+	msgInitStepApply = "%s applied"
+)
+
+var ErrMissingPassphrase = fmt.Errorf("the passphrase isn't set yet, inform it on the %s env", PassphraseEnv)
+
+// initConfig is the declarative setup of rit init --from-config, in YAML or JSON:
+//
+//	commons: https://github.com/acme/ritchie-formulas/tree/master # commons repository, the default one when empty
+//	repos:
+//	  - name: acme
+//	    url: https://github.com/acme/formulas/tree/master
+//	    priority: 1
+//	    contexts: [prod]
+//	contexts: [dev, prod]
+//	context: dev
+//	settings:
+//	  accessibility.plain: "true"
+//
+// Credentials aren't part of it, they are set later with rit set credential.
+type initConfig struct {
+	Commons  string            `json:"commons"`
+	Repos    []initRepo        `json:"repos"`
+	Contexts []string          `json:"contexts"`
+	Context  string            `json:"context"`
+	Settings map[string]string `json:"settings"`
+}
+
+type initRepo struct {
+	Name     string   `json:"name"`
+	URL      string   `json:"url"`
+	Priority int      `json:"priority"`
+	Contexts []string `json:"contexts"`
+}
+
+// readInitConfig reads and validates the setup file, so that no step runs when it is invalid
+func readInitConfig(file string) (initConfig, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return initConfig{}, err
+	}
+
+	var c initConfig
+	if err := yaml.UnmarshalStrict(b, &c); err != nil {
+		return initConfig{}, fmt.Errorf("invalid setup file %s: %v", file, err)
+	}
+
+	if err := c.validate(); err != nil {
+		return initConfig{}, fmt.Errorf("invalid setup file %s: %w", file, err)
+	}
+	return c, nil
+}
+
+func (c initConfig) validate() error {
+	if c.Commons != "" {
+		if err := validator.IsValidURL(c.Commons); err != nil {
+			return fmt.Errorf("commons: %w", err)
+		}
+	}
+
+	names := map[string]bool{commonsRepoName: true}
+	for i, r := range c.Repos {
+		if r.Name == "" {
+			return fmt.Errorf("repos[%d]: the name is required", i)
+		}
+		if names[r.Name] {
+			return fmt.Errorf("repos[%d]: the repository %q is declared twice", i, r.Name)
+		}
+		names[r.Name] = true
+
+		if err := validator.IsValidURL(r.URL); err != nil {
+			return fmt.Errorf("repos[%d]: %w", i, err)
+		}
+	}
+
+	for i, ctx := range c.Contexts {
+		if ctx == "" {
+			return fmt.Errorf("contexts[%d]: the context name is required", i)
+		}
+	}
+
+	if c.Context != "" && c.Context != rcontext.DefaultCtx && !sliceutil.Contains(c.Contexts, c.Context) {
+		return fmt.Errorf("context: %q isn't declared on contexts", c.Context)
+	}
+
+	for k, v := range c.Settings {
+		if err := config.Validate(k, v); err != nil {
+			return fmt.Errorf("settings: %w", err)
+		}
+	}
+	return nil
+}
+
+// fromConfigSteps builds the init steps of the setup file, the settings are applied
+// right after the ritchie home is created so that the repositories are added with them
+func (o initSingleCmd) fromConfigSteps(c initConfig) ([]initStep, error) {
+	passphrase := os.Getenv(PassphraseEnv)
+	if passphrase == "" && o.Validate() != nil {
+		return nil, ErrMissingPassphrase
+	}
+
+	var steps []initStep
+	for _, s := range o.steps(func() error { return o.Save(security.Passphrase(passphrase)) }) {
+		if s.name == commonsStep && c.Commons != "" {
+			s.run = func() error {
+				return o.Add(formula.Repository{Name: commonsRepoName, TreePath: c.Commons})
+			}
+		}
+		steps = append(steps, s)
+
+		if s.name == homeStep {
+			steps = append(steps, settingsStep(o.cfgSetter, c.Settings))
+		}
+	}
+
+	for _, r := range c.Repos {
+		steps = append(steps, o.repoStep(r))
+	}
+
+	if len(c.Contexts) > 0 || c.Context != "" {
+		steps = append(steps, contextsStep(o.ctxSetter, c.Contexts, c.Context))
+	}
+
+	return reportSteps(steps), nil
+}
+
+func settingsStep(cs config.Setter, settings map[string]string) initStep {
+	return initStep{
+		name: "settings",
+		done: func() (bool, error) {
+			return len(settings) == 0, nil
+		},
+		run: func() error {
+			keys := make([]string, 0, len(settings))
+			for k := range settings {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				if err := cs.Set(k, settings[k]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func (o initSingleCmd) repoStep(r initRepo) initStep {
+	return initStep{
+		name: fmt.Sprintf("%s repository", r.Name),
+		done: func() (bool, error) {
+			return o.repoAdded(r.Name)
+		},
+		run: func() error {
+			return o.Add(formula.Repository{
+				Name:     r.Name,
+				TreePath: r.URL,
+				Priority: r.Priority,
+				Contexts: r.Contexts,
+			})
+		},
+	}
+}
+
+// contextsStep creates the contexts and sets the current one, the last one
+// created is the current context when none is informed
+func contextsStep(cs rcontext.Setter, contexts []string, current string) initStep {
+	return initStep{
+		name: "contexts",
+		done: func() (bool, error) {
+			return false, nil
+		},
+		run: func() error {
+			for _, ctx := range contexts {
+				if _, err := cs.Set(ctx); err != nil {
+					return err
+				}
+			}
+
+			if current == "" {
+				return nil
+			}
+			_, err := cs.Set(current)
+			return err
+		},
+	}
+}
+
+// reportSteps prints each step applied, the skipped ones are already reported by runInitSteps
+func reportSteps(steps []initStep) []initStep {
+	for i, s := range steps {
+		run, name := s.run, s.name
+		steps[i].run = func() error {
+			if err := run(); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			prompt.Success(fmt.Sprintf(msgInitStepApply, name))
+			return nil
+		}
+	}
+	return steps
+}
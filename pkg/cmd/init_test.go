@@ -3,8 +3,12 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
 	"github.com/ZupIT/ritchie-cli/pkg/security/otp"
 
@@ -14,7 +18,7 @@ import (
 )
 
 func TestNewSingleInitCmd(t *testing.T) {
-	cmd := NewSingleInitCmd(inputPasswordMock{}, passphraseManagerMock{}, repoLoaderMock{})
+	cmd := NewSingleInitCmd(os.TempDir(), inputPasswordMock{}, passphraseManagerMock{}, repoLoaderMock{}, sessionValidatorMock{}, repoListerMock{}, repoAdder{}, ctxSetterMock{}, &configSetterMock{})
 	cmd.PersistentFlags().Bool("stdin", false, "input by stdin")
 
 	if cmd == nil {
@@ -26,6 +30,80 @@ func TestNewSingleInitCmd(t *testing.T) {
 	}
 }
 
+func TestSingleInitResume(t *testing.T) {
+	home := filepath.Join(os.TempDir(), "rit-init-resume")
+	_ = fileutil.RemoveDir(home)
+	defer fileutil.RemoveDir(home)
+
+	commons := []formula.Repository{{Name: "commons"}}
+	tests := []struct {
+		name      string
+		session   error
+		repos     []formula.Repository
+		force     bool
+		wantSaved bool
+		wantLoad  bool
+	}{
+		{
+			name:      "fresh init runs every step",
+			session:   errors.New("no session"),
+			wantSaved: true,
+			wantLoad:  true,
+		},
+		{
+			name:     "resume after passphrase saved",
+			repos:    []formula.Repository{},
+			wantLoad: true,
+		},
+		{
+			name:  "already initialized",
+			repos: commons,
+		},
+		{
+			name:      "force reruns completed steps",
+			repos:     commons,
+			force:     true,
+			wantSaved: true,
+			wantLoad:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pm := &passphraseManagerSpyMock{}
+			rl := &repoLoaderSpyMock{}
+			cmd := NewSingleInitCmd(
+				home,
+				inputPasswordMock{},
+				pm,
+				rl,
+				sessionValidatorCustomMock{tt.session},
+				repoListerCustomMock{tt.repos},
+				repoAdder{},
+				ctxSetterMock{},
+				&configSetterMock{},
+			)
+			cmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			if tt.force {
+				cmd.SetArgs([]string{"--force"})
+			}
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("%s = %v, want %v", cmd.Use, err, nil)
+			}
+			if !fileutil.Exists(home) {
+				t.Errorf("%s did not create the ritchie home", tt.name)
+			}
+			if pm.saved != tt.wantSaved {
+				t.Errorf("%s saved passphrase = %v, want %v", tt.name, pm.saved, tt.wantSaved)
+			}
+			if rl.loaded != tt.wantLoad {
+				t.Errorf("%s loaded commons = %v, want %v", tt.name, rl.loaded, tt.wantLoad)
+			}
+		})
+	}
+}
+
 func Test_initTeamCmd_runStdin(t *testing.T) {
 	type fields struct {
 		InputText     prompt.InputText
@@ -606,3 +684,124 @@ func Test_initTeamCmd_runPrompt(t *testing.T) {
 		})
 	}
 }
+
+func TestSingleInitFromConfig(t *testing.T) {
+	home := filepath.Join(os.TempDir(), "rit-init-from-config")
+	_ = fileutil.RemoveDir(home)
+	defer fileutil.RemoveDir(home)
+
+	setup := filepath.Join(os.TempDir(), "rit-setup.yaml")
+	defer os.Remove(setup)
+
+	valid := `commons: https://github.com/acme/ritchie-formulas/tree/master
+repos:
+  - name: acme
+    url: https://github.com/acme/formulas/tree/master
+    priority: 1
+contexts: [dev, prod]
+context: dev
+settings:
+  accessibility.plain: "true"
+`
+
+	tests := []struct {
+		name       string
+		setup      string
+		passphrase string
+		session    error
+		wantErr    bool
+		wantRepos  []string
+		wantCtx    []string
+		wantKey    string
+	}{
+		{
+			name:       "applies every step",
+			setup:      valid,
+			passphrase: "secret",
+			session:    errors.New("no session"),
+			wantRepos:  []string{"commons", "acme"},
+			wantCtx:    []string{"dev", "prod", "dev"},
+			wantKey:    "accessibility.plain",
+		},
+		{
+			name:    "passphrase missing",
+			setup:   valid,
+			session: errors.New("no session"),
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			setup:   "commons: https://github.com/acme/formulas\ntutorials: off\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid repo url",
+			setup:   "repos:\n  - name: acme\n    url: acme\n",
+			wantErr: true,
+		},
+		{
+			name:    "undeclared current context",
+			setup:   "contexts: [dev]\ncontext: prod\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid setting",
+			setup:   "settings:\n  accessibility.plain: maybe\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := fileutil.WriteFile(setup, []byte(tt.setup)); err != nil {
+				t.Fatal(err)
+			}
+			_ = os.Setenv(PassphraseEnv, tt.passphrase)
+			defer os.Unsetenv(PassphraseEnv)
+
+			ra := &repoAdderSpyMock{}
+			cs := &ctxSetterSpyMock{}
+			cfs := &configSetterMock{}
+			cmd := NewSingleInitCmd(
+				home,
+				inputPasswordMock{},
+				&passphraseManagerSpyMock{},
+				&repoLoaderSpyMock{},
+				sessionValidatorCustomMock{tt.session},
+				repoListerCustomMock{[]formula.Repository{}},
+				ra,
+				cs,
+				cfs,
+			)
+			cmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			cmd.SetArgs([]string{"--from-config", setup})
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			err := cmd.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s = %v, wantErr %v", cmd.Use, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if len(ra.added) != 0 || len(cs.set) != 0 {
+					t.Errorf("%s applied steps of an invalid setup", tt.name)
+				}
+				return
+			}
+
+			var repos []string
+			for _, r := range ra.added {
+				repos = append(repos, r.Name)
+			}
+			if fmt.Sprint(repos) != fmt.Sprint(tt.wantRepos) {
+				t.Errorf("added repos = %v, want %v", repos, tt.wantRepos)
+			}
+			if fmt.Sprint(cs.set) != fmt.Sprint(tt.wantCtx) {
+				t.Errorf("set contexts = %v, want %v", cs.set, tt.wantCtx)
+			}
+			if cfs.key != tt.wantKey {
+				t.Errorf("set config key = %q, want %q", cfs.key, tt.wantKey)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+)
+
+// fullTreeCmds are the core commands that need every formula command registered,
+// because they list, run, complete or suggest formulas
+var fullTreeCmds = []string{"help", "completion", "list", "run", "rerun", "tree", "__complete", "__completeNoDesc"}
+
+// NeedsFormulas tells whether the invocation, without the binary name, needs the
+// formula commands to be registered. Core commands skip reading the repository
+// trees, everything else (help, formula paths, unknown commands and completion)
+// gets the full command tree, so help output and suggestions are unchanged.
+// The flags of the root command tell which flags take the next arg as their value.
+func NeedsFormulas(rootCmd *cobra.Command, args []string, coreCmds api.Commands) bool {
+	first := ""
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			break
+		}
+		if !strings.HasPrefix(a, "-") {
+			first = a
+			break
+		}
+		if a == "--version" || a == "-v" {
+			return false
+		}
+		if takesValue(rootCmd, a) {
+			i++
+		}
+	}
+
+	if first == "" {
+		return true
+	}
+
+	for _, c := range fullTreeCmds {
+		if first == c {
+			return true
+		}
+	}
+
+	for _, c := range coreCmds {
+		if c.Parent == RootCmd && c.Usage == first {
+			return false
+		}
+	}
+
+	return true
+}
+
+// takesValue tells whether the flag arg, without an =value, takes the next arg as its value,
+// like cobra does when it looks for the command of the args
+func takesValue(rootCmd *cobra.Command, arg string) bool {
+	if strings.Contains(arg, "=") {
+		return false
+	}
+
+	for _, flags := range []*pflag.FlagSet{rootCmd.PersistentFlags(), rootCmd.Flags()} {
+		var flag *pflag.Flag
+		if name := strings.TrimPrefix(arg, "--"); name != arg {
+			flag = flags.Lookup(name)
+		} else if len(arg) == 2 {
+			flag = flags.ShorthandLookup(arg[1:])
+		}
+		if flag != nil {
+			return flag.NoOptDefVal == ""
+		}
+	}
+	return false
+}
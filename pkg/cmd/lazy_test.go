@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestNeedsFormulas(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{args: []string{}, want: true},
+		{args: []string{"--help"}, want: true},
+		{args: []string{"--version"}, want: false},
+		{args: []string{"help", "aws"}, want: true},
+		{args: []string{"set", "credential"}, want: false},
+		{args: []string{"--stdin", "init"}, want: false},
+		{args: []string{"list", "repo"}, want: true},
+		{args: []string{"run"}, want: true},
+		{args: []string{"rerun", "2"}, want: true},
+		{args: []string{"completion", "zsh"}, want: true},
+		{args: []string{"__complete", "aws", ""}, want: true},
+		{args: []string{"aws", "create"}, want: true},
+		{args: []string{"unknown"}, want: true},
+		{args: []string{"--color", "never", "set", "credential"}, want: false},
+		{args: []string{"--color=never", "init"}, want: false},
+		{args: []string{"--log-level", "debug", "aws", "create"}, want: true},
+		{args: []string{"--output", "json", "init"}, want: false},
+		{args: []string{"-o", "json", "init"}, want: false},
+		{args: []string{"-q", "init"}, want: false},
+		{args: []string{"--color", "init"}, want: true},
+		{args: []string{"--", "init"}, want: true},
+	}
+
+	rootCmd := lazyRootCmd()
+	rootCmd.PersistentFlags().BoolP(quietFlag, "q", false, "quiet")
+	addColorFlag(rootCmd)
+	addLogLevelFlag(rootCmd)
+	rootCmd.Flags().StringP("output", "o", "", "output format")
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.args), func(t *testing.T) {
+			if got := NeedsFormulas(rootCmd, tt.args, api.CoreCmds); got != tt.want {
+				t.Errorf("NeedsFormulas(%v) got %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLazyCoreCmdHelp ensures a core command renders the same help
+// whether the formula commands were registered or not
+func TestLazyCoreCmdHelp(t *testing.T) {
+	help := func(withFormulas bool) string {
+		rootCmd := lazyRootCmd()
+		if withFormulas {
+			if err := NewFormulaCommand(api.CoreCmds, lazyTreeMock(10), runnerMock{}, runnerMock{}, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+		}
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{"set", "--help"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Execute got %v, want nil", err)
+		}
+		return out.String()
+	}
+
+	full, lazy := help(true), help(false)
+	if full != lazy {
+		t.Errorf("help differs, full:\n%s\nlazy:\n%s", full, lazy)
+	}
+}
+
+func BenchmarkCoreCmdRegistration(b *testing.B) {
+	tree := lazyTreeMock(500)
+	b.Run("full", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rootCmd := lazyRootCmd()
+			if NeedsFormulas(rootCmd, []string{"aws"}, api.CoreCmds) {
+				_ = NewFormulaCommand(api.CoreCmds, tree, runnerMock{}, runnerMock{}, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd)
+			}
+		}
+	})
+	b.Run("lazy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rootCmd := lazyRootCmd()
+			if NeedsFormulas(rootCmd, []string{"set", "credential"}, api.CoreCmds) {
+				_ = NewFormulaCommand(api.CoreCmds, tree, runnerMock{}, runnerMock{}, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd)
+			}
+		}
+	})
+}
+
+func lazyRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{Use: "rit"}
+	rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+	setCmd := NewSetCmd()
+	setCmd.AddCommand(NewSetContextCmd(ctxFindSetterMock{}, inputTextMock{}, inputListMock{}))
+	rootCmd.AddCommand(setCmd)
+	return rootCmd
+}
+
+func lazyTreeMock(size int) treeMock {
+	cc := api.Commands{}
+	for i := 0; i < size; i++ {
+		group := fmt.Sprintf("group%d", i)
+		cc = append(cc,
+			api.Command{Parent: RootCmd, Usage: group, Help: group},
+			api.Command{Parent: RootCmd + "_" + group, Usage: "run", Help: "run", Formula: &api.Formula{Path: group}},
+		)
+	}
+	return treeMock{tree: formula.Tree{Commands: cc}}
+}
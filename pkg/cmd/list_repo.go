@@ -2,50 +2,105 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/gosuri/uitable"
 	"github.com/spf13/cobra"
 
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
 )
 
 // listRepoCmd type for list repo command
 type listRepoCmd struct {
 	formula.RepoLister
+	ctxFinder rcontext.Finder
 }
 
 // NewListRepoCmd creates a new cmd instance
-func NewListRepoCmd(ls formula.RepoLister) *cobra.Command {
-	l := &listRepoCmd{ls}
+func NewListRepoCmd(ls formula.RepoLister, cf rcontext.Finder) *cobra.Command {
+	l := &listRepoCmd{ls, cf}
 
 	cmd := &cobra.Command{
 		Use:     "repo",
 		Short:   "List all repositories.",
-		Example: "rit list repo",
+		Example: "rit list repo\nrit list repo --output yaml",
 		RunE:    l.runFunc(),
 	}
+	addOutputFlag(cmd, "repositories")
 
 	return cmd
 }
 
 func (l listRepoCmd) runFunc() CommandRunnerFunc {
 	return func(cmd *cobra.Command, args []string) error {
+		output, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
 		rr, err := l.List()
 		if err != nil {
 			return err
 		}
 
-		printList(rr)
+		ctx, err := l.ctxFinder.Find()
+		if err != nil {
+			return err
+		}
+
+		if output != "" {
+			return printOutput(output, repoOutputs(rr, ctx.Current))
+		}
+
+		printList(rr, ctx.Current)
 
 		return nil
 	}
 }
 
-func printList(rr []formula.Repository) {
+// repoOutput is a repository of rit list repo --output, the credentials aren't printed
+type repoOutput struct {
+	Name     string   `json:"name"`
+	URL      string   `json:"url"`
+	Priority int      `json:"priority"`
+	Contexts []string `json:"contexts,omitempty"`
+	Active   bool     `json:"active"`
+	Locked   bool     `json:"locked"`
+}
+
+func repoOutputs(rr []formula.Repository, ctx string) []repoOutput {
+	out := make([]repoOutput, 0, len(rr))
+	for _, r := range rr {
+		out = append(out, repoOutput{
+			Name:     r.Name,
+			URL:      r.TreePath,
+			Priority: r.Priority,
+			Contexts: r.Contexts,
+			Active:   r.ActiveIn(ctx),
+			Locked:   r.Locked,
+		})
+	}
+	return out
+}
+
+func printList(rr []formula.Repository, ctx string) {
 	table := uitable.New()
-	table.AddRow("NAME", "URL")
+	table.AddRow("NAME", "URL", "CONTEXTS", "ACTIVE", "LOCKED")
 	for _, re := range rr {
-		table.AddRow(re.Name, re.TreePath)
+		contexts := "all"
+		if len(re.Contexts) > 0 {
+			contexts = strings.Join(re.Contexts, ",")
+		}
+		active := "no"
+		if re.ActiveIn(ctx) {
+			active = "yes"
+		}
+		locked := "no"
+		if re.Locked {
+			locked = "yes"
+		}
+		table.AddRow(re.Name, re.TreePath, contexts, active, locked)
 	}
 	raw := table.Bytes()
 	raw = append(raw, []byte("\n")...)
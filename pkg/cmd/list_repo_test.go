@@ -5,7 +5,7 @@ import (
 )
 
 func TestNewListRepoCmd(t *testing.T) {
-	cmd := NewListRepoCmd(repoListerMock{})
+	cmd := NewListRepoCmd(repoListerMock{}, ctxFinderMock{})
 	if cmd == nil {
 		t.Errorf("NewListRepoCmd got %v", cmd)
 
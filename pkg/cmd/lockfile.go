@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	lockfileFlag     = "file"
+	lockedFlag       = "locked"
+	msgLockWritten   = "The lock file %s was written with %d repositories and %d formulas"
+	msgSynced        = "%d repositories and %d formulas synced from %s"
+	msgFormulaNoTree = "formula %s of the repository %q %w, it isn't on the repository tree anymore"
+	descLockLong     = `Write the rit.lock lock file of the repositories and of their installed formulas, with the
+version of each repository tree and the checksums of the formula files.
+Run rit sync --locked on another machine to reproduce exactly the same formulas.
+This is unrelated to rit repo lock, which only stops rit update repo from updating a repository.`
+	descSyncLong = `Add the repositories of the rit.lock lock file and install its formulas.
+With --locked, rit fails when a repository tree or a formula file doesn't match the lock file,
+nothing is installed when a repository doesn't match. Without it, the mismatches are warned
+and the current versions are installed. The repositories not on the lock file are kept.`
+)
+
+var ErrLockfileVersion = errors.New("unsupported lock file version, upgrade rit to read it")
+
+// lockCmd type for lock command
+type lockCmd struct {
+	formula.RepoPinner
+}
+
+// syncCmd type for sync command
+type syncCmd struct {
+	pinner    formula.RepoPinner
+	installer formula.LockedInstaller
+	refresher formula.Refresher
+}
+
+// NewLockCmd creates a new cmd instance
+func NewLockCmd(rp formula.RepoPinner) *cobra.Command {
+	l := lockCmd{rp}
+
+	cmd := &cobra.Command{
+		Use:     "lock",
+		Short:   "Write the lock file of the repositories and formulas",
+		Long:    descLockLong,
+		Example: "rit lock\nrit lock --file envs/ci.lock",
+		Args:    cobra.NoArgs,
+		RunE:    l.runFunc(),
+	}
+	cmd.Flags().String(lockfileFlag, formula.LockfileName, "Path of the lock file")
+
+	return cmd
+}
+
+func (l lockCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		lock, err := l.Lockfile()
+		if err != nil {
+			return err
+		}
+
+		b, err := json.MarshalIndent(lock, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		file, _ := cmd.Flags().GetString(lockfileFlag)
+		if err := ioutil.WriteFile(file, append(b, '\n'), 0644); err != nil {
+			return err
+		}
+
+		formulas := 0
+		for _, r := range lock.Repos {
+			formulas += len(r.Formulas)
+		}
+		prompt.Success(fmt.Sprintf(msgLockWritten, file, len(lock.Repos), formulas))
+		return nil
+	}
+}
+
+// NewSyncCmd creates a new cmd instance
+func NewSyncCmd(rp formula.RepoPinner, li formula.LockedInstaller, rf formula.Refresher) *cobra.Command {
+	s := syncCmd{rp, li, rf}
+
+	cmd := &cobra.Command{
+		Use:     "sync",
+		Short:   "Install the repositories and formulas of the lock file",
+		Long:    descSyncLong,
+		Example: "rit sync --locked\nrit sync --file envs/ci.lock",
+		Args:    cobra.NoArgs,
+		RunE:    s.runFunc(),
+	}
+	cmd.Flags().String(lockfileFlag, formula.LockfileName, "Path of the lock file")
+	cmd.Flags().Bool(lockedFlag, false, "Fail when a repository or a formula doesn't match the lock file")
+
+	return cmd
+}
+
+func (s syncCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString(lockfileFlag)
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		var lock formula.Lockfile
+		if err := json.Unmarshal(b, &lock); err != nil {
+			return err
+		}
+		if lock.Version != formula.LockfileVersion {
+			return fmt.Errorf("%w: %d", ErrLockfileVersion, lock.Version)
+		}
+
+		strict := boolFlag(cmd, lockedFlag)
+		trees, err := s.pinner.SyncLocked(lock, strict)
+		if err != nil {
+			return err
+		}
+
+		formulas := 0
+		for _, r := range lock.Repos {
+			for _, f := range r.Formulas {
+				if err := s.install(trees[r.Name], r.Name, f, strict); err != nil {
+					return err
+				}
+				formulas++
+			}
+		}
+
+		prompt.Success(fmt.Sprintf(msgSynced, len(lock.Repos), formulas, file))
+		return nil
+	}
+}
+
+// install installs the locked formula of the repository tree, verifying its checksums when strict
+func (s syncCmd) install(tree formula.Tree, repo string, f formula.LockedFormula, strict bool) error {
+	for _, c := range tree.Commands {
+		if c.Formula == nil || c.Formula.Path != f.Path {
+			continue
+		}
+
+		def := definition(formula.CommandPath(c), repo, *c.Formula)
+		if strict {
+			return s.installer.InstallLocked(def, f.Checksums)
+		}
+		return s.refresher.Refresh(def)
+	}
+
+	err := fmt.Errorf(msgFormulaNoTree, f.Path, repo, formula.ErrLockMismatch)
+	if strict {
+		return err
+	}
+	prompt.Warning(err.Error())
+	return nil
+}
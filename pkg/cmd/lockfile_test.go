@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+type repoPinnerMock struct {
+	lock   formula.Lockfile
+	trees  map[string]formula.Tree
+	strict *bool
+}
+
+func (m repoPinnerMock) Lockfile() (formula.Lockfile, error) {
+	return m.lock, nil
+}
+
+func (m repoPinnerMock) SyncLocked(_ formula.Lockfile, strict bool) (map[string]formula.Tree, error) {
+	*m.strict = strict
+	return m.trees, nil
+}
+
+type lockedInstallerMock struct {
+	installed map[string]map[string]string
+}
+
+func (m lockedInstallerMock) InstallLocked(def formula.Definition, checksums map[string]string) error {
+	m.installed[def.Path] = checksums
+	return nil
+}
+
+func TestLockAndSyncCmd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, formula.LockfileName)
+
+	sums := map[string]string{"config.json": "abc"}
+	lock := formula.Lockfile{Version: formula.LockfileVersion, Repos: []formula.LockedRepo{
+		{Name: "commons", TreePath: "https://commons/tree.json", Version: "sha256:0123456789ab", Formulas: []formula.LockedFormula{
+			{Path: "aws/create", Checksums: sums},
+		}},
+	}}
+	trees := map[string]formula.Tree{"commons": {Commands: api.Commands{
+		{Parent: "root_aws", Usage: "create", Formula: &api.Formula{Path: "aws/create", RepoURL: "https://commons"}},
+	}}}
+
+	var strict bool
+	pinner := repoPinnerMock{lock: lock, trees: trees, strict: &strict}
+	lockCmd := NewLockCmd(pinner)
+	lockCmd.SetArgs([]string{"--file", file})
+	if err := lockCmd.Execute(); err != nil {
+		t.Fatalf("%s error = %v", lockCmd.Use, err)
+	}
+
+	var written formula.Lockfile
+	b, _ := ioutil.ReadFile(file)
+	if err := json.Unmarshal(b, &written); err != nil || !reflect.DeepEqual(written, lock) {
+		t.Fatalf("%s wrote %s (%v), want %+v", lockCmd.Use, b, err, lock)
+	}
+
+	t.Run("locked", func(t *testing.T) {
+		installer := lockedInstallerMock{installed: map[string]map[string]string{}}
+		var refreshed []formula.Definition
+		cmd := NewSyncCmd(pinner, installer, refresherMock{defs: &refreshed})
+		cmd.SetArgs([]string{"--file", file, "--locked"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%s error = %v", cmd.Use, err)
+		}
+		if !strict || !reflect.DeepEqual(installer.installed["aws/create"], sums) || len(refreshed) != 0 {
+			t.Errorf("%s --locked installed %v and refreshed %v, want aws/create verified by its checksums", cmd.Use, installer.installed, refreshed)
+		}
+	})
+
+	t.Run("not locked", func(t *testing.T) {
+		installer := lockedInstallerMock{installed: map[string]map[string]string{}}
+		var refreshed []formula.Definition
+		cmd := NewSyncCmd(pinner, installer, refresherMock{defs: &refreshed})
+		cmd.SetArgs([]string{"--file", file})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%s error = %v", cmd.Use, err)
+		}
+		if strict || len(installer.installed) != 0 || len(refreshed) != 1 || refreshed[0].RepoName != "commons" {
+			t.Errorf("%s installed %v and refreshed %v, want aws/create refreshed", cmd.Use, installer.installed, refreshed)
+		}
+	})
+
+	t.Run("formula removed from the repository", func(t *testing.T) {
+		empty := repoPinnerMock{lock: lock, trees: map[string]formula.Tree{}, strict: &strict}
+		cmd := NewSyncCmd(empty, lockedInstallerMock{installed: map[string]map[string]string{}}, refresherMock{defs: &[]formula.Definition{}})
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		cmd.SetArgs([]string{"--file", file, "--locked"})
+		if err := cmd.Execute(); !errors.Is(err, formula.ErrLockMismatch) {
+			t.Errorf("%s error = %v, want %v", cmd.Use, err, formula.ErrLockMismatch)
+		}
+	})
+
+	t.Run("newer lock file", func(t *testing.T) {
+		newer := filepath.Join(dir, "newer.lock")
+		_ = ioutil.WriteFile(newer, []byte(`{"lockfileVersion": 2}`), 0644)
+		cmd := NewSyncCmd(pinner, lockedInstallerMock{}, refresherMock{})
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		cmd.SetArgs([]string{"--file", newer})
+		if err := cmd.Execute(); !errors.Is(err, ErrLockfileVersion) {
+			t.Errorf("%s error = %v, want %v", cmd.Use, err, ErrLockfileVersion)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const logLevelFlag = "log-level"
+
+// defaultLogLevel is the level of the runs without --log-level or --quiet, see SetDefaultLogLevel
+var defaultLogLevel = prompt.LevelInfo
+
+// SetDefaultLogLevel sets the level of the rit messages of the runs without --log-level or
+// --quiet, it is the log.level config
+func SetDefaultLogLevel(l prompt.Level) {
+	defaultLogLevel = l
+}
+
+// addLogLevelFlag adds the persistent flag that sets the level of the rit messages
+func addLogLevelFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().String(logLevelFlag, "", "Print only the rit messages of this level and above [debug|info|warn|error], --quiet is error")
+}
+
+// setLogLevel applies the level of --log-level, --quiet is the error level and, without
+// both, the default level is applied
+func setLogLevel(cmd *cobra.Command) error {
+	level := defaultLogLevel
+	if boolFlag(cmd, quietFlag) {
+		level = prompt.LevelError
+	}
+
+	if f := cmd.Flags().Lookup(logLevelFlag); f != nil && f.Changed {
+		l, err := prompt.ParseLevel(f.Value.String())
+		if err != nil {
+			return err
+		}
+		level = l
+	}
+
+	prompt.SetLevel(level)
+	return nil
+}
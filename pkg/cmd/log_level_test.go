@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+func TestSetLogLevel(t *testing.T) {
+	defer prompt.SetLevel(prompt.LevelInfo)
+	defer SetDefaultLogLevel(prompt.LevelInfo)
+
+	tests := []struct {
+		name         string
+		args         []string
+		defaultLevel prompt.Level
+		want         prompt.Level
+		wantErr      error
+	}{
+		{name: "default info", defaultLevel: prompt.LevelInfo, want: prompt.LevelInfo},
+		{name: "log.level config", defaultLevel: prompt.LevelWarn, want: prompt.LevelWarn},
+		{name: "quiet", args: []string{"--quiet"}, defaultLevel: prompt.LevelInfo, want: prompt.LevelError},
+		{name: "log level replaces the config", args: []string{"--log-level", "debug"}, defaultLevel: prompt.LevelWarn, want: prompt.LevelDebug},
+		{name: "log level replaces quiet", args: []string{"-q", "--log-level", "warn"}, defaultLevel: prompt.LevelInfo, want: prompt.LevelWarn},
+		{name: "invalid", args: []string{"--log-level", "trace"}, wantErr: prompt.ErrInvalidLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetDefaultLogLevel(tt.defaultLevel)
+			prompt.SetLevel(prompt.LevelInfo)
+			cmd := &cobra.Command{
+				Use:  "rit",
+				RunE: func(cmd *cobra.Command, args []string) error { return setLogLevel(cmd) },
+			}
+			cmd.PersistentFlags().BoolP(quietFlag, "q", false, "quiet")
+			addLogLevelFlag(cmd)
+			cmd.SetArgs(tt.args)
+
+			if err := cmd.Execute(); err != tt.wantErr {
+				t.Fatalf("setLogLevel(%s) got %v, want %v", tt.name, err, tt.wantErr)
+			}
+			if got := prompt.CurrentLevel(); tt.wantErr == nil && got != tt.want {
+				t.Errorf("setLogLevel(%s) got level %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runlog"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	lastFlag      = "last"
+	formulaFlag   = "formula"
+	followFlag    = "follow"
+	filterFlag    = "filter"
+	labelFilter   = "label="
+	runLogTimeFmt = "2006-01-02 15:04:05"
+	descLogsLong  = `Print the output of the latest formula runs.
+
+Without arguments the run logs are listed, use --last or the log ID to print one.
+The runs are logged once enabled with rit set config logs.enabled true, the formula
+output is kept up to 1MB per run and only the latest 50 runs are kept.
+Inputs are passed to formulas as environment variables, so they are never logged
+unless the formula prints them. The runs started with --detach are always logged,
+their log ID is the ID listed by rit ps.`
+)
+
+var (
+	ErrNoRunLogs         = errors.New("no formula run logs found")
+	ErrRunLogNotFound    = errors.New("formula run log not found")
+	ErrInvalidLogsFilter = errors.New("--filter must be label=KEY=VALUE or label=KEY")
+)
+
+type logsCmd struct {
+	runlog.ListFollower
+}
+
+// NewLogsCmd creates a new cmd instance
+func NewLogsCmd(lf runlog.ListFollower) *cobra.Command {
+	l := logsCmd{lf}
+
+	cmd := &cobra.Command{
+		Use:   "logs [ID]",
+		Short: "Print the output of formula runs",
+		Long:  descLogsLong,
+		Example: `rit logs
+rit logs --last
+rit logs --last --follow --formula "rit aws create"
+rit logs --filter label=ci=123`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: l.runFunc(),
+	}
+
+	flags := cmd.Flags()
+	flags.Bool(lastFlag, false, "Print the log of the last run")
+	flags.String(formulaFlag, "", "Only the runs of the formula, e.g. \"rit aws create\"")
+	flags.BoolP(followFlag, "f", false, "Keep printing the log output until the run ends")
+	flags.StringArray(filterFlag, nil, "Only the runs with the --label, e.g. label=ci=123 or label=ci, can be repeated")
+	addOutputFlag(cmd, "logs metadata")
+
+	return cmd
+}
+
+func (l logsCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		last := boolFlag(cmd, lastFlag)
+		follow := boolFlag(cmd, followFlag)
+		form, err := cmd.Flags().GetString(formulaFlag)
+		if err != nil {
+			return err
+		}
+		output, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+		filters, err := cmd.Flags().GetStringArray(filterFlag)
+		if err != nil {
+			return err
+		}
+
+		ee, err := l.List()
+		if err != nil {
+			return err
+		}
+		ee = filterRunLogs(ee, form)
+		if ee, err = filterRunLogLabels(ee, filters); err != nil {
+			return err
+		}
+
+		if len(args) == 0 && !last && !follow {
+			if output != "" {
+				return printOutput(output, ee)
+			}
+			if len(ee) == 0 {
+				return ErrNoRunLogs
+			}
+			printRunLogs(ee)
+			return nil
+		}
+
+		e, err := selectRunLog(ee, args)
+		if err != nil {
+			return err
+		}
+
+		if output != "" {
+			return printOutput(output, e)
+		}
+
+		if follow {
+			return l.Follow(e, os.Stdout)
+		}
+
+		b, err := fileutil.ReadFile(e.File)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(b))
+		return nil
+	}
+}
+
+// filterRunLogs keeps the logs of the formula, all of them when form is empty
+func filterRunLogs(ee []runlog.Entry, form string) []runlog.Entry {
+	if form == "" {
+		return ee
+	}
+
+	var filtered []runlog.Entry
+	for _, e := range ee {
+		if e.Command == form {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// filterRunLogLabels keeps the logs with every label of the filters, label=KEY=VALUE
+// matches the label value and label=KEY any value of the label
+func filterRunLogLabels(ee []runlog.Entry, filters []string) ([]runlog.Entry, error) {
+	for _, f := range filters {
+		if !strings.HasPrefix(f, labelFilter) || len(f) == len(labelFilter) {
+			return nil, ErrInvalidLogsFilter
+		}
+		kv := strings.SplitN(strings.TrimPrefix(f, labelFilter), "=", 2)
+
+		var filtered []runlog.Entry
+		for _, e := range ee {
+			v, ok := e.Labels[kv[0]]
+			if ok && (len(kv) == 1 || v == kv[1]) {
+				filtered = append(filtered, e)
+			}
+		}
+		ee = filtered
+	}
+	return ee, nil
+}
+
+// selectRunLog returns the log with the ID passed as arg or the last one
+func selectRunLog(ee []runlog.Entry, args []string) (runlog.Entry, error) {
+	if len(ee) == 0 {
+		return runlog.Entry{}, ErrNoRunLogs
+	}
+
+	if len(args) == 0 {
+		return ee[len(ee)-1], nil
+	}
+
+	for _, e := range ee {
+		if e.ID == args[0] {
+			return e, nil
+		}
+	}
+	return runlog.Entry{}, ErrRunLogNotFound
+}
+
+func printRunLogs(ee []runlog.Entry) {
+	table := uitable.New()
+	table.AddRow("ID", "COMMAND", "START", "STATUS", "LABELS")
+	for _, e := range ee {
+		table.AddRow(e.ID, e.Command, e.Start.Format(runLogTimeFmt), runLogStatus(e), runLogLabels(e))
+	}
+	fmt.Println(table)
+}
+
+// runLogLabels returns the labels of the run sorted by key, e.g. "ci=123,ticket=OPS-1"
+func runLogLabels(e runlog.Entry) string {
+	labels := make([]string, 0, len(e.Labels))
+	for k, v := range e.Labels {
+		labels = append(labels, k+"="+v)
+	}
+	sort.Strings(labels)
+	return strings.Join(labels, ",")
+}
+
+func runLogStatus(e runlog.Entry) string {
+	switch {
+	case e.Running():
+		return "running"
+	case e.Error != "":
+		return prompt.Red("failed")
+	default:
+		return prompt.Green("succeeded")
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runlog"
+)
+
+func TestLogsCmd(t *testing.T) {
+	file := filepath.Join(os.TempDir(), "rit-logs-cmd.log")
+	if err := fileutil.WriteFile(file, []byte("bucket created\n")); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file)
+
+	start := time.Date(2020, 7, 20, 10, 0, 0, 0, time.UTC)
+	entries := []runlog.Entry{
+		{ID: "1-rit-aws-create", Command: "rit aws create", File: file, Start: start, End: start, Labels: map[string]string{"ci": "123"}},
+		{ID: "2-rit-aws-delete", Command: "rit aws delete", File: file, Start: start, End: start, Error: "exit status 1"},
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		entries  []runlog.Entry
+		want     []string
+		followed string
+		wantErr  error
+	}{
+		{
+			name:    "list logs",
+			entries: entries,
+			want:    []string{"1-rit-aws-create", "2-rit-aws-delete", "failed", "ci=123"},
+		},
+		{
+			name:     "follow last log with a label value",
+			args:     []string{"--follow", "--filter", "label=ci=123"},
+			entries:  entries,
+			followed: "1-rit-aws-create",
+		},
+		{
+			name:     "follow last log with a label",
+			args:     []string{"--follow", "--filter", "label=ci"},
+			entries:  entries,
+			followed: "1-rit-aws-create",
+		},
+		{
+			name:    "no log with the label value",
+			args:    []string{"--last", "--filter", "label=ci=999"},
+			entries: entries,
+			wantErr: ErrNoRunLogs,
+		},
+		{
+			name:    "invalid filter",
+			args:    []string{"--filter", "ci=123"},
+			entries: entries,
+			wantErr: ErrInvalidLogsFilter,
+		},
+		{
+			name:    "print last log",
+			args:    []string{"--last"},
+			entries: entries,
+			want:    []string{"bucket created"},
+		},
+		{
+			name:     "follow last log of a formula",
+			args:     []string{"--follow", "--formula", "rit aws create"},
+			entries:  entries,
+			followed: "1-rit-aws-create",
+		},
+		{
+			name:    "logs metadata as json",
+			args:    []string{"--output", "json"},
+			entries: entries,
+			want:    []string{`"id": "2-rit-aws-delete"`, `"error": "exit status 1"`},
+		},
+		{
+			name:    "log not found",
+			args:    []string{"3-rit-aws-update"},
+			entries: entries,
+			wantErr: ErrRunLogNotFound,
+		},
+		{
+			name:    "no logs",
+			wantErr: ErrNoRunLogs,
+		},
+		{
+			name:    "logs metadata as yaml",
+			args:    []string{"--output", "yaml"},
+			entries: entries,
+			want:    []string{`id: 2-rit-aws-delete`, `error: exit status 1`},
+		},
+		{
+			name:    "invalid output",
+			args:    []string{"--output", "xml"},
+			wantErr: ErrInvalidOutput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lf := &runLogsMock{entries: tt.entries}
+			cmd := NewLogsCmd(lf)
+			cmd.SetArgs(tt.args)
+
+			var err error
+			out := captureStdout(func() {
+				err = cmd.Execute()
+			})
+
+			if err != tt.wantErr {
+				t.Fatalf("%s got error %v, want %v", tt.name, err, tt.wantErr)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(out, w) {
+					t.Errorf("%s output %q does not contain %q", tt.name, out, w)
+				}
+			}
+			if lf.followed != tt.followed {
+				t.Errorf("%s followed %q, want %q", tt.name, lf.followed, tt.followed)
+			}
+		})
+	}
+}
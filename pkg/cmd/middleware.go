@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/server"
+	"github.com/ZupIT/ritchie-cli/pkg/session"
+	"github.com/ZupIT/ritchie-cli/pkg/version"
+	"github.com/ZupIT/ritchie-cli/pkg/workspace"
+)
+
+// Middleware wraps the execution of a command. It runs its code before calling next,
+// which runs the rest of the chain and the command, and its code after next returns.
+// A middleware short-circuits the command by returning without calling next, it can
+// read or annotate the command through cmd.Annotations.
+type Middleware func(cmd *cobra.Command, args []string, next func() error) error
+
+// Chain is an ordered list of middleware wrapping every runnable command.
+// The first middleware registered is the outermost one: the code before next runs
+// in the registration order and the code after next in the reverse order.
+// The first error returned stops the chain and is returned by the command.
+type Chain struct {
+	middleware []Middleware
+}
+
+// NewChain creates a chain with the middleware
+func NewChain(mm ...Middleware) *Chain {
+	return &Chain{middleware: mm}
+}
+
+// NewSingleChain creates the chain with the built-in middleware of the single edition
+func NewSingleChain(wc workspace.Checker, sv session.Validator, vr version.Resolver) *Chain {
+	return NewChain(
+		logLevelMiddleware,
+		colorMiddleware,
+		workspaceMiddleware(wc),
+		singleInitMiddleware(sv),
+		versionMiddleware(vr),
+	)
+}
+
+// NewTeamChain creates the chain with the built-in middleware of the team edition
+func NewTeamChain(wc workspace.Checker, sf server.Finder, sv session.Validator, vr version.Resolver) *Chain {
+	return NewChain(
+		logLevelMiddleware,
+		colorMiddleware,
+		workspaceMiddleware(wc),
+		teamSessionMiddleware(sf, sv),
+		versionMiddleware(vr),
+	)
+}
+
+// Use registers middleware at the end of the chain, closer to the command
+func (c *Chain) Use(mm ...Middleware) {
+	c.middleware = append(c.middleware, mm...)
+}
+
+// Run runs the command through the chain
+func (c *Chain) Run(cmd *cobra.Command, args []string, run CommandRunnerFunc) error {
+	var next func(i int) error
+	next = func(i int) error {
+		if i == len(c.middleware) {
+			return run(cmd, args)
+		}
+		return c.middleware[i](cmd, args, func() error { return next(i + 1) })
+	}
+	return next(0)
+}
+
+// Apply wraps the runnable commands of the tree, it must be called
+// after all the commands, including the formulas, are added to root
+func (c *Chain) Apply(root *cobra.Command) {
+	root.InitDefaultHelpCmd()
+	c.apply(root)
+}
+
+func (c *Chain) apply(cmd *cobra.Command) {
+	for _, child := range cmd.Commands() {
+		c.apply(child)
+	}
+
+	if cmd.Run != nil && cmd.RunE == nil {
+		run := cmd.Run
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			run(cmd, args)
+			return nil
+		}
+		cmd.Run = nil
+	}
+
+	if cmd.RunE == nil {
+		return
+	}
+
+	run := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return c.Run(cmd, args, run)
+	}
+}
+
+// logLevelMiddleware sets the level of the messages of the prompt, --quiet silences all but the errors
+func logLevelMiddleware(cmd *cobra.Command, args []string, next func() error) error {
+	if err := setLogLevel(cmd); err != nil {
+		return err
+	}
+	return next()
+}
+
+func colorMiddleware(cmd *cobra.Command, args []string, next func() error) error {
+	if err := setColor(cmd); err != nil {
+		return err
+	}
+	return next()
+}
+
+func workspaceMiddleware(wc workspace.Checker) Middleware {
+	return func(cmd *cobra.Command, args []string, next func() error) error {
+		if err := checkWorkspace(wc, cmd); err != nil {
+			return err
+		}
+		return next()
+	}
+}
+
+func singleInitMiddleware(sv session.Validator) Middleware {
+	return func(cmd *cobra.Command, args []string, next func() error) error {
+		if isWhitelist(singleIgnorelist, cmd) || isCompleteCmd(cmd) {
+			return next()
+		}
+
+		if err := sv.Validate(); err != nil {
+			fmt.Println(MsgInit)
+			return nil
+		}
+		return next()
+	}
+}
+
+func teamSessionMiddleware(sf server.Finder, sv session.Validator) Middleware {
+	return func(cmd *cobra.Command, args []string, next func() error) error {
+		if isWhitelist(teamIgnorelist, cmd) || isCompleteCmd(cmd) {
+			return next()
+		}
+
+		cfg, err := sf.Find()
+		if err != nil {
+			return err
+		} else if cfg.URL == "" {
+			fmt.Println(MsgInit)
+			return nil
+		}
+
+		if err := sv.Validate(); err != nil {
+			fmt.Println(MsgSession)
+			return nil
+		}
+		return next()
+	}
+}
+
+func versionMiddleware(vr version.Resolver) Middleware {
+	return func(cmd *cobra.Command, args []string, next func() error) error {
+		if err := next(); err != nil {
+			return err
+		}
+		verifyNewVersion(cmd, vr)
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func recordMiddleware(name string, calls *[]string) Middleware {
+	return func(cmd *cobra.Command, args []string, next func() error) error {
+		*calls = append(*calls, "before "+name)
+		err := next()
+		*calls = append(*calls, "after "+name)
+		return err
+	}
+}
+
+func TestChainOrder(t *testing.T) {
+	var calls []string
+	chain := NewChain(recordMiddleware("first", &calls))
+	chain.Use(recordMiddleware("second", &calls))
+
+	root := &cobra.Command{Use: "rit"}
+	root.AddCommand(&cobra.Command{
+		Use: "cmd",
+		Run: func(cmd *cobra.Command, args []string) {
+			calls = append(calls, "cmd "+args[0])
+		},
+	})
+	chain.Apply(root)
+
+	root.SetArgs([]string{"cmd", "arg"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute error = %v", err)
+	}
+
+	want := []string{"before first", "before second", "cmd arg", "after second", "after first"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Chain got %v, want %v", calls, want)
+	}
+}
+
+func TestChainShortCircuit(t *testing.T) {
+	errStop := errors.New("stop")
+	tests := []struct {
+		name    string
+		stop    Middleware
+		wantErr error
+	}{
+		{
+			name: "skip the command",
+			stop: func(cmd *cobra.Command, args []string, next func() error) error {
+				return nil
+			},
+		},
+		{
+			name: "return an error",
+			stop: func(cmd *cobra.Command, args []string, next func() error) error {
+				return errStop
+			},
+			wantErr: errStop,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			chain := NewChain(recordMiddleware("outer", &calls), tt.stop, recordMiddleware("inner", &calls))
+
+			run := func(cmd *cobra.Command, args []string) error {
+				calls = append(calls, "cmd")
+				return nil
+			}
+			err := chain.Run(&cobra.Command{}, nil, run)
+			if err != tt.wantErr {
+				t.Errorf("Run error = %v, want %v", err, tt.wantErr)
+			}
+
+			want := []string{"before outer", "after outer"}
+			if !reflect.DeepEqual(calls, want) {
+				t.Errorf("Run got %v, want %v", calls, want)
+			}
+		})
+	}
+}
+
+func TestSingleInitMiddleware(t *testing.T) {
+	m := singleInitMiddleware(sessionValidatorCustomMock{errors.New("no session")})
+
+	ran := false
+	next := func() error {
+		ran = true
+		return nil
+	}
+
+	root := &cobra.Command{Use: "rit"}
+	list := &cobra.Command{Use: "list"}
+	initCmd := &cobra.Command{Use: "init"}
+	root.AddCommand(list, initCmd)
+
+	if err := m(list, nil, next); err != nil || ran {
+		t.Errorf("rit list without init got err %v and ran %v, want it skipped", err, ran)
+	}
+
+	if err := m(initCmd, nil, next); err != nil || !ran {
+		t.Errorf("rit init got err %v and ran %v, want it to run", err, ran)
+	}
+}
@@ -3,14 +3,17 @@ package cmd
 import (
 	"github.com/ZupIT/ritchie-cli/pkg/api"
 	"github.com/ZupIT/ritchie-cli/pkg/autocomplete"
+	"github.com/ZupIT/ritchie-cli/pkg/config"
 	"github.com/ZupIT/ritchie-cli/pkg/credential"
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runlog"
 	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
 	"github.com/ZupIT/ritchie-cli/pkg/security"
 	"github.com/ZupIT/ritchie-cli/pkg/security/otp"
 	"github.com/ZupIT/ritchie-cli/pkg/server"
 
 	"errors"
+	"io"
 
 	"github.com/spf13/cobra"
 )
@@ -174,6 +177,32 @@ func (repoDeleterMock) Delete(name string) error {
 	return nil
 }
 
+type repoDelListerCustomMock struct {
+	repos  []formula.Repository
+	delete func(name string) error
+}
+
+func (m repoDelListerCustomMock) List() ([]formula.Repository, error) {
+	return m.repos, nil
+}
+
+func (m repoDelListerCustomMock) Delete(name string) error {
+	return m.delete(name)
+}
+
+type ctxFindRemoverCustomMock struct {
+	find   func() (rcontext.ContextHolder, error)
+	remove func(ctx string) (rcontext.ContextHolder, error)
+}
+
+func (m ctxFindRemoverCustomMock) Find() (rcontext.ContextHolder, error) {
+	return m.find()
+}
+
+func (m ctxFindRemoverCustomMock) Remove(ctx string) (rcontext.ContextHolder, error) {
+	return m.remove(ctx)
+}
+
 type repoListerMock struct{}
 
 func (repoListerMock) List() ([]formula.Repository, error) {
@@ -188,10 +217,39 @@ func (repoLoaderMock) Load() error {
 
 type repoUpdaterMock struct{}
 
-func (repoUpdaterMock) Update() error {
+func (repoUpdaterMock) Update(force bool) error {
 	return nil
 }
 
+type repoLockerMock struct {
+	locked map[string]bool
+	err    error
+}
+
+func (m repoLockerMock) Lock(name string, locked bool) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.locked[name] = locked
+	return nil
+}
+
+type repoPlannerMock struct {
+	plans []formula.RepoPlan
+}
+
+func (m repoPlannerMock) PlanAdd(r formula.Repository) (formula.RepoPlan, error) {
+	return formula.RepoPlan{Operation: formula.RepoAdd, Repo: r.Name}, nil
+}
+
+func (m repoPlannerMock) PlanUpdate(force bool) ([]formula.RepoPlan, error) {
+	return m.plans, nil
+}
+
+func (m repoPlannerMock) PlanDelete(name string) (formula.RepoPlan, error) {
+	return formula.RepoPlan{Operation: formula.RepoDelete, Repo: name}, nil
+}
+
 type loginManagerMock struct{}
 
 func (loginManagerMock) Login(security.User) error {
@@ -267,6 +325,91 @@ func (r runnerMock) Run(def formula.Definition, inputType api.TermInputType, ver
 	return r.error
 }
 
+type runnerSpyMock struct {
+	def   *formula.Definition
+	error error
+}
+
+func (r runnerSpyMock) Run(def formula.Definition, inputType api.TermInputType, verboseFlag string) error {
+	*r.def = def
+	return r.error
+}
+
+// runnerRecorderMock records the formulas run, failing with fail[def.Command] when set
+type runnerRecorderMock struct {
+	runs *[]formula.Definition
+	fail map[string]error
+}
+
+func (r runnerRecorderMock) Run(def formula.Definition, inputType api.TermInputType, verboseFlag string) error {
+	*r.runs = append(*r.runs, def)
+	return r.fail[def.Command]
+}
+
+// runnerFlakyMock fails its nth run with errs[n], the runs after the errors succeed.
+// The inputs are answered with inputs and the env of the last run is kept on env.
+type runnerFlakyMock struct {
+	runs   *int
+	errs   []error
+	inputs map[string]string
+	env    *[]string
+}
+
+func (r runnerFlakyMock) Run(def formula.Definition, inputType api.TermInputType, verboseFlag string) error {
+	*r.runs++
+	if r.env != nil {
+		*r.env = def.Env
+	}
+	for name, v := range r.inputs {
+		if def.Inputs != nil {
+			def.Inputs[name] = v
+		}
+	}
+	if *r.runs <= len(r.errs) {
+		return r.errs[*r.runs-1]
+	}
+	return nil
+}
+
+type pullerMock struct {
+	error error
+}
+
+func (p pullerMock) Pull(def formula.Definition) error {
+	return p.error
+}
+
+type repoDifferMock struct {
+	changes []formula.FileChange
+	err     error
+}
+
+func (r repoDifferMock) Diff(name string) ([]formula.FileChange, error) {
+	return r.changes, r.err
+}
+
+// sessionStopperMock records the formulas whose session was stopped
+type sessionStopperMock struct {
+	stopped *[]string
+	error   error
+}
+
+func (s sessionStopperMock) StopSession(def formula.Definition) error {
+	if s.stopped != nil {
+		*s.stopped = append(*s.stopped, def.Path)
+	}
+	return s.error
+}
+
+type configLoaderMock struct {
+	config formula.Config
+	err    error
+}
+
+func (c configLoaderMock) LoadConfig(formula.Definition) (formula.Config, error) {
+	return c.config, c.err
+}
+
 type treeMock struct {
 	tree  formula.Tree
 	error error
@@ -286,6 +429,64 @@ func (passphraseManagerMock) Save(security.Passphrase) error {
 	return nil
 }
 
+type passphraseManagerSpyMock struct {
+	saved bool
+}
+
+func (m *passphraseManagerSpyMock) Save(security.Passphrase) error {
+	m.saved = true
+	return nil
+}
+
+type repoLoaderSpyMock struct {
+	loaded bool
+}
+
+func (m *repoLoaderSpyMock) Load() error {
+	m.loaded = true
+	return nil
+}
+
+type repoAdderSpyMock struct {
+	added []formula.Repository
+}
+
+func (m *repoAdderSpyMock) Add(r formula.Repository) error {
+	m.added = append(m.added, r)
+	return nil
+}
+
+type ctxSetterSpyMock struct {
+	set []string
+}
+
+func (m *ctxSetterSpyMock) Set(ctx string) (rcontext.ContextHolder, error) {
+	m.set = append(m.set, ctx)
+	return rcontext.ContextHolder{Current: ctx}, nil
+}
+
+type repoListerCustomMock struct {
+	repos []formula.Repository
+}
+
+func (m repoListerCustomMock) List() ([]formula.Repository, error) {
+	return m.repos, nil
+}
+
+type sessionValidatorMock struct{}
+
+func (sessionValidatorMock) Validate() error {
+	return nil
+}
+
+type sessionValidatorCustomMock struct {
+	err error
+}
+
+func (m sessionValidatorCustomMock) Validate() error {
+	return m.err
+}
+
 type findSetterServerMock struct{}
 
 func (findSetterServerMock) Set(*server.Config) error {
@@ -407,4 +608,54 @@ func (fmc FileManagerCustomMock) Read(path string) ([]byte, error) {
 // Exists of FileManagerCustomMock
 func (fmc FileManagerCustomMock) Exists(path string) bool {
 	return fmc.exists(path)
-}
\ No newline at end of file
+}
+type configFinderMock struct {
+	cfg config.Config
+}
+
+func (m configFinderMock) Find() (config.Config, error) {
+	return m.cfg, nil
+}
+
+type configSetterMock struct {
+	key string
+	err error
+}
+
+func (m *configSetterMock) Set(key, value string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.key = key
+	return nil
+}
+
+type runLogsMock struct {
+	entries  []runlog.Entry
+	followed string
+}
+
+func (m *runLogsMock) List() ([]runlog.Entry, error) {
+	return m.entries, nil
+}
+
+func (m *runLogsMock) Follow(e runlog.Entry, w io.Writer) error {
+	m.followed = e.ID
+	return nil
+}
+
+type workspaceCheckerMock struct {
+	error error
+}
+
+func (w workspaceCheckerMock) Check() error {
+	return w.error
+}
+
+type selfTesterMock struct {
+	err error
+}
+
+func (s selfTesterMock) SelfTest() error {
+	return s.err
+}
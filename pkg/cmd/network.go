@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/config"
+	"github.com/ZupIT/ritchie-cli/pkg/http/httpclient"
+)
+
+const (
+	networkTimeoutFlag = "network-timeout"
+	networkRetriesFlag = "network-retries"
+)
+
+// addNetworkFlags adds the persistent flags of the network policy, see NetworkArgs
+func addNetworkFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	flags.Duration(networkTimeoutFlag, 0, "Timeout of the network requests, e.g. 10s, overrides the network.timeout config")
+	flags.Int(networkRetriesFlag, 0, "Times a failed network request is retried, overrides the network.retries config")
+}
+
+// NetworkArgs returns the network policy of the args, falling back to the config one.
+// Like TLSArgs, it must be read before the http clients are created.
+func NetworkArgs(args []string, cfg config.Config) httpclient.Network {
+	n := httpclient.Network{
+		Timeout: cfg.Duration(config.NetworkTimeoutKey),
+		Retries: cfg.Int(config.NetworkRetriesKey),
+	}
+	if d, err := time.ParseDuration(flagArg(args, networkTimeoutFlag)); err == nil && d > 0 {
+		n.Timeout = d
+	}
+	if r, err := strconv.Atoi(flagArg(args, networkRetriesFlag)); err == nil && r >= 0 {
+		n.Retries = r
+	}
+	return n
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/config"
+	"github.com/ZupIT/ritchie-cli/pkg/http/httpclient"
+)
+
+func TestNetworkArgs(t *testing.T) {
+	cfg := config.Config{config.NetworkTimeoutKey: "10s", config.NetworkRetriesKey: "5"}
+
+	tests := []struct {
+		name string
+		args []string
+		cfg  config.Config
+		want httpclient.Network
+	}{
+		{name: "defaults", args: []string{"list", "repo"}, want: httpclient.Network{}},
+		{name: "config", args: []string{"list", "repo"}, cfg: cfg, want: httpclient.Network{Timeout: 10 * time.Second, Retries: 5}},
+		{
+			name: "flags override the config",
+			args: []string{"update", "repo", "--network-timeout", "1m", "--network-retries=0"},
+			cfg:  cfg,
+			want: httpclient.Network{Timeout: time.Minute},
+		},
+		{
+			name: "formula args",
+			args: []string{"aws", "create", "--", "--network-retries", "2"},
+			want: httpclient.Network{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NetworkArgs(tt.args, tt.cfg); got != tt.want {
+				t.Errorf("NetworkArgs got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
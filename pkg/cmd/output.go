@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	outputFlag = "output"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+var ErrInvalidOutput = errors.New("invalid output, use --output json or --output yaml")
+
+// addOutputFlag adds the flag of the commands that print structured data, what names the data
+func addOutputFlag(cmd *cobra.Command, what string) {
+	cmd.Flags().StringP(outputFlag, "o", "", fmt.Sprintf("Output format of the %s [json|yaml]", what))
+}
+
+// outputFormat returns the validated format of the output flag, empty for the text output
+func outputFormat(cmd *cobra.Command) (string, error) {
+	output, err := cmd.Flags().GetString(outputFlag)
+	if err != nil {
+		return "", err
+	}
+
+	switch output {
+	case "", outputJSON, outputYAML:
+		return output, nil
+	default:
+		return "", ErrInvalidOutput
+	}
+}
+
+// printOutput prints v as JSON or YAML. The YAML is converted from the JSON,
+// so both formats have the same field names.
+func printOutput(format string, v interface{}) error {
+	if format == outputYAML {
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(b))
+		return nil
+	}
+	return printJSON(v)
+}
+
+func printJSON(v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/config"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
+)
+
+type ctxFinderCustomMock struct {
+	holder rcontext.ContextHolder
+}
+
+func (m ctxFinderCustomMock) Find() (rcontext.ContextHolder, error) {
+	return m.holder, nil
+}
+
+func TestStructuredOutput(t *testing.T) {
+	repos := repoListerCustomMock{repos: []formula.Repository{
+		{Name: "commons", TreePath: "https://commons/tree.json", Priority: 0, Password: "s3cr3t"},
+		{Name: "acme", TreePath: "https://acme/tree.json", Priority: 1, Contexts: []string{"prod"}, Locked: true},
+	}}
+	ctx := ctxFinderCustomMock{holder: rcontext.ContextHolder{Current: "dev", All: []string{"dev", "prod"}}}
+	version := stubVersionResolver{stableVersion: func() (string, error) { return "9.9.9", nil }}
+
+	tests := []struct {
+		name string
+		cmd  func() *cobra.Command
+		want interface{}
+	}{
+		{
+			name: "list repo",
+			cmd:  func() *cobra.Command { return NewListRepoCmd(repos, ctx) },
+			want: []interface{}{
+				map[string]interface{}{"name": "commons", "url": "https://commons/tree.json", "priority": 0.0, "active": true, "locked": false},
+				map[string]interface{}{"name": "acme", "url": "https://acme/tree.json", "priority": 1.0, "contexts": []interface{}{"prod"}, "active": false, "locked": true},
+			},
+		},
+		{
+			name: "show context",
+			cmd:  func() *cobra.Command { return NewShowContextCmd(ctx) },
+			want: map[string]interface{}{"current": "dev", "contexts": []interface{}{"dev", "prod"}},
+		},
+		{
+			name: "show config",
+			cmd: func() *cobra.Command {
+				return NewShowConfigCmd(configFinderMock{cfg: config.Config{config.PlainKey: "true"}})
+			},
+		},
+		{
+			name: "version",
+			cmd:  func() *cobra.Command { return NewVersionCmd(api.Single, version) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputs := map[string]interface{}{}
+			for _, format := range []string{outputJSON, outputYAML} {
+				cmd := tt.cmd()
+				cmd.SetArgs([]string{"--output", format})
+
+				var err error
+				out := captureStdout(func() { err = cmd.Execute() })
+				if err != nil {
+					t.Fatalf("--output %s = %v, want nil", format, err)
+				}
+
+				var v interface{}
+				if format == outputJSON {
+					err = json.Unmarshal([]byte(out), &v)
+				} else {
+					err = yaml.Unmarshal([]byte(out), &v)
+				}
+				if err != nil {
+					t.Fatalf("--output %s printed %q: %v", format, out, err)
+				}
+				outputs[format] = v
+			}
+
+			if !reflect.DeepEqual(outputs[outputJSON], outputs[outputYAML]) {
+				t.Errorf("json got %v and yaml got %v, want the same fields", outputs[outputJSON], outputs[outputYAML])
+			}
+			if tt.want != nil && !reflect.DeepEqual(outputs[outputJSON], tt.want) {
+				t.Errorf("json got %v, want %v", outputs[outputJSON], tt.want)
+			}
+
+			cmd := tt.cmd()
+			cmd.SetArgs([]string{"--output", "xml"})
+			if err := cmd.Execute(); err != ErrInvalidOutput {
+				t.Errorf("--output xml = %v, want ErrInvalidOutput", err)
+			}
+		})
+	}
+}
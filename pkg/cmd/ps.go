@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula/detach"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runlog"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	allFlag       = "all"
+	msgNoDetached = "No formula running in the background, start one with --detach"
+	descPsLong    = `List the formula runs started with --detach that are running in the background.
+
+The ID of a run is the ID of its run log, so rit logs ID --follow prints its output and
+rit stop ID stops it. With --all the finished runs are listed too, with their status.`
+	detachedExited = "exited"
+)
+
+type psCmd struct {
+	runs detach.Lister
+	logs runlog.Lister
+}
+
+// NewPsCmd creates the ps command, it lists the formula runs detached from the terminal
+func NewPsCmd(dl detach.Lister, ll runlog.Lister) *cobra.Command {
+	p := psCmd{dl, ll}
+
+	cmd := &cobra.Command{
+		Use:     "ps",
+		Short:   "List the formula runs started with --detach",
+		Long:    descPsLong,
+		Example: "rit ps\nrit ps --all --output json",
+		Args:    cobra.NoArgs,
+		RunE:    p.runFunc(),
+	}
+	cmd.Flags().BoolP(allFlag, "a", false, "List the finished runs too")
+	addOutputFlag(cmd, "runs")
+
+	return cmd
+}
+
+func (p psCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		output, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		runs, err := p.runs.List()
+		if err != nil {
+			return err
+		}
+		if !boolFlag(cmd, allFlag) {
+			running := []detach.Run{}
+			for _, r := range runs {
+				if r.Running {
+					running = append(running, r)
+				}
+			}
+			runs = running
+		}
+
+		if output != "" {
+			return printOutput(output, runs)
+		}
+		if len(runs) == 0 {
+			prompt.Info(msgNoDetached)
+			return nil
+		}
+
+		logs, err := p.logs.List()
+		if err != nil {
+			return err
+		}
+		entries := make(map[string]runlog.Entry, len(logs))
+		for _, e := range logs {
+			entries[e.ID] = e
+		}
+
+		table := uitable.New()
+		table.AddRow("ID", "COMMAND", "PID", "START", "STATUS")
+		for _, r := range runs {
+			table.AddRow(r.ID, r.Command, strconv.Itoa(r.PID), r.Start.Format(runLogTimeFmt), detachedStatus(r, entries))
+		}
+		fmt.Println(table)
+		return nil
+	}
+}
+
+// detachedStatus is the status of the run log of a finished run, a run that exited without
+// it, e.g. on a failed setup, has its messages on the output file of the run
+func detachedStatus(r detach.Run, entries map[string]runlog.Entry) string {
+	if r.Running {
+		return "running"
+	}
+	e, ok := entries[r.ID]
+	if !ok || e.Running() {
+		return prompt.Yellow(detachedExited)
+	}
+	return runLogStatus(e)
+}
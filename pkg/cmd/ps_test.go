@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula/detach"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runlog"
+)
+
+type detachedRunsMock struct {
+	runs    []detach.Run
+	stopped *string
+	err     error
+}
+
+func (m detachedRunsMock) List() ([]detach.Run, error) {
+	return m.runs, nil
+}
+
+func (m detachedRunsMock) Stop(id string) (detach.Run, error) {
+	*m.stopped = id
+	return detach.Run{ID: id, Command: "rit aws tunnel"}, m.err
+}
+
+func TestPsCmd(t *testing.T) {
+	start := time.Date(2020, 7, 20, 10, 0, 0, 0, time.UTC)
+	runs := []detach.Run{
+		{ID: "1-rit-aws-tunnel", Command: "rit aws tunnel", PID: 4242, Start: start, Running: true},
+		{ID: "2-rit-aws-watch", Command: "rit aws watch", PID: 4343, Start: start},
+		{ID: "3-rit-aws-setup", Command: "rit aws setup", PID: 4444, Start: start},
+	}
+	entries := []runlog.Entry{
+		{ID: "1-rit-aws-tunnel", Command: "rit aws tunnel", Start: start},
+		{ID: "2-rit-aws-watch", Command: "rit aws watch", Start: start, End: start, Error: "exit status 1"},
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		runs    []detach.Run
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "running runs",
+			runs:    runs,
+			want:    []string{"1-rit-aws-tunnel", "4242", "running"},
+			notWant: []string{"2-rit-aws-watch", "3-rit-aws-setup"},
+		},
+		{
+			name: "all runs",
+			args: []string{"--all"},
+			runs: runs,
+			want: []string{"1-rit-aws-tunnel", "2-rit-aws-watch", "failed", "3-rit-aws-setup", "exited"},
+		},
+		{
+			name:    "as json",
+			args:    []string{"--output", "json"},
+			runs:    runs,
+			want:    []string{`"id": "1-rit-aws-tunnel"`, `"pid": 4242`},
+			notWant: []string{"2-rit-aws-watch"},
+		},
+		{name: "no detached run", runs: runs[1:], want: []string{msgNoDetached}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewPsCmd(detachedRunsMock{runs: tt.runs}, &runLogsMock{entries: entries})
+			cmd.SetArgs(tt.args)
+
+			var err error
+			out := captureStdout(func() { err = cmd.Execute() })
+			if err != nil {
+				t.Fatalf("ps got error %v", err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(out, w) {
+					t.Errorf("ps printed %q, want %q", out, w)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(out, w) {
+					t.Errorf("ps printed %q, don't want %q", out, w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/config"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
+)
+
+func TestQuiet(t *testing.T) {
+	defer prompt.SetQuiet(false)
+
+	newRoot := func() *cobra.Command {
+		root := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+		root.PersistentFlags().Bool("stdin", false, "input by stdin")
+		root.PersistentFlags().BoolP(quietFlag, "q", false, "Suppress warnings and informational messages")
+		addConfirmFlags(root)
+
+		set, del, add, show := NewSetCmd(), NewDeleteCmd(), NewAddCmd(), NewShowCmd()
+		set.AddCommand(NewSetContextCmd(ctxFindSetterMock{}, inputTextMock{}, inputListMock{}),
+			NewSetConfigCmd(&configSetterMock{}, inputTextMock{}, inputListMock{}))
+		fr := ctxFindRemoverCustomMock{
+			find: func() (rcontext.ContextHolder, error) {
+				return rcontext.ContextHolder{Current: "dev", All: []string{"dev", "qa"}}, nil
+			},
+			remove: func(string) (rcontext.ContextHolder, error) { return rcontext.ContextHolder{}, nil },
+		}
+		del.AddCommand(NewDeleteContextCmd(fr, inputTrueMock{}, inputListMock{}))
+		add.AddCommand(NewAddRepoCmd(repoAdder{}, repoPlannerMock{}, inputTextMock{}, inputURLMock{}, inputIntMock{}, inputTrueMock{}))
+		show.AddCommand(NewShowContextCmd(ctxFinderMock{}))
+		root.AddCommand(set, del, add, show)
+
+		NewChain(logLevelMiddleware, colorMiddleware).Apply(root)
+		return root
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantQuiet bool
+	}{
+		{name: "set context", args: []string{"set", "context"}, wantQuiet: true},
+		{name: "set config", args: []string{"set", "config", config.PlainKey, "true"}, wantQuiet: true},
+		{name: "delete context", args: []string{"delete", "context", "-y"}, wantQuiet: true},
+		{name: "add repo", args: []string{"add", "repo"}, wantQuiet: true},
+		{name: "show context prints the context asked for", args: []string{"show", "context"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, quiet := range []bool{false, true} {
+				root := newRoot()
+				args := tt.args
+				if quiet {
+					args = append([]string{"--quiet"}, args...)
+				}
+				root.SetArgs(args)
+
+				var err error
+				out := captureStdout(func() { err = root.Execute() })
+				if err != nil {
+					t.Fatalf("%v = %v, want nil", args, err)
+				}
+
+				if silent := out == ""; silent != (quiet && tt.wantQuiet) {
+					t.Errorf("%v printed %q, want silent %v", args, out, quiet && tt.wantQuiet)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+const descRepoLong = `
+This command consists of multiple subcommands to manage the repositories.
+
+It can be used to lock a repository against the updates and unlock it, and
+to verify that its installed formulas weren't changed since they were downloaded.
+`
+
+// NewRepoCmd create a new repo instance
+func NewRepoCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "repo SUBCOMMAND",
+		Short: "Lock, unlock and verify repositories",
+		Long:  descRepoLong,
+	}
+}
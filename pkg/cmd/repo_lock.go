@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	msgRepoLocked   = "The %q repository is locked, rit update repo skips it unless --force is used"
+	msgRepoUnlocked = "The %q repository is unlocked"
+)
+
+// repoLockCmd type for repo lock and repo unlock commands
+type repoLockCmd struct {
+	formula.RepoLocker
+	locked bool
+}
+
+// NewRepoLockCmd creates a new cmd instance
+func NewRepoLockCmd(rl formula.RepoLocker, ls formula.RepoLister) *cobra.Command {
+	l := &repoLockCmd{rl, true}
+
+	return &cobra.Command{
+		Use:   "lock NAME",
+		Short: "Lock a repository against the updates",
+		Long: "Lock a repository so rit update repo skips it unless --force is used.\n" +
+			"The formulas of a locked repository are kept as they are until it is unlocked.",
+		Example: "rit repo lock commons",
+		Args:    cobra.ExactArgs(1),
+		RunE:    l.runFunc(),
+
+		ValidArgsFunction: completeRepos(ls),
+	}
+}
+
+// NewRepoUnlockCmd creates a new cmd instance
+func NewRepoUnlockCmd(rl formula.RepoLocker, ls formula.RepoLister) *cobra.Command {
+	l := &repoLockCmd{rl, false}
+
+	return &cobra.Command{
+		Use:     "unlock NAME",
+		Short:   "Unlock a repository locked by rit repo lock",
+		Example: "rit repo unlock commons",
+		Args:    cobra.ExactArgs(1),
+		RunE:    l.runFunc(),
+
+		ValidArgsFunction: completeRepos(ls),
+	}
+}
+
+func (l repoLockCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := l.Lock(args[0], l.locked); err != nil {
+			return err
+		}
+
+		msg := msgRepoUnlocked
+		if l.locked {
+			msg = msgRepoLocked
+		}
+		prompt.Success(fmt.Sprintf(msg, args[0]))
+		return nil
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestRepoLockCmd(t *testing.T) {
+	tests := []struct {
+		name       string
+		newCmd     func(formula.RepoLocker, formula.RepoLister) *cobra.Command
+		locker     repoLockerMock
+		args       []string
+		wantLocked bool
+		wantErr    bool
+	}{
+		{name: "lock", newCmd: NewRepoLockCmd, args: []string{"commons"}, wantLocked: true},
+		{name: "unlock", newCmd: NewRepoUnlockCmd, args: []string{"commons"}, wantLocked: false},
+		{
+			name:    "unknown repository",
+			newCmd:  NewRepoLockCmd,
+			locker:  repoLockerMock{err: errors.New("repository \"missing\" not found")},
+			args:    []string{"missing"},
+			wantErr: true,
+		},
+		{name: "repository name is required", newCmd: NewRepoUnlockCmd, args: []string{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locked := map[string]bool{}
+			tt.locker.locked = locked
+			cmd := tt.newCmd(tt.locker, repoListerMock{})
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s = %v, wantErr %v", cmd.Use, err, tt.wantErr)
+			}
+			if !tt.wantErr && locked["commons"] != tt.wantLocked {
+				t.Errorf("%s locked %v, want %v", cmd.Use, locked["commons"], tt.wantLocked)
+			}
+		})
+	}
+}
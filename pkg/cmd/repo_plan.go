@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	dryRunFlag      = "dry-run"
+	planJSONFlag    = "json"
+	msgDryRun       = "Dry run, nothing was changed:"
+	msgDryRunNoDiff = "  no formula changes"
+	msgDryRunLocked = "  locked, skipped without --force"
+
+	// RepoPlanSchemaVersion is the version of the --dry-run --json schema, it only
+	// changes when a field is removed or changes its meaning, adding fields keeps it
+	RepoPlanSchemaVersion = 1
+	planActionSkip        = "skip"
+	planActionNoop        = "noop"
+)
+
+var (
+	ErrPlanJSONNeedsDryRun = errors.New("--json is the --dry-run plan, use it with --dry-run")
+	ErrPlanJSONWithOutput  = errors.New("--json has its own schema, don't use it with --output")
+)
+
+// repoPlanDoc is the --dry-run --json plan, a stable schema for the reconcilers of
+// GitOps setups. Each action is add, update, delete, skip for a locked repository
+// or noop for an update that changes nothing.
+type repoPlanDoc struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	Actions       []repoAction `json:"actions"`
+}
+
+// repoAction is a repository change of the plan. The versions are the digests of the
+// trees, empty when there is none, and estimatedFiles is what the action writes on the
+// rit home: the tree and a bundle per formula, or what it removes.
+type repoAction struct {
+	Action         string `json:"action"`
+	Repo           string `json:"repo"`
+	FromVersion    string `json:"fromVersion"`
+	ToVersion      string `json:"toVersion"`
+	DownloadSize   int    `json:"downloadSize"`
+	EstimatedFiles int    `json:"estimatedFiles"`
+	Error          string `json:"error,omitempty"`
+}
+
+// addDryRunFlags adds the flags of the repo commands that only report their changes
+func addDryRunFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool(dryRunFlag, false, "Report what would change without changing it")
+	cmd.Flags().Bool(planJSONFlag, false, "Print the --dry-run plan as versioned JSON for the GitOps reconcilers, with nothing else on stdout")
+	addOutputFlag(cmd, "--dry-run report")
+}
+
+// dryRun tells whether the command only reports its changes, validating the output format
+func dryRun(cmd *cobra.Command) (bool, error) {
+	output, err := outputFormat(cmd)
+	if err != nil {
+		return false, err
+	}
+
+	dry := boolFlag(cmd, dryRunFlag)
+	if boolFlag(cmd, planJSONFlag) {
+		switch {
+		case !dry:
+			return false, ErrPlanJSONNeedsDryRun
+		case output != "":
+			return false, ErrPlanJSONWithOutput
+		}
+	}
+	return dry, nil
+}
+
+// printRepoPlans prints the plans of a --dry-run, as JSON or YAML with --output
+// or as the versioned plan with --json
+func printRepoPlans(cmd *cobra.Command, plans ...formula.RepoPlan) error {
+	if boolFlag(cmd, planJSONFlag) {
+		return printJSON(newRepoPlanDoc(plans))
+	}
+
+	if output, _ := outputFormat(cmd); output != "" {
+		return printOutput(output, plans)
+	}
+
+	prompt.Info(msgDryRun)
+	for _, p := range plans {
+		fmt.Println(repoPlanText(p))
+	}
+	return nil
+}
+
+func repoPlanText(p formula.RepoPlan) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s repo %q (%s)", p.Operation, p.Repo, p.TreePath))
+	if p.Error != "" {
+		sb.WriteString(fmt.Sprintf("\n  unable to reach the repository: %s", strings.TrimSpace(p.Error)))
+		return sb.String()
+	}
+	if p.Locked {
+		sb.WriteString("\n" + msgDryRunLocked)
+		return sb.String()
+	}
+
+	if p.Replaces {
+		sb.WriteString("\n  replaces the repository with the same name")
+	}
+	if p.DownloadSize > 0 {
+		sb.WriteString(fmt.Sprintf("\n  download: %s", byteSize(p.DownloadSize)))
+	}
+
+	changes := []struct {
+		title string
+		items []string
+	}{
+		{"formulas added", p.Added},
+		{"formulas removed", p.Removed},
+		{"formulas deprecated", p.Deprecated},
+		{"files removed", p.FilesRemoved},
+	}
+	changed := false
+	for _, c := range changes {
+		for _, item := range c.items {
+			sb.WriteString(fmt.Sprintf("\n  %s: %s", c.title, item))
+			changed = true
+		}
+	}
+	if !changed {
+		sb.WriteString("\n" + msgDryRunNoDiff)
+	}
+	return sb.String()
+}
+
+func newRepoPlanDoc(plans []formula.RepoPlan) repoPlanDoc {
+	doc := repoPlanDoc{SchemaVersion: RepoPlanSchemaVersion, Actions: make([]repoAction, 0, len(plans))}
+	for _, p := range plans {
+		a := repoAction{
+			Action:       p.Operation,
+			Repo:         p.Repo,
+			FromVersion:  p.FromVersion,
+			ToVersion:    p.ToVersion,
+			DownloadSize: p.DownloadSize,
+			Error:        strings.TrimSpace(p.Error),
+		}
+
+		switch {
+		case p.Locked:
+			a.Action = planActionSkip
+		case p.Operation == formula.RepoDelete:
+			a.EstimatedFiles = len(p.FilesRemoved)
+		case p.Error == "":
+			a.EstimatedFiles = 1 + p.Formulas
+			if p.Operation == formula.RepoUpdate && p.FromVersion == p.ToVersion {
+				a.Action = planActionNoop
+				a.EstimatedFiles = 0
+			}
+		}
+		doc.Actions = append(doc.Actions, a)
+	}
+	return doc
+}
+
+func byteSize(b int) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := unit, 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "KMGT"[exp])
+}
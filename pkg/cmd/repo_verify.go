@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	allReposFlag    = "all"
+	msgRepoVerified = "The formulas of the %q repository match the installed ones"
+	msgRepoCorrupt  = "%d formula files differ from the installed ones"
+)
+
+var ErrRepoVerifyArgs = errors.New("inform a repository name or --all")
+
+// verifyIssues are the issues printed by rit repo verify for each file change
+var verifyIssues = map[string]string{
+	formula.FileAdded:    "extra",
+	formula.FileModified: "mismatch",
+	formula.FileDeleted:  "missing",
+}
+
+// repoIntegrity is a verified repository with the files that differ from the installed ones
+type repoIntegrity struct {
+	Repo    string               `json:"repo"`
+	Changes []formula.FileChange `json:"changes"`
+}
+
+// repoVerifyCmd type for repo verify command
+type repoVerifyCmd struct {
+	formula.RepoDiffer
+	formula.RepoLister
+}
+
+// NewRepoVerifyCmd creates a new cmd instance
+func NewRepoVerifyCmd(rd formula.RepoDiffer, rl formula.RepoLister) *cobra.Command {
+	v := &repoVerifyCmd{rd, rl}
+
+	cmd := &cobra.Command{
+		Use:   "verify [NAME]",
+		Short: "Check the installed formulas of the repositories against their checksums",
+		Long: "Recompute the checksums of the formula files of a repository, or of all of them with --all, and\n" +
+			"report the files that don't match (mismatch), were removed (missing) or were added (extra) since\n" +
+			"they were installed. It exits with an error on any difference, e.g. to run as a scheduled check.\n" +
+			"Only the formulas downloaded by a run are verified, their checksums are saved when they are downloaded.",
+		Example: "rit repo verify commons\nrit repo verify --all",
+		Args:    cobra.MaximumNArgs(1),
+		RunE:    v.runFunc(),
+
+		ValidArgsFunction: completeRepos(rl),
+	}
+	cmd.Flags().Bool(allReposFlag, false, "Verify all the repositories")
+	addOutputFlag(cmd, "report")
+
+	return cmd
+}
+
+func (v repoVerifyCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		output, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		all := boolFlag(cmd, allReposFlag)
+		if all == (len(args) == 1) {
+			return ErrRepoVerifyArgs
+		}
+
+		names := args
+		if all {
+			repos, err := v.List()
+			if err != nil {
+				return err
+			}
+			names = rNameList(repos)
+		}
+
+		report := make([]repoIntegrity, 0, len(names))
+		issues := 0
+		for _, name := range names {
+			changes, err := v.Diff(name)
+			if err != nil {
+				return err
+			}
+			if changes == nil {
+				changes = []formula.FileChange{}
+			}
+			report = append(report, repoIntegrity{Repo: name, Changes: changes})
+			issues += len(changes)
+		}
+
+		if output != "" {
+			if err := printOutput(output, report); err != nil {
+				return err
+			}
+		} else {
+			printRepoIntegrity(report)
+		}
+
+		if issues > 0 {
+			return fmt.Errorf(msgRepoCorrupt, issues)
+		}
+		return nil
+	}
+}
+
+func printRepoIntegrity(report []repoIntegrity) {
+	for _, r := range report {
+		if len(r.Changes) == 0 {
+			prompt.Success(fmt.Sprintf(msgRepoVerified, r.Repo))
+			continue
+		}
+		for _, c := range r.Changes {
+			fmt.Printf("%s %s: %s\n", r.Repo, verifyIssues[c.Status], c.Path)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+// repoDifferByNameMock returns the changes of each repository by its name
+type repoDifferByNameMock map[string][]formula.FileChange
+
+func (m repoDifferByNameMock) Diff(name string) ([]formula.FileChange, error) {
+	changes, ok := m[name]
+	if !ok {
+		return nil, errors.New("repository not found")
+	}
+	return changes, nil
+}
+
+func TestNewRepoVerifyCmd(t *testing.T) {
+	differ := repoDifferByNameMock{
+		"commons": nil,
+		"team": {
+			{Status: formula.FileAdded, Path: "aws/create/bin/new.sh"},
+			{Status: formula.FileModified, Path: "aws/create/config.json"},
+			{Status: formula.FileDeleted, Path: "aws/create/README.md"},
+		},
+	}
+	lister := repoListerCustomMock{repos: []formula.Repository{{Name: "commons"}, {Name: "team"}}}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "intact repository",
+			args: []string{"commons"},
+		},
+		{
+			name: "all the repositories",
+			args: []string{"--all"},
+			want: "team extra: aws/create/bin/new.sh\n" +
+				"team mismatch: aws/create/config.json\n" +
+				"team missing: aws/create/README.md\n",
+			wantErr: "3 formula files differ from the installed ones",
+		},
+		{
+			name:    "JSON report",
+			args:    []string{"team", "--output", "json"},
+			want:    `"status": "modified"`,
+			wantErr: "3 formula files differ from the installed ones",
+		},
+		{
+			name:    "unknown repository",
+			args:    []string{"missing"},
+			wantErr: "repository not found",
+		},
+		{
+			name:    "no repository",
+			wantErr: ErrRepoVerifyArgs.Error(),
+		},
+		{
+			name:    "name and --all",
+			args:    []string{"commons", "--all"},
+			wantErr: ErrRepoVerifyArgs.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewRepoVerifyCmd(differ, lister)
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			cmd.SetArgs(tt.args)
+
+			var err error
+			out := captureStdout(func() { err = cmd.Execute() })
+			if (err == nil) != (tt.wantErr == "") || (err != nil && err.Error() != tt.wantErr) {
+				t.Fatalf("%s = %v, want %q", cmd.Use, err, tt.wantErr)
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("%s printed %q, want %q", cmd.Use, out, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula/history"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runner"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/redact"
+)
+
+const (
+	msgRerun       = "Running %s again as on %s"
+	msgRerunPrompt = "%s isn't recorded, it is asked again"
+	descRerunLong  = `Run a formula again as a previous run of the history, the last one by default.
+
+The run is chosen by its position from the newest, 1 is the last run, or by its ID on
+rit history. The formula gets the args and the text and bool inputs of that run. The
+secret inputs, never recorded, and the inputs the formula didn't have then are asked
+again. The flags of the run, e.g. --docker, aren't recorded, pass them after --.`
+	exampleRerun = `rit rerun
+rit rerun 3
+rit rerun 20200720T100000.000000 -- --docker`
+)
+
+var (
+	ErrRerunNotFound   = errors.New("run not found on the history, list the runs with rit history")
+	ErrRerunMaskedArgs = errors.New("the args of the run had secrets, which aren't recorded, run the formula with its args instead")
+)
+
+// rerunRun runs the formula again on a child rit process, with the env vars informing its
+// inputs and the terminal of rit. It is a var so the reruns can be replaced on tests.
+var rerunRun = func(args, env []string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	c := exec.Command(exe, args...)
+	c.Env = append(os.Environ(), env...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+type rerunCmd struct {
+	history.Lister
+}
+
+// NewRerunCmd creates the rerun command, it runs a formula again as a run of the history
+func NewRerunCmd(l history.Lister) *cobra.Command {
+	r := rerunCmd{l}
+
+	return &cobra.Command{
+		Use:     "rerun [N|ID] [-- FLAGS]",
+		Short:   "Run a formula again with the inputs of a previous run",
+		Long:    descRerunLong,
+		Example: exampleRerun,
+		Args:    cobra.ArbitraryArgs,
+		RunE:    r.runFunc(),
+	}
+}
+
+func (r rerunCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		flags := passthroughArgs(cmd, args)
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			args = args[:dash]
+		}
+		if len(args) > 1 {
+			return fmt.Errorf("accepts at most 1 run, received %d", len(args))
+		}
+
+		ee, err := r.List()
+		if err != nil {
+			return err
+		} else if len(ee) == 0 {
+			return ErrNoHistory
+		}
+
+		e, err := selectRun(ee, args)
+		if err != nil {
+			return err
+		}
+
+		formulaArgs, err := rerunArgs(e, flags)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := runnableFormulas(cmd.Root())[e.Command]; !ok {
+			return fmt.Errorf("%w: %s", ErrFormulaPathNotFound, e.Command)
+		}
+
+		prompt.Info(fmt.Sprintf(msgRerun, e.Command, e.Start.Local().Format(runLogTimeFmt)))
+		return rerunRun(formulaArgs, rerunEnv(e))
+	}
+}
+
+// selectRun returns the run at the position from the newest, or with the ID, of the args,
+// the last run without args
+func selectRun(ee []history.Entry, args []string) (history.Entry, error) {
+	if len(args) == 0 {
+		return ee[len(ee)-1], nil
+	}
+
+	if n, err := strconv.Atoi(args[0]); err == nil {
+		if n < 1 || n > len(ee) {
+			return history.Entry{}, ErrRerunNotFound
+		}
+		return ee[len(ee)-n], nil
+	}
+
+	for _, e := range ee {
+		if e.ID == args[0] {
+			return e, nil
+		}
+	}
+	return history.Entry{}, ErrRerunNotFound
+}
+
+// rerunArgs returns the args of the formula run, its command path followed by the flags
+// and its recorded args after --. The masked args can't be replayed.
+func rerunArgs(e history.Entry, flags []string) ([]string, error) {
+	args := strings.Fields(strings.TrimPrefix(e.Command, cmdUse+" "))
+	args = append(args, flags...)
+	if len(e.Args) == 0 {
+		return args, nil
+	}
+
+	for _, a := range e.Args {
+		if strings.Contains(a, redact.Mask) {
+			return nil, ErrRerunMaskedArgs
+		}
+	}
+	return append(append(args, "--"), e.Args...), nil
+}
+
+// rerunEnv returns the env vars informing the recorded inputs to the formula, see
+// runner.InputEnvPrefix. The masked inputs are left to be asked again.
+func rerunEnv(e history.Entry) []string {
+	names := make([]string, 0, len(e.Inputs))
+	for n := range e.Inputs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	env := make([]string, 0, len(names))
+	for _, n := range names {
+		v := e.Inputs[n]
+		if strings.Contains(v, redact.Mask) {
+			prompt.Warning(fmt.Sprintf(msgRerunPrompt, n))
+			continue
+		}
+		env = append(env, runner.InputEnvName(n)+"="+v)
+	}
+	return env
+}
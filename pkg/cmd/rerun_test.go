@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/history"
+	"github.com/ZupIT/ritchie-cli/pkg/redact"
+)
+
+func TestRerunCmd(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "aws", Help: "aws formulas"},
+				{Parent: "root_aws", Usage: "create", Help: "create formula", Formula: &api.Formula{Path: "aws/create"}},
+				{Parent: "root_aws", Usage: "delete", Help: "delete formula", Formula: &api.Formula{Path: "aws/delete"}},
+			},
+		},
+	}
+	start := time.Date(2020, 7, 20, 10, 0, 0, 0, time.UTC)
+	entries := []history.Entry{
+		{
+			ID:      "20200720T100000.000000",
+			Command: "rit aws create",
+			Args:    []string{"--dry-run"},
+			Inputs:  map[string]string{"region": "sa-east-1", "my-name": "bucket", "token": redact.Mask},
+			Start:   start,
+		},
+		{ID: "20200720T100100.000000", Command: "rit aws delete", Start: start.Add(time.Minute)},
+		{ID: "20200720T100200.000000", Command: "rit aws update", Start: start.Add(2 * time.Minute)},
+		{ID: "20200720T100300.000000", Command: "rit aws delete", Args: []string{"--password", redact.Mask}, Start: start.Add(3 * time.Minute)},
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		entries  []history.Entry
+		wantArgs []string
+		wantEnv  []string
+		wantErr  error
+	}{
+		{
+			name:     "by position",
+			args:     []string{"4"},
+			entries:  entries,
+			wantArgs: []string{"aws", "create", "--", "--dry-run"},
+			wantEnv:  []string{"RIT_INPUT_MY_NAME=bucket", "RIT_INPUT_REGION=sa-east-1"},
+		},
+		{
+			name:     "by id with flags",
+			args:     []string{"20200720T100100.000000", "--", "--docker"},
+			entries:  entries,
+			wantArgs: []string{"aws", "delete", "--docker"},
+			wantEnv:  []string{},
+		},
+		{name: "last run with masked args", entries: entries, wantErr: ErrRerunMaskedArgs},
+		{name: "formula removed", args: []string{"2"}, entries: entries, wantErr: ErrFormulaPathNotFound},
+		{name: "position out of the history", args: []string{"5"}, entries: entries, wantErr: ErrRerunNotFound},
+		{name: "unknown id", args: []string{"20200720T000000.000000"}, entries: entries, wantErr: ErrRerunNotFound},
+		{name: "empty history", wantErr: ErrNoHistory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotArgs, gotEnv []string
+			oldRerunRun := rerunRun
+			defer func() { rerunRun = oldRerunRun }()
+			rerunRun = func(args, env []string) error {
+				gotArgs, gotEnv = args, env
+				return nil
+			}
+
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, runnerMock{}, runnerMock{}, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.AddCommand(NewRerunCmd(historyListerMock{entries: tt.entries}))
+			rootCmd.SetArgs(append([]string{"rerun"}, tt.args...))
+
+			if err := rootCmd.Execute(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("rit rerun %v = %v, want %v", tt.args, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) || !reflect.DeepEqual(gotEnv, tt.wantEnv) {
+				t.Errorf("rit rerun %v ran %q with env %q, want %q with env %q", tt.args, gotArgs, gotEnv, tt.wantArgs, tt.wantEnv)
+			}
+		})
+	}
+}
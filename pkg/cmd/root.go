@@ -2,20 +2,12 @@ package cmd
 
 import (
 	"fmt"
-	"net/http"
-	"os"
-	"runtime"
 	"strings"
-	"time"
 
 	"github.com/ZupIT/ritchie-cli/pkg/api"
-	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
 	"github.com/ZupIT/ritchie-cli/pkg/prompt"
-	"github.com/ZupIT/ritchie-cli/pkg/server"
-	"github.com/ZupIT/ritchie-cli/pkg/session"
 	"github.com/ZupIT/ritchie-cli/pkg/slice/sliceutil"
 	"github.com/ZupIT/ritchie-cli/pkg/version"
-	"github.com/ZupIT/ritchie-cli/pkg/workspace"
 
 	"github.com/spf13/cobra"
 )
@@ -24,6 +16,7 @@ const (
 	latestVersionMsg            = "Latest available version: %s"
 	versionMsg                  = "%s (%s)\n  Build date: %s\n  Built with: %s\n"
 	versionMsgWithLatestVersion = "%s (%s)\n  %s\n  Build date: %s\n  Built with: %s\n"
+	versionTemplateFunc         = "ritVersion"
 	cmdUse                      = "rit"
 	cmdShortDescription         = "rit is a NoOps CLI"
 	cmdDescription              = `A CLI that developers can build and operate
@@ -54,6 +47,8 @@ var (
 		fmt.Sprintf("%s completion powershell", cmdUse),
 		fmt.Sprintf("%s init", cmdUse),
 		fmt.Sprintf("%s upgrade", cmdUse),
+		fmt.Sprintf("%s version", cmdUse),
+		fmt.Sprintf("%s self-test", cmdUse),
 	}
 
 	teamIgnorelist = []string{
@@ -67,6 +62,8 @@ var (
 		fmt.Sprintf("%s completion powershell", cmdUse),
 		fmt.Sprintf("%s init", cmdUse),
 		fmt.Sprintf("%s upgrade", cmdUse),
+		fmt.Sprintf("%s version", cmdUse),
+		fmt.Sprintf("%s self-test", cmdUse),
 	}
 
 	upgradeValidationWhiteList = []string{
@@ -75,132 +72,57 @@ var (
 	}
 )
 
-type singleRootCmd struct {
-	workspaceChecker workspace.Checker
-	sessionValidator session.Validator
-}
-
-type teamRootCmd struct {
-	workspaceChecker workspace.Checker
-	serverFinder     server.Finder
-	sessionValidator session.Validator
-}
-
-// NewSingleRootCmd creates the root command for single edition.
-func NewSingleRootCmd(wc workspace.Checker, sv session.Validator) *cobra.Command {
-	o := &singleRootCmd{
-		workspaceChecker: wc,
-		sessionValidator: sv,
-	}
-
+// NewSingleRootCmd creates the root command for single edition,
+// its commands are checked by the middleware of NewSingleChain.
+func NewSingleRootCmd(vr version.Resolver) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:                cmdUse,
-		Version:            versionFlag(api.Single),
-		Short:              cmdShortDescription,
-		Long:               cmdDescription,
-		PersistentPreRunE:  o.PreRunFunc(),
-		PersistentPostRunE: o.PostRunFunc(),
-		RunE:               runHelp,
-		SilenceErrors:      true,
-		TraverseChildren:   true,
+		Use:              cmdUse,
+		Version:          Version,
+		Short:            cmdShortDescription,
+		Long:             cmdDescription,
+		RunE:             runHelp,
+		SilenceErrors:    true,
+		TraverseChildren: true,
 	}
 	cmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+	cmd.PersistentFlags().BoolP(quietFlag, "q", false, "Print only the errors and the output asked for, e.g. lists and JSON")
+	addConfirmFlags(cmd)
+	addColorFlag(cmd)
+	addLogLevelFlag(cmd)
+	addHomeFlag(cmd)
+	addTLSFlags(cmd)
+	addNetworkFlags(cmd)
+	setVersionTemplate(cmd, api.Single, vr)
 
 	return cmd
 }
 
-// NewTeamRootCmd creates the root command for team edition.
-func NewTeamRootCmd(wc workspace.Checker,
-	sf server.Finder,
-	sv session.Validator) *cobra.Command {
-	o := &teamRootCmd{
-		workspaceChecker: wc,
-		serverFinder:     sf,
-		sessionValidator: sv,
-	}
-
+// NewTeamRootCmd creates the root command for team edition,
+// its commands are checked by the middleware of NewTeamChain.
+func NewTeamRootCmd(vr version.Resolver) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:                cmdUse,
-		Version:            versionFlag(api.Team),
-		Short:              cmdShortDescription,
-		Long:               cmdDescription,
-		PersistentPreRunE:  o.PreRunFunc(),
-		PersistentPostRunE: o.PostRunFunc(),
-		RunE:               runHelp,
-		SilenceErrors:      true,
+		Use:           cmdUse,
+		Version:       Version,
+		Short:         cmdShortDescription,
+		Long:          cmdDescription,
+		RunE:          runHelp,
+		SilenceErrors: true,
 	}
 	cmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+	cmd.PersistentFlags().BoolP(quietFlag, "q", false, "Print only the errors and the output asked for, e.g. lists and JSON")
+	addConfirmFlags(cmd)
+	addColorFlag(cmd)
+	addLogLevelFlag(cmd)
+	addHomeFlag(cmd)
+	addTLSFlags(cmd)
+	addNetworkFlags(cmd)
+	setVersionTemplate(cmd, api.Team, vr)
 	return cmd
 }
 
-func (o *singleRootCmd) PreRunFunc() CommandRunnerFunc {
-	return func(cmd *cobra.Command, args []string) error {
-		if err := o.workspaceChecker.Check(); err != nil {
-			return err
-		}
-
-		if isWhitelist(singleIgnorelist, cmd) || isCompleteCmd(cmd) {
-			return nil
-		}
-
-		if err := o.sessionValidator.Validate(); err != nil {
-			fmt.Println(MsgInit)
-			os.Exit(0)
-		}
-
-		return nil
-	}
-}
-
-func (o *teamRootCmd) PreRunFunc() CommandRunnerFunc {
-	return func(cmd *cobra.Command, args []string) error {
-		if err := o.workspaceChecker.Check(); err != nil {
-			return err
-		}
-
-		if isWhitelist(teamIgnorelist, cmd) || isCompleteCmd(cmd) {
-			return nil
-		}
-
-		cfg, err := o.serverFinder.Find()
-		if err != nil {
-			return err
-		} else if cfg.URL == "" {
-			fmt.Println(MsgInit)
-			os.Exit(0)
-		}
-
-		if err := o.sessionValidator.Validate(); err != nil {
-			fmt.Println(MsgSession)
-			os.Exit(0)
-		}
-
-		return nil
-	}
-}
-
-func (o *singleRootCmd) PostRunFunc() CommandRunnerFunc {
-	return func(cmd *cobra.Command, args []string) error {
-		verifyNewVersion(cmd)
-		return nil
-	}
-}
-
-func (o *teamRootCmd) PostRunFunc() CommandRunnerFunc {
-	return func(cmd *cobra.Command, args []string) error {
-		verifyNewVersion(cmd)
-		return nil
-	}
-}
-
-func verifyNewVersion(cmd *cobra.Command) {
+func verifyNewVersion(cmd *cobra.Command, vr version.Resolver) {
 	if isWhitelist(upgradeValidationWhiteList, cmd) {
-		resolver := version.DefaultVersionResolver{
-			StableVersionUrl: StableVersionUrl,
-			FileUtilService:  fileutil.DefaultService{},
-			HttpClient:       &http.Client{Timeout: 1 * time.Second},
-		}
-		prompt.Warning(version.VerifyNewVersion(resolver, Version))
+		prompt.Warning(version.VerifyNewVersion(vr, Version))
 	}
 }
 
@@ -212,18 +134,11 @@ func isCompleteCmd(cmd *cobra.Command) bool {
 	return strings.Contains(cmd.CommandPath(), "__complete")
 }
 
-func versionFlag(edition api.Edition) string {
-	resolver := version.DefaultVersionResolver{
-		StableVersionUrl: StableVersionUrl,
-		FileUtilService:  fileutil.DefaultService{},
-		HttpClient:       &http.Client{Timeout: 1 * time.Second},
-	}
-	latestVersion, err := resolver.StableVersion()
-	if err == nil && latestVersion != Version {
-		formattedLatestVersionMsg := prompt.Yellow(fmt.Sprintf(latestVersionMsg, latestVersion))
-		return fmt.Sprintf(versionMsgWithLatestVersion, Version, edition, formattedLatestVersionMsg, BuildDate, runtime.Version())
-	}
-	return fmt.Sprintf(versionMsg, Version, edition, BuildDate, runtime.Version())
+// setVersionTemplate resolves the --version message only when the flag is used,
+// so the other commands don't wait for the latest stable version
+func setVersionTemplate(cmd *cobra.Command, edition api.Edition, vr version.Resolver) {
+	cobra.AddTemplateFunc(versionTemplateFunc, func() string { return versionMessage(edition, vr.StableVersion) })
+	cmd.SetVersionTemplate(fmt.Sprintf("{{%s}}", versionTemplateFunc))
 }
 
 func runHelp(cmd *cobra.Command, args []string) error {
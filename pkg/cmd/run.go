@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	// FormulaAnnotation marks the cobra commands that execute a formula
+	FormulaAnnotation     = "formula"
+	interactiveSelectFlag = "interactive-select"
+	msgChooseFormula      = "Choose a formula to run:"
+)
+
+var (
+	ErrNoFormulas          = errors.New("there are no formulas to run, add a repository with: rit add repo")
+	ErrNonInteractiveRun   = errors.New("rit run needs a formula path when the terminal is not interactive, e.g. rit run aws create")
+	ErrFormulaPathNotFound = errors.New("formula not found, use rit run without arguments to choose one from the list")
+)
+
+// runCmd type for run command
+type runCmd struct {
+	prompt.InputList
+	isTerminal func() bool
+}
+
+// NewRunCmd creates the run command, it runs the formula informed by its path
+// or shows a menu with all the runnable formulas
+func NewRunCmd(il prompt.InputList) *cobra.Command {
+	r := runCmd{
+		InputList: il,
+		isTerminal: func() bool {
+			return isatty.IsTerminal(os.Stdin.Fd())
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "run [FORMULA PATH]",
+		Short:   "Run a formula",
+		Long:    "Run a formula by its path, or choose it from a filterable menu when no path is informed.\n\nThe text, bool and password inputs can be informed by the RIT_INPUT_<NAME> env vars, with the input name upper cased and the chars other than letters and digits replaced by _. They aren't prompted and the --stdin inputs take precedence over them.\n\nThe local and docker formulas get the whole environment of rit, so the host env vars, e.g. AWS_PROFILE, reach them without being listed. The --ssh and --kubernetes formulas get the host env vars matching --env-passthrough, e.g. --env-passthrough \"AWS_*\", the RIT_ vars are never passed through and --env-file and the inputs replace them.",
+		Example: "rit run\nRIT_INPUT_REGION=sa-east-1 rit run aws create\nrit run aws create\nrit run aws create -- --dry-run\nrit run aws create --ssh build-host --env-passthrough \"AWS_*\"\nrit run aws deploy --on-failure \"aws rollback\"\nrit run aws deploy --max-retries 3 --retry-backoff 10s --retry-on 75\necho '{\"region\":\"sa-east-1\"}' | rit run aws create --stdin --count 10 --parallelism 4\nrit run batch -f plan.yaml\nrit run pipeline -f pipe.yaml",
+		RunE:    r.runFunc(),
+	}
+	cmd.Flags().Bool(interactiveSelectFlag, false, "Choose the formula to run from a menu")
+	addRunFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (r runCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		formulas := runnableFormulas(cmd.Root())
+		selectMenu, err := cmd.Flags().GetBool(interactiveSelectFlag)
+		if err != nil {
+			return err
+		}
+
+		formulaArgs := passthroughArgs(cmd, args)
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			args = args[:dash]
+		}
+
+		var path string
+		if len(args) > 0 && !selectMenu {
+			path = strings.Join(append([]string{cmd.Root().Name()}, args...), " ")
+		} else {
+			if !r.isTerminal() {
+				return ErrNonInteractiveRun
+			}
+			if len(formulas) == 0 {
+				return ErrNoFormulas
+			}
+
+			paths := make([]string, 0, len(formulas))
+			for p := range formulas {
+				paths = append(paths, p)
+			}
+			sort.Strings(paths)
+
+			if path, err = r.List(msgChooseFormula, paths); err != nil {
+				return err
+			}
+		}
+
+		formulaCmd, ok := formulas[path]
+		if !ok {
+			return ErrFormulaPathNotFound
+		}
+
+		// the dash keeps the args after it apart from the formula path, as on rit aws create -- args,
+		// the formula command parses the run flags as its own
+		formulaFlags := append(changedRunFlags(cmd), "--")
+		if err := formulaCmd.ParseFlags(append(formulaFlags, formulaArgs...)); err != nil {
+			return err
+		}
+		return formulaCmd.RunE(formulaCmd, formulaCmd.Flags().Args())
+	}
+}
+
+// changedRunFlags returns the run flags informed to rit run, to be parsed by the formula command
+func changedRunFlags(cmd *cobra.Command) []string {
+	var flags []string
+	local := cmd.LocalNonPersistentFlags()
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if f.Name == interactiveSelectFlag || local.Lookup(f.Name) == nil {
+			return
+		}
+
+		flags = append(flags, flagArgs(f)...)
+	})
+	return flags
+}
+
+// flagArgs returns the args setting the flag to its value, a slice flag
+// is repeated for each value as a string array value may have commas
+func flagArgs(f *pflag.Flag) []string {
+	s, ok := f.Value.(pflag.SliceValue)
+	if !ok {
+		return []string{"--" + f.Name + "=" + f.Value.String()}
+	}
+
+	args := make([]string, 0, len(s.GetSlice()))
+	for _, v := range s.GetSlice() {
+		args = append(args, "--"+f.Name+"="+v)
+	}
+	return args
+}
+
+// runnableFormulas walks the command tree and returns the formula commands by command path
+func runnableFormulas(root *cobra.Command) map[string]*cobra.Command {
+	formulas := make(map[string]*cobra.Command)
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		for _, child := range c.Commands() {
+			if _, ok := child.Annotations[FormulaAnnotation]; ok && child.RunE != nil {
+				formulas[child.CommandPath()] = child
+			}
+			walk(child)
+		}
+	}
+	walk(root)
+	return formulas
+}
@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	planFlag          = "file"
+	msgBatchSucceeded = "%s succeeded in %v"
+	msgBatchFailed    = "%s failed with exit code %d in %v"
+	msgBatchSummary   = "%d formulas: %d succeeded, %d failed, total %v"
+	msgBatchFailures  = "%d of %d formulas failed: %w"
+	descRunBatchLong  = `Run the formulas of a plan file with their preset inputs, up to --parallelism at a time,
+and print the summary of the runs. Each formula runs on a child rit process with its inputs
+on --stdin, so it never prompts. The first failure is returned, so rit exits with its code.
+
+The plan is a YAML or JSON file:
+
+  parallelism: 4
+  formulas:
+    - name: bucket
+      formula: aws create bucket
+      inputs:
+        region: sa-east-1
+      flags: ["--docker"]
+      args: ["--dry-run"]
+
+The formula is its command path without rit, the flags are the ones of the formula
+command and the args are passed after --. The name defaults to the formula path.`
+)
+
+var (
+	ErrNoPlan    = errors.New("inform the plan file with --file")
+	ErrEmptyPlan = errors.New("the plan has no formulas to run")
+)
+
+// batchPlan is the plan file of rit run batch
+type batchPlan struct {
+	Parallelism int         `json:"parallelism,omitempty"`
+	Formulas    []batchStep `json:"formulas"`
+}
+
+// batchStep is a formula of the plan with its preset inputs, the flags of its command and its args
+type batchStep struct {
+	Name    string                 `json:"name,omitempty"`
+	Formula string                 `json:"formula"`
+	Inputs  map[string]interface{} `json:"inputs,omitempty"`
+	Flags   []string               `json:"flags,omitempty"`
+	Args    []string               `json:"args,omitempty"`
+}
+
+// batchResult is the outcome of a formula of the plan, as printed by --output
+type batchResult struct {
+	Name     string  `json:"name"`
+	Formula  string  `json:"formula"`
+	ExitCode int     `json:"exitCode"`
+	Duration float64 `json:"duration"`
+	Error    string  `json:"error,omitempty"`
+	err      error
+	index    int
+}
+
+// NewRunBatchCmd creates the run batch command, it runs the formulas of a plan file concurrently
+func NewRunBatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "batch",
+		Short:   "Run the formulas of a plan file concurrently",
+		Long:    descRunBatchLong,
+		Example: "rit run batch -f plan.yaml\nrit run batch -f plan.yaml --parallelism 8 --output json",
+		Args:    cobra.NoArgs,
+		RunE:    runBatchFunc(),
+	}
+	cmd.Flags().StringP(planFlag, "f", "", "Plan file with the formulas to run and their inputs")
+	cmd.Flags().Int(parallelismFlag, 1, "How many formulas run at the same time, overrides the parallelism of the plan")
+	cmd.Flags().Bool(timestampsFlag, false, "Prefix each line of the formulas output with the time and the formula command, as --timestamps of each formula")
+	addOutputFlag(cmd, "summary")
+
+	return cmd
+}
+
+func runBatchFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		file, err := cmd.Flags().GetString(planFlag)
+		if err != nil {
+			return err
+		} else if file == "" {
+			return ErrNoPlan
+		}
+
+		output, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		plan, err := readPlan(file)
+		if err != nil {
+			return err
+		}
+
+		formulas := runnableFormulas(cmd.Root())
+		for i, s := range plan.Formulas {
+			if _, ok := formulas[cmdUse+" "+s.Formula]; !ok {
+				return fmt.Errorf("formula %d of the plan, %q: %w", i+1, s.Formula, ErrFormulaPathNotFound)
+			}
+		}
+
+		parallelism := plan.Parallelism
+		if cmd.Flags().Changed(parallelismFlag) || parallelism == 0 {
+			if parallelism, err = cmd.Flags().GetInt(parallelismFlag); err != nil {
+				return err
+			}
+		}
+		if parallelism < 1 {
+			return ErrInvalidParallelism
+		}
+
+		if boolFlag(cmd, timestampsFlag) {
+			for i := range plan.Formulas {
+				plan.Formulas[i].Flags = append(plan.Formulas[i].Flags, "--"+timestampsFlag)
+			}
+		}
+
+		results, err := runBatch(plan, parallelism, output == "")
+		if err != nil {
+			return err
+		}
+
+		if output != "" {
+			if err := printOutput(output, results); err != nil {
+				return err
+			}
+		} else {
+			printBatchResults(results)
+		}
+		return batchError(results)
+	}
+}
+
+// readPlan reads the YAML or JSON plan, the name of a formula defaults to its path
+func readPlan(file string) (batchPlan, error) {
+	b, err := fileutil.ReadFile(file)
+	if err != nil {
+		return batchPlan{}, err
+	}
+
+	var plan batchPlan
+	if err := yaml.UnmarshalStrict(b, &plan); err != nil {
+		return batchPlan{}, fmt.Errorf("invalid plan %s: %w", file, err)
+	}
+	if len(plan.Formulas) == 0 {
+		return batchPlan{}, ErrEmptyPlan
+	}
+
+	for i, s := range plan.Formulas {
+		s.Formula = strings.Join(strings.Fields(strings.TrimPrefix(strings.TrimSpace(s.Formula), cmdUse+" ")), " ")
+		if s.Formula == "" {
+			return batchPlan{}, fmt.Errorf("formula %d of the plan has no formula path", i+1)
+		}
+		if s.Name == "" {
+			s.Name = s.Formula
+		}
+		plan.Formulas[i] = s
+	}
+	return plan, nil
+}
+
+// runBatch runs the formulas of the plan, up to parallelism at a time, on child rit
+// processes with their inputs on stdin. The results are in the order of the plan.
+// Without the report, the stdout of rit is the --output of the results, so the formulas
+// write their stdout on stderr.
+func runBatch(plan batchPlan, parallelism int, report bool) ([]batchResult, error) {
+	var stdout io.Writer = os.Stdout
+	if !report {
+		stdout = os.Stderr
+	}
+
+	stdins := make([][]byte, len(plan.Formulas))
+	for i, s := range plan.Formulas {
+		inputs := s.Inputs
+		if inputs == nil {
+			inputs = map[string]interface{}{}
+		}
+		b, err := json.Marshal(inputs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid inputs of %s: %w", s.Name, err)
+		}
+		stdins[i] = b
+	}
+
+	results := make([]batchResult, len(plan.Formulas))
+	done := make(chan batchResult)
+	slots := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, s := range plan.Formulas {
+		wg.Add(1)
+		go func(i int, s batchStep) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			start := time.Now()
+			err := countRun(batchRunArgs(s), nil, stdins[i], stdout)
+			r := batchResult{Name: s.Name, Formula: s.Formula, Duration: time.Since(start).Seconds(), err: err, index: i}
+			if err != nil {
+				r.ExitCode = ExitCode(err)
+				r.Error = err.Error()
+			}
+			done <- r
+		}(i, s)
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for r := range done {
+		results[r.index] = r
+		if !report {
+			continue
+		}
+		duration := batchDuration(r)
+		if r.err == nil {
+			prompt.Info(fmt.Sprintf(msgBatchSucceeded, r.Name, duration))
+		} else {
+			prompt.Warning(fmt.Sprintf(msgBatchFailed, r.Name, r.ExitCode, duration))
+		}
+	}
+	return results, nil
+}
+
+// batchRunArgs returns the args of the child rit run of the formula
+func batchRunArgs(s batchStep) []string {
+	args := strings.Fields(s.Formula)
+	args = append(args, s.Flags...)
+	args = append(args, "--"+api.Stdin.ToLower(), "--")
+	return append(args, s.Args...)
+}
+
+func printBatchResults(results []batchResult) {
+	table := uitable.New()
+	table.AddRow("NAME", "FORMULA", "STATUS", "EXIT CODE", "DURATION")
+	var total time.Duration
+	var failed int
+	for _, r := range results {
+		status := prompt.Green("succeeded")
+		if r.err != nil {
+			status = prompt.Red("failed")
+			failed++
+		}
+		total += time.Duration(r.Duration * float64(time.Second))
+		table.AddRow(r.Name, r.Formula, status, strconv.Itoa(r.ExitCode), batchDuration(r))
+	}
+	fmt.Println(table)
+
+	summary := fmt.Sprintf(msgBatchSummary, len(results), len(results)-failed, failed, total.Round(time.Millisecond))
+	if failed > 0 {
+		prompt.Error(summary)
+		return
+	}
+	prompt.Success(summary)
+}
+
+// batchError returns the error of the first failed formula of the plan, nil when all succeeded
+func batchError(results []batchResult) error {
+	var failed int
+	var first error
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+			if first == nil {
+				first = r.err
+			}
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return fmt.Errorf(msgBatchFailures, failed, len(results), first)
+}
+
+func batchDuration(r batchResult) time.Duration {
+	return time.Duration(r.Duration * float64(time.Second)).Round(time.Millisecond)
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestRunBatchCmd(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "aws", Help: "aws formulas"},
+				{Parent: "root_aws", Usage: "create", Help: "create formula", Formula: &api.Formula{Path: "aws/create"}},
+				{Parent: "root_aws", Usage: "delete", Help: "delete formula", Formula: &api.Formula{Path: "aws/delete"}},
+			},
+		},
+	}
+	exit3 := exec.Command("sh", "-c", "exit 3").Run()
+
+	dir, err := ioutil.TempDir("", "rit-batch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	plan := `parallelism: 2
+formulas:
+  - name: bucket
+    formula: aws create
+    inputs:
+      region: sa-east-1
+    flags: ["--docker"]
+    args: ["--dry-run"]
+  - formula: rit aws delete
+`
+
+	tests := []struct {
+		name     string
+		plan     string
+		args     []string
+		fail     string
+		wantErr  error
+		wantOut  string
+		wantJSON bool
+		wantRuns map[string]string
+	}{
+		{
+			name: "runs the plan",
+			plan: plan,
+			wantRuns: map[string]string{
+				"aws create --docker --stdin -- --dry-run": `{"region":"sa-east-1"}`,
+				"aws delete --stdin --":                    `{}`,
+			},
+		},
+		{
+			name: "timestamps",
+			plan: plan,
+			args: []string{"--timestamps"},
+			wantRuns: map[string]string{
+				"aws create --docker --timestamps --stdin -- --dry-run": `{"region":"sa-east-1"}`,
+				"aws delete --timestamps --stdin --":                    `{}`,
+			},
+		},
+		{
+			name:     "a formula fails",
+			plan:     plan,
+			args:     []string{"--output", "json"},
+			fail:     "aws delete",
+			wantErr:  exit3,
+			wantOut:  `"exitCode": 3`,
+			wantJSON: true,
+			wantRuns: map[string]string{
+				"aws create --docker --stdin -- --dry-run": `{"region":"sa-east-1"}`,
+				"aws delete --stdin --":                    `{}`,
+			},
+		},
+		{
+			name:    "unknown formula",
+			plan:    "formulas:\n  - formula: aws update\n",
+			wantErr: ErrFormulaPathNotFound,
+		},
+		{
+			name:    "empty plan",
+			plan:    "parallelism: 2\n",
+			wantErr: ErrEmptyPlan,
+		},
+		{
+			name:    "invalid parallelism",
+			plan:    plan,
+			args:    []string{"--parallelism", "0"},
+			wantErr: ErrInvalidParallelism,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, "plan.yaml")
+			if err := ioutil.WriteFile(file, []byte(tt.plan), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var mu sync.Mutex
+			runs := map[string]string{}
+			oldCountRun := countRun
+			defer func() { countRun = oldCountRun }()
+			countRun = func(args, _ []string, in []byte, stdout io.Writer) error {
+				mu.Lock()
+				defer mu.Unlock()
+				fmt.Fprintln(stdout, "formula output")
+				runs[strings.Join(args, " ")] = string(in)
+				if strings.HasPrefix(strings.Join(args, " "), tt.fail+" ") {
+					return exit3
+				}
+				return nil
+			}
+
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, runnerMock{}, runnerMock{}, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			runCmd := NewRunCmd(inputListMock{})
+			runCmd.AddCommand(NewRunBatchCmd())
+			rootCmd.AddCommand(runCmd)
+			rootCmd.SetArgs(append([]string{"run", "batch", "-f", file}, tt.args...))
+
+			out := captureStdout(func() { err = rootCmd.Execute() })
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("%s = %v, want %v", rootCmd.Use, err, tt.wantErr)
+			}
+			if !strings.Contains(out, tt.wantOut) {
+				t.Errorf("rit run batch printed %q, want %q", out, tt.wantOut)
+			}
+			if tt.wantJSON && !json.Valid([]byte(out)) {
+				t.Errorf("rit run batch printed %q, want only the JSON of the results", out)
+			}
+			if tt.wantRuns == nil {
+				tt.wantRuns = map[string]string{}
+			}
+			if !reflect.DeepEqual(runs, tt.wantRuns) {
+				t.Errorf("batch runs = %q, want %q", runs, tt.wantRuns)
+			}
+		})
+	}
+}
+
+func TestRunBatchCmd_FormulaPath(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "aws", Help: "aws formulas"},
+				{Parent: "root_aws", Usage: "create", Help: "create formula", Formula: &api.Formula{Path: "aws/create"}},
+			},
+		},
+	}
+
+	rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+	rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+	var def formula.Definition
+	spy := runnerSpyMock{def: &def}
+	if err := NewFormulaCommand(api.CoreCmds, treeMock, spy, spy, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+		t.Fatalf("Add got %v, want nil", err)
+	}
+	runCmd := NewRunCmd(inputListMock{})
+	runCmd.AddCommand(NewRunBatchCmd())
+	rootCmd.AddCommand(runCmd)
+	rootCmd.SetArgs([]string{"run", "aws", "create"})
+
+	if err := rootCmd.Execute(); err != nil || def.Command != "rit aws create" {
+		t.Errorf("rit run aws create got %v and ran %q, want the formula run beside rit run batch", err, def.Command)
+	}
+}
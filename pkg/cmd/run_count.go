@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/slice/sliceutil"
+)
+
+const (
+	countFlag          = "count"
+	parallelismFlag    = "parallelism"
+	msgCountSucceeded  = "Run %d/%d succeeded in %v"
+	msgCountFailed     = "Run %d/%d failed with exit code %d in %v"
+	msgCountSummary    = "%d runs: %d succeeded, %d failed, total %v, average %v"
+	msgCountRunsFailed = "%d of %d runs failed: %w"
+	// countInputsEnv is the file the first run of --count without --stdin writes the values
+	// of its inputs on, as JSON, so that the other runs get them by env
+	countInputsEnv = "RIT_COUNT_INPUTS_FILE"
+)
+
+var (
+	ErrInvalidCount       = errors.New("--count must be at least 1")
+	ErrInvalidParallelism = errors.New("--parallelism must be at least 1")
+)
+
+// countRun runs the formula once on a child rit process, with the env added to the one of
+// rit and the stdin inputs, writing its stdout on stdout. A nil stdin is the one of rit, so
+// the run can prompt its inputs. It is a var so the runs of --count can be replaced on tests.
+var countRun = func(args, env []string, stdin []byte, stdout io.Writer) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	c := exec.Command(exe, args...)
+	c.Env = append(os.Environ(), env...)
+	c.Stdin = os.Stdin
+	if stdin != nil {
+		c.Stdin = bytes.NewReader(stdin)
+	}
+	c.Stdout = stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+// countResult is the outcome of one of the --count runs
+type countResult struct {
+	run      int
+	err      error
+	duration time.Duration
+}
+
+// countFlags returns the --count and --parallelism of the run
+func countFlags(cmd *cobra.Command) (int, int, error) {
+	count, err := cmd.Flags().GetInt(countFlag)
+	if err != nil {
+		return 0, 0, err
+	} else if count < 1 {
+		return 0, 0, ErrInvalidCount
+	}
+
+	parallelism, err := cmd.Flags().GetInt(parallelismFlag)
+	if err != nil {
+		return 0, 0, err
+	} else if parallelism < 1 {
+		return 0, 0, ErrInvalidParallelism
+	}
+	return count, parallelism, nil
+}
+
+// runCount runs the formula count times, up to parallelism runs at a time, and reports
+// each run and the summary. Every run is a child rit process, so it gets its own temp
+// workspace and working dir. The runs replay the same stdin inputs with --stdin, without it
+// the first run asks the inputs, or takes their defaults with --default, and the others
+// get its values by env. The error of the first failed run is returned, so rit exits with its code.
+func runCount(cmd *cobra.Command, count, parallelism int, formulaArgs []string) error {
+	args := childRunArgs(cmd, formulaArgs, countFlag, parallelismFlag)
+	results := make(chan countResult, count)
+	first := 1
+	var env []string
+	var stdin []byte
+	if boolFlag(cmd, api.Stdin.ToLower()) {
+		var err error
+		if stdin, err = ioutil.ReadAll(os.Stdin); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if env, err = firstCountRun(args, results); err != nil {
+			return err
+		}
+		// the other runs are informed every input, they have no stdin to prompt on
+		first, stdin = 2, []byte{}
+	}
+
+	slots := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i := first; i <= count; i++ {
+		wg.Add(1)
+		go func(run int) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			start := time.Now()
+			err := countRun(args, env, stdin, os.Stdout)
+			results <- countResult{run: run, err: err, duration: time.Since(start)}
+		}(i)
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var total time.Duration
+	var failed int
+	var firstErr error
+	for r := range results {
+		total += r.duration
+		duration := r.duration.Round(time.Millisecond)
+		if r.err == nil {
+			prompt.Info(fmt.Sprintf(msgCountSucceeded, r.run, count, duration))
+			continue
+		}
+
+		failed++
+		if firstErr == nil {
+			firstErr = r.err
+		}
+		prompt.Warning(fmt.Sprintf(msgCountFailed, r.run, count, ExitCode(r.err), duration))
+	}
+
+	average := (total / time.Duration(count)).Round(time.Millisecond)
+	summary := fmt.Sprintf(msgCountSummary, count, count-failed, failed, total.Round(time.Millisecond), average)
+	if failed > 0 {
+		prompt.Error(summary)
+		return fmt.Errorf(msgCountRunsFailed, failed, count, firstErr)
+	}
+	prompt.Success(summary)
+	return nil
+}
+
+// firstCountRun runs the first of the --count runs on the stdin of rit, so it asks the inputs,
+// and returns the env informing their values to the other runs. The values are written by the
+// run on a temp file only the user reads, see saveCountInputs.
+func firstCountRun(args []string, results chan<- countResult) ([]string, error) {
+	f, err := ioutil.TempFile("", "rit-count-inputs")
+	if err != nil {
+		return nil, err
+	}
+	_ = f.Close()
+	defer os.Remove(f.Name())
+
+	start := time.Now()
+	err = countRun(args, []string{countInputsEnv + "=" + f.Name()}, nil, os.Stdout)
+	results <- countResult{run: 1, err: err, duration: time.Since(start)}
+
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &values); err != nil {
+			return nil, err
+		}
+	}
+	return valuesEnv(values), nil
+}
+
+// saveCountInputs writes the values of the inputs of the first run of --count on the file of
+// countInputsEnv, for the parent rit to inform them to the other runs
+func saveCountInputs(values map[string]string) error {
+	file := os.Getenv(countInputsEnv)
+	if file == "" {
+		return nil
+	}
+
+	b, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, b, 0600)
+}
+
+// childRunArgs returns the args of a child rit run of the formula, as the ones of --count
+// and --detach: the formula command path with the flags informed to it, but the skip ones,
+// and the formula args
+func childRunArgs(cmd *cobra.Command, formulaArgs []string, skip ...string) []string {
+	args := strings.Fields(cmd.CommandPath())[1:]
+	// rit run sets the persistent flags, as --stdin, on its own flag set, so they aren't visited
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed && !sliceutil.Contains(skip, f.Name) {
+			args = append(args, flagArgs(f)...)
+		}
+	})
+	return append(append(args, "--"), formulaArgs...)
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/slice/sliceutil"
+	"github.com/ZupIT/ritchie-cli/pkg/stdin"
+)
+
+func TestFormulaCommand_Count(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "aws", Help: "aws formulas"},
+				{Parent: "root_aws", Usage: "create", Help: "create formula", Formula: &api.Formula{Path: "aws/create"}},
+			},
+		},
+	}
+	exit2 := exec.Command("sh", "-c", "exit 2").Run()
+	inputs := `{"region":"sa-east-1"}`
+
+	tests := []struct {
+		name            string
+		args            []string
+		failRun         int
+		wantErr         error
+		wantRuns        int
+		wantArgs        []string
+		wantFormulaRuns int
+	}{
+		{
+			name:     "runs the formula count times",
+			args:     []string{"aws", "create", "--stdin", "--count", "3", "--verbose", "--", "--dry-run"},
+			wantRuns: 3,
+			wantArgs: []string{"aws", "create", "--stdin=true", "--verbose=true", "--", "--dry-run"},
+		},
+		{
+			name:     "rit run in parallel",
+			args:     []string{"run", "aws", "create", "--stdin", "--count", "4", "--parallelism", "2", "--label", "a=1"},
+			wantRuns: 4,
+			wantArgs: []string{"aws", "create", "--label=a=1", "--stdin=true", "--"},
+		},
+		{
+			name:     "a run fails",
+			args:     []string{"aws", "create", "--stdin", "--count", "3", "--parallelism", "3"},
+			failRun:  2,
+			wantErr:  exit2,
+			wantRuns: 3,
+			wantArgs: []string{"aws", "create", "--stdin=true", "--"},
+		},
+		{
+			name:            "a single run is the formula run",
+			args:            []string{"aws", "create", "--stdin"},
+			wantFormulaRuns: 1,
+		},
+		{
+			name:     "prompt inputs once",
+			args:     []string{"aws", "create", "--count", "3"},
+			wantRuns: 3,
+			wantArgs: []string{"aws", "create", "--"},
+		},
+		{
+			name:     "default inputs once",
+			args:     []string{"aws", "create", "--default", "--count", "2", "--parallelism", "2"},
+			wantRuns: 2,
+			wantArgs: []string{"aws", "create", "--default=true", "--"},
+		},
+		{
+			name:    "invalid count",
+			args:    []string{"aws", "create", "--count", "0"},
+			wantErr: ErrInvalidCount,
+		},
+		{
+			name:    "invalid parallelism",
+			args:    []string{"aws", "create", "--count", "2", "--parallelism", "0"},
+			wantErr: ErrInvalidParallelism,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpfile, oldStdin, err := stdin.WriteToStdin(inputs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.Remove(tmpfile.Name())
+			defer func() { os.Stdin = oldStdin }()
+
+			stdinRuns := sliceutil.Contains(tt.args, "--stdin")
+			var mu sync.Mutex
+			var runs int
+			var gotArgs []string
+			oldCountRun := countRun
+			defer func() { countRun = oldCountRun }()
+			countRun = func(args, env []string, in []byte, _ io.Writer) error {
+				mu.Lock()
+				defer mu.Unlock()
+				runs++
+				gotArgs = args
+				switch {
+				case stdinRuns:
+					if string(in) != inputs || env != nil {
+						t.Errorf("run %d got the stdin %q and env %q, want %q and none", runs, in, env, inputs)
+					}
+				case runs == 1:
+					if in != nil || len(env) != 1 || !strings.HasPrefix(env[0], countInputsEnv+"=") {
+						t.Fatalf("first run got the stdin %q and env %q, want the rit stdin and %s", in, env, countInputsEnv)
+					}
+					if err := ioutil.WriteFile(strings.TrimPrefix(env[0], countInputsEnv+"="), []byte(inputs), 0600); err != nil {
+						t.Fatal(err)
+					}
+				default:
+					if len(in) != 0 || !reflect.DeepEqual(env, []string{"RIT_INPUT_REGION=sa-east-1"}) {
+						t.Errorf("run %d got the stdin %q and env %q, want none and the first run inputs", runs, in, env)
+					}
+				}
+				if runs == tt.failRun {
+					return exit2
+				}
+				return nil
+			}
+
+			var formulaRuns int
+			flaky := runnerFlakyMock{runs: &formulaRuns}
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, flaky, flaky, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			rootCmd.AddCommand(NewRunCmd(inputListMock{}))
+			rootCmd.SetArgs(tt.args)
+
+			err = rootCmd.Execute()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("%s = %v, want %v", rootCmd.Use, err, tt.wantErr)
+			}
+			if runs != tt.wantRuns || formulaRuns != tt.wantFormulaRuns {
+				t.Errorf("got %d count runs and %d formula runs, want %d and %d", runs, formulaRuns, tt.wantRuns, tt.wantFormulaRuns)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("count run args = %q, want %q", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
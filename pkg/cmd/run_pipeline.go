@@ -0,0 +1,360 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	onFailureStop       = "stop"
+	onFailureContinue   = "continue"
+	onFailureIgnore     = "ignore"
+	stepSucceeded       = "succeeded"
+	stepFailed          = "failed"
+	stepSkipped         = "skipped"
+	msgStepRunning      = "Running the step %s (%s)..."
+	msgStepSkipped      = "%s skipped, its input is the output %s, which its step didn't write as it failed or was skipped"
+	msgStepStopped      = "%s skipped, the pipeline stopped on the failure of %s"
+	msgPipelineSummary  = "%d steps: %d succeeded, %d failed, %d skipped, total %v"
+	msgPipelineFailed   = "the step %s failed: %w"
+	descRunPipelineLong = `Run the formulas of a pipeline file one after the other, each one with the outputs
+of the steps before it on its inputs. Each formula runs on a child rit process with its
+inputs on --stdin and --output json, so it never prompts and rit reads the outputs it
+declares on its config.json.
+
+The pipeline is a YAML or JSON file:
+
+  steps:
+    - name: bucket
+      formula: aws create bucket
+      inputs:
+        region: sa-east-1
+    - name: site
+      formula: aws deploy site
+      from:
+        bucket_url: bucket.url
+      flags: ["--docker"]
+      onFailure: continue
+
+The from inputs are the output of a step before, as step.output, they take precedence over
+the preset inputs. A step whose from outputs are missing, because their step failed or was
+skipped, is skipped. The formula is its command path without rit and the name defaults to it.
+
+The onFailure of a step is what the pipeline does when it fails:
+  stop      the next steps are skipped and the pipeline fails, the default
+  continue  the next steps run and the pipeline fails at the end
+  ignore    the next steps run and the failure doesn't fail the pipeline`
+)
+
+var (
+	ErrNoPipeline    = errors.New("inform the pipeline file with --file")
+	ErrEmptyPipeline = errors.New("the pipeline has no steps to run")
+)
+
+// pipelineRun runs a step on a child rit process with the stdin inputs and returns its
+// stdout, the outputs of the step as --output json prints them. It is a var so the tests
+// replace the runs.
+var pipelineRun = func(args []string, stdin []byte) ([]byte, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout bytes.Buffer
+	c := exec.Command(exe, args...)
+	c.Stdin = bytes.NewReader(stdin)
+	c.Stdout = &stdout
+	c.Stderr = os.Stderr
+	err = c.Run()
+	return stdout.Bytes(), err
+}
+
+// pipeline is the pipeline file of rit run pipeline
+type pipeline struct {
+	Steps []pipelineStep `json:"steps"`
+}
+
+// pipelineStep is a formula of the pipeline, its inputs are the preset ones and the from
+// ones, which name the output of a step before as step.output
+type pipelineStep struct {
+	Name      string                 `json:"name,omitempty"`
+	Formula   string                 `json:"formula"`
+	Inputs    map[string]interface{} `json:"inputs,omitempty"`
+	From      map[string]string      `json:"from,omitempty"`
+	Flags     []string               `json:"flags,omitempty"`
+	Args      []string               `json:"args,omitempty"`
+	OnFailure string                 `json:"onFailure,omitempty"`
+}
+
+// stepResult is the outcome of a step of the pipeline, as printed by --output
+type stepResult struct {
+	Name     string            `json:"name"`
+	Formula  string            `json:"formula"`
+	Status   string            `json:"status"`
+	ExitCode int               `json:"exitCode"`
+	Duration float64           `json:"duration"`
+	Outputs  map[string]string `json:"outputs,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	err      error
+}
+
+// NewRunPipelineCmd creates the run pipeline command, it runs the steps of a pipeline file
+// chaining the outputs of each formula to the inputs of the next ones
+func NewRunPipelineCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "pipeline",
+		Short:   "Run the formulas of a pipeline file, chaining their outputs to inputs",
+		Long:    descRunPipelineLong,
+		Example: "rit run pipeline -f pipe.yaml\nrit run pipeline -f pipe.yaml --output json",
+		Args:    cobra.NoArgs,
+		RunE:    runPipelineFunc(),
+	}
+	cmd.Flags().StringP(planFlag, "f", "", "Pipeline file with the steps to run")
+	addOutputFlag(cmd, "steps")
+
+	return cmd
+}
+
+func runPipelineFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		file, err := cmd.Flags().GetString(planFlag)
+		if err != nil {
+			return err
+		} else if file == "" {
+			return ErrNoPipeline
+		}
+
+		output, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		p, err := readPipeline(file)
+		if err != nil {
+			return err
+		}
+
+		formulas := runnableFormulas(cmd.Root())
+		for i, s := range p.Steps {
+			if _, ok := formulas[cmdUse+" "+s.Formula]; !ok {
+				return fmt.Errorf("step %d of the pipeline, %q: %w", i+1, s.Formula, ErrFormulaPathNotFound)
+			}
+		}
+
+		results, err := runPipeline(p, output == "")
+		if err != nil {
+			return err
+		}
+
+		if output != "" {
+			if err := printOutput(output, results); err != nil {
+				return err
+			}
+		} else {
+			printStepResults(results)
+		}
+		return pipelineError(p, results)
+	}
+}
+
+// readPipeline reads the YAML or JSON pipeline and validates its steps: the names are
+// unique, the from outputs are of steps before and the onFailure policies are known
+func readPipeline(file string) (pipeline, error) {
+	b, err := fileutil.ReadFile(file)
+	if err != nil {
+		return pipeline{}, err
+	}
+
+	var p pipeline
+	if err := yaml.UnmarshalStrict(b, &p); err != nil {
+		return pipeline{}, fmt.Errorf("invalid pipeline %s: %w", file, err)
+	}
+	if len(p.Steps) == 0 {
+		return pipeline{}, ErrEmptyPipeline
+	}
+
+	names := make(map[string]bool, len(p.Steps))
+	for i, s := range p.Steps {
+		s.Formula = strings.Join(strings.Fields(strings.TrimPrefix(strings.TrimSpace(s.Formula), cmdUse+" ")), " ")
+		if s.Formula == "" {
+			return pipeline{}, fmt.Errorf("step %d of the pipeline has no formula path", i+1)
+		}
+		if s.Name == "" {
+			s.Name = s.Formula
+		}
+		if names[s.Name] {
+			return pipeline{}, fmt.Errorf("step %d of the pipeline, the name %q is already used, name the steps apart", i+1, s.Name)
+		}
+
+		switch s.OnFailure {
+		case "":
+			s.OnFailure = onFailureStop
+		case onFailureStop, onFailureContinue, onFailureIgnore:
+		default:
+			return pipeline{}, fmt.Errorf("step %s has the invalid onFailure %q, use stop, continue or ignore", s.Name, s.OnFailure)
+		}
+
+		for input, ref := range s.From {
+			step, _ := splitOutputRef(ref)
+			if !names[step] {
+				return pipeline{}, fmt.Errorf("the input %s of the step %s is from %q, which isn't the output of a step before, as step.output", input, s.Name, ref)
+			}
+		}
+
+		names[s.Name] = true
+		p.Steps[i] = s
+	}
+	return p, nil
+}
+
+// splitOutputRef splits the step.output of a from input, the step names may have dots
+func splitOutputRef(ref string) (string, string) {
+	i := strings.LastIndex(ref, ".")
+	if i <= 0 || i == len(ref)-1 {
+		return "", ""
+	}
+	return ref[:i], ref[i+1:]
+}
+
+// runPipeline runs the steps in order, a step gets the outputs of the succeeded steps
+// before it. A step is skipped once a stop step failed or when its from outputs are missing.
+func runPipeline(p pipeline, report bool) ([]stepResult, error) {
+	results := make([]stepResult, 0, len(p.Steps))
+	outputs := make(map[string]map[string]string, len(p.Steps))
+	var stopped string
+	for _, s := range p.Steps {
+		r := stepResult{Name: s.Name, Formula: s.Formula, Status: stepSkipped}
+		if stopped != "" {
+			if report {
+				prompt.Warning(fmt.Sprintf(msgStepStopped, s.Name, stopped))
+			}
+			results = append(results, r)
+			continue
+		}
+
+		inputs, missing := stepInputs(s, outputs)
+		if missing != "" {
+			if report {
+				prompt.Warning(fmt.Sprintf(msgStepSkipped, s.Name, missing))
+			}
+			results = append(results, r)
+			continue
+		}
+
+		stdin, err := json.Marshal(inputs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid inputs of %s: %w", s.Name, err)
+		}
+
+		if report {
+			prompt.Info(fmt.Sprintf(msgStepRunning, s.Name, s.Formula))
+		}
+		start := time.Now()
+		out, err := pipelineRun(pipelineRunArgs(s), stdin)
+		r.Duration = time.Since(start).Seconds()
+		if err == nil {
+			err = json.Unmarshal(out, &r.Outputs)
+		}
+
+		if err != nil {
+			r.Status, r.ExitCode, r.Error, r.err, r.Outputs = stepFailed, ExitCode(err), err.Error(), err, nil
+			if s.OnFailure == onFailureStop {
+				stopped = s.Name
+			}
+		} else {
+			r.Status = stepSucceeded
+			outputs[s.Name] = r.Outputs
+		}
+
+		if report {
+			duration := time.Duration(r.Duration * float64(time.Second)).Round(time.Millisecond)
+			if r.err == nil {
+				prompt.Info(fmt.Sprintf(msgBatchSucceeded, r.Name, duration))
+			} else {
+				prompt.Warning(fmt.Sprintf(msgBatchFailed, r.Name, r.ExitCode, duration))
+			}
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// stepInputs returns the preset inputs of the step with its from inputs, or the first
+// from output that is missing
+func stepInputs(s pipelineStep, outputs map[string]map[string]string) (map[string]interface{}, string) {
+	inputs := make(map[string]interface{}, len(s.Inputs)+len(s.From))
+	for name, v := range s.Inputs {
+		inputs[name] = v
+	}
+	for name, ref := range s.From {
+		step, output := splitOutputRef(ref)
+		v, ok := outputs[step][output]
+		if !ok {
+			return nil, ref
+		}
+		inputs[name] = v
+	}
+	return inputs, ""
+}
+
+// pipelineRunArgs returns the args of the child rit run of the step
+func pipelineRunArgs(s pipelineStep) []string {
+	args := strings.Fields(s.Formula)
+	args = append(args, s.Flags...)
+	args = append(args, "--"+api.Stdin.ToLower(), "--"+outputFlag+"="+outputJSON, "--")
+	return append(args, s.Args...)
+}
+
+func printStepResults(results []stepResult) {
+	table := uitable.New()
+	table.AddRow("STEP", "FORMULA", "STATUS", "EXIT CODE", "DURATION")
+	var total time.Duration
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Status]++
+		total += time.Duration(r.Duration * float64(time.Second))
+
+		status := prompt.Green(r.Status)
+		switch r.Status {
+		case stepFailed:
+			status = prompt.Red(r.Status)
+		case stepSkipped:
+			status = prompt.Yellow(r.Status)
+		}
+		duration := time.Duration(r.Duration * float64(time.Second)).Round(time.Millisecond)
+		table.AddRow(r.Name, r.Formula, status, fmt.Sprint(r.ExitCode), duration)
+	}
+	fmt.Println(table)
+
+	summary := fmt.Sprintf(msgPipelineSummary, len(results), counts[stepSucceeded], counts[stepFailed], counts[stepSkipped], total.Round(time.Millisecond))
+	if counts[stepFailed] > 0 {
+		prompt.Error(summary)
+		return
+	}
+	prompt.Success(summary)
+}
+
+// pipelineError returns the error of the first failed step that isn't ignored, so rit
+// exits with its code, nil when the pipeline succeeded
+func pipelineError(p pipeline, results []stepResult) error {
+	for i, r := range results {
+		if r.err != nil && p.Steps[i].OnFailure != onFailureIgnore {
+			return fmt.Errorf(msgPipelineFailed, r.Name, r.err)
+		}
+	}
+	return nil
+}
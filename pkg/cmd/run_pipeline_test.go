@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestRunPipelineCmd(t *testing.T) {
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "aws", Help: "aws formulas"},
+				{Parent: "root_aws", Usage: "create", Help: "create formula", Formula: &api.Formula{Path: "aws/create"}},
+				{Parent: "root_aws", Usage: "deploy", Help: "deploy formula", Formula: &api.Formula{Path: "aws/deploy"}},
+				{Parent: "root_aws", Usage: "notify", Help: "notify formula", Formula: &api.Formula{Path: "aws/notify"}},
+			},
+		},
+	}
+	exit3 := exec.Command("sh", "-c", "exit 3").Run()
+
+	dir, err := ioutil.TempDir("", "rit-pipeline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pipe := func(onFailure string) string {
+		return `steps:
+  - name: bucket
+    formula: aws create
+    inputs:
+      region: sa-east-1
+    onFailure: ` + onFailure + `
+  - name: site
+    formula: rit aws deploy
+    inputs:
+      region: sa-east-1
+      bucket_url: replaced
+    from:
+      bucket_url: bucket.url
+    flags: ["--docker"]
+  - formula: aws notify
+`
+	}
+	createRun := "aws create --stdin --output=json --"
+	deployRun := "aws deploy --docker --stdin --output=json --"
+	notifyRun := "aws notify --stdin --output=json --"
+
+	tests := []struct {
+		name     string
+		pipe     string
+		args     []string
+		fail     string
+		invalid  bool
+		wantErr  error
+		wantOut  string
+		wantRuns []string
+		wantIn   map[string]string
+	}{
+		{
+			name:     "chains the outputs to the inputs",
+			pipe:     pipe("stop"),
+			wantRuns: []string{createRun, deployRun, notifyRun},
+			wantIn: map[string]string{
+				createRun: `{"region":"sa-east-1"}`,
+				deployRun: `{"bucket_url":"s3://site","region":"sa-east-1"}`,
+				notifyRun: `{}`,
+			},
+		},
+		{
+			name:     "stop",
+			pipe:     pipe("stop"),
+			args:     []string{"--output", "json"},
+			fail:     createRun,
+			wantErr:  exit3,
+			wantOut:  `"status": "skipped"`,
+			wantRuns: []string{createRun},
+		},
+		{
+			name:     "continue skips the steps of its outputs",
+			pipe:     pipe("continue"),
+			fail:     createRun,
+			wantErr:  exit3,
+			wantRuns: []string{createRun, notifyRun},
+		},
+		{
+			name:     "ignore",
+			pipe:     pipe("ignore"),
+			fail:     createRun,
+			wantRuns: []string{createRun, notifyRun},
+		},
+		{
+			name:    "output of a later step",
+			pipe:    "steps:\n  - formula: aws deploy\n    from:\n      url: bucket.url\n  - name: bucket\n    formula: aws create\n",
+			invalid: true,
+		},
+		{
+			name:    "invalid onFailure",
+			pipe:    pipe("retry"),
+			invalid: true,
+		},
+		{
+			name:    "unknown formula",
+			pipe:    "steps:\n  - formula: aws update\n",
+			wantErr: ErrFormulaPathNotFound,
+		},
+		{
+			name:    "empty pipeline",
+			pipe:    "steps: []\n",
+			wantErr: ErrEmptyPipeline,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, "pipe.yaml")
+			if err := ioutil.WriteFile(file, []byte(tt.pipe), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var runs []string
+			in := map[string]string{}
+			oldPipelineRun := pipelineRun
+			defer func() { pipelineRun = oldPipelineRun }()
+			pipelineRun = func(args []string, stdin []byte) ([]byte, error) {
+				run := strings.Join(args, " ")
+				runs = append(runs, run)
+				in[run] = string(stdin)
+				switch run {
+				case tt.fail:
+					return nil, exit3
+				case createRun:
+					return []byte(`{"url":"s3://site"}`), nil
+				}
+				return []byte(`{}`), nil
+			}
+
+			rootCmd := &cobra.Command{Use: "rit", SilenceErrors: true, SilenceUsage: true}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			if err := NewFormulaCommand(api.CoreCmds, treeMock, runnerMock{}, runnerMock{}, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+			runCmd := NewRunCmd(inputListMock{})
+			runCmd.AddCommand(NewRunPipelineCmd())
+			rootCmd.AddCommand(runCmd)
+			rootCmd.SetArgs(append([]string{"run", "pipeline", "-f", file}, tt.args...))
+
+			out := captureStdout(func() { err = rootCmd.Execute() })
+			if tt.invalid {
+				if err == nil || runs != nil {
+					t.Fatalf("rit run pipeline got %v and ran %q, want the invalid pipeline error", err, runs)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("rit run pipeline = %v, want %v", err, tt.wantErr)
+			}
+			if !strings.Contains(out, tt.wantOut) {
+				t.Errorf("rit run pipeline printed %q, want %q", out, tt.wantOut)
+			}
+			if !reflect.DeepEqual(runs, tt.wantRuns) {
+				t.Errorf("pipeline runs = %q, want %q", runs, tt.wantRuns)
+			}
+			for run, want := range tt.wantIn {
+				if in[run] != want {
+					t.Errorf("%s got the inputs %s, want %s", run, in[run], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestNewRunCmd(t *testing.T) {
+	tree := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "mock", Help: "mock for run"},
+				{Parent: "root_mock", Usage: "test", Help: "test formula", Formula: &api.Formula{Path: "mock/test"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		tree       treeMock
+		list       inputListCustomMock
+		terminal   bool
+		runnerErr  error
+		wantErr    error
+		wantChoice bool
+	}{
+		{
+			name:       "choose formula from menu",
+			args:       []string{"run"},
+			tree:       tree,
+			list:       inputListCustomMock{list: func(string, []string) (string, error) { return "rit mock test", nil }},
+			terminal:   true,
+			wantChoice: true,
+		},
+		{
+			name:      "run formula by path",
+			args:      []string{"run", "mock", "test"},
+			tree:      tree,
+			runnerErr: errors.New("formula executed"),
+			wantErr:   errors.New("formula executed"),
+		},
+		{
+			name:    "unknown formula path",
+			args:    []string{"run", "mock", "unknown"},
+			tree:    tree,
+			wantErr: ErrFormulaPathNotFound,
+		},
+		{
+			name:    "menu without terminal",
+			args:    []string{"run"},
+			tree:    tree,
+			wantErr: ErrNonInteractiveRun,
+		},
+		{
+			name:     "menu without formulas",
+			args:     []string{"run", "--interactive-select"},
+			terminal: true,
+			wantErr:  ErrNoFormulas,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "rit"}
+			rootCmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			if err := NewFormulaCommand(api.CoreCmds, tt.tree, runnerMock{error: tt.runnerErr}, runnerMock{}, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+				t.Fatalf("Add got %v, want nil", err)
+			}
+
+			chosen := false
+			list := inputListCustomMock{list: func(name string, items []string) (string, error) {
+				chosen = true
+				return tt.list.list(name, items)
+			}}
+			r := runCmd{InputList: list, isTerminal: func() bool { return tt.terminal }}
+			runCobra := NewRunCmd(list)
+			runCobra.RunE = r.runFunc()
+			rootCmd.AddCommand(runCobra)
+			rootCmd.SetArgs(tt.args)
+
+			err := rootCmd.Execute()
+			if (err == nil) != (tt.wantErr == nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Errorf("%s got %v, want %v", tt.name, err, tt.wantErr)
+			}
+			if chosen != tt.wantChoice {
+				t.Errorf("%s menu shown = %v, want %v", tt.name, chosen, tt.wantChoice)
+			}
+		})
+	}
+}
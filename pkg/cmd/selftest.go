@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	msgSelfTestRunning = "Running the built-in smoke formula..."
+	msgSelfTestFailed  = "rit self-test failed: %w"
+	msgSelfTestPassed  = "rit self-test passed, formulas can run on this machine"
+	descSelfTestLong   = `Check that formulas can run on this machine.
+
+A built-in formula is run through the same steps as any formula: its input is
+resolved, it runs on a temp work dir and its output is captured on a run log.
+No repository is needed, nothing is written on the rit home.`
+)
+
+type selfTestCmd struct {
+	formula.SelfTester
+}
+
+// NewSelfTestCmd creates a new cmd instance
+func NewSelfTestCmd(st formula.SelfTester) *cobra.Command {
+	s := selfTestCmd{st}
+
+	return &cobra.Command{
+		Use:     "self-test",
+		Short:   "Run a built-in formula to check the rit install",
+		Long:    descSelfTestLong,
+		Example: "rit self-test",
+		Args:    cobra.NoArgs,
+		RunE:    s.runFunc(),
+	}
+}
+
+func (s selfTestCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		prompt.Info(msgSelfTestRunning)
+		if err := s.SelfTest(); err != nil {
+			return fmt.Errorf(msgSelfTestFailed, err)
+		}
+
+		prompt.Success(msgSelfTestPassed)
+		return nil
+	}
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSelfTestCmd(t *testing.T) {
+	errRun := errors.New("running the smoke formula: exit status 1")
+	tests := []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{name: "passed"},
+		{name: "failed", err: errRun, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewSelfTestCmd(selfTesterMock{err: tt.err})
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			cmd.SetArgs([]string{})
+
+			err := cmd.Execute()
+			if (err != nil) != tt.wantErr || (tt.wantErr && !errors.Is(err, errRun)) {
+				t.Errorf("self-test error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
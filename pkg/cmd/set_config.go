@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/config"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/stdin"
+)
+
+// setConfigCmd type for set config command
+type setConfigCmd struct {
+	config.Setter
+	prompt.InputText
+	prompt.InputList
+}
+
+// setConfig type for stdin json decoder
+type setConfig struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// NewSetConfigCmd creates a new cmd instance
+func NewSetConfigCmd(s config.Setter, it prompt.InputText, il prompt.InputList) *cobra.Command {
+	c := setConfigCmd{s, it, il}
+
+	return &cobra.Command{
+		Use:     "config",
+		Short:   "Set a rit setting",
+		Example: "rit set config accessibility.plain true",
+		Args:    cobra.MaximumNArgs(2),
+		RunE:    RunFuncE(c.runStdin(), c.runPrompt()),
+
+		ValidArgsFunction: completeConfig,
+	}
+}
+
+func (c setConfigCmd) runPrompt() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) == 2 {
+			return c.set(args[0], args[1])
+		}
+
+		key, err := c.List("Setting:", config.KeyNames())
+		if err != nil {
+			return err
+		}
+
+		value, err := c.Text(fmt.Sprintf("%s: ", config.Keys[key].Usage), true)
+		if err != nil {
+			return err
+		}
+
+		return c.set(key, value)
+	}
+}
+
+func (c setConfigCmd) runStdin() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		sc := setConfig{}
+
+		if err := stdin.ReadJson(os.Stdin, &sc); err != nil {
+			prompt.Error(stdin.MsgInvalidInput)
+			return err
+		}
+
+		return c.set(sc.Key, sc.Value)
+	}
+}
+
+func (c setConfigCmd) set(key, value string) error {
+	if err := c.Set(key, value); err != nil {
+		return prompt.NewError(err.Error())
+	}
+
+	prompt.Success(fmt.Sprintf("Set %s successful!", key))
+	return nil
+}
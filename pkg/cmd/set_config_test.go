@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/config"
+)
+
+func TestNewSetConfigCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		setErr  error
+		wantKey string
+		wantErr bool
+	}{
+		{
+			name:    "set by args",
+			args:    []string{config.PlainKey, "true"},
+			wantKey: config.PlainKey,
+		},
+		{
+			name:    "set by prompt",
+			wantKey: "item-mocked",
+		},
+		{
+			name:    "invalid value",
+			args:    []string{config.PlainKey, "sometimes"},
+			setErr:  errors.New("invalid value"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &configSetterMock{err: tt.setErr}
+			cmd := NewSetConfigCmd(s, inputTextMock{}, inputListMock{})
+			cmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("%s = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if s.key != tt.wantKey {
+				t.Errorf("%s set key %q, want %q", tt.name, s.key, tt.wantKey)
+			}
+		})
+	}
+}
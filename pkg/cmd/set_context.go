@@ -31,10 +31,13 @@ func NewSetContextCmd(
 	s := setContextCmd{fs, it, il}
 
 	cmd := &cobra.Command{
-		Use:     "context",
+		Use:     "context [NAME]",
 		Short:   "Set context",
-		Example: "rit set context",
-		RunE: RunFuncE(s.runStdin(), s.runPrompt()),
+		Example: "rit set context\nrit set context prod",
+		Args:    cobra.MaximumNArgs(1),
+		RunE:    RunFuncE(s.runStdin(), s.runPrompt()),
+
+		ValidArgsFunction: completeContexts(fs),
 	}
 
 	cmd.LocalFlags()
@@ -44,6 +47,10 @@ func NewSetContextCmd(
 
 func (s setContextCmd) runPrompt() CommandRunnerFunc {
 	return func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 {
+			return s.set(args[0])
+		}
+
 		ctxHolder, err := s.Find()
 		if err != nil {
 			return err
@@ -63,12 +70,7 @@ func (s setContextCmd) runPrompt() CommandRunnerFunc {
 			}
 		}
 
-		if _, err := s.Set(ctx); err != nil {
-			return err
-		}
-
-		prompt.Success("Set context successful!")
-		return nil
+		return s.set(ctx)
 	}
 
 }
@@ -84,11 +86,15 @@ func (s setContextCmd) runStdin() CommandRunnerFunc {
 			return err
 		}
 
-		if _, err := s.Set(sc.Context); err != nil {
-			return err
-		}
+		return s.set(sc.Context)
+	}
+}
 
-		prompt.Success("Set context successful!")
-		return nil
+func (s setContextCmd) set(ctx string) error {
+	if _, err := s.Set(ctx); err != nil {
+		return err
 	}
+
+	prompt.Success("Set context successful!")
+	return nil
 }
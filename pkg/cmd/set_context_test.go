@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
 )
 
 func TestNewSetContextCmd(t *testing.T) {
@@ -15,3 +17,22 @@ func TestNewSetContextCmd(t *testing.T) {
 		t.Errorf("%s = %v, want %v", cmd.Use, err, nil)
 	}
 }
+
+func TestSetContextName(t *testing.T) {
+	setter := &ctxSetterSpyMock{}
+	cmd := NewSetContextCmd(ctxFindSetterSpyMock{setter}, inputTextMock{}, inputListMock{})
+	cmd.PersistentFlags().Bool("stdin", false, "input by stdin")
+	cmd.SetArgs([]string{"prod"})
+
+	if err := cmd.Execute(); err != nil || len(setter.set) != 1 || setter.set[0] != "prod" {
+		t.Errorf("%s prod got %v and set %v, want prod set", cmd.Use, err, setter.set)
+	}
+}
+
+type ctxFindSetterSpyMock struct {
+	*ctxSetterSpyMock
+}
+
+func (ctxFindSetterSpyMock) Find() (rcontext.ContextHolder, error) {
+	return rcontext.ContextHolder{}, nil
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/config"
+)
+
+type showConfigCmd struct {
+	config.Finder
+}
+
+// NewShowConfigCmd creates a new cmd instance
+func NewShowConfigCmd(f config.Finder) *cobra.Command {
+	s := showConfigCmd{f}
+
+	cmd := &cobra.Command{
+		Use:     "config",
+		Short:   "Show rit settings",
+		Example: "rit show config\nrit show config --output json",
+		RunE:    s.runFunc(),
+	}
+	addOutputFlag(cmd, "settings")
+
+	return cmd
+}
+
+// settingOutput is a setting of rit show config --output
+type settingOutput struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Usage string `json:"usage"`
+}
+
+func (s showConfigCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		output, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		cfg, err := s.Find()
+		if err != nil {
+			return err
+		}
+
+		if output != "" {
+			settings := make([]settingOutput, 0, len(config.Keys))
+			for _, k := range config.KeyNames() {
+				settings = append(settings, settingOutput{Key: k, Value: cfg.Get(k), Usage: config.Keys[k].Usage})
+			}
+			return printOutput(output, settings)
+		}
+
+		table := uitable.New()
+		table.AddRow("KEY", "VALUE", "USAGE")
+		for _, k := range config.KeyNames() {
+			table.AddRow(k, cfg.Get(k), config.Keys[k].Usage)
+		}
+		fmt.Println(table)
+
+		return nil
+	}
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/config"
+)
+
+func TestNewShowConfigCmd(t *testing.T) {
+	cmd := NewShowConfigCmd(configFinderMock{config.Config{config.PlainKey: "true"}})
+	if cmd == nil {
+		t.Errorf("NewShowConfigCmd got %v", cmd)
+	}
+
+	if err := cmd.Execute(); err != nil {
+		t.Errorf("%s = %v, want %v", cmd.Use, err, nil)
+	}
+}
@@ -16,16 +16,30 @@ type showContextCmd struct {
 func NewShowContextCmd(f rcontext.Finder) *cobra.Command {
 	s := showContextCmd{f}
 
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "context",
 		Short:   "Show current context",
-		Example: "rit show context",
+		Example: "rit show context\nrit show context --output yaml",
 		RunE:    s.runFunc(),
 	}
+	addOutputFlag(cmd, "contexts")
+
+	return cmd
+}
+
+// contextOutput is the output of rit show context --output
+type contextOutput struct {
+	Current  string   `json:"current"`
+	Contexts []string `json:"contexts"`
 }
 
 func (s showContextCmd) runFunc() CommandRunnerFunc {
 	return func(cmd *cobra.Command, args []string) error {
+		output, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
 		ctx, err := s.Find()
 		if err != nil {
 			return err
@@ -35,7 +49,12 @@ func (s showContextCmd) runFunc() CommandRunnerFunc {
 			ctx.Current = rcontext.DefaultCtx
 		}
 
-		prompt.Info(fmt.Sprintf("Current context: %s \n", ctx.Current))
+		if output != "" {
+			return printOutput(output, contextOutput{Current: ctx.Current, Contexts: append([]string{}, ctx.All...)})
+		}
+
+		// the context is the output asked for, it is printed even with --quiet
+		fmt.Fprintln(prompt.Stdout, prompt.Bold(fmt.Sprintf("Current context: %s \n", ctx.Current)))
 		return nil
 	}
 }
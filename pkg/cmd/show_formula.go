@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+var ErrFormulaNotFound = errors.New("formula not found, list the formulas with rit help")
+
+type showFormulaCmd struct {
+	formula.TreeManager
+}
+
+// NewShowFormulaCmd creates a new cmd instance
+func NewShowFormulaCmd(tm formula.TreeManager) *cobra.Command {
+	s := showFormulaCmd{tm}
+
+	cmd := &cobra.Command{
+		Use:     "formula FORMULA PATH",
+		Short:   "Show the description and usage examples of a formula",
+		Example: "rit show formula aws create\nrit show formula aws create --output json",
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    s.runFunc(),
+	}
+	addOutputFlag(cmd, "formula description")
+
+	return cmd
+}
+
+// formulaOutput is the formula of rit show formula --output
+type formulaOutput struct {
+	Command         string        `json:"command"`
+	Repo            string        `json:"repo"`
+	Description     string        `json:"description"`
+	LongDescription string        `json:"longDescription,omitempty"`
+	Examples        []api.Example `json:"examples,omitempty"`
+	Deprecated      string        `json:"deprecated,omitempty"`
+}
+
+func (s showFormulaCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		output, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		path := strings.Join(append([]string{cmdUse}, args...), " ")
+		var found *api.Command
+		for _, c := range s.MergedTree(false).Commands {
+			if c.Formula != nil && formula.CommandPath(c) == path {
+				c := c
+				found = &c
+				break
+			}
+		}
+		if found == nil {
+			return fmt.Errorf("%w: %s", ErrFormulaNotFound, path)
+		}
+
+		f := formulaOutput{
+			Command:         path,
+			Repo:            found.Repo,
+			Description:     found.Help,
+			LongDescription: found.LongHelp,
+			Examples:        found.Examples,
+		}
+		if found.Deprecation != nil {
+			f.Deprecated = formula.DeprecationMsg(path, *found.Deprecation)
+		}
+
+		if output != "" {
+			return printOutput(output, f)
+		}
+		fmt.Println(formulaText(f))
+		return nil
+	}
+}
+
+func formulaText(f formulaOutput) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s (repo %s)\n\n%s\n", f.Command, f.Repo, f.Description))
+	if f.LongDescription != "" {
+		sb.WriteString("\n" + f.LongDescription + "\n")
+	}
+	if f.Deprecated != "" {
+		sb.WriteString("\n" + f.Deprecated + "\n")
+	}
+	if len(f.Examples) > 0 {
+		sb.WriteString("\nExamples:\n" + formulaExamples(f.Examples) + "\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestShowFormulaCmd(t *testing.T) {
+	tree := treeMock{tree: formula.Tree{Commands: api.Commands{
+		{Parent: "root", Usage: "aws", Help: "aws commands"},
+		{
+			Parent:   "root_aws",
+			Usage:    "create",
+			Help:     "Create a stack",
+			LongHelp: "Create a CloudFormation stack and wait for it.",
+			Examples: []api.Example{{Description: "on sa-east-1", Command: "rit aws create --region sa-east-1"}},
+			Formula:  &api.Formula{Path: "aws/create"},
+			Repo:     "commons",
+		},
+	}}}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "text",
+			args: []string{"aws", "create"},
+			want: []string{"rit aws create (repo commons)", "Create a CloudFormation stack", "  # on sa-east-1\n  rit aws create --region sa-east-1"},
+		},
+		{name: "json", args: []string{"aws", "create", "--output", "json"}, want: []string{`"longDescription": "Create a CloudFormation stack and wait for it."`}},
+		{name: "not a formula", args: []string{"aws"}, wantErr: ErrFormulaNotFound},
+		{name: "unknown formula", args: []string{"aws", "delete"}, wantErr: ErrFormulaNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewShowFormulaCmd(tree)
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			cmd.SetArgs(tt.args)
+
+			var err error
+			out := captureStdout(func() { err = cmd.Execute() })
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("show formula error = %v, want %v", err, tt.wantErr)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(out, w) {
+					t.Errorf("show formula printed %q, want %q", out, w)
+				}
+			}
+		})
+	}
+}
+
+func TestFormulaCommand_Help(t *testing.T) {
+	tree := treeMock{tree: formula.Tree{Commands: api.Commands{
+		{Parent: "root", Usage: "aws", Help: "aws commands"},
+		{
+			Parent:   "root_aws",
+			Usage:    "create",
+			Help:     "Create a stack",
+			LongHelp: "Create a CloudFormation stack and wait for it.",
+			Examples: []api.Example{{Command: "rit aws create --region sa-east-1"}},
+			Formula:  &api.Formula{Path: "aws/create"},
+		},
+		{Parent: "root_aws", Usage: "list", Help: "List the stacks", Formula: &api.Formula{Path: "aws/list"}},
+	}}}
+
+	rootCmd := &cobra.Command{Use: "rit"}
+	if err := NewFormulaCommand(api.CoreCmds, tree, runnerMock{}, runnerMock{}, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{}).Add(rootCmd); err != nil {
+		t.Fatalf("Add got %v, want nil", err)
+	}
+
+	create, _, _ := rootCmd.Find([]string{"aws", "create"})
+	if create.Short != "Create a stack" || !strings.HasPrefix(create.Long, "Create a CloudFormation stack") ||
+		create.Example != "  rit aws create --region sa-east-1" {
+		t.Errorf("aws create got short %q, long %q and example %q", create.Short, create.Long, create.Example)
+	}
+	list, _, _ := rootCmd.Find([]string{"aws", "list"})
+	if !strings.HasPrefix(list.Long, "List the stacks") || list.Example != "" {
+		t.Errorf("aws list got long %q and example %q", list.Long, list.Example)
+	}
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula/detach"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	msgStopped   = "%s was asked to stop, it is listed by rit ps --all once it exits"
+	descStopLong = `Stop a formula run started with --detach, by the ID listed by rit ps.
+
+The formula is stopped as on a Ctrl+C, so it has the time of the --timeout-kill-grace of
+its run to clean up before it is killed, then its run log records the run as failed.`
+)
+
+type stopCmd struct {
+	detach.Stopper
+}
+
+// NewStopCmd creates the stop command, it stops a formula run detached from the terminal
+func NewStopCmd(s detach.Stopper) *cobra.Command {
+	c := stopCmd{s}
+
+	return &cobra.Command{
+		Use:     "stop ID",
+		Short:   "Stop a formula run started with --detach",
+		Long:    descStopLong,
+		Example: "rit stop 20200720T100000.000000-rit-aws-tunnel",
+		Args:    cobra.ExactArgs(1),
+		RunE:    c.runFunc(),
+	}
+}
+
+func (c stopCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		run, err := c.Stop(args[0])
+		if err != nil {
+			return err
+		}
+		prompt.Success(fmt.Sprintf(msgStopped, run.Command))
+		return nil
+	}
+}
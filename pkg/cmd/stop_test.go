@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula/detach"
+)
+
+func TestStopCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		err     error
+		wantErr bool
+	}{
+		{name: "stops the run", args: []string{"1-rit-aws-tunnel"}},
+		{name: "finished run", args: []string{"1-rit-aws-tunnel"}, err: detach.ErrNotRunning, wantErr: true},
+		{name: "without ID", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stopped string
+			cmd := NewStopCmd(detachedRunsMock{stopped: &stopped, err: tt.err})
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			cmd.SetArgs(tt.args)
+
+			if err := cmd.Execute(); (err != nil) != tt.wantErr {
+				t.Fatalf("stop got error %v, want error %v", err, tt.wantErr)
+			}
+			if len(tt.args) > 0 && stopped != tt.args[0] {
+				t.Errorf("stop stopped %q, want %q", stopped, tt.args[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/config"
+	"github.com/ZupIT/ritchie-cli/pkg/http/httpclient"
+)
+
+const (
+	clientCertFlag = "client-cert"
+	clientKeyFlag  = "client-key"
+	caCertFlag     = "ca-cert"
+)
+
+// addTLSFlags adds the persistent flags of the TLS files, see TLSArgs
+func addTLSFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	flags.String(clientCertFlag, "", "PEM client certificate file for servers requiring mutual TLS, overrides the tls.client-cert config")
+	flags.String(clientKeyFlag, "", "PEM private key file of the client certificate, overrides the tls.client-key config")
+	flags.String(caCertFlag, "", "PEM CA bundle file trusted besides the system CAs, overrides the tls.ca config")
+}
+
+// TLSArgs returns the TLS files of the args, falling back to the config ones.
+// Like HomeArg, they must be read before the http clients are created.
+func TLSArgs(args []string, cfg config.Config) httpclient.TLS {
+	value := func(flag, key string) string {
+		if v := flagArg(args, flag); v != "" {
+			return v
+		}
+		return cfg.Get(key)
+	}
+
+	return httpclient.TLS{
+		CertFile: value(clientCertFlag, config.TLSClientCertKey),
+		KeyFile:  value(clientKeyFlag, config.TLSClientKeyKey),
+		CAFile:   value(caCertFlag, config.TLSCAKey),
+	}
+}
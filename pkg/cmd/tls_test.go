@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/config"
+	"github.com/ZupIT/ritchie-cli/pkg/http/httpclient"
+)
+
+func TestTLSArgs(t *testing.T) {
+	cfg := config.Config{config.TLSClientCertKey: "/etc/rit/cert.pem", config.TLSCAKey: "/etc/rit/ca.pem"}
+	got := TLSArgs([]string{"list", "repo", "--client-cert", "/tmp/cert.pem", "--client-key=/tmp/key.pem"}, cfg)
+
+	want := httpclient.TLS{CertFile: "/tmp/cert.pem", KeyFile: "/tmp/key.pem", CAFile: "/etc/rit/ca.pem"}
+	if got != want {
+		t.Errorf("TLSArgs got %+v, want %+v", got, want)
+	}
+}
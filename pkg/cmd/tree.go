@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+const descTreeLong = `Print the full command tree, with the core commands and the formulas.
+
+Use --output json or --output yaml to export it for tooling, as IDE plugins and
+documentation generators. The formula inputs are exported when the formula config is already
+downloaded, which happens on the first run of the formula.`
+
+// FormulaFinder finds the tree command of a formula by its command path, e.g. "rit aws create"
+type FormulaFinder interface {
+	Formula(path string) (api.Command, bool)
+}
+
+// treeNode is a command of the exported tree
+type treeNode struct {
+	Name        string          `json:"name"`
+	Path        string          `json:"path"`
+	Description string          `json:"description"`
+	Runnable    bool            `json:"runnable"`
+	Formula     bool            `json:"formula"`
+	Repo        string          `json:"repo,omitempty"`
+	Flags       []treeFlag      `json:"flags,omitempty"`
+	Inputs      []formula.Input `json:"inputs,omitempty"`
+	Commands    []treeNode      `json:"commands,omitempty"`
+}
+
+type treeFlag struct {
+	Name        string `json:"name"`
+	Shorthand   string `json:"shorthand,omitempty"`
+	Type        string `json:"type"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description"`
+}
+
+// treeCmd type for tree command
+type treeCmd struct {
+	homePath string
+	FormulaFinder
+}
+
+// NewTreeCmd creates the tree command
+func NewTreeCmd(homePath string, ff FormulaFinder) *cobra.Command {
+	t := treeCmd{homePath: homePath, FormulaFinder: ff}
+
+	cmd := &cobra.Command{
+		Use:     "tree",
+		Short:   "Print the full command tree",
+		Long:    descTreeLong,
+		Example: "rit tree\nrit tree --output json\nrit tree --output yaml",
+		Args:    cobra.NoArgs,
+		RunE:    t.runFunc(),
+	}
+	addOutputFlag(cmd, "tree")
+
+	return cmd
+}
+
+func (t treeCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		output, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		root := t.node(cmd.Root())
+		if output != "" {
+			return printOutput(output, root)
+		}
+
+		printTree(root, 0)
+		return nil
+	}
+}
+
+// node builds the tree node of the command and its available subcommands
+func (t treeCmd) node(c *cobra.Command) treeNode {
+	_, isFormula := c.Annotations[FormulaAnnotation]
+	n := treeNode{
+		Name:        c.Name(),
+		Path:        c.CommandPath(),
+		Description: c.Short,
+		Runnable:    c.Runnable(),
+		Formula:     isFormula,
+		Repo:        c.Annotations[FormulaAnnotation],
+	}
+
+	c.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		n.Flags = append(n.Flags, treeFlag{
+			Name:        f.Name,
+			Shorthand:   f.Shorthand,
+			Type:        f.Value.Type(),
+			Default:     f.DefValue,
+			Description: f.Usage,
+		})
+	})
+
+	if isFormula {
+		n.Inputs = t.inputs(n.Path)
+	}
+
+	for _, child := range c.Commands() {
+		if child.IsAvailableCommand() {
+			n.Commands = append(n.Commands, t.node(child))
+		}
+	}
+	return n
+}
+
+// inputs reads the inputs of the formula config, there are none when
+// the config wasn't downloaded yet, so that no download is made
+func (t treeCmd) inputs(path string) []formula.Input {
+	c, ok := t.Formula(path)
+	if !ok {
+		return nil
+	}
+
+	d := definition(path, c.Repo, *c.Formula)
+	configPath := d.ConfigPath(d.FormulaPath(t.homePath), d.ConfigName())
+	if !fileutil.Exists(configPath) {
+		return nil
+	}
+
+	b, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return nil
+	}
+
+	config, err := formula.UnmarshalConfig(b, c.Repo)
+	if err != nil {
+		return nil
+	}
+	return config.Inputs
+}
+
+func printTree(n treeNode, depth int) {
+	fmt.Printf("%s%s  %s\n", strings.Repeat("  ", depth), n.Name, n.Description)
+	for _, c := range n.Commands {
+		printTree(c, depth+1)
+	}
+}
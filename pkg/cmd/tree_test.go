@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestTreeCmd_Node(t *testing.T) {
+	home, err := ioutil.TempDir("", "tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	configDir := filepath.Join(home, "formulas", "aws", "create")
+	if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	config := `{"inputs": [{"name": "region", "type": "text", "label": "Region: "}]}`
+	if err := ioutil.WriteFile(filepath.Join(configDir, "config.json"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	treeMock := treeMock{
+		tree: formula.Tree{
+			Commands: api.Commands{
+				{Parent: "root", Usage: "aws", Help: "aws formulas", Repo: "commons"},
+				{Parent: "root_aws", Usage: "create", Help: "create formula", Repo: "commons", Formula: &api.Formula{Path: "aws/create"}},
+				{Parent: "root_aws", Usage: "delete", Help: "delete formula", Repo: "commons", Formula: &api.Formula{Path: "aws/delete"}},
+			},
+		},
+	}
+
+	rootCmd := &cobra.Command{Use: "rit"}
+	formulaCmd := NewFormulaCommand(api.CoreCmds, treeMock, runnerMock{}, runnerMock{}, runnerMock{}, runnerMock{}, pullerMock{}, sessionStopperMock{}, configLoaderMock{}, nil, RunDefaults{})
+	if err := formulaCmd.Add(rootCmd); err != nil {
+		t.Fatalf("Add got %v, want nil", err)
+	}
+	rootCmd.AddCommand(NewTreeCmd(home, formulaCmd))
+
+	tree := treeCmd{homePath: home, FormulaFinder: formulaCmd}.node(rootCmd)
+	if tree.Name != "rit" || tree.Runnable || len(tree.Commands) != 2 {
+		t.Fatalf("node got root %+v, want rit with the aws and tree commands", tree)
+	}
+
+	aws := tree.Commands[0]
+	if aws.Path != "rit aws" || aws.Formula || len(aws.Commands) != 2 {
+		t.Fatalf("node got %+v, want the aws group with 2 formulas", aws)
+	}
+
+	create := aws.Commands[0]
+	if !create.Formula || !create.Runnable || create.Repo != "commons" || create.Description != "create formula" {
+		t.Errorf("node got %+v, want the runnable formula rit aws create", create)
+	}
+	if len(create.Inputs) != 1 || create.Inputs[0].Name != "region" {
+		t.Errorf("node got inputs %+v, want the region input of the downloaded config", create.Inputs)
+	}
+	if len(create.Flags) == 0 {
+		t.Error("node got no flags for the formula")
+	}
+
+	if del := aws.Commands[1]; del.Inputs != nil {
+		t.Errorf("node got inputs %+v for a formula without downloaded config, want none", del.Inputs)
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	msgFormulaUpdated     = "The formula %q was updated"
+	msgFormulaFullUpdate  = "The repository of %q can't update a single formula, updating the repositories instead"
+	descUpdateFormulaLong = `Download the files of a formula again from its repository, without updating the whole repository.
+The input caches of the formula are kept. When the repository can't serve the files of a
+single formula, the repositories are updated instead, as by rit update repo.`
+)
+
+// updateFormulaCmd type for update formula command
+type updateFormulaCmd struct {
+	FormulaFinder
+	refresher formula.Refresher
+	updater   formula.RepoUpdater
+}
+
+// NewUpdateFormulaCmd creates a new cmd instance
+func NewUpdateFormulaCmd(ff FormulaFinder, rf formula.Refresher, up formula.RepoUpdater) *cobra.Command {
+	u := updateFormulaCmd{ff, rf, up}
+
+	cmd := &cobra.Command{
+		Use:     "formula FORMULA PATH",
+		Short:   "Update a single formula",
+		Long:    descUpdateFormulaLong,
+		Example: "rit update formula aws create",
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    u.runFunc(),
+	}
+
+	return cmd
+}
+
+func (u updateFormulaCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		path := strings.Join(append([]string{cmdUse}, args...), " ")
+		c, ok := u.Formula(path)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrFormulaNotFound, path)
+		}
+
+		err := u.refresher.Refresh(definition(path, c.Repo, *c.Formula))
+		if errors.Is(err, formula.ErrRefreshUnsupported) {
+			prompt.Warning(fmt.Sprintf(msgFormulaFullUpdate, path))
+			return u.updater.Update(false)
+		} else if err != nil {
+			return err
+		}
+
+		prompt.Success(fmt.Sprintf(msgFormulaUpdated, path))
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+type formulaFinderMock map[string]api.Command
+
+func (m formulaFinderMock) Formula(path string) (api.Command, bool) {
+	c, ok := m[path]
+	return c, ok
+}
+
+type refresherMock struct {
+	err  error
+	defs *[]formula.Definition
+}
+
+func (r refresherMock) Refresh(def formula.Definition) error {
+	*r.defs = append(*r.defs, def)
+	return r.err
+}
+
+type repoUpdaterSpy struct {
+	updates *int
+}
+
+func (r repoUpdaterSpy) Update(bool) error {
+	*r.updates++
+	return nil
+}
+
+func TestUpdateFormulaCmd(t *testing.T) {
+	finder := formulaFinderMock{
+		"rit aws create": {Parent: "root_aws", Usage: "create", Repo: "commons", Formula: &api.Formula{Path: "aws/create", RepoURL: "https://commons"}},
+	}
+	errDownload := errors.New("download failed")
+
+	tests := []struct {
+		name        string
+		args        []string
+		refreshErr  error
+		wantErr     error
+		wantRefresh bool
+		wantUpdates int
+	}{
+		{name: "refreshes the formula", args: []string{"aws", "create"}, wantRefresh: true},
+		{name: "falls back to the repo update", args: []string{"aws", "create"}, refreshErr: formula.ErrRefreshUnsupported, wantRefresh: true, wantUpdates: 1},
+		{name: "refresh fails", args: []string{"aws", "create"}, refreshErr: errDownload, wantErr: errDownload, wantRefresh: true},
+		{name: "formula not found", args: []string{"aws", "delete"}, wantErr: ErrFormulaNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var defs []formula.Definition
+			var updates int
+			cmd := NewUpdateFormulaCmd(finder, refresherMock{err: tt.refreshErr, defs: &defs}, repoUpdaterSpy{updates: &updates})
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			cmd.SetArgs(tt.args)
+
+			if err := cmd.Execute(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("%s error = %v, want %v", cmd.Use, err, tt.wantErr)
+			}
+			if tt.wantRefresh && (len(defs) != 1 || defs[0].Path != "aws/create" || defs[0].RepoName != "commons") {
+				t.Errorf("%s refreshed %+v, want the aws/create formula of commons", cmd.Use, defs)
+			}
+			if updates != tt.wantUpdates {
+				t.Errorf("%s updated the repos %d times, want %d", cmd.Use, updates, tt.wantUpdates)
+			}
+		})
+	}
+}
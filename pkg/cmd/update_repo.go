@@ -9,25 +9,43 @@ import (
 // updateRepoCmd type for update command
 type updateRepoCmd struct {
 	formula.RepoUpdater
+	planner formula.RepoPlanner
 }
 
 // NewUpdateRepoCmd creates a new cmd instance
-func NewUpdateRepoCmd(up formula.RepoUpdater) *cobra.Command {
-	u := &updateRepoCmd{up}
+func NewUpdateRepoCmd(up formula.RepoUpdater, rp formula.RepoPlanner) *cobra.Command {
+	u := &updateRepoCmd{up, rp}
 
 	cmd := &cobra.Command{
 		Use:     "repo",
 		Short:   "Update all repositories",
-		Example: "rit update repo",
+		Long:    "Update all repositories, the ones locked by rit repo lock are skipped unless --force is used",
+		Example: "rit update repo\nrit update repo --force\nrit update repo --dry-run --output json\nrit update repo --dry-run --json",
 		RunE:    u.runFunc(),
 	}
+	cmd.Flags().Bool(forceFlag, false, "Update the locked repositories too")
+	addDryRunFlags(cmd)
 
 	return cmd
 }
 
 func (u updateRepoCmd) runFunc() CommandRunnerFunc {
 	return func(cmd *cobra.Command, args []string) error {
-		if err := u.Update(); err != nil {
+		dry, err := dryRun(cmd)
+		if err != nil {
+			return err
+		}
+
+		force := boolFlag(cmd, forceFlag)
+		if dry {
+			plans, err := u.planner.PlanUpdate(force)
+			if err != nil {
+				return err
+			}
+			return printRepoPlans(cmd, plans...)
+		}
+
+		if err := u.Update(force); err != nil {
 			return err
 		}
 
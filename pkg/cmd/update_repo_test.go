@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
 )
 
 func TestNewUpdateRepoCmd(t *testing.T) {
-	cmd := NewUpdateRepoCmd(repoUpdaterMock{})
+	cmd := NewUpdateRepoCmd(repoUpdaterMock{}, repoPlannerMock{})
 	cmd.PersistentFlags().Bool("stdin", false, "input by stdin")
 	if cmd == nil {
 		t.Errorf("NewUpdateRepoCmd got %v", cmd)
@@ -15,3 +17,125 @@ func TestNewUpdateRepoCmd(t *testing.T) {
 		t.Errorf("%s = %v, want %v", cmd.Use, err, nil)
 	}
 }
+
+func TestRepoPlanText(t *testing.T) {
+	tests := []struct {
+		name string
+		plan formula.RepoPlan
+		want string
+	}{
+		{
+			name: "update with changes",
+			plan: formula.RepoPlan{
+				Operation:    formula.RepoUpdate,
+				Repo:         "commons",
+				TreePath:     "https://commons/tree.json",
+				DownloadSize: 2048,
+				Added:        []string{"rit aws create"},
+				Removed:      []string{"rit aws old"},
+			},
+			want: "update repo \"commons\" (https://commons/tree.json)\n  download: 2.0 KB\n" +
+				"  formulas added: rit aws create\n  formulas removed: rit aws old",
+		},
+		{
+			name: "add without changes",
+			plan: formula.RepoPlan{Operation: formula.RepoAdd, Repo: "acme", TreePath: "https://acme", Replaces: true, DownloadSize: 10},
+			want: "add repo \"acme\" (https://acme)\n  replaces the repository with the same name\n  download: 10 B\n  no formula changes",
+		},
+		{
+			name: "locked repository",
+			plan: formula.RepoPlan{Operation: formula.RepoUpdate, Repo: "acme", TreePath: "https://acme", Locked: true},
+			want: "update repo \"acme\" (https://acme)\n  locked, skipped without --force",
+		},
+		{
+			name: "unreachable repository",
+			plan: formula.RepoPlan{Operation: formula.RepoUpdate, Repo: "acme", TreePath: "https://acme", Error: "404 - failed\n"},
+			want: "update repo \"acme\" (https://acme)\n  unable to reach the repository: 404 - failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repoPlanText(tt.plan); got != tt.want {
+				t.Errorf("repoPlanText got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateRepoCmd_PlanJSON(t *testing.T) {
+	plans := []formula.RepoPlan{
+		{Operation: formula.RepoUpdate, Repo: "commons", FromVersion: "sha256:a", ToVersion: "sha256:b", DownloadSize: 2048, Formulas: 3},
+		{Operation: formula.RepoUpdate, Repo: "same", FromVersion: "sha256:c", ToVersion: "sha256:c", DownloadSize: 10, Formulas: 1},
+		{Operation: formula.RepoUpdate, Repo: "acme", FromVersion: "sha256:d", Locked: true},
+		{Operation: formula.RepoUpdate, Repo: "down", FromVersion: "sha256:e", Error: "404 - failed\n"},
+	}
+	want := `{
+  "schemaVersion": 1,
+  "actions": [
+    {
+      "action": "update",
+      "repo": "commons",
+      "fromVersion": "sha256:a",
+      "toVersion": "sha256:b",
+      "downloadSize": 2048,
+      "estimatedFiles": 4
+    },
+    {
+      "action": "noop",
+      "repo": "same",
+      "fromVersion": "sha256:c",
+      "toVersion": "sha256:c",
+      "downloadSize": 10,
+      "estimatedFiles": 0
+    },
+    {
+      "action": "skip",
+      "repo": "acme",
+      "fromVersion": "sha256:d",
+      "toVersion": "",
+      "downloadSize": 0,
+      "estimatedFiles": 0
+    },
+    {
+      "action": "update",
+      "repo": "down",
+      "fromVersion": "sha256:e",
+      "toVersion": "",
+      "downloadSize": 0,
+      "estimatedFiles": 0,
+      "error": "404 - failed"
+    }
+  ]
+}
+`
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr error
+	}{
+		{name: "plan", args: []string{"--dry-run", "--json"}, want: want},
+		{name: "without dry run", args: []string{"--json"}, wantErr: ErrPlanJSONNeedsDryRun},
+		{name: "with output", args: []string{"--dry-run", "--json", "--output", "yaml"}, wantErr: ErrPlanJSONWithOutput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewUpdateRepoCmd(repoUpdaterMock{}, repoPlannerMock{plans: plans})
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			cmd.SetArgs(tt.args)
+
+			var err error
+			got := captureStdout(func() { err = cmd.Execute() })
+			if err != tt.wantErr {
+				t.Fatalf("update repo error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("update repo printed\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
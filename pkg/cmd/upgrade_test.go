@@ -26,14 +26,19 @@ func (uf stubUrlFinder) Url(edition api.Edition, resolver version.Resolver) stri
 }
 
 type stubVersionResolver struct {
-	stableVersion func() (string, error)
-	updateCache func() error
+	stableVersion       func() (string, error)
+	updateCache         func() error
+	remoteStableVersion func() (string, error)
 }
 
 func (vr stubVersionResolver) StableVersion() (string, error) {
 	return vr.stableVersion()
 }
 
+func (vr stubVersionResolver) RemoteStableVersion() (string, error) {
+	return vr.remoteStableVersion()
+}
+
 func (vr stubVersionResolver) UpdateCache() error {
 	return vr.updateCache()
 }
@@ -61,6 +66,9 @@ func TestUpgradeCmd_runFunc(t *testing.T) {
 					func() error {
 						return nil
 					},
+					func() (string, error) {
+						return "1.0.0", nil
+					},
 				},
 				Manager: stubUpgradeManager{
 					func(upgradeUrl string) error {
@@ -85,6 +93,9 @@ func TestUpgradeCmd_runFunc(t *testing.T) {
 					func() error {
 						return errors.New("some error")
 					},
+					func() (string, error) {
+						return "", nil
+					},
 				},
 				Manager: stubUpgradeManager{
 					func(upgradeUrl string) error {
@@ -109,6 +120,9 @@ func TestUpgradeCmd_runFunc(t *testing.T) {
 					func() error {
 						return nil
 					},
+					func() (string, error) {
+						return "", nil
+					},
 				},
 				Manager: stubUpgradeManager{
 					func(upgradeUrl string) error {
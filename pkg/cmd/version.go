@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/version"
+)
+
+const (
+	shortFlag       = "short"
+	checkRemoteFlag = "check-remote"
+)
+
+// versionCmd type for version command
+type versionCmd struct {
+	edition  api.Edition
+	resolver version.Resolver
+}
+
+// NewVersionCmd creates a new cmd instance of version command
+func NewVersionCmd(e api.Edition, r version.Resolver) *cobra.Command {
+	v := versionCmd{edition: e, resolver: r}
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print rit version",
+		Long: `Print rit version, build date and the latest stable version.
+Use --short to print only the version number, e.g. v=$(rit version --short)
+Use --check-remote to skip the cached stable version and fetch it from the server`,
+		RunE: v.runFunc(),
+	}
+	cmd.Flags().Bool(shortFlag, false, "Print only the version number")
+	cmd.Flags().Bool(checkRemoteFlag, false, "Fetch the latest stable version from the server, refreshing its cache")
+	addOutputFlag(cmd, "version")
+
+	return cmd
+}
+
+func (v versionCmd) runFunc() CommandRunnerFunc {
+	return func(cmd *cobra.Command, args []string) error {
+		short, err := cmd.Flags().GetBool(shortFlag)
+		if err != nil {
+			return err
+		}
+
+		output, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+
+		latestVersion := v.resolver.StableVersion
+		if boolFlag(cmd, checkRemoteFlag) {
+			remote, err := v.resolver.RemoteStableVersion()
+			if err != nil {
+				return err
+			}
+			latestVersion = func() (string, error) { return remote, nil }
+		}
+
+		if output != "" {
+			return printOutput(output, newVersionOutput(v.edition, latestVersion))
+		}
+
+		if short {
+			fmt.Fprintln(cmd.OutOrStdout(), Version)
+			return nil
+		}
+
+		fmt.Fprint(cmd.OutOrStdout(), versionMessage(v.edition, latestVersion))
+		return nil
+	}
+}
+
+// versionOutput is the output of rit version --output
+type versionOutput struct {
+	Version       string `json:"version"`
+	Edition       string `json:"edition"`
+	BuildDate     string `json:"buildDate"`
+	GoVersion     string `json:"goVersion"`
+	LatestVersion string `json:"latestVersion,omitempty"`
+}
+
+func newVersionOutput(edition api.Edition, latestVersion func() (string, error)) versionOutput {
+	v := versionOutput{
+		Version:   Version,
+		Edition:   string(edition),
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+	}
+	if latest, err := latestVersion(); err == nil {
+		v.LatestVersion = latest
+	}
+	return v
+}
+
+// versionMessage builds the full version message, it shows the latest
+// stable version when it can be resolved and differs from the current one
+func versionMessage(edition api.Edition, latestVersion func() (string, error)) string {
+	latest, err := latestVersion()
+	if err == nil && latest != Version {
+		formattedLatestVersionMsg := prompt.Yellow(fmt.Sprintf(latestVersionMsg, latest))
+		return fmt.Sprintf(versionMsgWithLatestVersion, Version, edition, formattedLatestVersionMsg, BuildDate, runtime.Version())
+	}
+	return fmt.Sprintf(versionMsg, Version, edition, BuildDate, runtime.Version())
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+)
+
+func TestVersionCmd(t *testing.T) {
+	offline := stubVersionResolver{
+		stableVersion: func() (string, error) {
+			t.Error("rit version --short should not resolve the stable version")
+			return "", nil
+		},
+	}
+	online := stubVersionResolver{
+		stableVersion: func() (string, error) {
+			return "9.9.9", nil
+		},
+	}
+
+	remote := stubVersionResolver{
+		stableVersion: func() (string, error) {
+			t.Error("rit version --check-remote should not read the cached stable version")
+			return "9.9.9", nil
+		},
+		remoteStableVersion: func() (string, error) {
+			return "10.0.0", nil
+		},
+	}
+
+	tests := []struct {
+		name     string
+		resolver stubVersionResolver
+		args     []string
+		want     string
+	}{
+		{
+			name:     "short",
+			resolver: offline,
+			args:     []string{"--short"},
+			want:     Version + "\n",
+		},
+		{
+			name:     "full",
+			resolver: online,
+			args:     []string{},
+			want:     "9.9.9",
+		},
+		{
+			name:     "check remote",
+			resolver: remote,
+			args:     []string{"--check-remote"},
+			want:     "10.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			cmd := NewVersionCmd(api.Single, tt.resolver)
+			cmd.SetOut(&out)
+			cmd.SetArgs(tt.args)
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("%s error = %v", cmd.Use, err)
+			}
+
+			if tt.name == "short" && out.String() != tt.want {
+				t.Errorf("rit version --short got %q, want %q", out.String(), tt.want)
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("rit version got %q, want it to contain %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionCmd_CheckRemoteError(t *testing.T) {
+	errUnreachable := errors.New("unreachable")
+	resolver := stubVersionResolver{
+		remoteStableVersion: func() (string, error) {
+			return "", errUnreachable
+		},
+	}
+
+	cmd := NewVersionCmd(api.Single, resolver)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--check-remote"})
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	if err := cmd.Execute(); !errors.Is(err, errUnreachable) {
+		t.Errorf("rit version --check-remote error = %v, want %v", err, errUnreachable)
+	}
+}
@@ -0,0 +1,363 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ConfigPath is the global config file pattern, relative to the ritchie home
+	ConfigPath = "%s/config.json"
+	// PlainKey enables the plain output mode, see prompt.SetPlain
+	PlainKey = "accessibility.plain"
+	// ThemeKey is the theme of the output and the prompts, see prompt.SetTheme
+	ThemeKey = "theme.name"
+	// ThemeColorsKey replaces the colors of the theme, role=color comma separated
+	ThemeColorsKey = "theme.colors"
+	// ThemeSymbolsKey replaces the symbols of the theme, role=symbol comma separated
+	ThemeSymbolsKey = "theme.symbols"
+	// RunLogsKey enables the formula run logs read by rit logs
+	RunLogsKey = "logs.enabled"
+	// HistoryKey enables the history of the formula runs read by rit history
+	HistoryKey = "history.enabled"
+	// LogLevelKey is the level of the rit messages of the runs without --log-level or --quiet
+	LogLevelKey = "log.level"
+	// InputsRequiredFirstKey prompts the required formula inputs before the optional ones
+	InputsRequiredFirstKey = "inputs.required-first"
+	// InputsLocaleKey is the locale of the formula input labels, see formula.Input.Localize
+	InputsLocaleKey = "inputs.locale"
+	// TLSClientCertKey is the client certificate file presented to the repo and version servers
+	TLSClientCertKey = "tls.client-cert"
+	// TLSClientKeyKey is the private key file of the client certificate
+	TLSClientKeyKey = "tls.client-key"
+	// TLSCAKey is a CA bundle file trusted besides the system ones
+	TLSCAKey = "tls.ca"
+	// NetworkTimeoutKey is the timeout of each network request, replacing the default of every operation
+	NetworkTimeoutKey = "network.timeout"
+	// NetworkRetriesKey is how many times a failed network request is retried
+	NetworkRetriesKey = "network.retries"
+	// FormulaRunnerKey is the container engine of the formula runs, docker or podman, or a runner plugin
+	FormulaRunnerKey = "formula.runner"
+	// FormulaTimeoutKey is the timeout of the formula runs without --timeout
+	FormulaTimeoutKey = "formula.timeout"
+	// FormulaVolumesKey are the host paths the formula containers may mount, comma separated
+	FormulaVolumesKey = "formula.volumes"
+	// KubernetesNamespaceKey is the namespace of the formula jobs run with --kubernetes
+	KubernetesNamespaceKey = "kubernetes.namespace"
+	// KubernetesServiceAccountKey is the service account of the formula jobs
+	KubernetesServiceAccountKey = "kubernetes.service-account"
+	// KubernetesKubeconfigKey is the kubeconfig file of the cluster running the formula jobs
+	KubernetesKubeconfigKey = "kubernetes.kubeconfig"
+	// KubernetesRegistryKey is the registry the formula images are pushed to for the cluster
+	KubernetesRegistryKey = "kubernetes.registry"
+)
+
+var (
+	// ErrUnknownKey error for a key not supported by the config
+	ErrUnknownKey = errors.New("unknown config key")
+
+	boolValues   = []string{"true", "false"}
+	runnerValues = []string{"docker", "podman"}
+	levelValues  = []string{"debug", "info", "warn", "error"}
+	themeValues  = []string{"default", "light", "high-contrast"}
+
+	// localeName is a locale as pt-BR, es or zh_Hant_TW
+	localeName = regexp.MustCompile(`^[a-zA-Z]{2,3}([-_][a-zA-Z0-9]{2,8})*$`)
+
+	// kubernetesName is a name of the namespaces and the service accounts
+	kubernetesName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+	// Keys are the config keys supported by rit with their value validation
+	Keys = map[string]Key{
+		PlainKey: {
+			Usage:    "Plain output without spinners, colors and unicode glyphs [true|false]",
+			Values:   boolValues,
+			Validate: isBool,
+		},
+		ThemeKey: {
+			Usage:    "Theme of the output and the prompts, light for the light terminals [default|light|high-contrast]",
+			Default:  "default",
+			Values:   themeValues,
+			Validate: oneOf(themeValues),
+		},
+		ThemeColorsKey: {
+			Usage:    "Colors replacing the ones of the theme by role, e.g. warning=magenta+bold,answer=blue, the roles are error, success, warning, info, debug, question, message, answer, default, select, marked and unmarked",
+			Validate: isAssignmentList,
+		},
+		ThemeSymbolsKey: {
+			Usage:    "Symbols replacing the ones of the theme by role, e.g. select=>>,marked=[*], the roles are question, error, select, marked and unmarked",
+			Validate: isAssignmentList,
+		},
+		RunLogsKey: {
+			Usage:    "Keep the output of the latest formula runs [true|false]",
+			Default:  "false",
+			Values:   boolValues,
+			Validate: isBool,
+		},
+		HistoryKey: {
+			Usage:    "Record every formula run on the history [true|false]",
+			Default:  "true",
+			Values:   boolValues,
+			Validate: isBool,
+		},
+		LogLevelKey: {
+			Usage:    "Least severe rit messages printed, --log-level replaces it and --quiet is error [debug|info|warn|error]",
+			Default:  "info",
+			Values:   levelValues,
+			Validate: oneOf(levelValues),
+		},
+		InputsRequiredFirstKey: {
+			Usage:    "Ask the required formula inputs before the optional ones [true|false]",
+			Values:   boolValues,
+			Validate: isBool,
+		},
+		InputsLocaleKey: {
+			Usage:    "Locale of the formula input labels and tutorials, e.g. pt-BR or es, the one of LANG when not set",
+			Validate: isLocale,
+		},
+		TLSClientCertKey: {
+			Usage:    "PEM client certificate file for servers requiring mutual TLS, needs tls.client-key",
+			Validate: isFile,
+		},
+		TLSClientKeyKey: {
+			Usage:    "PEM private key file of tls.client-cert",
+			Validate: isFile,
+		},
+		TLSCAKey: {
+			Usage:    "PEM CA bundle file trusted besides the system CAs",
+			Validate: isFile,
+		},
+		NetworkTimeoutKey: {
+			Usage:    "Timeout of the network requests, as the version check and the downloads, e.g. 10s",
+			Validate: isDuration,
+		},
+		FormulaRunnerKey: {
+			Usage:    "Container engine of the formulas run with --docker or --session, or a runner plugin running every formula run [docker|podman]",
+			Default:  "docker",
+			Values:   runnerValues,
+			Validate: oneOf(runnerValues),
+		},
+		FormulaTimeoutKey: {
+			Usage:    "Timeout of the formula runs, they are stopped and cleaned up when they run for longer, e.g. 30m",
+			Validate: isDuration,
+		},
+		FormulaVolumesKey: {
+			Usage:    "Host paths the formula containers may mount with --volume or the volumes of config.json, comma separated, e.g. ~/.kube,~/.ssh",
+			Validate: isPathList,
+		},
+		NetworkRetriesKey: {
+			Usage:    "Times a network request is retried when it fails or the server is unavailable",
+			Default:  "0",
+			Validate: isNonNegativeInt,
+		},
+		KubernetesNamespaceKey: {
+			Usage:    "Namespace of the formula jobs run with --kubernetes, the one of the kubectl context when not set",
+			Validate: isKubernetesName,
+		},
+		KubernetesServiceAccountKey: {
+			Usage:    "Service account of the formula jobs, the default one of the namespace when not set",
+			Validate: isKubernetesName,
+		},
+		KubernetesKubeconfigKey: {
+			Usage:    "Kubeconfig file of the cluster running the formula jobs, the one of kubectl when not set",
+			Validate: isFile,
+		},
+		KubernetesRegistryKey: {
+			Usage:    "Registry the formula images are pushed to for the cluster to pull them, e.g. ghcr.io/team",
+			Validate: isRegistry,
+		},
+	}
+)
+
+// Key describes a supported config key, Default is the value of a key not set
+// and Values, when set, are the only values the key accepts, e.g. to complete them
+type Key struct {
+	Usage    string
+	Default  string
+	Values   []string
+	Validate func(value string) error
+}
+
+// Config holds the global rit settings by key
+type Config map[string]string
+
+type Finder interface {
+	Find() (Config, error)
+}
+
+type Setter interface {
+	Set(key, value string) error
+}
+
+type FindSetter interface {
+	Finder
+	Setter
+}
+
+// Get returns the key value or its default when it isn't set
+func (c Config) Get(key string) string {
+	if v, ok := c[key]; ok {
+		return v
+	}
+	return Keys[key].Default
+}
+
+// Bool returns the key value as bool, false when the value isn't a bool
+func (c Config) Bool(key string) bool {
+	b, err := strconv.ParseBool(c.Get(key))
+	return err == nil && b
+}
+
+// Duration returns the key value as duration, zero when the value isn't a duration
+func (c Config) Duration(key string) time.Duration {
+	d, _ := time.ParseDuration(c.Get(key))
+	return d
+}
+
+// Int returns the key value as int, zero when the value isn't an int
+func (c Config) Int(key string) int {
+	i, _ := strconv.Atoi(c.Get(key))
+	return i
+}
+
+// List returns the key value split on the commas, empty when it isn't set
+func (c Config) List(key string) []string {
+	var list []string
+	for _, v := range strings.Split(c.Get(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// AddRunner makes the formula.runner key accept the name of a runner plugin
+func AddRunner(name string) {
+	k := Keys[FormulaRunnerKey]
+	k.Values = append(append([]string{}, k.Values...), name)
+	k.Validate = oneOf(k.Values)
+	Keys[FormulaRunnerKey] = k
+}
+
+// KeyNames returns the supported config keys sorted by name
+func KeyNames() []string {
+	var names []string
+	for k := range Keys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks whether the key is supported and the value valid for it
+func Validate(key, value string) error {
+	k, ok := Keys[key]
+	if !ok {
+		return fmt.Errorf("%w %q, use one of %v", ErrUnknownKey, key, KeyNames())
+	}
+
+	if err := k.Validate(value); err != nil {
+		return fmt.Errorf("invalid value %q for %s: %w", value, key, err)
+	}
+	return nil
+}
+
+func isBool(value string) error {
+	if _, err := strconv.ParseBool(value); err != nil {
+		return errors.New("must be true or false")
+	}
+	return nil
+}
+
+// isDuration accepts a positive duration or an empty value, which unsets the key
+func isDuration(value string) error {
+	if value == "" {
+		return nil
+	}
+	if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+		return errors.New("must be a positive duration, e.g. 10s or 1m")
+	}
+	return nil
+}
+
+// isPathList accepts comma separated absolute paths, or starting with ~, or an empty value,
+// which unsets the key
+func isPathList(value string) error {
+	for _, p := range strings.Split(value, ",") {
+		p = strings.TrimSpace(p)
+		if p != "" && p != "~" && !strings.HasPrefix(p, "~/") && !filepath.IsAbs(p) {
+			return errors.New("must be absolute paths or paths starting with ~, comma separated")
+		}
+	}
+	return nil
+}
+
+// isAssignmentList accepts comma separated name=value pairs or an empty value, which unsets the key
+func isAssignmentList(value string) error {
+	for _, a := range strings.Split(value, ",") {
+		if a = strings.TrimSpace(a); a != "" && strings.Index(a, "=") < 1 {
+			return errors.New("must be name=value pairs, comma separated")
+		}
+	}
+	return nil
+}
+
+func isNonNegativeInt(value string) error {
+	if i, err := strconv.Atoi(value); err != nil || i < 0 {
+		return errors.New("must be a number not lower than 0")
+	}
+	return nil
+}
+
+func oneOf(values []string) func(string) error {
+	return func(value string) error {
+		for _, v := range values {
+			if value == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %v", values)
+	}
+}
+
+// isFile accepts the absolute path of an existing file or an empty value, which unsets the key
+func isFile(value string) error {
+	if value == "" {
+		return nil
+	}
+	if info, err := os.Stat(value); err != nil || info.IsDir() || !filepath.IsAbs(value) {
+		return errors.New("must be the absolute path of an existing file")
+	}
+	return nil
+}
+
+// isKubernetesName accepts a name of a namespace or a service account or an empty value, which unsets the key
+func isKubernetesName(value string) error {
+	if value != "" && !kubernetesName.MatchString(value) {
+		return errors.New("must be lowercase letters, numbers and dashes, up to 63 chars")
+	}
+	return nil
+}
+
+// isLocale accepts a locale or an empty value, which unsets the key
+func isLocale(value string) error {
+	if value != "" && !localeName.MatchString(value) {
+		return errors.New("must be a locale, e.g. pt-BR or es")
+	}
+	return nil
+}
+
+// isRegistry accepts a registry with an optional path, without the scheme, or an empty value
+func isRegistry(value string) error {
+	if strings.Contains(value, "://") || strings.ContainsAny(value, " \t@") {
+		return errors.New("must be a registry without the scheme, e.g. ghcr.io/team")
+	}
+	return nil
+}
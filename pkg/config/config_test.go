@@ -0,0 +1,117 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSetAndFind(t *testing.T) {
+	tmp := filepath.Join(os.TempDir(), "rit-config")
+	_ = os.RemoveAll(tmp)
+	if err := os.MkdirAll(tmp, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	finder := NewFinder(tmp)
+	setter := NewSetter(tmp, finder)
+
+	cfg, err := finder.Find()
+	if err != nil || len(cfg) != 0 {
+		t.Fatalf("Find() without config file got %v, %v, want empty config", cfg, err)
+	}
+
+	if cfg.Bool(RunLogsKey) {
+		t.Errorf("Bool(%s) without config got true, want the default false", RunLogsKey)
+	}
+	if !cfg.Bool(HistoryKey) {
+		t.Errorf("Bool(%s) without config got false, want the default true", HistoryKey)
+	}
+
+	if err := setter.Set(PlainKey, "true"); err != nil {
+		t.Fatalf("Set(%s) got %v, want nil", PlainKey, err)
+	}
+
+	cfg, err = finder.Find()
+	if err != nil {
+		t.Fatalf("Find() got %v, want nil", err)
+	}
+	if !cfg.Bool(PlainKey) {
+		t.Errorf("Bool(%s) got false, want true", PlainKey)
+	}
+
+	cfg = Config{NetworkTimeoutKey: "10s", NetworkRetriesKey: "5"}
+	if d, r := cfg.Duration(NetworkTimeoutKey), cfg.Int(NetworkRetriesKey); d != 10*time.Second || r != 5 {
+		t.Errorf("Duration and Int got %v and %d, want 10s and 5", d, r)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{name: "valid bool", key: PlainKey, value: "false"},
+		{name: "invalid bool", key: PlainKey, value: "sometimes", wantErr: true},
+		{name: "unknown key", key: "color.theme", value: "dark", wantErr: true},
+		{name: "existing file", key: TLSCAKey, value: os.Args[0]},
+		{name: "unset file", key: TLSCAKey, value: ""},
+		{name: "missing file", key: TLSCAKey, value: "/rit/missing.pem", wantErr: true},
+		{name: "relative file", key: TLSCAKey, value: "ca.pem", wantErr: true},
+		{name: "duration", key: NetworkTimeoutKey, value: "10s"},
+		{name: "unset duration", key: NetworkTimeoutKey, value: ""},
+		{name: "invalid duration", key: NetworkTimeoutKey, value: "10", wantErr: true},
+		{name: "negative duration", key: NetworkTimeoutKey, value: "-1s", wantErr: true},
+		{name: "retries", key: NetworkRetriesKey, value: "5"},
+		{name: "formula timeout", key: FormulaTimeoutKey, value: "30m"},
+		{name: "podman runner", key: FormulaRunnerKey, value: "podman"},
+		{name: "unknown runner", key: FormulaRunnerKey, value: "containerd", wantErr: true},
+		{name: "volumes", key: FormulaVolumesKey, value: "~/.kube, /etc/ssl/certs"},
+		{name: "relative volume", key: FormulaVolumesKey, value: "~/.kube,certs", wantErr: true},
+		{name: "negative retries", key: NetworkRetriesKey, value: "-1", wantErr: true},
+		{name: "namespace", key: KubernetesNamespaceKey, value: "formulas-prod"},
+		{name: "invalid namespace", key: KubernetesNamespaceKey, value: "Formulas", wantErr: true},
+		{name: "registry with a port", key: KubernetesRegistryKey, value: "localhost:5000/team"},
+		{name: "registry with the scheme", key: KubernetesRegistryKey, value: "https://ghcr.io/team", wantErr: true},
+		{name: "locale", key: InputsLocaleKey, value: "pt-BR"},
+		{name: "unset locale", key: InputsLocaleKey, value: ""},
+		{name: "invalid locale", key: InputsLocaleKey, value: "pt BR", wantErr: true},
+		{name: "theme", key: ThemeKey, value: "high-contrast"},
+		{name: "unknown theme", key: ThemeKey, value: "solarized", wantErr: true},
+		{name: "theme colors", key: ThemeColorsKey, value: "warning=magenta+bold, answer=blue"},
+		{name: "theme colors without a role", key: ThemeColorsKey, value: "magenta", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%s) got %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := Validate("color.theme", "dark"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Validate(unknown key) got %v, want %v", err, ErrUnknownKey)
+	}
+}
+
+func TestAddRunner(t *testing.T) {
+	defer func(k Key) { Keys[FormulaRunnerKey] = k }(Keys[FormulaRunnerKey])
+
+	AddRunner("nomad")
+	if err := Validate(FormulaRunnerKey, "nomad"); err != nil {
+		t.Errorf("Validate(nomad) got %v, want the runner plugin accepted", err)
+	}
+	if err := Validate(FormulaRunnerKey, "podman"); err != nil {
+		t.Errorf("Validate(podman) got %v, want the engines still accepted", err)
+	}
+	if len(runnerValues) != 2 {
+		t.Errorf("AddRunner() changed the engines to %v", runnerValues)
+	}
+}
@@ -0,0 +1,10 @@
+package config
+
+type FindSetterManager struct {
+	Finder
+	Setter
+}
+
+func NewFindSetter(f Finder, s Setter) FindSetterManager {
+	return FindSetterManager{Finder: f, Setter: s}
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+)
+
+type FindManager struct {
+	configFile string
+}
+
+func NewFinder(homePath string) FindManager {
+	return FindManager{configFile: fmt.Sprintf(ConfigPath, homePath)}
+}
+
+func (f FindManager) Find() (Config, error) {
+	cfg := Config{}
+
+	if !fileutil.Exists(f.configFile) {
+		return cfg, nil
+	}
+
+	file, err := fileutil.ReadFile(f.configFile)
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(file, &cfg); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
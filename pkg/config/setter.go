@@ -0,0 +1,36 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+)
+
+type SetterManager struct {
+	configFile string
+	finder     Finder
+}
+
+func NewSetter(homePath string, f Finder) SetterManager {
+	return SetterManager{configFile: fmt.Sprintf(ConfigPath, homePath), finder: f}
+}
+
+func (s SetterManager) Set(key, value string) error {
+	if err := Validate(key, value); err != nil {
+		return err
+	}
+
+	cfg, err := s.finder.Find()
+	if err != nil {
+		return err
+	}
+	cfg[key] = value
+
+	b, err := json.MarshalIndent(cfg, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return fileutil.WriteFilePerm(s.configFile, b, 0600)
+}
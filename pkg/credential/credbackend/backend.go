@@ -0,0 +1,70 @@
+package credbackend
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ZupIT/ritchie-cli/pkg/credential"
+)
+
+const (
+	// BackendsFile lists the external credential backends, relative to the ritchie home
+	BackendsFile = "credential_backends.json"
+	// ExecType is the type of the backends running a command to fetch the credential
+	ExecType = "exec"
+)
+
+// Config configures a backend of BackendsFile, as
+// [{"name": "vault", "type": "exec", "command": "vault kv get -field=$RIT_CREDENTIAL_FIELD secret/$RIT_CREDENTIAL_SERVICE"}]
+type Config struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Command string `json:"command"`
+}
+
+// Factory creates the backend of a config
+type Factory func(c Config) (credential.Backend, error)
+
+// factories are the backend types by name, new managers plug in with Register
+var factories = map[string]Factory{
+	ExecType: newExec,
+}
+
+// Register plugs a backend type in, replacing a type with the same name
+func Register(backendType string, f Factory) {
+	factories[backendType] = f
+}
+
+// Load creates the backends of BackendsFile in the order they are listed,
+// there are no backends when the file doesn't exist
+func Load(homePath string) ([]credential.Backend, error) {
+	b, err := ioutil.ReadFile(filepath.Join(homePath, BackendsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var configs []Config
+	if err := json.Unmarshal(b, &configs); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", BackendsFile, err)
+	}
+
+	backends := make([]credential.Backend, 0, len(configs))
+	for _, c := range configs {
+		f, ok := factories[c.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %q of the credential backend %q", c.Type, c.Name)
+		}
+
+		backend, err := f(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid credential backend %q: %v", c.Name, err)
+		}
+		backends = append(backends, backend)
+	}
+	return backends, nil
+}
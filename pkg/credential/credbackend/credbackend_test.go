@@ -0,0 +1,107 @@
+package credbackend
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/credential"
+)
+
+func TestExec_Fetch(t *testing.T) {
+	ref := credential.Reference{Service: "github", Field: "token"}
+
+	tests := []struct {
+		name    string
+		command string
+		want    string
+		wantErr error
+		fails   bool
+	}{
+		{
+			name:    "prints the secret",
+			command: `echo "$RIT_CREDENTIAL_SERVICE-$RIT_CREDENTIAL_FIELD-secret"`,
+			want:    "github-token-secret",
+		},
+		{
+			name:    "prints nothing",
+			command: "true",
+			wantErr: credential.ErrNotFound,
+		},
+		{
+			name:    "command fails",
+			command: "echo denied >&2; exit 2",
+			fails:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewExec("vault", tt.command).Fetch(ref)
+			if tt.fails {
+				if err == nil {
+					t.Fatal("Fetch got nil error, want the command failure")
+				}
+				return
+			}
+			if err != tt.wantErr || got != tt.want {
+				t.Errorf("Fetch got (%q, %v), want (%q, %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	home, err := ioutil.TempDir("", "credbackend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	backends, err := Load(home)
+	if err != nil || len(backends) != 0 {
+		t.Fatalf("Load without %s got (%v, %v), want no backends", BackendsFile, backends, err)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    int
+		wantErr bool
+	}{
+		{
+			name:    "exec backends",
+			content: `[{"name": "vault", "type": "exec", "command": "echo a"}, {"name": "aws", "type": "exec", "command": "echo b"}]`,
+			want:    2,
+		},
+		{
+			name:    "unknown type",
+			content: `[{"name": "vault", "type": "http"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "exec without command",
+			content: `[{"name": "vault", "type": "exec"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			content: `{`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ioutil.WriteFile(filepath.Join(home, BackendsFile), []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			backends, err := Load(home)
+			if (err != nil) != tt.wantErr || len(backends) != tt.want {
+				t.Errorf("Load got (%d backends, %v), want (%d, wantErr %v)", len(backends), err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package credbackend
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/credential"
+)
+
+const (
+	// ServiceEnv is the service of the referenced credential, as github
+	ServiceEnv = "RIT_CREDENTIAL_SERVICE"
+	// FieldEnv is the field of the referenced credential, as token
+	FieldEnv = "RIT_CREDENTIAL_FIELD"
+)
+
+// Exec fetches the credential running a command, which prints the secret on stdout.
+// The command gets the reference on the RIT_CREDENTIAL_SERVICE and RIT_CREDENTIAL_FIELD
+// envs, it prints nothing when the manager hasn't the credential and exits with a
+// nonzero code when it fails, so that any secret manager CLI can be integrated.
+type Exec struct {
+	name    string
+	command string
+}
+
+// NewExec creates the exec backend running the command with the system shell
+func NewExec(name, command string) Exec {
+	return Exec{name: name, command: command}
+}
+
+func newExec(c Config) (credential.Backend, error) {
+	if c.Command == "" {
+		return nil, errors.New("the command of an exec backend is required")
+	}
+	return NewExec(c.Name, c.Command), nil
+}
+
+func (e Exec) Fetch(ref credential.Reference) (string, error) {
+	cmd := shell(e.command)
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("%s=%s", ServiceEnv, ref.Service),
+		fmt.Sprintf("%s=%s", FieldEnv, ref.Field),
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("credential backend %q failed: %v %s", e.name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	secret := strings.TrimRight(stdout.String(), "\r\n")
+	if secret == "" {
+		return "", credential.ErrNotFound
+	}
+	return secret, nil
+}
+
+func shell(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
@@ -1,5 +1,7 @@
 package credential
 
+import "errors"
+
 const (
 	// Other credential path /admin
 	Other Type = "admin"
@@ -36,6 +38,21 @@ type Field struct {
 // Fields are used on single to represents providers.json
 type Fields map[string][]Field
 
+// ErrNotFound is returned by a backend without the referenced credential,
+// the next backend or the local store is consulted then
+var ErrNotFound = errors.New("credential not found")
+
+// Reference points to a credential field, CREDENTIAL_GITHUB_TOKEN references the field token of the service github
+type Reference struct {
+	Service string
+	Field   string
+}
+
+// Backend fetches credentials from an external secret manager, as Vault or AWS Secrets Manager, at run time
+type Backend interface {
+	Fetch(ref Reference) (string, error)
+}
+
 type Setter interface {
 	Set(d Detail) error
 }
@@ -44,6 +61,11 @@ type Finder interface {
 	Find(service string) (Detail, error)
 }
 
+// Lister lists the providers of the credentials the user can select, on the current context
+type Lister interface {
+	Providers() ([]string, error)
+}
+
 type Settings interface {
 	Fields() (Fields, error)
 }
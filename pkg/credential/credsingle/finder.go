@@ -55,3 +55,14 @@ func (f Finder) Find(provider string) (credential.Detail, error) {
 
 	return *cred, nil
 }
+
+// Providers returns the providers with a credential set on the current context
+func (f Finder) Providers() ([]string, error) {
+	ctx, err := f.ctxFinder.Find()
+	if err != nil {
+		return nil, err
+	} else if ctx.Current == "" {
+		ctx.Current = rcontext.DefaultCtx
+	}
+	return NewLister(f.homePath).Services(ctx.Current)
+}
@@ -2,6 +2,7 @@ package credsingle
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"reflect"
 	"testing"
@@ -54,3 +55,19 @@ func TestFind(t *testing.T) {
 		})
 	}
 }
+
+func TestProviders(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "rit-providers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	if err := NewSetter(tmp, ctxFinder, sessManager).Set(githubCred); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := NewFinder(tmp, ctxFinder, sessManager).Providers()
+	if err != nil || !reflect.DeepEqual(got, []string{githubCred.Service}) {
+		t.Errorf("Providers() got %v, %v, want [%s]", got, err, githubCred.Service)
+	}
+}
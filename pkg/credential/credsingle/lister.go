@@ -0,0 +1,35 @@
+package credsingle
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+// Lister lists the credentials set on the contexts
+type Lister struct {
+	homePath string
+}
+
+func NewLister(homePath string) Lister {
+	return Lister{homePath: homePath}
+}
+
+// Services returns the sorted services with a credential set on the context
+func (l Lister) Services(ctx string) ([]string, error) {
+	files, err := ioutil.ReadDir(Dir(l.homePath, ctx))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var services []string
+	for _, f := range files {
+		if !f.IsDir() {
+			services = append(services, f.Name())
+		}
+	}
+	sort.Strings(services)
+	return services, nil
+}
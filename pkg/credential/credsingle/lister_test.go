@@ -0,0 +1,33 @@
+package credsingle
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestListerServices(t *testing.T) {
+	home, err := ioutil.TempDir("", "rit-cred-lister")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	if err := os.MkdirAll(Dir(home, "dev"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"github", "aws"} {
+		if err := ioutil.WriteFile(File(home, "dev", s), []byte("secret"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l := NewLister(home)
+	if got, err := l.Services("dev"); err != nil || !reflect.DeepEqual(got, []string{"aws", "github"}) {
+		t.Errorf("Services(dev) = %v, %v, want [aws github]", got, err)
+	}
+	if got, err := l.Services("qa"); err != nil || got != nil {
+		t.Errorf("Services(qa) = %v, %v, want no services", got, err)
+	}
+}
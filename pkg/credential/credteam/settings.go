@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"sort"
 
 	"github.com/ZupIT/ritchie-cli/pkg/credential"
 	"github.com/ZupIT/ritchie-cli/pkg/http/headers"
@@ -88,3 +89,19 @@ func (s Settings) Fields() (credential.Fields, error) {
 		return nil, errors.New(string(b))
 	}
 }
+
+// Providers returns the sorted providers configured on the server, the credentials
+// of the team are set on the server
+func (s Settings) Providers() ([]string, error) {
+	fields, err := s.Fields()
+	if err != nil {
+		return nil, err
+	}
+
+	providers := make([]string, 0, len(fields))
+	for p := range fields {
+		providers = append(providers, p)
+	}
+	sort.Strings(providers)
+	return providers, nil
+}
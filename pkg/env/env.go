@@ -11,3 +11,11 @@ type Resolvers map[string]Resolver
 type Resolver interface {
 	Resolve(name string) (string, error)
 }
+
+// Selector is a resolver whose values can be listed to select one, e.g. the credential
+// resolver lists the providers with a credential and resolves every field of the one selected
+type Selector interface {
+	Resolver
+	List() ([]string, error)
+	ResolveAll(name string) (map[string]string, error)
+}
@@ -8,21 +8,71 @@ import (
 
 type CredentialResolver struct {
 	credential.Finder
+	lister   credential.Lister
+	backends []credential.Backend
 }
 
-// NewResolver creates a credential resolver instance of Resolver interface
-func NewResolver(cf credential.Finder) CredentialResolver {
-	return CredentialResolver{cf}
+// NewResolver creates a credential resolver instance of Resolver interface,
+// the backends are consulted in order before the credentials of the finder
+func NewResolver(cf credential.Finder, cl credential.Lister, backends ...credential.Backend) CredentialResolver {
+	return CredentialResolver{cf, cl, backends}
 }
 
 func (c CredentialResolver) Resolve(name string) (string, error) {
 	s := strings.Split(name, "_")
-	service := strings.ToLower(s[1])
-	cred, err := c.Find(service)
+	ref := credential.Reference{
+		Service: strings.ToLower(s[1]),
+		Field:   strings.ToLower(s[2]),
+	}
+
+	if secret, found, err := c.fetch(ref); err != nil || found {
+		return secret, err
+	}
+
+	cred, err := c.Find(ref.Service)
 	if err != nil {
 		return "", err
 	}
 
-	k := strings.ToLower(s[2])
-	return cred.Credential[k], nil
+	return cred.Credential[ref.Field], nil
+}
+
+// List returns the providers of the credentials the user can select
+func (c CredentialResolver) List() ([]string, error) {
+	return c.lister.Providers()
+}
+
+// ResolveAll returns every field of the credential of the provider, the fields of the
+// finder credential are consulted on the backends first
+func (c CredentialResolver) ResolveAll(provider string) (map[string]string, error) {
+	cred, err := c.Find(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]string, len(cred.Credential))
+	for field, v := range cred.Credential {
+		secret, found, err := c.fetch(credential.Reference{Service: provider, Field: field})
+		if err != nil {
+			return nil, err
+		} else if found {
+			v = secret
+		}
+		fields[field] = v
+	}
+	return fields, nil
+}
+
+// fetch returns the credential field of the first backend with it
+func (c CredentialResolver) fetch(ref credential.Reference) (string, bool, error) {
+	for _, b := range c.backends {
+		secret, err := b.Fetch(ref)
+		if err == credential.ErrNotFound {
+			continue
+		} else if err != nil {
+			return "", false, err
+		}
+		return secret, true, nil
+	}
+	return "", false, nil
 }
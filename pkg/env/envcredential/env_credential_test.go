@@ -0,0 +1,89 @@
+package envcredential
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/credential"
+)
+
+type finderMock struct{}
+
+func (finderMock) Find(service string) (credential.Detail, error) {
+	return credential.Detail{Credential: credential.Credential{"token": "local"}}, nil
+}
+
+type listerMock struct{}
+
+func (listerMock) Providers() ([]string, error) {
+	return []string{"github", "github-work"}, nil
+}
+
+type backendMock struct {
+	secret string
+	err    error
+}
+
+func (b backendMock) Fetch(ref credential.Reference) (string, error) {
+	return b.secret, b.err
+}
+
+func TestCredentialResolver_Resolve(t *testing.T) {
+	errBackend := errors.New("vault sealed")
+
+	tests := []struct {
+		name     string
+		backends []credential.Backend
+		want     string
+		wantErr  error
+	}{
+		{
+			name: "local store",
+			want: "local",
+		},
+		{
+			name:     "first backend with the credential",
+			backends: []credential.Backend{backendMock{err: credential.ErrNotFound}, backendMock{secret: "vault"}},
+			want:     "vault",
+		},
+		{
+			name:     "backends without the credential",
+			backends: []credential.Backend{backendMock{err: credential.ErrNotFound}},
+			want:     "local",
+		},
+		{
+			name:     "backend failure",
+			backends: []credential.Backend{backendMock{err: errBackend}, backendMock{secret: "vault"}},
+			wantErr:  errBackend,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewResolver(finderMock{}, listerMock{}, tt.backends...).Resolve("CREDENTIAL_GITHUB_TOKEN")
+			if got != tt.want || err != tt.wantErr {
+				t.Errorf("Resolve got (%q, %v), want (%q, %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCredentialResolver_ResolveAll(t *testing.T) {
+	r := NewResolver(finderMock{}, listerMock{}, backendMock{err: credential.ErrNotFound})
+	providers, err := r.List()
+	if err != nil || !reflect.DeepEqual(providers, []string{"github", "github-work"}) {
+		t.Errorf("List got (%q, %v), want the providers of the lister", providers, err)
+	}
+
+	got, err := r.ResolveAll("github")
+	if err != nil || !reflect.DeepEqual(got, map[string]string{"token": "local"}) {
+		t.Errorf("ResolveAll got (%q, %v), want the fields of the local store", got, err)
+	}
+
+	r = NewResolver(finderMock{}, listerMock{}, backendMock{secret: "vault"})
+	got, err = r.ResolveAll("github")
+	if err != nil || !reflect.DeepEqual(got, map[string]string{"token": "vault"}) {
+		t.Errorf("ResolveAll got (%q, %v), want the fields of the backend", got, err)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package fileutil
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// IsWritable tells whether files can be created in the dir, asking the access of the
+// dir to the kernel instead of writing on it
+func IsWritable(dir string) bool {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return false
+	}
+	return unix.Access(dir, unix.W_OK) == nil
+}
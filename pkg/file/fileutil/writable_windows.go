@@ -0,0 +1,27 @@
+package fileutil
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// writable caches the dirs found writable, the ACLs of windows are only known by writing
+var writable sync.Map
+
+// IsWritable tells whether files can be created in the dir, creating a probe file the
+// first time the dir is checked
+func IsWritable(dir string) bool {
+	if _, ok := writable.Load(dir); ok {
+		return true
+	}
+
+	f, err := ioutil.TempFile(dir, ".rit-write-check")
+	if err != nil {
+		return false
+	}
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+	writable.Store(dir, true)
+	return true
+}
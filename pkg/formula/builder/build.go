@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/ZupIT/ritchie-cli/pkg/file/fileextensions"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
 	"github.com/ZupIT/ritchie-cli/pkg/os/osutil"
 	"github.com/ZupIT/ritchie-cli/pkg/prompt"
 	"github.com/ZupIT/ritchie-cli/pkg/stream"
@@ -26,10 +27,10 @@ var (
 type Manager struct {
 	ritHome string
 	dir     stream.DirCreateListCopyRemover
-	file    stream.FileCopyExistLister
+	file    stream.FileReadWriteCopyExistLister
 }
 
-func New(ritHome string, dir stream.DirCreateListCopyRemover, file stream.FileCopyExistLister) Manager {
+func New(ritHome string, dir stream.DirCreateListCopyRemover, file stream.FileReadWriteCopyExistLister) Manager {
 	return Manager{ritHome: ritHome, dir: dir, file: file}
 }
 
@@ -58,6 +59,12 @@ func (m Manager) Build(workspacePath, formulaPath string) error {
 		cmd = exec.Command("make", "build")
 	}
 
+	env, err := m.imageEnv(formulaPath)
+	if err != nil {
+		return err
+	}
+	cmd.Env = append(os.Environ(), env...)
+
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
@@ -85,6 +92,10 @@ func (m Manager) Build(workspacePath, formulaPath string) error {
 		return err
 	}
 
+	if err := m.describeTree(workspacePath, formulaPath); err != nil {
+		return err
+	}
+
 	if err := m.copyTree(workspacePath); err != nil {
 		return err
 	}
@@ -92,6 +103,29 @@ func (m Manager) Build(workspacePath, formulaPath string) error {
 	return nil
 }
 
+// imageEnv is the env of the build telling the architecture of rit and the image of the
+// formula config.json for it, so the builds on docker don't run under emulation
+func (m Manager) imageEnv(formulaPath string) ([]string, error) {
+	env := []string{fmt.Sprintf(formula.EnvPattern, formula.ArchEnv, runtime.GOARCH)}
+	configPath := path.Join(formulaPath, formula.DefaultConfig)
+	if !m.file.Exists(configPath) {
+		return env, nil
+	}
+
+	b, err := m.file.Read(configPath)
+	if err != nil {
+		return nil, err
+	}
+	config, err := formula.UnmarshalConfig(b, localRepo)
+	if err != nil {
+		return nil, err
+	}
+	if img := config.Images[runtime.GOARCH]; img != "" {
+		env = append(env, fmt.Sprintf(formula.EnvPattern, formula.ImageEnv, img))
+	}
+	return env, nil
+}
+
 func (m Manager) copyDist(formulaPath, ritFormulaDistPath string) error {
 	formulaDist := path.Join(formulaPath, "/dist") // /dist directory that contains built formula
 	dirs, err := m.dir.List(formulaDist, false)
@@ -154,11 +188,19 @@ func (m Manager) copyTree(workspacePath string) error {
 		return err
 	}
 
-	if err := m.file.Copy(copyFile, destFile); err != nil {
+	tree, err := m.file.Read(copyFile)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	// the local tree is replaced at once, so the rit processes reading it don't need the lock
+	unlock, err := stream.Lock(destFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return stream.WriteAtomic(destFile, tree, 0644)
 }
 
 func (m Manager) formulaDestPath(formulaPath, workspacePath string) string {
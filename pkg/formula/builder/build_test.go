@@ -3,7 +3,11 @@ package builder
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
 	"testing"
 
 	"github.com/ZupIT/ritchie-cli/pkg/stream"
@@ -26,7 +30,7 @@ func TestBuild(t *testing.T) {
 	type in struct {
 		workspacePath string
 		formulaPath   string
-		fileManager   stream.FileCopyExistLister
+		fileManager   stream.FileReadWriteCopyExistLister
 		dirManager    stream.DirCreateListCopyRemover
 	}
 
@@ -204,3 +208,36 @@ func (f fileManagerMock) Copy(string, string) error {
 func (f fileManagerMock) Exists(string) bool {
 	return f.exist
 }
+
+func (f fileManagerMock) Read(string) ([]byte, error) {
+	return nil, nil
+}
+
+func (f fileManagerMock) Write(string, []byte) error {
+	return nil
+}
+
+func TestImageEnv(t *testing.T) {
+	formulaPath, err := ioutil.TempDir("", "rit-image-env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(formulaPath)
+
+	fileManager := stream.NewFileManager()
+	m := New(formulaPath, stream.NewDirManager(fileManager), fileManager)
+	arch := "RIT_ARCH=" + runtime.GOARCH
+
+	if env, err := m.imageEnv(formulaPath); err != nil || !reflect.DeepEqual(env, []string{arch}) {
+		t.Errorf("imageEnv() without config.json got %v, %v, want %v", env, err, []string{arch})
+	}
+
+	config := `{"inputs":[],"images":{"` + runtime.GOARCH + `":"node:14-native","s390x":"node:14-s390x"}}`
+	if err := ioutil.WriteFile(filepath.Join(formulaPath, "config.json"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{arch, "RIT_IMAGE=node:14-native"}
+	if env, err := m.imageEnv(formulaPath); err != nil || !reflect.DeepEqual(env, want) {
+		t.Errorf("imageEnv() got %v, %v, want %v", env, err, want)
+	}
+}
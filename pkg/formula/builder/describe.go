@@ -0,0 +1,68 @@
+package builder
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+const localRepo = "local"
+
+// describeTree copies the long description and the examples of the formula config
+// to its command on the workspace tree, so that the formula help shows them without
+// downloading the config. The tree is only written when they changed.
+func (m Manager) describeTree(workspacePath, formulaPath string) error {
+	configPath := path.Join(formulaPath, formula.DefaultConfig)
+	treePath := path.Join(workspacePath, formula.TreePath)
+	if !m.file.Exists(configPath) || !m.file.Exists(treePath) {
+		return nil
+	}
+
+	b, err := m.file.Read(configPath)
+	if err != nil {
+		return err
+	}
+	config, err := formula.UnmarshalConfig(b, localRepo)
+	if err != nil {
+		return err
+	}
+
+	b, err = m.file.Read(treePath)
+	if err != nil {
+		return err
+	}
+	tree, err := formula.UnmarshalTree(b, localRepo)
+	if err != nil {
+		return err
+	}
+
+	formulaCmd := strings.Trim(strings.TrimPrefix(formulaPath, workspacePath), "/")
+	changed := false
+	for i, c := range tree.Commands {
+		if c.Formula == nil || c.Formula.Path != formulaCmd {
+			continue
+		}
+		if c.LongHelp != config.LongDesc || !reflect.DeepEqual(c.Examples, config.Examples) {
+			tree.Commands[i].LongHelp = config.LongDesc
+			tree.Commands[i].Examples = config.Examples
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	b, err = json.Marshal(&tree)
+	if err != nil {
+		return err
+	}
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, b, "", "\t"); err != nil {
+		return err
+	}
+	return m.file.Write(treePath, pretty.Bytes())
+}
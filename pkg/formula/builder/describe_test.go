@@ -0,0 +1,57 @@
+package builder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/stream"
+)
+
+func TestDescribeTree(t *testing.T) {
+	workspace, err := ioutil.TempDir("", "rit-describe-tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(workspace)
+
+	formulaPath := filepath.Join(workspace, "aws", "create")
+	treePath := filepath.Join(workspace, "tree", "tree.json")
+	tree := `{"commands":[
+		{"parent":"root","usage":"aws","help":"aws commands"},
+		{"parent":"root_aws","usage":"create","help":"Create a stack","formula":{"path":"aws/create"}}]}`
+	config := `{"description":"Create a stack","longDescription":"Create a CloudFormation stack and wait for it.",
+		"examples":[{"description":"on sa-east-1","command":"rit aws create --region sa-east-1"}],"inputs":[]}`
+	for file, content := range map[string]string{treePath: tree, filepath.Join(formulaPath, "config.json"): config} {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fileManager := stream.NewFileManager()
+	m := New(workspace, stream.NewDirManager(fileManager), fileManager)
+	if err := m.describeTree(workspace, formulaPath); err != nil {
+		t.Fatalf("describeTree error = %v", err)
+	}
+
+	b, _ := ioutil.ReadFile(treePath)
+	got, err := formula.UnmarshalTree(b, localRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := got.Commands[1]
+	wantExamples := []api.Example{{Description: "on sa-east-1", Command: "rit aws create --region sa-east-1"}}
+	if c.LongHelp != "Create a CloudFormation stack and wait for it." || !reflect.DeepEqual(c.Examples, wantExamples) {
+		t.Errorf("describeTree got long help %q and examples %v", c.LongHelp, c.Examples)
+	}
+	if got.Commands[0].LongHelp != "" || c.Help != "Create a stack" {
+		t.Errorf("describeTree changed the other fields: %+v", got.Commands)
+	}
+}
@@ -0,0 +1,161 @@
+// Package checksum keeps the checksums of the formula files downloaded from the
+// repositories, so that the files changed on the rit home can be listed later.
+package checksum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+// File keeps the checksums of the formula files, on the formula dir
+const File = ".checksums.json"
+
+// cacheFiles matches the input caches written on the formula dir by the runs, see formula.CachePattern
+var cacheFiles = filepath.Base(fmt.Sprintf(formula.CachePattern, "", "*"))
+
+// Save writes the checksums of every file of the formula dir, it is called
+// after each download so the checksums match the files installed from the repository
+func Save(dir string) error {
+	sums, err := sumDir(dir)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(sums)
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteFile(filepath.Join(dir, File), b)
+}
+
+// Changes compares the files of the formula dir with the checksums saved on the install,
+// the paths are relative to dir. It returns false when the dir has no checksums saved,
+// e.g. the formula was never downloaded or it was installed by a rit version without them.
+func Changes(dir string) ([]formula.FileChange, bool, error) {
+	saved, ok, err := Load(dir)
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+
+	current, err := sumDir(dir)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var changes []formula.FileChange
+	for path, sum := range current {
+		savedSum, ok := saved[path]
+		switch {
+		case !ok:
+			changes = append(changes, formula.FileChange{Status: formula.FileAdded, Path: path})
+		case savedSum != sum:
+			changes = append(changes, formula.FileChange{Status: formula.FileModified, Path: path})
+		}
+	}
+	for path := range saved {
+		if _, ok := current[path]; !ok {
+			changes = append(changes, formula.FileChange{Status: formula.FileDeleted, Path: path})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, true, nil
+}
+
+// Load reads the checksums saved on the install of the formula dir, by their slash
+// separated path relative to dir. It returns false when the dir has no checksums saved.
+func Load(dir string) (map[string]string, bool, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, File))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	var saved map[string]string
+	if err := json.Unmarshal(b, &saved); err != nil {
+		return nil, false, err
+	}
+	return saved, true, nil
+}
+
+// Verify checks that the files of the dir are exactly the ones of want, with the same checksums.
+// The error lists the paths that differ, it wraps formula.ErrLockMismatch.
+func Verify(dir string, want map[string]string) error {
+	current, err := sumDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var paths []string
+	for path, sum := range current {
+		if want[path] != sum {
+			paths = append(paths, path)
+		}
+	}
+	for path := range want {
+		if _, ok := current[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	sort.Strings(paths)
+	return fmt.Errorf("%w, the files differ: %s", formula.ErrLockMismatch, strings.Join(paths, ", "))
+}
+
+// sumDir returns the sha256 of the dir files by their slash separated path relative to dir,
+// but the input caches, which change on every run
+func sumDir(dir string) (map[string]string, error) {
+	sums := make(map[string]string)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == File {
+			return err
+		}
+		if cache, _ := filepath.Match(cacheFiles, rel); cache {
+			return nil
+		}
+
+		sum, err := sumFile(path)
+		if err != nil {
+			return err
+		}
+		sums[filepath.ToSlash(rel)] = sum
+		return nil
+	})
+	return sums, err
+}
+
+func sumFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
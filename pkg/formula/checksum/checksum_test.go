@@ -0,0 +1,93 @@
+package checksum
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestChanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-checksum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, ok, err := Changes(dir); ok || err != nil {
+		t.Fatalf("Changes without checksums got %v, %v, want false", ok, err)
+	}
+
+	files := map[string]string{"config.json": "{}", "bin/run.sh": "echo run", "bin/lib.sh": "echo lib"}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		_ = os.MkdirAll(filepath.Dir(path), 0755)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Save(dir); err != nil {
+		t.Fatalf("Save got %v, want nil", err)
+	}
+
+	if changes, ok, err := Changes(dir); !ok || err != nil || len(changes) != 0 {
+		t.Fatalf("Changes after Save got %v, %v, %v, want no changes", changes, ok, err)
+	}
+
+	_ = ioutil.WriteFile(filepath.Join(dir, ".REGION.cache"), []byte(`["sa-east-1"]`), 0644)
+	if changes, ok, err := Changes(dir); !ok || err != nil || len(changes) != 0 {
+		t.Fatalf("Changes after an input cache got %v, %v, %v, want no changes", changes, ok, err)
+	}
+
+	_ = ioutil.WriteFile(filepath.Join(dir, "bin/run.sh"), []byte("echo changed"), 0644)
+	_ = ioutil.WriteFile(filepath.Join(dir, "bin/new.sh"), []byte("echo new"), 0644)
+	_ = os.Remove(filepath.Join(dir, "bin/lib.sh"))
+
+	changes, ok, err := Changes(dir)
+	if !ok || err != nil {
+		t.Fatalf("Changes got %v, %v", ok, err)
+	}
+	want := []formula.FileChange{
+		{Status: formula.FileDeleted, Path: "bin/lib.sh"},
+		{Status: formula.FileAdded, Path: "bin/new.sh"},
+		{Status: formula.FileModified, Path: "bin/run.sh"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Changes got %v, want %v", changes, want)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-checksum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	_ = os.MkdirAll(filepath.Join(dir, "bin"), 0755)
+	_ = ioutil.WriteFile(filepath.Join(dir, "config.json"), []byte("{}"), 0644)
+	_ = ioutil.WriteFile(filepath.Join(dir, "bin/run.sh"), []byte("echo run"), 0644)
+	if err := Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	want, ok, err := Load(dir)
+	if !ok || err != nil || len(want) != 2 {
+		t.Fatalf("Load after Save got %v, %v, %v, want the 2 files", want, ok, err)
+	}
+
+	if err := Verify(dir, want); err != nil {
+		t.Errorf("Verify of the saved files got %v, want nil", err)
+	}
+
+	_ = ioutil.WriteFile(filepath.Join(dir, "bin/run.sh"), []byte("echo changed"), 0644)
+	_ = ioutil.WriteFile(filepath.Join(dir, "extra.sh"), []byte("echo extra"), 0644)
+	err = Verify(dir, want)
+	if !errors.Is(err, formula.ErrLockMismatch) || !strings.Contains(err.Error(), "bin/run.sh, extra.sh") {
+		t.Errorf("Verify of the changed files got %v, want the mismatch of bin/run.sh and extra.sh", err)
+	}
+}
@@ -111,7 +111,7 @@ func (c CreateManager) isValidCmd(fCmd string) error {
 }
 
 func (c CreateManager) generateTreeJsonFile(formPath, fCmd, lang string) error {
-	treeCommands := formula.Tree{Commands: api.Commands{}}
+	treeCommands := formula.Tree{SchemaVersion: formula.TreeSchemaVersion, Commands: api.Commands{}}
 	treePath := path.Join(formPath, formula.TreePath)
 	if !c.file.Exists(treePath) {
 		if err := c.dir.Create(filepath.Dir(treePath)); err != nil {
@@ -122,7 +122,7 @@ func (c CreateManager) generateTreeJsonFile(formPath, fCmd, lang string) error {
 		if err != nil {
 			return err
 		}
-		if err := json.Unmarshal(jsonFile, &treeCommands); err != nil {
+		if treeCommands, err = formula.UnmarshalTree(jsonFile, "local"); err != nil {
 			return err
 		}
 	}
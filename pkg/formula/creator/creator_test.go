@@ -41,7 +41,7 @@ func TestCreator(t *testing.T) {
 	jsonDir := createDirWithTree(dirManager, fileManager)
 	fullDir := createFullDir(dirManager, fileManager)
 
-	treeMan := tree.NewTreeManager("../../testdata", repoListerMock{}, api.SingleCoreCmds)
+	treeMan := tree.NewTreeManager("../../testdata", repoListerMock{}, api.SingleCoreCmds, nil)
 
 	type in struct {
 		formCreate formula.Create
@@ -295,7 +295,7 @@ func TestCreator(t *testing.T) {
 				file: fileManagerMock{data: []byte(""), exist: true},
 			},
 			out: out{
-				err: errors.New("unexpected end of JSON input"),
+				err: errors.New(`invalid tree.json of the repo "local": empty file`),
 			},
 		},
 	}
@@ -320,7 +320,7 @@ func TestCreatorFail(t *testing.T) {
 
 	fullDir := createFullDir(dirManager, fileManager)
 
-	treeMan := tree.NewTreeManager("../../testdata", repoListerMock{}, api.SingleCoreCmds)
+	treeMan := tree.NewTreeManager("../../testdata", repoListerMock{}, api.SingleCoreCmds, nil)
 
 	tests := []string{langGo, langJava, langNode, langPhp, langPython, langShell}
 
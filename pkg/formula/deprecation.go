@@ -0,0 +1,72 @@
+package formula
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+)
+
+const (
+	// SunsetLayout is the date layout used by the deprecation sunset field
+	SunsetLayout        = "2006-01-02"
+	deprecatedMsg       = "The formula %q is deprecated: %s"
+	deprecatedUseMsg    = "%s\nUse %q instead."
+	deprecatedSunsetMsg = "%s\nIt will be removed on %s."
+)
+
+// IsSunset tells whether the sunset date of the deprecation has already passed.
+// The sunset date is read in the location of now, so the cutoff happens at the
+// user's local midnight. A deprecation without sunset date never expires.
+func IsSunset(d api.Deprecation, now time.Time) (bool, error) {
+	if d.Sunset == "" {
+		return false, nil
+	}
+
+	sunset, err := time.ParseInLocation(SunsetLayout, d.Sunset, now.Location())
+	if err != nil {
+		return false, fmt.Errorf("invalid deprecation sunset %q, use the format %s", d.Sunset, SunsetLayout)
+	}
+
+	return !now.Before(sunset), nil
+}
+
+// DeprecationMsg builds the message shown to the user before running a deprecated formula
+func DeprecationMsg(cmdPath string, d api.Deprecation) string {
+	msg := fmt.Sprintf(deprecatedMsg, cmdPath, d.Message)
+	if d.Replacement != "" {
+		msg = fmt.Sprintf(deprecatedUseMsg, msg, d.Replacement)
+	}
+	if d.Sunset != "" {
+		msg = fmt.Sprintf(deprecatedSunsetMsg, msg, d.Sunset)
+	}
+	return msg
+}
+
+// NewlyDeprecated returns the commands that are deprecated in the new tree
+// but were not deprecated in the old one
+func NewlyDeprecated(oldTree, newTree Tree) []api.Command {
+	deprecated := make(map[string]bool)
+	for _, c := range oldTree.Commands {
+		if c.Deprecation != nil {
+			deprecated[c.Parent+"_"+c.Usage] = true
+		}
+	}
+
+	var cc []api.Command
+	for _, c := range newTree.Commands {
+		if c.Deprecation != nil && !deprecated[c.Parent+"_"+c.Usage] {
+			cc = append(cc, c)
+		}
+	}
+
+	return cc
+}
+
+// CommandPath builds the rit command path from the tree command, e.g. "rit aws create"
+func CommandPath(c api.Command) string {
+	parents := strings.Split(c.Parent, "_")
+	parents[0] = "rit"
+	return strings.Join(append(parents, c.Usage), " ")
+}
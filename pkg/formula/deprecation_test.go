@@ -0,0 +1,96 @@
+package formula
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+)
+
+func TestIsSunset(t *testing.T) {
+	now := time.Date(2020, 7, 20, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		in      api.Deprecation
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "without sunset",
+			in:   api.Deprecation{Message: "deprecated"},
+			want: false,
+		},
+		{
+			name: "sunset in the future",
+			in:   api.Deprecation{Sunset: "2020-08-01"},
+			want: false,
+		},
+		{
+			name: "sunset in the past",
+			in:   api.Deprecation{Sunset: "2020-07-01"},
+			want: true,
+		},
+		{
+			name:    "invalid sunset",
+			in:      api.Deprecation{Sunset: "01/08/2020"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsSunset(tt.in, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsSunset(%s) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("IsSunset(%s) got %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSunsetLocalDate(t *testing.T) {
+	loc := time.FixedZone("UTC-3", -3*60*60)
+	d := api.Deprecation{Sunset: "2020-07-01"}
+
+	beforeMidnight := time.Date(2020, 6, 30, 22, 0, 0, 0, loc)
+	if got, _ := IsSunset(d, beforeMidnight); got {
+		t.Errorf("IsSunset before local midnight got %v, want false", got)
+	}
+
+	afterMidnight := time.Date(2020, 7, 1, 0, 30, 0, 0, loc)
+	if got, _ := IsSunset(d, afterMidnight); !got {
+		t.Errorf("IsSunset after local midnight got %v, want true", got)
+	}
+}
+
+func TestDeprecationMsg(t *testing.T) {
+	d := api.Deprecation{Message: "no longer maintained", Replacement: "rit aws create", Sunset: "2020-08-01"}
+	want := "The formula \"rit aws old\" is deprecated: no longer maintained\n" +
+		"Use \"rit aws create\" instead.\n" +
+		"It will be removed on 2020-08-01."
+
+	if got := DeprecationMsg("rit aws old", d); got != want {
+		t.Errorf("DeprecationMsg got %q, want %q", got, want)
+	}
+}
+
+func TestNewlyDeprecated(t *testing.T) {
+	dep := &api.Deprecation{Message: "deprecated"}
+	oldTree := Tree{Commands: api.Commands{
+		{Parent: "root_aws", Usage: "old", Deprecation: dep},
+		{Parent: "root_aws", Usage: "create"},
+	}}
+	newTree := Tree{Commands: api.Commands{
+		{Parent: "root_aws", Usage: "old", Deprecation: dep},
+		{Parent: "root_aws", Usage: "create", Deprecation: dep},
+		{Parent: "root_aws", Usage: "delete"},
+	}}
+
+	got := NewlyDeprecated(oldTree, newTree)
+	if len(got) != 1 || CommandPath(got[0]) != "rit aws create" {
+		t.Errorf("NewlyDeprecated got %v, want only rit aws create", got)
+	}
+}
@@ -0,0 +1,220 @@
+// Package detach keeps the formula runs started with --detach, which run in the
+// background on a child rit process, so they are listed by rit ps and stopped by rit stop.
+package detach
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runlog"
+)
+
+const (
+	// Dir is the detached runs dir pattern, relative to the ritchie home
+	Dir = "%s/detached"
+	// IDEnv is the env var with the ID of the detached run of the child rit, its run log ID
+	IDEnv = "RIT_DETACHED_ID"
+	// MaxRuns is the number of finished runs kept, the oldest ones are pruned
+	MaxRuns = 50
+
+	runExt    = ".json"
+	outputExt = ".out"
+)
+
+var (
+	ErrRunNotFound = errors.New("detached run not found, list them with rit ps --all")
+	ErrNotRunning  = errors.New("the detached run already finished")
+)
+
+// Run is a formula run detached from the terminal. Its ID is the ID of its run log, read by
+// rit logs, and Output is the file with the messages of the child rit, e.g. a failed setup.
+// Running tells whether the child rit process is still running. ProcessStart is when the
+// child rit process started, telling it apart from a later process reusing its PID.
+type Run struct {
+	ID           string    `json:"id"`
+	Command      string    `json:"command"`
+	PID          int       `json:"pid"`
+	ProcessStart string    `json:"processStart,omitempty"`
+	Output       string    `json:"output"`
+	Start        time.Time `json:"start"`
+	Running      bool      `json:"running"`
+}
+
+type Starter interface {
+	Start(command string, args []string, stdin []byte) (Run, error)
+}
+
+type Lister interface {
+	List() ([]Run, error)
+}
+
+type Stopper interface {
+	Stop(id string) (Run, error)
+}
+
+type Manager struct {
+	dir        string
+	executable func() (string, error)
+	now        func() time.Time
+}
+
+// NewManager creates the detached runs manager, the runs are child processes of the rit executable
+func NewManager(ritchieHome string) Manager {
+	return Manager{dir: fmt.Sprintf(Dir, ritchieHome), executable: os.Executable, now: time.Now}
+}
+
+// Start runs rit with the args on a new session, so it keeps running after the terminal
+// is closed, with the stdin inputs. The oldest finished runs are pruned.
+func (m Manager) Start(command string, args []string, stdin []byte) (Run, error) {
+	exe, err := m.executable()
+	if err != nil {
+		return Run{}, err
+	}
+
+	if err := fileutil.CreateDirIfNotExists(m.dir, 0755); err != nil {
+		return Run{}, err
+	}
+	if err := m.prune(MaxRuns - 1); err != nil {
+		return Run{}, err
+	}
+
+	start := m.now()
+	run := Run{ID: runlog.NewID(start, command), Command: command, Start: start}
+	run.Output = filepath.Join(m.dir, run.ID+outputExt)
+	out, err := os.OpenFile(run.Output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return Run{}, err
+	}
+	defer out.Close()
+
+	c := exec.Command(exe, args...)
+	c.Stdin = bytes.NewReader(stdin)
+	c.Stdout = out
+	c.Stderr = out
+	c.Env = append(os.Environ(), IDEnv+"="+run.ID)
+	c.SysProcAttr = sysProcAttr()
+	if err := c.Start(); err != nil {
+		return Run{}, err
+	}
+	run.PID = c.Process.Pid
+	run.ProcessStart, _ = processStart(run.PID)
+	run.Running = true
+	_ = c.Process.Release()
+
+	return run, m.write(run)
+}
+
+// List returns the detached runs from the oldest to the newest
+func (m Manager) List() ([]Run, error) {
+	if !fileutil.Exists(m.dir) {
+		return []Run{}, nil
+	}
+
+	files, err := ioutil.ReadDir(m.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []Run
+	for _, f := range files {
+		if filepath.Ext(f.Name()) != runExt {
+			continue
+		}
+
+		b, err := fileutil.ReadFile(filepath.Join(m.dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var r Run
+		if err := json.Unmarshal(b, &r); err != nil {
+			continue
+		}
+		r.Running = running(r)
+		runs = append(runs, r)
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].ID < runs[j].ID
+	})
+
+	return runs, nil
+}
+
+// Stop asks the child rit of the run to stop, it stops the formula as on a Ctrl+C,
+// giving it the time to clean up. A run whose PID is now of another process isn't running.
+func (m Manager) Stop(id string) (Run, error) {
+	runs, err := m.List()
+	if err != nil {
+		return Run{}, err
+	}
+
+	for _, r := range runs {
+		if r.ID != id {
+			continue
+		}
+		if !r.Running {
+			return r, ErrNotRunning
+		}
+		p, err := os.FindProcess(r.PID)
+		if err != nil {
+			return r, err
+		}
+		return r, terminate(p)
+	}
+	return Run{}, ErrRunNotFound
+}
+
+// running tells whether the child rit of the run is running: a process with its PID exists
+// and started when the child rit did, the PID of a finished run may be reused by another one
+func running(r Run) bool {
+	if r.ProcessStart == "" || !alive(r.PID) {
+		return false
+	}
+	start, err := processStart(r.PID)
+	return err == nil && start == r.ProcessStart
+}
+
+// prune keeps only the newest keep finished runs, the running ones are always kept
+func (m Manager) prune(keep int) error {
+	runs, err := m.List()
+	if err != nil {
+		return err
+	}
+
+	var finished []Run
+	for _, r := range runs {
+		if !r.Running {
+			finished = append(finished, r)
+		}
+	}
+
+	for len(finished) > keep {
+		r := finished[0]
+		for _, f := range []string{r.Output, filepath.Join(m.dir, r.ID+runExt)} {
+			if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		finished = finished[1:]
+	}
+
+	return nil
+}
+
+func (m Manager) write(r Run) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteFilePerm(filepath.Join(m.dir, r.ID+runExt), b, 0600)
+}
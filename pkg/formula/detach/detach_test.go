@@ -0,0 +1,144 @@
+//go:build !windows
+// +build !windows
+
+package detach
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+)
+
+func newTestManager(t *testing.T) (Manager, string) {
+	t.Helper()
+	home, err := ioutil.TempDir("", "rit-detach")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(home)
+	})
+
+	// the fake rit prints its detached ID, args and stdin and keeps running until it is stopped
+	exe := filepath.Join(home, "rit")
+	script := "#!/bin/sh\necho \"$" + IDEnv + " $*\"\ncat\necho\nexec sleep 30\n"
+	if err := ioutil.WriteFile(exe, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(home)
+	m.executable = func() (string, error) { return exe, nil }
+	m.now = func() time.Time { return time.Date(2020, 7, 20, 10, 0, 0, 0, time.UTC) }
+	return m, home
+}
+
+func TestStartListStop(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	run, err := m.Start("rit aws tunnel", []string{"aws", "tunnel", "--stdin=true", "--"}, []byte(`{"port":"8080"}`))
+	if err != nil {
+		t.Fatalf("Start() got %v, want nil", err)
+	}
+	if run.ID != "20200720T100000.000000-rit-aws-tunnel" || run.PID == 0 || !run.Running {
+		t.Errorf("Start() got %+v, want the running run", run)
+	}
+
+	want := run.ID + " aws tunnel --stdin=true --\n{\"port\":\"8080\"}\n"
+	var out []byte
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline) && string(out) != want; {
+		time.Sleep(10 * time.Millisecond)
+		out, _ = ioutil.ReadFile(run.Output)
+	}
+	if string(out) != want {
+		t.Errorf("the child rit printed %q, want %q", out, want)
+	}
+
+	runs, err := m.List()
+	if err != nil || len(runs) != 1 || runs[0].ID != run.ID || !runs[0].Running {
+		t.Fatalf("List() got %+v, %v, want the running run", runs, err)
+	}
+
+	if _, err := m.Stop("missing"); err != ErrRunNotFound {
+		t.Errorf("Stop() of a missing run got %v, want ErrRunNotFound", err)
+	}
+	if _, err := m.Stop(run.ID); err != nil {
+		t.Errorf("Stop() got %v, want nil", err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	m, home := newTestManager(t)
+	dir := filepath.Join(home, "detached")
+	if err := fileutil.CreateDirIfNotExists(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// the finished runs have the PID of a process that doesn't exist or that started after
+	// them, the running one the test PID and start
+	start, err := processStart(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < MaxRuns+2; i++ {
+		r := Run{ID: fmt.Sprintf("%02d-rit-aws-watch", i), Command: "rit aws watch", PID: 1 << 30, ProcessStart: start}
+		switch i {
+		case 0:
+			r.PID = os.Getpid()
+		case 1:
+			r.PID, r.ProcessStart = os.Getpid(), "0"
+		}
+		r.Output = filepath.Join(dir, r.ID+outputExt)
+		b, _ := json.Marshal(r)
+		_ = ioutil.WriteFile(filepath.Join(dir, r.ID+runExt), b, 0600)
+		_ = ioutil.WriteFile(r.Output, nil, 0600)
+	}
+
+	if err := m.prune(MaxRuns - 1); err != nil {
+		t.Fatalf("prune() got %v, want nil", err)
+	}
+
+	runs, err := m.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var finished int
+	for _, r := range runs {
+		if !r.Running {
+			finished++
+		} else if r.ID != "00-rit-aws-watch" {
+			t.Errorf("List() got the running run %s, want only 00-rit-aws-watch running", r.ID)
+		}
+	}
+	if finished != MaxRuns-1 || len(runs) != MaxRuns {
+		t.Errorf("prune() kept %d runs, %d finished, want %d and %d", len(runs), finished, MaxRuns, MaxRuns-1)
+	}
+	if runs[1].ID != "03-rit-aws-watch" {
+		t.Errorf("prune() kept %s, want the oldest finished runs pruned", runs[1].ID)
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 2*MaxRuns {
+		t.Errorf("prune() kept %d files, want %d", len(files), 2*MaxRuns)
+	}
+}
+
+func TestStopReusedPID(t *testing.T) {
+	m, home := newTestManager(t)
+	dir := filepath.Join(home, "detached")
+	if err := fileutil.CreateDirIfNotExists(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// the test process reuses the PID of the finished run, it must not be signaled
+	r := Run{ID: "00-rit-aws-watch", Command: "rit aws watch", PID: os.Getpid(), ProcessStart: "0"}
+	b, _ := json.Marshal(r)
+	_ = ioutil.WriteFile(filepath.Join(dir, r.ID+runExt), b, 0600)
+
+	if _, err := m.Stop(r.ID); err != ErrNotRunning {
+		t.Errorf("Stop() of a run with a reused PID got %v, want ErrNotRunning", err)
+	}
+}
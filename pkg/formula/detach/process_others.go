@@ -0,0 +1,52 @@
+//go:build !windows
+// +build !windows
+
+package detach
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// sysProcAttr starts the child rit on its own session, away from the signals of the terminal
+func sysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
+
+// alive tells whether the process is running, the signal 0 only checks it exists
+func alive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return p.Signal(syscall.Signal(0)) == nil
+}
+
+// processStart returns when the process started: its start time since the boot on the /proc
+// of linux, the lstart of ps on the other unix systems
+func processStart(pid int) (string, error) {
+	if b, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid)); err == nil {
+		// the command name may have spaces, the start time is the 20th field after it
+		stat := string(b)
+		fields := strings.Fields(stat[strings.LastIndex(stat, ")")+1:])
+		if len(fields) > 19 {
+			return fields[19], nil
+		}
+	}
+
+	out, err := exec.Command("ps", "-o", "lstart=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// terminate sends a SIGTERM to the child rit, it stops the formula before exiting
+func terminate(p *os.Process) error {
+	return p.Signal(syscall.SIGTERM)
+}
@@ -0,0 +1,42 @@
+package detach
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// sysProcAttr starts the child rit on its own process group, away from the Ctrl+C of the console
+func sysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// alive tells whether the process is running, FindProcess opens it only while it exists
+func alive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
+
+// processStart returns the creation time of the process
+func processStart(pid int) (string, error) {
+	h, err := syscall.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return "", err
+	}
+	defer syscall.CloseHandle(h)
+
+	var creation, exit, kernel, user syscall.Filetime
+	if err := syscall.GetProcessTimes(h, &creation, &exit, &kernel, &user); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(creation.Nanoseconds(), 10), nil
+}
+
+// terminate kills the child rit, windows has no SIGTERM to stop it gracefully
+func terminate(p *os.Process) error {
+	return p.Kill()
+}
@@ -0,0 +1,104 @@
+package formula
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidEnvFile is returned for a line of an --env-file that isn't NAME=VALUE
+var ErrInvalidEnvFile = errors.New("invalid --env-file line, it must be NAME=VALUE")
+
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ReadEnvFile reads the NAME=VALUE env vars of a dotenv file, skipping the blank lines and the
+// # comments. A line may start with export and a value may be quoted, "..." values take the
+// escapes of a Go string, e.g. \n, '...' values are literal and the unquoted ones end at a #.
+func ReadEnvFile(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var env []string
+	s := bufio.NewScanner(f)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kv := strings.SplitN(strings.TrimPrefix(line, "export "), "=", 2)
+		name := strings.TrimSpace(kv[0])
+		if len(kv) != 2 || !envName.MatchString(name) {
+			return nil, fmt.Errorf("%w: %s:%d", ErrInvalidEnvFile, file, n)
+		}
+
+		value, err := envValue(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s:%d: %v", ErrInvalidEnvFile, file, n, err)
+		}
+		env = append(env, name+"="+value)
+	}
+	return env, s.Err()
+}
+
+func envValue(v string) (string, error) {
+	switch {
+	case len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"':
+		return strconv.Unquote(v)
+	case len(v) >= 2 && v[0] == '\'' && v[len(v)-1] == '\'':
+		return v[1 : len(v)-1], nil
+	}
+
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = strings.TrimSpace(v[:i])
+	}
+	return v, nil
+}
+
+// ErrInvalidEnvPattern is returned for an --env-passthrough pattern that isn't a valid glob
+var ErrInvalidEnvPattern = errors.New("invalid --env-passthrough pattern, it must be a glob of env var names, e.g. AWS_*")
+
+// internalEnvPrefixes are the env vars of rit itself, never passed through to the formulas
+var internalEnvPrefixes = []string{"RIT_", "RITCHIE_"}
+
+// PassthroughEnv returns the NAME=VALUE vars of environ, e.g. os.Environ(), whose names match
+// one of the glob patterns, e.g. AWS_*, leaving out the vars of rit, as RIT_INPUT_REGION
+func PassthroughEnv(environ, patterns []string) ([]string, error) {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidEnvPattern, p)
+		}
+	}
+
+	var env []string
+	for _, e := range environ {
+		name := strings.SplitN(e, "=", 2)[0]
+		if internalEnv(name) {
+			continue
+		}
+		for _, p := range patterns {
+			if ok, _ := path.Match(p, name); ok {
+				env = append(env, e)
+				break
+			}
+		}
+	}
+	return env, nil
+}
+
+func internalEnv(name string) bool {
+	for _, prefix := range internalEnvPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package formula
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadEnvFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-env-file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "dotenv",
+			content: `# the aws account
+AWS_REGION=sa-east-1
+export STAGE = dev
+
+GREETING="hello\nworld"
+PATTERN='$HOME #literal'
+URL=http://host?a=b # the api
+EMPTY=
+`,
+			want: []string{"AWS_REGION=sa-east-1", "STAGE=dev", "GREETING=hello\nworld", "PATTERN=$HOME #literal", "URL=http://host?a=b", "EMPTY="},
+		},
+		{name: "empty file"},
+		{name: "missing value", content: "AWS_REGION\n", wantErr: true},
+		{name: "invalid name", content: "AWS-REGION=sa-east-1\n", wantErr: true},
+		{name: "invalid quotes", content: `NAME="a"b"` + "\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, ".env")
+			if err := ioutil.WriteFile(file, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ReadEnvFile(file)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidEnvFile) {
+					t.Errorf("ReadEnvFile() got error %v, want ErrInvalidEnvFile", err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadEnvFile() got %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := ReadEnvFile(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("ReadEnvFile() of a missing file got %v, want not exist", err)
+	}
+}
+
+func TestPassthroughEnv(t *testing.T) {
+	environ := []string{"AWS_PROFILE=dev", "AWS_REGION=sa-east-1", "HOME=/home/dev", "GITHUB_TOKEN=x", "RIT_INPUT_REGION=us-east-1", "RITCHIE_HOME=/rit"}
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+		wantErr  bool
+	}{
+		{name: "prefix", patterns: []string{"AWS_*"}, want: []string{"AWS_PROFILE=dev", "AWS_REGION=sa-east-1"}},
+		{name: "many patterns", patterns: []string{"AWS_REGION", "GITHUB_*"}, want: []string{"AWS_REGION=sa-east-1", "GITHUB_TOKEN=x"}},
+		{name: "rit vars left out", patterns: []string{"*"}, want: []string{"AWS_PROFILE=dev", "AWS_REGION=sa-east-1", "HOME=/home/dev", "GITHUB_TOKEN=x"}},
+		{name: "no patterns"},
+		{name: "invalid pattern", patterns: []string{"AWS_["}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PassthroughEnv(environ, tt.patterns)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidEnvPattern) {
+					t.Errorf("PassthroughEnv() got %v, want ErrInvalidEnvPattern", err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PassthroughEnv() got %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -1,14 +1,19 @@
 package formula
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/ZupIT/ritchie-cli/pkg/api"
 	"github.com/ZupIT/ritchie-cli/pkg/file/fileextensions"
+	"github.com/ZupIT/ritchie-cli/pkg/json/jsonutil"
 	"github.com/ZupIT/ritchie-cli/pkg/os/osutil"
 )
 
@@ -23,6 +28,27 @@ const (
 	PwdEnv               = "PWD"
 	CPwdEnv              = "CURRENT_PWD"
 	VerboseEnv           = "VERBOSE_MODE"
+	ArgsEnv              = "FORMULA_ARGS"
+	FailedCommandEnv     = "FAILED_COMMAND"
+	FailedExitCodeEnv    = "FAILED_EXIT_CODE"
+	FailedErrorEnv       = "FAILED_ERROR"
+	OutputsEnv           = "RIT_OUTPUTS_FILE"
+	HookFormulaDirEnv    = "RIT_FORMULA_DIR"
+	ImageEnv             = "RIT_IMAGE"
+	ArchEnv              = "RIT_ARCH"
+	MultiselectType      = "multiselect"
+	PathType             = "path"
+	SecretType           = "secret"
+	EditorType           = "editor"
+	IntType              = "int"
+	FloatType            = "float"
+	CredentialType       = "credential"
+	JSONEnvSuffix        = "_JSON"
+	DefaultDelimiter     = ","
+	HookFail             = "fail"
+	HookContinue         = "continue"
+	ProfileCPU           = "cpu"
+	ProfileMem           = "mem"
 	BinPattern           = "%s%s"
 	BinPathPattern       = "%s" + PathSeparator + "bin"
 	EnvPattern           = "%s=%s"
@@ -34,19 +60,82 @@ const (
 )
 
 type (
+	// Input is an input of the formula config.json. The stdout of FromCommand, run on
+	// the working dir, is the default of a text input or its value with --stdin.
+	// A multiselect input is a checkbox prompt of its Items, its Default are the items
+	// checked, separated by its Delimiter, "," when empty. The formula gets the selected
+	// items on its env var separated by the Delimiter and as a JSON array on the env var
+	// with the JSONEnvSuffix, e.g. REGIONS=us-east-1,sa-east-1 and
+	// REGIONS_JSON=["us-east-1","sa-east-1"].
+	// The Pattern of a text, password or secret input is a regex its value must match and, with a
+	// Min or Max, its value must be a number in the range, see Input.Validate.
+	// An int or float input is a text prompt whose value must be an integer or a number, with
+	// the Pattern, Min and Max of a text input, and gets a typed flag. The Default of an int,
+	// float or bool input must be of its type, a bool one is the item selected first.
+	// A path input is a file picker, its Pattern filters the files by name, e.g. *.tf. Its
+	// path must exist and the formula gets it absolute. The docker runs get the paths on the
+	// working dir on its /app mount and, with Mount, mount the other ones on the container.
+	// ItemsFrom reads the items of the input when it is prompted, see ItemsFrom.
+	// Condition asks the input only for some values of an earlier one, see Condition.
+	// The inputs of a Group, declared together, are asked on a page of a wizard showing the
+	// step of each page and going back to the previous one, see Pages.
+	// The Tutorial of an input is shown before its prompt. Locales have the label, tutorial
+	// and error text of the input in other languages by locale, e.g. pt-BR or es, the prompts
+	// show the ones of the locale of the user, see Input.Localize.
+	// The value of a Sensitive input is never cached, kept on the history nor shown on the
+	// run log, like the value of a password. A secret input is a password that is never
+	// informed by a flag either, so that it isn't kept on the shell history.
+	// An editor input opens $EDITOR for a multi-line content, e.g. a YAML manifest. The formula
+	// gets the path of a temp file with the content, its Pattern names the file, e.g. *.yaml,
+	// and the docker runs mount it on the container. With --stdin or an env var, the value
+	// informed is the content.
+	// A credential input lists the providers the user has a credential of, e.g. github, its
+	// Items keep the ones listed, an item also listing the other accounts of the provider,
+	// e.g. github-work for github. The formula gets the provider selected on its env var and
+	// each field of its credential on NAME_FIELD, e.g. GIT=github-work and GIT_TOKEN=...,
+	// instead of the credential of a fixed provider given by a CREDENTIAL_PROVIDER_FIELD type.
 	Input struct {
-		Name    string   `json:"name"`
-		Type    string   `json:"type"`
-		Default string   `json:"default"`
-		Label   string   `json:"label"`
-		Items   []string `json:"items"`
-		Cache   Cache    `json:"cache"`
+		Name        string                     `json:"name"`
+		Type        string                     `json:"type"`
+		Default     string                     `json:"default"`
+		Label       string                     `json:"label"`
+		Items       []string                   `json:"items"`
+		Cache       Cache                      `json:"cache"`
+		FromCommand string                     `json:"fromCommand,omitempty"`
+		Delimiter   string                     `json:"delimiter,omitempty"`
+		Pattern     string                     `json:"pattern,omitempty"`
+		Mount       bool                       `json:"mount,omitempty"`
+		Min         *float64                   `json:"min,omitempty"`
+		Max         *float64                   `json:"max,omitempty"`
+		ErrorText   string                     `json:"errorText,omitempty"`
+		Sensitive   bool                       `json:"sensitive,omitempty"`
+		ItemsFrom   *ItemsFrom                 `json:"itemsFrom,omitempty"`
+		Condition   *Condition                 `json:"condition,omitempty"`
+		Group       string                     `json:"group,omitempty"`
+		Tutorial    string                     `json:"tutorial,omitempty"`
+		Locales     map[string]InputLocale     `json:"locales,omitempty"`
+		Extra       map[string]json.RawMessage `json:"-"`
 	}
 
+	// ItemsFrom is where the items of an input are read from when it is prompted, instead of
+	// its Items, e.g. the clusters or the repos of the user: the lines of the stdout of Command,
+	// run on the working dir, the response of a GET of URL or the lines of File, relative to
+	// the working dir. A JSON array of strings is read as the items too. Like the label, they
+	// can use the values of the earlier inputs, e.g. "git -C {{ .repo }} branch --format ...",
+	// quoted on Command as single shell words.
+	ItemsFrom struct {
+		Command string `json:"command,omitempty"`
+		URL     string `json:"url,omitempty"`
+		File    string `json:"file,omitempty"`
+	}
+
+	// Cache keeps the last Qty values of an input, DefaultCacheQty when 0, prompting them as
+	// its items. With a TTL, e.g. "24h", they expire TTL after the last run that cached one.
 	Cache struct {
 		Active   bool   `json:"active"`
 		Qty      int    `json:"qty"`
 		NewLabel string `json:"newLabel"`
+		TTL      string `json:"ttl,omitempty"`
 	}
 	Create struct {
 		FormulaCmd    string `json:"formulaCmd"`
@@ -55,36 +144,205 @@ type (
 		FormulaPath   string `json:"formulaPath"`
 	}
 
+	// Config type of the formula config.json, Description is the short description of
+	// the lists and LongDesc and Examples are shown on the help of the formula command.
+	// AllowOverride lets the runs replace the formula binary or image entrypoint, see
+	// Definition.Entrypoint, e.g. to debug the formula on its own env.
+	// Outputs are the results the formula writes on the file of the OutputsEnv env var.
+	// Sidecars are the services the docker runs of the formula start with it, see Sidecar.
+	// Resources are the default limits of the container of its docker runs.
+	// Images are the images it is built from by architecture, as runtime.GOARCH, e.g. amd64
+	// and arm64, for the base images without a manifest list. The image of the architecture
+	// of rit is the ImageEnv build arg of its Dockerfile, e.g. ARG RIT_IMAGE=node:14 then
+	// FROM $RIT_IMAGE, and the ImageEnv env var of rit build formula, with ArchEnv.
+	// Volumes are the host paths its docker runs mount, as HOST:CONTAINER or HOST:CONTAINER:ro,
+	// e.g. ~/.kube:/root/.kube:ro, the host paths must be on the formula.volumes config.
+	// PreRun and PostRun are the hooks that run before and after the formula, see Hook.
+	// Retry is the default retry of its failed runs, see Retry.
+	// Requires are the tools its local runs need on the PATH, with an optional version as
+	// >=, >, <=, < or = a version, e.g. kubectl, docker>=20 and node>=16, checked before it runs.
 	Config struct {
-		Name        string  `json:"name"`
-		Command     string  `json:"command"`
-		Description string  `json:"description"`
-		Language    string  `json:"language"`
-		Inputs      []Input `json:"inputs"`
+		SchemaVersion int                        `json:"schemaVersion,omitempty"`
+		Name          string                     `json:"name"`
+		Command       string                     `json:"command"`
+		Description   string                     `json:"description"`
+		LongDesc      string                     `json:"longDescription,omitempty"`
+		Examples      []api.Example              `json:"examples,omitempty"`
+		Language      string                     `json:"language"`
+		Inputs        []Input                    `json:"inputs"`
+		Isolation     *Isolation                 `json:"isolation,omitempty"`
+		AllowOverride bool                       `json:"allowOverride,omitempty"`
+		Outputs       []Output                   `json:"outputs,omitempty"`
+		Sidecars      []Sidecar                  `json:"sidecars,omitempty"`
+		Resources     *Resources                 `json:"resources,omitempty"`
+		Volumes       []string                   `json:"volumes,omitempty"`
+		Images        map[string]string          `json:"images,omitempty"`
+		PreRun        []Hook                     `json:"preRun,omitempty"`
+		PostRun       []Hook                     `json:"postRun,omitempty"`
+		Retry         *Retry                     `json:"retry,omitempty"`
+		Requires      []string                   `json:"requires,omitempty"`
+		Extra         map[string]json.RawMessage `json:"-"`
+	}
+
+	// Hook is a formula or a script that runs on the host of rit before or after the
+	// formula, in order. Formula is the command path of a formula, without rit, run
+	// with the terminal to prompt its inputs. Script is a shell command run on the working
+	// dir, the files of the formula are on the dir of the HookFormulaDirEnv env var.
+	// OnFailure is HookFail, the default, or HookContinue: a failed preRun hook stops the
+	// run and a failed postRun hook fails it, unless they continue. The postRun hooks run
+	// after a successful run and, when Always, after a failed one too, as a teardown, with
+	// the failure on the FailedCommandEnv, FailedExitCodeEnv and FailedErrorEnv env vars.
+	Hook struct {
+		Formula   string `json:"formula,omitempty"`
+		Script    string `json:"script,omitempty"`
+		OnFailure string `json:"onFailure,omitempty"`
+		Always    bool   `json:"always,omitempty"`
+	}
+
+	// Retry runs the formula again when it fails with a nonzero exit code, e.g. on a flaky
+	// network or a rate limit. MaxRetries is the number of retries, Backoff the wait before
+	// the first one, e.g. 10s, doubled on each retry, and On the exit codes that are retried,
+	// any nonzero one when empty. The --max-retries, --retry-backoff and --retry-on flags of
+	// the run replace them.
+	Retry struct {
+		MaxRetries int    `json:"maxRetries,omitempty"`
+		Backoff    string `json:"backoff,omitempty"`
+		On         []int  `json:"on,omitempty"`
+	}
+
+	// Resources limits the container of a docker run, CPUs is a number of CPUs, e.g. 1.5,
+	// and Memory a docker memory size, e.g. 512m or 2g. Empty values don't limit it.
+	Resources struct {
+		CPUs   string `json:"cpus,omitempty"`
+		Memory string `json:"memory,omitempty"`
+	}
+
+	// Sidecar is a service container, e.g. a database, the docker runs of the formula start
+	// before it and remove after it. The formula reaches it on a network of the run by its
+	// Name, e.g. postgres:5432. Env and Command configure the container and Ready, when set,
+	// is a command run on the sidecar until it succeeds before the formula starts, e.g.
+	// ["pg_isready"].
+	Sidecar struct {
+		Name    string            `json:"name"`
+		Image   string            `json:"image"`
+		Env     map[string]string `json:"env,omitempty"`
+		Command []string          `json:"command,omitempty"`
+		Ready   []string          `json:"ready,omitempty"`
 	}
 
-	// Definition type that represents a Formula
+	// Output is a result of the formula, e.g. the id or the url of what it created. The formula
+	// writes a NAME=VALUE line per output on the file of the OutputsEnv env var and rit prints
+	// the declared ones after a successful local or docker run.
+	Output struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// Isolation declares the files of the working directory an isolated run, see
+	// Definition.Isolate, works on. Inputs are copied to the temp workspace before
+	// the run and Outputs are copied back after a successful run. Both are glob
+	// patterns relative to the working directory, e.g. "src", "*.tf", "dist/*".
+	Isolation struct {
+		Inputs  []string `json:"inputs,omitempty"`
+		Outputs []string `json:"outputs,omitempty"`
+	}
+
+	// Definition type that represents a Formula.
+	// Command is the rit command path that runs it, e.g. "rit aws create".
+	// Args are the args informed after "--", passed verbatim to the formula.
+	// Env are extra env vars of the run, the host ones of --env-passthrough first, and Stdin,
+	// when set, replaces os.Stdin.
+	// Session runs the formula on a docker container kept between the runs and
+	// Isolate runs it on a temp copy of the working directory, see Isolation.
+	// Timeout stops the formula once it runs for longer, zero lets it run until it exits.
+	// KillGrace is how long the formula has to exit after it is asked to stop, before it is killed.
+	// Labels are the --label metadata of the run kept on its run log.
+	// CaptureMetrics is the file the run metrics are appended to, see metrics.RunEvent.
+	// Profile reports the time of each run phase and, with ProfileCPU or ProfileMem, the CPU
+	// time or the peak memory of the formula process, as JSON with ProfileJSON.
+	// RequiredFirst prompts the required inputs before the optional ones, see RequiredFirst.
+	// InputTimeout accepts the default of a prompt nobody answered in time, the prompts
+	// without a default keep waiting or, with InputTimeoutFail, fail the run.
+	// AcceptDefaults takes the default of every input with one instead of asking it.
+	// Entrypoint replaces the entrypoint of the formula image on a docker run and
+	// CommandOverride runs a shell command instead of the formula binary on a local run,
+	// both only for the formulas with Config.AllowOverride.
+	// PrintCommand prints the command line of the run, its secrets masked, before it runs.
+	// DryRun prints the command line and the env of the run, its secrets masked, instead of running it.
+	// Remote is the ssh host the formula runs on, as user@host or a host of ~/.ssh/config.
+	// Kubernetes runs the formula image as a Kubernetes Job, see runner.KubernetesRunner.
+	// OutputFormat prints the Config.Outputs of the run as json or yaml, moving the formula
+	// stdout to stderr, empty prints them as text.
+	// Resources are the --cpus and --memory limits of a docker run, each one replaces the
+	// limit of Config.Resources. Volumes are the --volume mounts of a docker run, besides
+	// the Config.Volumes. A session container keeps the limits and volumes it started with.
+	// Sandbox runs a local formula on its own user and network namespaces on Linux, with a
+	// private tmp dir, SandboxNetwork keeps its network.
+	// Timestamps prefixes each line of the formula output with the time and the Command.
+	// LogID is the ID of the run log, set on the runs started with --detach, see detach.Run.
 	Definition struct {
-		Path     string
-		Bin      string
-		LBin     string
-		MBin     string
-		WBin     string
-		Bundle   string
-		Config   string
-		RepoURL  string
-		RepoName string
+		Command          string
+		Args             []string
+		Env              []string
+		Stdin            io.Reader
+		Session          bool
+		Isolate          bool
+		Timeout          time.Duration
+		KillGrace        time.Duration
+		Labels           map[string]string
+		CaptureMetrics   string
+		Profile          string
+		ProfileJSON      bool
+		RequiredFirst    bool
+		InputTimeout     time.Duration
+		InputTimeoutFail bool
+		AcceptDefaults   bool
+		Entrypoint       string
+		CommandOverride  string
+		PrintCommand     bool
+		DryRun           bool
+		Remote           string
+		Kubernetes       bool
+		OutputFormat     string
+		Resources        Resources
+		Volumes          []string
+		Sandbox          bool
+		SandboxNetwork   bool
+		Timestamps       bool
+		LogID            string
+		Path             string
+		Bin              string
+		LBin             string
+		MBin             string
+		WBin             string
+		Bundle           string
+		Config           string
+		RepoURL          string
+		RepoName         string
+
+		// Inputs, when not nil, gets the values the inputs of the run are answered with, by
+		// input name, so that a retry of the run informs them by env instead of asking them again
+		Inputs map[string]string
+		// Runner is the container engine of the run, docker or podman, or the runner plugin
+		// running it in place of the container, empty is docker
+		Runner string
 	}
 
 	Setup struct {
-		Pwd            string
-		FormulaPath    string
-		BinPath        string
-		TmpDir         string
-		TmpBinDir      string
-		TmpBinFilePath string
-		Config         Config
-		ContainerId    string
+		Pwd              string
+		FormulaPath      string
+		BinPath          string
+		TmpDir           string
+		TmpBinDir        string
+		TmpBinFilePath   string
+		Config           Config
+		ContainerId      string
+		RequiredFirst    bool
+		InputTimeout     time.Duration
+		InputTimeoutFail bool
+		AcceptDefaults   bool
+		// Engine is the container engine of the run, see runner.Engine
+		Engine string
 	}
 )
 
@@ -96,6 +354,46 @@ type Runner interface {
 	Run(def Definition, inputType api.TermInputType, verboseFlag string) error
 }
 
+type Puller interface {
+	Pull(def Definition) error
+}
+
+// ImagePull is the pull of a docker image the Formulas, by command, are built from
+type ImagePull struct {
+	Image    string
+	Formulas []string
+	Err      error
+}
+
+// ImageCache keeps the docker images of the installed formulas on the machine
+type ImageCache interface {
+	// PullAll pulls the images the formulas are built from, up to parallelism at a time
+	PullAll(defs []Definition, parallelism int) ([]ImagePull, error)
+	// Unused returns the images rit built or pulled that none of the formulas is built from
+	Unused(defs []Definition) ([]string, error)
+	// Remove removes the images from the machine
+	Remove(images []string) error
+}
+
+// ErrRefreshUnsupported is returned by the Refresher when the formula repository
+// doesn't serve the files of each formula, so only the whole repository can be updated
+var ErrRefreshUnsupported = errors.New("the formula repository doesn't support updating a single formula")
+
+// Refresher downloads the files of a formula again from its repository
+type Refresher interface {
+	Refresh(def Definition) error
+}
+
+// SessionStopper stops the docker session of a formula started by a run with Definition.Session
+type SessionStopper interface {
+	StopSession(def Definition) error
+}
+
+// SelfTester runs a built-in formula to check that the runner works end to end
+type SelfTester interface {
+	SelfTest() error
+}
+
 type PostRunner interface {
 	PostRun(p Setup, docker bool) error
 }
@@ -108,6 +406,11 @@ type Setuper interface {
 	Setup(def Definition) (Setup, error)
 }
 
+// ConfigLoader reads the config.json of a formula, downloading it when the formula never ran
+type ConfigLoader interface {
+	LoadConfig(def Definition) (Config, error)
+}
+
 type Creator interface {
 	Create(cf Create) error
 }
@@ -125,6 +428,36 @@ type CreateBuilder interface {
 	Builder
 }
 
+func (c *Config) UnmarshalJSON(b []byte) error {
+	type config Config
+	extra, err := jsonutil.UnmarshalExtra(b, (*config)(c))
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	return nil
+}
+
+func (c Config) MarshalJSON() ([]byte, error) {
+	type config Config
+	return jsonutil.MarshalExtra(config(c), c.Extra)
+}
+
+func (i *Input) UnmarshalJSON(b []byte) error {
+	type input Input
+	extra, err := jsonutil.UnmarshalExtra(b, (*input)(i))
+	if err != nil {
+		return err
+	}
+	i.Extra = extra
+	return nil
+}
+
+func (i Input) MarshalJSON() ([]byte, error) {
+	type input Input
+	return jsonutil.MarshalExtra(input(i), i.Extra)
+}
+
 // FormulaPath builds the formula path from ritchie home
 func (d *Definition) FormulaPath(home string) string {
 	return fmt.Sprintf(PathPattern, home, d.Path)
@@ -0,0 +1,189 @@
+// Package history keeps every formula run, so the runs can be audited with
+// rit history and run again with rit rerun. The secret inputs are never kept.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/stream"
+)
+
+const (
+	// File is the history file pattern, relative to the ritchie home, a run per JSON line
+	File = "%s/history/runs.jsonl"
+	// MaxEntries is the number of runs kept, the oldest ones are pruned
+	MaxEntries = 1000
+
+	idLayout = "20060102T150405.000000"
+	// pruneSlack are the runs appended beyond MaxEntries before the file is rewritten
+	pruneSlack = MaxEntries / 10
+)
+
+// Entry is a formula run. Args and Labels are the redacted args and --label values
+// of the run, Inputs are the values of its text and bool inputs, redacted too, and
+// InputsHash identifies them. The secret inputs are left out of both, as the hash of
+// a few values can be brute forced.
+// Version is the version of the repository tree the formula came from and ExitCode
+// is the formula exit code, or -1 when it didn't exit on its own.
+type Entry struct {
+	ID         string            `json:"id"`
+	Command    string            `json:"command"`
+	Formula    string            `json:"formula"`
+	Repo       string            `json:"repo,omitempty"`
+	Version    string            `json:"version,omitempty"`
+	Args       []string          `json:"args,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Inputs     map[string]string `json:"inputs,omitempty"`
+	InputsHash string            `json:"inputsHash,omitempty"`
+	Start      time.Time         `json:"start"`
+	Duration   float64           `json:"durationSeconds"`
+	ExitCode   int               `json:"exitCode"`
+}
+
+type Recorder interface {
+	Record(e Entry) error
+}
+
+type Lister interface {
+	List() ([]Entry, error)
+}
+
+type Manager struct {
+	file     string
+	enabled  bool
+	versions formula.RepoVersioner
+}
+
+// NewManager creates the history manager, when disabled no run is recorded.
+// The versions resolve the version of the repository of each run.
+func NewManager(ritchieHome string, enabled bool, versions formula.RepoVersioner) Manager {
+	return Manager{file: fmt.Sprintf(File, ritchieHome), enabled: enabled, versions: versions}
+}
+
+// Record appends the run to the history, its ID is its start
+func (m Manager) Record(e Entry) error {
+	if !m.enabled {
+		return nil
+	}
+
+	e.ID = e.Start.Format(idLayout)
+	if e.Version == "" && e.Repo != "" && m.versions != nil {
+		e.Version = m.versions.Version(e.Repo)
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if err := fileutil.CreateDirIfNotExists(filepath.Dir(m.file), 0755); err != nil {
+		return err
+	}
+
+	// the lock keeps the runs other rit processes append while the file is pruned
+	unlock, err := stream.Lock(m.file)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := os.OpenFile(m.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return m.prune()
+}
+
+// List returns the runs from the oldest to the newest, the lines that aren't a run are skipped
+func (m Manager) List() ([]Entry, error) {
+	lines, err := m.lines()
+	if err != nil {
+		return nil, err
+	}
+
+	ee := make([]Entry, 0, len(lines))
+	for _, l := range lines {
+		var e Entry
+		if err := json.Unmarshal(l, &e); err != nil {
+			continue
+		}
+		ee = append(ee, e)
+	}
+
+	sort.SliceStable(ee, func(i, j int) bool {
+		return ee[i].Start.Before(ee[j].Start)
+	})
+	return ee, nil
+}
+
+func (m Manager) lines() ([][]byte, error) {
+	b, err := ioutil.ReadFile(m.file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var lines [][]byte
+	s := bufio.NewScanner(bytes.NewReader(b))
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for s.Scan() {
+		if l := bytes.TrimSpace(s.Bytes()); len(l) > 0 {
+			lines = append(lines, append([]byte(nil), l...))
+		}
+	}
+	return lines, s.Err()
+}
+
+// prune keeps only the newest MaxEntries runs, the file is rewritten once it has
+// pruneSlack runs more so most runs only append their line. It is called holding the
+// lock of the file, which is replaced at once so that List never reads it partially.
+func (m Manager) prune() error {
+	lines, err := m.lines()
+	if err != nil || len(lines) <= MaxEntries+pruneSlack {
+		return err
+	}
+
+	lines = lines[len(lines)-MaxEntries:]
+	return stream.WriteAtomic(m.file, append(bytes.Join(lines, []byte("\n")), '\n'), 0600)
+}
+
+// InputsHash identifies the input values of a run by their names, empty without inputs
+func InputsHash(inputs map[string]string) string {
+	if len(inputs) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(inputs))
+	for n := range inputs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, n := range names {
+		b, _ := json.Marshal([]string{n, inputs[n]})
+		h.Write(append(b, '\n'))
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)[:12])
+}
@@ -0,0 +1,138 @@
+package history
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type versionerMock map[string]string
+
+func (v versionerMock) Version(name string) string {
+	return v[name]
+}
+
+func TestManager(t *testing.T) {
+	home, err := ioutil.TempDir("", "rit-history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	m := NewManager(home, true, versionerMock{"commons": "sha256:0f3c"})
+	start := time.Date(2020, 7, 20, 10, 0, 0, 0, time.UTC)
+	runs := []Entry{
+		{Command: "rit aws delete", Formula: "aws/delete", Start: start.Add(time.Second), ExitCode: 3},
+		{Command: "rit aws create", Formula: "aws/create", Repo: "commons", Start: start, Duration: 1.5, InputsHash: "sha256:9a1b"},
+	}
+	for _, e := range runs {
+		if err := m.Record(e); err != nil {
+			t.Fatalf("Record() = %v, want nil", err)
+		}
+	}
+
+	got, err := m.List()
+	if err != nil {
+		t.Fatalf("List() = %v, want nil", err)
+	}
+	want := []Entry{
+		{ID: "20200720T100000.000000", Command: "rit aws create", Formula: "aws/create", Repo: "commons",
+			Version: "sha256:0f3c", Start: start, Duration: 1.5, InputsHash: "sha256:9a1b"},
+		{ID: "20200720T100001.000000", Command: "rit aws delete", Formula: "aws/delete", Start: start.Add(time.Second), ExitCode: 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %+v, want %+v", got, want)
+	}
+}
+
+func TestManager_Disabled(t *testing.T) {
+	home, err := ioutil.TempDir("", "rit-history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	m := NewManager(home, false, nil)
+	if err := m.Record(Entry{Command: "rit aws create", Start: time.Now()}); err != nil {
+		t.Fatalf("Record() = %v, want nil", err)
+	}
+	if got, err := m.List(); err != nil || len(got) != 0 {
+		t.Errorf("List() = %v, %v, want no runs", got, err)
+	}
+}
+
+func TestManager_Prune(t *testing.T) {
+	home, err := ioutil.TempDir("", "rit-history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	m := NewManager(home, true, nil)
+	start := time.Date(2020, 7, 20, 10, 0, 0, 0, time.UTC)
+	for i := 0; i <= MaxEntries+pruneSlack; i++ {
+		if err := m.Record(Entry{Command: "rit aws create", Start: start.Add(time.Duration(i) * time.Second)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := m.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != MaxEntries || !got[len(got)-1].Start.Equal(start.Add(time.Duration(MaxEntries+pruneSlack)*time.Second)) {
+		t.Errorf("List() got %d runs, want the newest %d", len(got), MaxEntries)
+	}
+	if _, err := os.Stat(filepath.Join(home, "history", "runs.jsonl")); err != nil {
+		t.Errorf("history file stat got %v", err)
+	}
+}
+
+func TestManager_PruneConcurrent(t *testing.T) {
+	home, err := ioutil.TempDir("", "rit-history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	m := NewManager(home, true, nil)
+	start := time.Date(2020, 7, 20, 10, 0, 0, 0, time.UTC)
+	const writers, runs = 4, (MaxEntries + pruneSlack + 100) / 4
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < runs; i++ {
+				e := Entry{Command: "rit aws create", Start: start.Add(time.Duration(w*runs+i) * time.Second)}
+				if err := m.Record(e); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	// the file is pruned once, the runs appended by the other writers meanwhile are kept
+	got, err := m.List()
+	if want := MaxEntries + writers*runs - (MaxEntries + pruneSlack + 1); err != nil || len(got) != want {
+		t.Errorf("List() got %d runs, %v, want %d", len(got), err, want)
+	}
+}
+
+func TestInputsHash(t *testing.T) {
+	a := InputsHash(map[string]string{"region": "sa-east-1", "name": "bucket"})
+	b := InputsHash(map[string]string{"name": "bucket", "region": "sa-east-1"})
+	c := InputsHash(map[string]string{"name": "bucket", "region": "us-east-1"})
+	if a != b || a == c || len(a) != len("sha256:")+24 {
+		t.Errorf("InputsHash() = %q, %q and %q, want the same hash for the same inputs only", a, b, c)
+	}
+	if got := InputsHash(nil); got != "" {
+		t.Errorf("InputsHash(nil) = %q, want empty", got)
+	}
+}
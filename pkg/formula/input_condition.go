@@ -0,0 +1,74 @@
+package formula
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	msgInvalidOperator    = "invalid operator %q on the condition of the input %q, use ==, !=, >, >=, < or <="
+	msgConditionReference = "the condition of the input %q references %q, which is not an earlier input"
+	msgConditionNumbers   = "the condition of the input %q compares %q and %q, which are not both numbers"
+)
+
+// Condition asks an input only when the value of an earlier input, Variable, compares with
+// Value by Operator, e.g. {"variable": "useDatabase", "operator": "==", "value": "yes"}.
+// The values are compared as text by == and != and as numbers by >, >=, < and <=. An input
+// that isn't asked has no value, an empty text for == and != and never met by the others.
+type Condition struct {
+	Variable string `json:"variable"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+// validateCondition checks the operator of the condition and that it is on an earlier input
+func validateCondition(in Input, earlier map[string]bool) error {
+	c := in.Condition
+	switch c.Operator {
+	case "==", "!=", ">", ">=", "<", "<=":
+	default:
+		return fmt.Errorf(msgInvalidOperator, c.Operator, in.Name)
+	}
+	if !earlier[c.Variable] {
+		return fmt.Errorf(msgConditionReference, in.Name, c.Variable)
+	}
+	return nil
+}
+
+// Asked tells whether the input is asked with the values of the earlier inputs,
+// the inputs without a condition are always asked
+func (in Input) Asked(values map[string]string) (bool, error) {
+	c := in.Condition
+	if c == nil {
+		return true, nil
+	}
+
+	v := values[c.Variable]
+	switch c.Operator {
+	case "==":
+		return v == c.Value, nil
+	case "!=":
+		return v != c.Value, nil
+	}
+
+	if v == "" {
+		return false, nil
+	}
+	a, errA := strconv.ParseFloat(v, 64)
+	b, errB := strconv.ParseFloat(c.Value, 64)
+	if errA != nil || errB != nil {
+		return false, fmt.Errorf(msgConditionNumbers, in.Name, v, c.Value)
+	}
+	switch c.Operator {
+	case ">":
+		return a > b, nil
+	case ">=":
+		return a >= b, nil
+	case "<":
+		return a < b, nil
+	case "<=":
+		return a <= b, nil
+	default:
+		return false, fmt.Errorf(msgInvalidOperator, c.Operator, in.Name)
+	}
+}
@@ -0,0 +1,32 @@
+package formula
+
+import "testing"
+
+func TestInputAsked(t *testing.T) {
+	values := map[string]string{"engine": "postgres", "replicas": "3", "name": "db"}
+	tests := []struct {
+		name      string
+		condition *Condition
+		want      bool
+		wantErr   bool
+	}{
+		{name: "without condition", want: true},
+		{name: "equal", condition: &Condition{Variable: "engine", Operator: "==", Value: "postgres"}, want: true},
+		{name: "not equal", condition: &Condition{Variable: "engine", Operator: "!=", Value: "postgres"}},
+		{name: "equal to an input not asked", condition: &Condition{Variable: "port", Operator: "==", Value: ""}, want: true},
+		{name: "greater than", condition: &Condition{Variable: "replicas", Operator: ">", Value: "2"}, want: true},
+		{name: "less or equal", condition: &Condition{Variable: "replicas", Operator: "<=", Value: "2.5"}},
+		{name: "number of an input not asked", condition: &Condition{Variable: "port", Operator: ">=", Value: "0"}},
+		{name: "number of a text", condition: &Condition{Variable: "name", Operator: "<", Value: "2"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := Input{Name: "zone", Condition: tt.condition}
+			got, err := in.Asked(values)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Asked() got %v, %v, want %v, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
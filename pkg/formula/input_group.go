@@ -0,0 +1,35 @@
+package formula
+
+import "fmt"
+
+const msgSplitGroup = "the inputs of the group %q must be declared together, %q is apart from them"
+
+// validateGroups checks the inputs of each group are declared together
+func validateGroups(inputs []Input) error {
+	seen := make(map[string]bool)
+	prev := ""
+	for _, in := range inputs {
+		if in.Group != prev && seen[in.Group] {
+			return fmt.Errorf(msgSplitGroup, in.Group, in.Name)
+		}
+		if in.Group != "" {
+			seen[in.Group] = true
+		}
+		prev = in.Group
+	}
+	return nil
+}
+
+// Pages splits the inputs into the pages of their groups, in order, the inputs without
+// a group between two groups are a page of their own. The inputs without any group are
+// a single page.
+func Pages(inputs []Input) [][]Input {
+	var pages [][]Input
+	for i, in := range inputs {
+		if i == 0 || in.Group != inputs[i-1].Group {
+			pages = append(pages, nil)
+		}
+		pages[len(pages)-1] = append(pages[len(pages)-1], in)
+	}
+	return pages
+}
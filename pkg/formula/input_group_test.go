@@ -0,0 +1,46 @@
+package formula
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPages(t *testing.T) {
+	host := Input{Name: "host", Group: "Database"}
+	port := Input{Name: "port", Group: "Database"}
+	name := Input{Name: "name"}
+	replicas := Input{Name: "replicas", Group: "Deploy"}
+
+	tests := []struct {
+		name   string
+		inputs []Input
+		want   [][]Input
+	}{
+		{name: "no groups", inputs: []Input{name, name}, want: [][]Input{{name, name}}},
+		{name: "groups", inputs: []Input{host, port, replicas}, want: [][]Input{{host, port}, {replicas}}},
+		{name: "inputs between groups", inputs: []Input{host, port, name, replicas}, want: [][]Input{{host, port}, {name}, {replicas}}},
+		{name: "no inputs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Pages(tt.inputs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Pages() got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateGroups(t *testing.T) {
+	host := Input{Name: "host", Group: "Database"}
+	port := Input{Name: "port", Group: "Database"}
+	name := Input{Name: "name"}
+
+	if err := ValidateInputs([]Input{name, host, port, name}); err != nil {
+		t.Errorf("ValidateInputs() got %v, want nil", err)
+	}
+	want := `the inputs of the group "Database" must be declared together, "port" is apart from them`
+	if err := ValidateInputs([]Input{host, name, port}); err == nil || err.Error() != want {
+		t.Errorf("ValidateInputs() got %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,65 @@
+package formula
+
+import (
+	"sort"
+	"strings"
+)
+
+// InputLocale is the text of an input in a locale, the empty fields keep the ones of the input
+type InputLocale struct {
+	Label     string `json:"label,omitempty"`
+	Tutorial  string `json:"tutorial,omitempty"`
+	ErrorText string `json:"errorText,omitempty"`
+}
+
+// Localize returns the input with its label, tutorial and error text in the locale, e.g. pt-BR,
+// or in its language, pt, when the input has no text in the locale. The locales are matched
+// case-insensitively and with - or _, as pt_BR.
+func (in Input) Localize(locale string) Input {
+	if len(in.Locales) == 0 || locale == "" {
+		return in
+	}
+
+	byLocale := make(map[string]InputLocale, len(in.Locales))
+	for l, text := range in.Locales {
+		byLocale[normalizeLocale(l)] = text
+	}
+	locale = normalizeLocale(locale)
+	text, ok := byLocale[locale]
+	if !ok {
+		text, ok = byLocale[strings.SplitN(locale, "-", 2)[0]]
+	}
+	if !ok {
+		return in
+	}
+
+	if text.Label != "" {
+		in.Label = text.Label
+	}
+	if text.Tutorial != "" {
+		in.Tutorial = text.Tutorial
+	}
+	if text.ErrorText != "" {
+		in.ErrorText = text.ErrorText
+	}
+	return in
+}
+
+// localeLabels returns the localized labels of the input, sorted by locale
+func (in Input) localeLabels() []string {
+	locales := make([]string, 0, len(in.Locales))
+	for l := range in.Locales {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+
+	labels := make([]string, 0, len(locales))
+	for _, l := range locales {
+		labels = append(labels, in.Locales[l].Label)
+	}
+	return labels
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.Replace(locale, "_", "-", -1))
+}
@@ -0,0 +1,36 @@
+package formula
+
+import "testing"
+
+func TestLocalize(t *testing.T) {
+	in := Input{
+		Name:     "name",
+		Label:    "Type your name:",
+		Tutorial: "The name of the project",
+		Locales: map[string]InputLocale{
+			"pt-BR": {Label: "Digite seu nome:", Tutorial: "O nome do projeto"},
+			"es":    {Label: "Escribe tu nombre:"},
+		},
+	}
+
+	tests := []struct {
+		locale       string
+		wantLabel    string
+		wantTutorial string
+	}{
+		{locale: "pt-BR", wantLabel: "Digite seu nome:", wantTutorial: "O nome do projeto"},
+		{locale: "pt_br", wantLabel: "Digite seu nome:", wantTutorial: "O nome do projeto"},
+		{locale: "es-AR", wantLabel: "Escribe tu nombre:", wantTutorial: "The name of the project"},
+		{locale: "fr", wantLabel: "Type your name:", wantTutorial: "The name of the project"},
+		{locale: "", wantLabel: "Type your name:", wantTutorial: "The name of the project"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			got := in.Localize(tt.locale)
+			if got.Label != tt.wantLabel || got.Tutorial != tt.wantTutorial {
+				t.Errorf("Localize(%q) got %q, %q, want %q, %q", tt.locale, got.Label, got.Tutorial, tt.wantLabel, tt.wantTutorial)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package formula
+
+// Required tells whether the input must be answered on the prompt, the text, password, secret,
+// editor, int, float, credential and multiselect inputs without a default or a command computing it
+func (in Input) Required() bool {
+	switch in.Type {
+	case "text", "password", SecretType, EditorType, IntType, FloatType, CredentialType, MultiselectType:
+		return in.Default == "" && in.FromCommand == ""
+	default:
+		return false
+	}
+}
+
+// RequiredFirst orders the inputs to prompt the required ones before the optional ones.
+// The order is only changed as far as the input templates allow:
+//   - an input is still asked after every input its label, default or condition references,
+//     so the optional inputs referenced by a required one are asked along with it;
+//   - the required inputs keep their order, and so do the optional ones.
+func RequiredFirst(inputs []Input) []Input {
+	byName := make(map[string]Input, len(inputs))
+	for _, in := range inputs {
+		byName[in.Name] = in
+	}
+
+	first := make(map[string]bool)
+	var pull func(in Input)
+	pull = func(in Input) {
+		if first[in.Name] {
+			return
+		}
+		first[in.Name] = true
+		if in.Condition != nil {
+			if ref, ok := byName[in.Condition.Variable]; ok {
+				pull(ref)
+			}
+		}
+		for _, text := range []string{in.Label, in.Default} {
+			refs, _ := templateRefs(text)
+			for _, r := range refs {
+				if ref, ok := byName[r]; ok {
+					pull(ref)
+				}
+			}
+		}
+	}
+	for _, in := range inputs {
+		if in.Required() {
+			pull(in)
+		}
+	}
+
+	ordered := make([]Input, 0, len(inputs))
+	for _, in := range inputs {
+		if first[in.Name] {
+			ordered = append(ordered, in)
+		}
+	}
+	for _, in := range inputs {
+		if !first[in.Name] {
+			ordered = append(ordered, in)
+		}
+	}
+	return ordered
+}
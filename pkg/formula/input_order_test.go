@@ -0,0 +1,71 @@
+package formula
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRequiredFirst(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []Input
+		want []string
+	}{
+		{
+			name: "required before optional",
+			in: []Input{
+				{Name: "region", Type: "text", Default: "sa-east-1"},
+				{Name: "name", Type: "text"},
+				{Name: "public", Type: "bool"},
+				{Name: "pass", Type: "password"},
+				{Name: "token", Type: "CREDENTIAL_GITHUB_TOKEN"},
+				{Name: "branch", Type: "text", FromCommand: "git rev-parse --abbrev-ref HEAD"},
+			},
+			want: []string{"name", "pass", "region", "public", "token", "branch"},
+		},
+		{
+			name: "optional referenced by a required",
+			in: []Input{
+				{Name: "env", Type: "text", Default: "dev"},
+				{Name: "size", Type: "text", Default: "small"},
+				{Name: "user", Type: "text", Default: "admin"},
+				{Name: "pass", Type: "password", Label: "Password of {{ .user }} on {{ .env }}:"},
+			},
+			want: []string{"env", "user", "pass", "size"},
+		},
+		{
+			name: "optional referencing a required",
+			in: []Input{
+				{Name: "size", Type: "text", Default: "small"},
+				{Name: "name", Type: "text"},
+				{Name: "bucket", Type: "text", Default: "{{ .name }}-bucket"},
+			},
+			want: []string{"name", "size", "bucket"},
+		},
+		{
+			name: "optional on the condition of a required",
+			in: []Input{
+				{Name: "size", Type: "text", Default: "small"},
+				{Name: "engine", Type: "text", Default: "postgres"},
+				{Name: "version", Type: "text", Condition: &Condition{Variable: "engine", Operator: "!=", Value: "sqlite"}},
+			},
+			want: []string{"engine", "version", "size"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ordered := RequiredFirst(tt.in)
+			var got []string
+			for _, in := range ordered {
+				got = append(got, in.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RequiredFirst got %v, want %v", got, tt.want)
+			}
+			if err := ValidateInputs(ordered); err != nil {
+				t.Errorf("RequiredFirst broke the template order: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package formula
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+const (
+	templateDelim         = "{{"
+	msgInvalidTemplate    = "invalid template on the %s of the input %q: %v"
+	msgUndefinedReference = "the %s of the input %q references %q, which is not an earlier input"
+	msgInvalidItemsFrom   = "the itemsFrom of the input %q needs one of command, url or file"
+	msgInvalidCacheTTL    = "invalid cache ttl %q of the input %q, use a duration like 12h or 30m"
+)
+
+// ValidateInputs checks the input groups, templates, conditions, validation rules and cache ttl. The label, default and itemsFrom of an input
+// can use the values of the earlier inputs, e.g. "Password for {{ .username }}",
+// since the inputs are resolved in order a reference to a later or unknown input is an error.
+func ValidateInputs(inputs []Input) error {
+	if err := validateGroups(inputs); err != nil {
+		return err
+	}
+	earlier := make(map[string]bool)
+	for _, in := range inputs {
+		fields := []struct{ name, text string }{{"label", in.Label}, {"default", in.Default}}
+		for _, label := range in.localeLabels() {
+			fields = append(fields, struct{ name, text string }{"label", label})
+		}
+		if f := in.ItemsFrom; f != nil {
+			set := 0
+			for _, s := range []string{f.Command, f.URL, f.File} {
+				if s != "" {
+					set++
+				}
+			}
+			if set != 1 {
+				return fmt.Errorf(msgInvalidItemsFrom, in.Name)
+			}
+			fields = append(fields, struct{ name, text string }{"itemsFrom", f.Command + f.URL + f.File})
+		}
+		if err := validateRules(in); err != nil {
+			return err
+		}
+		if ttl := in.Cache.TTL; ttl != "" {
+			if _, err := time.ParseDuration(ttl); err != nil {
+				return fmt.Errorf(msgInvalidCacheTTL, ttl, in.Name)
+			}
+		}
+		if in.Condition != nil {
+			if err := validateCondition(in, earlier); err != nil {
+				return err
+			}
+		}
+		for _, f := range fields {
+			refs, err := templateRefs(f.text)
+			if err != nil {
+				return fmt.Errorf(msgInvalidTemplate, f.name, in.Name, err)
+			}
+
+			for _, r := range refs {
+				if !earlier[r] {
+					return fmt.Errorf(msgUndefinedReference, f.name, in.Name, r)
+				}
+			}
+		}
+		earlier[in.Name] = true
+	}
+	return nil
+}
+
+// RenderInput replaces the references to earlier inputs on the label, default
+// and itemsFrom of the input with their values, quoted as single shell words
+// on the itemsFrom command so an answer can't run commands of its own
+func RenderInput(in Input, values map[string]string) (Input, error) {
+	var err error
+	if in.Label, err = render(in.Label, values); err != nil {
+		return in, err
+	}
+	if in.Default, err = render(in.Default, values); err != nil {
+		return in, err
+	}
+	if in.ItemsFrom != nil {
+		f := *in.ItemsFrom
+		quoted := make(map[string]string, len(values))
+		for k, v := range values {
+			quoted[k] = shellQuote(v)
+		}
+		if f.Command, err = render(f.Command, quoted); err != nil {
+			return in, err
+		}
+		for _, s := range []*string{&f.URL, &f.File} {
+			if *s, err = render(*s, values); err != nil {
+				return in, err
+			}
+		}
+		in.ItemsFrom = &f
+	}
+	return in, nil
+}
+
+func render(text string, values map[string]string) (string, error) {
+	if !strings.Contains(text, templateDelim) {
+		return text, nil
+	}
+
+	tpl, err := template.New("input").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tpl.Execute(&b, values); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// shellQuote quotes the value as a single word of the shell running the itemsFrom commands,
+// sh or the cmd of windows
+func shellQuote(v string) string {
+	if runtime.GOOS == "windows" {
+		return `"` + strings.Replace(v, `"`, `""`, -1) + `"`
+	}
+	return "'" + strings.Replace(v, "'", `'\''`, -1) + "'"
+}
+
+// templateRefs returns the input names referenced by the text template
+func templateRefs(text string) ([]string, error) {
+	if !strings.Contains(text, templateDelim) {
+		return nil, nil
+	}
+
+	tpl, err := template.New("input").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []string
+	var walk func(n parse.Node)
+	walk = func(n parse.Node) {
+		switch n := n.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Nodes {
+				walk(c)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Cmds {
+				walk(c)
+			}
+		case *parse.CommandNode:
+			for _, a := range n.Args {
+				walk(a)
+			}
+		case *parse.FieldNode:
+			refs = append(refs, n.Ident[0])
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		}
+	}
+	walk(tpl.Tree.Root)
+
+	return refs, nil
+}
@@ -0,0 +1,148 @@
+package formula
+
+import "testing"
+
+func TestValidateInputs(t *testing.T) {
+	five, three := 5.0, 3.0
+	tests := []struct {
+		name    string
+		in      []Input
+		wantErr bool
+	}{
+		{
+			name: "without templates",
+			in:   []Input{{Name: "user", Label: "User:"}, {Name: "pass", Label: "Password:"}},
+		},
+		{
+			name: "reference to an earlier input",
+			in: []Input{
+				{Name: "user", Label: "User:"},
+				{Name: "pass", Label: "Password for {{ .user }}:", Default: "{{ if .user }}{{ .user }}123{{ end }}"},
+			},
+		},
+		{
+			name:    "reference to a later input",
+			in:      []Input{{Name: "pass", Label: "Password for {{ .user }}:"}, {Name: "user", Label: "User:"}},
+			wantErr: true,
+		},
+		{
+			name:    "reference to an unknown input on the default",
+			in:      []Input{{Name: "user", Label: "User:", Default: "{{ .login }}"}},
+			wantErr: true,
+		},
+		{
+			name: "items from a command referencing an earlier input",
+			in: []Input{
+				{Name: "repo", Label: "Repo:"},
+				{Name: "branch", Label: "Branch:", ItemsFrom: &ItemsFrom{Command: "git -C {{ .repo }} branch"}},
+			},
+		},
+		{
+			name:    "items from a url referencing a later input",
+			in:      []Input{{Name: "cluster", ItemsFrom: &ItemsFrom{URL: "https://clusters/{{ .org }}"}}, {Name: "org"}},
+			wantErr: true,
+		},
+		{
+			name:    "items from both a command and a file",
+			in:      []Input{{Name: "cluster", ItemsFrom: &ItemsFrom{Command: "kubectl config get-contexts -o name", File: "clusters.txt"}}},
+			wantErr: true,
+		},
+		{
+			name: "condition on an earlier input",
+			in: []Input{
+				{Name: "useDatabase", Type: "bool"},
+				{Name: "dbName", Condition: &Condition{Variable: "useDatabase", Operator: "==", Value: "true"}},
+			},
+		},
+		{
+			name:    "condition on a later input",
+			in:      []Input{{Name: "dbName", Condition: &Condition{Variable: "useDatabase", Operator: "==", Value: "true"}}, {Name: "useDatabase"}},
+			wantErr: true,
+		},
+		{
+			name:    "condition with an invalid operator",
+			in:      []Input{{Name: "replicas"}, {Name: "zone", Condition: &Condition{Variable: "replicas", Operator: "=>", Value: "2"}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid pattern of a text",
+			in:      []Input{{Name: "email", Type: "text", Pattern: "[a-z"}},
+			wantErr: true,
+		},
+		{
+			name:    "min greater than max",
+			in:      []Input{{Name: "replicas", Type: "text", Min: &five, Max: &three}},
+			wantErr: true,
+		},
+		{
+			name: "typed defaults",
+			in: []Input{
+				{Name: "replicas", Type: IntType, Default: "3", Max: &five},
+				{Name: "ratio", Type: FloatType, Default: "0.5"},
+				{Name: "public", Type: "bool", Default: "false"},
+				{Name: "confirm", Type: "bool", Default: "yes", Items: []string{"yes", "no"}},
+				{Name: "zones", Type: IntType, Default: "{{ .replicas }}"},
+			},
+		},
+		{
+			name:    "int default not an integer",
+			in:      []Input{{Name: "replicas", Type: IntType, Default: "three"}},
+			wantErr: true,
+		},
+		{
+			name:    "int default out of range",
+			in:      []Input{{Name: "replicas", Type: IntType, Default: "10", Max: &five}},
+			wantErr: true,
+		},
+		{
+			name: "bool default not a bool",
+			in:   []Input{{Name: "public", Type: "bool", Default: "maybe"}},
+		},
+		{
+			name:    "invalid cache ttl",
+			in:      []Input{{Name: "region", Type: "text", Cache: Cache{Active: true, TTL: "1 day"}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid template",
+			in:      []Input{{Name: "user", Label: "User {{ .name"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateInputs(tt.in); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateInputs(%s) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderInput(t *testing.T) {
+	in := Input{Name: "pass", Label: "Password for {{ .user }}:", Default: "{{ .user }}@{{ .region }}"}
+	got, err := RenderInput(in, map[string]string{"user": "dennis", "region": "sa-east-1"})
+	if err != nil {
+		t.Fatalf("RenderInput error = %v", err)
+	}
+	if got.Label != "Password for dennis:" || got.Default != "dennis@sa-east-1" {
+		t.Errorf("RenderInput got label %q and default %q", got.Label, got.Default)
+	}
+
+	in = Input{Name: "bucket", ItemsFrom: &ItemsFrom{Command: "aws s3 ls --region {{ .region }}"}}
+	if got, err := RenderInput(in, map[string]string{"region": "sa-east-1"}); err != nil || got.ItemsFrom.Command != "aws s3 ls --region 'sa-east-1'" {
+		t.Errorf("RenderInput got itemsFrom %+v, %v", got.ItemsFrom, err)
+	}
+	if in.ItemsFrom.Command != "aws s3 ls --region {{ .region }}" {
+		t.Errorf("RenderInput changed the itemsFrom of the config to %q", in.ItemsFrom.Command)
+	}
+
+	injected := "x'; rm -rf ~; echo '$(id)"
+	if got, err := RenderInput(in, map[string]string{"region": injected}); err != nil || got.ItemsFrom.Command != `aws s3 ls --region 'x'\''; rm -rf ~; echo '\''$(id)'` {
+		t.Errorf("RenderInput got itemsFrom %+v, %v, want the value quoted", got.ItemsFrom, err)
+	}
+
+	if _, err := RenderInput(in, map[string]string{"user": "dennis"}); err == nil {
+		t.Error("RenderInput with a missing value should return an error")
+	}
+}
@@ -0,0 +1,132 @@
+package formula
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/slice/sliceutil"
+)
+
+const (
+	msgInvalidRegex   = "invalid pattern %q of the input %q: %v"
+	msgInvalidRange   = "the min %v of the input %q is greater than its max %v"
+	msgValueMismatch  = "the value of the input %s doesn't match %s"
+	msgValueNotNumber = "the value of the input %s is not a number"
+	msgValueNotInt    = "the value of the input %s is not an integer"
+	msgInvalidDefault = "invalid default %q of the input %q: %v"
+	msgDefaultNotBool = "The default of the bool input %q is %q, it should be true, false or one of its items"
+	msgValueBelowMin  = "the value of the input %s is less than %v"
+	msgValueAboveMax  = "the value of the input %s is greater than %v"
+)
+
+// validated tells whether the values of the input are validated by its pattern, min and max,
+// the pattern of a path input is a glob on its file name instead
+func (in Input) validated() bool {
+	switch in.Type {
+	case "text", "password", SecretType, IntType, FloatType:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateRules checks the pattern compiles, the range and the default of the input
+func validateRules(in Input) error {
+	if err := validateDefault(in); err != nil {
+		return err
+	}
+	if !in.validated() {
+		return nil
+	}
+	if in.Pattern != "" {
+		if _, err := regexp.Compile(in.Pattern); err != nil {
+			return fmt.Errorf(msgInvalidRegex, in.Pattern, in.Name, err)
+		}
+	}
+	if in.Min != nil && in.Max != nil && *in.Min > *in.Max {
+		return fmt.Errorf(msgInvalidRange, *in.Min, in.Name, *in.Max)
+	}
+	return nil
+}
+
+// Validate checks the value of a text, password, secret, int or float input matches its
+// pattern and, with a min or max, is a number in the range. The value of an int input must be
+// an integer and the one of a float input a number. The empty values of the optional inputs
+// aren't checked and the errors don't show the values. ErrorText replaces the error of
+// an invalid value when it is set.
+func (in Input) Validate(v string) error {
+	if !in.validated() || v == "" {
+		return nil
+	}
+	if err := in.validate(v); err != nil {
+		if in.ErrorText != "" {
+			return errors.New(in.ErrorText)
+		}
+		return err
+	}
+	return nil
+}
+
+func (in Input) validate(v string) error {
+	if in.Pattern != "" {
+		re, err := regexp.Compile(in.Pattern)
+		if err != nil {
+			return fmt.Errorf(msgInvalidRegex, in.Pattern, in.Name, err)
+		}
+		if !re.MatchString(v) {
+			return fmt.Errorf(msgValueMismatch, in.Name, in.Pattern)
+		}
+	}
+	if in.Type == IntType {
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			return fmt.Errorf(msgValueNotInt, in.Name)
+		}
+	}
+	if in.Type != IntType && in.Type != FloatType && in.Min == nil && in.Max == nil {
+		return nil
+	}
+
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fmt.Errorf(msgValueNotNumber, in.Name)
+	}
+	if in.Min != nil && n < *in.Min {
+		return fmt.Errorf(msgValueBelowMin, in.Name, *in.Min)
+	}
+	if in.Max != nil && n > *in.Max {
+		return fmt.Errorf(msgValueAboveMax, in.Name, *in.Max)
+	}
+	return nil
+}
+
+// validateDefault checks the default of an int or float input is of its type, the
+// defaults using earlier inputs are only known when the input is asked
+func validateDefault(in Input) error {
+	if in.Default == "" || strings.Contains(in.Default, templateDelim) || (in.Type != IntType && in.Type != FloatType) {
+		return nil
+	}
+	if err := in.validate(in.Default); err != nil {
+		return fmt.Errorf(msgInvalidDefault, in.Default, in.Name, err)
+	}
+	return nil
+}
+
+// InputWarnings returns the problems of the inputs the formulas shipped with before they were
+// validated, so they warn instead of failing: the defaults of the bool inputs that are neither
+// true, false nor one of their items
+func InputWarnings(inputs []Input) []string {
+	var warnings []string
+	for _, in := range inputs {
+		if in.Type != "bool" || in.Default == "" || strings.Contains(in.Default, templateDelim) {
+			continue
+		}
+		if _, err := strconv.ParseBool(in.Default); err == nil || sliceutil.Contains(in.Items, in.Default) {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf(msgDefaultNotBool, in.Name, in.Default))
+	}
+	return warnings
+}
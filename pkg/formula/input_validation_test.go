@@ -0,0 +1,55 @@
+package formula
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInputValidate(t *testing.T) {
+	one, ten := 1.0, 10.0
+	tests := []struct {
+		name    string
+		in      Input
+		value   string
+		wantErr string
+	}{
+		{name: "matching pattern", in: Input{Name: "email", Type: "text", Pattern: `^\S+@\S+$`}, value: "dev@zup.com"},
+		{name: "mismatching pattern", in: Input{Name: "email", Type: "text", Pattern: `^\S+@\S+$`}, value: "dev", wantErr: `the value of the input email doesn't match ^\S+@\S+$`},
+		{name: "custom error", in: Input{Name: "pass", Type: "password", Pattern: ".{8,}", ErrorText: "use 8 chars at least"}, value: "123", wantErr: "use 8 chars at least"},
+		{name: "in range", in: Input{Name: "replicas", Type: "text", Min: &one, Max: &ten}, value: "3"},
+		{name: "below min", in: Input{Name: "replicas", Type: "text", Min: &one}, value: "0", wantErr: "the value of the input replicas is less than 1"},
+		{name: "above max", in: Input{Name: "replicas", Type: "text", Max: &ten}, value: "10.5", wantErr: "the value of the input replicas is greater than 10"},
+		{name: "not a number", in: Input{Name: "replicas", Type: "text", Min: &one}, value: "three", wantErr: "the value of the input replicas is not a number"},
+		{name: "empty value", in: Input{Name: "replicas", Type: "text", Min: &one}, value: ""},
+		{name: "int", in: Input{Name: "replicas", Type: IntType, Max: &ten}, value: "-3"},
+		{name: "int not an integer", in: Input{Name: "replicas", Type: IntType}, value: "2.5", wantErr: "the value of the input replicas is not an integer"},
+		{name: "float", in: Input{Name: "ratio", Type: FloatType}, value: "0.75"},
+		{name: "float not a number", in: Input{Name: "ratio", Type: FloatType}, value: "half", wantErr: "the value of the input ratio is not a number"},
+		{name: "glob of a path", in: Input{Name: "plan", Type: PathType, Pattern: "*.tf"}, value: "main.tf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.in.Validate(tt.value)
+			if (tt.wantErr == "" && err != nil) || (tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr)) {
+				t.Errorf("Validate(%q) got %v, want %q", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInputWarnings(t *testing.T) {
+	inputs := []Input{
+		{Name: "public", Type: "bool", Default: "false"},
+		{Name: "confirm", Type: "bool", Default: "yes", Items: []string{"yes", "no"}},
+		{Name: "shared", Type: "bool", Default: "{{ .public }}"},
+		{Name: "region", Type: "text", Default: "maybe"},
+		{Name: "tls", Type: "bool", Default: "maybe"},
+	}
+
+	got := InputWarnings(inputs)
+	want := []string{`The default of the bool input "tls" is "maybe", it should be true, false or one of its items`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("InputWarnings() got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,87 @@
+package formula
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	// ErrInvalidInputsFile is returned for an --inputs-file that isn't a map of the inputs to their values
+	ErrInvalidInputsFile = errors.New("invalid --inputs-file, it must be a YAML or JSON map of the input names to their values")
+	// ErrUnsetInputsFileVar is returned for a var of an --inputs-file value that isn't on the env
+	ErrUnsetInputsFileVar = errors.New("the --inputs-file uses an env var that isn't set")
+)
+
+// ReadInputsFile reads the input values of a YAML or JSON file, by input name, e.g. region: sa-east-1.
+// The $VAR and ${VAR} of the text values are replaced by the env vars, $$ is a $, and a list is the
+// JSON array of the items of a multiselect input.
+func ReadInputsFile(file string) (map[string]string, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var data map[string]interface{}
+	if err := yaml.Unmarshal(b, &data); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidInputsFile, file, err)
+	}
+
+	values := make(map[string]string, len(data))
+	for name, v := range data {
+		if values[name], err = inputsFileValue(v); err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", file, name, err)
+		}
+	}
+	return values, nil
+}
+
+func inputsFileValue(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return expandEnv(v)
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := inputsFileValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		b, err := json.Marshal(items)
+		return string(b), err
+	default:
+		return "", ErrInvalidInputsFile
+	}
+}
+
+// expandEnv replaces the env vars of the value, failing on the ones that aren't set
+func expandEnv(v string) (string, error) {
+	var unset string
+	s := os.Expand(v, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok && unset == "" {
+			unset = name
+		}
+		return value
+	})
+	if unset != "" {
+		return "", fmt.Errorf("%w: %s", ErrUnsetInputsFileVar, unset)
+	}
+	return s, nil
+}
@@ -0,0 +1,72 @@
+package formula
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadInputsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-inputs-file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	_ = os.Setenv("RIT_TEST_STAGE", "dev")
+	defer os.Unsetenv("RIT_TEST_STAGE")
+
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name: "yaml",
+			content: `region: sa-east-1
+bucket: app-${RIT_TEST_STAGE}
+price: $$5
+public: true
+replicas: 3
+regions: [us-east-1, sa-east-1]
+description:
+`,
+			want: map[string]string{
+				"region":      "sa-east-1",
+				"bucket":      "app-dev",
+				"price":       "$5",
+				"public":      "true",
+				"replicas":    "3",
+				"regions":     `["us-east-1","sa-east-1"]`,
+				"description": "",
+			},
+		},
+		{name: "json", content: `{"region": "$RIT_TEST_STAGE-1", "size": 1.5}`, want: map[string]string{"region": "dev-1", "size": "1.5"}},
+		{name: "unset env var", content: "bucket: app-${RIT_TEST_MISSING}\n", wantErr: ErrUnsetInputsFileVar},
+		{name: "nested map", content: "aws:\n  region: sa-east-1\n", wantErr: ErrInvalidInputsFile},
+		{name: "list", content: "- sa-east-1\n", wantErr: ErrInvalidInputsFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, "inputs.yaml")
+			if err := ioutil.WriteFile(file, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ReadInputsFile(file)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ReadInputsFile() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadInputsFile() got %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
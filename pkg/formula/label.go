@@ -0,0 +1,26 @@
+package formula
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidLabel is returned for a run label that isn't key=value
+var ErrInvalidLabel = errors.New("--label must be key=value, e.g. --label ci=123")
+
+// ParseLabels parses the key=value labels of a run, a repeated key keeps its last value
+func ParseLabels(ll []string) (map[string]string, error) {
+	if len(ll) == 0 {
+		return nil, nil
+	}
+
+	labels := make(map[string]string, len(ll))
+	for _, l := range ll {
+		i := strings.Index(l, "=")
+		if i <= 0 {
+			return nil, ErrInvalidLabel
+		}
+		labels[l[:i]] = l[i+1:]
+	}
+	return labels, nil
+}
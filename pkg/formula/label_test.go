@@ -0,0 +1,34 @@
+package formula
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []string
+		want    map[string]string
+		wantErr error
+	}{
+		{name: "no labels"},
+		{
+			name: "labels",
+			in:   []string{"ci=123", "ticket=OPS-1", "url=http://host?a=b", "ci=456"},
+			want: map[string]string{"ci": "456", "ticket": "OPS-1", "url": "http://host?a=b"},
+		},
+		{name: "empty value", in: []string{"ci="}, want: map[string]string{"ci": ""}},
+		{name: "missing value", in: []string{"ci"}, wantErr: ErrInvalidLabel},
+		{name: "missing key", in: []string{"=123"}, wantErr: ErrInvalidLabel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLabels(tt.in)
+			if err != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLabels(%v) got %v, %v, want %v, %v", tt.in, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package formula
+
+import "errors"
+
+const (
+	// LockfileName is the lock file written by rit lock and read by rit sync, on the working directory
+	LockfileName = "rit.lock"
+	// LockfileVersion is the version of the lock file format
+	LockfileVersion = 1
+)
+
+// ErrLockMismatch is returned when a repository or a formula doesn't match the lock file
+var ErrLockMismatch = errors.New("doesn't match the lock file")
+
+type (
+	// Lockfile pins the repositories and their installed formulas, so that rit sync can
+	// reproduce them on another machine. The repositories aren't versioned, their version
+	// is the digest of the tree, as on RepoPlan.
+	Lockfile struct {
+		Version int          `json:"lockfileVersion"`
+		Repos   []LockedRepo `json:"repos"`
+	}
+
+	// LockedRepo is a repository of the lock file, its credentials are never locked
+	LockedRepo struct {
+		Name     string          `json:"name"`
+		TreePath string          `json:"treePath"`
+		Priority int             `json:"priority"`
+		Version  string          `json:"version"`
+		Formulas []LockedFormula `json:"formulas,omitempty"`
+	}
+
+	// LockedFormula is an installed formula with the checksums of its files on the install,
+	// by their slash separated path relative to the formula dir
+	LockedFormula struct {
+		Path      string            `json:"path"`
+		Checksums map[string]string `json:"checksums"`
+	}
+)
+
+// RepoPinner builds the lock file of the repositories and adds the repositories of a lock file
+type RepoPinner interface {
+	Lockfile() (Lockfile, error)
+	// SyncLocked adds or replaces the repositories of the lock file and returns their
+	// trees by name. With strict, nothing is written when a tree isn't on its locked version.
+	SyncLocked(l Lockfile, strict bool) (map[string]Tree, error)
+}
+
+// LockedInstaller installs a formula only when its files match the locked checksums
+type LockedInstaller interface {
+	InstallLocked(def Definition, checksums map[string]string) error
+}
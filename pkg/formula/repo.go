@@ -1,17 +1,97 @@
 package formula
 
+const defaultCtx = "default"
+
+// Repository type that represents a formula repository.
+// Contexts restricts the repository to the listed contexts, a repository
+// without contexts is shared and its formulas are available in every context.
+// A locked repository is skipped by rit update repo unless it is forced.
 type Repository struct {
-	Priority int    `json:"priority"`
-	Name     string `json:"name"`
-	TreePath string `json:"treePath"`
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Priority int      `json:"priority"`
+	Name     string   `json:"name"`
+	TreePath string   `json:"treePath"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	Contexts []string `json:"contexts,omitempty"`
+	Locked   bool     `json:"locked,omitempty"`
+}
+
+// ActiveIn tells whether the repository formulas are available in the context,
+// an empty context is the default context
+func (r Repository) ActiveIn(ctx string) bool {
+	if len(r.Contexts) == 0 {
+		return true
+	}
+
+	if ctx == "" {
+		ctx = defaultCtx
+	}
+	for _, c := range r.Contexts {
+		if c == ctx {
+			return true
+		}
+	}
+	return false
 }
 
 type RepositoryFile struct {
 	Values []Repository `json:"repositories,omitempty"`
 }
 
+const (
+	RepoAdd    = "add"
+	RepoUpdate = "update"
+	RepoDelete = "delete"
+)
+
+// RepoPlan is the change a repository operation would make, it is built by
+// downloading and comparing the trees without writing anything on the rit home.
+// A locked repository isn't downloaded when the update isn't forced.
+// Added, Removed and Deprecated are formula command paths, e.g. "rit aws create".
+// The versions are the digests of the cached and downloaded trees, as the
+// repositories aren't versioned, and Formulas is the count of the downloaded tree.
+type RepoPlan struct {
+	Operation    string   `json:"operation"`
+	Repo         string   `json:"repo"`
+	TreePath     string   `json:"treePath"`
+	Replaces     bool     `json:"replaces,omitempty"`
+	Locked       bool     `json:"locked,omitempty"`
+	FromVersion  string   `json:"fromVersion,omitempty"`
+	ToVersion    string   `json:"toVersion,omitempty"`
+	DownloadSize int      `json:"downloadSize,omitempty"`
+	Formulas     int      `json:"formulas,omitempty"`
+	Added        []string `json:"added,omitempty"`
+	Removed      []string `json:"removed,omitempty"`
+	Deprecated   []string `json:"deprecated,omitempty"`
+	FilesRemoved []string `json:"filesRemoved,omitempty"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// RepoPlanner plans the repository operations, for the --dry-run of the repo commands
+type RepoPlanner interface {
+	PlanAdd(r Repository) (RepoPlan, error)
+	PlanUpdate(force bool) ([]RepoPlan, error)
+	PlanDelete(name string) (RepoPlan, error)
+}
+
+const (
+	FileAdded    = "added"
+	FileModified = "modified"
+	FileDeleted  = "deleted"
+)
+
+// FileChange is a formula file changed on the rit home since it was installed from its repository
+type FileChange struct {
+	Status string `json:"status"`
+	Path   string `json:"path"`
+}
+
+// RepoDiffer lists the formula files of a repository changed on the rit home,
+// e.g. by rit build formula, the paths are relative to the formulas dir
+type RepoDiffer interface {
+	Diff(name string) ([]FileChange, error)
+}
+
 type RepoAdder interface {
 	Add(d Repository) error
 }
@@ -20,8 +100,20 @@ type RepoLister interface {
 	List() ([]Repository, error)
 }
 
+// RepoVersioner returns the version of the cached tree of a repository, the digest of the
+// tree as on RepoPlan, empty when the repository has no tree cached
+type RepoVersioner interface {
+	Version(name string) string
+}
+
+// RepoUpdater updates the repositories, the locked ones only when force is set
 type RepoUpdater interface {
-	Update() error
+	Update(force bool) error
+}
+
+// RepoLocker locks and unlocks a repository against the updates
+type RepoLocker interface {
+	Lock(name string, locked bool) error
 }
 
 type RepoDeleter interface {
@@ -1,8 +1,8 @@
 package repo
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -11,9 +11,6 @@ import (
 	"sort"
 	"strings"
 	"sync"
-	"time"
-
-	"github.com/gofrs/flock"
 
 	"github.com/ZupIT/ritchie-cli/pkg/api"
 	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
@@ -22,6 +19,7 @@ import (
 	"github.com/ZupIT/ritchie-cli/pkg/prompt"
 	"github.com/ZupIT/ritchie-cli/pkg/server"
 	"github.com/ZupIT/ritchie-cli/pkg/session"
+	"github.com/ZupIT/ritchie-cli/pkg/stream"
 )
 
 const (
@@ -30,6 +28,8 @@ const (
 	repositoryCacheFolderPattern = "%s/repo/cache"
 	treeCacheFilePattern         = "%s/repo/cache/%s-tree.json"
 	providerPath                 = "%s/repositories"
+
+	msgRepoLockedSkip = "...Skipping the locked %q formula repository, update it with --force or unlock it"
 )
 
 var (
@@ -85,19 +85,11 @@ func (dm Manager) Add(r formula.Repository) error {
 		return err
 	}
 
-	lockFile := strings.Replace(dm.repoFile, filepath.Ext(dm.repoFile), ".lock", 1)
-	lock := flock.New(lockFile)
-	lockCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	locked, err := lock.TryLockContext(lockCtx, time.Second)
-	if locked {
-		defer func() {
-			_ = lock.Unlock()
-		}()
-	}
+	unlock, err := stream.Lock(dm.repoFile)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
 	if !fileutil.Exists(dm.repoFile) {
 		wb, err := json.Marshal(formula.RepositoryFile{})
@@ -121,7 +113,7 @@ func (dm Manager) Add(r formula.Repository) error {
 	}
 
 	if err := dm.loadTreeFile(r); err != nil {
-		return fmt.Errorf("looks like %q is not a valid formula repository or cannot be reached\n", r.TreePath)
+		return addError(r, err)
 	}
 
 	added := false
@@ -143,32 +135,74 @@ func (dm Manager) Add(r formula.Repository) error {
 	return nil
 }
 
-func (dm Manager) Update() error {
+// addError is the error of a repository whose tree can't be added
+func addError(r formula.Repository, err error) error {
+	var schemaErr formula.SchemaError
+	if errors.As(err, &schemaErr) {
+		return err
+	}
+	return fmt.Errorf("looks like %q is not a valid formula repository or cannot be reached\n", r.TreePath)
+}
+
+func (dm Manager) Update(force bool) error {
 	f, err := dm.loadReposFromDisk()
 	if fileutil.IsNotExistErr(err) || len(f.Values) == 0 {
 		return ErrNoRepoToShow
 	}
 
-	fmt.Println("Wait while we update your repositories...")
-	var wg sync.WaitGroup
+	unlocked := f.Values[:0]
 	for _, v := range f.Values {
+		if v.Locked && !force {
+			prompt.Print(fmt.Sprintf(msgRepoLockedSkip, v.Name))
+			continue
+		}
+		unlocked = append(unlocked, v)
+	}
+	f.Values = unlocked
+
+	for _, v := range f.Values {
+		if msg := dm.localChangesWarning(v.Name); msg != "" {
+			prompt.Warning(msg)
+		}
+	}
+
+	prompt.Print("Wait while we update your repositories...")
+	var wg sync.WaitGroup
+	deprecated := make([][]api.Command, len(f.Values))
+	for i, v := range f.Values {
 		wg.Add(1)
-		go func(v formula.Repository) {
+		go func(i int, v formula.Repository) {
 			defer wg.Done()
+			oldTree, cached := dm.treeCache(v.Name)
 			if err := dm.loadTreeFile(v); err != nil {
 				fmt.Printf("...Unable to get an update from the %q formula repository (%s):\n\t%s\n", v.Name, v.TreePath, err)
-			} else {
-				fmt.Printf("...Successfully got an update from the %q formula repository\n", v.Name)
+				return
 			}
-		}(v)
+			prompt.Print(fmt.Sprintf("...Successfully got an update from the %q formula repository", v.Name))
+			if newTree, ok := dm.treeCache(v.Name); cached && ok {
+				deprecated[i] = formula.NewlyDeprecated(oldTree, newTree)
+			}
+		}(i, v)
 	}
 	wg.Wait()
-	fmt.Println("Done.")
+
+	for i, v := range f.Values {
+		if msg := deprecatedSummary(v.Name, deprecated[i]); msg != "" {
+			prompt.Warning(msg)
+		}
+	}
+	prompt.Print("Done.")
 
 	return nil
 }
 
 func (dm Manager) Delete(name string) error {
+	unlock, err := stream.Lock(dm.repoFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	f, err := dm.loadReposFromDisk()
 	if fileutil.IsNotExistErr(err) || len(f.Values) == 0 {
 		return ErrNoRepoToShow
@@ -182,7 +216,7 @@ func (dm Manager) Delete(name string) error {
 		}
 	}
 	if len(f.Values) == l {
-		return fmt.Errorf("repository %q not found\n", name)
+		return repoNotFound(name)
 	}
 
 	if err := writeFile(f, dm.repoFile, 0644); err != nil {
@@ -197,6 +231,10 @@ func (dm Manager) Delete(name string) error {
 	return nil
 }
 
+func repoNotFound(name string) error {
+	return fmt.Errorf("repository %q not found\n", name)
+}
+
 func (dm Manager) List() ([]formula.Repository, error) {
 	f, err := dm.loadReposFromDisk()
 
@@ -213,14 +251,41 @@ func (dm Manager) List() ([]formula.Repository, error) {
 }
 
 func (dm Manager) loadTreeFile(r formula.Repository) error {
+	treeFile, err := dm.fetchTree(r)
+	if err != nil {
+		return err
+	}
+
+	return dm.writeTreeCache(r.Name, treeFile)
+}
+
+// writeTreeCache writes the cached tree of the repository under its lock, the tree
+// is replaced at once so the rit processes reading it don't need to take the lock
+func (dm Manager) writeTreeCache(name string, treeFile []byte) error {
+	treeCacheFile := fmt.Sprintf(treeCacheFilePattern, dm.homePath, name)
+	treeDir := filepath.Dir(treeCacheFile)
+	if err := fileutil.CreateDirIfNotExists(treeDir, 0755); err != nil {
+		return err
+	}
 
+	unlock, err := stream.Lock(treeCacheFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return stream.WriteAtomic(treeCacheFile, treeFile, 0644)
+}
+
+// fetchTree downloads the tree of the repository in the current schema
+func (dm Manager) fetchTree(r formula.Repository) ([]byte, error) {
 	session, err := dm.sessionManager.Current()
 	if err != nil {
-		return prompt.NewError("error restore current session")
+		return nil, prompt.NewError("error restore current session")
 	}
 	req, err := http.NewRequest(http.MethodGet, r.TreePath, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if dm.edition == api.Team {
@@ -230,32 +295,55 @@ func (dm Manager) loadTreeFile(r formula.Repository) error {
 	}
 	resp, err := dm.httpClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("%d - failed to get index for %s\n", resp.StatusCode, r.TreePath)
+		resp.Body.Close()
+		return nil, fmt.Errorf("%d - failed to get index for %s\n", resp.StatusCode, r.TreePath)
 	}
 
 	treeFile, err := fileutil.ReadAll(resp.Body)
 	resp.Body.Close()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	treeCacheFile := fmt.Sprintf(treeCacheFilePattern, dm.homePath, r.Name)
-	treeDir := filepath.Dir(treeCacheFile)
-	err = fileutil.CreateDirIfNotExists(treeDir, 0755)
+	// the cache keeps the tree in the current schema, so old repos are migrated only once
+	return formula.MigrateTree(treeFile, r.Name)
+}
+
+// treeCache reads the cached tree of the repository,
+// the returned bool is false when the cache is missing or cannot be parsed
+func (dm Manager) treeCache(name string) (formula.Tree, bool) {
+	b, err := dm.treeCacheFile(name)
 	if err != nil {
-		return err
+		return formula.Tree{}, false
 	}
-
-	err = fileutil.WriteFile(treeCacheFile, treeFile)
+	tree, err := formula.UnmarshalTree(b, name)
 	if err != nil {
-		return err
+		return formula.Tree{}, false
 	}
+	return tree, true
+}
 
-	return nil
+func (dm Manager) treeCacheFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(fmt.Sprintf(treeCacheFilePattern, dm.homePath, name))
+}
+
+// deprecatedSummary builds the message about the formulas deprecated by a repository update,
+// it returns an empty string when there is nothing to report
+func deprecatedSummary(repo string, cc []api.Command) string {
+	if len(cc) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("...The %q formula repository deprecated %d formula(s):", repo, len(cc)))
+	for _, c := range cc {
+		sb.WriteString(fmt.Sprintf("\n\t%s: %s", formula.CommandPath(c), c.Deprecation.Message))
+	}
+	return sb.String()
 }
 
 func (dm Manager) loadReposFromDisk() (formula.RepositoryFile, error) {
@@ -288,5 +376,5 @@ func writeFile(rf formula.RepositoryFile, path string, perm os.FileMode) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return err
 	}
-	return ioutil.WriteFile(path, b, perm)
+	return stream.WriteAtomic(path, b, perm)
 }
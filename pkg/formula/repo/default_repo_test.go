@@ -0,0 +1,68 @@
+package repo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+)
+
+func TestTreeCache(t *testing.T) {
+	home, err := ioutil.TempDir("", "rit-repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	writeCache := func(name, content string) {
+		file := fmt.Sprintf(treeCacheFilePattern, home, name)
+		_ = os.MkdirAll(filepath.Dir(file), os.ModePerm)
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeCache("valid", `{"commands":[{"parent":"root_aws","usage":"old","deprecation":{"message":"gone"}}]}`)
+	writeCache("corrupt", `{"commands":`)
+
+	dm := NewSingleRepoManager(home, nil, nil)
+
+	tests := []struct {
+		name     string
+		repo     string
+		wantOk   bool
+		wantCmds int
+	}{
+		{name: "valid cache", repo: "valid", wantOk: true, wantCmds: 1},
+		{name: "corrupt cache", repo: "corrupt", wantOk: false},
+		{name: "missing cache", repo: "missing", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, ok := dm.treeCache(tt.repo)
+			if ok != tt.wantOk {
+				t.Errorf("treeCache(%s) ok = %v, want %v", tt.repo, ok, tt.wantOk)
+			}
+			if len(tree.Commands) != tt.wantCmds {
+				t.Errorf("treeCache(%s) got %d commands, want %d", tt.repo, len(tree.Commands), tt.wantCmds)
+			}
+		})
+	}
+}
+
+func TestDeprecatedSummary(t *testing.T) {
+	if got := deprecatedSummary("commons", nil); got != "" {
+		t.Errorf("deprecatedSummary without commands got %q, want empty", got)
+	}
+
+	cc := []api.Command{
+		{Parent: "root_aws", Usage: "old", Deprecation: &api.Deprecation{Message: "use rit aws new"}},
+	}
+	want := "...The \"commons\" formula repository deprecated 1 formula(s):\n\trit aws old: use rit aws new"
+	if got := deprecatedSummary("commons", cc); got != want {
+		t.Errorf("deprecatedSummary got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,58 @@
+package repo
+
+import (
+	"fmt"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/checksum"
+)
+
+const msgLocalChanges = "The %q repository has %d formula files changed locally, " +
+	"they may not match the updated formulas, see: rit diff repo %s"
+
+// Diff lists the files of the repository formulas changed since they were downloaded,
+// the formulas not downloaded yet have no changes
+func (dm Manager) Diff(name string) ([]formula.FileChange, error) {
+	f, err := dm.loadReposFromDisk()
+	if fileutil.IsNotExistErr(err) || len(f.Values) == 0 {
+		return nil, ErrNoRepoToShow
+	} else if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for _, v := range f.Values {
+		found = found || v.Name == name
+	}
+	if !found {
+		return nil, repoNotFound(name)
+	}
+
+	tree, _ := dm.treeCache(name)
+	var changes []formula.FileChange
+	for _, c := range tree.Commands {
+		if c.Formula == nil || c.Formula.Path == "" {
+			continue
+		}
+
+		cc, _, err := checksum.Changes(fmt.Sprintf(formula.PathPattern, dm.homePath, c.Formula.Path))
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range cc {
+			change.Path = c.Formula.Path + "/" + change.Path
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// localChangesWarning warns that the repository formulas were changed locally, before they are updated
+func (dm Manager) localChangesWarning(name string) string {
+	changes, err := dm.Diff(name)
+	if err != nil || len(changes) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(msgLocalChanges, name, len(changes), name)
+}
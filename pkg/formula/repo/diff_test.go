@@ -0,0 +1,59 @@
+package repo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/checksum"
+)
+
+func TestManagerDiff(t *testing.T) {
+	home, err := ioutil.TempDir("", "rit-repo-diff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	dm := NewSingleRepoManager(home, nil, sessionManagerMock{})
+	repos := formula.RepositoryFile{Values: []formula.Repository{{Name: "commons"}}}
+	if err := writeFile(repos, dm.repoFile, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tree := `{"commands":[
+		{"parent":"root_aws","usage":"create","formula":{"path":"aws/create"}},
+		{"parent":"root_aws","usage":"list","formula":{"path":"aws/list"}}]}`
+	_ = fileutil.CreateDirIfNotExists(dm.cacheFile, 0755)
+	if err := fileutil.WriteFile(fmt.Sprintf(treeCacheFilePattern, home, "commons"), []byte(tree)); err != nil {
+		t.Fatal(err)
+	}
+
+	// aws/create was downloaded and changed later, aws/list was never downloaded
+	formulaDir := filepath.Join(home, "formulas", "aws", "create")
+	_ = os.MkdirAll(formulaDir, 0755)
+	_ = ioutil.WriteFile(filepath.Join(formulaDir, "config.json"), []byte("{}"), 0644)
+	if err := checksum.Save(formulaDir); err != nil {
+		t.Fatal(err)
+	}
+	_ = ioutil.WriteFile(filepath.Join(formulaDir, "config.json"), []byte(`{"inputs":[]}`), 0644)
+
+	changes, err := dm.Diff("commons")
+	want := []formula.FileChange{{Status: formula.FileModified, Path: "aws/create/config.json"}}
+	if err != nil || !reflect.DeepEqual(changes, want) {
+		t.Errorf("Diff got %v, %v, want %v", changes, err, want)
+	}
+
+	if msg := dm.localChangesWarning("commons"); msg == "" {
+		t.Error("localChangesWarning got no warning, want the changed files warned")
+	}
+
+	if _, err := dm.Diff("missing"); err == nil {
+		t.Error("Diff of an unknown repository should return an error")
+	}
+}
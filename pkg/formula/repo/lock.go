@@ -0,0 +1,32 @@
+package repo
+
+import (
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/stream"
+)
+
+// Lock sets whether the repository is locked, a locked repository
+// keeps its tree until it is unlocked or the update is forced
+func (dm Manager) Lock(name string, locked bool) error {
+	unlock, err := stream.Lock(dm.repoFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := dm.loadReposFromDisk()
+	if fileutil.IsNotExistErr(err) || len(f.Values) == 0 {
+		return ErrNoRepoToShow
+	}
+	if err != nil {
+		return err
+	}
+
+	for i, v := range f.Values {
+		if v.Name == name {
+			f.Values[i].Locked = locked
+			return writeFile(f, dm.repoFile, 0644)
+		}
+	}
+	return repoNotFound(name)
+}
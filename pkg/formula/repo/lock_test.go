@@ -0,0 +1,78 @@
+package repo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestManagerLock(t *testing.T) {
+	home, err := ioutil.TempDir("", "rit-repo-lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	var mu sync.Mutex
+	fetched := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetched[r.URL.Path]++
+		mu.Unlock()
+		fmt.Fprint(w, `{"commands":[]}`)
+	}))
+	defer server.Close()
+
+	dm := NewSingleRepoManager(home, server.Client(), sessionManagerMock{})
+	repos := formula.RepositoryFile{Values: []formula.Repository{
+		{Name: "commons", TreePath: server.URL + "/commons.json"},
+		{Name: "pinned", TreePath: server.URL + "/pinned.json"},
+	}}
+	if err := writeFile(repos, dm.repoFile, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dm.Lock("pinned", true); err != nil {
+		t.Fatalf("Lock error = %v", err)
+	}
+	if err := dm.Lock("missing", true); err == nil {
+		t.Error("Lock of an unknown repository should return an error")
+	}
+
+	rr, _ := dm.List()
+	if rr[0].Locked || !rr[1].Locked {
+		t.Errorf("List got %+v, want only pinned locked", rr)
+	}
+
+	plans, err := dm.PlanUpdate(false)
+	if err != nil || plans[0].Locked || !plans[1].Locked || plans[1].DownloadSize != 0 {
+		t.Errorf("PlanUpdate got %+v, %v, want pinned locked and not downloaded", plans, err)
+	}
+
+	if err := dm.Update(false); err != nil {
+		t.Fatalf("Update error = %v", err)
+	}
+	if fetched["/commons.json"] != 2 || fetched["/pinned.json"] != 0 {
+		t.Errorf("Update fetched %v, want the locked repository skipped", fetched)
+	}
+
+	if err := dm.Update(true); err != nil {
+		t.Fatalf("Update with force error = %v", err)
+	}
+	if fetched["/pinned.json"] != 1 {
+		t.Errorf("Update with force fetched %v, want the locked repository updated", fetched)
+	}
+
+	if err := dm.Lock("pinned", false); err != nil {
+		t.Fatalf("unlock error = %v", err)
+	}
+	if rr, _ := dm.List(); rr[1].Locked {
+		t.Error("unlock must clear the lock of the repository")
+	}
+}
@@ -0,0 +1,109 @@
+package repo
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/checksum"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/stream"
+)
+
+// Lockfile locks the repositories on the version of their cached trees, with the
+// formulas already downloaded and the checksums saved on their install
+func (dm Manager) Lockfile() (formula.Lockfile, error) {
+	f, err := dm.loadReposFromDisk()
+	if fileutil.IsNotExistErr(err) || len(f.Values) == 0 {
+		return formula.Lockfile{}, ErrNoRepoToShow
+	} else if err != nil {
+		return formula.Lockfile{}, err
+	}
+
+	sort.Sort(ByPriority(f.Values))
+	l := formula.Lockfile{Version: formula.LockfileVersion, Repos: make([]formula.LockedRepo, 0, len(f.Values))}
+	for _, v := range f.Values {
+		r := formula.LockedRepo{Name: v.Name, TreePath: v.TreePath, Priority: v.Priority, Version: dm.cachedVersion(v.Name)}
+		tree, _ := dm.treeCache(v.Name)
+		for _, c := range tree.Commands {
+			if c.Formula == nil || c.Formula.Path == "" {
+				continue
+			}
+
+			sums, ok, err := checksum.Load(fmt.Sprintf(formula.PathPattern, dm.homePath, c.Formula.Path))
+			if err != nil {
+				return formula.Lockfile{}, err
+			} else if ok {
+				r.Formulas = append(r.Formulas, formula.LockedFormula{Path: c.Formula.Path, Checksums: sums})
+			}
+		}
+		l.Repos = append(l.Repos, r)
+	}
+	return l, nil
+}
+
+// SyncLocked downloads the trees of the lock file repositories and compares them with their
+// locked versions before writing any of them. A tree on another version fails with strict,
+// otherwise it is added with a warning. The repositories are added with the priority of the
+// lock file, the ones already added keep their credentials and the other ones are kept.
+func (dm Manager) SyncLocked(l formula.Lockfile, strict bool) (map[string]formula.Tree, error) {
+	files := make(map[string][]byte, len(l.Repos))
+	trees := make(map[string]formula.Tree, len(l.Repos))
+	for _, lr := range l.Repos {
+		r := formula.Repository{Name: lr.Name, TreePath: lr.TreePath, Priority: lr.Priority}
+		b, err := dm.fetchTree(r)
+		if err != nil {
+			return nil, addError(r, err)
+		}
+
+		if v := treeVersion(b); v != lr.Version {
+			err := fmt.Errorf("repository %q on %s %w, locked on %s", lr.Name, v, formula.ErrLockMismatch, lr.Version)
+			if strict {
+				return nil, err
+			}
+			prompt.Warning(err.Error())
+		}
+
+		tree, err := formula.UnmarshalTree(b, lr.Name)
+		if err != nil {
+			return nil, err
+		}
+		files[lr.Name] = b
+		trees[lr.Name] = tree
+	}
+
+	unlock, err := stream.Lock(dm.repoFile)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	f, err := dm.loadReposFromDisk()
+	if err != nil && !fileutil.IsNotExistErr(err) {
+		return nil, err
+	}
+
+	for _, lr := range l.Repos {
+		if err := dm.writeTreeCache(lr.Name, files[lr.Name]); err != nil {
+			return nil, err
+		}
+
+		added := false
+		for i, v := range f.Values {
+			if v.Name == lr.Name {
+				f.Values[i].TreePath = lr.TreePath
+				f.Values[i].Priority = lr.Priority
+				added = true
+			}
+		}
+		if !added {
+			f.Values = append(f.Values, formula.Repository{Name: lr.Name, TreePath: lr.TreePath, Priority: lr.Priority})
+		}
+	}
+
+	if err := writeFile(f, dm.repoFile, 0644); err != nil {
+		return nil, err
+	}
+	return trees, nil
+}
@@ -0,0 +1,103 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/checksum"
+)
+
+func TestManagerLockfile(t *testing.T) {
+	tree := `{"commands":[{"parent":"root_aws","usage":"create","formula":{"path":"aws/create"}},
+		{"parent":"root_aws","usage":"list","formula":{"path":"aws/list"}}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, tree)
+	}))
+	defer server.Close()
+
+	home, err := ioutil.TempDir("", "rit-lockfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	dm := NewSingleRepoManager(home, server.Client(), sessionManagerMock{})
+	commons := formula.Repository{Name: "commons", TreePath: server.URL + "/tree.json", Priority: 1, Username: "user", Password: "secret"}
+	if err := dm.Add(commons); err != nil {
+		t.Fatal(err)
+	}
+	formulaDir := fmt.Sprintf(formula.PathPattern, home, "aws/create")
+	if err := fileutil.CreateDirIfNotExists(formulaDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(formulaDir, "config.json"), []byte(`{"inputs":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := checksum.Save(formulaDir); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := dm.Lockfile()
+	if err != nil {
+		t.Fatalf("Lockfile() error = %v", err)
+	}
+	if len(l.Repos) != 1 || l.Version != formula.LockfileVersion {
+		t.Fatalf("Lockfile() = %+v, want the commons repository", l)
+	}
+	r := l.Repos[0]
+	if r.Name != "commons" || r.Priority != 1 || r.Version != dm.cachedVersion("commons") || r.Version == "" {
+		t.Errorf("Lockfile() locked %+v, want commons on its cached version", r)
+	}
+	if len(r.Formulas) != 1 || r.Formulas[0].Path != "aws/create" || r.Formulas[0].Checksums["config.json"] == "" {
+		t.Errorf("Lockfile() locked the formulas %+v, want only the installed aws/create", r.Formulas)
+	}
+
+	t.Run("sync on another home", func(t *testing.T) {
+		other, err := ioutil.TempDir("", "rit-lockfile-sync")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(other)
+
+		odm := NewSingleRepoManager(other, server.Client(), sessionManagerMock{})
+		trees, err := odm.SyncLocked(l, true)
+		if err != nil {
+			t.Fatalf("SyncLocked() error = %v", err)
+		}
+		if len(trees["commons"].Commands) != 2 {
+			t.Errorf("SyncLocked() trees = %+v, want the commons tree", trees)
+		}
+		if v := odm.cachedVersion("commons"); v != r.Version {
+			t.Errorf("SyncLocked() cached the version %s, want %s", v, r.Version)
+		}
+		repos, err := odm.List()
+		if err != nil || len(repos) != 1 || repos[0].TreePath != commons.TreePath || repos[0].Password != "" {
+			t.Errorf("SyncLocked() added %+v (%v), want commons without credentials", repos, err)
+		}
+	})
+
+	t.Run("tree changed", func(t *testing.T) {
+		changed := l
+		changed.Repos = []formula.LockedRepo{r}
+		changed.Repos[0].Version = "sha256:000000000000"
+
+		if _, err := dm.SyncLocked(changed, true); !errors.Is(err, formula.ErrLockMismatch) {
+			t.Errorf("SyncLocked() strict error = %v, want %v", err, formula.ErrLockMismatch)
+		}
+		if _, err := dm.SyncLocked(changed, false); err != nil {
+			t.Errorf("SyncLocked() error = %v, want the mismatch only warned", err)
+		}
+		repos, _ := dm.List()
+		if len(repos) != 1 || repos[0].Password != "secret" {
+			t.Errorf("SyncLocked() got the repositories %+v, want commons keeping its credentials", repos)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package repo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+// PlanAdd plans the Add of the repository, it downloads the tree to compare
+// its formulas with the ones of the repository being replaced
+func (dm Manager) PlanAdd(r formula.Repository) (formula.RepoPlan, error) {
+	plan := formula.RepoPlan{Operation: formula.RepoAdd, Repo: r.Name, TreePath: r.TreePath}
+
+	f, err := dm.loadReposFromDisk()
+	if err != nil && !fileutil.IsNotExistErr(err) {
+		return formula.RepoPlan{}, err
+	}
+	for _, v := range f.Values {
+		if v.Name == r.Name {
+			plan.Replaces = true
+		}
+	}
+
+	if err := dm.planTree(&plan, r); err != nil {
+		return formula.RepoPlan{}, addError(r, err)
+	}
+	return plan, nil
+}
+
+// PlanUpdate plans the Update of every repository, the repositories
+// that can't be reached keep the reason on the plan error
+func (dm Manager) PlanUpdate(force bool) ([]formula.RepoPlan, error) {
+	f, err := dm.loadReposFromDisk()
+	if fileutil.IsNotExistErr(err) || len(f.Values) == 0 {
+		return nil, ErrNoRepoToShow
+	}
+
+	plans := make([]formula.RepoPlan, 0, len(f.Values))
+	for _, v := range f.Values {
+		plan := formula.RepoPlan{Operation: formula.RepoUpdate, Repo: v.Name, TreePath: v.TreePath}
+		plan.FromVersion = dm.cachedVersion(v.Name)
+		if v.Locked && !force {
+			plan.Locked = true
+		} else if err := dm.planTree(&plan, v); err != nil {
+			plan.Error = err.Error()
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// PlanDelete plans the Delete of the repository, its formulas and tree cache are removed
+func (dm Manager) PlanDelete(name string) (formula.RepoPlan, error) {
+	f, err := dm.loadReposFromDisk()
+	if fileutil.IsNotExistErr(err) || len(f.Values) == 0 {
+		return formula.RepoPlan{}, ErrNoRepoToShow
+	}
+
+	for _, v := range f.Values {
+		if v.Name != name {
+			continue
+		}
+
+		plan := formula.RepoPlan{Operation: formula.RepoDelete, Repo: v.Name, TreePath: v.TreePath}
+		plan.FromVersion = dm.cachedVersion(name)
+		if oldTree, ok := dm.treeCache(name); ok {
+			plan.Removed = formulaPaths(oldTree)
+		}
+		if treeCacheFile := fmt.Sprintf(treeCacheFilePattern, dm.homePath, name); fileutil.Exists(treeCacheFile) {
+			plan.FilesRemoved = []string{treeCacheFile}
+		}
+		return plan, nil
+	}
+	return formula.RepoPlan{}, repoNotFound(name)
+}
+
+// planTree downloads the tree of the repository and fills the plan with
+// the download size and the formulas changed from the cached tree
+func (dm Manager) planTree(plan *formula.RepoPlan, r formula.Repository) error {
+	b, err := dm.fetchTree(r)
+	if err != nil {
+		return err
+	}
+
+	newTree, err := formula.UnmarshalTree(b, r.Name)
+	if err != nil {
+		return err
+	}
+
+	plan.DownloadSize = len(b)
+	plan.FromVersion = dm.cachedVersion(r.Name)
+	plan.ToVersion = treeVersion(b)
+	plan.Formulas = len(formulaPaths(newTree))
+	oldTree, _ := dm.treeCache(r.Name)
+	plan.Added = difference(formulaPaths(newTree), formulaPaths(oldTree))
+	plan.Removed = difference(formulaPaths(oldTree), formulaPaths(newTree))
+	for _, c := range formula.NewlyDeprecated(oldTree, newTree) {
+		plan.Deprecated = append(plan.Deprecated, formula.CommandPath(c))
+	}
+	return nil
+}
+
+// Version returns the version of the cached tree of the repository, see formula.RepoVersioner
+func (dm Manager) Version(name string) string {
+	return dm.cachedVersion(name)
+}
+
+// cachedVersion returns the version of the cached tree of the repository, empty without a cache
+func (dm Manager) cachedVersion(name string) string {
+	b, err := dm.treeCacheFile(name)
+	if err != nil {
+		return ""
+	}
+	return treeVersion(b)
+}
+
+// treeVersion identifies the tree by its content, the repositories have no version of their own
+func treeVersion(b []byte) string {
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:6])
+}
+
+// formulaPaths returns the sorted command paths of the tree formulas
+func formulaPaths(t formula.Tree) []string {
+	var paths []string
+	for _, c := range t.Commands {
+		if c.Formula != nil && c.Formula.Path != "" {
+			paths = append(paths, formula.CommandPath(c))
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// difference returns the paths of a that aren't in b
+func difference(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, p := range b {
+		in[p] = true
+	}
+
+	var diff []string
+	for _, p := range a {
+		if !in[p] {
+			diff = append(diff, p)
+		}
+	}
+	return diff
+}
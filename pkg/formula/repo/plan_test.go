@@ -0,0 +1,120 @@
+package repo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/session"
+)
+
+type sessionManagerMock struct{}
+
+func (sessionManagerMock) Create(s session.Session) error { return nil }
+
+func (sessionManagerMock) Current() (session.Session, error) { return session.Session{}, nil }
+
+func (sessionManagerMock) Destroy() error { return nil }
+
+func TestManagerPlans(t *testing.T) {
+	home, err := ioutil.TempDir("", "rit-repo-plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	newTree := `{"commands":[
+		{"parent":"root","usage":"aws"},
+		{"parent":"root_aws","usage":"create","formula":{"path":"aws/create"}},
+		{"parent":"root_aws","usage":"list","formula":{"path":"aws/list"},"deprecation":{"message":"use rit aws ls"}}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tree.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, newTree)
+	}))
+	defer server.Close()
+
+	dm := NewSingleRepoManager(home, server.Client(), sessionManagerMock{})
+	commons := formula.Repository{Name: "commons", TreePath: server.URL + "/tree.json"}
+	broken := formula.Repository{Name: "broken", TreePath: server.URL + "/missing.json"}
+	repos := formula.RepositoryFile{Values: []formula.Repository{commons, broken}}
+	if err := writeFile(repos, dm.repoFile, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cacheFile := fmt.Sprintf(treeCacheFilePattern, home, "commons")
+	oldTree := `{"commands":[
+		{"parent":"root_aws","usage":"list","formula":{"path":"aws/list"}},
+		{"parent":"root_aws","usage":"old","formula":{"path":"aws/old"}}]}`
+	if err := fileutil.CreateDirIfNotExists(dm.cacheFile, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fileutil.WriteFile(cacheFile, []byte(oldTree)); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("add", func(t *testing.T) {
+		plan, err := dm.PlanAdd(commons)
+		if err != nil {
+			t.Fatalf("PlanAdd error = %v", err)
+		}
+		want := formula.RepoPlan{
+			Operation:    formula.RepoAdd,
+			Repo:         "commons",
+			TreePath:     commons.TreePath,
+			Replaces:     true,
+			FromVersion:  treeVersion([]byte(oldTree)),
+			ToVersion:    plan.ToVersion,
+			DownloadSize: plan.DownloadSize,
+			Formulas:     2,
+			Added:        []string{"rit aws create"},
+			Removed:      []string{"rit aws old"},
+			Deprecated:   []string{"rit aws list"},
+		}
+		if plan.DownloadSize == 0 || plan.ToVersion == "" || plan.ToVersion == plan.FromVersion || !reflect.DeepEqual(plan, want) {
+			t.Errorf("PlanAdd got %+v, want %+v", plan, want)
+		}
+
+		if _, err := dm.PlanAdd(broken); err == nil {
+			t.Error("PlanAdd of an unreachable repository should return an error")
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		plans, err := dm.PlanUpdate(false)
+		if err != nil {
+			t.Fatalf("PlanUpdate error = %v", err)
+		}
+		if len(plans) != 2 || plans[0].Error != "" || plans[1].Error == "" || plans[0].ToVersion == plans[0].FromVersion {
+			t.Errorf("PlanUpdate got %+v, want commons planned and broken with an error", plans)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		plan, err := dm.PlanDelete("commons")
+		if err != nil {
+			t.Fatalf("PlanDelete error = %v", err)
+		}
+		if !reflect.DeepEqual(plan.Removed, []string{"rit aws list", "rit aws old"}) || plan.FromVersion == "" ||
+			!reflect.DeepEqual(plan.FilesRemoved, []string{cacheFile}) {
+			t.Errorf("PlanDelete got %+v", plan)
+		}
+
+		if _, err := dm.PlanDelete("missing"); err == nil {
+			t.Error("PlanDelete of an unknown repository should return an error")
+		}
+	})
+
+	b, err := ioutil.ReadFile(cacheFile)
+	if err != nil || string(b) != oldTree {
+		t.Error("the plans must not change the tree cache")
+	}
+}
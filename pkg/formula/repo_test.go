@@ -0,0 +1,45 @@
+package formula
+
+import "testing"
+
+func TestRepositoryActiveIn(t *testing.T) {
+	tests := []struct {
+		name string
+		repo Repository
+		ctx  string
+		want bool
+	}{
+		{
+			name: "shared repo",
+			repo: Repository{Name: "commons"},
+			ctx:  "prd",
+			want: true,
+		},
+		{
+			name: "repo in the current context",
+			repo: Repository{Name: "prd-tools", Contexts: []string{"stg", "prd"}},
+			ctx:  "prd",
+			want: true,
+		},
+		{
+			name: "repo in another context",
+			repo: Repository{Name: "prd-tools", Contexts: []string{"prd"}},
+			ctx:  "stg",
+			want: false,
+		},
+		{
+			name: "repo in the default context",
+			repo: Repository{Name: "dev-tools", Contexts: []string{"default"}},
+			ctx:  "",
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.repo.ActiveIn(tt.ctx); got != tt.want {
+				t.Errorf("ActiveIn(%s) got %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+// Package runlog keeps the output of the latest formula runs so it can be
+// read back with rit logs after it scrolled away.
+package runlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/redact"
+)
+
+const (
+	// LogsDir is the run logs dir pattern, relative to the ritchie home
+	LogsDir = "%s/logs/runs"
+	// MaxSize is the max size in bytes kept for a run log, the remaining output is dropped
+	MaxSize = 1 << 20
+	// MaxLogs is the number of run logs kept, the oldest ones are pruned
+	MaxLogs = 50
+
+	timeLayout   = "20060102T150405.000000"
+	logExt       = ".log"
+	metaExt      = ".json"
+	truncatedMsg = "\n[rit: log truncated at %d bytes]\n"
+	followRate   = 500 * time.Millisecond
+)
+
+// Entry is the metadata of a run log. The ID is the log file name without
+// extension and identifies the run, Args and Labels are the redacted args
+// and --label values of the run.
+type Entry struct {
+	ID        string            `json:"id"`
+	Command   string            `json:"command"`
+	Args      []string          `json:"args,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	File      string            `json:"file"`
+	Start     time.Time         `json:"start"`
+	End       time.Time         `json:"end,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Truncated bool              `json:"truncated,omitempty"`
+}
+
+// Running tells whether the run that writes the log didn't finish yet
+func (e Entry) Running() bool {
+	return e.End.IsZero()
+}
+
+type Creator interface {
+	Create(id, command string, args []string, labels map[string]string, r redact.Redactor) (*Log, error)
+}
+
+type Lister interface {
+	List() ([]Entry, error)
+}
+
+type Follower interface {
+	Follow(e Entry, w io.Writer) error
+}
+
+type ListFollower interface {
+	Lister
+	Follower
+}
+
+type Manager struct {
+	dir     string
+	enabled bool
+	now     func() time.Time
+}
+
+// NewManager creates the run logs manager, when disabled no log is written
+func NewManager(ritchieHome string, enabled bool) Manager {
+	return Manager{dir: fmt.Sprintf(LogsDir, ritchieHome), enabled: enabled, now: time.Now}
+}
+
+// NewID returns the ID of the log of a command run started at start
+func NewID(start time.Time, command string) string {
+	return fmt.Sprintf("%s-%s", start.Format(timeLayout), fileName(command))
+}
+
+// Create starts the log of a command run and prunes the oldest logs, the
+// args, labels and output are masked by the redactor before being written.
+// An empty id is generated with NewID. A run with an id, as a detached run
+// that has no terminal, is logged even when the logs are disabled.
+func (m Manager) Create(id, command string, args []string, labels map[string]string, r redact.Redactor) (*Log, error) {
+	if !m.enabled && id == "" {
+		return &Log{}, nil
+	}
+
+	if err := fileutil.CreateDirIfNotExists(m.dir, 0755); err != nil {
+		return nil, err
+	}
+
+	if err := m.prune(MaxLogs - 1); err != nil {
+		return nil, err
+	}
+
+	start := m.now()
+	if id == "" {
+		id = NewID(start, command)
+	}
+	entry := Entry{
+		ID:      id,
+		Command: command,
+		Args:    r.Args(args),
+		Labels:  r.Map(labels),
+		File:    filepath.Join(m.dir, id+logExt),
+		Start:   start,
+	}
+
+	f, err := os.OpenFile(entry.File, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Log{file: f, entry: entry, meta: filepath.Join(m.dir, id+metaExt), now: m.now, redactor: r}
+	if err := l.writeMeta(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// List returns the run logs from the oldest to the newest
+func (m Manager) List() ([]Entry, error) {
+	if !fileutil.Exists(m.dir) {
+		return []Entry{}, nil
+	}
+
+	files, err := ioutil.ReadDir(m.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var ee []Entry
+	for _, f := range files {
+		if filepath.Ext(f.Name()) != metaExt {
+			continue
+		}
+
+		b, err := fileutil.ReadFile(filepath.Join(m.dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var e Entry
+		if err := json.Unmarshal(b, &e); err != nil {
+			continue
+		}
+		ee = append(ee, e)
+	}
+
+	sort.Slice(ee, func(i, j int) bool {
+		return ee[i].ID < ee[j].ID
+	})
+
+	return ee, nil
+}
+
+// Follow writes the log to w and keeps writing the new output until the run ends.
+// A run interrupted without finishing its log is followed until Ctrl+C.
+func (m Manager) Follow(e Entry, w io.Writer) error {
+	f, err := os.Open(e.File)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	for {
+		if _, err := io.Copy(w, f); err != nil {
+			return err
+		}
+
+		if !e.Running() {
+			return nil
+		}
+		time.Sleep(followRate)
+
+		if e, err = m.find(e.ID); err != nil {
+			return err
+		}
+	}
+}
+
+func (m Manager) find(id string) (Entry, error) {
+	b, err := fileutil.ReadFile(filepath.Join(m.dir, id+metaExt))
+	if err != nil {
+		return Entry{}, err
+	}
+
+	var e Entry
+	err = json.Unmarshal(b, &e)
+	return e, err
+}
+
+// prune keeps only the newest keep logs
+func (m Manager) prune(keep int) error {
+	ee, err := m.List()
+	if err != nil {
+		return err
+	}
+
+	for len(ee) > keep {
+		e := ee[0]
+		if err := os.Remove(e.File); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err := os.Remove(filepath.Join(m.dir, e.ID+metaExt)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		ee = ee[1:]
+	}
+
+	return nil
+}
+
+// Log is the size capped writer of a run log. The zero value discards the output.
+// The output is redacted line by line, so a secret split across writes is masked too.
+type Log struct {
+	file     *os.File
+	entry    Entry
+	meta     string
+	written  int64
+	now      func() time.Time
+	redactor redact.Redactor
+	pending  []byte
+}
+
+// Enabled tells whether the log keeps the output
+func (l *Log) Enabled() bool {
+	return l.file != nil
+}
+
+// Entry returns the metadata of the log
+func (l *Log) Entry() Entry {
+	return l.entry
+}
+
+// Write keeps the output up to MaxSize bytes and never fails,
+// so a log problem never breaks the formula run
+func (l *Log) Write(p []byte) (int, error) {
+	if l.file == nil || l.entry.Truncated {
+		return len(p), nil
+	}
+
+	l.pending = append(l.pending, p...)
+	i := bytes.LastIndexByte(l.pending, '\n')
+	if i < 0 && len(l.pending) < MaxSize {
+		return len(p), nil
+	}
+	if i < 0 {
+		i = len(l.pending) - 1
+	}
+
+	lines := l.pending[:i+1]
+	l.pending = append([]byte(nil), l.pending[i+1:]...)
+	l.write([]byte(l.redactor.String(string(lines))))
+	return len(p), nil
+}
+
+// write keeps the redacted output up to MaxSize bytes
+func (l *Log) write(b []byte) {
+	if l.entry.Truncated {
+		return
+	}
+
+	if l.written+int64(len(b)) > MaxSize {
+		b = b[:MaxSize-l.written]
+		l.entry.Truncated = true
+	}
+
+	n, _ := l.file.Write(b)
+	l.written += int64(n)
+
+	if l.entry.Truncated {
+		_, _ = fmt.Fprintf(l.file, truncatedMsg, MaxSize)
+	}
+}
+
+// Close finishes the log recording the run result
+func (l *Log) Close(runErr error) error {
+	if l.file == nil {
+		return nil
+	}
+
+	if len(l.pending) > 0 {
+		l.write([]byte(l.redactor.String(string(l.pending))))
+		l.pending = nil
+	}
+
+	l.entry.End = l.now()
+	if runErr != nil {
+		l.entry.Error = l.redactor.String(runErr.Error())
+	}
+
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+
+	return l.writeMeta()
+}
+
+func (l *Log) writeMeta() error {
+	b, err := json.Marshal(l.entry)
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteFilePerm(l.meta, b, 0600)
+}
+
+// fileName turns the command path into a file name, e.g. "rit aws create" into "rit-aws-create"
+func fileName(command string) string {
+	return strings.Join(strings.FieldsFunc(command, func(r rune) bool {
+		return r == ' ' || r == os.PathSeparator || r == '/'
+	}), "-")
+}
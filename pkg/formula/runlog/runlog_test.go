@@ -0,0 +1,174 @@
+package runlog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/redact"
+)
+
+func newTestManager(t *testing.T) Manager {
+	t.Helper()
+	home := filepath.Join(os.TempDir(), "rit-runlog")
+	_ = fileutil.RemoveDir(home)
+	t.Cleanup(func() {
+		_ = fileutil.RemoveDir(home)
+	})
+
+	m := NewManager(home, true)
+	now := time.Date(2020, 7, 20, 10, 0, 0, 0, time.UTC)
+	m.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	return m
+}
+
+func TestCreateAndList(t *testing.T) {
+	m := newTestManager(t)
+
+	l, err := m.Create("", "rit aws create", nil, nil, redact.Redactor{})
+	if err != nil {
+		t.Fatalf("Create() got %v, want nil", err)
+	}
+	_, _ = fmt.Fprint(l, "creating bucket\n")
+	if err := l.Close(errors.New("exit status 1")); err != nil {
+		t.Fatalf("Close() got %v, want nil", err)
+	}
+
+	ee, err := m.List()
+	if err != nil || len(ee) != 1 {
+		t.Fatalf("List() got %v, %v, want one entry", ee, err)
+	}
+
+	e := ee[0]
+	if e.ID != "20200720T100001.000000-rit-aws-create" || e.Command != "rit aws create" {
+		t.Errorf("List() got entry %+v", e)
+	}
+	if e.Running() || e.Error != "exit status 1" {
+		t.Errorf("List() got running %v and error %q, want finished with exit status 1", e.Running(), e.Error)
+	}
+
+	var out bytes.Buffer
+	if err := m.Follow(e, &out); err != nil || out.String() != "creating bucket\n" {
+		t.Errorf("Follow() got %q, %v, want the log output", out.String(), err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	m := newTestManager(t)
+
+	for i := 0; i < MaxLogs+2; i++ {
+		l, err := m.Create("", fmt.Sprintf("rit test %d", i), nil, nil, redact.Redactor{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = l.Close(nil)
+	}
+
+	ee, _ := m.List()
+	if len(ee) != MaxLogs {
+		t.Fatalf("List() got %d logs, want %d", len(ee), MaxLogs)
+	}
+	if ee[0].Command != "rit test 2" {
+		t.Errorf("oldest log got %q, want rit test 2", ee[0].Command)
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	m := newTestManager(t)
+
+	labels := map[string]string{"ci": "123", "deploy_key": "k3y"}
+	l, err := m.Create("", "rit db create", []string{"--set", "password=abc123", "--token", "t0k3n"}, labels, redact.New("s3cr3t"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = fmt.Fprint(l, "connecting with s3")
+	_, _ = fmt.Fprint(l, "cr3t\ndone\n")
+	_ = l.Close(errors.New("auth failed for s3cr3t"))
+
+	want := []string{"--set", "password=" + redact.Mask, "--token", redact.Mask}
+	if e := l.Entry(); !reflect.DeepEqual(e.Args, want) || e.Error != "auth failed for "+redact.Mask {
+		t.Errorf("Entry() got args %v and error %q, want the secrets masked", e.Args, e.Error)
+	}
+	wantLabels := map[string]string{"ci": "123", "deploy_key": redact.Mask}
+	if e := l.Entry(); !reflect.DeepEqual(e.Labels, wantLabels) {
+		t.Errorf("Entry() got labels %v, want %v", e.Labels, wantLabels)
+	}
+
+	b, err := ioutil.ReadFile(l.Entry().File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(b); got != "connecting with "+redact.Mask+"\ndone\n" {
+		t.Errorf("log output got %q, want the secret split across writes masked", got)
+	}
+}
+
+func TestSizeCap(t *testing.T) {
+	m := newTestManager(t)
+
+	l, err := m.Create("", "rit big output", nil, nil, redact.Redactor{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunk := []byte(strings.Repeat("x", 1024))
+	for i := 0; i < MaxSize/len(chunk)+10; i++ {
+		if n, err := l.Write(chunk); n != len(chunk) || err != nil {
+			t.Fatalf("Write() got %d, %v, want the whole chunk written", n, err)
+		}
+	}
+	_ = l.Close(nil)
+
+	info, err := os.Stat(l.Entry().File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() > MaxSize+int64(len(truncatedMsg))+10 {
+		t.Errorf("log size got %d, want at most %d", info.Size(), MaxSize)
+	}
+	if !l.Entry().Truncated {
+		t.Errorf("Entry().Truncated got false, want true")
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	home := filepath.Join(os.TempDir(), "rit-runlog-disabled")
+	m := NewManager(home, false)
+
+	l, err := m.Create("", "rit aws create", nil, nil, redact.Redactor{})
+	if err != nil || l.Enabled() {
+		t.Fatalf("Create() got enabled %v, %v, want a disabled log", l.Enabled(), err)
+	}
+	if fileutil.Exists(home) {
+		t.Errorf("disabled manager created %s", home)
+	}
+}
+
+func TestDisabled_WithID(t *testing.T) {
+	home := filepath.Join(os.TempDir(), "rit-runlog-detached")
+	defer os.RemoveAll(home)
+	m := NewManager(home, false)
+
+	id := NewID(time.Date(2020, 7, 20, 10, 0, 0, 0, time.UTC), "rit aws tunnel")
+	l, err := m.Create(id, "rit aws tunnel", nil, nil, redact.Redactor{})
+	if err != nil || !l.Enabled() {
+		t.Fatalf("Create() got enabled %v, %v, want the log of the run with an id", l.Enabled(), err)
+	}
+	if err := l.Close(nil); err != nil {
+		t.Fatalf("Close() got %v, want nil", err)
+	}
+	if e := l.Entry(); e.ID != "20200720T100000.000000-rit-aws-tunnel" || !fileutil.Exists(e.File) {
+		t.Errorf("Create() got entry %+v, want the log of %s", e, id)
+	}
+}
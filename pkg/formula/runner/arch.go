@@ -0,0 +1,38 @@
+package runner
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const msgNoArchImage = "The formula has no image for %s on its config.json, its Dockerfile default image may run under emulation"
+
+// hostArch is the architecture the formula images are resolved for, a var so the tests
+// resolve the images of the other architectures
+var hostArch = runtime.GOARCH
+
+// archImage returns the image of the formula config.json for the architecture of rit,
+// empty when the formula has no images by architecture
+func archImage(c formula.Config) string {
+	if len(c.Images) == 0 {
+		return ""
+	}
+	img := c.Images[hostArch]
+	if img == "" {
+		prompt.Warning(fmt.Sprintf(msgNoArchImage, hostArch))
+	}
+	return img
+}
+
+// imageBuildArgs are the docker build args of the image of the architecture of rit, the
+// Dockerfile builds FROM it with ARG RIT_IMAGE
+func imageBuildArgs(c formula.Config) []string {
+	img := archImage(c)
+	if img == "" {
+		return nil
+	}
+	return []string{"--build-arg", fmt.Sprintf(formula.EnvPattern, formula.ImageEnv, img)}
+}
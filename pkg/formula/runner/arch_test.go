@@ -0,0 +1,34 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestImageBuildArgs(t *testing.T) {
+	defer func(arch string) { hostArch = arch }(hostArch)
+	images := map[string]string{"amd64": "node:14", "arm64": "arm64v8/node:14"}
+
+	tests := []struct {
+		name   string
+		arch   string
+		images map[string]string
+		want   []string
+	}{
+		{name: "no images", arch: "arm64"},
+		{name: "amd64", arch: "amd64", images: images, want: []string{"--build-arg", "RIT_IMAGE=node:14"}},
+		{name: "arm64", arch: "arm64", images: images, want: []string{"--build-arg", "RIT_IMAGE=arm64v8/node:14"}},
+		{name: "no image of the architecture", arch: "s390x", images: images},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hostArch = tt.arch
+			if got := imageBuildArgs(formula.Config{Images: tt.images}); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("imageBuildArgs() got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,60 +1,362 @@
 package runner
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/history"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runlog"
+	"github.com/ZupIT/ritchie-cli/pkg/metrics"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/redact"
 
 	"github.com/ZupIT/ritchie-cli/pkg/api"
 )
 
+const (
+	msgCaptureMetricsFailed = "Unable to capture the run metrics on %s: %v"
+	msgRecordHistoryFailed  = "Unable to record the run on the history: %v"
+	msgDebugCommand         = "Running %s: %s"
+)
+
+var (
+	// commandWriter is where --print-command prints, stderr keeps the formula stdout clean
+	commandWriter io.Writer = os.Stderr
+
+	// runHistory records the formula runs, no run is recorded while it is nil
+	runHistory history.Recorder
+)
+
+// SetHistory sets where the formula runs are recorded, as read by rit history
+func SetHistory(h history.Recorder) {
+	runHistory = h
+}
+
 type DefaultRunner struct {
 	formula.PreRunner
 	formula.PostRunner
 	formula.InputRunner
+	logs runlog.Creator
+	// launcher is the executable plugin the formula command runs through, none on the local runs
+	launcher string
 }
 
-func NewDefaultRunner(preRunner formula.PreRunner, postRunner formula.PostRunner, inRunner formula.InputRunner) DefaultRunner {
-	return DefaultRunner{preRunner, postRunner, inRunner}
+func NewDefaultRunner(preRunner formula.PreRunner, postRunner formula.PostRunner, inRunner formula.InputRunner, logs runlog.Creator) DefaultRunner {
+	return DefaultRunner{preRunner, postRunner, inRunner, logs, ""}
 }
 
-func (d DefaultRunner) Run(def formula.Definition, inputType api.TermInputType, verboseFlag string) error {
+func (d DefaultRunner) Run(def formula.Definition, inputType api.TermInputType, verboseFlag string) (err error) {
+	p := newProfile(def)
+	defer p.print(def.ProfileJSON)
+
 	setup, err := d.PreRun(def)
 	if err != nil {
 		return err
 	}
+	defer cleanFailed(setup, false, &err)
+
+	if !def.DryRun {
+		if err := checkRequires(setup.Config.Requires); err != nil {
+			return err
+		}
+	}
+
+	o, err := isolate(def, &setup)
+	if err != nil {
+		return err
+	}
+	defer o.remove()
+	p.phase(phaseSetup)
 
-	cmd := exec.Command(setup.TmpBinFilePath)
+	cmd, err := localCommand(def, setup)
+	if err != nil {
+		return err
+	}
+	if d.launcher != "" {
+		cmd = exec.Command(d.launcher, cmd.Args...)
+	}
 
 	cmd.Env = os.Environ()
+	local := len(cmd.Env)
 	pwdEnv := fmt.Sprintf(formula.EnvPattern, formula.PwdEnv, setup.Pwd)
 	cPwdEnv := fmt.Sprintf(formula.EnvPattern, formula.CPwdEnv, setup.Pwd)
 	verboseEnv := fmt.Sprintf(formula.EnvPattern, formula.VerboseEnv, verboseFlag)
 	cmd.Env = append(cmd.Env, pwdEnv)
 	cmd.Env = append(cmd.Env, cPwdEnv)
 	cmd.Env = append(cmd.Env, verboseEnv)
+	addArgsEnv(cmd, def.Args)
+	cmd.Env = append(cmd.Env, def.Env...)
+
+	outputs, err := outputsDir(def, setup)
+	if err != nil {
+		return err
+	} else if outputs != "" {
+		cmd.Env = append(cmd.Env, outputsEnv(filepath.Join(outputs, outputsFile)))
+	}
 
 	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
+	if def.Stdin != nil {
+		cmd.Stdin = def.Stdin
+	}
+	cmd.Stdout = formulaStdout(def)
 	cmd.Stderr = os.Stderr
 
 	if err := d.Inputs(cmd, setup, inputType); err != nil {
 		return err
 	}
+	keepInputs(def, cmd.Env, setup.Config.Inputs)
+	p.phase(phaseInputs)
+
+	if def.DryRun {
+		dryRun(def, cmd.Args, nil, cmd.Env[local:], setup, false)
+		return nil
+	}
 
-	if err := cmd.Start(); err != nil {
+	removeSandbox, err := sandbox(cmd, def)
+	if err != nil {
 		return err
 	}
+	defer removeSandbox()
 
-	if err := cmd.Wait(); err != nil {
+	err = runHooked(def, setup, func() error {
+		return runLogged(cmd, d.logs, def, setup.Config.Inputs, stopProcess)
+	})
+	p.phase(phaseExecution)
+	p.process(cmd.ProcessState, false)
+	if err != nil {
+		return err
+	}
+
+	if err := printOutputs(def, setup, outputs); err != nil {
 		return err
 	}
 
 	if err := d.PostRun(setup, false); err != nil {
 		return err
 	}
+	p.phase(phasePostRun)
+
+	return o.commit()
+}
+
+// formulaStdout returns the stdout of the formula, stderr when the run prints its outputs
+// as json or yaml so the stdout of rit is only the outputs
+func formulaStdout(def formula.Definition) io.Writer {
+	if def.OutputFormat != "" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// addArgsEnv adds the args informed after "--" to the FORMULA_ARGS env,
+// each one shell quoted so that eval set -- "$FORMULA_ARGS" restores them
+func addArgsEnv(cmd *exec.Cmd, args []string) {
+	if len(args) == 0 {
+		return
+	}
 
-	return nil
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shellQuote(a)
+	}
+	argsEnv := fmt.Sprintf(formula.EnvPattern, formula.ArgsEnv, strings.Join(quoted, " "))
+	cmd.Env = append(cmd.Env, argsEnv)
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// printCommand prints the command line the formula runs with, shell quoted to be copied,
+// as --print-command does. Its secrets are masked, the env of a docker run is on its env file.
+// The env vars holding secrets are listed masked, e.g. PASS=******, so that they are set
+// when the command is copied.
+func printCommand(cmd *exec.Cmd, r redact.Redactor) {
+	fmt.Fprintln(commandWriter, "+ "+commandLine(cmd.Args, r))
+	for i, e := range r.Args(cmd.Env) {
+		if e != cmd.Env[i] {
+			fmt.Fprintln(commandWriter, "  "+e)
+		}
+	}
+}
+
+// commandLine returns the args masked by the redactor, the ones a shell would split or expand are quoted
+func commandLine(args []string, r redact.Redactor) string {
+	args = r.Args(args)
+	for i, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\n'\"\\$`|&;<>()*?[]#~{}!") {
+			args[i] = shellQuote(a)
+		}
+	}
+	return strings.Join(args, " ")
+}
+
+// runLogged runs the formula command teeing its output into a run log.
+// While teeing, the formula stdout and stderr are pipes instead of the
+// terminal, so interactive formulas checking for a TTY may behave as if
+// their output was redirected. Disable the run logs to avoid it.
+func runLogged(cmd *exec.Cmd, logs runlog.Creator, def formula.Definition, inputs []formula.Input, stop stopFunc) error {
+	r := redact.New(secretValues(cmd.Env, inputs)...)
+	log, err := logs.Create(def.LogID, def.Command, def.Args, def.Labels, r)
+	if err != nil {
+		return err
+	}
+
+	if def.PrintCommand {
+		printCommand(cmd, r)
+	} else {
+		prompt.Debug(fmt.Sprintf(msgDebugCommand, def.Command, commandLine(cmd.Args, r)))
+	}
+
+	start := time.Now()
+	if !log.Enabled() && !def.Timestamps {
+		err = runGraceful(cmd, def.Timeout, def.KillGrace, stop)
+		captureMetrics(def, r, start, len(inputs), err)
+		recordHistory(def, r, cmd.Env, inputs, start, err)
+		return err
+	}
+
+	// the run log keeps the output as the formula wrote it, a whole line at a time so that the
+	// lines of its stdout and stderr don't interleave, the terminal gets the output as it is
+	// written unless the lines are timestamped
+	logOut, logErr := newLineWriters(log, log)
+	writers := []*lineWriter{logOut, logErr}
+	out, errOut := formulaStdout(def), io.Writer(os.Stderr)
+	if def.Timestamps {
+		tsOut, tsErr := newLineWriters(timestampWriter{out, def.Command}, timestampWriter{errOut, def.Command})
+		out, errOut = tsOut, tsErr
+		writers = append(writers, tsOut, tsErr)
+	}
+	cmd.Stdout = io.MultiWriter(out, logOut)
+	cmd.Stderr = io.MultiWriter(errOut, logErr)
+
+	err = runGraceful(cmd, def.Timeout, def.KillGrace, stop)
+	for _, w := range writers {
+		_ = w.Flush()
+	}
+	captureMetrics(def, r, start, len(inputs), err)
+	recordHistory(def, r, cmd.Env, inputs, start, err)
+	if cErr := log.Close(err); cErr != nil && err == nil {
+		return cErr
+	}
+	return err
+}
+
+// captureMetrics appends the run event to the --capture-metrics file, a failure
+// to write it is only reported as it must not fail the formula run
+func captureMetrics(def formula.Definition, r redact.Redactor, start time.Time, inputs int, err error) {
+	if def.CaptureMetrics == "" {
+		return
+	}
+
+	e := metrics.RunEvent{
+		CmdUse: metrics.CmdUse{
+			Cmd:    strings.Join(r.Args(append(strings.Fields(def.Command), def.Args...)), " "),
+			Labels: r.Map(def.Labels),
+		},
+		Formula:  def.Path,
+		Start:    start.UTC(),
+		Duration: time.Since(start).Seconds(),
+		ExitCode: exitCode(err),
+		Inputs:   inputs,
+	}
+	if cErr := metrics.Capture(def.CaptureMetrics, e); cErr != nil {
+		prompt.Warning(fmt.Sprintf(msgCaptureMetricsFailed, def.CaptureMetrics, cErr))
+	}
+}
+
+// recordHistory records the run on the history with its plain inputs, see plainInput, and their
+// hash, the secret ones are left out of both. A failure to write it is only reported as it must
+// not fail the formula run.
+func recordHistory(def formula.Definition, r redact.Redactor, env []string, inputs []formula.Input, start time.Time, err error) {
+	if runHistory == nil {
+		return
+	}
+
+	replayable := map[string]string{}
+	for _, in := range inputs {
+		if !plainInput(in) {
+			continue
+		}
+		prefix := strings.ToUpper(in.Name) + "="
+		for _, e := range env {
+			if strings.HasPrefix(e, prefix) {
+				replayable[in.Name] = strings.TrimPrefix(e, prefix)
+			}
+		}
+	}
+	replayable = r.Map(replayable)
+
+	e := history.Entry{
+		Command:    def.Command,
+		Formula:    def.Path,
+		Repo:       def.RepoName,
+		Args:       r.Args(def.Args),
+		Labels:     r.Map(def.Labels),
+		Inputs:     replayable,
+		InputsHash: history.InputsHash(replayable),
+		Start:      start.UTC(),
+		Duration:   time.Since(start).Seconds(),
+		ExitCode:   exitCode(err),
+	}
+	if hErr := runHistory.Record(e); hErr != nil {
+		prompt.Warning(fmt.Sprintf(msgRecordHistoryFailed, hErr))
+	}
+}
+
+// exitCode returns the exit code of the formula run, -1 when it didn't exit on its own
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// plainInput tells whether the value of the input is kept on the history and shown on the run
+// log, the values of the text, bool, int, float, credential, multiselect and path inputs not marked
+// as sensitive, the provider of a credential input is its value
+func plainInput(in formula.Input) bool {
+	if in.Sensitive {
+		return false
+	}
+	switch in.Type {
+	case "text", "bool", formula.IntType, formula.FloatType, formula.CredentialType, formula.MultiselectType, formula.PathType:
+		return true
+	default:
+		return false
+	}
+}
+
+// secretValues returns the values of the password, credential and sensitive inputs on the formula env,
+// so that they are masked on the run log. The path of the file of an editor input isn't masked,
+// the fields of the credential of a credential input are, see credentialFields.
+func secretValues(env []string, inputs []formula.Input) []string {
+	var ss []string
+	for _, in := range inputs {
+		if in.Type == formula.CredentialType {
+			ss = append(ss, credentialFields(env, in)...)
+		}
+		if plainInput(in) || in.Type == formula.EditorType {
+			continue
+		}
+
+		prefix := strings.ToUpper(in.Name) + "="
+		for _, e := range env {
+			if strings.HasPrefix(e, prefix) {
+				ss = append(ss, strings.TrimPrefix(e, prefix))
+			}
+		}
+	}
+	return ss
 }
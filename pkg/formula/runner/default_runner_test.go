@@ -1,15 +1,26 @@
 package runner
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/ZupIT/ritchie-cli/pkg/api"
 	"github.com/ZupIT/ritchie-cli/pkg/env"
 	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/history"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runlog"
+	"github.com/ZupIT/ritchie-cli/pkg/metrics"
+	"github.com/ZupIT/ritchie-cli/pkg/redact"
 )
 
 var RepoUrl = os.Getenv("REPO_URL")
@@ -108,8 +119,8 @@ func TestDefaultRunner_Run(t *testing.T) {
 			}
 
 			resolvers := env.Resolvers{"test": in.envMock}
-			inputManager := NewInputManager(resolvers, in.inText, in.inText, in.inBool, in.inPass)
-			defaultRunner := NewDefaultRunner(preRunner, postRunner, inputManager)
+			inputManager := NewInputManager(resolvers, in.inText, in.inText, in.inText, inputMock{}, in.inText, in.inBool, in.inPass, false)
+			defaultRunner := NewDefaultRunner(preRunner, postRunner, inputManager, runlog.NewManager(home, true))
 
 			got := defaultRunner.Run(def, api.Prompt, verboseFlag)
 
@@ -122,15 +133,28 @@ func TestDefaultRunner_Run(t *testing.T) {
 }
 
 type inputMock struct {
-	text    string
-	boolean bool
-	err     error
+	text     string
+	boolean  bool
+	selected []string
+	err      error
 }
 
 func (i inputMock) List(string, []string) (string, error) {
 	return i.text, i.err
 }
 
+func (i inputMock) Multiselect(string, []string, []string, bool) ([]string, error) {
+	return i.selected, i.err
+}
+
+func (i inputMock) Path(string, string, string) (string, error) {
+	return i.text, i.err
+}
+
+func (i inputMock) Editor(string, string, string, bool) (string, error) {
+	return i.text, i.err
+}
+
 func (i inputMock) Text(string, bool, ...string) (string, error) {
 	return i.text, i.err
 }
@@ -172,3 +196,147 @@ type postRunnerMock struct {
 func (p postRunnerMock) PostRun(formula.Setup, bool) error {
 	return p.error
 }
+
+func TestAddArgsEnv(t *testing.T) {
+	cmd := &exec.Cmd{}
+	addArgsEnv(cmd, nil)
+	if len(cmd.Env) != 0 {
+		t.Errorf("addArgsEnv without args got env %v, want none", cmd.Env)
+	}
+
+	addArgsEnv(cmd, []string{"--region", "sa east", "it's"})
+	want := `FORMULA_ARGS='--region' 'sa east' 'it'\''s'`
+	if len(cmd.Env) != 1 || cmd.Env[0] != want {
+		t.Errorf("addArgsEnv got env %v, want %s", cmd.Env, want)
+	}
+}
+
+func TestRunLoggedCaptureMetrics(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-capture-metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	def := formula.Definition{
+		Command:        "rit mock test",
+		Path:           "mock/test",
+		Args:           []string{"--password", "s3cr3t"},
+		Labels:         map[string]string{"ci": "123", "token": "t0k3n"},
+		CaptureMetrics: filepath.Join(dir, "metrics.jsonl"),
+	}
+	inputs := []formula.Input{{Name: "name", Type: "text"}, {Name: "pass", Type: "password"}}
+	logs := runlog.NewManager(dir, false)
+	for _, script := range []string{"exit 0", "exit 3"} {
+		cmd := exec.Command("sh", "-c", script)
+		cmd.Env = []string{"PASS=hunter2"}
+		_ = runLogged(cmd, logs, def, inputs, stopProcess)
+	}
+
+	b, err := ioutil.ReadFile(def.CaptureMetrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("runLogged captured %d events, want 2", len(lines))
+	}
+	for i, wantCode := range []int{0, 3} {
+		var e metrics.RunEvent
+		if err := json.Unmarshal([]byte(lines[i]), &e); err != nil {
+			t.Fatal(err)
+		}
+		want := metrics.RunEvent{
+			CmdUse: metrics.CmdUse{
+				Cmd:    "rit mock test --password " + redact.Mask,
+				Labels: map[string]string{"ci": "123", "token": redact.Mask},
+			},
+			Formula:  "mock/test",
+			Start:    e.Start,
+			Duration: e.Duration,
+			ExitCode: wantCode,
+			Inputs:   2,
+		}
+		if e.Start.IsZero() || !reflect.DeepEqual(e, want) {
+			t.Errorf("captured event got %+v, want %+v", e, want)
+		}
+	}
+}
+
+type historyRecorderMock struct {
+	entries *[]history.Entry
+}
+
+func (h historyRecorderMock) Record(e history.Entry) error {
+	*h.entries = append(*h.entries, e)
+	return nil
+}
+
+func TestRunLoggedHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var entries []history.Entry
+	SetHistory(historyRecorderMock{&entries})
+	defer SetHistory(nil)
+
+	def := formula.Definition{
+		Command:  "rit mock test",
+		Path:     "mock/test",
+		RepoName: "commons",
+		Args:     []string{"--password", "s3cr3t"},
+		Labels:   map[string]string{"ci": "123"},
+	}
+	inputs := []formula.Input{{Name: "name", Type: "text"}, {Name: "pass", Type: "password"}}
+	cmd := exec.Command("sh", "-c", "exit 3")
+	cmd.Env = []string{"NAME=bucket", "PASS=hunter2"}
+	_ = runLogged(cmd, runlog.NewManager(dir, false), def, inputs, stopProcess)
+
+	if len(entries) != 1 {
+		t.Fatalf("runLogged recorded %d runs, want 1", len(entries))
+	}
+	e := entries[0]
+	want := history.Entry{
+		Command:    "rit mock test",
+		Formula:    "mock/test",
+		Repo:       "commons",
+		Args:       []string{"--password", redact.Mask},
+		Labels:     map[string]string{"ci": "123"},
+		Inputs:     map[string]string{"name": "bucket"},
+		InputsHash: history.InputsHash(map[string]string{"name": "bucket"}),
+		Start:      e.Start,
+		Duration:   e.Duration,
+		ExitCode:   3,
+	}
+	if e.Start.IsZero() || !reflect.DeepEqual(e, want) {
+		t.Errorf("recorded run got %+v, want %+v", e, want)
+	}
+}
+
+func TestRunLoggedPrintCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-print-command")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var out bytes.Buffer
+	commandWriter = &out
+	defer func() { commandWriter = os.Stderr }()
+
+	def := formula.Definition{Command: "rit mock test", PrintCommand: true}
+	inputs := []formula.Input{{Name: "pass", Type: "password"}}
+	cmd := exec.Command("sh", "-c", "exit 0", "sh", "--password", "s3cr3t", "hunter2 here", "it's")
+	cmd.Env = []string{"HOME=/home/rit", "PASS=hunter2", "GITHUB_TOKEN=ghp"}
+	if err := runLogged(cmd, runlog.NewManager(dir, false), def, inputs, stopProcess); err != nil {
+		t.Fatalf("runLogged() = %v, want nil", err)
+	}
+
+	want := `+ sh -c 'exit 0' sh --password '******' '****** here' 'it'\''s'` + "\n  PASS=******\n  GITHUB_TOKEN=******\n"
+	if out.String() != want {
+		t.Errorf("printed %q, want %q", out.String(), want)
+	}
+}
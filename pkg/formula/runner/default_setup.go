@@ -1,12 +1,12 @@
 package runner
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
 
 	"github.com/docker/docker/pkg/urlutil"
 	"github.com/google/uuid"
@@ -14,9 +14,11 @@ import (
 	"github.com/ZupIT/ritchie-cli/pkg/api"
 	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/checksum"
 	"github.com/ZupIT/ritchie-cli/pkg/http/headers"
 	"github.com/ZupIT/ritchie-cli/pkg/prompt"
 	"github.com/ZupIT/ritchie-cli/pkg/session"
+	"github.com/ZupIT/ritchie-cli/pkg/stream"
 )
 
 var (
@@ -81,32 +83,49 @@ func (d DefaultSetup) Setup(def formula.Definition) (formula.Setup, error) {
 	tmpBinFilePath := def.BinFilePath(tmpBinDir, binName)
 
 	s := formula.Setup{
-		Pwd:            pwd,
-		FormulaPath:    formulaPath,
-		BinPath:        binPath,
-		TmpDir:         tmpDir,
-		TmpBinDir:      tmpBinDir,
-		TmpBinFilePath: tmpBinFilePath,
-		Config:         config,
+		Pwd:              pwd,
+		FormulaPath:      formulaPath,
+		BinPath:          binPath,
+		TmpDir:           tmpDir,
+		TmpBinDir:        tmpBinDir,
+		TmpBinFilePath:   tmpBinFilePath,
+		Config:           config,
+		RequiredFirst:    def.RequiredFirst,
+		AcceptDefaults:   def.AcceptDefaults,
+		InputTimeout:     def.InputTimeout,
+		InputTimeoutFail: def.InputTimeoutFail,
+		Engine:           Engine(def.Runner),
 	}
 
 	return s, nil
 }
 
+// LoadConfig reads the config.json of the formula, downloading it when the formula never ran
+func (d DefaultSetup) LoadConfig(def formula.Definition) (formula.Config, error) {
+	return d.loadConfig(def.FormulaPath(d.ritchieHome), def)
+}
+
 func (d DefaultSetup) loadConfig(formulaPath string, def formula.Definition) (formula.Config, error) {
 	configName := def.ConfigName()
 	configPath := def.ConfigPath(formulaPath, configName)
-	if !fileutil.Exists(configPath) {
+	err := loadOnce(formulaPath, configPath, func() error {
 		url := def.ConfigURL(configName)
 		if !urlutil.IsURL(url) {
-			return formula.Config{}, ErrInvalidRepoUrl
+			return ErrInvalidRepoUrl
 		}
 
 		prompt.Info("Downloading formula config...")
 		if err := d.downloadConfig(url, formulaPath, configName, def.RepoName); err != nil {
-			return formula.Config{}, err
+			return err
+		}
+		if err := checksum.Save(formulaPath); err != nil {
+			return err
 		}
 		prompt.Success("Formula config download completed!")
+		return nil
+	})
+	if err != nil {
+		return formula.Config{}, err
 	}
 
 	configFile, err := ioutil.ReadFile(configPath)
@@ -114,15 +133,22 @@ func (d DefaultSetup) loadConfig(formulaPath string, def formula.Definition) (fo
 		return formula.Config{}, err
 	}
 
-	var formulaConfig formula.Config
-	if err := json.Unmarshal(configFile, &formulaConfig); err != nil {
+	formulaConfig, err := formula.UnmarshalConfig(configFile, def.RepoName)
+	if err != nil {
 		return formula.Config{}, err
 	}
+
+	if err := formula.ValidateInputs(formulaConfig.Inputs); err != nil {
+		return formula.Config{}, prompt.NewError(err.Error())
+	}
+	for _, w := range formula.InputWarnings(formulaConfig.Inputs) {
+		prompt.Warning(w)
+	}
 	return formulaConfig, nil
 }
 
 func (d DefaultSetup) loadBundle(formulaPath, binFilePath string, def formula.Definition) error {
-	if !fileutil.Exists(binFilePath) {
+	return loadOnce(formulaPath, binFilePath, func() error {
 		url := def.BundleURL()
 		if !urlutil.IsURL(url) {
 			return ErrInvalidRepoUrl
@@ -137,9 +163,31 @@ func (d DefaultSetup) loadBundle(formulaPath, binFilePath string, def formula.De
 		if err := unzipFile(zipFile, formulaPath); err != nil {
 			return err
 		}
+
+		// the checksums let rit diff repo list the files changed after the install
+		return checksum.Save(formulaPath)
+	})
+}
+
+// loadOnce downloads the file on path of the formula with load unless it exists. The download
+// is made under the lock of the formula, so of the rit processes running a formula that isn't
+// downloaded yet, e.g. the jobs of a CI matrix, only one downloads it and the others wait for it.
+// The file is checked without the lock first, so load must make it show up complete at once.
+func loadOnce(formulaPath, path string, load func() error) error {
+	if fileutil.Exists(path) {
+		return nil
+	}
+
+	unlock, err := stream.Lock(formulaPath)
+	if err != nil {
+		return err
 	}
+	defer unlock()
 
-	return nil
+	if fileutil.Exists(path) {
+		return nil
+	}
+	return load()
 }
 
 func (d DefaultSetup) downloadFormulaBundle(url, destPath, zipName, repoName string) (string, error) {
@@ -230,22 +278,24 @@ func (d DefaultSetup) downloadConfig(url, destPath, configName, repoName string)
 		return err
 	}
 
-	out, err := os.Create(file)
+	// the config is checked without the lock, so it is written at once
+	b, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return err
 	}
-	defer out.Close()
-
-	if _, err := io.Copy(out, resp.Body); err != nil {
-		return err
-	}
-
-	return nil
+	return stream.WriteAtomic(file, b, 0644)
 }
 
 func createWorkDir(ritchieHome, binPath string, def formula.Definition) (string, string, error) {
+	// the tmp area of the ritchie home may be read-only, the run is staged on the system tmp dir then
+	tmpHome := ritchieHome
+	tmp := filepath.Join(ritchieHome, "tmp")
+	if err := fileutil.CreateDirIfNotExists(tmp, 0755); err != nil || !fileutil.IsWritable(tmp) {
+		tmpHome = filepath.Join(os.TempDir(), "rit")
+	}
+
 	u := uuid.New().String()
-	tDir, tBDir := def.TmpWorkDirPath(ritchieHome, u)
+	tDir, tBDir := def.TmpWorkDirPath(tmpHome, u)
 
 	if err := fileutil.CreateDirIfNotExists(tBDir, 0755); err != nil {
 		return "", "", err
@@ -258,18 +308,40 @@ func createWorkDir(ritchieHome, binPath string, def formula.Definition) (string,
 	return tDir, tBDir, nil
 }
 
+// unzipFile extracts the bundle on a temp dir of destPath then renames its entries into
+// destPath, so the bin dir shows up at once and the rit processes finding the bin file
+// without the lock never run a bundle half extracted
 func unzipFile(filename, destPath string) error {
-	if err := fileutil.CreateDirIfNotExists(destPath, 0655); err != nil {
+	if err := fileutil.CreateDirIfNotExists(destPath, 0755); err != nil {
 		return err
 	}
 
-	if err := fileutil.Unzip(filename, destPath); err != nil {
+	tmp, err := ioutil.TempDir(destPath, ".bundle-")
+	if err != nil {
 		return err
 	}
+	defer os.RemoveAll(tmp)
 
-	if err := fileutil.RemoveFile(filename); err != nil {
+	if err := fileutil.Unzip(filename, tmp); err != nil {
+		return err
+	}
+
+	entries, err := ioutil.ReadDir(tmp)
+	if err != nil {
 		return err
 	}
+	for _, e := range entries {
+		target := filepath.Join(destPath, e.Name())
+		// a dir can't be renamed over a dir, the leftovers of a failed install are replaced
+		if e.IsDir() {
+			if err := os.RemoveAll(target); err != nil {
+				return err
+			}
+		}
+		if err := os.Rename(filepath.Join(tmp, e.Name()), target); err != nil {
+			return err
+		}
+	}
 
-	return nil
+	return fileutil.RemoveFile(filename)
 }
@@ -1,9 +1,13 @@
 package runner
 
 import (
+	"archive/zip"
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
@@ -113,3 +117,80 @@ func (s sessManagerMock) Current() (session.Session, error) {
 func (s sessManagerMock) Destroy() error {
 	return s.error
 }
+
+func TestCreateWorkDirReadOnlyTmp(t *testing.T) {
+	home := filepath.Join(os.TempDir(), "rit-read-only-tmp")
+	binPath := filepath.Join(home, "bin")
+	_ = fileutil.RemoveDir(home)
+	if err := os.MkdirAll(binPath, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	defer fileutil.RemoveDir(home)
+
+	// a file in place of the tmp dir makes the home tmp area unusable, even for root
+	if err := fileutil.WriteFile(filepath.Join(home, "tmp"), []byte{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fileutil.WriteFile(filepath.Join(binPath, "run.sh"), []byte("echo ok")); err != nil {
+		t.Fatal(err)
+	}
+
+	def := formula.Definition{Path: "mock/test"}
+	tmpDir, tmpBinDir, err := createWorkDir(home, binPath, def)
+	if err != nil {
+		t.Fatalf("createWorkDir() got %v, want nil", err)
+	}
+	defer fileutil.RemoveDir(tmpDir)
+
+	if !strings.HasPrefix(tmpDir, filepath.Join(os.TempDir(), "rit", "tmp")) {
+		t.Errorf("createWorkDir() got tmp dir %s, want it on the system tmp dir", tmpDir)
+	}
+	if !fileutil.Exists(filepath.Join(tmpBinDir, "run.sh")) {
+		t.Errorf("createWorkDir() did not copy the formula bin to %s", tmpBinDir)
+	}
+}
+
+func TestUnzipFile(t *testing.T) {
+	formulaPath, err := ioutil.TempDir("", "rit-unzip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(formulaPath)
+
+	bundle := filepath.Join(formulaPath, "linux.zip")
+	f, err := os.Create(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	_, _ = w.Create("bin/")
+	run, _ := w.Create("bin/run.sh")
+	_, _ = run.Write([]byte("echo ok"))
+	_ = w.Close()
+	_ = f.Close()
+
+	// the leftovers of a failed install are replaced and the config kept
+	_ = os.MkdirAll(filepath.Join(formulaPath, "bin"), os.ModePerm)
+	if err := fileutil.WriteFile(filepath.Join(formulaPath, "bin", "stale.sh"), []byte{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fileutil.WriteFile(filepath.Join(formulaPath, "config.json"), []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := unzipFile(bundle, formulaPath); err != nil {
+		t.Fatalf("unzipFile() got %v", err)
+	}
+
+	entries, _ := ioutil.ReadDir(formulaPath)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if strings.Join(names, ",") != "bin,config.json" {
+		t.Errorf("unzipFile() left %v, want bin and config.json", names)
+	}
+	if !fileutil.Exists(filepath.Join(formulaPath, "bin", "run.sh")) || fileutil.Exists(filepath.Join(formulaPath, "bin", "stale.sh")) {
+		t.Errorf("unzipFile() did not replace the bin dir with the bundle one")
+	}
+}
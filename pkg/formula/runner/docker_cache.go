@@ -0,0 +1,275 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/slice/sliceutil"
+)
+
+const (
+	// PulledImagesFile is the file of the images pulled by rit prepull, relative to the ritchie home
+	PulledImagesFile = "%s/docker/pulled-images.json"
+
+	imageBuilderKey    = "dockerImageBuilder"
+	msgSkipImages      = "Skipping the docker images of %s: %v"
+	msgKeepPulled      = "Keeping the pulled images, the images of some formulas are unknown"
+	msgRemoveImagesErr = "unable to remove the docker images %s"
+)
+
+// builtImage is the name of the images the docker runs build, a random uuid
+var builtImage = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// DockerCache pulls the images the installed formulas are built from, so their first docker
+// run doesn't download them, and finds the images rit left behind. The pulled images are
+// recorded, so they are pruned once no installed formula is built from them anymore.
+type DockerCache struct {
+	formula.Setuper
+	formula.PostRunner
+	file   string
+	engine string
+}
+
+// NewDockerCache creates the cache of the images of the formulas on the container engine
+func NewDockerCache(setuper formula.Setuper, postRunner formula.PostRunner, ritchieHome, engine string) DockerCache {
+	return DockerCache{setuper, postRunner, fmt.Sprintf(PulledImagesFile, ritchieHome), engine}
+}
+
+// PullAll pulls the images of the formulas Dockerfiles and of their dockerImageBuilder
+// config, each image once. The formulas that can't be set up are reported and skipped.
+func (d DockerCache) PullAll(defs []formula.Definition, parallelism int) ([]formula.ImagePull, error) {
+	if err := CheckDocker(d.engine); err != nil {
+		return nil, err
+	}
+
+	byImage, _, err := d.images(defs)
+	if err != nil {
+		return nil, err
+	}
+
+	pulls := make([]formula.ImagePull, 0, len(byImage))
+	for img, formulas := range byImage {
+		pulls = append(pulls, formula.ImagePull{Image: img, Formulas: formulas})
+	}
+	sort.Slice(pulls, func(i, j int) bool { return pulls[i].Image < pulls[j].Image })
+
+	slots := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i := range pulls {
+		wg.Add(1)
+		go func(p *formula.ImagePull) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			// quiet, the progress of the parallel pulls would be mixed up
+			prompt.Info(fmt.Sprintf(msgPulling, p.Image))
+			if _, err := dockerOutput(d.engine, dockerPullCmd, "--quiet", p.Image); err != nil {
+				p.Err = prompt.NewError(fmt.Sprintf(msgPullFailed, p.Image, err))
+			}
+		}(&pulls[i])
+	}
+	wg.Wait()
+
+	pulled, err := d.pulled()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pulls {
+		if p.Err == nil {
+			pulled[p.Image] = true
+		}
+	}
+	return pulls, d.savePulled(pulled)
+}
+
+// Unused returns the images the docker runs built and left behind, unless a container
+// uses them, and the pulled images none of the formulas is built from anymore. When a
+// formula can't be set up its images are unknown, so the pulled images are all kept.
+func (d DockerCache) Unused(defs []formula.Definition) ([]string, error) {
+	if err := CheckDocker(d.engine); err != nil {
+		return nil, err
+	}
+
+	used := map[string]bool{}
+	containers, err := dockerOutput(d.engine, "ps", "-a", "--format", "{{.Image}}")
+	if err != nil {
+		return nil, err
+	}
+	for _, img := range strings.Fields(containers) {
+		used[img] = true
+	}
+
+	var unused []string
+	images, err := dockerOutput(d.engine, "images", "--format", "{{.Repository}}")
+	if err != nil {
+		return nil, err
+	}
+	for _, img := range strings.Fields(images) {
+		if builtImage.MatchString(img) && !used[img] {
+			unused = append(unused, img)
+		}
+	}
+
+	byImage, complete, err := d.images(defs)
+	if err != nil {
+		return nil, err
+	}
+	if !complete {
+		prompt.Warning(msgKeepPulled)
+		sort.Strings(unused)
+		return unused, nil
+	}
+	pulled, err := d.pulled()
+	if err != nil {
+		return nil, err
+	}
+	for img := range pulled {
+		if _, ok := byImage[img]; !ok && !used[img] {
+			unused = append(unused, img)
+		}
+	}
+
+	sort.Strings(unused)
+	return unused, nil
+}
+
+// Remove removes the images and forgets the pulled ones, the images that can't be removed,
+// e.g. already removed or tagged again, are reported together
+func (d DockerCache) Remove(images []string) error {
+	pulled, err := d.pulled()
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, img := range images {
+		if _, err := dockerOutput(d.engine, dockerRemoveImageCmd, img); err != nil {
+			failed = append(failed, img)
+			continue
+		}
+		delete(pulled, img)
+	}
+
+	if err := d.savePulled(pulled); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return prompt.NewError(fmt.Sprintf(msgRemoveImagesErr, strings.Join(failed, ", ")))
+	}
+	return nil
+}
+
+// images returns the images the formulas are built from with the commands of the formulas
+// built from each one. Each formula is set up to read its Dockerfile, downloading its bundle
+// when needed, the formulas without a Dockerfile only run locally and have no image.
+// The formulas that can't be set up are skipped, complete tells whether none was.
+func (d DockerCache) images(defs []formula.Definition) (byImage map[string][]string, complete bool, err error) {
+	// the setup moves to the workdir of the formula, removed after it
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	byImage = map[string][]string{}
+	complete = true
+	for _, def := range defs {
+		images, err := d.formulaImages(def)
+		_ = os.Chdir(wd)
+		if err != nil {
+			prompt.Warning(fmt.Sprintf(msgSkipImages, def.Command, err))
+			complete = false
+			continue
+		}
+		for _, img := range images {
+			byImage[img] = append(byImage[img], def.Command)
+		}
+	}
+	return byImage, complete, nil
+}
+
+func (d DockerCache) formulaImages(def formula.Definition) ([]string, error) {
+	setup, err := d.Setup(def)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = d.PostRun(setup, false) }()
+
+	var images []string
+	if raw, ok := setup.Config.Extra[imageBuilderKey]; ok {
+		var builder string
+		if err := json.Unmarshal(raw, &builder); err == nil && builder != "" {
+			images = append(images, builder)
+		}
+	}
+	if img := archImage(setup.Config); img != "" {
+		images = append(images, img)
+	}
+
+	f, err := os.Open(filepath.Join(setup.TmpBinDir, "Dockerfile"))
+	if os.IsNotExist(err) {
+		return images, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	from, err := baseImages(f)
+	if err != nil {
+		return nil, err
+	}
+	for _, img := range from {
+		if !sliceutil.Contains(images, img) {
+			images = append(images, img)
+		}
+	}
+	return images, nil
+}
+
+// pulled reads the images pulled by rit, an empty set before the first pull
+func (d DockerCache) pulled() (map[string]bool, error) {
+	pulled := map[string]bool{}
+	if !fileutil.Exists(d.file) {
+		return pulled, nil
+	}
+
+	b, err := fileutil.ReadFile(d.file)
+	if err != nil {
+		return nil, err
+	}
+	var images []string
+	if err := json.Unmarshal(b, &images); err != nil {
+		return nil, err
+	}
+	for _, img := range images {
+		pulled[img] = true
+	}
+	return pulled, nil
+}
+
+func (d DockerCache) savePulled(pulled map[string]bool) error {
+	images := make([]string, 0, len(pulled))
+	for img := range pulled {
+		images = append(images, img)
+	}
+	sort.Strings(images)
+
+	b, err := json.MarshalIndent(images, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := fileutil.CreateDirIfNotExists(filepath.Dir(d.file), 0755); err != nil {
+		return err
+	}
+	return fileutil.WriteFile(d.file, b)
+}
@@ -0,0 +1,162 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+// setuperMock sets up the formulas on the bin dirs of their paths, a path without a dir fails
+type setuperMock struct {
+	bins map[string]string
+}
+
+func (s setuperMock) Setup(def formula.Definition) (formula.Setup, error) {
+	bin, ok := s.bins[def.Path]
+	if !ok {
+		return formula.Setup{}, errors.New("bundle not found")
+	}
+
+	setup := formula.Setup{TmpBinDir: bin}
+	if b, err := ioutil.ReadFile(filepath.Join(bin, "config.json")); err == nil {
+		return setup, json.Unmarshal(b, &setup.Config)
+	}
+	return setup, nil
+}
+
+func TestDockerCache(t *testing.T) {
+	defer func(l func(string) (string, error), i func(string) error, o func(string, ...string) (string, error)) {
+		lookPath, dockerInfo, dockerOutput = l, i, o
+	}(lookPath, dockerInfo, dockerOutput)
+	lookPath = func(string) (string, error) { return "docker", nil }
+	dockerInfo = func(string) error { return nil }
+
+	home, err := ioutil.TempDir("", "rit-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	bins := map[string]string{}
+	formulas := map[string]map[string]string{
+		"go":    {"Dockerfile": "FROM golang:1.14 AS builder\nFROM alpine:3.12\n"},
+		"shell": {"Dockerfile": "FROM alpine:3.12\n", "config.json": `{"dockerImageBuilder":"ritclizup/rit-shell-bat-builder"}`},
+		"local": {"config.json": `{}`},
+		"fails": {"Dockerfile": "FROM node:14\n"},
+	}
+	for name, files := range formulas {
+		bins[name] = filepath.Join(home, "formulas", name)
+		if err := os.MkdirAll(bins[name], os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		for f, content := range files {
+			if err := ioutil.WriteFile(filepath.Join(bins[name], f), []byte(content), os.ModePerm); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	defs := []formula.Definition{
+		{Command: "rit go", Path: "go"},
+		{Command: "rit shell", Path: "shell"},
+		{Command: "rit local", Path: "local"},
+		{Command: "rit fails", Path: "fails"},
+		{Command: "rit missing", Path: "missing"},
+	}
+
+	// the pulls run in parallel
+	var mu sync.Mutex
+	images, containers := "", ""
+	dockerOutput = func(_ string, args ...string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case args[0] == dockerPullCmd && args[2] == "node:14":
+			return "", errors.New("manifest unknown")
+		case args[0] == "images":
+			return images, nil
+		case args[0] == "ps":
+			return containers, nil
+		case args[0] == dockerRemoveImageCmd && args[1] == "in-use":
+			return "", errors.New("image is being used")
+		}
+		return "", nil
+	}
+
+	cache := NewDockerCache(setuperMock{bins}, postRunnerMock{}, home, EngineDocker)
+	pulls, err := cache.PullAll(defs, 2)
+	if err != nil {
+		t.Fatalf("PullAll() got error %v", err)
+	}
+
+	var got []string
+	for _, p := range pulls {
+		pull := p.Image + " " + strings.Join(p.Formulas, ",")
+		if p.Err != nil {
+			pull += " failed"
+		}
+		got = append(got, pull)
+	}
+	want := []string{
+		"alpine:3.12 rit go,rit shell",
+		"golang:1.14 rit go",
+		"node:14 rit fails failed",
+		"ritclizup/rit-shell-bat-builder rit shell",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PullAll() got %q, want %q", got, want)
+	}
+
+	t.Run("unused images", func(t *testing.T) {
+		images = "3f9b7a2c-1d2e-4f5a-8b6c-7d8e9f0a1b2c\nbuilt-by-me\n0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+		containers = "0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+
+		// the go formula was removed, alpine is still used by shell
+		got, err := cache.Unused(defs[1:4])
+		if err != nil {
+			t.Fatalf("Unused() got error %v", err)
+		}
+		want := []string{"3f9b7a2c-1d2e-4f5a-8b6c-7d8e9f0a1b2c", "golang:1.14"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Unused() got %v, want %v", got, want)
+		}
+
+		// the images of the missing formula are unknown, so the pulled ones are kept
+		got, err = cache.Unused(defs[1:])
+		want = []string{"3f9b7a2c-1d2e-4f5a-8b6c-7d8e9f0a1b2c"}
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("Unused() with a formula not set up got %v, %v, want %v", got, err, want)
+		}
+	})
+
+	t.Run("remove forgets the removed images", func(t *testing.T) {
+		if err := cache.Remove([]string{"golang:1.14", "in-use"}); err == nil || !strings.Contains(err.Error(), "in-use") {
+			t.Errorf("Remove() got %v, want the error of in-use", err)
+		}
+
+		pulled, err := cache.pulled()
+		if err != nil {
+			t.Fatalf("pulled() got error %v", err)
+		}
+		want := map[string]bool{"alpine:3.12": true, "ritclizup/rit-shell-bat-builder": true}
+		if !reflect.DeepEqual(pulled, want) {
+			t.Errorf("pulled() got %v, want %v", pulled, want)
+		}
+	})
+
+	t.Run("docker not running", func(t *testing.T) {
+		dockerInfo = func(string) error { return errors.New("cannot connect") }
+		defer func() { dockerInfo = func(string) error { return nil } }()
+
+		if _, err := cache.PullAll(defs, 1); err != ErrDockerDaemonNotRunning {
+			t.Errorf("PullAll() got %v, want ErrDockerDaemonNotRunning", err)
+		}
+	})
+}
@@ -13,7 +13,16 @@ import (
 )
 
 var ErrNotEnableDocker = prompt.NewError("this formula is not enabled to run in a container")
-var ErrDockerNotFound =  prompt.NewError("you must have the docker installed on the machine to run formulas inside a container")
+var ErrDockerNotFound = prompt.NewError("you must have the docker installed on the machine to run formulas inside a container")
+var ErrDockerDaemonNotRunning = prompt.NewError("Docker is installed but the daemon isn't running, start Docker (or Docker Desktop) and try again")
+
+var (
+	// lookPath and dockerInfo are vars so the docker probe can be replaced on tests
+	lookPath   = exec.LookPath
+	dockerInfo = func(engine string) error {
+		return exec.Command(engine, "info").Run()
+	}
+)
 
 type DockerPreRunner struct {
 	sDefault formula.Setuper
@@ -23,37 +32,59 @@ func NewDockerPreRunner(setuper formula.Setuper) DockerPreRunner {
 	return DockerPreRunner{sDefault: setuper}
 }
 
-func (d DockerPreRunner) PreRun(def formula.Definition) (formula.Setup, error) {
+func (d DockerPreRunner) PreRun(def formula.Definition) (_ formula.Setup, err error) {
+	if err := CheckDocker(Engine(def.Runner)); err != nil {
+		return formula.Setup{}, err
+	}
+
 	setup, err := d.sDefault.Setup(def)
 	if err != nil {
 		return formula.Setup{}, err
 	}
+	// the runners only clean up the workspace of the formulas set up, e.g. not on a failed build
+	defer cleanFailed(setup, false, &err)
 
 	if err := validate(setup.TmpBinDir); err != nil {
 		return formula.Setup{}, err
 	}
 
-	containerId, err := uuid.NewRandom()
-	if err != nil {
-		return formula.Setup{}, err
+	// the image of a running session is reused, it is built again once the session stops
+	if def.Session {
+		setup.ContainerId = SessionName(def)
+		if _, running := sessionState(setup.Engine, setup.ContainerId); running {
+			return setup, nil
+		}
+	} else {
+		containerId, err := uuid.NewRandom()
+		if err != nil {
+			return formula.Setup{}, err
+		}
+		setup.ContainerId = containerId.String()
 	}
 
-	setup.ContainerId = containerId.String()
-	if err := buildImg(setup.ContainerId); err != nil {
+	if err := buildImg(setup.Engine, setup.ContainerId, imageBuildArgs(setup.Config)); err != nil {
 		return formula.Setup{}, err
 	}
 
 	return setup, nil
 }
 
-func validate(tmpBinDir string) error {
-	args := []string{"version", "--format", "'{{.Server.Version}}'"}
-	cmd := exec.Command("docker", args...)
-	output, err := cmd.CombinedOutput()
-	if output == nil || err != nil {
-		return ErrDockerNotFound
+// CheckDocker probes the installation of the container engine, it returns ErrDockerNotFound
+// when the docker binary is not installed and ErrDockerDaemonNotRunning when the binary
+// exists but the daemon does not answer, or ErrPodmanNotFound and ErrPodmanNotRunning
+func CheckDocker(engine string) error {
+	if _, err := lookPath(engine); err != nil {
+		return errEngineNotFound[engine]
+	}
+
+	if err := dockerInfo(engine); err != nil {
+		return errEngineNotRunning[engine]
 	}
 
+	return nil
+}
+
+func validate(tmpBinDir string) error {
 	dockerFile := fmt.Sprintf("%s/Dockerfile", tmpBinDir)
 	if !fileutil.Exists(dockerFile) {
 		return ErrNotEnableDocker
@@ -62,10 +93,10 @@ func validate(tmpBinDir string) error {
 	return nil
 }
 
-func buildImg(containerId string) error {
-	fmt.Println("Building docker image...")
-	args := []string{dockerBuildCmd, "-t", containerId, "."}
-	cmd := exec.Command(docker, args...) // Run command "docker build -t (randomId) ."
+func buildImg(engine, containerId string, buildArgs []string) error {
+	prompt.Print("Building docker image...")
+	args := append(append([]string{dockerBuildCmd}, buildArgs...), "-t", containerId, ".")
+	cmd := exec.Command(engine, args...) // Run command "docker build -t (randomId) ."
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Start(); err != nil {
@@ -76,6 +107,6 @@ func buildImg(containerId string) error {
 		return err
 	}
 
-	fmt.Println("Docker image was built :)")
+	prompt.Print("Docker image was built :)")
 	return nil
 }
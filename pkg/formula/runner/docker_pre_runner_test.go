@@ -0,0 +1,95 @@
+package runner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestCheckDocker(t *testing.T) {
+	defer func(l func(string) (string, error), i func(string) error) {
+		lookPath, dockerInfo = l, i
+	}(lookPath, dockerInfo)
+
+	tests := []struct {
+		name     string
+		engine   string
+		lookPath func(string) (string, error)
+		info     func(string) error
+		want     error
+	}{
+		{
+			name:     "docker not installed",
+			lookPath: func(string) (string, error) { return "", errors.New("not found") },
+			info:     func(string) error { return nil },
+			want:     ErrDockerNotFound,
+		},
+		{
+			name:     "docker daemon not running",
+			lookPath: func(string) (string, error) { return "/usr/bin/docker", nil },
+			info:     func(string) error { return errors.New("cannot connect to the docker daemon") },
+			want:     ErrDockerDaemonNotRunning,
+		},
+		{
+			name:     "docker running",
+			lookPath: func(string) (string, error) { return "/usr/bin/docker", nil },
+			info:     func(string) error { return nil },
+			want:     nil,
+		},
+		{
+			name:   "podman not installed",
+			engine: EnginePodman,
+			lookPath: func(file string) (string, error) {
+				if file != EnginePodman {
+					return "/usr/bin/" + file, nil
+				}
+				return "", errors.New("not found")
+			},
+			info: func(string) error { return nil },
+			want: ErrPodmanNotFound,
+		},
+		{
+			name:     "podman unable to run containers",
+			engine:   EnginePodman,
+			lookPath: func(string) (string, error) { return "/usr/bin/podman", nil },
+			info:     func(string) error { return errors.New("cannot connect to the podman machine") },
+			want:     ErrPodmanNotRunning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookPath, dockerInfo = tt.lookPath, tt.info
+			if got := CheckDocker(Engine(tt.engine)); got != tt.want {
+				t.Errorf("CheckDocker(%s) got %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngine(t *testing.T) {
+	defer delete(plugins, "nomad")
+	plugins["nomad"] = func(Parts) formula.Runner { return nil }
+
+	tests := []struct {
+		runner     string
+		wantEngine string
+		wantErr    error
+	}{
+		{runner: "", wantEngine: EngineDocker},
+		{runner: EngineDocker, wantEngine: EngineDocker},
+		{runner: EnginePodman, wantEngine: EnginePodman},
+		{runner: "nomad", wantEngine: EngineDocker},
+		{runner: "containerd", wantEngine: EngineDocker, wantErr: ErrInvalidEngine},
+	}
+
+	for _, tt := range tests {
+		if got := Engine(tt.runner); got != tt.wantEngine {
+			t.Errorf("Engine(%q) got %s, want %s", tt.runner, got, tt.wantEngine)
+		}
+		if err := ValidRunner(tt.runner); err != tt.wantErr {
+			t.Errorf("ValidRunner(%q) got %v, want %v", tt.runner, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package runner
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	dockerPullCmd  = "pull"
+	msgPulling     = "Pulling docker image %s..."
+	msgPullFailed  = "unable to pull the docker image %q: %v"
+	msgNoImages    = "the formula Dockerfile has no image to pull"
+	msgPulledImage = "The docker images of the formula were pulled, the next runs will start faster"
+)
+
+// dockerPull is a var so the docker pull can be replaced on tests
+var dockerPull = func(engine, image string) error {
+	cmd := exec.Command(engine, dockerPullCmd, image) // Run command "docker pull (image)"
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+type DockerPuller struct {
+	formula.Setuper
+	formula.PostRunner
+}
+
+func NewDockerPuller(setuper formula.Setuper, postRunner formula.PostRunner) DockerPuller {
+	return DockerPuller{setuper, postRunner}
+}
+
+// Pull pulls the images the formula Dockerfile is built from, without running
+// the formula, so the docker runs don't need to download them later
+func (d DockerPuller) Pull(def formula.Definition) error {
+	if err := CheckDocker(Engine(def.Runner)); err != nil {
+		return err
+	}
+
+	setup, err := d.Setup(def)
+	if err != nil {
+		return err
+	}
+	defer d.PostRun(setup, false)
+
+	if err := validate(setup.TmpBinDir); err != nil {
+		return err
+	}
+
+	f, err := os.Open(fmt.Sprintf("%s/Dockerfile", setup.TmpBinDir))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	images, err := baseImages(f)
+	if err != nil {
+		return err
+	}
+	if img := archImage(setup.Config); img != "" {
+		images = append([]string{img}, images...)
+	}
+	if len(images) == 0 {
+		return prompt.NewError(msgNoImages)
+	}
+
+	for _, img := range images {
+		prompt.Info(fmt.Sprintf(msgPulling, img))
+		if err := dockerPull(Engine(def.Runner), img); err != nil {
+			return prompt.NewError(fmt.Sprintf(msgPullFailed, img, err))
+		}
+	}
+
+	prompt.Success(msgPulledImage)
+	return nil
+}
+
+// baseImages reads the images of the Dockerfile FROM instructions, skipping
+// scratch, the previous build stages and the images built from ARG values
+func baseImages(dockerfile io.Reader) ([]string, error) {
+	stages := map[string]bool{"scratch": true}
+	pulled := make(map[string]bool)
+	var images []string
+
+	scanner := bufio.NewScanner(dockerfile)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+
+		args := fields[1:]
+		for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		img := args[0]
+		if !stages[strings.ToLower(img)] && !strings.Contains(img, "$") && !pulled[img] {
+			images = append(images, img)
+			pulled[img] = true
+		}
+
+		if len(args) == 3 && strings.EqualFold(args[1], "AS") {
+			stages[strings.ToLower(args[2])] = true
+		}
+	}
+
+	return images, scanner.Err()
+}
@@ -0,0 +1,30 @@
+package runner
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBaseImages(t *testing.T) {
+	dockerfile := `FROM golang:1.14 AS builder
+WORKDIR /app
+FROM --platform=linux/amd64 alpine:3.12
+COPY --from=builder /app/bin /bin
+from ubuntu:20.04 as tools
+FROM tools
+FROM scratch
+FROM ${BASE_IMAGE}
+FROM alpine:3.12
+`
+
+	got, err := baseImages(strings.NewReader(dockerfile))
+	if err != nil {
+		t.Fatalf("baseImages() got error %v", err)
+	}
+
+	want := []string{"golang:1.14", "alpine:3.12", "ubuntu:20.04"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("baseImages() got %v, want %v", got, want)
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 
 	"github.com/mattn/go-isatty"
 
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runlog"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
 	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
 
 	"github.com/ZupIT/ritchie-cli/pkg/api"
@@ -15,12 +18,12 @@ import (
 )
 
 const (
-	docker          = "docker"
-	dockerBuildCmd  = "build"
-	dockerRunCmd    = "run"
-	dockerRemoveCmd = "rm"
-	envFile         = ".env"
-	isDocker        = true
+	dockerBuildCmd       = "build"
+	dockerRunCmd         = "run"
+	dockerRemoveCmd      = "rm"
+	dockerRemoveImageCmd = "rmi"
+	envFile              = ".env"
+	isDocker             = true
 )
 
 type DockerRunner struct {
@@ -28,40 +31,137 @@ type DockerRunner struct {
 	formula.PostRunner
 	formula.InputRunner
 	ctxFinder rcontext.Finder
+	logs      runlog.Creator
+	// volumes are the host paths the volumes of the formula containers must be on
+	volumes []string
 }
 
-func NewDockerRunner(preRunner formula.PreRunner, postRunner formula.PostRunner, inputRunner formula.InputRunner, ctxFinder rcontext.Finder) DockerRunner {
-	return DockerRunner{preRunner, postRunner, inputRunner, ctxFinder}
+// NewDockerRunner creates the runner of the formula containers, they mount no host path out of
+// the allowed volumes, the formula.volumes config
+func NewDockerRunner(preRunner formula.PreRunner, postRunner formula.PostRunner, inputRunner formula.InputRunner, ctxFinder rcontext.Finder, logs runlog.Creator, volumes []string) DockerRunner {
+	return DockerRunner{preRunner, postRunner, inputRunner, ctxFinder, logs, volumes}
 }
 
-func (d DockerRunner) Run(def formula.Definition, inputType api.TermInputType, verboseFlag string) error {
+func (d DockerRunner) Run(def formula.Definition, inputType api.TermInputType, verboseFlag string) (err error) {
+	p := newProfile(def)
+	defer p.print(def.ProfileJSON)
+
 	setup, err := d.PreRun(def)
 	if err != nil {
 		return err
 	}
+	defer cleanFailed(setup, !def.Session, &err)
+
+	o, err := isolate(def, &setup)
+	if err != nil {
+		return err
+	}
+	defer o.remove()
+	p.phase(phaseSetup)
 
 	volume := fmt.Sprintf("%s:/app", setup.Pwd)
+	tty := isatty.IsTerminal(os.Stdout.Fd())
+
+	entrypoint, err := entrypointArgs(def, setup)
+	if err != nil {
+		return err
+	}
+
+	// a session container keeps the volumes it started with, its runs don't collect outputs
+	var outputs string
+	if !def.Session {
+		if outputs, err = outputsDir(def, setup); err != nil {
+			return err
+		}
+	}
+
+	// a session container outlives the run, which removes the sidecars
+	if err := validateSidecars(setup.Config.Sidecars); err != nil {
+		return err
+	} else if def.Session && len(setup.Config.Sidecars) > 0 {
+		return prompt.NewError(msgSidecarSession)
+	}
+	// the files of the editor inputs are mounted on the docker run, a session container
+	// keeps the volumes it started with
+	if def.Session {
+		if err := checkEditorInputs(setup, "--session"); err != nil {
+			return err
+		}
+	}
+
+	limits, err := resources(def, setup)
+	if err != nil {
+		return err
+	}
+
+	volumes, err := volumeArgs(def, setup, d.volumes)
+	if err != nil {
+		return err
+	}
 
 	var args []string
-	if isatty.IsTerminal(os.Stdout.Fd()) {
-		args = []string{dockerRunCmd, "-it", "--env-file", envFile, "-v", volume, "--name", setup.ContainerId, setup.ContainerId}
+	if def.Session {
+		if args, err = sessionArgs(setup, tty, append(resourceArgs(limits), volumes...)); err != nil {
+			return err
+		}
 	} else {
-		args = []string{dockerRunCmd, "--env-file", envFile, "-v", volume, "--name", setup.ContainerId, setup.ContainerId}
+		args = []string{dockerRunCmd}
+		if tty {
+			args = append(args, "-it")
+		}
+		args = append(args, "--env-file", envFile, "-v", volume)
+		args = append(args, resourceArgs(limits)...)
+		args = append(args, volumes...)
+		if outputs != "" {
+			args = append(args, "-v", outputs+":"+containerOutputsDir)
+		}
+		if network := sidecarNetwork(setup); network != "" {
+			args = append(args, "--network", network)
+		}
+		args = append(args, "--name", setup.ContainerId)
+		args = append(append(args, entrypoint...), setup.ContainerId)
 	}
 
-	cmd := exec.Command(docker, args...) // Run command "docker run -env-file .env -v "$(pwd):/app" --name (randomId) (randomId)"
+	cmd := exec.Command(setup.Engine, args...) // Run command "docker run -env-file .env -v "$(pwd):/app" --name (randomId) (randomId)"
 	cmd.Env = os.Environ()
+	local := len(cmd.Env)
 
 	verboseEnv := fmt.Sprintf(formula.EnvPattern, formula.VerboseEnv, verboseFlag)
 	cmd.Env = append(cmd.Env, verboseEnv)
+	// the args are not appended to docker run, they would replace the CMD of the formula image
+	addArgsEnv(cmd, def.Args)
+	cmd.Env = append(cmd.Env, def.Env...)
+	if outputs != "" {
+		cmd.Env = append(cmd.Env, outputsEnv(path.Join(containerOutputsDir, outputsFile)))
+	}
 
 	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
+	if def.Stdin != nil {
+		cmd.Stdin = def.Stdin
+	}
+	cmd.Stdout = formulaStdout(def)
 	cmd.Stderr = os.Stderr
 
 	if err := d.Inputs(cmd, setup, inputType); err != nil {
 		return err
 	}
+	keepInputs(def, cmd.Env, setup.Config.Inputs)
+	if !def.Session {
+		insertArgs(cmd, dockerNameArg, pathMounts(cmd, setup))
+	}
+	p.phase(phaseInputs)
+
+	ctx, err := d.ctxFinder.Find()
+	if err != nil {
+		return err
+	}
+	contextEnv := "CONTEXT=" + ctx.Current
+
+	// the env of rit reaches the container too, only the env of the run is printed
+	if def.DryRun {
+		dryRun(def, cmd.Args, nil, append(cmd.Env[local:], contextEnv), setup, !def.Session)
+		return nil
+	}
 
 	for _, e := range cmd.Env { // Create a file named .env and add the environment variable inName=inValue
 		if !fileutil.Exists(envFile) {
@@ -75,26 +175,50 @@ func (d DockerRunner) Run(def formula.Definition, inputType api.TermInputType, v
 		}
 	}
 
-	ctx, err := d.ctxFinder.Find()
-	if err != nil {
+	if err := fileutil.AppendFileData(envFile, []byte(contextEnv+"\n")); err != nil {
 		return err
 	}
 
-	if err := fileutil.AppendFileData(envFile, []byte("CONTEXT="+ctx.Current+"\n")); err != nil {
+	removeSidecars, err := startSidecars(setup)
+	if err != nil {
 		return err
 	}
-
-	if err := cmd.Start(); err != nil {
+	defer removeSidecars()
+
+	// a session is stopped as a whole, the next run starts it again
+	stop := stopContainer(setup.Engine, setup.ContainerId)
+	err = runHooked(def, setup, func() error {
+		return runLogged(cmd, d.logs, def, setup.Config.Inputs, stop)
+	})
+	p.phase(phaseExecution)
+	p.process(cmd.ProcessState, isDocker)
+	if err != nil {
+		if !def.Session {
+			warnOOMKilled(def, setup.ContainerId, limits)
+		}
 		return err
 	}
 
-	if err := cmd.Wait(); err != nil {
+	// the session container is kept for the next runs, only the env file is removed
+	if def.Session {
+		if err := fileutil.RemoveFile(envFile); err != nil {
+			return err
+		}
+		if err := d.PostRun(setup, false); err != nil {
+			return err
+		}
+		p.phase(phasePostRun)
+		return o.commit()
+	}
+
+	if err := printOutputs(def, setup, outputs); err != nil {
 		return err
 	}
 
 	if err := d.PostRun(setup, isDocker); err != nil {
 		return err
 	}
+	p.phase(phasePostRun)
 
-	return nil
+	return o.commit()
 }
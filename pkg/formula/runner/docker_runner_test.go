@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runlog"
 	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
 
 	"github.com/ZupIT/ritchie-cli/pkg/api"
@@ -107,8 +108,8 @@ func TestDockerRunner_Run(t *testing.T) {
 			}
 
 			resolvers := env.Resolvers{"test": in.envMock}
-			inputManager := NewInputManager(resolvers, in.inText, in.inText, in.inBool, in.inPassword)
-			dockerRunner := NewDockerRunner(preRunner, postRunner, inputManager, ctxFinder)
+			inputManager := NewInputManager(resolvers, in.inText, in.inText, in.inText, inputMock{}, in.inText, in.inBool, in.inPassword, false)
+			dockerRunner := NewDockerRunner(preRunner, postRunner, inputManager, ctxFinder, runlog.NewManager(home, true), nil)
 
 			got := dockerRunner.Run(def, api.Prompt, verboseFlag)
 
@@ -0,0 +1,135 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	dockerExecCmd    = "exec"
+	dockerInspectCmd = "inspect"
+	// the session container and the formula image have the same name, docker inspect
+	// of the name alone matches the container first
+	dockerContainerCmd = "container"
+	dockerImageCmd     = "image"
+	sessionPrefix      = "rit-session-"
+	sessionPwdLabel    = "rit.session.pwd"
+	msgSessionStart    = "Starting the session container %s, stop it with --session-stop"
+	// SessionTTL is how long a session container lives after it starts. The container
+	// only sleeps between the runs, it holds the memory of the formula image processes
+	// left behind by the runs and is removed by docker when the TTL ends.
+	SessionTTL = time.Hour
+)
+
+var (
+	ErrNoSession      = prompt.NewError("there is no session running for this formula")
+	ErrNoImageCommand = prompt.NewError("the formula image has no command to run in the session")
+	invalidNameChars  = regexp.MustCompile(`[^a-z0-9_.-]+`)
+)
+
+// dockerOutput runs the container engine with the args, it is a var so the docker calls of
+// the sessions can be replaced on tests
+var dockerOutput = func(engine string, args ...string) (string, error) {
+	out, err := exec.Command(engine, args...).Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// SessionName returns the name of the session container and image of the formula
+func SessionName(def formula.Definition) string {
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(def.Path), "-")
+	return sessionPrefix + strings.Trim(name, "-.")
+}
+
+// sessionState returns the pwd mounted on the session container and whether it is running
+func sessionState(engine, name string) (string, bool) {
+	format := fmt.Sprintf("{{.State.Running}} {{index .Config.Labels %q}}", sessionPwdLabel)
+	out, err := dockerOutput(engine, dockerContainerCmd, dockerInspectCmd, "-f", format, name)
+	if err != nil {
+		return "", false
+	}
+
+	state := strings.SplitN(out, " ", 2)
+	if len(state) < 2 {
+		return "", state[0] == "true"
+	}
+	return state[1], state[0] == "true"
+}
+
+// sessionArgs returns the docker exec args running the formula on its session container,
+// the container is (re)started when it isn't running or has another pwd mounted
+func sessionArgs(setup formula.Setup, tty bool, runArgs []string) ([]string, error) {
+	name := setup.ContainerId
+	if pwd, running := sessionState(setup.Engine, name); !running || pwd != setup.Pwd {
+		if err := startSession(setup.Engine, name, setup.Pwd, runArgs); err != nil {
+			return nil, err
+		}
+	}
+
+	command, err := imageCommand(setup.Engine, name)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{dockerExecCmd}
+	if tty {
+		args = append(args, "-it")
+	}
+	args = append(args, "--env-file", envFile, name)
+	return append(args, command...), nil
+}
+
+// startSession runs the session container of the image, it only sleeps until the formula
+// is executed on it by docker exec. The limits of the resources and the volumes of runArgs
+// last while it runs.
+func startSession(engine, name, pwd string, runArgs []string) error {
+	_, _ = dockerOutput(engine, dockerRemoveCmd, "-f", name)
+
+	prompt.Info(fmt.Sprintf(msgSessionStart, name))
+	ttl := strconv.Itoa(int(SessionTTL.Seconds()))
+	volume := fmt.Sprintf("%s:/app", pwd)
+	args := []string{dockerRunCmd, "-d", "--rm", "--name", name, "--label", sessionPwdLabel + "=" + pwd, "-v", volume}
+	args = append(append(args, runArgs...), "--entrypoint", "sleep", name, ttl)
+	_, err := dockerOutput(engine, args...)
+	return err
+}
+
+// imageCommand returns the entrypoint and cmd of the formula image, the command docker run would execute
+func imageCommand(engine, image string) ([]string, error) {
+	out, err := dockerOutput(engine, dockerImageCmd, dockerInspectCmd, "-f", "{{json .Config}}", image)
+	if err != nil {
+		return nil, err
+	}
+
+	var config struct {
+		Entrypoint []string
+		Cmd        []string
+	}
+	if err := json.Unmarshal([]byte(out), &config); err != nil {
+		return nil, err
+	}
+
+	command := append(config.Entrypoint, config.Cmd...)
+	if len(command) == 0 {
+		return nil, ErrNoImageCommand
+	}
+	return command, nil
+}
+
+// StopSession removes the session container of the formula and its image
+func (d DockerRunner) StopSession(def formula.Definition) error {
+	name, engine := SessionName(def), Engine(def.Runner)
+	_, containerErr := dockerOutput(engine, dockerRemoveCmd, "-f", name)
+	_, imageErr := dockerOutput(engine, dockerRemoveImageCmd, name)
+	if containerErr != nil && imageErr != nil {
+		return ErrNoSession
+	}
+	return nil
+}
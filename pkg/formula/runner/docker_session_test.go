@@ -0,0 +1,103 @@
+package runner
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestSessionName(t *testing.T) {
+	got := SessionName(formula.Definition{Path: "AWS/create_bucket v2"})
+	if got != "rit-session-aws-create_bucket-v2" {
+		t.Errorf("SessionName got %q", got)
+	}
+}
+
+func TestSessionArgs(t *testing.T) {
+	defer func(f func(string, ...string) (string, error)) { dockerOutput = f }(dockerOutput)
+
+	const config = `{"Entrypoint":["/bin/sh","-c"],"Cmd":["./run.sh"]}`
+	tests := []struct {
+		name      string
+		state     string
+		tty       bool
+		wantStart bool
+		wantArgs  []string
+	}{
+		{
+			name:     "reuses the running session",
+			state:    "true /home/dev",
+			wantArgs: []string{"exec", "--env-file", ".env", "rit-session-mock", "/bin/sh", "-c", "./run.sh"},
+		},
+		{
+			name:      "starts the stopped session",
+			state:     "",
+			tty:       true,
+			wantStart: true,
+			wantArgs:  []string{"exec", "-it", "--env-file", ".env", "rit-session-mock", "/bin/sh", "-c", "./run.sh"},
+		},
+		{
+			name:      "restarts the session mounting another pwd",
+			state:     "true /home/other",
+			wantStart: true,
+			wantArgs:  []string{"exec", "--env-file", ".env", "rit-session-mock", "/bin/sh", "-c", "./run.sh"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started := false
+			dockerOutput = func(_ string, args ...string) (string, error) {
+				switch {
+				case args[0] == dockerRunCmd:
+					started = true
+					return "", nil
+				case args[0] == dockerContainerCmd && args[1] == dockerInspectCmd:
+					if tt.state == "" {
+						return "", errors.New("no such container")
+					}
+					return tt.state, nil
+				case args[0] == dockerImageCmd && args[1] == dockerInspectCmd:
+					return config, nil
+				}
+				return "", nil
+			}
+
+			setup := formula.Setup{Pwd: "/home/dev", ContainerId: "rit-session-mock", Engine: EngineDocker}
+			got, err := sessionArgs(setup, tt.tty, nil)
+			if err != nil {
+				t.Fatalf("sessionArgs got %v, want nil", err)
+			}
+			if started != tt.wantStart || !reflect.DeepEqual(got, tt.wantArgs) {
+				t.Errorf("sessionArgs got started %v and %q, want %v and %q", started, got, tt.wantStart, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestStopSession(t *testing.T) {
+	defer func(f func(string, ...string) (string, error)) { dockerOutput = f }(dockerOutput)
+
+	var removed []string
+	dockerOutput = func(engine string, args ...string) (string, error) {
+		removed = append(removed, engine+" "+strings.Join(args, " "))
+		return "", nil
+	}
+	if err := (DockerRunner{}).StopSession(formula.Definition{Path: "mock/test", Runner: EnginePodman}); err != nil {
+		t.Fatalf("StopSession got %v, want nil", err)
+	}
+	want := []string{"podman rm -f rit-session-mock-test", "podman rmi rit-session-mock-test"}
+	if !reflect.DeepEqual(removed, want) {
+		t.Errorf("StopSession got %v, want %v", removed, want)
+	}
+
+	dockerOutput = func(_ string, args ...string) (string, error) {
+		return "", errors.New("no such object")
+	}
+	if err := (DockerRunner{}).StopSession(formula.Definition{Path: "mock/test"}); err != ErrNoSession {
+		t.Errorf("StopSession got %v, want ErrNoSession", err)
+	}
+}
@@ -0,0 +1,41 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/redact"
+)
+
+const (
+	msgDryRun    = "Dry run of %s, the formula is not executed"
+	msgDryRunEnv = "Env besides the one of rit:"
+)
+
+// dryRunWriter is where --dry-run prints the run, it is a var so the tests read it
+var dryRunWriter io.Writer = os.Stdout
+
+// dryRun prints the run the formula would execute, as --dry-run does, and removes its temp
+// workspace with, when image is set, the image built for it. The command is followed by
+// the lines of the runner, e.g. the job of a kubernetes run, and the env the inputs,
+// credentials and args add to the formula, the secrets masked.
+func dryRun(def formula.Definition, args []string, lines []string, env []string, setup formula.Setup, image bool) {
+	r := redact.New(secretValues(env, setup.Config.Inputs)...)
+	fmt.Fprintf(dryRunWriter, msgDryRun+"\n", def.Command)
+	fmt.Fprintln(dryRunWriter, "+ "+commandLine(args, r))
+	for _, l := range lines {
+		fmt.Fprintln(dryRunWriter, l)
+	}
+
+	fmt.Fprintln(dryRunWriter, msgDryRunEnv)
+	for _, e := range r.Args(env) {
+		fmt.Fprintln(dryRunWriter, "  "+e)
+	}
+
+	if image {
+		_, _ = dockerOutput(setup.Engine, dockerRemoveImageCmd, setup.ContainerId)
+	}
+	removeWorkDir(setup.TmpDir)
+}
@@ -0,0 +1,45 @@
+package runner
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestDryRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-dry-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var out bytes.Buffer
+	dryRunWriter = &out
+	defer func() { dryRunWriter = os.Stdout }()
+
+	def := formula.Definition{Command: "rit aws create"}
+	setup := formula.Setup{
+		TmpDir: dir,
+		Config: formula.Config{Inputs: []formula.Input{{Name: "pass", Type: "password"}, {Name: "region", Type: "text"}}},
+	}
+	env := []string{"REGION=sa-east-1", "PASS=hunter2", "AWS_SECRET_ACCESS_KEY=abc123"}
+	args := []string{"docker", "run", "--env-file", ".env", "-v", "/home/ops:/app", "--name", "0f3c", "0f3c"}
+	dryRun(def, args, []string{"after the setup"}, env, setup, false)
+
+	want := "Dry run of rit aws create, the formula is not executed\n" +
+		"+ docker run --env-file .env -v /home/ops:/app --name 0f3c 0f3c\n" +
+		"after the setup\n" +
+		"Env besides the one of rit:\n" +
+		"  REGION=sa-east-1\n" +
+		"  PASS=******\n" +
+		"  AWS_SECRET_ACCESS_KEY=******\n"
+	if out.String() != want {
+		t.Errorf("dryRun printed %q, want %q", out.String(), want)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("the workspace of the dry run is kept, stat got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package runner
+
+import (
+	"errors"
+
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	// EngineDocker and EnginePodman are the container engines running the formulas with a
+	// Dockerfile, podman takes the same commands and flags as docker and needs no daemon
+	EngineDocker = "docker"
+	EnginePodman = "podman"
+)
+
+var (
+	// Engines are the container engines a formula runs on
+	Engines = []string{EngineDocker, EnginePodman}
+
+	ErrInvalidEngine    = errors.New("the formula runner must be docker, podman or the name of a runner plugin")
+	ErrPodmanNotFound   = prompt.NewError("you must have podman installed on the machine to run formulas inside a container with it")
+	ErrPodmanNotRunning = prompt.NewError("podman is installed but unable to run containers, check podman info, e.g. start the podman machine")
+	errEngineNotFound   = map[string]error{EngineDocker: ErrDockerNotFound, EnginePodman: ErrPodmanNotFound}
+	errEngineNotRunning = map[string]error{EngineDocker: ErrDockerDaemonNotRunning, EnginePodman: ErrPodmanNotRunning}
+)
+
+// Engine returns the container engine of the runner of a formula run, the runner itself when it
+// is docker or podman. An empty runner and the runner plugins, whose sessions and pulls still
+// use a container, are docker.
+func Engine(runner string) string {
+	if runner == EnginePodman {
+		return EnginePodman
+	}
+	return EngineDocker
+}
+
+// ValidRunner returns ErrInvalidEngine unless the runner is empty, a container engine or the
+// name of a registered runner plugin
+func ValidRunner(runner string) error {
+	if runner == "" || runner == EngineDocker || runner == EnginePodman || plugins[runner] != nil {
+		return nil
+	}
+	return ErrInvalidEngine
+}
@@ -0,0 +1,126 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/os/osutil"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	hookPreRun     = "preRun"
+	hookPostRun    = "postRun"
+	msgRunningHook = "Running the %s hook %s..."
+	msgHookIgnored = "The %s hook %s failed, continuing: %v"
+	msgHookFailed  = "the %s hook %s failed: %w"
+	msgHookInvalid = "the %s hook %d of config.json needs either a formula or a script, and an onFailure of fail or continue"
+)
+
+// hookRun runs the command of a hook, it is a var so the tests replace the hook runs
+var hookRun = func(c *exec.Cmd) error {
+	return c.Run()
+}
+
+// validateHooks checks the hooks of config.json before anything runs
+func validateHooks(c formula.Config) error {
+	kinds := []struct {
+		name  string
+		hooks []formula.Hook
+	}{{hookPreRun, c.PreRun}, {hookPostRun, c.PostRun}}
+	for _, k := range kinds {
+		for i, h := range k.hooks {
+			oneOf := (h.Formula == "") != (h.Script == "")
+			onFailure := h.OnFailure == "" || h.OnFailure == formula.HookFail || h.OnFailure == formula.HookContinue
+			if !oneOf || !onFailure {
+				return prompt.NewError(fmt.Sprintf(msgHookInvalid, k.name, i+1))
+			}
+		}
+	}
+	return nil
+}
+
+// runHooked runs the preRun hooks of the formula, the formula with run and its postRun hooks.
+// A failed preRun hook stops the run as a failed formula would, then only the postRun hooks
+// that always run are run. The error of the formula takes precedence over the postRun ones.
+func runHooked(def formula.Definition, setup formula.Setup, run func() error) error {
+	if err := validateHooks(setup.Config); err != nil {
+		return err
+	}
+
+	runErr := runHooks(hookPreRun, setup.Config.PreRun, def, setup, nil)
+	if runErr == nil {
+		runErr = run()
+	}
+
+	postErr := runHooks(hookPostRun, setup.Config.PostRun, def, setup, runErr)
+	if runErr != nil {
+		return runErr
+	}
+	return postErr
+}
+
+// runHooks runs the hooks in order until one that doesn't continue fails, failure is the
+// failed run the postRun hooks run after, when they always run
+func runHooks(kind string, hooks []formula.Hook, def formula.Definition, setup formula.Setup, failure error) error {
+	for _, h := range hooks {
+		if failure != nil && !h.Always {
+			continue
+		}
+
+		name := h.Formula
+		if name == "" {
+			name = h.Script
+		}
+		prompt.Info(fmt.Sprintf(msgRunningHook, kind, name))
+
+		err := hookRun(hookCommand(h, def, setup, failure))
+		switch {
+		case err == nil:
+		case h.OnFailure == formula.HookContinue:
+			prompt.Warning(fmt.Sprintf(msgHookIgnored, kind, name, err))
+		default:
+			return fmt.Errorf(msgHookFailed, kind, name, err)
+		}
+	}
+	return nil
+}
+
+// hookCommand returns the command of the hook: rit running the hook formula or the shell
+// running the hook script on the working dir. Both get the env of the formula run.
+func hookCommand(h formula.Hook, def formula.Definition, setup formula.Setup, failure error) *exec.Cmd {
+	var c *exec.Cmd
+	switch {
+	case h.Formula != "":
+		exe, err := os.Executable()
+		if err != nil {
+			exe = os.Args[0]
+		}
+		c = exec.Command(exe, strings.Fields(strings.TrimPrefix(strings.TrimSpace(h.Formula), "rit "))...)
+	case runtime.GOOS == osutil.Windows:
+		c = exec.Command("cmd", "/C", h.Script)
+	default:
+		c = exec.Command("sh", "-c", h.Script)
+	}
+	c.Dir = setup.Pwd
+
+	c.Env = append(os.Environ(), def.Env...)
+	c.Env = append(c.Env, fmt.Sprintf(formula.EnvPattern, formula.HookFormulaDirEnv, setup.TmpBinDir))
+	if failure != nil {
+		c.Env = append(c.Env,
+			fmt.Sprintf(formula.EnvPattern, formula.FailedCommandEnv, def.Command),
+			fmt.Sprintf(formula.EnvPattern, formula.FailedExitCodeEnv, strconv.Itoa(exitCode(failure))),
+			fmt.Sprintf(formula.EnvPattern, formula.FailedErrorEnv, failure.Error()),
+		)
+	}
+
+	c.Stdin = os.Stdin
+	c.Stdout = formulaStdout(def)
+	c.Stderr = os.Stderr
+	return c
+}
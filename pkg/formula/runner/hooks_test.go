@@ -0,0 +1,150 @@
+package runner
+
+import (
+	"errors"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestRunHooked(t *testing.T) {
+	defer func(r func(*exec.Cmd) error) { hookRun = r }(hookRun)
+	exit2 := exec.Command("sh", "-c", "exit 2").Run()
+
+	refresh := formula.Hook{Formula: "aws refresh credentials"}
+	setupScript := formula.Hook{Script: "./setup.sh", OnFailure: formula.HookContinue}
+	cleanup := formula.Hook{Script: "rm -rf .cache", Always: true}
+	notify := formula.Hook{Formula: "rit slack notify"}
+	config := formula.Config{PreRun: []formula.Hook{refresh, setupScript}, PostRun: []formula.Hook{cleanup, notify}}
+
+	tests := []struct {
+		name      string
+		config    formula.Config
+		fail      string
+		runErr    error
+		wantRuns  []string
+		wantRan   bool
+		wantErr   error
+		wantError string
+	}{
+		{
+			name:     "runs the hooks around the formula",
+			config:   config,
+			wantRuns: []string{"aws refresh credentials", "sh -c ./setup.sh", "sh -c rm -rf .cache", "slack notify"},
+			wantRan:  true,
+		},
+		{
+			name:     "a failed pre hook that continues",
+			config:   config,
+			fail:     "./setup.sh",
+			wantRuns: []string{"aws refresh credentials", "sh -c ./setup.sh", "sh -c rm -rf .cache", "slack notify"},
+			wantRan:  true,
+		},
+		{
+			name:      "a failed pre hook stops the run",
+			config:    config,
+			fail:      "refresh",
+			wantRuns:  []string{"aws refresh credentials", "sh -c rm -rf .cache"},
+			wantErr:   exit2,
+			wantError: "the preRun hook aws refresh credentials failed",
+		},
+		{
+			name:     "a failed formula runs only the always hooks",
+			config:   config,
+			runErr:   exit2,
+			wantRuns: []string{"aws refresh credentials", "sh -c ./setup.sh", "sh -c rm -rf .cache"},
+			wantRan:  true,
+			wantErr:  exit2,
+		},
+		{
+			name:      "a failed post hook fails the run",
+			config:    config,
+			fail:      "notify",
+			wantRuns:  []string{"aws refresh credentials", "sh -c ./setup.sh", "sh -c rm -rf .cache", "slack notify"},
+			wantRan:   true,
+			wantErr:   exit2,
+			wantError: "the postRun hook rit slack notify failed",
+		},
+		{
+			name:      "invalid hook",
+			config:    formula.Config{PostRun: []formula.Hook{{Formula: "slack notify", Script: "./notify.sh"}}},
+			wantError: "the postRun hook 1 of config.json",
+		},
+		{
+			name:      "invalid onFailure",
+			config:    formula.Config{PreRun: []formula.Hook{{Script: "./setup.sh", OnFailure: "retry"}}},
+			wantError: "the preRun hook 1 of config.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var runs []string
+			var failedEnv []string
+			hookRun = func(c *exec.Cmd) error {
+				args := c.Args[1:]
+				if c.Args[0] == "sh" {
+					args = c.Args
+				}
+				run := strings.Join(args, " ")
+				runs = append(runs, run)
+				for _, e := range c.Env {
+					if strings.HasPrefix(e, formula.FailedExitCodeEnv+"=") {
+						failedEnv = append(failedEnv, e)
+					}
+				}
+				if tt.fail != "" && strings.Contains(run, tt.fail) {
+					return exit2
+				}
+				return nil
+			}
+
+			ran := false
+			setup := formula.Setup{Pwd: "/home/user/project", TmpBinDir: "/tmp/bin", Config: tt.config}
+			err := runHooked(formula.Definition{Command: "rit aws deploy"}, setup, func() error {
+				ran = true
+				return tt.runErr
+			})
+
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("runHooked() got %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantError != "" && (err == nil || !strings.Contains(err.Error(), tt.wantError)) {
+				t.Errorf("runHooked() got %v, want %q", err, tt.wantError)
+			}
+			if tt.wantErr == nil && tt.wantError == "" && err != nil {
+				t.Errorf("runHooked() got %v, want nil", err)
+			}
+			if ran != tt.wantRan {
+				t.Errorf("runHooked() ran the formula %v, want %v", ran, tt.wantRan)
+			}
+			if !reflect.DeepEqual(runs, tt.wantRuns) {
+				t.Errorf("runHooked() ran the hooks %q, want %q", runs, tt.wantRuns)
+			}
+			if tt.runErr != nil && !reflect.DeepEqual(failedEnv, []string{formula.FailedExitCodeEnv + "=2"}) {
+				t.Errorf("the always hook got %q, want the exit code of the failure", failedEnv)
+			}
+		})
+	}
+}
+
+func TestHookCommand(t *testing.T) {
+	setup := formula.Setup{Pwd: "/home/user/project", TmpBinDir: "/tmp/bin"}
+	c := hookCommand(formula.Hook{Script: "$RIT_FORMULA_DIR/setup.sh"}, formula.Definition{Env: []string{"STAGE=dev"}}, setup, nil)
+
+	if c.Dir != setup.Pwd {
+		t.Errorf("hookCommand() runs on %s, want the working dir %s", c.Dir, setup.Pwd)
+	}
+	env := strings.Join(c.Env, "\n")
+	for _, want := range []string{"STAGE=dev", formula.HookFormulaDirEnv + "=/tmp/bin"} {
+		if !strings.Contains(env, want) {
+			t.Errorf("hookCommand() env got no %s", want)
+		}
+	}
+	if strings.Contains(env, formula.FailedCommandEnv+"=") {
+		t.Errorf("hookCommand() env got %s without a failure", formula.FailedCommandEnv)
+	}
+}
@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+const cacheSuffix = ".cache"
+
+// cacheable tells whether the values of the input are cached, the inputs with an active cache
+// but the password, credential and sensitive ones, see plainInput
+func cacheable(input formula.Input) bool {
+	return input.Cache.Active && plainInput(input)
+}
+
+// cacheExpired tells whether the cached values of the input are older than its cache ttl,
+// they expire ttl after the last run that cached a value
+func cacheExpired(input formula.Input, cachePath string) bool {
+	if input.Cache.TTL == "" {
+		return false
+	}
+	ttl, err := time.ParseDuration(input.Cache.TTL)
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(cachePath)
+	return err == nil && time.Since(info.ModTime()) > ttl
+}
+
+// CleanInputCache removes the cached input values of all the formulas, returning how many
+// cache files were removed
+func CleanInputCache(ritchieHome string) (int, error) {
+	dir := filepath.Join(ritchieHome, "formulas")
+	var removed int
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		name := info.Name()
+		if info.IsDir() || !strings.HasPrefix(name, ".") || !strings.HasSuffix(name, cacheSuffix) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
@@ -0,0 +1,95 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestLoadItemsCacheTTL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	region := formula.Input{Name: "region", Type: "text", Items: []string{"sa-east-1"}, Cache: formula.Cache{Active: true, TTL: "1h"}}
+	cachePath := filepath.Join(dir, ".REGION.cache")
+	if err := ioutil.WriteFile(cachePath, []byte(`["us-east-1","sa-east-1"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		input formula.Input
+		age   time.Duration
+		want  []string
+	}{
+		{name: "fresh cache", input: region, age: time.Minute, want: []string{"us-east-1", "sa-east-1"}},
+		{name: "expired cache", input: region, age: 2 * time.Hour, want: []string{"sa-east-1"}},
+		{name: "sensitive input", input: formula.Input{Name: "region", Type: "text", Items: []string{"sa-east-1"}, Cache: formula.Cache{Active: true}, Sensitive: true}, want: []string{"sa-east-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = ioutil.WriteFile(cachePath, []byte(`["us-east-1","sa-east-1"]`), 0644)
+			modTime := time.Now().Add(-tt.age)
+			if err := os.Chtimes(cachePath, modTime, modTime); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := loadItems(tt.input, dir)
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadItems() got %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPersistCacheSensitive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	token := formula.Input{Name: "token", Type: "text", Cache: formula.Cache{Active: true}, Sensitive: true}
+	persistCache(dir, "s3cr3t", token, nil)
+	if _, err := os.Stat(filepath.Join(dir, ".TOKEN.cache")); !os.IsNotExist(err) {
+		t.Errorf("persistCache() cached the sensitive input, stat got %v", err)
+	}
+}
+
+func TestCleanInputCache(t *testing.T) {
+	home, err := ioutil.TempDir("", "rit-home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	files := []string{"formulas/aws/terraform/.REGION.cache", "formulas/aws/terraform/config.json", "formulas/github/repo/.NAME.cache"}
+	for _, f := range files {
+		p := filepath.Join(home, f)
+		_ = os.MkdirAll(filepath.Dir(p), os.ModePerm)
+		if err := ioutil.WriteFile(p, []byte("[]"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := CleanInputCache(home)
+	if err != nil || removed != 2 {
+		t.Errorf("CleanInputCache() got %d, %v, want 2", removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(home, files[1])); err != nil {
+		t.Errorf("CleanInputCache() removed the config.json: %v", err)
+	}
+
+	if removed, err := CleanInputCache(filepath.Join(home, "missing")); err != nil || removed != 0 {
+		t.Errorf("CleanInputCache() of a home without formulas got %d, %v, want 0", removed, err)
+	}
+}
@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/os/osutil"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	// fromCommandTimeout is how long the fromCommand of an input may run
+	fromCommandTimeout   = 5 * time.Second
+	msgFromCommandFailed = "The fromCommand of the input %s failed, using its declared default: %v"
+)
+
+// commandOutput runs the command on the dir with the shell and returns its stdout.
+// It is a var so the input commands can be replaced on tests.
+var commandOutput = func(ctx context.Context, dir, command string) (string, error) {
+	var c *exec.Cmd
+	if runtime.GOOS == osutil.Windows {
+		c = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	c.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("%v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// fromCommand returns the value of the text or multiselect input computed by its fromCommand, run on the
+// working dir of the user. A command printing nothing, failing or exceeding fromCommandTimeout
+// falls back to the declared default, the failure is only logged with the verbose mode.
+func fromCommand(cmd *exec.Cmd, setup formula.Setup, input formula.Input) string {
+	if (input.Type != "text" && input.Type != formula.MultiselectType) || input.FromCommand == "" {
+		return input.Default
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fromCommandTimeout)
+	defer cancel()
+
+	out, err := commandOutput(ctx, setup.Pwd, input.FromCommand)
+	if out = strings.TrimSpace(out); err == nil && out != "" {
+		return out
+	}
+
+	if verbose(cmd) {
+		prompt.Warning(fmt.Sprintf(msgFromCommandFailed, input.Name, err))
+	}
+	return input.Default
+}
+
+// verbose tells whether the formula runs with the verbose mode, from its env
+func verbose(cmd *exec.Cmd) bool {
+	on := false
+	prefix := formula.VerboseEnv + "="
+	for _, e := range cmd.Env {
+		if strings.HasPrefix(e, prefix) {
+			on = strings.TrimPrefix(e, prefix) == "true"
+		}
+	}
+	return on
+}
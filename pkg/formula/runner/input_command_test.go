@@ -0,0 +1,93 @@
+package runner
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/env"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestFromCommand(t *testing.T) {
+	pwd, err := ioutil.TempDir("", "rit-from-command")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(pwd)
+	setup := formula.Setup{Pwd: pwd}
+
+	tests := []struct {
+		name  string
+		input formula.Input
+		want  string
+	}{
+		{name: "stdout", input: formula.Input{Type: "text", Default: "main", FromCommand: "echo ' feature '"}, want: "feature"},
+		{name: "working dir", input: formula.Input{Type: "text", FromCommand: "pwd"}, want: pwd},
+		{name: "failure", input: formula.Input{Type: "text", Default: "main", FromCommand: "echo feature; exit 3"}, want: "main"},
+		{name: "no output", input: formula.Input{Type: "text", Default: "main", FromCommand: "true"}, want: "main"},
+		{name: "not a text input", input: formula.Input{Type: "bool", Default: "false", FromCommand: "echo true"}, want: "false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fromCommand(&exec.Cmd{}, setup, tt.input)
+			// the tmp dir may be a symlink, as on macOS
+			if got != tt.want && !strings.HasSuffix(got, tt.want) {
+				t.Errorf("fromCommand got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("timeout", func(t *testing.T) {
+		old := commandOutput
+		defer func() { commandOutput = old }()
+		commandOutput = func(ctx context.Context, dir, command string) (string, error) {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("the input command must run with a timeout")
+			}
+			return "", context.DeadlineExceeded
+		}
+
+		cmd := &exec.Cmd{Env: []string{formula.VerboseEnv + "=true"}}
+		input := formula.Input{Name: "branch", Type: "text", Default: "main", FromCommand: "sleep 10"}
+		if got := fromCommand(cmd, setup, input); got != "main" {
+			t.Errorf("fromCommand got %q, want the default", got)
+		}
+	})
+}
+
+func TestInputManager_FromCommand(t *testing.T) {
+	inputs := []formula.Input{
+		{Name: "branch", Type: "text", Label: "branch", Default: "main", FromCommand: "echo feature"},
+		{Name: "tag", Type: "text", Label: "tag", FromCommand: "echo v1"},
+	}
+	setup := formula.Setup{Config: formula.Config{Inputs: inputs}}
+
+	t.Run("stdin", func(t *testing.T) {
+		inputManager := NewInputManager(env.Resolvers{}, inputMock{}, inputMock{}, inputMock{}, inputMock{}, inputMock{}, inputMock{}, inputMock{}, false)
+		cmd := &exec.Cmd{Stdin: strings.NewReader(`{"tag": "v2"}`)}
+		if err := inputManager.Inputs(cmd, setup, api.Stdin); err != nil {
+			t.Fatalf("Inputs error = %v", err)
+		}
+		if want := []string{"BRANCH=feature", "TAG=v2"}; !reflect.DeepEqual(cmd.Env, want) {
+			t.Errorf("Inputs env = %v, want %v", cmd.Env, want)
+		}
+	})
+
+	t.Run("prompt", func(t *testing.T) {
+		inputManager := NewInputManager(env.Resolvers{}, inputMock{}, inputMock{}, inputMock{}, inputMock{}, inputMock{}, inputMock{}, inputMock{}, false)
+		cmd := &exec.Cmd{}
+		if err := inputManager.Inputs(cmd, setup, api.Prompt); err != nil {
+			t.Fatalf("Inputs error = %v", err)
+		}
+		if want := []string{"BRANCH=feature", "TAG=v1"}; !reflect.DeepEqual(cmd.Env, want) {
+			t.Errorf("Inputs env = %v, want %v", cmd.Env, want)
+		}
+	})
+}
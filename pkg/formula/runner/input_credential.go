@@ -0,0 +1,112 @@
+package runner
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/env"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/slice/sliceutil"
+)
+
+const (
+	// credentialFieldsEnv lists the env vars of the fields of a credential input, comma separated,
+	// e.g. GIT_TOKEN,GIT_USERNAME, so only the fields resolved are masked on the run log
+	credentialFieldsEnv  = "RIT_CREDENTIAL_FIELDS"
+	msgNoCredential      = "no credential to select for the input %s, set one with rit set credential"
+	msgUnknownCredential = "the credential input %s has no provider %q, choose among %s"
+)
+
+var ErrCredentialSelector = prompt.NewError("the credential inputs can't list the credentials of the user")
+
+// credentialProviders returns the providers the credential input lists, the ones with
+// a credential matching its items
+func (d InputManager) credentialProviders(input formula.Input) (env.Selector, []string, error) {
+	sel, ok := d.envResolvers[env.Credential].(env.Selector)
+	if !ok {
+		return nil, nil, ErrCredentialSelector
+	}
+
+	all, err := sel.List()
+	if err != nil {
+		return nil, nil, err
+	}
+	var providers []string
+	for _, p := range all {
+		if credentialMatch(input.Items, p) {
+			providers = append(providers, p)
+		}
+	}
+	if len(providers) == 0 {
+		return nil, nil, prompt.NewError(fmt.Sprintf(msgNoCredential, input.Name))
+	}
+	return sel, providers, nil
+}
+
+// credentialMatch tells whether the provider is one of the items or another account of one,
+// e.g. github-work for github, every provider matches without items
+func credentialMatch(items []string, provider string) bool {
+	if len(items) == 0 {
+		return true
+	}
+	for _, item := range items {
+		if provider == item || strings.HasPrefix(provider, item+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// addCredentialEnv adds the env vars of the fields of the credential of the provider
+// selected, e.g. GIT_TOKEN for the field token of the input git
+func (d InputManager) addCredentialEnv(cmd *exec.Cmd, input formula.Input, provider string) error {
+	sel, providers, err := d.credentialProviders(input)
+	if err != nil {
+		return err
+	}
+	if !sliceutil.Contains(providers, provider) {
+		return prompt.NewError(fmt.Sprintf(msgUnknownCredential, input.Name, provider, strings.Join(providers, ", ")))
+	}
+
+	fields, err := sel.ResolveAll(provider)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	envNames := make([]string, len(names))
+	for i, name := range names {
+		addEnv(cmd, input.Name+"_"+name, fields[name])
+		envNames[i] = strings.ToUpper(input.Name + "_" + name)
+	}
+	addEnv(cmd, credentialFieldsEnv, strings.Join(envNames, ","))
+	return nil
+}
+
+// credentialFields returns the values of the fields of the credential input on the formula env,
+// only the fields resolved for it on credentialFieldsEnv, not the other vars sharing their prefix
+func credentialFields(env []string, in formula.Input) []string {
+	fields := make(map[string]bool)
+	prefix := strings.ToUpper(in.Name) + "_"
+	for _, e := range env {
+		if strings.HasPrefix(e, credentialFieldsEnv+"=") {
+			for _, name := range strings.Split(strings.TrimPrefix(e, credentialFieldsEnv+"="), ",") {
+				fields[name] = strings.HasPrefix(name, prefix)
+			}
+		}
+	}
+
+	var ss []string
+	for _, e := range env {
+		if kv := strings.SplitN(e, "=", 2); len(kv) == 2 && fields[kv[0]] {
+			ss = append(ss, kv[1])
+		}
+	}
+	return ss
+}
@@ -0,0 +1,51 @@
+package runner
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	// editorDirName is the dir of the editor input files on the temp workspace of the run
+	editorDirName = "inputs"
+
+	msgEditorUnsupported = "the input %s is written to a local file, which a %s run can't read, run the formula locally or with --docker"
+)
+
+// editorValue writes the content of an editor input on a file of the temp workspace of the
+// run and returns its path, the file is removed with the workspace after the run
+func editorValue(setup formula.Setup, input formula.Input, content string) (string, error) {
+	dir := filepath.Join(setup.TmpDir, editorDirName)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	file := filepath.Join(dir, editorFileName(input))
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		return "", err
+	}
+	return file, nil
+}
+
+// checkEditorInputs fails the runs of the formulas with editor inputs on the runners their
+// files don't reach, the ssh hosts, the clusters and the session containers, before the
+// inputs are asked
+func checkEditorInputs(setup formula.Setup, run string) error {
+	for _, in := range setup.Config.Inputs {
+		if in.Type == formula.EditorType {
+			return prompt.NewError(fmt.Sprintf(msgEditorUnsupported, in.Name, run))
+		}
+	}
+	return nil
+}
+
+// editorFileName is the name of the file of an editor input, e.g. manifest.yaml for the
+// input manifest with the pattern *.yaml
+func editorFileName(input formula.Input) string {
+	return input.Name + filepath.Ext(input.Pattern)
+}
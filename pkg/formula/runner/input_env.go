@@ -0,0 +1,83 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+// InputEnvPrefix is the prefix of the env vars informing the formula inputs, e.g.
+// RIT_INPUT_REGION informs the input region. The input name is upper cased with the
+// chars other than letters and digits replaced by _, so my-region is RIT_INPUT_MY_REGION,
+// and the var names are matched case-insensitively. The inputs sharing a var get the same
+// value, an empty var is taken as not set and the vars of no input are ignored.
+const InputEnvPrefix = "RIT_INPUT_"
+
+const msgUnknownInputEnv = "%s doesn't inform any input of the formula, it is ignored"
+
+var (
+	ErrInvalidInputEnv = errors.New("the bool input needs true or false")
+	invalidEnvChars    = regexp.MustCompile(`[^A-Z0-9]`)
+)
+
+// InputEnvName returns the env var informing the input
+func InputEnvName(input string) string {
+	return InputEnvPrefix + invalidEnvChars.ReplaceAllString(strings.ToUpper(input), "_")
+}
+
+// envInputs returns the values of the text, bool, int, float, password, secret, editor, credential, multiselect and path inputs informed
+// by the env vars of the formula, by input name. When the env has the same var more than once,
+// in different cases, the last one wins. The vars of no input are reported with verbose.
+func envInputs(cmd *exec.Cmd, inputs []formula.Input) map[string]string {
+	byVar := make(map[string]string)
+	for _, e := range cmd.Env {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) == 2 && strings.HasPrefix(strings.ToUpper(kv[0]), InputEnvPrefix) {
+			byVar[strings.ToUpper(kv[0])] = kv[1]
+		}
+	}
+
+	values := make(map[string]string)
+	used := make(map[string]bool)
+	for _, in := range inputs {
+		switch in.Type {
+		case "text", "bool", formula.IntType, formula.FloatType, "password", formula.SecretType, formula.EditorType, formula.CredentialType, formula.MultiselectType, formula.PathType:
+		default:
+			continue
+		}
+
+		name := InputEnvName(in.Name)
+		used[name] = true
+		if v := byVar[name]; v != "" {
+			values[in.Name] = v
+		}
+	}
+
+	if verbose(cmd) {
+		for name := range byVar {
+			if !used[name] {
+				prompt.Warning(fmt.Sprintf(msgUnknownInputEnv, name))
+			}
+		}
+	}
+	return values
+}
+
+// envValue validates the env value of the input, the bool values are normalized to true or false
+func envValue(input formula.Input, v string) (string, error) {
+	if input.Type != "bool" {
+		return v, validValue(input, v)
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", InputEnvName(input.Name), ErrInvalidInputEnv)
+	}
+	return strconv.FormatBool(b), nil
+}
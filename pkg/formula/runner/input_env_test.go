@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"errors"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/env"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestInputEnvName(t *testing.T) {
+	tests := map[string]string{
+		"region":       "RIT_INPUT_REGION",
+		"my-region":    "RIT_INPUT_MY_REGION",
+		"aws.zone_1":   "RIT_INPUT_AWS_ZONE_1",
+		"Cluster Name": "RIT_INPUT_CLUSTER_NAME",
+	}
+
+	for in, want := range tests {
+		if got := InputEnvName(in); got != want {
+			t.Errorf("InputEnvName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestEnvInputs(t *testing.T) {
+	inputs := []formula.Input{
+		{Name: "region", Type: "text"},
+		{Name: "my-zone", Type: "text"},
+		{Name: "debug", Type: "bool"},
+		{Name: "size", Type: "text"},
+		{Name: "kind", Type: "text", Items: []string{"a", "b"}},
+		{Name: "token", Type: "CREDENTIAL_AWS_TOKEN"},
+	}
+	cmd := &exec.Cmd{Env: []string{
+		"RIT_INPUT_REGION=sa-east-1",
+		"rit_input_my_zone=a",
+		"RIT_INPUT_MY_ZONE=b",
+		"RIT_INPUT_DEBUG=1",
+		"RIT_INPUT_SIZE=",
+		"RIT_INPUT_TOKEN=secret",
+		"RIT_INPUT_UNKNOWN=x",
+		"REGION=us-east-1",
+	}}
+
+	want := map[string]string{"region": "sa-east-1", "my-zone": "b", "debug": "1"}
+	if got := envInputs(cmd, inputs); !reflect.DeepEqual(got, want) {
+		t.Errorf("envInputs = %v, want %v", got, want)
+	}
+}
+
+func TestInputManager_EnvInputs(t *testing.T) {
+	inputs := []formula.Input{
+		{Name: "region", Type: "text", Label: "region", Default: "us-east-1"},
+		{Name: "debug", Type: "bool", Label: "debug", Items: []string{"false", "true"}},
+		{Name: "zone", Type: "password", Label: "zone"},
+	}
+	setup := formula.Setup{Config: formula.Config{Inputs: inputs}}
+	prompted := inputMock{text: "prompted"}
+	inputManager := NewInputManager(env.Resolvers{}, prompted, prompted, prompted, prompted, prompted, prompted, prompted, false)
+	environ := []string{"RIT_INPUT_REGION=sa-east-1", "RIT_INPUT_DEBUG=yes", "RIT_INPUT_ZONE=b"}
+
+	tests := []struct {
+		name    string
+		in      api.TermInputType
+		stdin   string
+		environ []string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "stdin takes precedence over env",
+			in:      api.Stdin,
+			stdin:   `{"region": "eu-west-1", "debug": false}`,
+			environ: []string{"RIT_INPUT_REGION=sa-east-1", "RIT_INPUT_ZONE=b"},
+			want:    []string{"REGION=eu-west-1", "DEBUG=false", "ZONE=b"},
+		},
+		{
+			name:    "env fills the inputs missing on stdin",
+			in:      api.Stdin,
+			stdin:   `{"region": "eu-west-1"}`,
+			environ: []string{"RIT_INPUT_DEBUG=true", "RIT_INPUT_ZONE=b"},
+			want:    []string{"REGION=eu-west-1", "DEBUG=true", "ZONE=b"},
+		},
+		{
+			name:    "prompt skips the env inputs",
+			in:      api.Prompt,
+			environ: []string{"RIT_INPUT_REGION=sa-east-1", "RIT_INPUT_DEBUG=T"},
+			want:    []string{"REGION=sa-east-1", "DEBUG=true", "ZONE=prompted"},
+		},
+		{
+			name:    "invalid bool",
+			in:      api.Prompt,
+			environ: environ,
+			wantErr: ErrInvalidInputEnv,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &exec.Cmd{Stdin: strings.NewReader(tt.stdin), Env: tt.environ}
+			err := inputManager.Inputs(cmd, setup, tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Inputs error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got := cmd.Env[len(tt.environ):]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Inputs env = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
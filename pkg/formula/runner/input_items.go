@@ -0,0 +1,91 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	msgItemsFromFailed = "Unable to read the items of the input %s, using its declared items: %v"
+	msgItemsFromStatus = "GET %s returned %s"
+)
+
+// itemsClient gets the items of the inputs with a url, it is a var so the tests replace it
+var itemsClient = &http.Client{Timeout: fromCommandTimeout}
+
+// itemsFrom returns the items of the input read from its itemsFrom when it is prompted. When
+// they can't be read the declared items are used, the failure is reported as a warning.
+func itemsFrom(setup formula.Setup, input formula.Input) []string {
+	if input.ItemsFrom == nil {
+		return input.Items
+	}
+
+	items, err := readItems(setup.Pwd, *input.ItemsFrom)
+	if err != nil {
+		prompt.Warning(fmt.Sprintf(msgItemsFromFailed, input.Name, err))
+		return input.Items
+	}
+	return items
+}
+
+// readItems reads the items from the stdout of the command, the response of the url or
+// the file, they take as long as the fromCommand of an input at most
+func readItems(pwd string, from formula.ItemsFrom) ([]string, error) {
+	var out []byte
+	switch {
+	case from.Command != "":
+		ctx, cancel := context.WithTimeout(context.Background(), fromCommandTimeout)
+		defer cancel()
+		s, err := commandOutput(ctx, pwd, from.Command)
+		if err != nil {
+			return nil, err
+		}
+		out = []byte(s)
+	case from.URL != "":
+		resp, err := itemsClient.Get(from.URL)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf(msgItemsFromStatus, from.URL, resp.Status)
+		}
+		if out, err = ioutil.ReadAll(resp.Body); err != nil {
+			return nil, err
+		}
+	default:
+		file := from.File
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(pwd, file)
+		}
+		var err error
+		if out, err = ioutil.ReadFile(file); err != nil {
+			return nil, err
+		}
+	}
+	return parseItems(out), nil
+}
+
+// parseItems parses a JSON array of strings or the lines of the text, the blank lines skipped
+func parseItems(out []byte) []string {
+	var items []string
+	if text := strings.TrimSpace(string(out)); strings.HasPrefix(text, "[") && json.Unmarshal([]byte(text), &items) == nil {
+		return items
+	}
+
+	items = nil
+	for _, l := range strings.Split(string(out), "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			items = append(items, l)
+		}
+	}
+	return items
+}
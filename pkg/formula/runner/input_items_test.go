@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestItemsFrom(t *testing.T) {
+	defer func(o func(context.Context, string, string) (string, error)) { commandOutput = o }(commandOutput)
+	commandOutput = func(_ context.Context, dir, command string) (string, error) {
+		if command == "kubectl config get-contexts -o name" {
+			return "dev\n\nprod\n", nil
+		}
+		return "", errors.New("exit status 127")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`["ritchie-cli", "ritchie-formulas"]`))
+	}))
+	defer server.Close()
+
+	pwd, err := ioutil.TempDir("", "rit-items")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(pwd)
+	if err := ioutil.WriteFile(filepath.Join(pwd, "regions.txt"), []byte("us-east-1\r\nsa-east-1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	declared := []string{"default"}
+	tests := []struct {
+		name string
+		from *formula.ItemsFrom
+		want []string
+	}{
+		{name: "declared items", want: declared},
+		{name: "command lines", from: &formula.ItemsFrom{Command: "kubectl config get-contexts -o name"}, want: []string{"dev", "prod"}},
+		{name: "url json array", from: &formula.ItemsFrom{URL: server.URL + "/repos"}, want: []string{"ritchie-cli", "ritchie-formulas"}},
+		{name: "file lines", from: &formula.ItemsFrom{File: "regions.txt"}, want: []string{"us-east-1", "sa-east-1"}},
+		{name: "failed command", from: &formula.ItemsFrom{Command: "kubectx"}, want: declared},
+		{name: "failed url", from: &formula.ItemsFrom{URL: server.URL + "/clusters"}, want: declared},
+		{name: "missing file", from: &formula.ItemsFrom{File: "clusters.txt"}, want: declared},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := formula.Input{Name: "item", Type: "text", Items: declared, ItemsFrom: tt.from}
+			if got := itemsFrom(formula.Setup{Pwd: pwd}, input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("itemsFrom() got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/slice/sliceutil"
+)
+
+const msgInvalidSelection = "the multiselect input %s has no item %q, choose among %s"
+
+// selection returns the items selected on the value of a multiselect input informed by
+// stdin or env, a JSON array or a text with the items separated by the input delimiter
+func selection(input formula.Input, v interface{}) ([]string, error) {
+	var selected []string
+	switch v := v.(type) {
+	case nil:
+	case []interface{}:
+		for _, item := range v {
+			selected = append(selected, fmt.Sprintf("%v", item))
+		}
+	default:
+		selected = splitSelection(input, fmt.Sprintf("%v", v))
+	}
+
+	// the items read from itemsFrom are only known when the input is prompted
+	for _, s := range selected {
+		if input.ItemsFrom == nil && !sliceutil.Contains(input.Items, s) {
+			return nil, prompt.NewError(fmt.Sprintf(msgInvalidSelection, input.Name, s, strings.Join(input.Items, ", ")))
+		}
+	}
+	return selected, nil
+}
+
+// splitSelection splits the text of the selected items, a JSON array is taken as is
+func splitSelection(input formula.Input, v string) []string {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return nil
+	}
+
+	var items []string
+	if strings.HasPrefix(v, "[") && json.Unmarshal([]byte(v), &items) == nil {
+		return items
+	}
+	for _, item := range strings.Split(v, delimiter(input)) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func delimiter(input formula.Input) string {
+	if input.Delimiter != "" {
+		return input.Delimiter
+	}
+	return formula.DefaultDelimiter
+}
+
+// addSelectionEnv adds the env vars of the selected items, separated by the input delimiter
+// and as a JSON array, returning the value of the first one
+func addSelectionEnv(cmd *exec.Cmd, input formula.Input, selected []string) string {
+	if selected == nil {
+		selected = []string{}
+	}
+
+	v := strings.Join(selected, delimiter(input))
+	b, _ := json.Marshal(selected)
+	addEnv(cmd, input.Name, v)
+	addEnv(cmd, input.Name+formula.JSONEnvSuffix, string(b))
+	return v
+}
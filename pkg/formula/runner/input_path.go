@@ -0,0 +1,96 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	// containerInputsDir is where the docker runs mount the path inputs out of the working dir
+	containerInputsDir = "/rit/inputs"
+	containerWorkDir   = "/app"
+	msgPathNotFound    = "the path %s of the input %s doesn't exist"
+	msgPathMissing     = "the path input %s is missing a value"
+	msgPathNotMatching = "the path %s of the input %s doesn't match %s"
+	msgInvalidPathGlob = "invalid pattern %q of the input %s: %v"
+	dockerNameArg      = "--name"
+)
+
+// pathValue validates the path of a path input, informed relative to the working dir,
+// absolute or on the home dir, and returns it absolute. An empty path is missing, it is
+// never the working dir.
+func pathValue(input formula.Input, pwd, v string) (string, error) {
+	if strings.TrimSpace(v) == "" {
+		return "", prompt.NewError(fmt.Sprintf(msgPathMissing, input.Name))
+	}
+	p := expandHome(strings.TrimSpace(v))
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(pwd, p)
+	}
+
+	if _, err := os.Stat(p); err != nil {
+		return "", prompt.NewError(fmt.Sprintf(msgPathNotFound, v, input.Name))
+	}
+	if input.Pattern != "" {
+		ok, err := filepath.Match(input.Pattern, filepath.Base(p))
+		if err != nil {
+			return "", prompt.NewError(fmt.Sprintf(msgInvalidPathGlob, input.Pattern, input.Name, err))
+		} else if !ok {
+			return "", prompt.NewError(fmt.Sprintf(msgPathNotMatching, v, input.Name, input.Pattern))
+		}
+	}
+	return p, nil
+}
+
+// pathMounts replaces the path inputs on the env of the docker run with their paths on the
+// container and returns the volume args mounting them. The paths on the working dir are
+// on its /app mount already, the other ones are mounted on containerInputsDir with Mount
+// and kept as they are otherwise. The files of the editor inputs are always mounted.
+func pathMounts(cmd *exec.Cmd, setup formula.Setup) []string {
+	var args []string
+	for _, in := range setup.Config.Inputs {
+		if in.Type != formula.PathType && in.Type != formula.EditorType {
+			continue
+		}
+
+		prefix := strings.ToUpper(in.Name) + "="
+		for i, e := range cmd.Env {
+			if !strings.HasPrefix(e, prefix) {
+				continue
+			}
+
+			host := strings.TrimPrefix(e, prefix)
+			var container string
+			if rel, err := filepath.Rel(setup.Pwd, host); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				container = path.Join(containerWorkDir, filepath.ToSlash(rel))
+			} else if in.Mount || in.Type == formula.EditorType {
+				container = path.Join(containerInputsDir, in.Name, filepath.Base(host))
+				args = append(args, "-v", host+":"+container)
+			} else {
+				continue
+			}
+			cmd.Env[i] = prefix + container
+		}
+	}
+	return args
+}
+
+// insertArgs inserts the args on the command line of cmd before the arg before
+func insertArgs(cmd *exec.Cmd, before string, args []string) {
+	if len(args) == 0 {
+		return
+	}
+	for i, a := range cmd.Args {
+		if a == before {
+			cmd.Args = append(cmd.Args[:i], append(args, cmd.Args[i:]...)...)
+			return
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestPathValue(t *testing.T) {
+	pwd, err := ioutil.TempDir("", "rit-path")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(pwd)
+	main := filepath.Join(pwd, "main.tf")
+	if err := ioutil.WriteFile(main, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tf := formula.Input{Name: "plan", Type: formula.PathType, Pattern: "*.tf"}
+	tests := []struct {
+		name    string
+		input   formula.Input
+		value   string
+		want    string
+		wantErr string
+	}{
+		{name: "relative path", input: tf, value: "main.tf", want: main},
+		{name: "absolute path", input: formula.Input{Name: "dir", Type: formula.PathType}, value: pwd, want: pwd},
+		{name: "empty path", input: tf, value: " ", wantErr: "the path input plan is missing a value"},
+		{name: "missing path", input: tf, value: "vars.tf", wantErr: "the path vars.tf of the input plan doesn't exist"},
+		{name: "path not matching", input: formula.Input{Name: "plan", Pattern: "*.json"}, value: "main.tf", wantErr: "doesn't match *.json"},
+		{name: "invalid pattern", input: formula.Input{Name: "plan", Pattern: "[tf"}, value: "main.tf", wantErr: `invalid pattern "[tf" of the input plan`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pathValue(tt.input, pwd, tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("pathValue() got %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("pathValue() got %s, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathMounts(t *testing.T) {
+	pwd := filepath.Join(os.TempDir(), "project")
+	kube := filepath.Join(os.TempDir(), "kube", "config")
+	manifest := filepath.Join(os.TempDir(), "rit", editorDirName, "manifest.yaml")
+	setup := formula.Setup{Pwd: pwd, Config: formula.Config{Inputs: []formula.Input{
+		{Name: "plan", Type: formula.PathType},
+		{Name: "kubeconfig", Type: formula.PathType, Mount: true},
+		{Name: "cert", Type: formula.PathType},
+		{Name: "name", Type: "text"},
+		{Name: "manifest", Type: formula.EditorType},
+	}}}
+	cmd := exec.Command("docker", "run", "-v", pwd+":/app", "--name", "rit-1", "rit-1")
+	cmd.Env = []string{
+		"PLAN=" + filepath.Join(pwd, "envs", "dev.tfplan"),
+		"KUBECONFIG=" + kube,
+		"CERT=/etc/ssl/cert.pem",
+		"NAME=" + filepath.Join(pwd, "name"),
+		"MANIFEST=" + manifest,
+	}
+
+	insertArgs(cmd, dockerNameArg, pathMounts(cmd, setup))
+
+	wantEnv := []string{"PLAN=/app/envs/dev.tfplan", "KUBECONFIG=/rit/inputs/kubeconfig/config", "CERT=/etc/ssl/cert.pem", "NAME=" + filepath.Join(pwd, "name"), "MANIFEST=/rit/inputs/manifest/manifest.yaml"}
+	if !reflect.DeepEqual(cmd.Env, wantEnv) {
+		t.Errorf("pathMounts() env got %q, want %q", cmd.Env, wantEnv)
+	}
+	wantArgs := []string{"docker", "run", "-v", pwd + ":/app", "-v", kube + ":/rit/inputs/kubeconfig/config", "-v", manifest + ":/rit/inputs/manifest/manifest.yaml", "--name", "rit-1", "rit-1"}
+	if !reflect.DeepEqual(cmd.Args, wantArgs) {
+		t.Errorf("pathMounts() args got %q, want %q", cmd.Args, wantArgs)
+	}
+}
@@ -0,0 +1,152 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/slice/sliceutil"
+)
+
+const (
+	msgInvalidStdin   = "The stdin inputs don't match the inputs of the formula:\n%s\nExpected a JSON like %s"
+	msgStdinViolation = "  - %s: %v"
+	msgStdinRequired  = "is required"
+	msgStdinType      = "must be %s, got %s"
+	msgStdinItem      = "must be one of %s, got %q"
+	msgUnknownStdin   = "The stdin inputs %s aren't inputs of the formula, they are ignored"
+)
+
+// stdinValue returns the value of a text, password, secret, editor, int, float or bool input informed by the stdin JSON, the
+// texts accept numbers and the bools "true" and "false" too. The text inputs with items, unless
+// cached or read from itemsFrom, accept only one of them. A missing input gets its default,
+// the one computed by its fromCommand for a text input, and a missing required one is an error.
+func stdinValue(input formula.Input, v interface{}, def func() string) (string, error) {
+	if v == nil {
+		if input.Required() {
+			return "", errors.New(msgStdinRequired)
+		}
+		return def(), nil
+	}
+
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		s = strconv.FormatBool(v)
+	default:
+		return "", fmt.Errorf(msgStdinType, stdinKind(input), jsonKind(v))
+	}
+
+	if input.Type == "bool" {
+		b, err := strconv.ParseBool(s)
+		if _, isNumber := v.(float64); err != nil || isNumber {
+			return "", fmt.Errorf(msgStdinType, stdinKind(input), jsonKind(v))
+		}
+		return strconv.FormatBool(b), nil
+	} else if _, isBool := v.(bool); isBool {
+		return "", fmt.Errorf(msgStdinType, stdinKind(input), jsonKind(v))
+	}
+
+	if len(input.Items) > 0 && input.ItemsFrom == nil && !input.Cache.Active && !sliceutil.Contains(input.Items, s) {
+		return "", fmt.Errorf(msgStdinItem, strings.Join(input.Items, ", "), s)
+	}
+	return s, input.Validate(s)
+}
+
+// stdinKind describes the JSON value expected for the input
+func stdinKind(input formula.Input) string {
+	switch input.Type {
+	case "bool":
+		return "a bool"
+	case formula.IntType:
+		return "an integer"
+	case formula.FloatType:
+		return "a number"
+	case formula.MultiselectType:
+		return "a list of texts"
+	case formula.PathType:
+		return "a path"
+	default:
+		return "a text"
+	}
+}
+
+func jsonKind(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a bool"
+	case float64:
+		return "a number"
+	case string:
+		return "a text"
+	case []interface{}:
+		return "a list"
+	default:
+		return "an object"
+	}
+}
+
+// stdinError reports every violation of the stdin inputs along with the JSON the formula expects
+func stdinError(inputs []formula.Input, violations []string) error {
+	return prompt.NewError(fmt.Sprintf(msgInvalidStdin, strings.Join(violations, "\n"), stdinShape(inputs)))
+}
+
+// stdinShape returns a sample of the stdin JSON of the inputs, e.g.
+// {"region": "<us-east-1|sa-east-1>", "public": <true|false>, "tags": ["<text>"]}
+func stdinShape(inputs []formula.Input) string {
+	var fields []string
+	for _, in := range inputs {
+		text := "<" + strings.SplitN(stdinKind(in), " ", 2)[1] + ">"
+		if len(in.Items) > 0 {
+			text = "<" + strings.Join(in.Items, "|") + ">"
+		}
+
+		var v string
+		switch in.Type {
+		case "text", "password", formula.SecretType, formula.EditorType, formula.CredentialType, formula.PathType:
+			v = strconv.Quote(text)
+		case "bool":
+			v = "<true|false>"
+		case formula.IntType, formula.FloatType:
+			v = text
+		case formula.MultiselectType:
+			if len(in.Items) == 0 {
+				text = "<text>"
+			}
+			v = "[" + strconv.Quote(text) + "]"
+		default:
+			continue
+		}
+		fields = append(fields, fmt.Sprintf("%q: %s", in.Name, v))
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+// warnUnknownStdin warns about the stdin inputs that aren't inputs of the formula, likely a typo
+func warnUnknownStdin(inputs []formula.Input, data map[string]interface{}) {
+	known := make(map[string]bool, len(inputs))
+	for _, in := range inputs {
+		known[in.Name] = true
+	}
+
+	var unknown []string
+	for name := range data {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		prompt.Warning(fmt.Sprintf(msgUnknownStdin, strings.Join(unknown, ", ")))
+	}
+}
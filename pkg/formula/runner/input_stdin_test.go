@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/env"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/slice/sliceutil"
+)
+
+func TestInputManager_StdinValidation(t *testing.T) {
+	setup := formula.Setup{Config: formula.Config{Inputs: []formula.Input{
+		{Name: "name", Type: "text"},
+		{Name: "region", Type: "text", Items: []string{"us-east-1", "sa-east-1"}, Default: "us-east-1"},
+		{Name: "public", Type: "bool", Default: "false"},
+		{Name: "pass", Type: "password"},
+		{Name: "tags", Type: formula.MultiselectType, Items: []string{"dev", "prod"}},
+		{Name: "size", Type: "text", Default: "small"},
+	}}}
+
+	tests := []struct {
+		name    string
+		stdin   string
+		want    []string
+		wantErr []string
+	}{
+		{
+			name:  "valid inputs",
+			stdin: `{"name":"app","public":"true","pass":"123","tags":["dev"],"replicas":2}`,
+			want:  []string{"NAME=app", "REGION=us-east-1", "PUBLIC=true", "PASS=123", "TAGS=dev", "SIZE=small"},
+		},
+		{
+			name:  "every violation",
+			stdin: `{"region":"eu-west-1","public":1,"pass":true,"size":3}`,
+			wantErr: []string{
+				"  - name: is required",
+				`  - region: must be one of us-east-1, sa-east-1, got "eu-west-1"`,
+				"  - public: must be a bool, got a number",
+				"  - pass: must be a text, got a bool",
+				"  - tags: is required",
+				`Expected a JSON like {"name": "<text>", "region": "<us-east-1|sa-east-1>", "public": <true|false>, "pass": "<text>", "tags": ["<dev|prod>"], "size": "<text>"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputManager := NewInputManager(env.Resolvers{}, inputMock{}, inputMock{}, inputMock{}, inputMock{}, inputMock{}, inputMock{}, inputMock{}, false)
+			cmd := &exec.Cmd{Stdin: strings.NewReader(tt.stdin)}
+
+			err := inputManager.Inputs(cmd, setup, api.Stdin)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatalf("Inputs() got nil, want %q", tt.wantErr)
+				}
+				for _, e := range tt.wantErr {
+					if !strings.Contains(err.Error(), e) {
+						t.Errorf("Inputs() got %v, want %s", err, e)
+					}
+				}
+				if strings.Contains(err.Error(), "  - size") {
+					t.Errorf("Inputs() reported the number of a text input: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Inputs() got %v, want nil", err)
+			}
+			for _, e := range tt.want {
+				if !sliceutil.Contains(cmd.Env, e) {
+					t.Errorf("Inputs() env got %q, want %s", cmd.Env, e)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package runner
+
+import (
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+// validValue validates the value of the input informed by stdin or env
+func validValue(input formula.Input, v string) error {
+	if err := input.Validate(v); err != nil {
+		return prompt.NewError(err.Error())
+	}
+	return nil
+}
+
+// promptValid asks the input until its value is valid, the invalid ones are reported
+// and asked again
+func promptValid(input formula.Input, ask func() (string, error)) (string, error) {
+	for {
+		v, err := ask()
+		if err != nil {
+			return v, err
+		}
+		if err := input.Validate(v); err != nil {
+			prompt.Error(err.Error())
+			continue
+		}
+		return v, nil
+	}
+}
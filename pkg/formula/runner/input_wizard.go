@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	msgWizardStep  = "Step %d of %d"
+	msgWizardGroup = "Step %d of %d: %s"
+	msgWizardNav   = "Step %d of %d done"
+	wizardNext     = "Next step"
+	wizardRun      = "Run"
+	wizardBack     = "Back to the previous step"
+)
+
+// wizard asks the pages of inputs one at a time, showing the step of each one. After a page
+// the previous one can be asked again, dropping the answers of both, so that the inputs
+// depending on them are asked again too.
+func (d InputManager) wizard(cmd *exec.Cmd, setup formula.Setup, pages [][]formula.Input, envValues, values map[string]string) error {
+	marks := make([]int, len(pages))
+	for i := 0; i < len(pages); {
+		marks[i] = len(cmd.Env)
+		prompt.Info(wizardStep(i, pages))
+		for _, input := range pages[i] {
+			if err := d.promptInput(cmd, setup, input, envValues, values); err != nil {
+				return err
+			}
+		}
+
+		back, err := d.wizardBack(setup, i, len(pages))
+		if err != nil {
+			return err
+		} else if !back {
+			i++
+			continue
+		}
+
+		i--
+		cmd.Env = cmd.Env[:marks[i]]
+		for _, page := range pages[i:] {
+			for _, input := range page {
+				delete(values, input.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// wizardStep is the title of the page, its step and its group
+func wizardStep(i int, pages [][]formula.Input) string {
+	if group := pages[i][0].Group; group != "" {
+		return fmt.Sprintf(msgWizardGroup, i+1, len(pages), group)
+	}
+	return fmt.Sprintf(msgWizardStep, i+1, len(pages))
+}
+
+// wizardBack asks whether to go back to the previous page, the first page has none and
+// with the defaults accepted the wizard goes on. The next step is the default on idle.
+func (d InputManager) wizardBack(setup formula.Setup, i, steps int) (bool, error) {
+	if i == 0 || setup.AcceptDefaults {
+		return false, nil
+	}
+
+	next := wizardNext
+	if i == steps-1 {
+		next = wizardRun
+	}
+	prompt.SetIdle(setup.InputTimeout, true)
+	choice, err := d.List(fmt.Sprintf(msgWizardNav, i+1, steps), []string{next, wizardBack})
+	if err != nil {
+		return false, err
+	}
+	return choice == wizardBack, nil
+}
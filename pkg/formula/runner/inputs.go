@@ -2,7 +2,9 @@ package runner
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os/exec"
 	"strconv"
@@ -19,9 +21,15 @@ import (
 
 var ErrInputNotRecognized = prompt.NewError("terminal input not recognized")
 
+// InputManager resolves the formula inputs, requiredFirst prompts the required
+// inputs before the optional ones on every run, as Setup.RequiredFirst does for one run
 type InputManager struct {
-	envResolvers env.Resolvers
+	envResolvers  env.Resolvers
+	requiredFirst bool
 	prompt.InputList
+	prompt.InputMultiselect
+	prompt.InputPath
+	prompt.InputEditor
 	prompt.InputText
 	prompt.InputBool
 	prompt.InputPassword
@@ -30,19 +38,34 @@ type InputManager struct {
 func NewInputManager(
 	env env.Resolvers,
 	inList prompt.InputList,
+	inMulti prompt.InputMultiselect,
+	inPath prompt.InputPath,
+	inEditor prompt.InputEditor,
 	inText prompt.InputText,
 	inBool prompt.InputBool,
-	inPass prompt.InputPassword) InputManager {
+	inPass prompt.InputPassword,
+	requiredFirst bool) InputManager {
 	return InputManager{
-		envResolvers:  env,
-		InputList:     inList,
-		InputText:     inText,
-		InputBool:     inBool,
-		InputPassword: inPass,
+		envResolvers:     env,
+		requiredFirst:    requiredFirst,
+		InputList:        inList,
+		InputMultiselect: inMulti,
+		InputPath:        inPath,
+		InputEditor:      inEditor,
+		InputText:        inText,
+		InputBool:        inBool,
+		InputPassword:    inPass,
 	}
 }
 
 func (d InputManager) Inputs(cmd *exec.Cmd, setup formula.Setup, inputType api.TermInputType) error {
+	// the labels, tutorials and error texts are the ones of the locale of the user
+	inputs := make([]formula.Input, 0, len(setup.Config.Inputs))
+	for _, in := range setup.Config.Inputs {
+		inputs = append(inputs, in.Localize(prompt.Locale()))
+	}
+	setup.Config.Inputs = inputs
+
 	switch inputType {
 	case api.Prompt:
 		if err := d.fromPrompt(cmd, setup); err != nil {
@@ -68,12 +91,79 @@ func (d InputManager) fromStdin(cmd *exec.Cmd, setup formula.Setup) error {
 
 	config := setup.Config
 
+	// the JSON inputs take precedence over the env ones, every violation of the declared
+	// inputs is reported at once
+	warnUnknownStdin(config.Inputs, data)
+	envValues := envInputs(cmd, config.Inputs)
+	values := make(map[string]string)
+	var violations []string
 	for _, input := range config.Inputs {
+		if asked, err := input.Asked(values); err != nil {
+			return err
+		} else if !asked {
+			continue
+		}
+
+		if v, ok := envValues[input.Name]; ok && data[input.Name] == nil {
+			v, err := d.addEnvInput(cmd, setup, input, v)
+			if err != nil {
+				violations = append(violations, fmt.Sprintf(msgStdinViolation, input.Name, err))
+				continue
+			}
+			values[input.Name] = v
+			continue
+		}
+
 		var inputVal string
 		var err error
 		switch iType := input.Type; iType {
-		case "text", "bool":
-			inputVal = fmt.Sprintf("%v", data[input.Name])
+		case "text", "bool", "password", formula.SecretType, formula.IntType, formula.FloatType:
+			inputVal, err = stdinValue(input, data[input.Name], func() string {
+				return fromCommand(cmd, setup, input)
+			})
+		case formula.MultiselectType:
+			v, ok := data[input.Name]
+			if !ok && input.Required() {
+				err = errors.New(msgStdinRequired)
+				break
+			} else if !ok {
+				v = fromCommand(cmd, setup, input)
+			}
+			var selected []string
+			if selected, err = selection(input, v); err == nil {
+				values[input.Name] = addSelectionEnv(cmd, input, selected)
+				continue
+			}
+		case formula.PathType:
+			v, ok := data[input.Name]
+			if !ok {
+				v = input.Default
+			}
+			if _, isText := v.(string); !isText {
+				err = fmt.Errorf(msgStdinType, stdinKind(input), jsonKind(v))
+				break
+			}
+			inputVal, err = pathValue(input, setup.Pwd, v.(string))
+		case formula.CredentialType:
+			v, ok := data[input.Name]
+			if !ok {
+				v = input.Default
+			}
+			if provider, isText := v.(string); !isText {
+				err = fmt.Errorf(msgStdinType, stdinKind(input), jsonKind(v))
+			} else if provider == "" {
+				err = errors.New(msgStdinRequired)
+			} else if err = d.addCredentialEnv(cmd, input, provider); err == nil {
+				inputVal = provider
+			}
+		case formula.EditorType:
+			var content string
+			content, err = stdinValue(input, data[input.Name], func() string {
+				return fromCommand(cmd, setup, input)
+			})
+			if err == nil {
+				inputVal, err = editorValue(setup, input, content)
+			}
 		default:
 			inputVal, err = d.resolveIfReserved(input)
 			if err != nil {
@@ -82,10 +172,18 @@ func (d InputManager) fromStdin(cmd *exec.Cmd, setup formula.Setup) error {
 			}
 		}
 
+		if err != nil {
+			violations = append(violations, fmt.Sprintf(msgStdinViolation, input.Name, err))
+			continue
+		}
+		values[input.Name] = inputVal
 		if len(inputVal) != 0 {
 			addEnv(cmd, input.Name, inputVal)
 		}
 	}
+	if len(violations) > 0 {
+		return stdinError(config.Inputs, violations)
+	}
 	if len(config.Command) != 0 {
 		command := fmt.Sprintf(formula.EnvPattern, formula.CommandEnv, config.Command)
 		cmd.Env = append(cmd.Env, command)
@@ -95,52 +193,218 @@ func (d InputManager) fromStdin(cmd *exec.Cmd, setup formula.Setup) error {
 
 func (d InputManager) fromPrompt(cmd *exec.Cmd, setup formula.Setup) error {
 	config := setup.Config
-	for _, input := range config.Inputs {
-		var inputVal string
-		var valBool bool
-		items, err := loadItems(input, setup.FormulaPath)
-		if err != nil {
+	defer prompt.SetIdle(0, false)
+
+	// the inputs informed by env aren't prompted, nor the ones whose condition isn't met,
+	// the inputs of groups are asked by a wizard
+	envValues := envInputs(cmd, config.Inputs)
+	values := make(map[string]string)
+	pages := formula.Pages(config.Inputs)
+	for i, page := range pages {
+		if d.requiredFirst || setup.RequiredFirst {
+			pages[i] = formula.RequiredFirst(page)
+		}
+	}
+	if len(pages) > 1 {
+		if err := d.wizard(cmd, setup, pages, envValues, values); err != nil {
 			return err
 		}
-		switch iType := input.Type; iType {
-		case "text":
-			if items != nil {
-				inputVal, err = d.loadInputValList(items, input)
-			} else {
-				validate := input.Default == ""
-				inputVal, err = d.Text(input.Label, validate)
-				if inputVal == "" {
-					inputVal = input.Default
-				}
-			}
-		case "bool":
-			valBool, err = d.Bool(input.Label, items)
-			inputVal = strconv.FormatBool(valBool)
-		case "password":
-			inputVal, err = d.Password(input.Label)
-		default:
-			inputVal, err = d.resolveIfReserved(input)
-			if err != nil {
-				log.Fatalf("Fail to resolve input: %v, verify your credentials. [try using set credential]", input.Type)
+	} else if len(pages) == 1 {
+		for _, input := range pages[0] {
+			if err := d.promptInput(cmd, setup, input, envValues, values); err != nil {
+				return err
 			}
 		}
+	}
+	if len(config.Command) != 0 {
+		command := fmt.Sprintf(formula.EnvPattern, formula.CommandEnv, config.Command)
+		cmd.Env = append(cmd.Env, command)
+	}
+	return nil
+}
+
+// setIdle sets the idle timeout of the input prompt. The prompts with a default, a text
+// default or the first item of a list, accept it on timeout, the other ones fail with
+// Setup.InputTimeoutFail or keep waiting for an answer.
+func setIdle(setup formula.Setup, input formula.Input, items []string) {
+	if setup.InputTimeout <= 0 {
+		return
+	}
 
+	switch {
+	case hasDefault(input, items):
+		prompt.SetIdle(setup.InputTimeout, true)
+	case setup.InputTimeoutFail:
+		prompt.SetIdle(setup.InputTimeout, false)
+	default:
+		prompt.SetIdle(0, false)
+	}
+}
+
+// hasDefault tells whether the prompt of the input has an answer when enter is pressed
+func hasDefault(input formula.Input, items []string) bool {
+	switch input.Type {
+	case "text", formula.IntType, formula.FloatType:
+		return len(items) > 0 || input.Default != ""
+	case "bool":
+		return len(items) > 0
+	case formula.MultiselectType:
+		return !input.Required()
+	default:
+		return false
+	}
+}
+
+// promptInput asks the input and adds it to the env of the formula, its value is added to values
+func (d InputManager) promptInput(cmd *exec.Cmd, setup formula.Setup, input formula.Input, envValues, values map[string]string) error {
+	if asked, err := input.Asked(values); err != nil {
+		return err
+	} else if !asked {
+		return nil
+	}
+
+	if v, ok := envValues[input.Name]; ok {
+		v, err := d.addEnvInput(cmd, setup, input, v)
 		if err != nil {
 			return err
 		}
+		values[input.Name] = v
+		return nil
+	}
 
-		if len(inputVal) != 0 {
-			persistCache(setup.FormulaPath, inputVal, input, items)
-			addEnv(cmd, input.Name, inputVal)
+	var inputVal string
+	var valBool bool
+	var selected []string
+	input, err := formula.RenderInput(input, values)
+	if err != nil {
+		return err
+	}
+	input.Default = fromCommand(cmd, setup, input)
+	input.Items = itemsFrom(setup, input)
+	items, err := loadItems(input, setup.FormulaPath)
+	if err != nil {
+		return err
+	}
+	if input.Type == "bool" {
+		items = boolItems(input, items)
+	}
+	if v, ok := defaultValue(input, items); ok && setup.AcceptDefaults {
+		if v, err = d.addEnvInput(cmd, setup, input, v); err != nil {
+			return err
 		}
+		values[input.Name] = v
+		return nil
 	}
-	if len(config.Command) != 0 {
-		command := fmt.Sprintf(formula.EnvPattern, formula.CommandEnv, config.Command)
-		cmd.Env = append(cmd.Env, command)
+	if input.Tutorial != "" {
+		prompt.Print(input.Tutorial)
+	}
+	setIdle(setup, input, items)
+	switch iType := input.Type; iType {
+	case "text", formula.IntType, formula.FloatType:
+		if items != nil {
+			inputVal, err = d.loadInputValList(items, input)
+		} else {
+			inputVal, err = promptValid(input, func() (string, error) {
+				v, err := d.Text(input.Label, input.Default == "")
+				if v == "" {
+					v = input.Default
+				}
+				return v, err
+			})
+		}
+	case "bool":
+		valBool, err = d.Bool(input.Label, items)
+		inputVal = strconv.FormatBool(valBool)
+	case "password", formula.SecretType:
+		inputVal, err = promptValid(input, func() (string, error) {
+			return d.Password(input.Label)
+		})
+	case formula.MultiselectType:
+		selected, err = d.Multiselect(input.Label, items, splitSelection(input, input.Default), input.Required())
+	case formula.PathType:
+		if inputVal, err = d.Path(input.Label, setup.Pwd, input.Pattern); err == nil {
+			inputVal, err = pathValue(input, setup.Pwd, inputVal)
+		}
+	case formula.CredentialType:
+		var providers []string
+		if _, providers, err = d.credentialProviders(input); err != nil {
+			break
+		}
+		if inputVal, err = d.List(input.Label, providers); err == nil {
+			err = d.addCredentialEnv(cmd, input, inputVal)
+		}
+	case formula.EditorType:
+		if inputVal, err = d.Editor(input.Label, input.Default, input.Pattern, input.Required()); err == nil {
+			inputVal, err = editorValue(setup, input, inputVal)
+		}
+	default:
+		inputVal, err = d.resolveIfReserved(input)
+		if err != nil {
+			log.Fatalf("Fail to resolve input: %v, verify your credentials. [try using set credential]", input.Type)
+		}
+	}
+
+	if errors.Is(err, prompt.ErrInputIdle) {
+		return fmt.Errorf("%s: %w", input.Name, err)
+	} else if err != nil {
+		return err
+	}
+
+	if input.Type == formula.MultiselectType {
+		values[input.Name] = addSelectionEnv(cmd, input, selected)
+		return nil
+	}
+
+	values[input.Name] = inputVal
+	if len(inputVal) != 0 {
+		persistCache(setup.FormulaPath, inputVal, input, items)
+		addEnv(cmd, input.Name, inputVal)
 	}
 	return nil
 }
 
+// defaultValue returns the value the prompt of the input takes when enter is pressed, for
+// the inputs with one: the default or the first item of a list, the default items of a
+// multiselect or the default of an editor, path or credential input
+func defaultValue(input formula.Input, items []string) (string, bool) {
+	switch input.Type {
+	case "text", formula.IntType, formula.FloatType:
+		if len(items) > 0 {
+			return items[0], true
+		}
+		return input.Default, input.Default != ""
+	case "bool":
+		if input.Default == "" && len(input.Items) == 0 {
+			return "", false
+		}
+		return strconv.FormatBool(prompt.BoolItem(items[0])), true
+	case formula.MultiselectType:
+		return input.Default, !input.Required()
+	case formula.EditorType, formula.PathType, formula.CredentialType:
+		return input.Default, input.Default != ""
+	default:
+		return "", false
+	}
+}
+
+// boolItems returns the items of a bool prompt, true and false when the input has none,
+// its default first so that it is the one selected
+func boolItems(input formula.Input, items []string) []string {
+	if len(items) == 0 {
+		items = []string{"true", "false"}
+	}
+
+	ordered := []string{}
+	for _, item := range items {
+		if item == input.Default {
+			ordered = append([]string{item}, ordered...)
+		} else {
+			ordered = append(ordered, item)
+		}
+	}
+	return ordered
+}
+
 // addEnv Add environment variable to run formulas.
 // add the variable inName=inValue to cmd.Env
 func addEnv(cmd *exec.Cmd, inName, inValue string) {
@@ -148,9 +412,76 @@ func addEnv(cmd *exec.Cmd, inName, inValue string) {
 	cmd.Env = append(cmd.Env, e)
 }
 
+// keepInputs keeps the values the inputs were answered with, on the formula env, on def.Inputs
+// when it isn't nil. The content of an editor input is kept instead of the path of its file,
+// as its file is removed with the temp workspace of the run.
+func keepInputs(def formula.Definition, env []string, inputs []formula.Input) {
+	if def.Inputs == nil {
+		return
+	}
+
+	for _, in := range inputs {
+		prefix := strings.ToUpper(in.Name) + "="
+		for _, e := range env {
+			if !strings.HasPrefix(e, prefix) {
+				continue
+			}
+
+			v := strings.TrimPrefix(e, prefix)
+			if in.Type == formula.EditorType {
+				b, err := ioutil.ReadFile(v)
+				if err != nil {
+					continue
+				}
+				v = string(b)
+			}
+			def.Inputs[in.Name] = v
+		}
+	}
+}
+
+// addEnvInput adds the input informed by its RIT_INPUT_ env var, returning its value
+func (d InputManager) addEnvInput(cmd *exec.Cmd, setup formula.Setup, input formula.Input, v string) (string, error) {
+	switch input.Type {
+	case formula.CredentialType:
+		if err := d.addCredentialEnv(cmd, input, v); err != nil {
+			return "", err
+		}
+		addEnv(cmd, input.Name, v)
+		return v, nil
+	case formula.PathType:
+		p, err := pathValue(input, setup.Pwd, v)
+		if err != nil {
+			return "", err
+		}
+		addEnv(cmd, input.Name, p)
+		return p, nil
+	case formula.MultiselectType:
+		selected, err := selection(input, v)
+		if err != nil {
+			return "", err
+		}
+		return addSelectionEnv(cmd, input, selected), nil
+	case formula.EditorType:
+		p, err := editorValue(setup, input, v)
+		if err != nil {
+			return "", err
+		}
+		addEnv(cmd, input.Name, p)
+		return p, nil
+	}
+
+	v, err := envValue(input, v)
+	if err != nil {
+		return "", err
+	}
+	addEnv(cmd, input.Name, v)
+	return v, nil
+}
+
 func persistCache(formulaPath, inputVal string, input formula.Input, items []string) {
 	cachePath := fmt.Sprintf(formula.CachePattern, formulaPath, strings.ToUpper(input.Name))
-	if input.Cache.Active {
+	if cacheable(input) {
 		if items == nil {
 			items = []string{inputVal}
 		} else {
@@ -181,7 +512,7 @@ func persistCache(formulaPath, inputVal string, input formula.Input, items []str
 
 func (d InputManager) loadInputValList(items []string, input formula.Input) (string, error) {
 	newLabel := formula.DefaultCacheNewLabel
-	if input.Cache.Active {
+	if cacheable(input) {
 		if input.Cache.NewLabel != "" {
 			newLabel = input.Cache.NewLabel
 		}
@@ -198,10 +529,13 @@ func (d InputManager) loadInputValList(items []string, input formula.Input) (str
 	return inputVal, err
 }
 
+// loadItems returns the items of the input prompt, the cached ones first, the items read
+// from itemsFrom aren't cached as they change between the runs. The expired cached items
+// are replaced by the declared ones.
 func loadItems(input formula.Input, formulaPath string) ([]string, error) {
-	if input.Cache.Active {
+	if cacheable(input) && input.ItemsFrom == nil {
 		cachePath := fmt.Sprintf(formula.CachePattern, formulaPath, strings.ToUpper(input.Name))
-		if fileutil.Exists(cachePath) {
+		if fileutil.Exists(cachePath) && !cacheExpired(input, cachePath) {
 			fileBytes, err := fileutil.ReadFile(cachePath)
 			if err != nil {
 				return nil, err
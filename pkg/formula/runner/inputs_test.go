@@ -2,16 +2,22 @@ package runner
 
 import (
 	"errors"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ZupIT/ritchie-cli/pkg/api"
 	"github.com/ZupIT/ritchie-cli/pkg/env"
 	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/slice/sliceutil"
 )
 
 func TestInputManager_Inputs(t *testing.T) {
@@ -105,7 +111,7 @@ func TestInputManager_Inputs(t *testing.T) {
 			iBool := tt.in.iBool
 			iPass := tt.in.iPass
 
-			inputManager := NewInputManager(resolvers, iList, iText, iBool, iPass)
+			inputManager := NewInputManager(resolvers, iList, iList, iText, inputMock{}, iText, iBool, iPass, false)
 
 			cmd := &exec.Cmd{}
 			if tt.in.inType == api.Stdin {
@@ -120,3 +126,591 @@ func TestInputManager_Inputs(t *testing.T) {
 		})
 	}
 }
+
+// promptOrderMock records the labels of the prompts in the order they are asked
+type promptOrderMock struct {
+	inputMock
+	labels *[]string
+}
+
+func (p promptOrderMock) Text(label string, _ bool, _ ...string) (string, error) {
+	*p.labels = append(*p.labels, label)
+	return "value", nil
+}
+
+func (p promptOrderMock) Password(label string) (string, error) {
+	*p.labels = append(*p.labels, label)
+	return "value", nil
+}
+
+func TestInputManager_RequiredFirst(t *testing.T) {
+	inputs := []formula.Input{
+		{Name: "region", Type: "text", Label: "region", Default: "sa-east-1"},
+		{Name: "name", Type: "text", Label: "name"},
+		{Name: "pass", Type: "password", Label: "pass"},
+	}
+
+	tests := []struct {
+		name          string
+		requiredFirst bool
+		setup         bool
+		want          []string
+	}{
+		{name: "config order", want: []string{"region", "name", "pass"}},
+		{name: "required first by config", requiredFirst: true, want: []string{"name", "pass", "region"}},
+		{name: "required first by run", setup: true, want: []string{"name", "pass", "region"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var labels []string
+			p := promptOrderMock{labels: &labels}
+			inputManager := NewInputManager(env.Resolvers{}, p, p, p, inputMock{}, p, p, p, tt.requiredFirst)
+			setup := formula.Setup{Config: formula.Config{Inputs: inputs}, RequiredFirst: tt.setup}
+
+			if err := inputManager.Inputs(&exec.Cmd{}, setup, api.Prompt); err != nil {
+				t.Fatalf("Inputs error = %v", err)
+			}
+			if !reflect.DeepEqual(labels, tt.want) {
+				t.Errorf("Inputs asked %v, want %v", labels, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasDefault(t *testing.T) {
+	tests := []struct {
+		name  string
+		input formula.Input
+		items []string
+		want  bool
+	}{
+		{name: "text with default", input: formula.Input{Type: "text", Default: "sa-east-1"}, want: true},
+		{name: "text without default", input: formula.Input{Type: "text"}},
+		{name: "list", input: formula.Input{Type: "text"}, items: []string{"dev", "prod"}, want: true},
+		{name: "bool", input: formula.Input{Type: "bool"}, items: []string{"yes", "no"}, want: true},
+		{name: "password", input: formula.Input{Type: "password", Default: "ignored"}},
+		{name: "multiselect with default", input: formula.Input{Type: "multiselect", Default: "us-east-1"}, want: true},
+		{name: "multiselect without default", input: formula.Input{Type: "multiselect"}, items: []string{"us-east-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasDefault(tt.input, tt.items); got != tt.want {
+				t.Errorf("hasDefault got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInputManager_InputIdle(t *testing.T) {
+	p := inputMock{err: prompt.ErrInputIdle}
+	inputManager := NewInputManager(env.Resolvers{}, p, p, p, p, p, p, p, false)
+	setup := formula.Setup{
+		Config:           formula.Config{Inputs: []formula.Input{{Name: "name", Type: "text", Label: "name"}}},
+		InputTimeout:     time.Second,
+		InputTimeoutFail: true,
+	}
+
+	err := inputManager.Inputs(&exec.Cmd{}, setup, api.Prompt)
+	if !errors.Is(err, prompt.ErrInputIdle) || !strings.HasPrefix(err.Error(), "name: ") {
+		t.Errorf("Inputs got %v, want %v of the input name", err, prompt.ErrInputIdle)
+	}
+}
+
+func TestInputManager_Multiselect(t *testing.T) {
+	regions := formula.Input{Name: "regions", Type: "multiselect", Label: "regions", Items: []string{"us-east-1", "sa-east-1", "eu-west-1"}}
+	services := formula.Input{Name: "services", Type: "multiselect", Label: "services", Items: []string{"s3", "ec2"}, Delimiter: " ", Default: "s3"}
+	setup := formula.Setup{Config: formula.Config{Inputs: []formula.Input{regions, services}}}
+
+	tests := []struct {
+		name      string
+		inputType api.TermInputType
+		stdin     string
+		env       []string
+		want      []string
+		wantErr   string
+	}{
+		{
+			name:      "prompt",
+			inputType: api.Prompt,
+			want:      []string{"REGIONS=us-east-1,sa-east-1", `REGIONS_JSON=["us-east-1","sa-east-1"]`, "SERVICES=us-east-1 sa-east-1"},
+		},
+		{
+			name:      "stdin array and default",
+			inputType: api.Stdin,
+			stdin:     `{"regions":["eu-west-1","sa-east-1"]}`,
+			want:      []string{"REGIONS=eu-west-1,sa-east-1", `REGIONS_JSON=["eu-west-1","sa-east-1"]`, "SERVICES=s3", `SERVICES_JSON=["s3"]`},
+		},
+		{
+			name:      "stdin delimited text",
+			inputType: api.Stdin,
+			stdin:     `{"regions":"us-east-1, eu-west-1","services":""}`,
+			want:      []string{"REGIONS=us-east-1,eu-west-1", "SERVICES=", "SERVICES_JSON=[]"},
+		},
+		{
+			name:      "env",
+			inputType: api.Prompt,
+			env:       []string{"RIT_INPUT_REGIONS=sa-east-1", `RIT_INPUT_SERVICES=["ec2","s3"]`},
+			want:      []string{"REGIONS=sa-east-1", `REGIONS_JSON=["sa-east-1"]`, "SERVICES=ec2 s3"},
+		},
+		{
+			name:      "unknown item",
+			inputType: api.Stdin,
+			stdin:     `{"regions":["ap-south-1"]}`,
+			wantErr:   `the multiselect input regions has no item "ap-south-1", choose among us-east-1, sa-east-1, eu-west-1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := inputMock{selected: []string{"us-east-1", "sa-east-1"}}
+			inputManager := NewInputManager(env.Resolvers{}, p, p, p, p, p, p, p, false)
+			cmd := &exec.Cmd{Env: tt.env, Stdin: strings.NewReader(tt.stdin)}
+
+			err := inputManager.Inputs(cmd, setup, tt.inputType)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Inputs() got %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Inputs() got %v, want nil", err)
+			}
+			for _, e := range tt.want {
+				if !sliceutil.Contains(cmd.Env, e) {
+					t.Errorf("Inputs() env got %q, want %s", cmd.Env, e)
+				}
+			}
+		})
+	}
+}
+
+func TestInputManager_Condition(t *testing.T) {
+	useDatabase := formula.Input{Name: "useDatabase", Type: "bool", Label: "database?", Items: []string{"false", "true"}}
+	dbName := formula.Input{Name: "dbName", Type: "text", Label: "database name", Condition: &formula.Condition{Variable: "useDatabase", Operator: "==", Value: "true"}}
+	setup := formula.Setup{Config: formula.Config{Inputs: []formula.Input{useDatabase, dbName}}}
+
+	tests := []struct {
+		name      string
+		inputType api.TermInputType
+		boolean   bool
+		stdin     string
+		want      []string
+		wantNot   string
+	}{
+		{name: "prompt condition met", inputType: api.Prompt, boolean: true, want: []string{"USEDATABASE=true", "DBNAME=orders"}},
+		{name: "prompt condition not met", inputType: api.Prompt, want: []string{"USEDATABASE=false"}, wantNot: "DBNAME"},
+		{name: "stdin condition met", inputType: api.Stdin, stdin: `{"useDatabase":true,"dbName":"orders"}`, want: []string{"USEDATABASE=true", "DBNAME=orders"}},
+		{name: "stdin condition not met", inputType: api.Stdin, stdin: `{"useDatabase":false,"dbName":"orders"}`, want: []string{"USEDATABASE=false"}, wantNot: "DBNAME"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := inputMock{text: "orders", boolean: tt.boolean}
+			inputManager := NewInputManager(env.Resolvers{}, p, p, p, p, p, p, p, false)
+			cmd := &exec.Cmd{Stdin: strings.NewReader(tt.stdin)}
+
+			if err := inputManager.Inputs(cmd, setup, tt.inputType); err != nil {
+				t.Fatalf("Inputs() got %v, want nil", err)
+			}
+			for _, e := range tt.want {
+				if !sliceutil.Contains(cmd.Env, e) {
+					t.Errorf("Inputs() env got %q, want %s", cmd.Env, e)
+				}
+			}
+			for _, e := range cmd.Env {
+				if tt.wantNot != "" && strings.HasPrefix(e, tt.wantNot+"=") {
+					t.Errorf("Inputs() env got %q, want no %s", cmd.Env, tt.wantNot)
+				}
+			}
+		})
+	}
+}
+
+func TestInputManager_Validation(t *testing.T) {
+	min := 1.0
+	replicas := formula.Input{Name: "replicas", Type: "text", Label: "replicas", Pattern: `^\d+$`, Min: &min}
+	setup := formula.Setup{Config: formula.Config{Inputs: []formula.Input{replicas}}}
+
+	tests := []struct {
+		name      string
+		inputType api.TermInputType
+		stdin     string
+		env       []string
+		want      string
+		wantErr   string
+	}{
+		{name: "prompt", inputType: api.Prompt, want: "REPLICAS=3"},
+		{name: "stdin", inputType: api.Stdin, stdin: `{"replicas":"2"}`, want: "REPLICAS=2"},
+		{name: "stdin below min", inputType: api.Stdin, stdin: `{"replicas":"0"}`, wantErr: "the value of the input replicas is less than 1"},
+		{name: "env mismatching pattern", inputType: api.Prompt, env: []string{"RIT_INPUT_REPLICAS=two"}, wantErr: `the value of the input replicas doesn't match ^\d+$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := inputMock{text: "3"}
+			inputManager := NewInputManager(env.Resolvers{}, p, p, p, p, p, p, p, false)
+			cmd := &exec.Cmd{Env: tt.env, Stdin: strings.NewReader(tt.stdin)}
+
+			err := inputManager.Inputs(cmd, setup, tt.inputType)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Inputs() got %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !sliceutil.Contains(cmd.Env, tt.want) {
+				t.Errorf("Inputs() got %q, %v, want %s", cmd.Env, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestInputManager_Secret(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	apiKey := formula.Input{Name: "apiKey", Type: formula.SecretType, Label: "API key", Cache: formula.Cache{Active: true}}
+	setup := formula.Setup{FormulaPath: dir, Config: formula.Config{Inputs: []formula.Input{apiKey}}}
+	p := inputMock{text: "s3cr3t"}
+	inputManager := NewInputManager(env.Resolvers{}, p, p, p, p, p, p, p, false)
+	cmd := &exec.Cmd{}
+
+	if err := inputManager.Inputs(cmd, setup, api.Prompt); err != nil {
+		t.Fatalf("Inputs() got %v, want nil", err)
+	}
+	if !sliceutil.Contains(cmd.Env, "APIKEY=s3cr3t") {
+		t.Errorf("Inputs() env got %q, want APIKEY=s3cr3t", cmd.Env)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".APIKEY.cache")); !os.IsNotExist(err) {
+		t.Errorf("Inputs() cached the secret input, stat got %v", err)
+	}
+	if got := secretValues(cmd.Env, setup.Config.Inputs); !reflect.DeepEqual(got, []string{"s3cr3t"}) {
+		t.Errorf("secretValues() got %q, want the secret masked", got)
+	}
+}
+
+func TestInputManager_Editor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-editor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifest := formula.Input{Name: "manifest", Type: formula.EditorType, Label: "Manifest", Pattern: "*.yaml"}
+	setup := formula.Setup{TmpDir: dir, Config: formula.Config{Inputs: []formula.Input{manifest}}}
+	file := filepath.Join(dir, editorDirName, "manifest.yaml")
+	tests := []struct {
+		name      string
+		inputType api.TermInputType
+		env       []string
+		stdin     string
+		want      string
+	}{
+		{name: "prompt", inputType: api.Prompt, want: "kind: Pod\nmetadata:\n  name: web\n"},
+		{name: "env", inputType: api.Prompt, env: []string{"RIT_INPUT_MANIFEST=kind: Service\n"}, want: "kind: Service\n"},
+		{name: "stdin", inputType: api.Stdin, stdin: `{"manifest": "kind: Job\n"}`, want: "kind: Job\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := inputMock{text: "kind: Pod\nmetadata:\n  name: web\n"}
+			inputManager := NewInputManager(env.Resolvers{}, p, p, p, p, p, p, p, false)
+			cmd := &exec.Cmd{Env: tt.env, Stdin: strings.NewReader(tt.stdin)}
+
+			if err := inputManager.Inputs(cmd, setup, tt.inputType); err != nil {
+				t.Fatalf("Inputs() got %v, want nil", err)
+			}
+			if !sliceutil.Contains(cmd.Env, "MANIFEST="+file) {
+				t.Errorf("Inputs() env got %q, want MANIFEST=%s", cmd.Env, file)
+			}
+			if got, err := ioutil.ReadFile(file); err != nil || string(got) != tt.want {
+				t.Errorf("Inputs() file got %q, %v, want %q", got, err, tt.want)
+			}
+			if got := secretValues(cmd.Env, setup.Config.Inputs); len(got) != 0 {
+				t.Errorf("secretValues() got %q, want the file path shown", got)
+			}
+		})
+	}
+}
+
+func TestCheckEditorInputs(t *testing.T) {
+	setup := formula.Setup{Config: formula.Config{Inputs: []formula.Input{{Name: "name", Type: "text"}}}}
+	if err := checkEditorInputs(setup, "--ssh"); err != nil {
+		t.Errorf("checkEditorInputs() without editor inputs got %v, want nil", err)
+	}
+
+	setup.Config.Inputs = append(setup.Config.Inputs, formula.Input{Name: "manifest", Type: formula.EditorType})
+	if err := checkEditorInputs(setup, "--ssh"); err == nil || !strings.Contains(err.Error(), "manifest") {
+		t.Errorf("checkEditorInputs() got %v, want the error of the input manifest", err)
+	}
+}
+
+func TestKeepInputs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-keep")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "manifest.yaml")
+	_ = ioutil.WriteFile(file, []byte("kind: Pod\n"), 0644)
+
+	inputs := []formula.Input{
+		{Name: "region", Type: "text"},
+		{Name: "manifest", Type: formula.EditorType},
+		{Name: "public", Type: "bool"},
+	}
+	env := []string{"REGION=us-east-1", "PWD=/home", "REGION=sa-east-1", "MANIFEST=" + file}
+
+	keepInputs(formula.Definition{}, env, inputs)
+
+	def := formula.Definition{Inputs: map[string]string{}}
+	keepInputs(def, env, inputs)
+	want := map[string]string{"region": "sa-east-1", "manifest": "kind: Pod\n"}
+	if !reflect.DeepEqual(def.Inputs, want) {
+		t.Errorf("keepInputs() got %q, want %q", def.Inputs, want)
+	}
+}
+
+func TestInputManager_Typed(t *testing.T) {
+	replicas := formula.Input{Name: "replicas", Type: formula.IntType, Label: "replicas"}
+	ratio := formula.Input{Name: "ratio", Type: formula.FloatType, Label: "ratio", Default: "0.5"}
+	setup := formula.Setup{Config: formula.Config{Inputs: []formula.Input{replicas, ratio}}}
+
+	tests := []struct {
+		name      string
+		inputType api.TermInputType
+		stdin     string
+		env       []string
+		want      []string
+		wantErr   string
+	}{
+		{name: "stdin numbers", inputType: api.Stdin, stdin: `{"replicas": 3, "ratio": 0.25}`, want: []string{"REPLICAS=3", "RATIO=0.25"}},
+		{name: "stdin default", inputType: api.Stdin, stdin: `{"replicas": "3"}`, want: []string{"REPLICAS=3", "RATIO=0.5"}},
+		{name: "stdin not an integer", inputType: api.Stdin, stdin: `{"replicas": 2.5}`, wantErr: "replicas: the value of the input replicas is not an integer"},
+		{name: "stdin bool", inputType: api.Stdin, stdin: `{"replicas": true}`, wantErr: "replicas: must be an integer, got a bool"},
+		{name: "env not a number", inputType: api.Prompt, env: []string{"RIT_INPUT_RATIO=half"}, wantErr: "the value of the input ratio is not a number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := inputMock{text: "3"}
+			inputManager := NewInputManager(env.Resolvers{}, p, p, p, p, p, p, p, false)
+			cmd := &exec.Cmd{Env: tt.env, Stdin: strings.NewReader(tt.stdin)}
+
+			err := inputManager.Inputs(cmd, setup, tt.inputType)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Inputs() got %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(cmd.Env, tt.want) {
+				t.Errorf("Inputs() got %q, %v, want %q", cmd.Env, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoolItems(t *testing.T) {
+	tests := []struct {
+		name  string
+		input formula.Input
+		items []string
+		want  []string
+	}{
+		{name: "no items", input: formula.Input{Type: "bool"}, want: []string{"true", "false"}},
+		{name: "default false", input: formula.Input{Type: "bool", Default: "false"}, want: []string{"false", "true"}},
+		{name: "default item", input: formula.Input{Type: "bool", Default: "no"}, items: []string{"yes", "no"}, want: []string{"no", "yes"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := boolItems(tt.input, tt.items); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("boolItems() got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+type selectorMock struct {
+	providers []string
+	fields    map[string]map[string]string
+}
+
+func (s selectorMock) Resolve(string) (string, error) {
+	return "", nil
+}
+
+func (s selectorMock) List() ([]string, error) {
+	return s.providers, nil
+}
+
+func (s selectorMock) ResolveAll(provider string) (map[string]string, error) {
+	return s.fields[provider], nil
+}
+
+func TestInputManager_Credential(t *testing.T) {
+	git := formula.Input{Name: "git", Type: formula.CredentialType, Label: "Git account", Items: []string{"github"}}
+	setup := formula.Setup{Config: formula.Config{Inputs: []formula.Input{git}}}
+	resolvers := env.Resolvers{env.Credential: selectorMock{
+		providers: []string{"aws", "github", "github-work", "githubber"},
+		fields: map[string]map[string]string{
+			"github":      {"token": "ghp_home", "username": "dev"},
+			"github-work": {"token": "ghp_work", "username": "dev-zup"},
+		},
+	}}
+
+	tests := []struct {
+		name      string
+		inputType api.TermInputType
+		resolvers env.Resolvers
+		stdin     string
+		env       []string
+		want      []string
+		wantErr   string
+	}{
+		{name: "prompt", inputType: api.Prompt, resolvers: resolvers, env: []string{"GIT_PAGER=cat"}, want: []string{"GIT_PAGER=cat", "GIT_TOKEN=ghp_work", "GIT_USERNAME=dev-zup", "RIT_CREDENTIAL_FIELDS=GIT_TOKEN,GIT_USERNAME", "GIT=github-work"}},
+		{name: "stdin", inputType: api.Stdin, resolvers: resolvers, stdin: `{"git": "github"}`, want: []string{"GIT_TOKEN=ghp_home", "GIT_USERNAME=dev", "RIT_CREDENTIAL_FIELDS=GIT_TOKEN,GIT_USERNAME", "GIT=github"}},
+		{name: "stdin unmatched provider", inputType: api.Stdin, resolvers: resolvers, stdin: `{"git": "aws"}`, wantErr: `the credential input git has no provider "aws", choose among github, github-work`},
+		{name: "stdin missing", inputType: api.Stdin, resolvers: resolvers, stdin: `{}`, wantErr: "git: is required"},
+		{name: "env", inputType: api.Prompt, resolvers: resolvers, env: []string{"RIT_INPUT_GIT=github"}, want: []string{"RIT_INPUT_GIT=github", "GIT_TOKEN=ghp_home", "GIT_USERNAME=dev", "RIT_CREDENTIAL_FIELDS=GIT_TOKEN,GIT_USERNAME", "GIT=github"}},
+		{name: "no selector", inputType: api.Prompt, resolvers: env.Resolvers{}, wantErr: ErrCredentialSelector.Error()},
+		{name: "no credential", inputType: api.Prompt, resolvers: env.Resolvers{env.Credential: selectorMock{providers: []string{"aws"}}}, wantErr: "no credential to select for the input git"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := inputMock{text: "github-work"}
+			inputManager := NewInputManager(tt.resolvers, p, p, p, p, p, p, p, false)
+			cmd := &exec.Cmd{Env: tt.env, Stdin: strings.NewReader(tt.stdin)}
+
+			err := inputManager.Inputs(cmd, setup, tt.inputType)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Inputs() got %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(cmd.Env, tt.want) {
+				t.Errorf("Inputs() got %q, %v, want %q", cmd.Env, err, tt.want)
+			}
+			if got := secretValues(cmd.Env, setup.Config.Inputs); len(got) != 2 || sliceutil.Contains(got, "github") || sliceutil.Contains(got, "cat") {
+				t.Errorf("secretValues() got %q, want the credential fields only", got)
+			}
+		})
+	}
+}
+
+func TestInputManager_AcceptDefaults(t *testing.T) {
+	inputs := []formula.Input{
+		{Name: "region", Type: "text", Label: "region", Default: "us-east-1"},
+		{Name: "replicas", Type: formula.IntType, Label: "replicas"},
+		{Name: "public", Type: "bool", Label: "public", Default: "false"},
+		{Name: "confirm", Type: "bool", Label: "confirm"},
+		{Name: "tier", Type: "text", Label: "tier", Items: []string{"free", "pro"}},
+		{Name: "zones", Type: formula.MultiselectType, Label: "zones", Items: []string{"a", "b"}, Default: "b"},
+	}
+
+	tests := []struct {
+		name           string
+		acceptDefaults bool
+		want           []string
+	}{
+		{
+			name:           "defaults accepted",
+			acceptDefaults: true,
+			want:           []string{"REGION=us-east-1", "REPLICAS=3", "PUBLIC=false", "CONFIRM=true", "TIER=free", "ZONES=b", `ZONES_JSON=["b"]`},
+		},
+		{
+			name: "defaults asked",
+			want: []string{"REGION=3", "REPLICAS=3", "PUBLIC=true", "CONFIRM=true", "TIER=3", "ZONES=a", `ZONES_JSON=["a"]`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := formula.Setup{Config: formula.Config{Inputs: inputs}, AcceptDefaults: tt.acceptDefaults}
+			p := inputMock{text: "3", boolean: true, selected: []string{"a"}}
+			inputManager := NewInputManager(env.Resolvers{}, p, p, p, p, p, p, p, false)
+			cmd := &exec.Cmd{}
+
+			if err := inputManager.Inputs(cmd, setup, api.Prompt); err != nil {
+				t.Fatalf("Inputs() got %v, want nil", err)
+			}
+			if !reflect.DeepEqual(cmd.Env, tt.want) {
+				t.Errorf("Inputs() env got %q, want %q", cmd.Env, tt.want)
+			}
+		})
+	}
+}
+
+// scriptedMock answers the text and list prompts in order
+type scriptedMock struct {
+	inputMock
+	texts *[]string
+	lists *[]string
+}
+
+func (s scriptedMock) Text(string, bool, ...string) (string, error) {
+	v := (*s.texts)[0]
+	*s.texts = (*s.texts)[1:]
+	return v, nil
+}
+
+func (s scriptedMock) List(string, []string) (string, error) {
+	v := (*s.lists)[0]
+	*s.lists = (*s.lists)[1:]
+	return v, nil
+}
+
+func TestInputManager_Wizard(t *testing.T) {
+	setup := formula.Setup{Config: formula.Config{Inputs: []formula.Input{
+		{Name: "host", Type: "text", Label: "host", Group: "Database"},
+		{Name: "port", Type: formula.IntType, Label: "port", Group: "Database"},
+		{Name: "name", Type: "text", Label: "name", Group: "App"},
+	}}}
+
+	tests := []struct {
+		name  string
+		texts []string
+		lists []string
+		want  []string
+	}{
+		{
+			name:  "run",
+			texts: []string{"db", "5432", "orders"},
+			lists: []string{wizardRun},
+			want:  []string{"HOST=db", "PORT=5432", "NAME=orders"},
+		},
+		{
+			name:  "back to the previous step",
+			texts: []string{"db", "5432", "orders", "db.prod", "5433", "payments"},
+			lists: []string{wizardBack, wizardRun},
+			want:  []string{"HOST=db.prod", "PORT=5433", "NAME=payments"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := scriptedMock{texts: &tt.texts, lists: &tt.lists}
+			inputManager := NewInputManager(env.Resolvers{}, p, p, p, p, p, p, p, false)
+			cmd := &exec.Cmd{}
+
+			if err := inputManager.Inputs(cmd, setup, api.Prompt); err != nil {
+				t.Fatalf("Inputs() got %v, want nil", err)
+			}
+			if !reflect.DeepEqual(cmd.Env, tt.want) {
+				t.Errorf("Inputs() env got %q, want %q", cmd.Env, tt.want)
+			}
+			if len(tt.texts) != 0 || len(tt.lists) != 0 {
+				t.Errorf("Inputs() left the answers %q and %q", tt.texts, tt.lists)
+			}
+		})
+	}
+}
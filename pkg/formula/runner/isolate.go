@@ -0,0 +1,112 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+var ErrIsolationOutsidePwd = prompt.NewError("the isolation inputs and outputs of the formula must be inside the working directory")
+
+// overlay is the temp workspace of an isolated run, the formula runs on it
+// instead of the working directory, which only gets the declared outputs back
+type overlay struct {
+	pwd     string
+	dir     string
+	outputs []string
+}
+
+// isolate moves the run of an isolated formula to an overlay workspace with a copy of its
+// inputs, setup.Pwd becomes the overlay dir. It returns nil when the run isn't isolated.
+func isolate(def formula.Definition, setup *formula.Setup) (*overlay, error) {
+	if !def.Isolate {
+		return nil, nil
+	}
+
+	var iso formula.Isolation
+	if setup.Config.Isolation != nil {
+		iso = *setup.Config.Isolation
+	}
+
+	dir, err := ioutil.TempDir("", "rit-isolated-")
+	if err != nil {
+		return nil, err
+	}
+
+	o := &overlay{pwd: setup.Pwd, dir: dir, outputs: iso.Outputs}
+	if err := copyMatches(setup.Pwd, dir, iso.Inputs); err != nil {
+		o.remove()
+		return nil, err
+	}
+
+	setup.Pwd = dir
+	return o, nil
+}
+
+// commit copies the outputs of a successful run back to the working directory
+func (o *overlay) commit() error {
+	if o == nil {
+		return nil
+	}
+	return copyMatches(o.dir, o.pwd, o.outputs)
+}
+
+// remove deletes the overlay workspace, the changes not committed are discarded
+func (o *overlay) remove() {
+	if o == nil {
+		return
+	}
+	_ = fileutil.RemoveDir(o.dir)
+}
+
+// copyMatches copies the files and dirs of src matching the glob patterns to dst, keeping their relative paths
+func copyMatches(src, dst string, patterns []string) error {
+	for _, p := range patterns {
+		matches, err := filepath.Glob(filepath.Join(src, p))
+		if err != nil {
+			return err
+		}
+
+		for _, m := range matches {
+			rel, err := filepath.Rel(src, m)
+			if err != nil {
+				return err
+			}
+			if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return ErrIsolationOutsidePwd
+			}
+
+			if err := copyPath(m, filepath.Join(dst, rel)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func copyPath(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		if err := fileutil.CreateDirIfNotExists(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := fileutil.Copy(src, dst); err != nil {
+			return err
+		}
+		return os.Chmod(dst, info.Mode())
+	}
+
+	if err := fileutil.CreateDirIfNotExists(dst, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return fileutil.CopyDirectory(src, dst)
+}
@@ -0,0 +1,76 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestIsolate(t *testing.T) {
+	pwd, err := ioutil.TempDir("", "rit-isolate-pwd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(pwd)
+
+	_ = os.MkdirAll(filepath.Join(pwd, "src"), 0755)
+	_ = ioutil.WriteFile(filepath.Join(pwd, "src", "main.tf"), []byte("source"), 0644)
+	_ = ioutil.WriteFile(filepath.Join(pwd, "notes.txt"), []byte("notes"), 0644)
+
+	setup := formula.Setup{
+		Pwd: pwd,
+		Config: formula.Config{Isolation: &formula.Isolation{
+			Inputs:  []string{"src", "missing.txt"},
+			Outputs: []string{"dist/*"},
+		}},
+	}
+
+	if o, err := isolate(formula.Definition{}, &setup); o != nil || err != nil || setup.Pwd != pwd {
+		t.Fatalf("isolate of a run without --isolate got %v, %v, want the working directory kept", o, err)
+	}
+
+	o, err := isolate(formula.Definition{Isolate: true}, &setup)
+	if err != nil {
+		t.Fatalf("isolate got %v, want nil", err)
+	}
+	if setup.Pwd == pwd {
+		t.Fatal("isolate must move the run to the overlay")
+	}
+	if b, _ := ioutil.ReadFile(filepath.Join(setup.Pwd, "src", "main.tf")); string(b) != "source" {
+		t.Errorf("overlay got src/main.tf %q, want the input copied", b)
+	}
+	if fileutil.Exists(filepath.Join(setup.Pwd, "notes.txt")) {
+		t.Error("overlay got notes.txt, want only the inputs copied")
+	}
+
+	// the formula run changes the inputs and writes its outputs
+	_ = ioutil.WriteFile(filepath.Join(setup.Pwd, "src", "main.tf"), []byte("destroyed"), 0644)
+	_ = os.MkdirAll(filepath.Join(setup.Pwd, "dist"), 0755)
+	_ = ioutil.WriteFile(filepath.Join(setup.Pwd, "dist", "plan.out"), []byte("plan"), 0644)
+
+	if err := o.commit(); err != nil {
+		t.Fatalf("commit got %v, want nil", err)
+	}
+	o.remove()
+
+	if b, _ := ioutil.ReadFile(filepath.Join(pwd, "src", "main.tf")); string(b) != "source" {
+		t.Errorf("working directory got src/main.tf %q, want it untouched", b)
+	}
+	if b, _ := ioutil.ReadFile(filepath.Join(pwd, "dist", "plan.out")); string(b) != "plan" {
+		t.Errorf("working directory got dist/plan.out %q, want the output copied back", b)
+	}
+	if fileutil.Exists(setup.Pwd) {
+		t.Error("remove must delete the overlay")
+	}
+
+	escape := formula.Setup{Pwd: filepath.Join(pwd, "src"), Config: formula.Config{
+		Isolation: &formula.Isolation{Inputs: []string{"../notes.txt"}},
+	}}
+	if _, err := isolate(formula.Definition{Isolate: true}, &escape); err != ErrIsolationOutsidePwd {
+		t.Errorf("isolate of an input outside the working directory got %v, want ErrIsolationOutsidePwd", err)
+	}
+}
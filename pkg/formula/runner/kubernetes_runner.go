@@ -0,0 +1,320 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runlog"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	kubectlCmd        = "kubectl"
+	jobContainer      = "formula"
+	jobManagedBy      = "rit"
+	jobCommandKey     = "ritchie.zup.com.br/command"
+	podRunningTimeout = "5m"
+	msgJobPush        = "Pushing the formula image %s..."
+	msgJobCreated     = "Running the kubernetes job %s, it is kept on the cluster after the run"
+	msgProfileJob     = "the formula process runs on the kubernetes cluster, only the phases are measured"
+	msgDryRunJob      = "streaming the logs of the job %s, created with the image %s once it is pushed to the registry"
+)
+
+var (
+	ErrKubectlNotFound = prompt.NewError("you must have kubectl installed on the machine to run formulas on kubernetes")
+	ErrNoRegistry      = prompt.NewError("the cluster pulls the formula image from a registry, set it with rit set config kubernetes.registry REGISTRY")
+
+	// jobPoll and jobWait are how often and how long the job status is read after its logs end,
+	// they are vars so the tests don't wait
+	jobPoll = time.Second
+	jobWait = time.Minute
+
+	// imageName are the chars a repository name of a registry can't have
+	imageName = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// kubectlOutput is a var so the kubectl calls reading the jobs can be replaced on tests
+var kubectlOutput = func(stdin []byte, args ...string) (string, error) {
+	cmd := exec.Command(kubectlCmd, args...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// Kubernetes is where the formula jobs run, see the kubernetes configs. An empty Namespace,
+// ServiceAccount or Kubeconfig keeps the ones of the current kubectl context. Registry is
+// where the formula image is pushed to be pulled by the cluster, e.g. ghcr.io/team.
+type Kubernetes struct {
+	Namespace      string
+	ServiceAccount string
+	Kubeconfig     string
+	Registry       string
+}
+
+// args returns the kubectl args of the cluster and namespace of the jobs followed by args
+func (k Kubernetes) args(args ...string) []string {
+	var a []string
+	if k.Kubeconfig != "" {
+		a = append(a, "--kubeconfig", k.Kubeconfig)
+	}
+	if k.Namespace != "" {
+		a = append(a, "--namespace", k.Namespace)
+	}
+	return append(a, args...)
+}
+
+// JobError is a formula job that failed, Code is the exit code of the formula container
+type JobError struct {
+	Job  string
+	Code int
+}
+
+func (e JobError) Error() string {
+	return fmt.Sprintf("the kubernetes job %s failed with exit code %d", e.Job, e.Code)
+}
+
+// ExitCode returns the exit code of the formula, as exec.ExitError does
+func (e JobError) ExitCode() int {
+	return e.Code
+}
+
+// KubernetesRunner runs the formula image as a Kubernetes Job with kubectl, so the run is
+// audited and resourced by the cluster. The image is built like a docker run and pushed
+// to the registry, the inputs are asked locally and sent on a Secret removed after the run.
+// The job logs are streamed until the formula exits, the formula has no stdin and the
+// files it writes stay on its pod.
+type KubernetesRunner struct {
+	formula.PreRunner
+	formula.PostRunner
+	formula.InputRunner
+	kube Kubernetes
+	logs runlog.Creator
+}
+
+func NewKubernetesRunner(
+	preRunner formula.PreRunner,
+	postRunner formula.PostRunner,
+	inRunner formula.InputRunner,
+	kube Kubernetes,
+	logs runlog.Creator) KubernetesRunner {
+	return KubernetesRunner{preRunner, postRunner, inRunner, kube, logs}
+}
+
+func (k KubernetesRunner) Run(def formula.Definition, inputType api.TermInputType, verboseFlag string) (err error) {
+	if _, err := lookPath(kubectlCmd); err != nil {
+		return ErrKubectlNotFound
+	}
+	if k.kube.Registry == "" {
+		return ErrNoRegistry
+	}
+
+	p := newProfile(def)
+	defer p.print(def.ProfileJSON)
+
+	setup, err := k.PreRun(def)
+	if err != nil {
+		return err
+	}
+	defer cleanFailed(setup, false, &err)
+	if err := checkEditorInputs(setup, "--kubernetes"); err != nil {
+		return err
+	}
+
+	p.phase(phaseSetup)
+
+	cmd := exec.Command(kubectlCmd)
+	cmd.Env = os.Environ()
+	local := len(cmd.Env)
+	cmd.Env = append(cmd.Env, fmt.Sprintf(formula.EnvPattern, formula.VerboseEnv, verboseFlag))
+	addArgsEnv(cmd, def.Args)
+	cmd.Env = append(cmd.Env, def.Env...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := k.Inputs(cmd, setup, inputType); err != nil {
+		return err
+	}
+	keepInputs(def, cmd.Env, setup.Config.Inputs)
+	p.phase(phaseInputs)
+
+	name := "rit-" + setup.ContainerId
+	image := jobImage(k.kube.Registry, def.Command, setup.ContainerId)
+	cmd.Args = append(cmd.Args, k.kube.args("logs", "-f", "job/"+name, "--pod-running-timeout="+podRunningTimeout)...)
+	if def.DryRun {
+		job := fmt.Sprintf(msgDryRunJob, name, image)
+		dryRun(def, cmd.Args, []string{job}, cmd.Env[local:], setup, true)
+		return nil
+	}
+
+	// the job is created after the preRun hooks and the postRun hooks run after it finishes
+	defer func() { _, _ = kubectlOutput(nil, k.kube.args("delete", "secret", name, "--ignore-not-found")...) }()
+	err = runHooked(def, setup, func() error {
+		if err := pushImage(setup.Engine, setup.ContainerId, image); err != nil {
+			return err
+		}
+		manifest, err := jobManifest(name, image, def.Command, k.kube.ServiceAccount, cmd.Env[local:])
+		if err != nil {
+			return err
+		}
+		if _, err := kubectlOutput(manifest, k.kube.args("create", "-f", "-")...); err != nil {
+			return err
+		}
+		prompt.Info(fmt.Sprintf(msgJobCreated, name))
+
+		if err := runLogged(cmd, k.logs, def, setup.Config.Inputs, stopJob(k.kube, name)); err != nil {
+			return err
+		}
+		return waitJob(k.kube, name)
+	})
+	p.phase(phaseExecution)
+	p.remote(msgProfileJob)
+	if err != nil {
+		return err
+	}
+
+	if err := k.PostRun(setup, false); err != nil {
+		return err
+	}
+	p.phase(phasePostRun)
+	return nil
+}
+
+// jobImage returns the image the cluster pulls, named after the formula command and
+// tagged with the id of the local build, e.g. ghcr.io/team/rit-aws-create:<id>
+func jobImage(registry, command, id string) string {
+	repo := imageName.ReplaceAllString(strings.ToLower(command), "-")
+	return fmt.Sprintf("%s/%s:%s", strings.TrimSuffix(registry, "/"), strings.Trim(repo, "-"), id)
+}
+
+// pushImage pushes the formula image to the registry, the registry tag is removed after
+func pushImage(engine, id, image string) error {
+	prompt.Info(fmt.Sprintf(msgJobPush, image))
+	if _, err := dockerOutput(engine, "tag", id, image); err != nil {
+		return err
+	}
+	defer func() { _, _ = dockerOutput(engine, dockerRemoveImageCmd, image) }()
+
+	push := exec.Command(engine, "push", image)
+	push.Stderr = os.Stderr
+	return push.Run()
+}
+
+// jobManifest returns the Secret with the env of the formula and the Job running it, the job
+// fails on the first formula failure as a formula run is never retried by the cluster.
+// The env vars whose names a container can't have are left out.
+func jobManifest(name, image, command, serviceAccount string, env []string) ([]byte, error) {
+	data := map[string]string{}
+	for _, e := range env {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) == 2 && envName.MatchString(kv[0]) {
+			data[kv[0]] = kv[1]
+		}
+	}
+
+	labels := map[string]string{"app.kubernetes.io/managed-by": jobManagedBy}
+	meta := map[string]interface{}{
+		"name":        name,
+		"labels":      labels,
+		"annotations": map[string]string{jobCommandKey: command},
+	}
+
+	pod := map[string]interface{}{
+		"restartPolicy": "Never",
+		"containers": []interface{}{map[string]interface{}{
+			"name":    jobContainer,
+			"image":   image,
+			"envFrom": []interface{}{map[string]interface{}{"secretRef": map[string]string{"name": name}}},
+		}},
+	}
+	if serviceAccount != "" {
+		pod["serviceAccountName"] = serviceAccount
+	}
+
+	list := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items": []interface{}{
+			map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata":   meta,
+				"type":       "Opaque",
+				"stringData": data,
+			},
+			map[string]interface{}{
+				"apiVersion": "batch/v1",
+				"kind":       "Job",
+				"metadata":   meta,
+				"spec": map[string]interface{}{
+					"backoffLimit": 0,
+					"template": map[string]interface{}{
+						"metadata": map[string]interface{}{"labels": labels},
+						"spec":     pod,
+					},
+				},
+			},
+		},
+	}
+	return json.Marshal(list)
+}
+
+// waitJob waits the job status once its logs ended, it returns a JobError when the formula failed
+func waitJob(kube Kubernetes, name string) error {
+	deadline := time.Now().Add(jobWait)
+	for {
+		status, err := kubectlOutput(nil, kube.args("get", "job", name,
+			"-o", "jsonpath={.status.succeeded},{.status.failed}")...)
+		if err != nil {
+			return err
+		}
+
+		counts := strings.SplitN(status, ",", 2)
+		if len(counts) == 2 && counts[0] != "" && counts[0] != "0" {
+			return nil
+		}
+		if len(counts) == 2 && counts[1] != "" && counts[1] != "0" {
+			return JobError{Job: name, Code: jobExitCode(kube, name)}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("the kubernetes job %s didn't complete in %v after its logs ended", name, jobWait)
+		}
+		time.Sleep(jobPoll)
+	}
+}
+
+// jobExitCode returns the exit code of the formula container of the job, 1 when it is unknown
+func jobExitCode(kube Kubernetes, name string) int {
+	out, err := kubectlOutput(nil, kube.args("get", "pods", "-l", "job-name="+name,
+		"-o", "jsonpath={.items[0].status.containerStatuses[0].state.terminated.exitCode}")...)
+	if err != nil {
+		return 1
+	}
+	code, err := strconv.Atoi(out)
+	if err != nil || code == 0 {
+		return 1
+	}
+	return code
+}
+
+// stopJob deletes the job and then its pod, so the job doesn't replace it, the formula
+// gets a SIGTERM and is killed when it is still running after the grace
+func stopJob(kube Kubernetes, name string) stopFunc {
+	return func(_ *exec.Cmd, grace time.Duration) {
+		secs := strconv.Itoa(int(grace.Round(time.Second).Seconds()))
+		_, _ = kubectlOutput(nil, kube.args("delete", "job", name, "--cascade=false")...)
+		_, _ = kubectlOutput(nil, kube.args("delete", "pods", "-l", "job-name="+name, "--grace-period="+secs, "--wait=false")...)
+	}
+}
@@ -0,0 +1,143 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJobImage(t *testing.T) {
+	got := jobImage("ghcr.io/team/", "rit aws create_bucket", "0f3c")
+	if want := "ghcr.io/team/rit-aws-create-bucket:0f3c"; got != want {
+		t.Errorf("jobImage() = %q, want %q", got, want)
+	}
+}
+
+func TestKubernetes_Args(t *testing.T) {
+	k := Kubernetes{Namespace: "formulas", Kubeconfig: "/home/ops/.kube/prod"}
+	want := []string{"--kubeconfig", "/home/ops/.kube/prod", "--namespace", "formulas", "get", "jobs"}
+	if got := k.args("get", "jobs"); !reflect.DeepEqual(got, want) {
+		t.Errorf("args() = %q, want %q", got, want)
+	}
+	if got := (Kubernetes{}).args("get", "jobs"); !reflect.DeepEqual(got, []string{"get", "jobs"}) {
+		t.Errorf("args() without config = %q, want the kubectl context", got)
+	}
+}
+
+func TestJobManifest(t *testing.T) {
+	env := []string{"VERBOSE_MODE=false", "PASS=it's=secret", "BASH_FUNC_x%%=() {}"}
+	b, err := jobManifest("rit-0f3c", "ghcr.io/team/rit-aws:0f3c", "rit aws", "deployer", env)
+	if err != nil {
+		t.Fatalf("jobManifest() = %v, want nil", err)
+	}
+
+	var list struct {
+		Items []struct {
+			Kind       string            `json:"kind"`
+			StringData map[string]string `json:"stringData"`
+			Metadata   struct {
+				Name        string            `json:"name"`
+				Annotations map[string]string `json:"annotations"`
+			} `json:"metadata"`
+			Spec struct {
+				BackoffLimit *int `json:"backoffLimit"`
+				Template     struct {
+					Spec struct {
+						RestartPolicy      string `json:"restartPolicy"`
+						ServiceAccountName string `json:"serviceAccountName"`
+						Containers         []struct {
+							Image   string `json:"image"`
+							EnvFrom []struct {
+								SecretRef struct {
+									Name string `json:"name"`
+								} `json:"secretRef"`
+							} `json:"envFrom"`
+						} `json:"containers"`
+					} `json:"spec"`
+				} `json:"template"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(b, &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 2 || list.Items[0].Kind != "Secret" || list.Items[1].Kind != "Job" {
+		t.Fatalf("manifest = %s, want the secret then the job", b)
+	}
+
+	secret, job := list.Items[0], list.Items[1]
+	wantData := map[string]string{"VERBOSE_MODE": "false", "PASS": "it's=secret"}
+	if !reflect.DeepEqual(secret.StringData, wantData) {
+		t.Errorf("secret data = %v, want %v", secret.StringData, wantData)
+	}
+	if job.Metadata.Name != "rit-0f3c" || job.Metadata.Annotations[jobCommandKey] != "rit aws" {
+		t.Errorf("job metadata = %+v, want its name and command", job.Metadata)
+	}
+	if job.Spec.BackoffLimit == nil || *job.Spec.BackoffLimit != 0 {
+		t.Errorf("job backoffLimit = %v, want 0", job.Spec.BackoffLimit)
+	}
+
+	pod := job.Spec.Template.Spec
+	if pod.RestartPolicy != "Never" || pod.ServiceAccountName != "deployer" || len(pod.Containers) != 1 {
+		t.Fatalf("job pod = %+v, want a single container of the deployer account", pod)
+	}
+	c := pod.Containers[0]
+	if c.Image != "ghcr.io/team/rit-aws:0f3c" || len(c.EnvFrom) != 1 || c.EnvFrom[0].SecretRef.Name != "rit-0f3c" {
+		t.Errorf("job container = %+v, want the image with the env of the secret", c)
+	}
+}
+
+func TestWaitJob(t *testing.T) {
+	defer func(f func([]byte, ...string) (string, error)) { kubectlOutput = f }(kubectlOutput)
+	defer func(p, w time.Duration) { jobPoll, jobWait = p, w }(jobPoll, jobWait)
+	jobPoll, jobWait = time.Millisecond, 50*time.Millisecond
+
+	tests := []struct {
+		name     string
+		statuses []string
+		exitCode string
+		wantErr  error
+	}{
+		{name: "succeeded", statuses: []string{",", "1,"}},
+		{name: "failed", statuses: []string{",1"}, exitCode: "3", wantErr: JobError{Job: "rit-1", Code: 3}},
+		{name: "failed without exit code", statuses: []string{"0,1"}, wantErr: JobError{Job: "rit-1", Code: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			kubectlOutput = func(_ []byte, args ...string) (string, error) {
+				if args[1] == "pods" {
+					return tt.exitCode, nil
+				}
+				status := tt.statuses[calls]
+				if calls < len(tt.statuses)-1 {
+					calls++
+				}
+				return status, nil
+			}
+
+			if err := waitJob(Kubernetes{}, "rit-1"); !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("waitJob() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("running after the logs", func(t *testing.T) {
+		kubectlOutput = func(_ []byte, args ...string) (string, error) { return ",", nil }
+		if err := waitJob(Kubernetes{}, "rit-1"); err == nil || !strings.Contains(err.Error(), "didn't complete") {
+			t.Errorf("waitJob() = %v, want the job didn't complete", err)
+		}
+	})
+
+	t.Run("kubectl failure", func(t *testing.T) {
+		want := errors.New("forbidden")
+		kubectlOutput = func(_ []byte, args ...string) (string, error) { return "", want }
+		if err := waitJob(Kubernetes{}, "rit-1"); err != want {
+			t.Errorf("waitJob() = %v, want %v", err, want)
+		}
+	})
+}
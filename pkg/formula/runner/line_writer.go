@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// lineWriter writes the formula output a whole line at a time, the last line
+// without a newline is kept until Flush is called when the formula exits
+type lineWriter struct {
+	mu  *sync.Mutex
+	w   io.Writer
+	buf []byte
+}
+
+// newLineWriters creates the writers of the formula stdout and stderr, they share a
+// lock so the lines of both outputs never interleave on a writer they have in common
+func newLineWriters(stdout, stderr io.Writer) (*lineWriter, *lineWriter) {
+	mu := &sync.Mutex{}
+	return &lineWriter{mu: mu, w: stdout}, &lineWriter{mu: mu, w: stderr}
+}
+
+func (l *lineWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.buf = append(l.buf, p...)
+	i := bytes.LastIndexByte(l.buf, '\n')
+	if i < 0 {
+		return len(p), nil
+	}
+
+	_, err := l.w.Write(l.buf[:i+1])
+	l.buf = append(l.buf[:0], l.buf[i+1:]...)
+	return len(p), err
+}
+
+// Flush writes the pending output, the last line the formula wrote without a newline
+func (l *lineWriter) Flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.buf) == 0 {
+		return nil
+	}
+	_, err := l.w.Write(l.buf)
+	l.buf = l.buf[:0]
+	return err
+}
@@ -0,0 +1,107 @@
+package runner
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runlog"
+)
+
+func TestLineWriter(t *testing.T) {
+	var out bytes.Buffer
+	stdout, stderr := newLineWriters(&out, &out)
+
+	_, _ = stdout.Write([]byte("first line\nsecond "))
+	_, _ = stderr.Write([]byte("error line\n"))
+	_, _ = stdout.Write([]byte("line\nlast line"))
+	if got, want := out.String(), "first line\nerror line\nsecond line\n"; got != want {
+		t.Errorf("lineWriter wrote %q, want %q", got, want)
+	}
+
+	if err := stdout.Flush(); err != nil {
+		t.Fatalf("Flush error = %v", err)
+	}
+	if got, want := out.String(), "first line\nerror line\nsecond line\nlast line"; got != want {
+		t.Errorf("lineWriter flushed %q, want %q", got, want)
+	}
+}
+
+func TestRunLoggedLastLine(t *testing.T) {
+	home, err := ioutil.TempDir("", "rit-line-writer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	logs := runlog.NewManager(home, true)
+	cmd := exec.Command("sh", "-c", `printf 'first\nsecond\nlast line without newline'`)
+	if err := runLogged(cmd, logs, formula.Definition{Command: "rit mock test"}, nil, stopProcess); err != nil {
+		t.Fatalf("runLogged error = %v", err)
+	}
+
+	entries, err := logs.List()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("List got %v, %v, want the run log", entries, err)
+	}
+	b, _ := ioutil.ReadFile(entries[0].File)
+	if want := "first\nsecond\nlast line without newline"; string(b) != want {
+		t.Errorf("run log got %q, want %q", b, want)
+	}
+}
+
+func TestRunLoggedPartialLine(t *testing.T) {
+	home, err := ioutil.TempDir("", "rit-line-writer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(stdout *os.File) { os.Stdout = stdout }(os.Stdout)
+	os.Stdout = w
+
+	answer, answerW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("sh", "-c", `printf 'Name? '; read name; echo "hi $name"`)
+	cmd.Stdin = answer
+	done := make(chan error)
+	go func() {
+		done <- runLogged(cmd, runlog.NewManager(home, true), formula.Definition{Command: "rit mock test"}, nil, stopProcess)
+	}()
+
+	// the prompt without a newline shows up on the terminal before the formula reads the answer
+	prompted := make(chan string)
+	go func() {
+		b := make([]byte, len("Name? "))
+		_, _ = io.ReadFull(r, b)
+		prompted <- string(b)
+	}()
+	select {
+	case got := <-prompted:
+		if got != "Name? " {
+			t.Fatalf("terminal got %q, want the prompt", got)
+		}
+	case <-time.After(5 * time.Second):
+		_ = answerW.Close()
+		t.Fatal("terminal got no prompt before the answer")
+	}
+	_, _ = answerW.Write([]byte("rit\n"))
+	if err := <-done; err != nil {
+		t.Fatalf("runLogged error = %v", err)
+	}
+	_ = w.Close()
+	if rest, _ := ioutil.ReadAll(r); string(rest) != "hi rit\n" {
+		t.Errorf("terminal got %q after the prompt, want %q", rest, "hi rit\n")
+	}
+}
@@ -0,0 +1,123 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	outputsDirName      = ".rit-outputs"
+	outputsFile         = "outputs"
+	containerOutputsDir = "/rit/outputs"
+	outputsJSON         = "json"
+	outputsYAML         = "yaml"
+	msgOutputs          = "Outputs of %s:"
+	msgUndeclaredOutput = "%s wrote the output %q, which isn't declared on its config.json"
+)
+
+// outputsWriter is where the outputs of the run are printed, it is a var so the tests read it
+var outputsWriter io.Writer = os.Stdout
+
+// outputsDir creates the dir of the outputs file on the temp workspace of the run, empty when
+// the formula declares no outputs and the run has no --output. It is a dir, writable by any
+// user, so a container mounts it on containerOutputsDir.
+func outputsDir(def formula.Definition, setup formula.Setup) (string, error) {
+	if len(setup.Config.Outputs) == 0 && def.OutputFormat == "" {
+		return "", nil
+	}
+
+	dir := filepath.Join(setup.TmpDir, outputsDirName)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+	return dir, os.Chmod(dir, os.ModePerm)
+}
+
+// outputsEnv returns the env var of the outputs file, file is its path as the formula sees it
+func outputsEnv(file string) string {
+	return fmt.Sprintf(formula.EnvPattern, formula.OutputsEnv, file)
+}
+
+// readOutputs reads the NAME=VALUE lines the formula wrote on the outputs file, a later
+// line replaces the value of a name. The file is missing when the formula wrote nothing.
+func readOutputs(file string) (map[string]string, error) {
+	values := map[string]string{}
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return values, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for s.Scan() {
+		kv := strings.SplitN(strings.TrimSuffix(s.Text(), "\r"), "=", 2)
+		if name := strings.TrimSpace(kv[0]); len(kv) == 2 && name != "" {
+			values[name] = kv[1]
+		}
+	}
+	return values, s.Err()
+}
+
+// printOutputs prints the declared outputs the formula wrote on the outputs file of dir, as
+// text or on the OutputFormat of the run. The undeclared ones are reported on stderr, so the
+// json or yaml output stays parsable. An empty dir means the run doesn't collect outputs.
+func printOutputs(def formula.Definition, setup formula.Setup, dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	values, err := readOutputs(filepath.Join(dir, outputsFile))
+	if err != nil {
+		return err
+	}
+
+	outputs := map[string]string{}
+	for _, o := range setup.Config.Outputs {
+		if v, ok := values[o.Name]; ok {
+			outputs[o.Name] = v
+			delete(values, o.Name)
+		}
+	}
+	for name := range values {
+		fmt.Fprintln(os.Stderr, prompt.Yellow(fmt.Sprintf(msgUndeclaredOutput, def.Command, name)))
+	}
+
+	switch def.OutputFormat {
+	case outputsJSON:
+		b, err := json.MarshalIndent(outputs, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(outputsWriter, string(b))
+	case outputsYAML:
+		b, err := yaml.Marshal(outputs)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(outputsWriter, string(b))
+	default:
+		if len(outputs) == 0 {
+			return nil
+		}
+		fmt.Fprintf(outputsWriter, msgOutputs+"\n", def.Command)
+		for _, o := range setup.Config.Outputs {
+			if v, ok := outputs[o.Name]; ok {
+				fmt.Fprintf(outputsWriter, "  %s: %s\n", o.Name, v)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package runner
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestOutputsDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-outputs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	setup := formula.Setup{TmpDir: dir}
+	if got, err := outputsDir(formula.Definition{}, setup); got != "" || err != nil {
+		t.Errorf("outputsDir() without outputs = %q, %v, want no dir", got, err)
+	}
+
+	setup.Config.Outputs = []formula.Output{{Name: "url"}}
+	got, err := outputsDir(formula.Definition{}, setup)
+	if err != nil || got != filepath.Join(dir, outputsDirName) {
+		t.Fatalf("outputsDir() = %q, %v, want the dir on the workspace", got, err)
+	}
+	if info, err := os.Stat(got); err != nil || !info.IsDir() {
+		t.Errorf("outputs dir stat got %v, want a dir", err)
+	}
+}
+
+func TestPrintOutputs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-outputs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	lines := "id=i-0f3c\nurl=http://old\nurl=https://bucket.s3.amazonaws.com/a=b\r\nundeclared=1\nnot an output\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, outputsFile), []byte(lines), 0644); err != nil {
+		t.Fatal(err)
+	}
+	setup := formula.Setup{Config: formula.Config{Outputs: []formula.Output{{Name: "url"}, {Name: "id"}, {Name: "path"}}}}
+
+	tests := []struct {
+		name   string
+		format string
+		dir    string
+		want   string
+	}{
+		{
+			name: "text",
+			dir:  dir,
+			want: "Outputs of rit aws create:\n  url: https://bucket.s3.amazonaws.com/a=b\n  id: i-0f3c\n",
+		},
+		{
+			name:   "json",
+			format: "json",
+			dir:    dir,
+			want:   "{\n  \"id\": \"i-0f3c\",\n  \"url\": \"https://bucket.s3.amazonaws.com/a=b\"\n}\n",
+		},
+		{
+			name:   "yaml",
+			format: "yaml",
+			dir:    dir,
+			want:   "id: i-0f3c\nurl: https://bucket.s3.amazonaws.com/a=b\n",
+		},
+		{
+			name:   "nothing written",
+			format: "json",
+			dir:    filepath.Join(dir, "missing"),
+			want:   "{}\n",
+		},
+		{name: "not collected", format: "json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			outputsWriter = &out
+			defer func() { outputsWriter = os.Stdout }()
+
+			def := formula.Definition{Command: "rit aws create", OutputFormat: tt.format}
+			if err := printOutputs(def, setup, tt.dir); err != nil {
+				t.Fatalf("printOutputs() = %v, want nil", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("printOutputs() printed %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
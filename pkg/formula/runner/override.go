@@ -0,0 +1,41 @@
+package runner
+
+import (
+	"os/exec"
+	"runtime"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/os/osutil"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+var ErrOverrideNotAllowed = prompt.NewError("this formula doesn't allow overriding its command, its config.json must set allowOverride")
+
+// localCommand returns the command running the formula binary with the args or, with
+// the command override, the shell running the override command instead. The override
+// gets the args as its positional parameters, e.g. sh -c 'echo "$1"'.
+func localCommand(def formula.Definition, setup formula.Setup) (*exec.Cmd, error) {
+	if def.CommandOverride == "" {
+		return exec.Command(setup.TmpBinFilePath, def.Args...), nil
+	}
+	if !setup.Config.AllowOverride {
+		return nil, ErrOverrideNotAllowed
+	}
+
+	if runtime.GOOS == osutil.Windows {
+		return exec.Command("cmd", append([]string{"/C", def.CommandOverride}, def.Args...)...), nil
+	}
+	return exec.Command("sh", append([]string{"-c", def.CommandOverride, "sh"}, def.Args...)...), nil
+}
+
+// entrypointArgs returns the docker run args replacing the entrypoint of the formula image,
+// docker also clears the image CMD, so the entrypoint runs without the formula args
+func entrypointArgs(def formula.Definition, setup formula.Setup) ([]string, error) {
+	if def.Entrypoint == "" {
+		return nil, nil
+	}
+	if !setup.Config.AllowOverride {
+		return nil, ErrOverrideNotAllowed
+	}
+	return []string{"--entrypoint", def.Entrypoint}, nil
+}
@@ -0,0 +1,80 @@
+package runner
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/os/osutil"
+)
+
+func TestLocalCommand(t *testing.T) {
+	if runtime.GOOS == osutil.Windows {
+		t.Skip("the override runs on sh")
+	}
+
+	allowed := formula.Setup{TmpBinFilePath: "/tmp/bin/run.sh", Config: formula.Config{AllowOverride: true}}
+	tests := []struct {
+		name     string
+		def      formula.Definition
+		setup    formula.Setup
+		wantArgs []string
+		wantErr  error
+	}{
+		{
+			name:     "formula binary",
+			def:      formula.Definition{Args: []string{"--dry-run"}},
+			setup:    formula.Setup{TmpBinFilePath: "/tmp/bin/run.sh"},
+			wantArgs: []string{"/tmp/bin/run.sh", "--dry-run"},
+		},
+		{
+			name:     "command override",
+			def:      formula.Definition{CommandOverride: "env | grep AWS", Args: []string{"a"}},
+			setup:    allowed,
+			wantArgs: []string{"sh", "-c", "env | grep AWS", "sh", "a"},
+		},
+		{
+			name:    "formula without allowOverride",
+			def:     formula.Definition{CommandOverride: "/bin/sh"},
+			setup:   formula.Setup{TmpBinFilePath: "/tmp/bin/run.sh"},
+			wantErr: ErrOverrideNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := localCommand(tt.def, tt.setup)
+			if err != tt.wantErr {
+				t.Fatalf("localCommand() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(cmd.Args, tt.wantArgs) {
+				t.Errorf("localCommand() args = %q, want %q", cmd.Args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestEntrypointArgs(t *testing.T) {
+	allowed := formula.Setup{Config: formula.Config{AllowOverride: true}}
+	tests := []struct {
+		name    string
+		def     formula.Definition
+		setup   formula.Setup
+		want    []string
+		wantErr error
+	}{
+		{name: "image entrypoint", setup: allowed},
+		{name: "entrypoint override", def: formula.Definition{Entrypoint: "/bin/sh"}, setup: allowed, want: []string{"--entrypoint", "/bin/sh"}},
+		{name: "formula without allowOverride", def: formula.Definition{Entrypoint: "/bin/sh"}, wantErr: ErrOverrideNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := entrypointArgs(tt.def, tt.setup)
+			if err != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("entrypointArgs() = %q, %v, want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package runner
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runlog"
+	"github.com/ZupIT/ritchie-cli/pkg/os/osutil"
+)
+
+// PluginsDir is the dir of the executable runner plugins on the rit home, see RegisterExecutables
+const PluginsDir = "runners"
+
+type (
+	// Parts are the parts of rit a runner plugin is built from. PreRunner sets up the local
+	// build of the formula and DockerPreRunner its image, PostRunner cleans up the run, Inputs
+	// prompts the inputs of the formula and Logs creates its run log.
+	Parts struct {
+		PreRunner       formula.PreRunner
+		DockerPreRunner formula.PreRunner
+		PostRunner      formula.PostRunner
+		Inputs          formula.InputRunner
+		Logs            runlog.Creator
+	}
+
+	// Factory builds the runner of a plugin, once per run
+	Factory func(p Parts) formula.Runner
+)
+
+// plugins are the runner plugins registered by name
+var plugins = map[string]Factory{}
+
+// Register adds a runner plugin, e.g. on Firecracker or Nomad, selected by its name with the
+// formula.runner config or --runner as the container engines are. An external build of rit
+// registers its runners from the init func of a package the main package imports, e.g.
+// import _ "example.com/rit-nomad". It panics when the name is taken, as sql.Register does.
+func Register(name string, f Factory) {
+	if f == nil {
+		panic("runner: Register factory is nil")
+	}
+	if name == "" || name == EngineDocker || name == EnginePodman || plugins[name] != nil {
+		panic("runner: Register called twice for runner " + name)
+	}
+	plugins[name] = f
+}
+
+// RegisterExecutables registers the executables on dir as runner plugins named by their file
+// name without the extension, so that a runner is added without a build of rit. An executable
+// plugin gets the formula command as its args, run on the workspace of the formula with the env
+// of the run and its inputs answered, e.g. a nomad plugin ships the workspace to a Nomad job
+// running the command. A missing dir has no plugins, the names taken by the build are kept.
+func RegisterExecutables(dir string) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, f := range files {
+		name := strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))
+		executable := f.Mode()&0111 != 0 || runtime.GOOS == osutil.Windows
+		if f.IsDir() || !executable || name == "" || name == EngineDocker || name == EnginePodman || plugins[name] != nil {
+			continue
+		}
+		plugins[name] = execPlugin(filepath.Join(dir, f.Name()))
+	}
+}
+
+// execPlugin runs the formulas as the local runs do, through the plugin executable on path
+func execPlugin(path string) Factory {
+	return func(p Parts) formula.Runner {
+		return DefaultRunner{p.PreRunner, p.PostRunner, p.Inputs, p.Logs, path}
+	}
+}
+
+// Plugins returns the names of the registered runner plugins, sorted
+func Plugins() []string {
+	names := make([]string, 0, len(plugins))
+	for name := range plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PluginRunner runs the formulas on the runner plugin of their definition, or on the runner
+// it wraps when there is none. Each run mode is wrapped, so a plugin selected by the
+// formula.runner config runs the local, docker, ssh and Kubernetes runs alike, the mode
+// on the definition. A session always runs on docker.
+type PluginRunner struct {
+	runner formula.Runner
+	parts  Parts
+}
+
+// NewPluginRunner creates a runner of the plugins, runner runs the formulas without a plugin
+func NewPluginRunner(runner formula.Runner, parts Parts) PluginRunner {
+	return PluginRunner{runner, parts}
+}
+
+func (r PluginRunner) Run(def formula.Definition, inputType api.TermInputType, verbose string) error {
+	f, ok := plugins[def.Runner]
+	if !ok || def.Session {
+		return r.runner.Run(def, inputType, verbose)
+	}
+	return f(r.parts).Run(def, inputType, verbose)
+}
@@ -0,0 +1,94 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/os/osutil"
+)
+
+type runnerFunc func(def formula.Definition) error
+
+func (f runnerFunc) Run(def formula.Definition, _ api.TermInputType, _ string) error {
+	return f(def)
+}
+
+func TestPluginRunner(t *testing.T) {
+	defer delete(plugins, "nomad")
+
+	var ran []string
+	engine := runnerFunc(func(def formula.Definition) error {
+		ran = append(ran, Engine(def.Runner))
+		return nil
+	})
+	var parts Parts
+	Register("nomad", func(p Parts) formula.Runner {
+		parts = p
+		return runnerFunc(func(formula.Definition) error {
+			ran = append(ran, "nomad")
+			return nil
+		})
+	})
+	r := NewPluginRunner(engine, Parts{PostRunner: NewPostRunner()})
+
+	_ = r.Run(formula.Definition{Runner: EnginePodman}, api.Prompt, "false")
+	_ = r.Run(formula.Definition{Runner: "nomad"}, api.Prompt, "false")
+	_ = r.Run(formula.Definition{Runner: "nomad", Session: true}, api.Prompt, "false")
+
+	if want := []string{EnginePodman, "nomad", EngineDocker}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("Run() ran on %v, want %v", ran, want)
+	}
+	if parts.PostRunner == nil {
+		t.Errorf("the plugin got no parts of rit")
+	}
+	if !reflect.DeepEqual(Plugins(), []string{"nomad"}) {
+		t.Errorf("Plugins() got %v, want [nomad]", Plugins())
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Register(docker) didn't panic, want the engine names taken")
+		}
+	}()
+	Register(EngineDocker, func(Parts) formula.Runner { return nil })
+}
+
+func TestRegisterExecutables(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-runners")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func() {
+		delete(plugins, "nomad")
+		delete(plugins, "firecracker")
+	}()
+
+	_ = ioutil.WriteFile(filepath.Join(dir, "nomad.sh"), []byte("#!/bin/sh\nexec \"$@\"\n"), 0755)
+	_ = ioutil.WriteFile(filepath.Join(dir, "firecracker"), []byte("not executable"), 0644)
+	_ = ioutil.WriteFile(filepath.Join(dir, "docker"), []byte("#!/bin/sh\n"), 0755)
+
+	RegisterExecutables(dir)
+	RegisterExecutables(filepath.Join(dir, "missing"))
+
+	want := []string{"nomad"}
+	if runtime.GOOS == osutil.Windows {
+		want = []string{"firecracker", "nomad"}
+	}
+	if !reflect.DeepEqual(Plugins(), want) {
+		t.Fatalf("RegisterExecutables() got the plugins %v, want %v", Plugins(), want)
+	}
+
+	r, ok := plugins["nomad"](Parts{}).(DefaultRunner)
+	if !ok || r.launcher != filepath.Join(dir, "nomad.sh") {
+		t.Errorf("the nomad plugin got %#v, want a local run through nomad.sh", r)
+	}
+}
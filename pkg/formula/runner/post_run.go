@@ -21,7 +21,7 @@ func (PostRunnerManager) PostRun(p formula.Setup, docker bool) error {
 			return err
 		}
 
-		if err := removeContainer(p.ContainerId); err != nil {
+		if err := removeContainer(p.Engine, p.ContainerId); err != nil {
 			return err
 		}
 	}
@@ -46,9 +46,9 @@ func removeWorkDir(tmpDir string) {
 	}
 }
 
-func removeContainer(imgName string) error {
+func removeContainer(engine, imgName string) error {
 	args := []string{dockerRemoveCmd, imgName}
-	cmd := exec.Command(docker, args...)
+	cmd := exec.Command(engine, args...)
 
 	if err := cmd.Start(); err != nil {
 		return err
@@ -0,0 +1,139 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+const (
+	phaseSetup     = "setup"
+	phaseInputs    = "inputs"
+	phaseExecution = "execution"
+	phasePostRun   = "post-run"
+
+	msgProfileDocker = "the formula process runs on docker, only the phases are measured"
+	msgProfileRemote = "the formula process runs on the remote host, only the phases are measured"
+	msgProfileNoMem  = "the peak memory isn't measurable on this OS"
+)
+
+// profileWriter is where the --profile report is written, stderr keeps the formula stdout clean
+var profileWriter io.Writer = os.Stderr
+
+// profileReport is the --profile report, the times are in seconds
+type profileReport struct {
+	Command       string         `json:"command"`
+	Profile       string         `json:"profile"`
+	Phases        []profilePhase `json:"phases"`
+	Total         float64        `json:"totalSeconds"`
+	UserCPU       float64        `json:"userCpuSeconds,omitempty"`
+	SystemCPU     float64        `json:"systemCpuSeconds,omitempty"`
+	PeakMemory    int64          `json:"peakMemoryBytes,omitempty"`
+	Note          string         `json:"note,omitempty"`
+	started, last time.Time
+}
+
+type profilePhase struct {
+	Name    string  `json:"name"`
+	Seconds float64 `json:"seconds"`
+}
+
+// newProfile starts the profile of the run, it is nil without --profile
+// and every method of a nil profile does nothing
+func newProfile(def formula.Definition) *profileReport {
+	if def.Profile == "" {
+		return nil
+	}
+	now := time.Now()
+	return &profileReport{Command: def.Command, Profile: def.Profile, started: now, last: now}
+}
+
+// phase ends the phase that started with the previous one
+func (p *profileReport) phase(name string) {
+	if p == nil {
+		return
+	}
+	now := time.Now()
+	p.Phases = append(p.Phases, profilePhase{Name: name, Seconds: now.Sub(p.last).Seconds()})
+	p.last = now
+}
+
+// process reads the resource usage of the finished formula process, the one of
+// docker run is the docker client's, so the docker runs have only the phases
+func (p *profileReport) process(state *os.ProcessState, docker bool) {
+	if p == nil || state == nil {
+		return
+	}
+	if docker {
+		p.remote(msgProfileDocker)
+		return
+	}
+
+	switch p.Profile {
+	case formula.ProfileCPU:
+		p.UserCPU = state.UserTime().Seconds()
+		p.SystemCPU = state.SystemTime().Seconds()
+	case formula.ProfileMem:
+		if mem, ok := peakMemory(state); ok {
+			p.PeakMemory = mem
+		} else {
+			p.Note = msgProfileNoMem
+		}
+	}
+}
+
+// remote notes that the formula process doesn't run on this machine, so it has only the phases
+func (p *profileReport) remote(note string) {
+	if p != nil {
+		p.Note = note
+	}
+}
+
+// print writes the report of the phases run so far, as JSON or text
+func (p *profileReport) print(asJSON bool) {
+	if p == nil {
+		return
+	}
+	p.Total = time.Since(p.started).Seconds()
+
+	if asJSON {
+		b, err := json.Marshal(p)
+		if err == nil {
+			fmt.Fprintln(profileWriter, string(b))
+		}
+		return
+	}
+	fmt.Fprintln(profileWriter, p.text())
+}
+
+func (p *profileReport) text() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Profile of %s (%s):", p.Command, p.Profile))
+	line := func(name string, d time.Duration) {
+		sb.WriteString(fmt.Sprintf("\n  %-12s %v", name, d.Round(time.Millisecond)))
+	}
+	for _, ph := range p.Phases {
+		line(ph.Name, seconds(ph.Seconds))
+	}
+	line("total", seconds(p.Total))
+	if p.Profile == formula.ProfileCPU && p.Note == "" {
+		line("cpu user", seconds(p.UserCPU))
+		line("cpu system", seconds(p.SystemCPU))
+	}
+	if p.PeakMemory > 0 {
+		sb.WriteString(fmt.Sprintf("\n  %-12s %.1f MB", "peak memory", float64(p.PeakMemory)/(1024*1024)))
+	}
+	if p.Note != "" {
+		sb.WriteString("\n  " + p.Note)
+	}
+	return sb.String()
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,25 @@
+//go:build !windows
+// +build !windows
+
+package runner
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+
+	"github.com/ZupIT/ritchie-cli/pkg/os/osutil"
+)
+
+// peakMemory returns the maximum resident set size of the finished process in bytes,
+// getrusage reports it in kilobytes on Linux and in bytes on macOS
+func peakMemory(state *os.ProcessState) (int64, bool) {
+	ru, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return 0, false
+	}
+	if runtime.GOOS == osutil.Darwin {
+		return int64(ru.Maxrss), true
+	}
+	return int64(ru.Maxrss) * 1024, true
+}
@@ -0,0 +1,99 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/os/osutil"
+)
+
+func TestProfile(t *testing.T) {
+	if runtime.GOOS == osutil.Windows {
+		t.Skip("the profiled process is a shell command")
+	}
+
+	var out bytes.Buffer
+	old := profileWriter
+	defer func() { profileWriter = old }()
+	profileWriter = &out
+
+	if p := newProfile(formula.Definition{}); p != nil {
+		t.Fatalf("newProfile without --profile got %+v, want nil", p)
+	}
+	var none *profileReport
+	none.phase(phaseSetup)
+	none.print(false)
+	if out.Len() != 0 {
+		t.Fatalf("a run without --profile printed %q", out.String())
+	}
+
+	cmd := exec.Command("sh", "-c", "i=0; while [ $i -lt 2000 ]; do i=$((i+1)); done")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		def     formula.Definition
+		docker  bool
+		want    []string
+		wantCPU bool
+		wantMem bool
+	}{
+		{
+			name:    "cpu",
+			def:     formula.Definition{Command: "rit aws create", Profile: formula.ProfileCPU},
+			want:    []string{"Profile of rit aws create (cpu):", "setup", "inputs", "execution", "total", "cpu user"},
+			wantCPU: true,
+		},
+		{
+			name:    "mem",
+			def:     formula.Definition{Command: "rit aws create", Profile: formula.ProfileMem},
+			want:    []string{"(mem):", "execution", "peak memory"},
+			wantMem: true,
+		},
+		{
+			name:   "docker",
+			def:    formula.Definition{Command: "rit aws create", Profile: formula.ProfileMem},
+			docker: true,
+			want:   []string{"execution", msgProfileDocker},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out.Reset()
+			p := newProfile(tt.def)
+			p.phase(phaseSetup)
+			p.phase(phaseInputs)
+			p.phase(phaseExecution)
+			p.process(cmd.ProcessState, tt.docker)
+			p.print(false)
+
+			for _, w := range tt.want {
+				if !strings.Contains(out.String(), w) {
+					t.Errorf("profile report %q, want it to contain %q", out.String(), w)
+				}
+			}
+
+			out.Reset()
+			p.print(true)
+			var report profileReport
+			if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+				t.Fatalf("profile JSON %q: %v", out.String(), err)
+			}
+			if len(report.Phases) != 3 || report.Phases[2].Name != phaseExecution || report.Total <= 0 {
+				t.Errorf("profile JSON phases = %+v, total %v", report.Phases, report.Total)
+			}
+			// a short process may take less CPU time than the clock resolution
+			if (!tt.wantCPU && report.UserCPU+report.SystemCPU > 0) || (report.PeakMemory > 0) != tt.wantMem {
+				t.Errorf("profile JSON = %+v, want cpu %v and memory %v", report, tt.wantCPU, tt.wantMem)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package runner
+
+import "os"
+
+// peakMemory isn't measured on Windows, its process state has no resident set size
+func peakMemory(*os.ProcessState) (int64, bool) {
+	return 0, false
+}
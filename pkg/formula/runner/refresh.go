@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/pkg/urlutil"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/checksum"
+	"github.com/ZupIT/ritchie-cli/pkg/stream"
+)
+
+const (
+	// refreshDirSuffix names the dir the formula is downloaded to before replacing the installed one
+	refreshDirSuffix = ".refresh"
+	// replacedDirSuffix names the dir the installed formula is moved to while it is replaced
+	replacedDirSuffix = ".replaced"
+)
+
+// Refresh downloads the config and the bundle of the formula again. They are downloaded
+// to a sibling dir that replaces the formula dir only when both are complete, so a failed
+// refresh keeps the installed formula. The input caches of the formula are kept.
+func (d DefaultSetup) Refresh(def formula.Definition) error {
+	return d.refresh(def, nil)
+}
+
+// InstallLocked downloads the formula as Refresh, the installed formula is only
+// replaced when the downloaded files match the checksums of the lock file
+func (d DefaultSetup) InstallLocked(def formula.Definition, checksums map[string]string) error {
+	return d.refresh(def, func(dir string) error {
+		if err := checksum.Verify(dir, checksums); err != nil {
+			return fmt.Errorf("formula %s: %w", def.Path, err)
+		}
+		return nil
+	})
+}
+
+// refresh downloads the formula and, when the verify of the download dir passes, installs it
+func (d DefaultSetup) refresh(def formula.Definition, verify func(dir string) error) error {
+	if !urlutil.IsURL(def.BundleURL()) {
+		return formula.ErrRefreshUnsupported
+	}
+
+	formulaPath := def.FormulaPath(d.ritchieHome)
+	refreshPath := formulaPath + refreshDirSuffix
+	if err := os.RemoveAll(refreshPath); err != nil {
+		return err
+	}
+	defer os.RemoveAll(refreshPath)
+
+	if _, err := d.loadConfig(refreshPath, def); err != nil {
+		return err
+	}
+	binFilePath := def.BinFilePath(def.BinPath(refreshPath), def.BinName())
+	if err := d.loadBundle(refreshPath, binFilePath, def); err != nil {
+		return err
+	}
+	if verify != nil {
+		if err := verify(refreshPath); err != nil {
+			return err
+		}
+	}
+
+	return replaceFormula(formulaPath, refreshPath)
+}
+
+// replaceFormula swaps the installed formula dir for the downloaded one under the lock of the
+// formula, so a rit setting it up at the same time waits for it. The installed dir is moved
+// back when the swap fails, only once the swap is done its input caches are moved to the
+// downloaded formula and the rest of it removed.
+func replaceFormula(formulaPath, downloadPath string) error {
+	unlock, err := stream.Lock(formulaPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	replacedPath := formulaPath + replacedDirSuffix
+	if err := os.RemoveAll(replacedPath); err != nil {
+		return err
+	}
+	if err := os.Rename(formulaPath, replacedPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Rename(downloadPath, formulaPath); err != nil {
+		_ = os.Rename(replacedPath, formulaPath)
+		return err
+	}
+
+	caches, _ := filepath.Glob(fmt.Sprintf(formula.CachePattern, replacedPath, "*"))
+	for _, c := range caches {
+		_ = os.Rename(c, filepath.Join(formulaPath, filepath.Base(c)))
+	}
+	return os.RemoveAll(replacedPath)
+}
@@ -0,0 +1,167 @@
+package runner
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofrs/flock"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/checksum"
+	"github.com/ZupIT/ritchie-cli/pkg/stream"
+)
+
+func TestDefaultSetup_Refresh(t *testing.T) {
+	var bundle bytes.Buffer
+	zw := zip.NewWriter(&bundle)
+	if _, err := zw.Create("bin/"); err != nil {
+		t.Fatal(err)
+	}
+	w, err := zw.Create("bin/run.sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte("echo v2"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/aws/create/config.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"inputs": []}`))
+	})
+	mux.HandleFunc("/aws/create/formula.zip", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(bundle.Bytes())
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		def      formula.Definition
+		want     error
+		wantFile string
+	}{
+		{
+			name:     "refreshes the formula",
+			def:      formula.Definition{Path: "aws/create", Bin: "run.sh", Bundle: "formula.zip", RepoURL: server.URL},
+			wantFile: "echo v2",
+		},
+		{
+			name:     "keeps the formula on a failed download",
+			def:      formula.Definition{Path: "aws/create", Bin: "run.sh", Bundle: "missing.zip", RepoURL: server.URL},
+			want:     ErrFormulaBinNotFound,
+			wantFile: "echo v1",
+		},
+		{
+			name:     "repository without the formula files",
+			def:      formula.Definition{Path: "aws/create", Bin: "run.sh", Bundle: "formula.zip"},
+			want:     formula.ErrRefreshUnsupported,
+			wantFile: "echo v1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home, err := ioutil.TempDir("", "rit-refresh")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(home)
+
+			formulaPath := tt.def.FormulaPath(home)
+			if err := os.MkdirAll(filepath.Join(formulaPath, "bin"), os.ModePerm); err != nil {
+				t.Fatal(err)
+			}
+			bin := filepath.Join(formulaPath, "bin", "run.sh")
+			cache := filepath.Join(formulaPath, ".REGION.cache")
+			_ = ioutil.WriteFile(bin, []byte("echo v1"), 0644)
+			_ = ioutil.WriteFile(cache, []byte(`["sa-east-1"]`), 0644)
+
+			setup := NewDefaultSingleSetup(home, server.Client())
+			if err := setup.Refresh(tt.def); !errors.Is(err, tt.want) {
+				t.Fatalf("Refresh error = %v, want %v", err, tt.want)
+			}
+
+			if b, err := ioutil.ReadFile(bin); err != nil || string(b) != tt.wantFile {
+				t.Errorf("Refresh got the bin %q (%v), want %q", b, err, tt.wantFile)
+			}
+			if _, err := os.Stat(cache); err != nil {
+				t.Errorf("Refresh must keep the input cache: %v", err)
+			}
+			if _, err := os.Stat(formulaPath + refreshDirSuffix); !os.IsNotExist(err) {
+				t.Errorf("Refresh must remove its download dir: %v", err)
+			}
+			if _, err := os.Stat(formulaPath + replacedDirSuffix); !os.IsNotExist(err) {
+				t.Errorf("Refresh must remove the replaced formula: %v", err)
+			}
+		})
+	}
+
+	t.Run("locked by another rit", func(t *testing.T) {
+		defer func(d time.Duration) { stream.LockTimeout = d }(stream.LockTimeout)
+		stream.LockTimeout = 300 * time.Millisecond
+
+		home, err := ioutil.TempDir("", "rit-refresh")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(home)
+
+		def := formula.Definition{Path: "aws/create", Bin: "run.sh", Bundle: "formula.zip", RepoURL: server.URL}
+		formulaPath := def.FormulaPath(home)
+		cache := filepath.Join(formulaPath, ".REGION.cache")
+		_ = os.MkdirAll(formulaPath, os.ModePerm)
+		_ = ioutil.WriteFile(cache, []byte(`["sa-east-1"]`), 0644)
+
+		// flock locks of the same process on another open file conflict as the ones of another rit
+		other := flock.New(stream.LockPath(formulaPath))
+		if _, err := other.TryLock(); err != nil {
+			t.Fatal(err)
+		}
+		defer other.Unlock()
+
+		if err := NewDefaultSingleSetup(home, server.Client()).Refresh(def); err == nil {
+			t.Fatal("Refresh got no error, want the lock timeout")
+		}
+		if _, err := os.Stat(cache); err != nil {
+			t.Errorf("Refresh must keep the input cache while locked: %v", err)
+		}
+	})
+
+	t.Run("locked checksums", func(t *testing.T) {
+		home, err := ioutil.TempDir("", "rit-refresh")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(home)
+
+		def := formula.Definition{Path: "aws/create", Bin: "run.sh", Bundle: "formula.zip", RepoURL: server.URL}
+		setup := NewDefaultSingleSetup(home, server.Client())
+		if err := setup.InstallLocked(def, map[string]string{"config.json": "0000"}); !errors.Is(err, formula.ErrLockMismatch) {
+			t.Fatalf("InstallLocked with other checksums error = %v, want %v", err, formula.ErrLockMismatch)
+		}
+		if _, err := os.Stat(def.FormulaPath(home)); !os.IsNotExist(err) {
+			t.Errorf("InstallLocked must not install a formula not matching the lock: %v", err)
+		}
+
+		if err := setup.Refresh(def); err != nil {
+			t.Fatal(err)
+		}
+		locked, _, err := checksum.Load(def.FormulaPath(home))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := setup.InstallLocked(def, locked); err != nil {
+			t.Errorf("InstallLocked with the installed checksums error = %v, want nil", err)
+		}
+	})
+}
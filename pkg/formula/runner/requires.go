@@ -0,0 +1,103 @@
+package runner
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/version"
+)
+
+const (
+	msgInvalidRequire = "invalid requirement %q of config.json, use a tool and an optional version, e.g. node>=16"
+	msgUnmetRequires  = "the formula requires %s, install them before running it"
+	msgToolNotFound   = "%s (not found)"
+	msgToolVersion    = "%s (found %s)"
+	msgToolNoVersion  = "%s (found no version)"
+)
+
+var (
+	// requirement is a tool and an optional version constraint, e.g. kubectl or docker>=20
+	requirement = regexp.MustCompile(`^([A-Za-z0-9_.+-]+?)\s*(?:(>=|<=|==|=|>|<)\s*v?([0-9]+(?:\.[0-9]+){0,2}))?$`)
+	// toolVersion is the first version printed by a tool, e.g. 20.10.7 of Docker version 20.10.7
+	toolVersion = regexp.MustCompile(`[0-9]+\.[0-9]+(?:\.[0-9]+)?`)
+
+	// lookTool and toolOutput find the tools and run their version commands, they are vars so
+	// the tests replace the tools of the host
+	lookTool   = exec.LookPath
+	toolOutput = func(path string, args ...string) string {
+		out, _ := exec.Command(path, args...).CombinedOutput()
+		return string(out)
+	}
+)
+
+// checkRequires checks that the tools the formula requires are on the PATH with the required
+// versions, so a formula without them fails before it runs instead of halfway through it.
+// All the unmet requirements are reported at once.
+func checkRequires(requires []string) error {
+	var unmet []string
+	for _, r := range requires {
+		m := requirement.FindStringSubmatch(strings.TrimSpace(r))
+		if m == nil {
+			return prompt.NewError(fmt.Sprintf(msgInvalidRequire, r))
+		}
+		tool, op, want := m[1], m[2], m[3]
+
+		path, err := lookTool(tool)
+		if err != nil {
+			unmet = append(unmet, fmt.Sprintf(msgToolNotFound, r))
+			continue
+		}
+		if op == "" {
+			continue
+		}
+
+		found := installedVersion(path)
+		if found == "" {
+			unmet = append(unmet, fmt.Sprintf(msgToolNoVersion, r))
+			continue
+		}
+		if !versionMeets(found, op, want) {
+			unmet = append(unmet, fmt.Sprintf(msgToolVersion, r, found))
+		}
+	}
+
+	if len(unmet) > 0 {
+		return prompt.NewError(fmt.Sprintf(msgUnmetRequires, strings.Join(unmet, ", ")))
+	}
+	return nil
+}
+
+// installedVersion is the version printed by tool --version or, for the tools without it
+// as kubectl, by tool version
+func installedVersion(path string) string {
+	if v := toolVersion.FindString(toolOutput(path, "--version")); v != "" {
+		return v
+	}
+	return toolVersion.FindString(toolOutput(path, "version"))
+}
+
+// versionMeets compares the found version with the wanted one on the parts the wanted one
+// has, so node=16 is met by 16.3.0 and docker>20 by 21.0.0 but not by 20.10.7. The versions
+// were matched as numbers so they always parse.
+func versionMeets(found, op, want string) bool {
+	parts := strings.Split(found, ".")
+	if n := strings.Count(want, ".") + 1; len(parts) > n {
+		parts = parts[:n]
+	}
+	cmp, _ := version.Compare(strings.Join(parts, "."), want)
+	switch op {
+	case ">=":
+		return cmp >= 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp == 0
+	}
+}
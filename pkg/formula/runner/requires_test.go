@@ -0,0 +1,73 @@
+package runner
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckRequires(t *testing.T) {
+	defer func(l func(string) (string, error), o func(string, ...string) string) {
+		lookTool, toolOutput = l, o
+	}(lookTool, toolOutput)
+
+	// the host has docker 20.10.7, node 14.17.0, kubectl without --version and a tool without a version
+	outputs := map[string]map[string]string{
+		"docker":  {"--version": "Docker version 20.10.7, build f0df350"},
+		"node":    {"--version": "v14.17.0"},
+		"kubectl": {"--version": "Error: unknown flag: --version", "version": `Client Version: version.Info{GitVersion:"v1.21.0"}`},
+		"make":    {"--version": "make, the GNU make"},
+	}
+	lookTool = func(tool string) (string, error) {
+		if _, ok := outputs[tool]; !ok {
+			return "", errors.New("executable file not found in $PATH")
+		}
+		return tool, nil
+	}
+	toolOutput = func(path string, args ...string) string {
+		return outputs[path][args[0]]
+	}
+
+	tests := []struct {
+		name     string
+		requires []string
+		want     string
+	}{
+		{
+			name:     "met requirements",
+			requires: []string{"docker>=20", "node>14.1", "kubectl>=1.20", "node = 14", "make", "docker<21.0.0"},
+		},
+		{
+			name:     "missing tool",
+			requires: []string{"docker", "terraform>=0.13"},
+			want:     "the formula requires terraform>=0.13 (not found), install them",
+		},
+		{
+			name:     "outdated tools",
+			requires: []string{"node>=16", "docker>20", "kubectl<1.21"},
+			want:     "node>=16 (found 14.17.0), docker>20 (found 20.10.7), kubectl<1.21 (found 1.21.0)",
+		},
+		{
+			name:     "tool without a version",
+			requires: []string{"make>=4"},
+			want:     "make>=4 (found no version)",
+		},
+		{
+			name:     "invalid requirement",
+			requires: []string{"node>=latest"},
+			want:     `invalid requirement "node>=latest" of config.json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRequires(tt.requires)
+			if tt.want == "" && err != nil {
+				t.Errorf("checkRequires() got %v, want nil", err)
+			}
+			if tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+				t.Errorf("checkRequires() got %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
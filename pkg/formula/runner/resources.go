@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	msgInvalidCPUs   = "invalid cpus %q, it must be a number of CPUs greater than 0, e.g. 1.5"
+	msgInvalidMemory = "invalid memory %q, it must be a size as 512m or 2g"
+	msgOOMKilled     = "%s was killed for using more than its memory limit of %s, raise it with --memory"
+)
+
+// memorySize is a docker memory size, bytes or a number of kilobytes, megabytes or gigabytes
+var memorySize = regexp.MustCompile(`^[0-9]+[bkmgBKMG]?$`)
+
+// resources returns the limits of the run, the --cpus and --memory of the definition
+// replacing the resources of the formula config.json
+func resources(def formula.Definition, setup formula.Setup) (formula.Resources, error) {
+	var r formula.Resources
+	if setup.Config.Resources != nil {
+		r = *setup.Config.Resources
+	}
+	if def.Resources.CPUs != "" {
+		r.CPUs = def.Resources.CPUs
+	}
+	if def.Resources.Memory != "" {
+		r.Memory = def.Resources.Memory
+	}
+
+	if r.CPUs != "" {
+		if cpus, err := strconv.ParseFloat(r.CPUs, 64); err != nil || cpus <= 0 {
+			return r, prompt.NewError(fmt.Sprintf(msgInvalidCPUs, r.CPUs))
+		}
+	}
+	if r.Memory != "" && !memorySize.MatchString(r.Memory) {
+		return r, prompt.NewError(fmt.Sprintf(msgInvalidMemory, r.Memory))
+	}
+	return r, nil
+}
+
+// resourceArgs are the docker run args limiting the container to the resources
+func resourceArgs(r formula.Resources) []string {
+	var args []string
+	if r.CPUs != "" {
+		args = append(args, "--cpus", r.CPUs)
+	}
+	if r.Memory != "" {
+		args = append(args, "--memory", r.Memory)
+	}
+	return args
+}
+
+// warnOOMKilled tells that the formula failed because docker killed its container for
+// going over the memory limit, which only shows as the exit code 137 otherwise
+func warnOOMKilled(def formula.Definition, container string, r formula.Resources) {
+	if r.Memory == "" {
+		return
+	}
+	if out, err := dockerOutput(Engine(def.Runner), dockerInspectCmd, "-f", "{{.State.OOMKilled}}", container); err == nil && out == "true" {
+		prompt.Warning(fmt.Sprintf(msgOOMKilled, def.Command, r.Memory))
+	}
+}
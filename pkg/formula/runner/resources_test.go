@@ -0,0 +1,45 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestResources(t *testing.T) {
+	config := &formula.Resources{CPUs: "2", Memory: "1g"}
+
+	tests := []struct {
+		name     string
+		def      formula.Resources
+		config   *formula.Resources
+		wantArgs []string
+		wantErr  bool
+	}{
+		{name: "no limits"},
+		{name: "config.json", config: config, wantArgs: []string{"--cpus", "2", "--memory", "1g"}},
+		{name: "flags replace config.json", def: formula.Resources{Memory: "512M"}, config: config, wantArgs: []string{"--cpus", "2", "--memory", "512M"}},
+		{name: "only cpus", def: formula.Resources{CPUs: "0.5"}, wantArgs: []string{"--cpus", "0.5"}},
+		{name: "invalid cpus", def: formula.Resources{CPUs: "0"}, wantErr: true},
+		{name: "invalid memory", def: formula.Resources{Memory: "1.5gb"}, wantErr: true},
+		{name: "invalid config.json", config: &formula.Resources{CPUs: "many"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := formula.Definition{Resources: tt.def}
+			setup := formula.Setup{Config: formula.Config{Resources: tt.config}}
+			got, err := resources(def, setup)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resources() got error %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if args := resourceArgs(got); !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("resourceArgs() got %q, want %q", args, tt.wantArgs)
+			}
+		})
+	}
+}
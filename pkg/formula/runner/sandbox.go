@@ -0,0 +1,40 @@
+package runner
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+var (
+	ErrSandboxUnsupported = prompt.NewError("the sandbox needs the unprivileged user namespaces of Linux, enable them with sysctl kernel.unprivileged_userns_clone=1")
+	ErrSandboxNetwork     = prompt.NewError("the sandbox only cuts the network of the formula on Linux, use --sandbox-network to run it with the network")
+)
+
+// sandboxTmpEnv are the env vars of the tmp dir on the platforms
+var sandboxTmpEnv = []string{"TMPDIR", "TMP", "TEMP"}
+
+// sandbox prepares the local run of the formula for the sandbox of def.Sandbox: the formula
+// gets a private tmp dir, removed by the returned func, and runs on its own namespaces
+func sandbox(cmd *exec.Cmd, def formula.Definition) (func(), error) {
+	if !def.Sandbox {
+		return func() {}, nil
+	}
+
+	if err := sandboxProcess(cmd, def.SandboxNetwork); err != nil {
+		return nil, err
+	}
+
+	tmp, err := ioutil.TempDir("", "rit-sandbox")
+	if err != nil {
+		return nil, err
+	}
+	for _, env := range sandboxTmpEnv {
+		cmd.Env = append(cmd.Env, fmt.Sprintf(formula.EnvPattern, env, tmp))
+	}
+	return func() { _ = os.RemoveAll(tmp) }, nil
+}
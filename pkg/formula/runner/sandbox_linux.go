@@ -0,0 +1,33 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// userNamespacesSysctl is disabled on the kernels restricting the unprivileged user namespaces
+var userNamespacesSysctl = "/proc/sys/kernel/unprivileged_userns_clone"
+
+// sandboxProcess runs the formula on its own user, IPC and UTS namespaces, as the user that
+// runs rit, and on a network namespace without interfaces unless the network is kept
+func sandboxProcess(cmd *exec.Cmd, network bool) error {
+	if b, err := ioutil.ReadFile(userNamespacesSysctl); err == nil && strings.TrimSpace(string(b)) == "0" {
+		return ErrSandboxUnsupported
+	}
+
+	flags := uintptr(syscall.CLONE_NEWUSER | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS)
+	if !network {
+		flags |= syscall.CLONE_NEWNET
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags:                 flags,
+		UidMappings:                []syscall.SysProcIDMap{{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1}},
+		GidMappings:                []syscall.SysProcIDMap{{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1}},
+		GidMappingsEnableSetgroups: false,
+		Pdeathsig:                  syscall.SIGKILL,
+	}
+	return nil
+}
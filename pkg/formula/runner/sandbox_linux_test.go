@@ -0,0 +1,92 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestSandbox(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-sandbox-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(f string) { userNamespacesSysctl = f }(userNamespacesSysctl)
+	userNamespacesSysctl = filepath.Join(dir, "unprivileged_userns_clone")
+
+	tests := []struct {
+		name      string
+		def       formula.Definition
+		sysctl    string
+		wantFlags uintptr
+		wantTmp   bool
+		wantErr   error
+	}{
+		{name: "no sandbox"},
+		{
+			name:      "without the network",
+			def:       formula.Definition{Sandbox: true},
+			wantFlags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS | syscall.CLONE_NEWNET,
+			wantTmp:   true,
+		},
+		{
+			name:      "with the network",
+			def:       formula.Definition{Sandbox: true, SandboxNetwork: true},
+			sysctl:    "1",
+			wantFlags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS,
+			wantTmp:   true,
+		},
+		{name: "user namespaces disabled", def: formula.Definition{Sandbox: true}, sysctl: "0\n", wantErr: ErrSandboxUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = os.Remove(userNamespacesSysctl)
+			if tt.sysctl != "" {
+				_ = ioutil.WriteFile(userNamespacesSysctl, []byte(tt.sysctl), 0644)
+			}
+
+			cmd := exec.Command("true")
+			remove, err := sandbox(cmd, tt.def)
+			if err != tt.wantErr {
+				t.Fatalf("sandbox() got %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer remove()
+
+			var flags uintptr
+			if cmd.SysProcAttr != nil {
+				flags = cmd.SysProcAttr.Cloneflags
+			}
+			if flags != tt.wantFlags {
+				t.Errorf("sandbox() got the clone flags %x, want %x", flags, tt.wantFlags)
+			}
+
+			var tmp string
+			for _, e := range cmd.Env {
+				if strings.HasPrefix(e, "TMPDIR=") {
+					tmp = strings.TrimPrefix(e, "TMPDIR=")
+				}
+			}
+			if info, err := os.Stat(tmp); tt.wantTmp && (err != nil || info.Mode().Perm() != 0700) {
+				t.Errorf("sandbox() got the tmp dir %q, want a private one", tmp)
+			} else if !tt.wantTmp && tmp != "" {
+				t.Errorf("sandbox() got the tmp dir %q without a sandbox", tmp)
+			}
+
+			remove()
+			if _, err := os.Stat(tmp); tt.wantTmp && !os.IsNotExist(err) {
+				t.Errorf("the tmp dir %s is kept after the run", tmp)
+			}
+		})
+	}
+}
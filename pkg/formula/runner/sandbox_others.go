@@ -0,0 +1,15 @@
+//go:build !linux
+// +build !linux
+
+package runner
+
+import "os/exec"
+
+// sandboxProcess has no namespaces to run the formula on out of Linux, the sandbox only
+// gives it a private tmp dir, so its network can't be cut
+func sandboxProcess(_ *exec.Cmd, network bool) error {
+	if !network {
+		return ErrSandboxNetwork
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package runner
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/env"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runlog"
+)
+
+const (
+	selfTestInput   = "self_test_message"
+	selfTestMessage = "rit self-test"
+	selfTestConfig  = `{"inputs":[{"name":"self_test_message","type":"text","label":"Message:"}]}`
+	selfTestShell   = "#!/bin/sh\necho \"hello from $SELF_TEST_MESSAGE\"\n"
+	selfTestBatch   = "@echo off\r\necho hello from %SELF_TEST_MESSAGE%\r\n"
+	selfTestOutput  = "hello from " + selfTestMessage
+)
+
+// SelfTester runs the built-in smoke formula through the default runner pipeline.
+// The formula is written to a temp ritchie home, so no repository is needed: its
+// input is resolved from stdin, it runs as any local formula and its output must
+// reach the run log.
+type SelfTester struct{}
+
+func NewSelfTester() SelfTester {
+	return SelfTester{}
+}
+
+// SelfTest runs the smoke formula, the error tells the step that failed
+func (SelfTester) SelfTest() error {
+	home, err := ioutil.TempDir("", "rit-self-test")
+	if err != nil {
+		return fmt.Errorf("creating the self-test home: %w", err)
+	}
+	defer os.RemoveAll(home)
+
+	def := formula.Definition{
+		Command: "rit self-test",
+		Path:    "self-test",
+		Bin:     "run.sh",
+		WBin:    "run.bat",
+		Config:  formula.DefaultConfig,
+		Stdin:   strings.NewReader(fmt.Sprintf(`{%q:%q}`, selfTestInput, selfTestMessage)),
+	}
+	if err := writeSelfTestFormula(home, def); err != nil {
+		return fmt.Errorf("writing the smoke formula: %w", err)
+	}
+
+	// the setup moves to the formula work dir, the user one is restored after the run
+	pwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	defer os.Chdir(pwd)
+
+	logs := runlog.NewManager(home, true)
+	inputs := NewInputManager(env.Resolvers{}, nil, nil, nil, nil, nil, nil, nil, false)
+	r := NewDefaultRunner(NewDefaultPreRunner(NewDefaultSingleSetup(home, http.DefaultClient)), NewPostRunner(), inputs, logs)
+	if err := r.Run(def, api.Stdin, "false"); err != nil {
+		return fmt.Errorf("running the smoke formula: %w", err)
+	}
+
+	entries, err := logs.List()
+	if err != nil || len(entries) == 0 {
+		return fmt.Errorf("the smoke formula run wasn't logged: %v", err)
+	}
+	out, err := ioutil.ReadFile(entries[0].File)
+	if err != nil {
+		return fmt.Errorf("reading the smoke formula output: %w", err)
+	}
+	if !strings.Contains(string(out), selfTestOutput) {
+		return fmt.Errorf("the smoke formula output was %q, want %q", strings.TrimSpace(string(out)), selfTestOutput)
+	}
+	return nil
+}
+
+// writeSelfTestFormula writes the config and the bins of the smoke formula where the
+// setup looks for an installed formula
+func writeSelfTestFormula(home string, def formula.Definition) error {
+	formulaPath := def.FormulaPath(home)
+	binPath := def.BinPath(formulaPath)
+	if err := os.MkdirAll(binPath, 0755); err != nil {
+		return err
+	}
+
+	configPath := def.ConfigPath(formulaPath, def.ConfigName())
+	if err := ioutil.WriteFile(configPath, []byte(selfTestConfig), 0644); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(binPath, def.Bin), []byte(selfTestShell), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(binPath, def.WBin), []byte(selfTestBatch), 0755)
+}
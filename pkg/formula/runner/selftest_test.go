@@ -0,0 +1,16 @@
+package runner
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSelfTest(t *testing.T) {
+	pwd, _ := os.Getwd()
+	if err := NewSelfTester().SelfTest(); err != nil {
+		t.Fatalf("SelfTest error = %v", err)
+	}
+	if got, _ := os.Getwd(); got != pwd {
+		t.Errorf("SelfTest left the working dir on %s, want %s", got, pwd)
+	}
+}
@@ -0,0 +1,127 @@
+package runner
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	dockerNetworkCmd     = "network"
+	sidecarReadyTimeout  = time.Minute
+	msgStartingSidecar   = "Starting the sidecar %s (%s)..."
+	msgSidecarInvalid    = "the sidecar %q of config.json needs a name of lowercase letters, digits, '_', '.' and '-' and an image"
+	msgSidecarDuplicated = "the sidecar %q is declared twice on config.json"
+	msgSidecarFailed     = "unable to start the sidecar %q: %v"
+	msgSidecarNotReady   = "the sidecar %q wasn't ready after %s"
+	msgSidecarSession    = "the formula has sidecars, which a --session doesn't start, run it without --session"
+)
+
+var (
+	sidecarName = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+	// sidecarPoll is the interval the ready commands are retried, a var so the tests don't wait
+	sidecarPoll = time.Second
+)
+
+// sidecarNetwork is the docker network of the run, shared by the formula and its sidecars,
+// empty when the formula has no sidecars
+func sidecarNetwork(setup formula.Setup) string {
+	if len(setup.Config.Sidecars) == 0 {
+		return ""
+	}
+	return "rit-" + setup.ContainerId
+}
+
+// validateSidecars checks the sidecars of config.json before anything is started
+func validateSidecars(sidecars []formula.Sidecar) error {
+	names := make(map[string]bool, len(sidecars))
+	for _, s := range sidecars {
+		if !sidecarName.MatchString(s.Name) || s.Image == "" {
+			return prompt.NewError(fmt.Sprintf(msgSidecarInvalid, s.Name))
+		}
+		if names[s.Name] {
+			return prompt.NewError(fmt.Sprintf(msgSidecarDuplicated, s.Name))
+		}
+		names[s.Name] = true
+	}
+	return nil
+}
+
+// startSidecars creates the network of the run and starts the sidecars on it, each one
+// reachable by its name, waiting until they are ready. The returned func removes the
+// sidecars and the network, it is also called when a sidecar fails to start.
+func startSidecars(setup formula.Setup) (func(), error) {
+	network := sidecarNetwork(setup)
+	if network == "" {
+		return func() {}, nil
+	}
+
+	if _, err := dockerOutput(setup.Engine, dockerNetworkCmd, "create", network); err != nil {
+		return nil, err
+	}
+
+	var containers []string
+	remove := func() {
+		if len(containers) > 0 {
+			_, _ = dockerOutput(setup.Engine, append([]string{dockerRemoveCmd, "-f"}, containers...)...)
+		}
+		_, _ = dockerOutput(setup.Engine, dockerNetworkCmd, "rm", network)
+	}
+
+	for _, s := range setup.Config.Sidecars {
+		prompt.Info(fmt.Sprintf(msgStartingSidecar, s.Name, s.Image))
+		container := network + "-" + s.Name
+		if _, err := dockerOutput(setup.Engine, sidecarArgs(s, container, network)...); err != nil {
+			remove()
+			return nil, prompt.NewError(fmt.Sprintf(msgSidecarFailed, s.Name, err))
+		}
+		containers = append(containers, container)
+	}
+
+	for _, s := range setup.Config.Sidecars {
+		if err := waitSidecar(setup.Engine, s, network+"-"+s.Name); err != nil {
+			remove()
+			return nil, err
+		}
+	}
+
+	return remove, nil
+}
+
+// sidecarArgs are the docker run args of the sidecar, detached on the network of the run
+func sidecarArgs(s formula.Sidecar, container, network string) []string {
+	args := []string{dockerRunCmd, "-d", "--name", container, "--network", network, "--network-alias", s.Name}
+
+	names := make([]string, 0, len(s.Env))
+	for n := range s.Env {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		args = append(args, "-e", n+"="+s.Env[n])
+	}
+
+	return append(append(args, s.Image), s.Command...)
+}
+
+// waitSidecar runs the ready command of the sidecar until it succeeds, for sidecarReadyTimeout
+func waitSidecar(engine string, s formula.Sidecar, container string) error {
+	if len(s.Ready) == 0 {
+		return nil
+	}
+
+	deadline := time.Now().Add(sidecarReadyTimeout)
+	for {
+		if _, err := dockerOutput(engine, append([]string{dockerExecCmd, container}, s.Ready...)...); err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return prompt.NewError(fmt.Sprintf(msgSidecarNotReady, s.Name, sidecarReadyTimeout))
+		}
+		time.Sleep(sidecarPoll)
+	}
+}
@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestStartSidecars(t *testing.T) {
+	defer func(o func(string, ...string) (string, error)) { dockerOutput = o }(dockerOutput)
+	defer func(p time.Duration) { sidecarPoll = p }(sidecarPoll)
+	sidecarPoll = 0
+
+	sidecars := []formula.Sidecar{
+		{Name: "postgres", Image: "postgres:12", Env: map[string]string{"POSTGRES_USER": "rit", "POSTGRES_DB": "test"}, Ready: []string{"pg_isready"}},
+		{Name: "localstack", Image: "localstack/localstack", Command: []string{"--debug"}},
+	}
+
+	tests := []struct {
+		name      string
+		sidecars  []formula.Sidecar
+		failOn    string
+		want      []string
+		wantErr   bool
+		wantAfter []string
+	}{
+		{name: "no sidecars"},
+		{
+			name:     "started on the network of the run",
+			sidecars: sidecars,
+			want: []string{
+				"network create rit-123",
+				"run -d --name rit-123-postgres --network rit-123 --network-alias postgres -e POSTGRES_DB=test -e POSTGRES_USER=rit postgres:12",
+				"run -d --name rit-123-localstack --network rit-123 --network-alias localstack localstack/localstack --debug",
+				"exec rit-123-postgres pg_isready",
+				"exec rit-123-postgres pg_isready",
+			},
+			wantAfter: []string{"rm -f rit-123-postgres rit-123-localstack", "network rm rit-123"},
+		},
+		{
+			name:     "removes the started sidecars when one fails",
+			sidecars: sidecars,
+			failOn:   "localstack/localstack",
+			want: []string{
+				"network create rit-123",
+				"run -d --name rit-123-postgres --network rit-123 --network-alias postgres -e POSTGRES_DB=test -e POSTGRES_USER=rit postgres:12",
+				"run -d --name rit-123-localstack --network rit-123 --network-alias localstack localstack/localstack --debug",
+				"rm -f rit-123-postgres",
+				"network rm rit-123",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			readyTries := 0
+			dockerOutput = func(_ string, args ...string) (string, error) {
+				call := strings.Join(args, " ")
+				calls = append(calls, call)
+				switch {
+				case tt.failOn != "" && strings.Contains(call, tt.failOn):
+					return "", errors.New("pull access denied")
+				case args[0] == dockerExecCmd:
+					// the first try finds the database still starting
+					if readyTries++; readyTries == 1 {
+						return "", errors.New("no response")
+					}
+				}
+				return "", nil
+			}
+
+			setup := formula.Setup{ContainerId: "123", Engine: EngineDocker, Config: formula.Config{Sidecars: tt.sidecars}}
+			remove, err := startSidecars(setup)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("startSidecars() got error %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(calls, tt.want) {
+				t.Errorf("startSidecars() ran\n%q, want\n%q", calls, tt.want)
+			}
+			if err != nil {
+				return
+			}
+
+			calls = nil
+			remove()
+			if !reflect.DeepEqual(calls, tt.wantAfter) {
+				t.Errorf("remove() ran %q, want %q", calls, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestValidateSidecars(t *testing.T) {
+	tests := []struct {
+		name     string
+		sidecars []formula.Sidecar
+		wantErr  bool
+	}{
+		{name: "valid", sidecars: []formula.Sidecar{{Name: "db", Image: "postgres"}, {Name: "s3.local", Image: "localstack/localstack"}}},
+		{name: "without image", sidecars: []formula.Sidecar{{Name: "db"}}, wantErr: true},
+		{name: "invalid name", sidecars: []formula.Sidecar{{Name: "My DB", Image: "postgres"}}, wantErr: true},
+		{name: "duplicated name", sidecars: []formula.Sidecar{{Name: "db", Image: "postgres"}, {Name: "db", Image: "mysql"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateSidecars(tt.sidecars); (err != nil) != tt.wantErr {
+				t.Errorf("validateSidecars() got %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
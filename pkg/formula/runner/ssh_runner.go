@@ -0,0 +1,222 @@
+package runner
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/formula/runlog"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	sshCmd          = "ssh"
+	remoteDirPrefix = "/tmp/rit-"
+	remoteEnvFile   = ".rit-env.sh"
+	msgRemoteCopy   = "Copying the formula to %s..."
+	msgDryRunCopy   = "after copying the formula build %s to %s:%s"
+)
+
+var (
+	ErrSSHNotFound = prompt.NewError("you must have the ssh client installed on the machine to run formulas on a remote host")
+
+	// envName are the env var names the remote shell can export
+	envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// sshCommand is a var so the ssh calls copying the formula can be replaced on tests
+var sshCommand = func(stdin io.Reader, args ...string) error {
+	cmd := exec.Command(sshCmd, args...)
+	cmd.Stdin = stdin
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// SSHRunner runs the local build of the formula on the remote host of Definition.Remote
+// with the ssh client, so the host config, keys and jump hosts of ~/.ssh/config are used.
+// The inputs are asked locally and only the env they add is sent to the host, with the
+// bin dir of the formula, on a temp dir removed when the run ends. The formula outputs
+// are streamed back, the files it writes stay on the host until the temp dir is removed.
+type SSHRunner struct {
+	formula.PreRunner
+	formula.PostRunner
+	formula.InputRunner
+	logs runlog.Creator
+}
+
+func NewSSHRunner(preRunner formula.PreRunner, postRunner formula.PostRunner, inRunner formula.InputRunner, logs runlog.Creator) SSHRunner {
+	return SSHRunner{preRunner, postRunner, inRunner, logs}
+}
+
+func (s SSHRunner) Run(def formula.Definition, inputType api.TermInputType, verboseFlag string) (err error) {
+	if _, err := lookPath(sshCmd); err != nil {
+		return ErrSSHNotFound
+	}
+
+	p := newProfile(def)
+	defer p.print(def.ProfileJSON)
+
+	setup, err := s.PreRun(def)
+	if err != nil {
+		return err
+	}
+	defer cleanFailed(setup, false, &err)
+	if err := checkEditorInputs(setup, "--ssh"); err != nil {
+		return err
+	}
+	p.phase(phaseSetup)
+
+	dir := remoteDirPrefix + filepath.Base(setup.TmpDir)
+	cmd := exec.Command(sshCmd)
+	cmd.Env = os.Environ()
+	local := len(cmd.Env)
+	cmd.Env = append(cmd.Env, fmt.Sprintf(formula.EnvPattern, formula.PwdEnv, dir))
+	cmd.Env = append(cmd.Env, fmt.Sprintf(formula.EnvPattern, formula.CPwdEnv, dir))
+	cmd.Env = append(cmd.Env, fmt.Sprintf(formula.EnvPattern, formula.VerboseEnv, verboseFlag))
+	addArgsEnv(cmd, def.Args)
+	cmd.Env = append(cmd.Env, def.Env...)
+
+	cmd.Stdin = os.Stdin
+	if def.Stdin != nil {
+		cmd.Stdin = def.Stdin
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := s.Inputs(cmd, setup, inputType); err != nil {
+		return err
+	}
+	keepInputs(def, cmd.Env, setup.Config.Inputs)
+	p.phase(phaseInputs)
+
+	bin, err := filepath.Rel(setup.TmpBinDir, setup.TmpBinFilePath)
+	if err != nil {
+		return err
+	}
+	tty := isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd())
+	cmd.Args = append(cmd.Args, remoteArgs(def.Remote, dir, filepath.ToSlash(bin), def.Args, tty)...)
+
+	if def.DryRun {
+		copyLine := fmt.Sprintf(msgDryRunCopy, setup.TmpBinDir, def.Remote, dir)
+		dryRun(def, cmd.Args, []string{copyLine}, cmd.Env[local:], setup, false)
+		return nil
+	}
+
+	prompt.Info(fmt.Sprintf(msgRemoteCopy, def.Remote))
+	bundle, err := remoteBundle(setup.TmpBinDir, cmd.Env[local:])
+	if err != nil {
+		return err
+	}
+	mkdir := fmt.Sprintf("mkdir -p %s && tar -xf - -C %s", shellQuote(dir), shellQuote(dir))
+	if err := sshCommand(bundle, def.Remote, mkdir); err != nil {
+		return err
+	}
+
+	err = runHooked(def, setup, func() error {
+		return runLogged(cmd, s.logs, def, setup.Config.Inputs, stopProcess)
+	})
+	p.phase(phaseExecution)
+	p.remote(msgProfileRemote)
+	if err != nil {
+		return err
+	}
+
+	if err := s.PostRun(setup, false); err != nil {
+		return err
+	}
+	p.phase(phasePostRun)
+	return nil
+}
+
+// remoteArgs returns the ssh args running the formula bin on the remote dir with the env
+// of its inputs, the dir is removed when the formula exits. The command runs on sh, as the
+// login shell of the host may not be a POSIX one.
+func remoteArgs(host, dir, bin string, args []string, tty bool) []string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shellQuote(a)
+	}
+	script := fmt.Sprintf("trap 'rm -rf %s' EXIT; cd %s && . ./%s && rm -f %s && ./%s %s",
+		dir, shellQuote(dir), remoteEnvFile, remoteEnvFile, bin, strings.Join(quoted, " "))
+
+	ttyFlag := "-T"
+	if tty {
+		ttyFlag = "-t"
+	}
+	return []string{ttyFlag, host, "sh -c " + shellQuote(strings.TrimSpace(script))}
+}
+
+// remoteBundle archives the bin dir as a tar with the env script exporting the env, the
+// script is only readable by the user and removed before the formula runs. The env vars
+// whose names a shell can't export are left out.
+func remoteBundle(binDir string, env []string) (io.Reader, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	var script strings.Builder
+	for _, e := range env {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) == 2 && envName.MatchString(kv[0]) {
+			script.WriteString(fmt.Sprintf("export %s=%s\n", kv[0], shellQuote(kv[1])))
+		}
+	}
+	hdr := &tar.Header{Name: remoteEnvFile, Mode: 0600, Size: int64(script.Len())}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write([]byte(script.String())); err != nil {
+		return nil, err
+	}
+
+	err := filepath.Walk(binDir, func(file string, info os.FileInfo, err error) error {
+		if err != nil || file == binDir {
+			return err
+		}
+
+		rel, err := filepath.Rel(binDir, file)
+		if err != nil {
+			return err
+		}
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Clean(filepath.ToSlash(rel))
+		if err := tw.WriteHeader(hdr); err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
@@ -0,0 +1,107 @@
+package runner
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/os/osutil"
+)
+
+func TestRemoteBundle(t *testing.T) {
+	binDir, err := ioutil.TempDir("", "rit-ssh-bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(binDir)
+	if err := os.MkdirAll(filepath.Join(binDir, "lib"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(binDir, "run.sh"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(binDir, "lib", "util.sh"), []byte("util"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := remoteBundle(binDir, []string{"REGION=sa east", "PASS=it's", "BASH_FUNC_x%%=() {}"})
+	if err != nil {
+		t.Fatalf("remoteBundle() = %v, want nil", err)
+	}
+
+	files := map[string]string{}
+	modes := map[string]int64{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := ioutil.ReadAll(tr)
+		files[hdr.Name] = string(b)
+		modes[hdr.Name] = hdr.Mode
+	}
+
+	wantEnv := "export REGION='sa east'\nexport PASS='it'\\''s'\n"
+	if files[remoteEnvFile] != wantEnv || modes[remoteEnvFile] != 0600 {
+		t.Errorf("env script = %q with mode %o, want %q with mode 600", files[remoteEnvFile], modes[remoteEnvFile], wantEnv)
+	}
+	if files["run.sh"] != "#!/bin/sh\n" || modes["run.sh"]&0100 == 0 {
+		t.Errorf("run.sh = %q with mode %o, want the executable bin", files["run.sh"], modes["run.sh"])
+	}
+	if _, ok := files["lib"]; !ok || files["lib/util.sh"] != "util" {
+		t.Errorf("bundle files = %v, want the lib dir and lib/util.sh", files)
+	}
+}
+
+func TestRemoteArgs(t *testing.T) {
+	if runtime.GOOS == osutil.Windows {
+		t.Skip("the remote command runs on sh")
+	}
+
+	dir, err := ioutil.TempDir("", "rit-ssh-remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	script := "#!/bin/sh\necho \"$REGION|$1|$2\"\nls -a\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "run.sh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, remoteEnvFile), []byte("export REGION='sa east'\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	args := remoteArgs("jump", dir, "run.sh", []string{"--name", "it's"}, false)
+	if len(args) != 3 || args[0] != "-T" || args[1] != "jump" {
+		t.Fatalf("remoteArgs() = %q, want -T, the host and the command", args)
+	}
+
+	// the command ssh runs on the host, ran here by sh
+	out, err := exec.Command("sh", "-c", args[2]).CombinedOutput()
+	if err != nil {
+		t.Fatalf("remote command got %v: %s", err, out)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if lines[0] != "sa east|--name|it's" {
+		t.Errorf("remote command printed %q, want the env and the args", lines[0])
+	}
+	if strings.Contains(string(out), remoteEnvFile) {
+		t.Errorf("the env script was on the dir when the formula ran: %s", out)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("the remote dir is kept after the run, stat got %v", err)
+	}
+
+	if args := remoteArgs("jump", dir, "run.sh", nil, true); args[0] != "-t" {
+		t.Errorf("remoteArgs() with a tty = %q, want -t", args)
+	}
+}
@@ -0,0 +1,140 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	dockerStopCmd  = "stop"
+	msgTerminating = "Stopping the formula, it is killed if it is still running in %v"
+	msgTimedOut    = "The formula is running for more than its timeout of %v"
+	msgKilled      = "Killing the formula"
+	// TimeoutExitCode is the exit code of the formulas stopped by their timeout, as the one of timeout(1)
+	TimeoutExitCode = 124
+)
+
+// TimeoutError is a formula stopped because it ran for longer than Definition.Timeout
+type TimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e TimeoutError) Error() string {
+	return fmt.Sprintf("the formula was stopped after its timeout of %v", e.Timeout)
+}
+
+// ExitCode returns TimeoutExitCode, so rit exits as timeout(1) does
+func (e TimeoutError) ExitCode() int {
+	return TimeoutExitCode
+}
+
+// InterruptedError is a formula stopped because rit was interrupted, by a Ctrl+C or a SIGTERM
+type InterruptedError struct {
+	Signal os.Signal
+}
+
+func (e InterruptedError) Error() string {
+	return fmt.Sprintf("the formula was stopped by %v", e.Signal)
+}
+
+// ExitCode returns 128 plus the number of the signal, as the shells do
+func (e InterruptedError) ExitCode() int {
+	if s, ok := e.Signal.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
+
+// stopFunc asks the formula to exit, it is killed when it is still running after the grace
+type stopFunc func(cmd *exec.Cmd, grace time.Duration)
+
+// stopProcess sends a SIGTERM to the formula process, it is killed
+// right away where the signal isn't supported, as on windows
+func stopProcess(cmd *exec.Cmd, _ time.Duration) {
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		_ = cmd.Process.Kill()
+	}
+}
+
+// stopContainer stops the formula container with docker stop, that sends a SIGTERM
+// to the formula and kills the container when it is still running after the grace
+func stopContainer(engine, name string) stopFunc {
+	return func(_ *exec.Cmd, grace time.Duration) {
+		secs := strconv.Itoa(int(grace.Round(time.Second).Seconds()))
+		_, _ = dockerOutput(engine, dockerStopCmd, "-t", secs, name)
+	}
+}
+
+// runGraceful runs the formula command, when rit is interrupted or the formula runs for longer than
+// the timeout it is stopped in two phases to have a chance to clean up, see terminate. A formula
+// stopped by the timeout returns a TimeoutError and an interrupted one an InterruptedError, so the
+// runners clean up its run. A zero timeout lets the formula run until it exits.
+func runGraceful(cmd *exec.Cmd, timeout, grace time.Duration, stop stopFunc) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case s := <-sig:
+		_ = terminate(cmd, done, grace, stop, sig)
+		return InterruptedError{Signal: s}
+	case <-expired:
+		prompt.Warning(fmt.Sprintf(msgTimedOut, timeout))
+		_ = terminate(cmd, done, grace, stop, sig)
+		return TimeoutError{Timeout: timeout}
+	}
+}
+
+// cleanFailed removes what a failed run left behind: its temp workspace, with the env file
+// of a docker run, and the container unless it is a session. The runners defer it once the
+// workspace is set up, so it runs however the run ends, e.g. on a Ctrl+C or a failed input.
+func cleanFailed(setup formula.Setup, container bool, err *error) {
+	if *err == nil {
+		return
+	}
+	if container {
+		_ = removeContainer(setup.Engine, setup.ContainerId)
+	}
+	removeWorkDir(setup.TmpDir)
+}
+
+// terminate asks the formula to exit and kills it when it is still running after the grace or
+// on another signal of sig, e.g. a second Ctrl+C. done receives the result of the formula command.
+func terminate(cmd *exec.Cmd, done <-chan error, grace time.Duration, stop stopFunc, sig <-chan os.Signal) error {
+	prompt.Warning(fmt.Sprintf(msgTerminating, grace))
+	go stop(cmd, grace)
+
+	select {
+	case err := <-done:
+		return err
+	case <-sig:
+	case <-time.After(grace):
+	}
+	prompt.Warning(msgKilled)
+	_ = cmd.Process.Kill()
+	return <-done
+}
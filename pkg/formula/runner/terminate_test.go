@@ -0,0 +1,156 @@
+package runner
+
+import (
+	"bufio"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestTerminate(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		wantCode int
+		wantKill bool
+	}{
+		{
+			name:     "formula exiting on SIGTERM",
+			script:   `trap 'exit 3' TERM; echo ready; while :; do sleep 0.05; done`,
+			wantCode: 3,
+		},
+		{
+			name:     "formula ignoring SIGTERM is killed after the grace",
+			script:   `trap '' TERM; echo ready; while :; do sleep 0.05; done`,
+			wantKill: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command("sh", "-c", tt.script)
+			out, err := cmd.StdoutPipe()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := cmd.Start(); err != nil {
+				t.Fatal(err)
+			}
+			// the trap is set once the formula prints ready
+			if _, err := bufio.NewReader(out).ReadString('\n'); err != nil {
+				t.Fatal(err)
+			}
+
+			done := make(chan error, 1)
+			go func() { done <- cmd.Wait() }()
+
+			start := time.Now()
+			err = terminate(cmd, done, 200*time.Millisecond, stopProcess, nil)
+
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				t.Fatalf("terminate got %v, want the formula exit error", err)
+			}
+			if tt.wantKill && exitErr.ExitCode() != -1 {
+				t.Errorf("terminate got exit code %d, want the formula killed", exitErr.ExitCode())
+			}
+			if !tt.wantKill && exitErr.ExitCode() != tt.wantCode {
+				t.Errorf("terminate got exit code %d, want %d", exitErr.ExitCode(), tt.wantCode)
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("terminate took %v, want it bounded by the grace", elapsed)
+			}
+		})
+	}
+}
+
+func TestRunGracefulTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		timeout time.Duration
+		wantErr error
+	}{
+		{
+			name:    "formula running for longer than the timeout",
+			script:  `trap '' TERM; while :; do sleep 0.05; done`,
+			timeout: 100 * time.Millisecond,
+			wantErr: TimeoutError{Timeout: 100 * time.Millisecond},
+		},
+		{
+			name:    "formula exiting before the timeout",
+			script:  `exit 0`,
+			timeout: time.Minute,
+		},
+		{
+			name:   "no timeout",
+			script: `sleep 0.1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			err := runGraceful(exec.Command("sh", "-c", tt.script), tt.timeout, 100*time.Millisecond, stopProcess)
+			if err != tt.wantErr {
+				t.Fatalf("runGraceful() = %v, want %v", err, tt.wantErr)
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("runGraceful took %v, want it bounded by the timeout and the grace", elapsed)
+			}
+		})
+	}
+}
+
+func TestRunGracefulInterrupted(t *testing.T) {
+	cmd := exec.Command("sh", "-c", `trap 'exit 0' TERM; sleep 0.3; echo ready; while :; do sleep 0.05; done`)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- runGraceful(cmd, 0, time.Second, stopProcess) }()
+	// the formula is ready once runGraceful handles the signals
+	if _, err := bufio.NewReader(out).ReadString('\n'); err != nil {
+		t.Fatal(err)
+	}
+	rit, _ := os.FindProcess(os.Getpid())
+	if err := rit.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+
+	err = <-done
+	want := InterruptedError{Signal: os.Interrupt}
+	if err != want || want.ExitCode() != 130 {
+		t.Errorf("runGraceful() = %v, want %v exiting with 130", err, want)
+	}
+}
+
+func TestCleanFailed(t *testing.T) {
+	for _, tt := range []struct {
+		err         error
+		wantRemoved bool
+	}{
+		{err: TimeoutError{Timeout: time.Second}, wantRemoved: true},
+		{err: InterruptedError{Signal: os.Interrupt}, wantRemoved: true},
+		{err: errors.New("formula failed"), wantRemoved: true},
+		{err: nil},
+	} {
+		dir, err := ioutil.TempDir("", "rit-failed")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		cleanFailed(formula.Setup{TmpDir: dir}, false, &tt.err)
+		if _, err := os.Stat(dir); os.IsNotExist(err) != tt.wantRemoved {
+			t.Errorf("cleanFailed(%v) removed the workspace %v, want %v", tt.err, os.IsNotExist(err), tt.wantRemoved)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package runner
+
+import (
+	"bytes"
+	"io"
+	"time"
+)
+
+const timestampLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// timestampNow is a var so the timestamps are fixed on tests
+var timestampNow = time.Now
+
+// timestampWriter prefixes each line with the time it was written and the formula command,
+// e.g. "2020-07-20T10:00:00.000-03:00 [rit aws create] done", so the output of the formulas
+// run at the same time, e.g. by rit run batch, can be told apart. It is written by a
+// lineWriter, so it always gets whole lines.
+type timestampWriter struct {
+	w       io.Writer
+	command string
+}
+
+func (t timestampWriter) Write(p []byte) (int, error) {
+	prefix := []byte(timestampNow().Format(timestampLayout) + " [" + t.command + "] ")
+
+	var out []byte
+	for _, line := range bytes.SplitAfter(p, []byte("\n")) {
+		if len(line) > 0 {
+			out = append(append(out, prefix...), line...)
+		}
+	}
+
+	if _, err := t.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,26 @@
+package runner
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestTimestampWriter(t *testing.T) {
+	defer func(n func() time.Time) { timestampNow = n }(timestampNow)
+	timestampNow = func() time.Time { return time.Date(2020, 7, 20, 10, 0, 0, 0, time.UTC) }
+
+	var out bytes.Buffer
+	w, _ := newLineWriters(timestampWriter{&out, "rit aws create"}, nil)
+	_, _ = w.Write([]byte("creating\ncreated"))
+	_, _ = w.Write([]byte(" the bucket\n\nwithout newline"))
+	_ = w.Flush()
+
+	want := "2020-07-20T10:00:00.000Z [rit aws create] creating\n" +
+		"2020-07-20T10:00:00.000Z [rit aws create] created the bucket\n" +
+		"2020-07-20T10:00:00.000Z [rit aws create] \n" +
+		"2020-07-20T10:00:00.000Z [rit aws create] without newline"
+	if out.String() != want {
+		t.Errorf("timestampWriter wrote\n%q, want\n%q", out.String(), want)
+	}
+}
@@ -0,0 +1,84 @@
+package runner
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+)
+
+const (
+	msgInvalidVolume    = "invalid volume %q, it must be HOST:CONTAINER or HOST:CONTAINER:ro with absolute paths, e.g. ~/.kube:/root/.kube:ro"
+	msgVolumeNotAllowed = "the volume %q mounts %s, which isn't on the formula.volumes config, allow it with rit set config"
+)
+
+// volumeArgs returns the docker run args mounting the volumes of the formula config.json and
+// the --volume ones of the definition, their host paths on the allowed ones
+func volumeArgs(def formula.Definition, setup formula.Setup, allowed []string) ([]string, error) {
+	volumes := append(append([]string{}, setup.Config.Volumes...), def.Volumes...)
+
+	var args []string
+	for _, v := range volumes {
+		host, container, mode, ok := parseVolume(v)
+		if !ok {
+			return nil, prompt.NewError(fmt.Sprintf(msgInvalidVolume, v))
+		}
+		if !volumeAllowed(host, allowed) {
+			return nil, prompt.NewError(fmt.Sprintf(msgVolumeNotAllowed, v, host))
+		}
+
+		mount := host + ":" + container
+		if mode != "" {
+			mount += ":" + mode
+		}
+		args = append(args, "-v", mount)
+	}
+	return args, nil
+}
+
+// parseVolume splits a HOST:CONTAINER[:ro|rw] volume, the host path may start with ~ and
+// have a drive on Windows, so the container path is the last one
+func parseVolume(v string) (host, container, mode string, ok bool) {
+	if strings.HasSuffix(v, ":ro") || strings.HasSuffix(v, ":rw") {
+		v, mode = v[:len(v)-3], v[len(v)-2:]
+	}
+
+	i := strings.LastIndex(v, ":")
+	if i < 0 {
+		return "", "", "", false
+	}
+	host, container = expandHome(v[:i]), v[i+1:]
+	return host, container, mode, filepath.IsAbs(host) && path.IsAbs(container)
+}
+
+// volumeAllowed tells whether the host path is on an allowed path, the symlinks resolved
+// so that a link doesn't mount what it points to out of them
+func volumeAllowed(host string, allowed []string) bool {
+	resolved := resolvePath(host)
+	for _, a := range allowed {
+		a = resolvePath(expandHome(a))
+		if resolved == a || strings.HasPrefix(resolved, a+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func resolvePath(p string) string {
+	if resolved, err := filepath.EvalSymlinks(p); err == nil {
+		return resolved
+	}
+	return filepath.Clean(p)
+}
+
+// expandHome replaces the ~ a path starts with by the home dir of the user
+func expandHome(p string) string {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		return filepath.Join(api.UserHomeDir(), p[1:])
+	}
+	return p
+}
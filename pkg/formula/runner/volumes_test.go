@@ -0,0 +1,69 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+)
+
+func TestVolumeArgs(t *testing.T) {
+	home, err := ioutil.TempDir("", "rit-volumes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+	home, _ = filepath.EvalSymlinks(home)
+
+	kube := filepath.Join(home, ".kube")
+	_ = os.Mkdir(kube, 0755)
+	secrets := filepath.Join(home, "secrets")
+	_ = os.Mkdir(secrets, 0755)
+	link := filepath.Join(kube, "secrets")
+	_ = os.Symlink(secrets, link)
+
+	allowed := []string{kube, "/etc/ssl/certs", "~/.config/gcloud"}
+	gcloud := filepath.Join(api.UserHomeDir(), ".config", "gcloud")
+
+	tests := []struct {
+		name     string
+		config   []string
+		def      []string
+		wantArgs []string
+		wantErr  bool
+	}{
+		{name: "no volumes"},
+		{
+			name:     "config.json and --volume",
+			config:   []string{kube + ":/root/.kube:ro"},
+			def:      []string{"/etc/ssl/certs/ca.pem:/etc/ca.pem"},
+			wantArgs: []string{"-v", kube + ":/root/.kube:ro", "-v", "/etc/ssl/certs/ca.pem:/etc/ca.pem"},
+		},
+		{name: "home dir", def: []string{"~/.config/gcloud:/root/.config/gcloud"}, wantArgs: []string{"-v", gcloud + ":/root/.config/gcloud"}},
+		{name: "not allowed", def: []string{secrets + ":/secrets"}, wantErr: true},
+		{name: "prefix of an allowed path", def: []string{kube + "-old:/root/.kube"}, wantErr: true},
+		{name: "link out of an allowed path", def: []string{link + ":/secrets"}, wantErr: true},
+		{name: "parent of an allowed path", def: []string{kube + "/..:/root"}, wantErr: true},
+		{name: "relative host", def: []string{".kube:/root/.kube"}, wantErr: true},
+		{name: "relative container", config: []string{kube + ":.kube"}, wantErr: true},
+		{name: "no container", def: []string{kube}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := formula.Definition{Volumes: tt.def}
+			setup := formula.Setup{Config: formula.Config{Volumes: tt.config}}
+			got, err := volumeArgs(def, setup, allowed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("volumeArgs() got error %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.wantArgs) {
+				t.Errorf("volumeArgs() got %v, want %v", got, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package formula
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// TreeSchemaVersion is the newest tree.json schema this rit understands
+	TreeSchemaVersion = 2
+	// ConfigSchemaVersion is the newest formula config.json schema this rit understands
+	ConfigSchemaVersion = 2
+
+	schemaVersionKey = "schemaVersion"
+	treeFile         = "tree.json"
+	configFile       = "config.json"
+	msgNewerSchema   = "repo %q requires a newer rit (%s schema %d > supported %d), run rit upgrade"
+	msgInvalidSchema = "invalid %s of the repo %q: %v"
+)
+
+var errEmptyFile = errors.New("empty file")
+
+// SchemaError is returned when a file was written with a schema newer than the supported one
+type SchemaError struct {
+	Repo      string
+	File      string
+	Version   int
+	Supported int
+}
+
+func (e SchemaError) Error() string {
+	return fmt.Sprintf(msgNewerSchema, e.Repo, e.File, e.Version, e.Supported)
+}
+
+// migration upgrades a decoded document from its schema to the next one
+type migration func(doc map[string]interface{})
+
+// treeMigrations are indexed by the schema they upgrade from.
+// Schema 1 is the unversioned tree, where group commands carried
+// a formula object with empty fields and an empty "Repo" field.
+var treeMigrations = map[int]migration{
+	1: treeV1ToV2,
+}
+
+// configMigrations are indexed by the schema they upgrade from.
+// Schema 1 is the unversioned config, which allowed bool and number
+// literals on the default and items of the inputs.
+var configMigrations = map[int]migration{
+	1: configV1ToV2,
+}
+
+// MigrateTree upgrades the tree.json of the repo to the current schema,
+// it returns b unchanged when it is already current
+func MigrateTree(b []byte, repo string) ([]byte, error) {
+	return migrate(b, repo, treeFile, TreeSchemaVersion, treeMigrations)
+}
+
+// MigrateConfig upgrades the formula config.json of the repo to the current schema,
+// it returns b unchanged when it is already current
+func MigrateConfig(b []byte, repo string) ([]byte, error) {
+	return migrate(b, repo, configFile, ConfigSchemaVersion, configMigrations)
+}
+
+// UnmarshalTree decodes the tree.json of the repo migrating old schemas,
+// a current tree is decoded once
+func UnmarshalTree(b []byte, repo string) (Tree, error) {
+	var t Tree
+	if err := json.Unmarshal(b, &t); err == nil && t.SchemaVersion == TreeSchemaVersion {
+		return t, nil
+	}
+
+	b, err := MigrateTree(b, repo)
+	if err != nil {
+		return Tree{}, err
+	}
+
+	t = Tree{}
+	if err := json.Unmarshal(b, &t); err != nil {
+		return Tree{}, fmt.Errorf(msgInvalidSchema, treeFile, repo, err)
+	}
+	return t, nil
+}
+
+// UnmarshalConfig decodes the formula config.json of the repo migrating old schemas,
+// a current config is decoded once
+func UnmarshalConfig(b []byte, repo string) (Config, error) {
+	var c Config
+	if err := json.Unmarshal(b, &c); err == nil && c.SchemaVersion == ConfigSchemaVersion {
+		return c, nil
+	}
+
+	b, err := MigrateConfig(b, repo)
+	if err != nil {
+		return Config{}, err
+	}
+
+	c = Config{}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return Config{}, fmt.Errorf(msgInvalidSchema, configFile, repo, err)
+	}
+	return c, nil
+}
+
+func migrate(b []byte, repo, file string, supported int, migrations map[int]migration) ([]byte, error) {
+	var doc map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		if err == io.EOF {
+			err = errEmptyFile
+		}
+		return nil, fmt.Errorf(msgInvalidSchema, file, repo, err)
+	}
+
+	version, err := schemaVersion(doc)
+	if err != nil {
+		return nil, fmt.Errorf(msgInvalidSchema, file, repo, err)
+	}
+
+	if version > supported {
+		return nil, SchemaError{Repo: repo, File: file, Version: version, Supported: supported}
+	}
+	if version == supported {
+		return b, nil
+	}
+
+	for ; version < supported; version++ {
+		migrations[version](doc)
+	}
+	doc[schemaVersionKey] = supported
+
+	return json.MarshalIndent(doc, "", "\t")
+}
+
+// schemaVersion reads the schema of the document, files without one are schema 1
+func schemaVersion(doc map[string]interface{}) (int, error) {
+	v, ok := doc[schemaVersionKey]
+	if !ok {
+		return 1, nil
+	}
+
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("%s must be a number", schemaVersionKey)
+	}
+
+	i, err := n.Int64()
+	if err != nil || i < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer", schemaVersionKey)
+	}
+	return int(i), nil
+}
+
+func treeV1ToV2(doc map[string]interface{}) {
+	commands, _ := doc["commands"].([]interface{})
+	for _, c := range commands {
+		cmd, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if r, ok := cmd["Repo"].(string); ok && r == "" {
+			delete(cmd, "Repo")
+		}
+
+		if f, ok := cmd["formula"].(map[string]interface{}); ok && isEmpty(f) {
+			delete(cmd, "formula")
+		}
+	}
+}
+
+func configV1ToV2(doc map[string]interface{}) {
+	inputs, _ := doc["inputs"].([]interface{})
+	for _, i := range inputs {
+		in, ok := i.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if d, ok := in["default"]; ok && d != nil {
+			in["default"] = toString(d)
+		}
+
+		if items, ok := in["items"].([]interface{}); ok {
+			for j, item := range items {
+				items[j] = toString(item)
+			}
+		}
+	}
+}
+
+// isEmpty tells whether all the fields of the object are empty strings
+func isEmpty(obj map[string]interface{}) bool {
+	for _, v := range obj {
+		if s, ok := v.(string); !ok || s != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func toString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,131 @@
+package formula
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", "schema", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestUnmarshalTreeV1(t *testing.T) {
+	tree, err := UnmarshalTree(readFixture(t, "tree_v1.json"), "commons")
+	if err != nil {
+		t.Fatalf("UnmarshalTree error = %v", err)
+	}
+
+	if tree.SchemaVersion != TreeSchemaVersion {
+		t.Errorf("UnmarshalTree got schema %d, want %d", tree.SchemaVersion, TreeSchemaVersion)
+	}
+	if len(tree.Commands) != 2 {
+		t.Fatalf("UnmarshalTree got %d commands, want 2", len(tree.Commands))
+	}
+	if tree.Commands[0].Formula != nil {
+		t.Errorf("UnmarshalTree kept the empty formula of the group command: %+v", tree.Commands[0].Formula)
+	}
+	if f := tree.Commands[1].Formula; f == nil || f.Path != "testing/formula" {
+		t.Errorf("UnmarshalTree got formula %+v, want testing/formula", f)
+	}
+	if string(tree.Commands[1].Extra["owner"]) != `"platform-team"` {
+		t.Errorf("UnmarshalTree lost the unknown command field, got %v", tree.Commands[1].Extra)
+	}
+}
+
+func TestUnmarshalTreeV2RoundTrip(t *testing.T) {
+	tree, err := UnmarshalTree(readFixture(t, "tree_v2.json"), "commons")
+	if err != nil {
+		t.Fatalf("UnmarshalTree error = %v", err)
+	}
+
+	b, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{`"maintainers":["platform-team"]`, `"owner":"platform-team"`, `"schemaVersion":2`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("round trip got %s, want it to contain %s", b, want)
+		}
+	}
+}
+
+func TestUnmarshalTreeNewerSchema(t *testing.T) {
+	_, err := UnmarshalTree(readFixture(t, "tree_v3.json"), "commons")
+
+	var schemaErr SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("UnmarshalTree got error %v, want SchemaError", err)
+	}
+
+	want := `repo "commons" requires a newer rit (tree.json schema 3 > supported 2), run rit upgrade`
+	if err.Error() != want {
+		t.Errorf("UnmarshalTree got error %q, want %q", err, want)
+	}
+}
+
+func TestUnmarshalConfigV1(t *testing.T) {
+	config, err := UnmarshalConfig(readFixture(t, "config_v1.json"), "commons")
+	if err != nil {
+		t.Fatalf("UnmarshalConfig error = %v", err)
+	}
+
+	if config.SchemaVersion != ConfigSchemaVersion {
+		t.Errorf("UnmarshalConfig got schema %d, want %d", config.SchemaVersion, ConfigSchemaVersion)
+	}
+
+	number := config.Inputs[1]
+	if number.Default != "8080" || strings.Join(number.Items, ",") != "8080,9090" {
+		t.Errorf("UnmarshalConfig got number input %+v", number)
+	}
+
+	boolean := config.Inputs[2]
+	if boolean.Default != "false" || strings.Join(boolean.Items, ",") != "false,true" {
+		t.Errorf("UnmarshalConfig got bool input %+v", boolean)
+	}
+
+	if c := config.Inputs[0].Cache; !c.Active || c.Qty != 6 {
+		t.Errorf("UnmarshalConfig got cache %+v", c)
+	}
+}
+
+func TestUnmarshalConfigV2RoundTrip(t *testing.T) {
+	config, err := UnmarshalConfig(readFixture(t, "config_v2.json"), "commons")
+	if err != nil {
+		t.Fatalf("UnmarshalConfig error = %v", err)
+	}
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{`"timeout":"5m"`, `"tooltip":"Any text"`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("round trip got %s, want it to contain %s", b, want)
+		}
+	}
+}
+
+func TestMigrateCurrentSchema(t *testing.T) {
+	in := readFixture(t, "tree_v2.json")
+	out, err := MigrateTree(in, "commons")
+	if err != nil {
+		t.Fatalf("MigrateTree error = %v", err)
+	}
+	if string(out) != string(in) {
+		t.Error("MigrateTree should not rewrite a tree in the current schema")
+	}
+
+	if _, err := MigrateTree([]byte(`{"schemaVersion": "two"}`), "commons"); err == nil {
+		t.Error("MigrateTree with an invalid schema version should return an error")
+	}
+}
@@ -1,12 +1,34 @@
 package formula
 
-import "github.com/ZupIT/ritchie-cli/pkg/api"
+import (
+	"encoding/json"
+
+	"github.com/ZupIT/ritchie-cli/pkg/api"
+	"github.com/ZupIT/ritchie-cli/pkg/json/jsonutil"
+)
 
 type Tree struct {
-	Commands api.Commands `json:"commands"`
+	SchemaVersion int                        `json:"schemaVersion,omitempty"`
+	Commands      api.Commands               `json:"commands"`
+	Extra         map[string]json.RawMessage `json:"-"`
 }
 
 type TreeManager interface {
 	Tree() (map[string]Tree, error)
 	MergedTree(core bool) Tree
 }
+
+func (t *Tree) UnmarshalJSON(b []byte) error {
+	type tree Tree
+	extra, err := jsonutil.UnmarshalExtra(b, (*tree)(t))
+	if err != nil {
+		return err
+	}
+	t.Extra = extra
+	return nil
+}
+
+func (t Tree) MarshalJSON() ([]byte, error) {
+	type tree Tree
+	return jsonutil.MarshalExtra(tree(t), t.Extra)
+}
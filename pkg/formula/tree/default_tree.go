@@ -1,13 +1,15 @@
 package tree
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 
 	"github.com/ZupIT/ritchie-cli/pkg/api"
 	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
+	"github.com/ZupIT/ritchie-cli/pkg/rcontext"
 )
 
 const (
@@ -21,10 +23,11 @@ type Manager struct {
 	ritchieHome string
 	repoLister  formula.RepoLister
 	coreCmds    []api.Command
+	ctxFinder   rcontext.Finder
 }
 
-func NewTreeManager(ritchieHome string, rl formula.RepoLister, coreCmds []api.Command) Manager {
-	return Manager{ritchieHome: ritchieHome, repoLister: rl, coreCmds: coreCmds}
+func NewTreeManager(ritchieHome string, rl formula.RepoLister, coreCmds []api.Command, cf rcontext.Finder) Manager {
+	return Manager{ritchieHome: ritchieHome, repoLister: rl, coreCmds: coreCmds, ctxFinder: cf}
 }
 
 func (d Manager) Tree() (map[string]formula.Tree, error) {
@@ -41,7 +44,7 @@ func (d Manager) Tree() (map[string]formula.Tree, error) {
 	if err != nil {
 		return nil, err
 	}
-	for _, v := range rr {
+	for _, v := range d.activeRepos(rr) {
 		treeRepo, err := d.treeByRepo(v.Name)
 		if err != nil {
 			return nil, err
@@ -78,9 +81,13 @@ func (d Manager) MergedTree(core bool) formula.Tree {
 	}
 
 	rr, _ := d.repoLister.List()
-	for _, r := range rr {
+	for _, r := range d.activeRepos(rr) {
 		treeRepo, err := d.treeByRepo(r.Name)
 		if err != nil {
+			var schemaErr formula.SchemaError
+			if errors.As(err, &schemaErr) {
+				prompt.Warning(err.Error())
+			}
 			continue
 		}
 		var cc []api.Command
@@ -98,17 +105,37 @@ func (d Manager) MergedTree(core bool) formula.Tree {
 	return treeMain
 }
 
+// activeRepos filters the repositories available in the current context
+func (d Manager) activeRepos(rr []formula.Repository) []formula.Repository {
+	if d.ctxFinder == nil {
+		return rr
+	}
+
+	ctx, err := d.ctxFinder.Find()
+	if err != nil {
+		return rr
+	}
+
+	var active []formula.Repository
+	for _, r := range rr {
+		if r.ActiveIn(ctx.Current) {
+			active = append(active, r)
+		}
+	}
+	return active
+}
+
 func (d Manager) localTree() (formula.Tree, error) {
 	treeCmdFile := fmt.Sprintf(treeLocalCmdPattern, d.ritchieHome)
-	return loadTree(treeCmdFile)
+	return loadTree(treeCmdFile, "local")
 }
 
 func (d Manager) treeByRepo(repo string) (formula.Tree, error) {
 	treeCmdFile := fmt.Sprintf(treeRepoCmdPattern, d.ritchieHome, repo)
-	return loadTree(treeCmdFile)
+	return loadTree(treeCmdFile, repo)
 }
 
-func loadTree(treeCmdFile string) (formula.Tree, error) {
+func loadTree(treeCmdFile, repo string) (formula.Tree, error) {
 	tree := formula.Tree{}
 	if !fileutil.Exists(treeCmdFile) {
 		return tree, nil
@@ -119,9 +146,5 @@ func loadTree(treeCmdFile string) (formula.Tree, error) {
 		return tree, err
 	}
 
-	if err = json.Unmarshal(treeFile, &tree); err != nil {
-		return tree, err
-	}
-
-	return tree, nil
+	return formula.UnmarshalTree(treeFile, repo)
 }
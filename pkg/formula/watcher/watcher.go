@@ -7,7 +7,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/kaduartur/go-cli-spinner/pkg/spinner"
 	"github.com/radovskyb/watcher"
 
 	"github.com/ZupIT/ritchie-cli/pkg/formula"
@@ -61,7 +60,7 @@ func (w *WatchManager) Watch(workspacePath, formulaPath string) {
 
 func (w WatchManager) build(workspacePath, formulaPath string) {
 	buildInfo := prompt.Bold("Building formula...")
-	s := spinner.StartNew(buildInfo)
+	s := prompt.StartSpinner(buildInfo)
 	time.Sleep(2 * time.Second)
 
 	if err := w.formula.Build(workspacePath, formulaPath); err != nil {
@@ -0,0 +1,82 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+var ErrIncompleteClientCert = errors.New("the TLS client certificate and key must be informed together, use --client-cert and --client-key")
+
+// TLS holds the PEM files used on the TLS connections, all of them are optional.
+// CertFile and KeyFile are the client certificate presented to servers requiring
+// mutual TLS, CAFile is a CA bundle trusted besides the system CAs.
+type TLS struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+}
+
+// Empty tells whether no TLS file was informed
+func (t TLS) Empty() bool {
+	return t.CertFile == "" && t.KeyFile == "" && t.CAFile == ""
+}
+
+// Config builds the tls config with the client certificate and CAs,
+// it returns nil when no TLS file was informed
+func (t TLS) Config() (*tls.Config, error) {
+	if t.Empty() {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if t.CertFile != "" || t.KeyFile != "" {
+		if t.CertFile == "" || t.KeyFile == "" {
+			return nil, ErrIncompleteClientCert
+		}
+
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the TLS client certificate %q with the key %q: %v", t.CertFile, t.KeyFile, err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if t.CAFile != "" {
+		pem, err := ioutil.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the TLS CA file: %v", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificate found on the TLS CA file %q", t.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	return cfg, nil
+}
+
+// New creates an http client presenting the TLS files, a zero timeout means no timeout
+func New(t TLS, timeout time.Duration) (*http.Client, error) {
+	cfg, err := t.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: timeout}
+	if cfg != nil {
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		tr.TLSClientConfig = cfg
+		client.Transport = tr
+	}
+	return client, nil
+}
@@ -0,0 +1,139 @@
+package httpclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeClientCert generates a self-signed client certificate and writes its PEM files
+func writeClientCert(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "rit"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	writePem(t, certFile, "CERTIFICATE", der)
+	writePem(t, keyFile, "EC PRIVATE KEY", keyDer)
+	return cert, certFile, keyFile
+}
+
+func writePem(t *testing.T, file, kind string, der []byte) {
+	b := pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der})
+	if err := ioutil.WriteFile(file, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpclient")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientCert, certFile, keyFile := writeClientCert(t, dir)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := filepath.Join(dir, "ca.pem")
+	writePem(t, caFile, "CERTIFICATE", server.Certificate().Raw)
+	badFile := filepath.Join(dir, "bad.pem")
+	if err := ioutil.WriteFile(badFile, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		in         TLS
+		wantNewErr bool
+		wantGetErr bool
+	}{
+		{
+			name: "client certificate and CA",
+			in:   TLS{CertFile: certFile, KeyFile: keyFile, CAFile: caFile},
+		},
+		{
+			name:       "without client certificate",
+			in:         TLS{CAFile: caFile},
+			wantGetErr: true,
+		},
+		{
+			name:       "certificate without key",
+			in:         TLS{CertFile: certFile, CAFile: caFile},
+			wantNewErr: true,
+		},
+		{
+			name:       "invalid key",
+			in:         TLS{CertFile: certFile, KeyFile: badFile},
+			wantNewErr: true,
+		},
+		{
+			name:       "invalid CA",
+			in:         TLS{CAFile: badFile},
+			wantNewErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := New(tt.in, 5*time.Second)
+			if (err != nil) != tt.wantNewErr {
+				t.Fatalf("New(%s) error = %v, wantErr %v", tt.name, err, tt.wantNewErr)
+			}
+			if err != nil {
+				return
+			}
+
+			resp, err := client.Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if (err != nil) != tt.wantGetErr {
+				t.Errorf("Get(%s) error = %v, wantErr %v", tt.name, err, tt.wantGetErr)
+			}
+		})
+	}
+}
+
+func TestNewWithoutTLSFiles(t *testing.T) {
+	client, err := New(TLS{}, time.Second)
+	if err != nil || client.Transport != nil {
+		t.Errorf("New without TLS files got transport %v and error %v, want the default transport", client.Transport, err)
+	}
+}
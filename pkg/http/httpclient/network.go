@@ -0,0 +1,110 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// retryDelay is the wait before the first retry, it doubles on each retry.
+// It is a var so the retries don't wait on tests.
+var retryDelay = 500 * time.Millisecond
+
+// Network is the policy of the network requests, see the network.timeout and network.retries
+// configs. A zero Timeout keeps the default timeout of each operation, e.g. the version check.
+type Network struct {
+	Timeout time.Duration
+	Retries int
+}
+
+// Client returns a copy of the client with the policy, timeout is the default of the
+// operation, zero meaning no timeout. With retries the timeout is of each attempt, the
+// requests with a body, e.g. the metrics, are never retried as they can't be replayed.
+func (n Network) Client(c *http.Client, timeout time.Duration) *http.Client {
+	client := *c
+	if n.Timeout > 0 {
+		timeout = n.Timeout
+	}
+
+	if n.Retries <= 0 {
+		client.Timeout = timeout
+		return &client
+	}
+
+	next := c.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	client.Timeout = 0
+	client.Transport = retryTransport{next: next, retries: n.Retries, timeout: timeout}
+	return &client
+}
+
+// retryTransport retries the requests without a body that fail or get a status
+// telling the server is unavailable, each attempt limited by the timeout
+type retryTransport struct {
+	next    http.RoundTripper
+	retries int
+	timeout time.Duration
+}
+
+func (t retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	retryable := req.Body == nil || req.Body == http.NoBody
+	delay := retryDelay
+	for attempt := 0; ; attempt++ {
+		resp, err := t.attempt(req)
+		if !retryable || attempt == t.retries || !retry(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// attempt sends the request, its timeout is only cancelled once the body is closed
+func (t retryTransport) attempt(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// retry tells whether the request failed on the network or the server is unavailable
+func retry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
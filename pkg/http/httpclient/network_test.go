@@ -0,0 +1,123 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNetwork_Client(t *testing.T) {
+	retryDelay = time.Millisecond
+	defer func() { retryDelay = 500 * time.Millisecond }()
+
+	tests := []struct {
+		name       string
+		network    Network
+		method     string
+		failures   int32
+		slow       bool
+		wantStatus int
+		wantCalls  int32
+		wantErr    bool
+	}{
+		{
+			name:       "retries until the server is available",
+			network:    Network{Retries: 2},
+			failures:   2,
+			wantStatus: http.StatusOK,
+			wantCalls:  3,
+		},
+		{
+			name:       "retries exhausted",
+			network:    Network{Retries: 1},
+			failures:   2,
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  2,
+		},
+		{
+			name:       "no retries",
+			failures:   1,
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  1,
+		},
+		{
+			name:       "request with a body is not retried",
+			network:    Network{Retries: 2},
+			method:     http.MethodPost,
+			failures:   1,
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  1,
+		},
+		{
+			name:       "the timeout is of each attempt",
+			network:    Network{Timeout: 50 * time.Millisecond, Retries: 1},
+			failures:   1,
+			slow:       true,
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:      "timeout",
+			network:   Network{Timeout: 50 * time.Millisecond},
+			failures:  1,
+			slow:      true,
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&calls, 1) > tt.failures {
+					_, _ = w.Write([]byte("ok"))
+					return
+				}
+				if tt.slow {
+					time.Sleep(200 * time.Millisecond)
+				}
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			client := tt.network.Client(server.Client(), time.Second)
+			var resp *http.Response
+			var err error
+			if tt.method == http.MethodPost {
+				resp, err = client.Post(server.URL, "text/plain", strings.NewReader("body"))
+			} else {
+				resp, err = client.Get(server.URL)
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("request error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				_ = resp.Body.Close()
+				if resp.StatusCode != tt.wantStatus {
+					t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+				}
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("server got %d calls, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestNetwork_ClientTimeout(t *testing.T) {
+	c := &http.Client{}
+	if got := (Network{}).Client(c, time.Second).Timeout; got != time.Second {
+		t.Errorf("default timeout = %v, want 1s", got)
+	}
+	if got := (Network{Timeout: 10 * time.Second}).Client(c, time.Second).Timeout; got != 10*time.Second {
+		t.Errorf("network timeout = %v, want 10s", got)
+	}
+	if c.Timeout != 0 {
+		t.Errorf("the client was changed, its timeout is %v", c.Timeout)
+	}
+}
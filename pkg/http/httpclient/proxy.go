@@ -0,0 +1,13 @@
+package httpclient
+
+import "net/http"
+
+// Proxy returns the proxy of a rit request from HTTPS_PROXY, HTTP_PROXY and NO_PROXY,
+// or their lowercase names. NO_PROXY is a comma separated list of:
+//   - host names, matching their subdomains too, as corp.com, or only the
+//     subdomains, as .corp.com and *.corp.com;
+//   - IPs and CIDRs, as 10.1.2.3 and 10.0.0.0/8, matching the hosts informed by IP;
+//   - any of them with a port, as mirror.corp.com:8443, or * for every host.
+// The requests to localhost and the loopback IPs never go through the proxy.
+// Every transport created by rit must use it, a transport without Proxy ignores the env.
+var Proxy = http.ProxyFromEnvironment
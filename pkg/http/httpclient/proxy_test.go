@@ -0,0 +1,59 @@
+package httpclient
+
+import (
+	"net/http"
+	"os"
+	"testing"
+)
+
+const proxyURL = "http://proxy.corp.example:3128"
+
+// TestMain sets the proxy env before any request of the package tests, since
+// http.ProxyFromEnvironment reads it only once. The test servers listen on the
+// loopback, which is never proxied.
+func TestMain(m *testing.M) {
+	_ = os.Setenv("HTTP_PROXY", proxyURL)
+	_ = os.Setenv("HTTPS_PROXY", proxyURL)
+	_ = os.Setenv("NO_PROXY", "internal.example,.mirror.example,*.repo.example,10.0.0.0/8,versions.example:8443")
+	os.Exit(m.Run())
+}
+
+func TestProxy(t *testing.T) {
+	tests := []struct {
+		url     string
+		proxied bool
+	}{
+		{url: "https://commons-repo.ritchiecli.io/stable.txt", proxied: true},
+		{url: "https://internal.example/tree.json"},
+		{url: "https://repo.internal.example/tree.json"},
+		{url: "https://notinternal.example/tree.json", proxied: true},
+		{url: "https://a.mirror.example/tree.json"},
+		{url: "https://mirror.example/tree.json", proxied: true},
+		{url: "https://commons.repo.example/tree.json"},
+		{url: "https://repo.example/tree.json", proxied: true},
+		{url: "http://10.1.2.3/tree.json"},
+		{url: "http://11.1.2.3/tree.json", proxied: true},
+		{url: "https://versions.example:8443/stable.txt"},
+		{url: "https://versions.example/stable.txt", proxied: true},
+		{url: "http://localhost:8080/tree.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			proxy, err := Proxy(req)
+			if err != nil {
+				t.Fatalf("Proxy error = %v", err)
+			}
+			if got := proxy != nil; got != tt.proxied {
+				t.Errorf("Proxy(%s) got %v, want proxied %v", tt.url, proxy, tt.proxied)
+			}
+			if proxy != nil && proxy.String() != proxyURL {
+				t.Errorf("Proxy(%s) got %v, want %s", tt.url, proxy, proxyURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// unknownFieldErr prefixes the error of the decoders disallowing unknown fields
+const unknownFieldErr = "json: unknown field "
+
+// UnmarshalExtra decodes data into v, a pointer to struct, and returns the fields of
+// the JSON object that v does not declare, so they can be written back by MarshalExtra.
+// Data without extra fields, the usual case, is decoded once.
+func UnmarshalExtra(data []byte, v interface{}) (map[string]json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(v)
+	if err == nil || !strings.HasPrefix(err.Error(), unknownFieldErr) {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	known := knownFields(reflect.TypeOf(v).Elem())
+	for k := range fields {
+		for _, n := range known {
+			if strings.EqualFold(k, n) {
+				delete(fields, k)
+				break
+			}
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// MarshalExtra encodes v adding the extra fields it does not declare
+func MarshalExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return b, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	for k, e := range extra {
+		if _, ok := fields[k]; !ok {
+			fields[k] = e
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+// knownFields returns the JSON names of the fields declared by the struct type t
+func knownFields(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = f.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,54 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type sample struct {
+	Name  string                     `json:"name"`
+	Repo  string                     `json:"Repo,omitempty"`
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func TestExtraRoundTrip(t *testing.T) {
+	in := `{"name":"aws","repo":"commons","owner":{"team":"cloud"},"tags":["a","b"]}`
+
+	var s sample
+	extra, err := UnmarshalExtra([]byte(in), &s)
+	if err != nil {
+		t.Fatalf("UnmarshalExtra error = %v", err)
+	}
+	if s.Name != "aws" || s.Repo != "commons" {
+		t.Errorf("UnmarshalExtra got %+v", s)
+	}
+	if len(extra) != 2 {
+		t.Errorf("UnmarshalExtra got extra %v, want owner and tags", extra)
+	}
+
+	s.Name = "gcp"
+	out, err := MarshalExtra(s, extra)
+	if err != nil {
+		t.Fatalf("MarshalExtra error = %v", err)
+	}
+
+	want := `{"Repo":"commons","name":"gcp","owner":{"team":"cloud"},"tags":["a","b"]}`
+	if string(out) != want {
+		t.Errorf("MarshalExtra got %s, want %s", out, want)
+	}
+}
+
+func TestUnmarshalExtraWithoutExtra(t *testing.T) {
+	var s sample
+	extra, err := UnmarshalExtra([]byte(`{"name":"aws","Repo":"commons"}`), &s)
+	if err != nil {
+		t.Fatalf("UnmarshalExtra error = %v", err)
+	}
+	if s.Name != "aws" || s.Repo != "commons" || extra != nil {
+		t.Errorf("UnmarshalExtra got %+v and extra %v, want no extra", s, extra)
+	}
+
+	if _, err := UnmarshalExtra([]byte(`{"name":1}`), &s); err == nil {
+		t.Error("UnmarshalExtra got nil, want the type error")
+	}
+}
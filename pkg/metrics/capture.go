@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// RunEvent is the event of a formula run written by rit run --capture-metrics,
+// one JSON object per line appended to the file:
+//
+//	{"username":"","command":"rit aws create","labels":{"ci":"123"},"formula":"aws/create",
+//	 "start":"2020-07-20T10:00:00Z","durationSeconds":1.52,"exitCode":0,"inputs":3}
+//
+// Command and Labels are masked as on the metrics sent to the server, Username is
+// only known by the team edition and Inputs is the number of inputs of the formula.
+// ExitCode is the formula exit code, or -1 when it couldn't be run.
+type RunEvent struct {
+	CmdUse
+	Formula  string    `json:"formula"`
+	Start    time.Time `json:"start"`
+	Duration float64   `json:"durationSeconds"`
+	ExitCode int       `json:"exitCode"`
+	Inputs   int       `json:"inputs"`
+}
+
+// Capture appends the event to the file, it is written regardless of the metrics opt-out
+// as the file stays on the machine
+func Capture(file string, e RunEvent) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCapture(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "metrics.jsonl")
+	start := time.Date(2020, 7, 20, 10, 0, 0, 0, time.UTC)
+	events := []RunEvent{
+		{CmdUse: CmdUse{Cmd: "rit aws create"}, Formula: "aws/create", Start: start, Duration: 1.5, Inputs: 3},
+		{CmdUse: CmdUse{Cmd: "rit aws create", Labels: map[string]string{"ci": "123"}}, Formula: "aws/create", Start: start, ExitCode: 2},
+	}
+	for _, e := range events {
+		if err := Capture(file, e); err != nil {
+			t.Fatalf("Capture error = %v", err)
+		}
+	}
+
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != len(events) {
+		t.Fatalf("Capture wrote %d lines, want %d", len(lines), len(events))
+	}
+	var got RunEvent
+	if err := json.Unmarshal([]byte(lines[1]), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ExitCode != 2 || got.Labels["ci"] != "123" || !got.Start.Equal(start) {
+		t.Errorf("Capture got %+v, want %+v", got, events[1])
+	}
+
+	if err := Capture(filepath.Join(dir, "missing", "metrics.jsonl"), events[0]); err == nil {
+		t.Error("Capture into a missing dir should return an error")
+	}
+}
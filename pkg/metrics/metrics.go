@@ -8,19 +8,23 @@ import (
 	"os"
 	"strings"
 
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
 	"github.com/ZupIT/ritchie-cli/pkg/http/headers"
+	"github.com/ZupIT/ritchie-cli/pkg/redact"
 	"github.com/ZupIT/ritchie-cli/pkg/server"
 	"github.com/ZupIT/ritchie-cli/pkg/session"
 )
 
 const (
 	urlPattern = "%s/usage"
+	labelFlag  = "label"
 )
 
-// CmdUse type that represents a metric use
+// CmdUse type that represents a metric use, Labels are the --label values of a formula run
 type CmdUse struct {
-	Username string `json:"username"`
-	Cmd      string `json:"command"`
+	Username string            `json:"username"`
+	Cmd      string            `json:"command"`
+	Labels   map[string]string `json:"labels,omitempty"`
 }
 
 type Sender struct {
@@ -46,6 +50,7 @@ func (s Sender) SendCommand() {
 	cmdUse := CmdUse{
 		Username: session.Username,
 		Cmd:      cmd(),
+		Labels:   labels(os.Args),
 	}
 
 	b, err := json.Marshal(&cmdUse)
@@ -75,14 +80,31 @@ func (s Sender) SendCommand() {
 	defer resp.Body.Close()
 }
 
+// cmd returns the command line, the values of the sensitive args are masked
 func cmd() string {
-	var args []string
-	for i := 0; i < len(os.Args); i++ {
-		if i == len(os.Args)-1 {
-			args = append(args, os.Args[i])
-			continue
+	return strings.Join(redact.Redactor{}.Args(os.Args), " ")
+}
+
+// labels returns the --label values of the args, the invalid ones are ignored as
+// the run fails on them, and the values of the sensitive label keys are masked
+func labels(args []string) map[string]string {
+	var ll []string
+	for i, a := range args {
+		if a == "--" {
+			break
+		}
+
+		switch {
+		case a == "--"+labelFlag && i+1 < len(args):
+			ll = append(ll, args[i+1])
+		case strings.HasPrefix(a, "--"+labelFlag+"="):
+			ll = append(ll, strings.TrimPrefix(a, "--"+labelFlag+"="))
 		}
-		args = append(args, os.Args[i]+" ")
 	}
-	return strings.Join(args, "")
+
+	parsed, err := formula.ParseLabels(ll)
+	if err != nil {
+		return nil
+	}
+	return redact.Redactor{}.Map(parsed)
 }
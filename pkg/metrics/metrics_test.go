@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ZupIT/ritchie-cli/pkg/redact"
+)
+
+func TestLabels(t *testing.T) {
+	args := []string{"rit", "aws", "deploy", "--label", "ci=123", "--label=api_token=t0k3n", "--", "--label", "x=y"}
+	want := map[string]string{"ci": "123", "api_token": redact.Mask}
+	if got := labels(args); !reflect.DeepEqual(got, want) {
+		t.Errorf("labels got %v, want %v", got, want)
+	}
+
+	if got := labels([]string{"rit", "aws", "deploy", "--label", "ci"}); got != nil {
+		t.Errorf("labels of an invalid label got %v, want nil", got)
+	}
+}
@@ -20,9 +20,14 @@ func (SurveyBool) Bool(name string, items []string) (bool, error) {
 		Message: name,
 		Options: items,
 	}
-	if err := survey.AskOne(prompt, &choice); err != nil {
+	if err := askOne(prompt, &choice); err != nil {
 		return false, err
 	}
 
-	return boolOpts[choice], nil
+	return BoolItem(choice), nil
+}
+
+// BoolItem returns the bool of an item of the bool prompt, yes and true are true
+func BoolItem(item string) bool {
+	return boolOpts[item]
 }
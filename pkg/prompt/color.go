@@ -3,42 +3,94 @@ package prompt
 import (
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/gookit/color"
+	"github.com/mattn/go-isatty"
 )
 
+const (
+	// ColorAuto renders colors only when stdout is a terminal and NO_COLOR isn't set
+	ColorAuto = "auto"
+	// ColorAlways renders colors even when stdout is a pipe, e.g. for less -R
+	ColorAlways = "always"
+	// ColorNever never renders colors
+	ColorNever = "never"
+	// NoColorEnv disables the colors in auto mode, see https://no-color.org
+	NoColorEnv = "NO_COLOR"
+)
+
+var (
+	// ErrInvalidColorMode error for a color mode other than auto, always or never
+	ErrInvalidColorMode = fmt.Errorf("invalid color mode, use %s, %s or %s", ColorAuto, ColorAlways, ColorNever)
+
+	forceColor bool
+	isTerminal = func() bool {
+		return isatty.IsTerminal(os.Stdout.Fd())
+	}
+)
+
+// SetColor sets whether the prompt colors are rendered, an empty mode is auto.
+// The always mode also keeps the colors in plain mode.
+func SetColor(mode string) error {
+	switch mode {
+	case ColorAlways:
+		forceColor = true
+		color.Enable = color.ForceOpenColor()
+	case ColorNever:
+		forceColor = false
+		color.Enable = false
+	case ColorAuto, "":
+		forceColor = false
+		_, noColor := os.LookupEnv(NoColorEnv)
+		color.Enable = !plain && !noColor && isTerminal()
+	default:
+		return ErrInvalidColorMode
+	}
+	return nil
+}
+
 // NewError returns new error with red message
 func NewError(text string) error {
 	return errors.New(Red(text))
 }
 
-// Red returns a red string
+// Red returns the text in the error color of the theme, red by default, see SetTheme
 func Red(text string) string {
-	return color.FgRed.Render(text)
+	return render(RoleError, text)
 }
 
-// Error is a Println with red message
+// Error is a Println with red message, it prints at every level, even in quiet mode
 func Error(text string) {
-	fmt.Println(Red(text))
+	fmt.Fprintln(Stdout, Red(text))
 }
 
 func Green(text string) string {
-	return color.Success.Render(text)
+	return render(RoleSuccess, text)
 }
 func Success(text string) {
-	fmt.Println(Green(text))
+	if level > LevelInfo {
+		return
+	}
+	fmt.Fprintln(Stdout, Green(text))
 }
 
 func Bold(text string) string {
-	return color.Bold.Render(text)
+	return render(RoleInfo, text)
 }
 func Info(text string) {
-	fmt.Println(Bold(text))
+	if level > LevelInfo {
+		return
+	}
+	fmt.Fprintln(Stdout, Bold(text))
 }
 
 func Yellow(text string) string {
-	return color.Warn.Render(text)
+	return render(RoleWarning, text)
 }
 func Warning(text string) {
-	color.Warn.Println(text)
+	if level > LevelWarn {
+		return
+	}
+	fmt.Fprintln(Stdout, Yellow(text))
 }
@@ -0,0 +1,80 @@
+package prompt
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gookit/color"
+)
+
+func TestSetColor(t *testing.T) {
+	enable, terminal := color.Enable, isTerminal
+	defer func() {
+		color.Enable, isTerminal = enable, terminal
+		forceColor = false
+	}()
+
+	tests := []struct {
+		name     string
+		mode     string
+		terminal bool
+		noColor  bool
+		plain    bool
+		want     bool
+		wantErr  bool
+	}{
+		{name: "auto on terminal", mode: ColorAuto, terminal: true, want: true},
+		{name: "auto on pipe", mode: ColorAuto, terminal: false, want: false},
+		{name: "auto with NO_COLOR", mode: ColorAuto, terminal: true, noColor: true, want: false},
+		{name: "auto in plain mode", mode: ColorAuto, terminal: true, plain: true, want: false},
+		{name: "empty mode is auto", mode: "", terminal: true, want: true},
+		{name: "always on pipe", mode: ColorAlways, terminal: false, want: true},
+		{name: "always with NO_COLOR", mode: ColorAlways, noColor: true, want: true},
+		{name: "always in plain mode", mode: ColorAlways, plain: true, want: true},
+		{name: "never on terminal", mode: ColorNever, terminal: true, want: false},
+		{name: "invalid mode", mode: "sometimes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terminal := tt.terminal
+			isTerminal = func() bool { return terminal }
+			if tt.noColor {
+				_ = os.Setenv(NoColorEnv, "1")
+				defer os.Unsetenv(NoColorEnv)
+			}
+			plain = tt.plain
+			defer func() { plain = false }()
+
+			err := SetColor(tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetColor(%s) got %v, wantErr %v", tt.mode, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := Red("text") != "text"
+			if got != tt.want {
+				t.Errorf("SetColor(%s) colored %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainOutputForcedColor(t *testing.T) {
+	SetPlain(true)
+	_ = SetColor(ColorAlways)
+	defer func() {
+		_ = SetColor(ColorNever)
+		SetPlain(false)
+	}()
+
+	got := captureStdout(t, func() {
+		Error("✔ failed")
+	})
+
+	if got != "\x1b[31m[ok] failed\x1b[0m\n" {
+		t.Errorf("plain output with forced color got %q", got)
+	}
+}
@@ -0,0 +1,66 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	confirmMsg       = "This will remove %s. Continue?"
+	confirmStrictMsg = "This will remove %s. Type %q to confirm:"
+)
+
+var (
+	// ErrConfirmationRequired is returned when a confirmation is needed but the run is non interactive
+	ErrConfirmationRequired = errors.New("this operation needs confirmation, use --yes to confirm it in non interactive runs")
+	// ErrConfirmationMismatch is returned when the typed name does not match on strict confirmations
+	ErrConfirmationMismatch = errors.New("the typed name does not match, operation cancelled")
+)
+
+// Confirmer renders the confirmation of destructive operations
+// in a standard way, honoring the --yes and --non-interactive flags
+type Confirmer struct {
+	InputBool      InputBool
+	InputText      InputText
+	Yes            bool
+	NonInteractive bool
+}
+
+// NewConfirmer creates a Confirmer, inText is only required for strict confirmations
+func NewConfirmer(inBool InputBool, inText InputText, yes, nonInteractive bool) Confirmer {
+	return Confirmer{InputBool: inBool, InputText: inText, Yes: yes, NonInteractive: nonInteractive}
+}
+
+// Confirm asks "This will remove <what>. Continue? [y/N]", it returns true without
+// asking when --yes was informed and fails when the run is non interactive
+func (c Confirmer) Confirm(what string) (bool, error) {
+	if c.Yes {
+		return true, nil
+	}
+	if c.NonInteractive {
+		return false, ErrConfirmationRequired
+	}
+
+	return c.InputBool.Bool(fmt.Sprintf(confirmMsg, what), []string{"no", "yes"})
+}
+
+// ConfirmStrict asks the user to type the name of the resource to confirm
+// high risk operations, it follows the same --yes and --non-interactive rules of Confirm
+func (c Confirmer) ConfirmStrict(what, name string) (bool, error) {
+	if c.Yes {
+		return true, nil
+	}
+	if c.NonInteractive {
+		return false, ErrConfirmationRequired
+	}
+
+	typed, err := c.InputText.Text(fmt.Sprintf(confirmStrictMsg, what, name), true)
+	if err != nil {
+		return false, err
+	}
+	if typed != name {
+		return false, ErrConfirmationMismatch
+	}
+
+	return true, nil
+}
@@ -0,0 +1,31 @@
+package prompt
+
+import (
+	"github.com/AlecAivazis/survey/v2"
+)
+
+type SurveyEditor struct{}
+
+func NewSurveyEditor() SurveyEditor {
+	return SurveyEditor{}
+}
+
+// Editor opens $VISUAL or $EDITOR, vim or notepad when unset, on a temp file with the
+// default value as its initial content and returns the content saved. The fileName is
+// the pattern of the temp file, e.g. *.yaml, so that the editor highlights its syntax.
+func (SurveyEditor) Editor(name, defaultValue, fileName string, required bool) (string, error) {
+	value := ""
+	q := &survey.Question{
+		Prompt: &survey.Editor{
+			Message:       name,
+			Default:       defaultValue,
+			HideDefault:   true,
+			AppendDefault: true,
+			FileName:      fileName,
+		},
+	}
+	if required {
+		q.Validate = survey.Required
+	}
+	return value, ask([]*survey.Question{q}, &value)
+}
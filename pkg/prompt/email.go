@@ -26,5 +26,5 @@ func (SurveyEmail) Email(name string) (string, error) {
 		},
 	}
 
-	return value, survey.Ask(validationQs, &value)
+	return value, ask(validationQs, &value)
 }
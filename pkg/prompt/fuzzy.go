@@ -0,0 +1,31 @@
+package prompt
+
+import "strings"
+
+// fuzzyFilter is the filter of the select prompts: the typed chars must appear in the option
+// in order but not next to each other, case-insensitively, e.g. prdeks matches prod-eks-sa.
+// Spaces split the filter into terms matched on their own, in any order.
+func fuzzyFilter(filter, option string, _ int) bool {
+	option = strings.ToLower(option)
+	for _, term := range strings.Fields(strings.ToLower(filter)) {
+		if !subsequence(term, option) {
+			return false
+		}
+	}
+	return true
+}
+
+// subsequence tells whether the chars of term appear in s in order
+func subsequence(term, s string) bool {
+	rs := []rune(term)
+	i := 0
+	for _, r := range s {
+		if i == len(rs) {
+			break
+		}
+		if r == rs[i] {
+			i++
+		}
+	}
+	return i == len(rs)
+}
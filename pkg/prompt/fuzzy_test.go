@@ -0,0 +1,28 @@
+package prompt
+
+import "testing"
+
+func TestFuzzyFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		option string
+		want   bool
+	}{
+		{filter: "", option: "prod-eks-sa", want: true},
+		{filter: "eks", option: "prod-eks-sa", want: true},
+		{filter: "prdeks", option: "prod-eks-sa", want: true},
+		{filter: "PRD", option: "prod-eks-sa", want: true},
+		{filter: "sa prod", option: "prod-eks-sa", want: true},
+		{filter: "ekp", option: "prod-eks-sa", want: false},
+		{filter: "sa dev", option: "prod-eks-sa", want: false},
+		{filter: "ção", option: "configuração", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			if got := fuzzyFilter(tt.filter, tt.option, 0); got != tt.want {
+				t.Errorf("fuzzyFilter(%q, %q) got %v, want %v", tt.filter, tt.option, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package prompt
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/terminal"
+)
+
+// ttyPath is the terminal the idle prompts read from, it is opened on each prompt
+// so that its read deadline can be set, which os.Stdin doesn't support
+const ttyPath = "/dev/tty"
+
+const msgIdleUnsupported = "The prompts of this terminal can't time out, they will wait for an answer"
+
+// ErrInputIdle is returned by a prompt nobody answered before its idle timeout, see SetIdle
+var ErrInputIdle = errors.New("no answer before the input timeout")
+
+var idle struct {
+	timeout time.Duration
+	accept  bool
+	warned  bool
+}
+
+// SetIdle makes the next prompts give up after the timeout without a key press.
+// With accept the prompt is submitted as if enter was pressed, accepting the text
+// default or the selected item, otherwise the prompt fails with ErrInputIdle.
+// A zero timeout restores the prompts waiting for an answer.
+// Only the terminals supporting read deadlines time out, as the unix ones.
+func SetIdle(timeout time.Duration, accept bool) {
+	idle.timeout = timeout
+	idle.accept = accept
+}
+
+// idleReader reads the terminal, answering enter or failing when no key is pressed in time
+type idleReader struct {
+	*os.File
+}
+
+// Fd returns the stdin fd to set the terminal mode, the Fd of the tty file
+// would turn it blocking, ignoring the read deadlines
+func (idleReader) Fd() uintptr {
+	return os.Stdin.Fd()
+}
+
+func (r idleReader) Read(p []byte) (int, error) {
+	if err := r.SetReadDeadline(time.Now().Add(idle.timeout)); err != nil {
+		return 0, err
+	}
+
+	n, err := r.File.Read(p)
+	if !os.IsTimeout(err) {
+		return n, err
+	}
+	if !idle.accept || len(p) == 0 {
+		return 0, ErrInputIdle
+	}
+	p[0] = terminal.KeyEnter
+	return 1, nil
+}
+
+// ask asks the survey questions reading the answers with the idle timeout set by SetIdle,
+// the options of the select prompts are filtered by fuzzyFilter and the icons are the ones of the theme
+func ask(qs []*survey.Question, response interface{}, opts ...survey.AskOpt) error {
+	opts = append([]survey.AskOpt{survey.WithFilter(fuzzyFilter), survey.WithIcons(withIcons)}, opts...)
+	if idle.timeout <= 0 {
+		return survey.Ask(qs, response, opts...)
+	}
+
+	tty, err := os.Open(ttyPath)
+	if err == nil {
+		err = tty.SetReadDeadline(time.Time{})
+	}
+	if err != nil {
+		if tty != nil {
+			_ = tty.Close()
+		}
+		if !idle.warned {
+			Warning(msgIdleUnsupported)
+			idle.warned = true
+		}
+		return survey.Ask(qs, response, opts...)
+	}
+	defer tty.Close()
+
+	opts = append(opts, survey.WithStdio(idleReader{tty}, os.Stdout, os.Stderr))
+	return survey.Ask(qs, response, opts...)
+}
+
+// askOne asks a single survey prompt, see ask
+func askOne(p survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
+	return ask([]*survey.Question{{Prompt: p}}, response, opts...)
+}
@@ -0,0 +1,39 @@
+package prompt
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2/terminal"
+)
+
+func TestIdleReader(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if err := r.SetReadDeadline(time.Time{}); err != nil {
+		t.Skipf("the pipes don't support read deadlines here: %v", err)
+	}
+	defer SetIdle(0, false)
+	reader := idleReader{r}
+	buf := make([]byte, 8)
+
+	SetIdle(10*time.Millisecond, true)
+	if n, err := reader.Read(buf); err != nil || n != 1 || buf[0] != terminal.KeyEnter {
+		t.Errorf("idle Read with accept got %q, %v, want enter", buf[:n], err)
+	}
+
+	_, _ = w.Write([]byte("y"))
+	if n, err := reader.Read(buf); err != nil || string(buf[:n]) != "y" {
+		t.Errorf("Read of a key press got %q, %v, want y", buf[:n], err)
+	}
+
+	SetIdle(10*time.Millisecond, false)
+	if _, err := reader.Read(buf); err != ErrInputIdle {
+		t.Errorf("idle Read without accept got %v, want %v", err, ErrInputIdle)
+	}
+}
@@ -26,7 +26,7 @@ func (SurveyInt) Int(name string) (int64, error) {
 			Validate: validateSurveyIntIn,
 		},
 	}
-	if err := survey.Ask(validationQs, &value); err != nil {
+	if err := ask(validationQs, &value); err != nil {
 		return 0, err
 	}
 
@@ -0,0 +1,56 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level is the severity of a message, only the messages of the current level and above are printed
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// Levels are the names of the levels, from the least severe
+var Levels = []string{"debug", "info", "warn", "error"}
+
+// ErrInvalidLevel error for a level other than debug, info, warn or error
+var ErrInvalidLevel = fmt.Errorf("invalid log level, use %s", strings.Join(Levels, ", "))
+
+// level is info by default, quiet is the error level
+var level = LevelInfo
+
+// ParseLevel parses the name of a level, an empty name is info
+func ParseLevel(name string) (Level, error) {
+	if name == "" {
+		return LevelInfo, nil
+	}
+	for i, l := range Levels {
+		if strings.EqualFold(name, l) {
+			return Level(i), nil
+		}
+	}
+	return LevelInfo, ErrInvalidLevel
+}
+
+// SetLevel sets the least severe level printed, the error messages are always printed
+func SetLevel(l Level) {
+	level = l
+}
+
+// CurrentLevel returns the least severe level printed
+func CurrentLevel() Level {
+	return level
+}
+
+// Debug is a Println of a gray message, only printed at the debug level
+func Debug(text string) {
+	if level > LevelDebug {
+		return
+	}
+	fmt.Fprintln(Stdout, render(RoleDebug, text))
+}
@@ -17,7 +17,7 @@ func (SurveyList) List(name string, items []string) (string, error) {
 		Message: name,
 		Options: items,
 	}
-	if err := survey.AskOne(prompt, &choice); err != nil {
+	if err := askOne(prompt, &choice); err != nil {
 		return "", err
 	}
 
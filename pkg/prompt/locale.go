@@ -0,0 +1,43 @@
+package prompt
+
+import (
+	"os"
+	"strings"
+)
+
+// localeEnvs are the env vars of the locale of the user, by precedence
+var localeEnvs = []string{"LC_ALL", "LC_MESSAGES", "LANG"}
+
+var locale string
+
+// DetectLocale returns the locale of the prompts, e.g. pt-BR: the configured one or
+// the one of the LC_ALL, LC_MESSAGES or LANG env vars, without their encoding, e.g.
+// pt_BR.UTF-8 is pt-BR. The C and POSIX locales are none.
+func DetectLocale(configured string) string {
+	if configured != "" {
+		return configured
+	}
+
+	for _, e := range localeEnvs {
+		v := os.Getenv(e)
+		if v == "" {
+			continue
+		}
+		v = strings.SplitN(strings.SplitN(v, ".", 2)[0], "@", 2)[0]
+		if v == "C" || v == "POSIX" {
+			return ""
+		}
+		return strings.Replace(v, "_", "-", -1)
+	}
+	return ""
+}
+
+// SetLocale sets the locale of the formula input prompts, see formula.Input.Localize
+func SetLocale(l string) {
+	locale = l
+}
+
+// Locale returns the locale of the formula input prompts, empty when there is none
+func Locale() string {
+	return locale
+}
@@ -0,0 +1,40 @@
+package prompt
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDetectLocale(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		env        map[string]string
+		want       string
+	}{
+		{name: "configured", configured: "es", env: map[string]string{"LANG": "pt_BR.UTF-8"}, want: "es"},
+		{name: "lang", env: map[string]string{"LANG": "pt_BR.UTF-8"}, want: "pt-BR"},
+		{name: "lc_all first", env: map[string]string{"LC_ALL": "es_AR.UTF-8", "LANG": "pt_BR.UTF-8"}, want: "es-AR"},
+		{name: "modifier", env: map[string]string{"LANG": "de_DE@euro"}, want: "de-DE"},
+		{name: "posix", env: map[string]string{"LC_ALL": "C", "LANG": "pt_BR.UTF-8"}, want: ""},
+		{name: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, e := range localeEnvs {
+				old, ok := os.LookupEnv(e)
+				_ = os.Setenv(e, tt.env[e])
+				if ok {
+					defer os.Setenv(e, old)
+				} else {
+					defer os.Unsetenv(e)
+				}
+			}
+
+			if got := DetectLocale(tt.configured); got != tt.want {
+				t.Errorf("DetectLocale(%q) got %q, want %q", tt.configured, got, tt.want)
+			}
+		})
+	}
+}
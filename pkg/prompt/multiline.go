@@ -33,6 +33,6 @@ func (SurveyMultiline) MultiLineText(name string, required bool) (string, error)
 			},
 		}
 	}
-	return value, survey.Ask(validationQs, &value)
+	return value, ask(validationQs, &value)
 }
 
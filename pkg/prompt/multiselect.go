@@ -0,0 +1,23 @@
+package prompt
+
+import (
+	"github.com/AlecAivazis/survey/v2"
+)
+
+type SurveyMultiselect struct{}
+
+func NewSurveyMultiselect() SurveyMultiselect {
+	return SurveyMultiselect{}
+}
+
+// Multiselect shows a checkbox prompt of the items with the defaults checked,
+// when required at least one item must be checked
+func (SurveyMultiselect) Multiselect(name string, items, defaults []string, required bool) ([]string, error) {
+	var choices []string
+	q := &survey.Question{Prompt: &survey.MultiSelect{Message: name, Options: items, Default: defaults}}
+	if required {
+		q.Validate = survey.Required
+	}
+
+	return choices, ask([]*survey.Question{q}, &choices)
+}
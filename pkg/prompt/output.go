@@ -0,0 +1,106 @@
+package prompt
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/color"
+	"github.com/mattn/go-isatty"
+)
+
+// PlainEnv enables the plain output mode when set to a true value, e.g. RIT_PLAIN=1
+const PlainEnv = "RIT_PLAIN"
+
+var (
+	// Stdout is the capability-aware writer used by every prompt emitter.
+	// In plain mode it drops escape sequences and carriage returns and swaps
+	// unicode glyphs for ASCII.
+	Stdout io.Writer = writer{}
+
+	plain  bool
+	escape = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+	glyphs = strings.NewReplacer(
+		"✔", "[ok]",
+		"✘", "[x]",
+		"∙", "*",
+		"•", "*",
+		"⚠", "!",
+		"…", "...",
+		"\r", "",
+	)
+)
+
+// DetectPlain tells whether the output must be plain: when it is configured,
+// when RIT_PLAIN is set, on dumb terminals or when stdout isn't a terminal.
+func DetectPlain(configured bool) bool {
+	if configured {
+		return true
+	}
+
+	if v, err := strconv.ParseBool(os.Getenv(PlainEnv)); err == nil {
+		return v
+	}
+
+	return os.Getenv("TERM") == "dumb" || !isatty.IsTerminal(os.Stdout.Fd())
+}
+
+// SetPlain turns the plain output mode on or off, colors are disabled in plain
+// mode unless they are forced, see SetColor
+func SetPlain(p bool) {
+	plain = p
+	color.Enable = !p || forceColor
+}
+
+// IsPlain tells whether the plain output mode is on
+func IsPlain() bool {
+	return plain
+}
+
+// SetQuiet turns the quiet mode on or off, in quiet mode the informational messages,
+// warnings and spinners print nothing, only the errors and the output the command
+// was asked for, e.g. tables and JSON, are printed. It is the error level, see SetLevel.
+func SetQuiet(q bool) {
+	level = LevelInfo
+	if q {
+		level = LevelError
+	}
+}
+
+// IsQuiet tells whether the quiet mode is on
+func IsQuiet() bool {
+	return level >= LevelError
+}
+
+// Print is a Println of an informational message without color, silenced above the info level
+func Print(text string) {
+	if level <= LevelInfo {
+		fmt.Fprintln(Stdout, text)
+	}
+}
+
+// Plain removes from text everything a dumb terminal can't render,
+// escape sequences are kept when the colors are forced
+func Plain(text string) string {
+	if forceColor {
+		return glyphs.Replace(text)
+	}
+	return glyphs.Replace(escape.ReplaceAllString(text, ""))
+}
+
+// writer resolves os.Stdout on each write so redirections done after init are honored
+type writer struct{}
+
+func (writer) Write(p []byte) (int, error) {
+	if !plain {
+		return os.Stdout.Write(p)
+	}
+
+	if _, err := io.WriteString(os.Stdout, Plain(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
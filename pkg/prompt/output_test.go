@@ -0,0 +1,161 @@
+package prompt
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	f()
+	os.Stdout = stdout
+	_ = w.Close()
+
+	out, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestPlainOutput(t *testing.T) {
+	SetPlain(true)
+	defer SetPlain(false)
+
+	got := captureStdout(t, func() {
+		Info("Formula path is /tmp")
+		Success("✔ Build completed!")
+		Warning("⚠ deprecated formula\r")
+		Error("∙ something went wrong")
+		StartSpinner("Building formula...").Success(Green("✔ Build completed!"))
+		StartSpinner("Creating formula...").Error(NewError("permission denied"))
+		_, _ = Stdout.Write([]byte("\x1b[2K\x1b[1A\x1b[31mred\x1b[0m\n"))
+	})
+
+	golden := "Formula path is /tmp\n" +
+		"[ok] Build completed!\n" +
+		"! deprecated formula\n" +
+		"* something went wrong\n" +
+		"Building formula... done\n" +
+		"[ok] Build completed!\n" +
+		"Creating formula... failed\n" +
+		"permission denied\n" +
+		"red\n"
+
+	if got != golden {
+		t.Errorf("plain output got %q, want %q", got, golden)
+	}
+
+	for _, r := range got {
+		if r < 0x20 && r != '\n' || r == 0x7f {
+			t.Errorf("plain output has control character %q", r)
+		}
+		if r > 0x7e {
+			t.Errorf("plain output has non ASCII glyph %q", r)
+		}
+	}
+}
+
+func TestDetectPlain(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured bool
+		plainEnv   string
+		want       bool
+	}{
+		{name: "configured", configured: true, plainEnv: "0", want: true},
+		{name: "env enabled", plainEnv: "1", want: true},
+		{name: "env disabled", plainEnv: "false", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = os.Setenv(PlainEnv, tt.plainEnv)
+			defer os.Unsetenv(PlainEnv)
+
+			if got := DetectPlain(tt.configured); got != tt.want {
+				t.Errorf("DetectPlain(%s) got %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	term := os.Getenv("TERM")
+	_ = os.Setenv("TERM", "dumb")
+	defer os.Setenv("TERM", term)
+	if !DetectPlain(false) {
+		t.Errorf("DetectPlain(TERM=dumb) got false, want true")
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	defer SetQuiet(false)
+	SetQuiet(true)
+
+	out := captureStdout(t, func() {
+		Info("info")
+		Success("success")
+		Warning("warning")
+		Print("print")
+		StartSpinner("working").Success("done")
+	})
+	if out != "" {
+		t.Errorf("quiet mode printed %q, want nothing", out)
+	}
+
+	out = captureStdout(t, func() {
+		Error("failed")
+		StartSpinner("working").Error(errors.New("spinner failed"))
+	})
+	if !strings.Contains(out, "failed") || !strings.Contains(out, "spinner failed") {
+		t.Errorf("quiet mode printed %q, want the errors", out)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	defer SetLevel(LevelInfo)
+
+	tests := []struct {
+		level string
+		want  []string
+	}{
+		{level: "debug", want: []string{"debug", "info", "warning", "failed"}},
+		{level: "", want: []string{"info", "warning", "failed"}},
+		{level: "WARN", want: []string{"warning", "failed"}},
+		{level: "error", want: []string{"failed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			l, err := ParseLevel(tt.level)
+			if err != nil {
+				t.Fatalf("ParseLevel(%q) got %v", tt.level, err)
+			}
+			SetLevel(l)
+
+			out := captureStdout(t, func() {
+				Debug("debug")
+				Info("info")
+				Warning("warning")
+				Error("failed")
+			})
+			if got := strings.Fields(Plain(out)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("level %q printed %q, want %q", tt.level, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseLevel("trace"); err != ErrInvalidLevel {
+		t.Errorf("ParseLevel(trace) got %v, want ErrInvalidLevel", err)
+	}
+}
@@ -18,5 +18,5 @@ func (SurveyPassword) Password(label string) (string, error) {
 		Message: label,
 	}
 
-	return password, survey.AskOne(prompt, &password)
+	return password, askOne(prompt, &password)
 }
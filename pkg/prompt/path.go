@@ -0,0 +1,202 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/terminal"
+)
+
+const (
+	pathThisDir  = ". (this directory)"
+	pathParent   = ".."
+	pathTyped    = "Type the path..."
+	pathPageSize = 15
+)
+
+var (
+	ErrPathNotFound = errors.New("no such file or directory")
+
+	// globMeta escapes the glob chars of the typed paths as classes of a single char
+	globMeta = strings.NewReplacer("*", "[*]", "?", "[?]", "[", "[[]")
+)
+
+type SurveyPath struct{}
+
+func NewSurveyPath() SurveyPath {
+	return SurveyPath{}
+}
+
+// Path shows a file picker starting on dir: the subdirectories are browsed and the files
+// are listed when their names match the glob pattern, all of them when it is empty. Typing
+// filters the listed entries and a path may be typed too. The directories are picked only
+// without a pattern.
+func (SurveyPath) Path(name, dir, pattern string) (string, error) {
+	for {
+		options, err := pathOptions(dir, pattern)
+		if err != nil {
+			return "", err
+		}
+
+		choice := ""
+		p := &survey.Select{Message: fmt.Sprintf("%s [%s]", name, dir), Options: options, PageSize: pathPageSize}
+		if err := askOne(p, &choice); err != nil {
+			return "", err
+		}
+
+		switch {
+		case choice == pathTyped:
+			return typedPath(name, dir)
+		case choice == pathThisDir:
+			return dir, nil
+		case choice == pathParent:
+			dir = filepath.Dir(dir)
+		case strings.HasSuffix(choice, "/"):
+			dir = filepath.Join(dir, strings.TrimSuffix(choice, "/"))
+		default:
+			return filepath.Join(dir, choice), nil
+		}
+	}
+}
+
+// pathOptions lists the entries of dir the picker shows, the directories, suffixed by /,
+// before the files matching the pattern
+func pathOptions(dir, pattern string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs, files []string
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() {
+			dirs = append(dirs, name+"/")
+			continue
+		}
+		if ok, _ := filepath.Match(pattern, name); ok || pattern == "" {
+			files = append(files, name)
+		}
+	}
+	sort.Strings(dirs)
+	sort.Strings(files)
+
+	options := []string{pathTyped}
+	if pattern == "" {
+		options = append(options, pathThisDir)
+	}
+	if filepath.Dir(dir) != dir {
+		options = append(options, pathParent)
+	}
+	return append(append(options, dirs...), files...), nil
+}
+
+// typedPath asks for a path, relative to dir or absolute, until it exists. Tab completes it.
+func typedPath(name, dir string) (string, error) {
+	value := ""
+	q := &survey.Question{
+		Prompt: &pathInput{Input: survey.Input{Message: name}, dir: dir},
+		Validate: func(ans interface{}) error {
+			p, _ := ans.(string)
+			if !filepath.IsAbs(p) {
+				p = filepath.Join(dir, p)
+			}
+			if _, err := os.Stat(p); err != nil {
+				return ErrPathNotFound
+			}
+			return nil
+		},
+	}
+	if err := ask([]*survey.Question{q}, &value); err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(value) {
+		value = filepath.Join(dir, value)
+	}
+	return value, nil
+}
+
+// pathInput is a survey.Input completing the typed path with tab, see completePath
+type pathInput struct {
+	survey.Input
+	dir string
+}
+
+func (p *pathInput) Prompt(config *survey.PromptConfig) (interface{}, error) {
+	rr := p.NewRuneReader()
+	_ = rr.SetTermMode()
+	defer rr.RestoreTermMode()
+
+	var line []rune
+	for {
+		data := survey.InputTemplateData{Input: p.Input, Config: config}
+		if err := p.Render(survey.InputQuestionTemplate, data); err != nil {
+			return "", err
+		}
+		fmt.Fprint(p.Stdio().Out, string(line))
+		p.AppendRenderedText(string(line))
+
+		r, _, err := rr.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case r == terminal.KeyInterrupt:
+			return "", terminal.InterruptErr
+		case r == terminal.KeyEnter || r == '\n' || r == terminal.KeyEndTransmission:
+			return string(line), nil
+		case r == terminal.KeyBackspace || r == terminal.KeyDelete:
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+			}
+		case r == '\t':
+			line = []rune(completePath(p.dir, string(line)))
+		case unicode.IsPrint(r):
+			line = append(line, r)
+		}
+	}
+}
+
+// completePath completes the typed path, relative to dir or absolute, with the entries it
+// starts: up to their longest common prefix, and a single directory with a trailing /.
+// The path is kept as it is when no entry matches.
+func completePath(dir, typed string) string {
+	parent, prefix := filepath.Split(typed)
+	search := parent
+	if !filepath.IsAbs(search) {
+		search = filepath.Join(dir, parent)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(search, globMeta.Replace(prefix)) + "*")
+	if len(matches) == 0 {
+		return typed
+	}
+
+	common := filepath.Base(matches[0])
+	for _, m := range matches[1:] {
+		common = commonPrefix(common, filepath.Base(m))
+	}
+	if len(matches) == 1 {
+		if info, err := os.Stat(matches[0]); err == nil && info.IsDir() {
+			common += string(filepath.Separator)
+		}
+	}
+	return parent + common
+}
+
+func commonPrefix(a, b string) string {
+	ra, rb := []rune(a), []rune(b)
+	i := 0
+	for i < len(ra) && i < len(rb) && ra[i] == rb[i] {
+		i++
+	}
+	return string(ra[:i])
+}
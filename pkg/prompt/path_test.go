@@ -0,0 +1,86 @@
+package prompt
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPathOptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-path")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, d := range []string{"modules", "envs"} {
+		_ = os.Mkdir(filepath.Join(dir, d), 0755)
+	}
+	for _, f := range []string{"main.tf", "vars.tf", "README.md"} {
+		_ = ioutil.WriteFile(filepath.Join(dir, f), nil, 0644)
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string
+	}{
+		{
+			name: "all the entries",
+			want: []string{pathTyped, pathThisDir, pathParent, "envs/", "modules/", "README.md", "main.tf", "vars.tf"},
+		},
+		{
+			name:    "files matching the pattern",
+			pattern: "*.tf",
+			want:    []string{pathTyped, pathParent, "envs/", "modules/", "main.tf", "vars.tf"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pathOptions(dir, tt.pattern)
+			if err != nil {
+				t.Fatalf("pathOptions() got %v, want nil", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pathOptions() got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompletePath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-path")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	_ = os.MkdirAll(filepath.Join(dir, "modules", "network"), 0755)
+	for _, f := range []string{"main.tf", "main_test.tf", "vars.tf", filepath.Join("modules", "network", "vpc.tf")} {
+		_ = ioutil.WriteFile(filepath.Join(dir, f), nil, 0644)
+	}
+	sep := string(filepath.Separator)
+
+	tests := []struct {
+		name  string
+		typed string
+		want  string
+	}{
+		{name: "single file", typed: "va", want: "vars.tf"},
+		{name: "common prefix", typed: "ma", want: "main"},
+		{name: "single dir", typed: "mod", want: "modules" + sep},
+		{name: "nested path", typed: filepath.Join("modules", "network", "v"), want: filepath.Join("modules", "network", "vpc.tf")},
+		{name: "absolute path", typed: filepath.Join(dir, "va"), want: filepath.Join(dir, "vars.tf")},
+		{name: "no match", typed: "out", want: "out"},
+		{name: "glob chars", typed: "*", want: "*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := completePath(dir, tt.typed); got != tt.want {
+				t.Errorf("completePath() got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
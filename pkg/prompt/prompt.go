@@ -24,6 +24,14 @@ type InputList interface {
 	List(name string, items []string) (string, error)
 }
 
+type InputMultiselect interface {
+	Multiselect(name string, items, defaults []string, required bool) ([]string, error)
+}
+
+type InputPath interface {
+	Path(name, dir, pattern string) (string, error)
+}
+
 type InputInt interface {
 	Int(name string) (int64, error)
 }
@@ -35,3 +43,7 @@ type InputEmail interface {
 type InputURL interface {
 	URL(name, defaultValue string) (string, error)
 }
+
+type InputEditor interface {
+	Editor(name, defaultValue, fileName string, required bool) (string, error)
+}
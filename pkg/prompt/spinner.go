@@ -0,0 +1,47 @@
+package prompt
+
+import (
+	"fmt"
+
+	"github.com/kaduartur/go-cli-spinner/pkg/spinner"
+)
+
+// Spinner gives feedback to the user while a long task runs
+type Spinner interface {
+	Success(msg string)
+	Error(err error)
+}
+
+// StartSpinner starts an animated spinner, in plain mode it prints a single
+// "title done" or "title failed" line instead of animating.
+// Above the info level, e.g. in quiet mode, it only prints the error.
+func StartSpinner(title string) Spinner {
+	if level > LevelInfo {
+		return quietSpinner{}
+	}
+	if plain {
+		fmt.Fprint(Stdout, title+" ")
+		return plainSpinner{}
+	}
+	return spinner.StartNew(title)
+}
+
+type plainSpinner struct{}
+
+func (plainSpinner) Success(msg string) {
+	fmt.Fprintln(Stdout, "done")
+	fmt.Fprintln(Stdout, msg)
+}
+
+func (plainSpinner) Error(err error) {
+	fmt.Fprintln(Stdout, "failed")
+	fmt.Fprintln(Stdout, err)
+}
+
+type quietSpinner struct{}
+
+func (quietSpinner) Success(string) {}
+
+func (quietSpinner) Error(err error) {
+	Error(err.Error())
+}
@@ -30,6 +30,6 @@ func (SurveyText) Text(name string, required bool, helper ...string) (string, er
 		validationQs[0].Prompt = &survey.Input{Message: name}
 	}
 
-	return value, survey.Ask(validationQs, &value)
+	return value, ask(validationQs, &value)
 }
 
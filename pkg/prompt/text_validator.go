@@ -25,5 +25,5 @@ func (SurveyTextValidator) Text(name string, validate func(interface{}) error, h
 		validationQs[0].Prompt = &survey.Input{Message: name}
 	}
 
-	return value, survey.Ask(validationQs, &value)
+	return value, ask(validationQs, &value)
 }
@@ -0,0 +1,229 @@
+package prompt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/core"
+	"github.com/gookit/color"
+)
+
+const (
+	// ThemeDefault are the colors of the dark terminals
+	ThemeDefault = "default"
+	// ThemeLight are the colors of the light terminals, without yellow, cyan and white
+	ThemeLight = "light"
+	// ThemeHighContrast is the bold default color of the terminal, the errors in red,
+	// and underlines instead of colors telling the answers and the warnings apart
+	ThemeHighContrast = "high-contrast"
+
+	// surveyRole is the prefix of the icon formats rendered with the theme colors
+	surveyRole = "rit:"
+)
+
+// The roles of the theme colors: the messages, the survey prompt parts and icons
+const (
+	RoleError    = "error"
+	RoleSuccess  = "success"
+	RoleWarning  = "warning"
+	RoleInfo     = "info"
+	RoleDebug    = "debug"
+	RoleQuestion = "question"
+	RoleMessage  = "message"
+	RoleAnswer   = "answer"
+	RoleDefault  = "default"
+	RoleSelect   = "select"
+	RoleMarked   = "marked"
+	RoleUnmarked = "unmarked"
+)
+
+var (
+	// Themes are the themes of the output and the prompts by name
+	Themes = map[string]Theme{
+		ThemeDefault: {
+			Colors: map[string]string{
+				RoleError: "red", RoleSuccess: "green+bold", RoleWarning: "yellow+bold", RoleInfo: "bold",
+				RoleDebug: "gray", RoleQuestion: "lightGreen+bold", RoleMessage: "bold", RoleAnswer: "cyan",
+				RoleDefault: "white", RoleSelect: "cyan+bold", RoleMarked: "green", RoleUnmarked: "bold",
+			},
+			Symbols: defaultSymbols,
+		},
+		ThemeLight: {
+			Colors: map[string]string{
+				RoleError: "red", RoleSuccess: "green+bold", RoleWarning: "magenta+bold", RoleInfo: "bold",
+				RoleDebug: "gray", RoleQuestion: "green+bold", RoleMessage: "bold", RoleAnswer: "blue",
+				RoleDefault: "black", RoleSelect: "blue+bold", RoleMarked: "green+bold", RoleUnmarked: "black",
+			},
+			Symbols: defaultSymbols,
+		},
+		ThemeHighContrast: {
+			Colors: map[string]string{
+				RoleError: "red+bold", RoleSuccess: "bold", RoleWarning: "bold+underscore", RoleInfo: "bold",
+				RoleDebug: "default", RoleQuestion: "bold", RoleMessage: "bold", RoleAnswer: "bold+underscore",
+				RoleDefault: "underscore", RoleSelect: "bold+reverse", RoleMarked: "bold", RoleUnmarked: "default",
+			},
+			Symbols: map[string]string{
+				RoleQuestion: "?", RoleError: "X", RoleSelect: "=>", RoleMarked: "[*]", RoleUnmarked: "[ ]",
+			},
+		},
+	}
+
+	defaultSymbols = map[string]string{
+		RoleQuestion: "?", RoleError: "X", RoleSelect: ">", RoleMarked: "[x]", RoleUnmarked: "[ ]",
+	}
+
+	// surveyColors are the colors hard-coded on the survey templates and their roles
+	surveyColors = map[string]string{
+		"default+hb": RoleMessage,
+		"cyan":       RoleAnswer,
+		"white":      RoleDefault,
+	}
+
+	styles       map[string]color.Style
+	symbols      map[string]string
+	surveyFormat = core.TemplateFuncsWithColor["color"].(func(string) string)
+)
+
+func init() {
+	styles, symbols, _ = loadTheme(ThemeDefault, nil, nil)
+}
+
+// Theme are the colors and the symbols of the output and the prompts by role. A color is
+// a name, black, red, green, yellow, blue, magenta, cyan, white, gray, default or one of
+// the light ones as lightRed, with the options joined by +, e.g. red+bold or bold+underscore.
+type Theme struct {
+	Colors  map[string]string
+	Symbols map[string]string
+}
+
+// SetTheme sets the theme of the output and the prompts, an empty name is the default theme.
+// The colors and the symbols of its roles are replaced by the role=value overrides,
+// e.g. warning=magenta or select=>>. The colors hard-coded on the survey templates are
+// rendered with the theme from then on.
+func SetTheme(name string, colorOverrides, symbolOverrides []string) error {
+	st, syms, err := loadTheme(name, colorOverrides, symbolOverrides)
+	if err != nil {
+		return err
+	}
+	styles, symbols = st, syms
+	core.TemplateFuncsWithColor["color"] = themeFormat
+	return nil
+}
+
+// loadTheme returns the styles and the symbols of the theme with the overrides
+func loadTheme(name string, colorOverrides, symbolOverrides []string) (map[string]color.Style, map[string]string, error) {
+	if name == "" {
+		name = ThemeDefault
+	}
+	t, ok := Themes[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid theme %q, use %s", name, strings.Join(themeNames(), ", "))
+	}
+
+	colors, err := override(t.Colors, colorOverrides)
+	if err != nil {
+		return nil, nil, err
+	}
+	syms, err := override(t.Symbols, symbolOverrides)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	st := make(map[string]color.Style, len(colors))
+	for role, c := range colors {
+		if st[role], err = parseStyle(c); err != nil {
+			return nil, nil, fmt.Errorf("invalid color %q of the theme role %s: %v", c, role, err)
+		}
+	}
+	return st, syms, nil
+}
+
+// override returns the values with the role=value overrides, only of the roles of the values
+func override(values map[string]string, overrides []string) (map[string]string, error) {
+	result := make(map[string]string, len(values))
+	for role, v := range values {
+		result[role] = v
+	}
+	for _, o := range overrides {
+		kv := strings.SplitN(o, "=", 2)
+		role := strings.TrimSpace(kv[0])
+		if _, ok := values[role]; !ok || len(kv) != 2 {
+			return nil, fmt.Errorf("invalid theme override %q, use role=value with the roles %s", o, strings.Join(keys(values), ", "))
+		}
+		result[role] = strings.TrimSpace(kv[1])
+	}
+	return result, nil
+}
+
+// parseStyle parses the color name and the options joined by +
+func parseStyle(c string) (color.Style, error) {
+	var s color.Style
+	for _, name := range strings.Split(c, "+") {
+		if v, ok := color.FgColors[name]; ok {
+			s = append(s, v)
+		} else if v, ok := color.ExFgColors[name]; ok {
+			s = append(s, v)
+		} else if v, ok := color.Options[name]; ok && name != "reset" {
+			s = append(s, v)
+		} else if name == "gray" {
+			s = append(s, color.FgGray)
+		} else {
+			return nil, fmt.Errorf("unknown color %q", name)
+		}
+	}
+	return s, nil
+}
+
+// render renders the text with the color of the role
+func render(role, text string) string {
+	return styles[role].Render(text)
+}
+
+// themeFormat is the color func of the survey templates, it renders the icons and the
+// colors hard-coded on the templates with the theme and nothing when colors are disabled
+func themeFormat(format string) string {
+	if !color.Enable {
+		return ""
+	}
+
+	role, ok := surveyColors[format]
+	if strings.HasPrefix(format, surveyRole) {
+		role, ok = strings.TrimPrefix(format, surveyRole), true
+	}
+	if !ok {
+		return surveyFormat(format)
+	}
+	return fmt.Sprintf("\x1b[%sm", styles[role].String())
+}
+
+// withIcons sets the symbols of the theme on the survey prompts, colored with their roles
+func withIcons(icons *survey.IconSet) {
+	icon := func(role string) survey.Icon {
+		return survey.Icon{Text: symbols[role], Format: surveyRole + role}
+	}
+	icons.Question = icon(RoleQuestion)
+	icons.Error = icon(RoleError)
+	icons.SelectFocus = icon(RoleSelect)
+	icons.MarkedOption = icon(RoleMarked)
+	icons.UnmarkedOption = icon(RoleUnmarked)
+}
+
+func themeNames() []string {
+	var names []string
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func keys(m map[string]string) []string {
+	var names []string
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
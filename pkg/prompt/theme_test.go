@@ -0,0 +1,91 @@
+package prompt
+
+import (
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2/core"
+	"github.com/gookit/color"
+)
+
+func TestSetTheme(t *testing.T) {
+	enable := color.Enable
+	color.Enable = true
+	defer func() {
+		color.Enable = enable
+		_ = SetTheme(ThemeDefault, nil, nil)
+	}()
+
+	tests := []struct {
+		name       string
+		theme      string
+		colors     []string
+		symbols    []string
+		wantError  string
+		wantWarn   string
+		wantSelect string
+		wantErr    bool
+	}{
+		{name: "default", theme: "", wantError: "\x1b[31mtext\x1b[0m", wantWarn: "\x1b[33;1mtext\x1b[0m", wantSelect: ">"},
+		{name: "light", theme: ThemeLight, wantError: "\x1b[31mtext\x1b[0m", wantWarn: "\x1b[35;1mtext\x1b[0m", wantSelect: ">"},
+		{name: "high contrast", theme: ThemeHighContrast, wantError: "\x1b[31;1mtext\x1b[0m", wantWarn: "\x1b[1;4mtext\x1b[0m", wantSelect: "=>"},
+		{
+			name: "overrides", theme: ThemeDefault, colors: []string{"warning=blue+underscore"}, symbols: []string{"select=>>"},
+			wantError: "\x1b[31mtext\x1b[0m", wantWarn: "\x1b[34;4mtext\x1b[0m", wantSelect: ">>",
+		},
+		{name: "unknown theme", theme: "solarized", wantErr: true},
+		{name: "unknown role", theme: ThemeDefault, colors: []string{"title=blue"}, wantErr: true},
+		{name: "override without a value", theme: ThemeDefault, symbols: []string{"select"}, wantErr: true},
+		{name: "unknown color", theme: ThemeDefault, colors: []string{"error=crimson"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SetTheme(tt.theme, tt.colors, tt.symbols)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetTheme(%s) got %v, wantErr %v", tt.theme, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := Red("text"); got != tt.wantError {
+				t.Errorf("SetTheme(%s) error color got %q, want %q", tt.name, got, tt.wantError)
+			}
+			if got := Yellow("text"); got != tt.wantWarn {
+				t.Errorf("SetTheme(%s) warning color got %q, want %q", tt.name, got, tt.wantWarn)
+			}
+			if got := symbols[RoleSelect]; got != tt.wantSelect {
+				t.Errorf("SetTheme(%s) select symbol got %q, want %q", tt.name, got, tt.wantSelect)
+			}
+		})
+	}
+}
+
+func TestThemeFormat(t *testing.T) {
+	enable := color.Enable
+	defer func() {
+		color.Enable = enable
+		_ = SetTheme(ThemeDefault, nil, nil)
+	}()
+	_ = SetTheme(ThemeLight, nil, nil)
+
+	// the survey templates render their colors with the theme once it is set
+	surveyColor := core.TemplateFuncsWithColor["color"].(func(string) string)
+	color.Enable = true
+	tests := map[string]string{
+		"white":               "\x1b[30m",
+		"cyan":                "\x1b[34m",
+		surveyRole + "select": "\x1b[34;1m",
+		"reset":               "\x1b[0m",
+	}
+	for format, want := range tests {
+		if got := surveyColor(format); got != want {
+			t.Errorf("survey color(%s) got %q, want %q", format, got, want)
+		}
+	}
+
+	color.Enable = false
+	if got := themeFormat("white"); got != "" {
+		t.Errorf("themeFormat(white) without colors got %q, want empty", got)
+	}
+}
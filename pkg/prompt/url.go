@@ -26,5 +26,5 @@ func (SurveyURL) URL(name, defaultValue string) (string, error) {
 		},
 	}
 
-	return value, survey.Ask(validationQs, &value)
+	return value, ask(validationQs, &value)
 }
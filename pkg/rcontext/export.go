@@ -0,0 +1,149 @@
+package rcontext
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/slice/sliceutil"
+)
+
+var (
+	ErrContextNotFound    = errors.New("context not found")
+	ErrContextExists      = errors.New("context already exists, use --overwrite to import it anyway")
+	ErrInvalidContextName = errors.New("invalid context name")
+	ErrInvalidCredential  = errors.New("invalid credential reference")
+
+	// validName are the names of contexts and credentials, they are paths on the rit home
+	validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+)
+
+type (
+	// ContextExport is a context of rit context export. Its credentials are only
+	// referenced by their service names, the secret values are never exported.
+	ContextExport struct {
+		Name        string   `json:"name"`
+		Credentials []string `json:"credentials,omitempty"`
+	}
+
+	// ExportFile is the file written by rit context export and read by rit context import
+	ExportFile struct {
+		Contexts []ContextExport `json:"contexts"`
+	}
+)
+
+// CredentialLister lists the services with a credential set on a context
+type CredentialLister interface {
+	Services(ctx string) ([]string, error)
+}
+
+type Exporter interface {
+	Export(names ...string) (ExportFile, error)
+}
+
+type Importer interface {
+	Import(f ExportFile, overwrite bool) (map[string][]string, error)
+}
+
+// ExportManager exports and imports the contexts, the credentials are listed by
+// the CredentialLister, a nil one lists none as the team credentials live on the server
+type ExportManager struct {
+	ctxFile string
+	finder  Finder
+	creds   CredentialLister
+}
+
+func NewExportManager(homePath string, f Finder, cl CredentialLister) ExportManager {
+	return ExportManager{ctxFile: fmt.Sprintf(ContextPath, homePath), finder: f, creds: cl}
+}
+
+// Export exports the named contexts, or the default and all the other contexts when no name is informed
+func (e ExportManager) Export(names ...string) (ExportFile, error) {
+	ctxHolder, err := e.finder.Find()
+	if err != nil {
+		return ExportFile{}, err
+	}
+
+	if len(names) == 0 {
+		names = append([]string{DefaultCtx}, ctxHolder.All...)
+	}
+
+	f := ExportFile{Contexts: make([]ContextExport, 0, len(names))}
+	for _, name := range names {
+		if name != DefaultCtx && !sliceutil.Contains(ctxHolder.All, name) {
+			return ExportFile{}, fmt.Errorf("%w: %s", ErrContextNotFound, name)
+		}
+
+		creds, err := e.services(name)
+		if err != nil {
+			return ExportFile{}, err
+		}
+		f.Contexts = append(f.Contexts, ContextExport{Name: name, Credentials: creds})
+	}
+	return f, nil
+}
+
+// Import adds the contexts of the export file, the current context is kept. Nothing is
+// imported when a name or credential reference is invalid or, without overwrite, when a
+// context already exists. The store only keeps the context names, so an overwritten context
+// keeps its credentials. It returns the credentials referenced by each context that aren't
+// set on it yet.
+func (e ExportManager) Import(f ExportFile, overwrite bool) (map[string][]string, error) {
+	ctxHolder, err := e.finder.Find()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range f.Contexts {
+		if !validName.MatchString(c.Name) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidContextName, c.Name)
+		}
+		for _, cred := range c.Credentials {
+			if !validName.MatchString(cred) {
+				return nil, fmt.Errorf("%w: %q on the context %s", ErrInvalidCredential, cred, c.Name)
+			}
+		}
+		if !overwrite && sliceutil.Contains(ctxHolder.All, c.Name) {
+			return nil, fmt.Errorf("%w: %s", ErrContextExists, c.Name)
+		}
+	}
+
+	missing := make(map[string][]string)
+	for _, c := range f.Contexts {
+		if c.Name != DefaultCtx && !sliceutil.Contains(ctxHolder.All, c.Name) {
+			ctxHolder.All = append(ctxHolder.All, c.Name)
+		}
+		if e.creds == nil {
+			continue
+		}
+
+		set, err := e.services(c.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, cred := range c.Credentials {
+			if !sliceutil.Contains(set, cred) {
+				missing[c.Name] = append(missing[c.Name], cred)
+			}
+		}
+	}
+
+	b, err := json.Marshal(&ctxHolder)
+	if err != nil {
+		return nil, err
+	}
+	if err := fileutil.WriteFilePerm(e.ctxFile, b, 0600); err != nil {
+		return nil, err
+	}
+
+	return missing, nil
+}
+
+func (e ExportManager) services(ctx string) ([]string, error) {
+	if e.creds == nil {
+		return nil, nil
+	}
+	return e.creds.Services(ctx)
+}
@@ -0,0 +1,107 @@
+package rcontext
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+type credListerMock map[string][]string
+
+func (c credListerMock) Services(ctx string) ([]string, error) {
+	return c[ctx], nil
+}
+
+func TestExportImport(t *testing.T) {
+	home, err := ioutil.TempDir("", "rit-context-export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	finder := NewFinder(home)
+	setter := NewSetter(home, finder)
+	for _, ctx := range []string{dev, qa} {
+		if _, err := setter.Set(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	creds := credListerMock{DefaultCtx: {"github"}, dev: {"aws", "github"}}
+	e := NewExportManager(home, finder, creds)
+
+	t.Run("export all", func(t *testing.T) {
+		got, err := e.Export()
+		want := ExportFile{Contexts: []ContextExport{
+			{Name: DefaultCtx, Credentials: []string{"github"}},
+			{Name: dev, Credentials: []string{"aws", "github"}},
+			{Name: qa},
+		}}
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("Export() = %+v, %v, want %+v", got, err, want)
+		}
+	})
+
+	t.Run("export unknown", func(t *testing.T) {
+		if _, err := e.Export(dev, "prod"); !errors.Is(err, ErrContextNotFound) {
+			t.Errorf("Export(prod) error = %v, want %v", err, ErrContextNotFound)
+		}
+	})
+
+	tests := []struct {
+		name        string
+		file        ExportFile
+		overwrite   bool
+		wantErr     error
+		wantMissing map[string][]string
+		wantAll     []string
+	}{
+		{
+			name:    "existing context",
+			file:    ExportFile{Contexts: []ContextExport{{Name: "prod"}, {Name: dev}}},
+			wantErr: ErrContextExists,
+			wantAll: []string{dev, qa},
+		},
+		{
+			name:    "invalid name",
+			file:    ExportFile{Contexts: []ContextExport{{Name: "../prod"}}},
+			wantErr: ErrInvalidContextName,
+			wantAll: []string{dev, qa},
+		},
+		{
+			name:    "invalid credential",
+			file:    ExportFile{Contexts: []ContextExport{{Name: "prod", Credentials: []string{"aws/../../x"}}}},
+			wantErr: ErrInvalidCredential,
+			wantAll: []string{dev, qa},
+		},
+		{
+			name: "overwrite",
+			file: ExportFile{Contexts: []ContextExport{
+				{Name: "prod", Credentials: []string{"aws"}},
+				{Name: dev, Credentials: []string{"aws", "jenkins"}},
+			}},
+			overwrite:   true,
+			wantMissing: map[string][]string{"prod": {"aws"}, dev: {"jenkins"}},
+			wantAll:     []string{dev, qa, "prod"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missing, err := e.Import(tt.file, tt.overwrite)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Import() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(missing, tt.wantMissing) {
+				t.Errorf("Import() missing = %v, want %v", missing, tt.wantMissing)
+			}
+
+			got, _ := finder.Find()
+			if !reflect.DeepEqual(got.All, tt.wantAll) || got.Current != qa {
+				t.Errorf("Import() contexts = %+v, want %v with the current %s", got, tt.wantAll, qa)
+			}
+		})
+	}
+}
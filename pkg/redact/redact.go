@@ -0,0 +1,109 @@
+// Package redact masks the secrets of the formula runs before they are written
+// anywhere rit keeps history, as the run logs read by rit logs.
+package redact
+
+import (
+	"sort"
+	"strings"
+)
+
+// Mask replaces the redacted values
+const Mask = "******"
+
+// minSecretLen avoids masking every occurrence of tiny values, as "1" or "y"
+const minSecretLen = 3
+
+// sensitiveNames are the name fragments of flags and keys holding secrets,
+// e.g. --password, --api-key, --set db.token=value
+var sensitiveNames = []string{"password", "passwd", "secret", "token", "key", "credential", "auth"}
+
+// Redactor masks the known secret values and the values of sensitive args.
+// The zero value only masks the sensitive args.
+type Redactor struct {
+	secrets []string
+}
+
+// New creates a redactor masking the secret values
+func New(secrets ...string) Redactor {
+	var r Redactor
+	r.Add(secrets...)
+	return r
+}
+
+// Add adds secret values to be masked, the longest ones are masked first
+// so that a secret containing another one is fully masked
+func (r *Redactor) Add(secrets ...string) {
+	for _, s := range secrets {
+		if len(s) >= minSecretLen {
+			r.secrets = append(r.secrets, s)
+		}
+	}
+	sort.Slice(r.secrets, func(i, j int) bool {
+		return len(r.secrets[i]) > len(r.secrets[j])
+	})
+}
+
+// String masks the secret values of s
+func (r Redactor) String(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, Mask)
+	}
+	return s
+}
+
+// Args masks the secret values and the values of the sensitive args, as
+// --password value, --password=value and name=value with a sensitive name
+func (r Redactor) Args(args []string) []string {
+	masked := make([]string, len(args))
+	maskNext := false
+	for i, a := range args {
+		switch {
+		case maskNext:
+			masked[i] = Mask
+			maskNext = false
+		case strings.HasPrefix(a, "-") && !strings.Contains(a, "="):
+			masked[i] = a
+			maskNext = IsSensitive(strings.TrimLeft(a, "-")) && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-")
+		default:
+			masked[i] = r.String(maskAssignment(a))
+		}
+	}
+	return masked
+}
+
+// Map masks the values of the sensitive keys and the secret values, e.g. of the run labels
+func (r Redactor) Map(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	masked := make(map[string]string, len(m))
+	for k, v := range m {
+		if IsSensitive(k) {
+			masked[k] = Mask
+			continue
+		}
+		masked[k] = r.String(v)
+	}
+	return masked
+}
+
+// IsSensitive tells whether the flag or key name looks like it holds a secret
+func IsSensitive(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range sensitiveNames {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskAssignment masks the value of name=value and --name=value when the name is sensitive
+func maskAssignment(a string) string {
+	i := strings.Index(a, "=")
+	if i <= 0 || !IsSensitive(strings.TrimLeft(a[:i], "-")) {
+		return a
+	}
+	return a[:i+1] + Mask
+}
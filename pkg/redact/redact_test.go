@@ -0,0 +1,61 @@
+package redact
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactor(t *testing.T) {
+	r := New("abc", "abcdef", "x")
+
+	if got := r.String("abcdef abc x"); got != Mask+" "+Mask+" x" {
+		t.Errorf("String() got %q, want the longest secret masked first and the tiny one kept", got)
+	}
+
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{
+			name: "sensitive flag value",
+			in:   []string{"--password", "p4ss", "--name", "bucket"},
+			want: []string{"--password", Mask, "--name", "bucket"},
+		},
+		{
+			name: "sensitive flag without value",
+			in:   []string{"--api-key", "--verbose"},
+			want: []string{"--api-key", "--verbose"},
+		},
+		{
+			name: "assignments",
+			in:   []string{"--secret=p4ss", "db.token=t0k3n", "region=us-east-1"},
+			want: []string{"--secret=" + Mask, "db.token=" + Mask, "region=us-east-1"},
+		},
+		{
+			name: "known secrets",
+			in:   []string{"--name", "abcdef", "url=http://abc@host"},
+			want: []string{"--name", Mask, "url=http://" + Mask + "@host"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Args(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Args() got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactorMap(t *testing.T) {
+	r := New("s3cr3t")
+	got := r.Map(map[string]string{"ci": "123", "api_token": "t0k3n", "url": "http://s3cr3t@host"})
+	want := map[string]string{"ci": "123", "api_token": Mask, "url": "http://" + Mask + "@host"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Map() got %v, want %v", got, want)
+	}
+
+	if r.Map(nil) != nil {
+		t.Error("Map(nil) must be nil")
+	}
+}
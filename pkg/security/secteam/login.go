@@ -43,7 +43,7 @@ func (l LoginManager) Login(user security.User) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println("Organization:", cfg.Organization)
+	prompt.Print("Organization: " + cfg.Organization)
 
 	url := fmt.Sprintf(urlLoginPattern, cfg.URL)
 
@@ -35,6 +35,12 @@ type FileCopyExistLister interface {
 	FileExister
 }
 
+type FileReadWriteCopyExistLister interface {
+	FileReader
+	FileWriter
+	FileCopyExistLister
+}
+
 type FileReadExister interface {
 	FileReader
 	FileExister
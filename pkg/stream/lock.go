@@ -0,0 +1,78 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+const (
+	lockExt        = ".lock"
+	msgLockTimeout = "another rit is still writing %s, try again once it is done"
+)
+
+var (
+	// LockTimeout is how long Lock waits for the other rit processes holding the lock
+	LockTimeout = 30 * time.Second
+	lockRetry   = 100 * time.Millisecond
+)
+
+// LockPath is the advisory lock file of path, e.g. repositories.lock for repositories.json
+func LockPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + lockExt
+}
+
+// Lock takes the advisory lock of path, so two rit processes, e.g. the jobs of a CI matrix,
+// don't write the file or the dir of path at the same time. It waits for the process
+// holding the lock up to LockTimeout. The lock is released by the returned func.
+func Lock(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	lock := flock.New(LockPath(path))
+	ctx, cancel := context.WithTimeout(context.Background(), LockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, lockRetry)
+	if !locked {
+		// closes the lock file the attempts opened
+		_ = lock.Close()
+	}
+	if err == context.DeadlineExceeded || (err == nil && !locked) {
+		return nil, fmt.Errorf(msgLockTimeout, path)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		_ = lock.Unlock()
+	}, nil
+}
+
+// WriteAtomic writes content to a temp file next to path then renames it over path,
+// so the rit processes reading path never read it partially written
+func WriteAtomic(path string, content []byte, perm os.FileMode) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,67 @@
+package stream
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(d time.Duration) { LockTimeout = d }(LockTimeout)
+	LockTimeout = 300 * time.Millisecond
+
+	tree := filepath.Join(dir, "cache", "aws-tree.json")
+	unlock, err := Lock(tree)
+	if err != nil {
+		t.Fatalf("Lock() got %v, want nil", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cache", "aws-tree.lock")); err != nil {
+		t.Errorf("Lock() got no lock file: %v", err)
+	}
+
+	if _, err := Lock(tree); err == nil || !strings.Contains(err.Error(), "another rit is still writing") {
+		t.Errorf("Lock() of a locked file got %v, want the lock timeout", err)
+	}
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		unlock()
+	}()
+	unlock, err = Lock(tree)
+	if err != nil {
+		t.Fatalf("Lock() got %v, want the lock once it is released", err)
+	}
+	unlock()
+}
+
+func TestWriteAtomic(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rit-lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tree := filepath.Join(dir, "tree.json")
+	if err := ioutil.WriteFile(tree, []byte(`{"commands":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"version":"2.0.0","commands":[]}`
+	if err := WriteAtomic(tree, []byte(want), 0644); err != nil {
+		t.Fatalf("WriteAtomic() got %v, want nil", err)
+	}
+
+	if b, _ := ioutil.ReadFile(tree); string(b) != want {
+		t.Errorf("WriteAtomic() wrote %q, want %q", b, want)
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 1 {
+		t.Errorf("WriteAtomic() left %d files, want only the tree", len(files))
+	}
+}
@@ -12,8 +12,10 @@ type Manager interface {
 	Run(upgradeUrl string) error
 }
 
+// DefaultManager downloads the new version with the HttpClient, http.DefaultClient when nil
 type DefaultManager struct {
 	Updater
+	HttpClient *http.Client
 }
 
 func (m DefaultManager) Run(upgradeUrl string) error {
@@ -21,7 +23,12 @@ func (m DefaultManager) Run(upgradeUrl string) error {
 		return errors.New("fail to resolve upgrade url")
 	}
 
-	resp, err := http.Get(upgradeUrl)
+	client := m.HttpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(upgradeUrl)
 	if err != nil {
 		return errors.New("fail to download stable version")
 	}
@@ -19,6 +19,10 @@ func (r stubResolver) StableVersion() (string, error) {
 	return r.stableVersion()
 }
 
+func (r stubResolver) RemoteStableVersion() (string, error) {
+	return r.stableVersion()
+}
+
 func (r stubResolver) UpdateCache() error {
 	return r.updateCache()
 }
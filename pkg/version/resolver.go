@@ -2,5 +2,7 @@ package version
 
 type Resolver interface {
 	StableVersion() (string, error)
+	// RemoteStableVersion fetches the stable version skipping the cache, which is updated with it
+	RemoteStableVersion() (string, error)
 	UpdateCache() error
 }
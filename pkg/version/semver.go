@@ -0,0 +1,93 @@
+package version
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Bump is the kind of change between two versions
+type Bump int
+
+const (
+	NoBump Bump = iota
+	PatchBump
+	MinorBump
+	MajorBump
+)
+
+var ErrInvalidVersion = errors.New("invalid version, use MAJOR.MINOR.PATCH")
+
+// semver is the major, minor and patch of a version, the pre-release and build suffixes are ignored
+type semver [3]int
+
+// parse parses versions as 2.0.4, v2.0.4 and 2.0.4-beta.1, a missing minor or patch is 0
+func parse(v string) (semver, error) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+
+	parts := strings.Split(v, ".")
+	if len(parts) > 3 {
+		return semver{}, ErrInvalidVersion
+	}
+
+	var s semver
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return semver{}, ErrInvalidVersion
+		}
+		s[i] = n
+	}
+	return s, nil
+}
+
+// Compare returns -1, 0 or 1 as the version a is older, the same or newer than b
+func Compare(a, b string) (int, error) {
+	va, err := parse(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parse(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range va {
+		switch {
+		case va[i] < vb[i]:
+			return -1, nil
+		case va[i] > vb[i]:
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// BumpOf returns the kind of the upgrade from the current version to the newer one,
+// NoBump when the newer version isn't newer
+func BumpOf(current, newer string) (Bump, error) {
+	c, err := parse(current)
+	if err != nil {
+		return NoBump, err
+	}
+	n, err := parse(newer)
+	if err != nil {
+		return NoBump, err
+	}
+
+	if cmp, _ := Compare(current, newer); cmp >= 0 {
+		return NoBump, nil
+	}
+
+	switch {
+	case n[0] != c[0]:
+		return MajorBump, nil
+	case n[1] != c[1]:
+		return MinorBump, nil
+	default:
+		return PatchBump, nil
+	}
+}
@@ -0,0 +1,45 @@
+package version
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		want    int
+		wantErr bool
+	}{
+		{a: "1.0.0", b: "1.0.0", want: 0},
+		{a: "v1.2.3", b: "1.2.3", want: 0},
+		{a: "1.2.3", b: "1.10.0", want: -1},
+		{a: "2.0", b: "1.9.9", want: 1},
+		{a: "2.0.4-beta.1", b: "2.0.4", want: 0},
+		{a: "dev", b: "1.0.0", wantErr: true},
+		{a: "1.0.0.1", b: "1.0.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Compare(tt.a, tt.b)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, %v, want %d", tt.a, tt.b, got, err, tt.want)
+		}
+	}
+}
+
+func TestBumpOf(t *testing.T) {
+	tests := []struct {
+		current, newer string
+		want           Bump
+	}{
+		{current: "1.0.0", newer: "1.0.1", want: PatchBump},
+		{current: "1.0.9", newer: "1.1.0", want: MinorBump},
+		{current: "1.9.9", newer: "2.0.0", want: MajorBump},
+		{current: "2.0.0", newer: "1.9.9", want: NoBump},
+		{current: "2.0.0", newer: "2.0.0", want: NoBump},
+	}
+
+	for _, tt := range tests {
+		if got, err := BumpOf(tt.current, tt.newer); err != nil || got != tt.want {
+			t.Errorf("BumpOf(%q, %q) = %v, %v, want %v", tt.current, tt.newer, got, err, tt.want)
+		}
+	}
+}
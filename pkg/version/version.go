@@ -2,6 +2,8 @@ package version
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -9,13 +11,33 @@ import (
 
 	"github.com/ZupIT/ritchie-cli/pkg/api"
 	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/prompt"
 )
 
 var (
 	// MsgUpgrade error message to inform user to upgrade rit version
 	MsgRitUpgrade = "\nWarning: Rit has a new stable version.\nPlease run: rit upgrade"
+	// MsgRitMajorUpgrade warns that the new stable version is a new major, which may break formulas and scripts
+	MsgRitMajorUpgrade = "\nWarning: Rit %s is a new major version and may have breaking changes.\n" +
+		"Review the changelog before upgrading: %s\nThen run: rit upgrade"
+	// MsgRitPatchUpgrade notes that the new stable version only has fixes
+	MsgRitPatchUpgrade = "\nRit %s is available with fixes, run: rit upgrade"
+	// ChangelogURL is where the changes of the rit versions are described
+	ChangelogURL = "https://github.com/ZupIT/ritchie-cli/releases"
 	// stableVersionFileCache is the file name to cache stableVersion
 	stableVersionFileCache = "stable-version-cache.json"
+	msgClockSkew           = "The stable version cache was saved in the future, the system clock may be wrong"
+
+	// ErrStableVersionStatus is returned when the stable version server doesn't answer 200 OK
+	ErrStableVersionStatus = errors.New("unable to get the stable version")
+)
+
+const (
+	// cacheTTL is how long the stable version is cached
+	cacheTTL = 10 * time.Hour
+	// clockSkewTolerance is how far in the future a cache may be saved before the clock is taken as skewed,
+	// it absorbs the small drifts of the clock sync
+	clockSkewTolerance = time.Minute
 )
 
 type DefaultVersionResolver struct {
@@ -24,9 +46,26 @@ type DefaultVersionResolver struct {
 	HttpClient       *http.Client
 }
 
+// stableVersionCache is the cached stable version, FetchedAt is missing on the caches saved by older versions
 type stableVersionCache struct {
 	StableVersion string `json:"stableVersion"`
 	ExpiresAt     int64  `json:"expiresAt"`
+	FetchedAt     int64  `json:"fetchedAt,omitempty"`
+}
+
+// fresh tells whether the cache can be used at now. A cache fetched in the future means
+// the clock was wrong when it was saved or is wrong now, it is stale as its TTL can't be trusted.
+func (c stableVersionCache) fresh(now time.Time) bool {
+	fetchedAt := time.Unix(c.FetchedAt, 0)
+	if c.FetchedAt == 0 {
+		fetchedAt = time.Unix(c.ExpiresAt, 0).Add(-cacheTTL)
+	}
+
+	if fetchedAt.After(now.Add(clockSkewTolerance)) {
+		prompt.Warning(msgClockSkew)
+		return false
+	}
+	return now.Before(fetchedAt.Add(cacheTTL))
 }
 
 func (r DefaultVersionResolver) UpdateCache() error {
@@ -41,6 +80,19 @@ func (r DefaultVersionResolver) UpdateCache() error {
 	return err
 }
 
+// RemoteStableVersion fetches the stable version from the server even when the cache is
+// fresh and caches it, the cache is best effort as on StableVersion
+func (r DefaultVersionResolver) RemoteStableVersion() (string, error) {
+	stableVersion, err := requestStableVersion(r.StableVersionUrl, r.HttpClient)
+	if err != nil {
+		return "", err
+	}
+
+	cachePath := api.RitchieHomeDir() + "/" + stableVersionFileCache
+	_ = saveCache(stableVersion, cachePath, r.FileUtilService)
+	return stableVersion, nil
+}
+
 func (r DefaultVersionResolver) StableVersion() (string, error) {
 	cachePath := api.RitchieHomeDir() + "/" + stableVersionFileCache
 	cacheData, err := r.FileUtilService.ReadFile(cachePath)
@@ -50,15 +102,13 @@ func (r DefaultVersionResolver) StableVersion() (string, error) {
 		err = json.Unmarshal(cacheData, cache)
 	}
 
-	if err != nil || cache.ExpiresAt <= time.Now().Unix() {
+	if err != nil || !cache.fresh(time.Now()) {
 		stableVersion, err := requestStableVersion(r.StableVersionUrl, r.HttpClient)
 		if err != nil {
 			return "", err
 		}
-		err = saveCache(stableVersion, cachePath, r.FileUtilService)
-		if err != nil {
-			return "", err
-		}
+		// the cache is best effort, the ritchie home may be read-only
+		_ = saveCache(stableVersion, cachePath, r.FileUtilService)
 		return stableVersion, nil
 	} else {
 		return cache.StableVersion, nil
@@ -76,6 +126,11 @@ func requestStableVersion(stableVersionUrl string, httpClient *http.Client) (str
 	if err != nil {
 		return "", err
 	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: %s", ErrStableVersionStatus, response.Status)
+	}
 	stableVersionBytes, err := ioutil.ReadAll(response.Body)
 	if err != nil {
 		return "", err
@@ -87,9 +142,11 @@ func requestStableVersion(stableVersionUrl string, httpClient *http.Client) (str
 }
 
 func saveCache(stableVersion string, cachePath string, fileUtilService fileutil.Service) error {
+	now := time.Now()
 	newCache := stableVersionCache{
 		StableVersion: stableVersion,
-		ExpiresAt:     time.Now().Add(time.Hour * 10).Unix(),
+		ExpiresAt:     now.Add(cacheTTL).Unix(),
+		FetchedAt:     now.Unix(),
 	}
 
 	newCacheJson, err := json.Marshal(newCache)
@@ -100,13 +157,28 @@ func saveCache(stableVersion string, cachePath string, fileUtilService fileutil.
 	return err
 }
 
+// VerifyNewVersion returns the message telling to upgrade rit when there is a newer stable
+// version, a new major is stressed to review the changelog and a patch is only noted.
+// The versions that aren't MAJOR.MINOR.PATCH, as dev builds, get the upgrade message when they differ.
 func VerifyNewVersion(resolve Resolver, currentVersion string) string {
 	stableVersion, err := resolve.StableVersion()
-	if err != nil {
+	if err != nil || currentVersion == stableVersion {
 		return ""
 	}
-	if currentVersion != stableVersion {
+
+	bump, err := BumpOf(currentVersion, stableVersion)
+	if err != nil {
 		return MsgRitUpgrade
 	}
-	return ""
+
+	switch bump {
+	case MajorBump:
+		return fmt.Sprintf(MsgRitMajorUpgrade, stableVersion, ChangelogURL)
+	case MinorBump:
+		return MsgRitUpgrade
+	case PatchBump:
+		return fmt.Sprintf(MsgRitPatchUpgrade, stableVersion)
+	default:
+		return ""
+	}
 }
@@ -3,6 +3,7 @@ package version
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -20,6 +21,10 @@ func (r StubResolverVersions) StableVersion() (string, error) {
 	return r.stableVersion()
 }
 
+func (r StubResolverVersions) RemoteStableVersion() (string, error) {
+	return r.stableVersion()
+}
+
 func (r StubResolverVersions) UpdateCache() error {
 	return r.updateCache()
 }
@@ -105,8 +110,8 @@ func TestDefaultVersionResolver_StableVersion(t *testing.T) {
 				FileUtilService: StubFileUtilService{
 					readFile: func(_ string) ([]byte, error) {
 						cache := stableVersionCache{
-							"1.4.5",
-							time.Now().Add(time.Hour * 1).Unix(),
+							StableVersion: "1.4.5",
+							ExpiresAt:     time.Now().Add(time.Hour * 1).Unix(),
 						}
 
 						return json.Marshal(cache)
@@ -126,8 +131,8 @@ func TestDefaultVersionResolver_StableVersion(t *testing.T) {
 				FileUtilService: StubFileUtilService{
 					readFile: func(_ string) ([]byte, error) {
 						cache := stableVersionCache{
-							"1.5.0",
-							time.Now().Add(time.Hour * 1 * -1).Unix(),
+							StableVersion: "1.5.0",
+							ExpiresAt:     time.Now().Add(time.Hour * 1 * -1).Unix(),
 						}
 
 						return json.Marshal(cache)
@@ -140,7 +145,7 @@ func TestDefaultVersionResolver_StableVersion(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "Error on save cache",
+			name: "Should get stableVersion when the cache can't be saved",
 			fields: fields{
 				CurrentVersion:   "Any value",
 				StableVersionUrl: mockHttpCase1.URL,
@@ -152,8 +157,8 @@ func TestDefaultVersionResolver_StableVersion(t *testing.T) {
 				},
 				HttpClient: mockHttpCase1.Client(),
 			},
-			want:    "",
-			wantErr: true,
+			want:    expectedResultCase1,
+			wantErr: false,
 		},
 	}
 	for _, tt := range tests {
@@ -175,6 +180,64 @@ func TestDefaultVersionResolver_StableVersion(t *testing.T) {
 	}
 }
 
+func TestDefaultVersionResolver_RemoteStableVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("2.0.0\n"))
+	}))
+	defer server.Close()
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+
+	var saved stableVersionCache
+	files := StubFileUtilService{
+		readFile: func(_ string) ([]byte, error) {
+			t.Error("RemoteStableVersion must not read the cache")
+			return nil, errors.New("some error")
+		},
+		writeFilePerm: func(_ string, content []byte, _ int32) error {
+			return json.Unmarshal(content, &saved)
+		},
+	}
+
+	r := DefaultVersionResolver{StableVersionUrl: server.URL, FileUtilService: files, HttpClient: server.Client()}
+	got, err := r.RemoteStableVersion()
+	if err != nil || got != "2.0.0" {
+		t.Fatalf("RemoteStableVersion() = %q, %v, want 2.0.0", got, err)
+	}
+	if saved.StableVersion != "2.0.0" {
+		t.Errorf("RemoteStableVersion() cached %q, want 2.0.0", saved.StableVersion)
+	}
+
+	r = DefaultVersionResolver{StableVersionUrl: notFound.URL, FileUtilService: files, HttpClient: notFound.Client()}
+	if _, err := r.RemoteStableVersion(); !errors.Is(err, ErrStableVersionStatus) {
+		t.Errorf("RemoteStableVersion() of a 404 error = %v, want %v", err, ErrStableVersionStatus)
+	}
+}
+
+func TestStableVersionCacheFresh(t *testing.T) {
+	now := time.Date(2020, 7, 20, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		cache stableVersionCache
+		want  bool
+	}{
+		{name: "fetched within the TTL", cache: stableVersionCache{FetchedAt: now.Add(-time.Hour).Unix()}, want: true},
+		{name: "fetched before the TTL", cache: stableVersionCache{FetchedAt: now.Add(-11 * time.Hour).Unix()}, want: false},
+		{name: "fetched slightly ahead by the clock sync", cache: stableVersionCache{FetchedAt: now.Add(30 * time.Second).Unix()}, want: true},
+		{name: "fetched in the future", cache: stableVersionCache{FetchedAt: now.Add(24 * time.Hour).Unix()}, want: false},
+		{name: "older cache within the TTL", cache: stableVersionCache{ExpiresAt: now.Add(time.Hour).Unix()}, want: true},
+		{name: "older cache expiring after the TTL", cache: stableVersionCache{ExpiresAt: now.Add(100 * time.Hour).Unix()}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cache.fresh(now); got != tt.want {
+				t.Errorf("fresh() got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestVerifyNewVersion(t *testing.T) {
 	type args struct {
 		resolve        Resolver
@@ -207,6 +270,54 @@ func TestVerifyNewVersion(t *testing.T) {
 				},
 				currentVersion: "1.0.0",
 			},
+			want: fmt.Sprintf(MsgRitPatchUpgrade, "1.0.1"),
+		},
+		{
+			name: "Should return the upgrade msg on a new minor",
+			args: args{
+				resolve: StubResolverVersions{
+					stableVersion: func() (string, error) {
+						return "1.2.0", nil
+					},
+				},
+				currentVersion: "1.1.3",
+			},
+			want: MsgRitUpgrade,
+		},
+		{
+			name: "Should return the major msg on a new major",
+			args: args{
+				resolve: StubResolverVersions{
+					stableVersion: func() (string, error) {
+						return "2.0.0", nil
+					},
+				},
+				currentVersion: "v1.9.2",
+			},
+			want: fmt.Sprintf(MsgRitMajorUpgrade, "2.0.0", ChangelogURL),
+		},
+		{
+			name: "Should return empty when current version is newer than stableVersion",
+			args: args{
+				resolve: StubResolverVersions{
+					stableVersion: func() (string, error) {
+						return "1.0.0", nil
+					},
+				},
+				currentVersion: "1.1.0-beta.1",
+			},
+			want: "",
+		},
+		{
+			name: "Should return the upgrade msg when current version isn't a release",
+			args: args{
+				resolve: StubResolverVersions{
+					stableVersion: func() (string, error) {
+						return "1.0.0", nil
+					},
+				},
+				currentVersion: "dev",
+			},
 			want: MsgRitUpgrade,
 		},
 		{
@@ -1,18 +1,12 @@
 package workspace
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
-	"path/filepath"
-	"strings"
-	"time"
-
-	"github.com/ZupIT/ritchie-cli/pkg/formula"
-
-	"github.com/gofrs/flock"
 
 	"github.com/ZupIT/ritchie-cli/pkg/file/fileutil"
+	"github.com/ZupIT/ritchie-cli/pkg/formula"
+	"github.com/ZupIT/ritchie-cli/pkg/stream"
 )
 
 const (
@@ -32,8 +26,8 @@ func (d DefaultChecker) Check() error {
 	dirRepo := fmt.Sprintf("%s%s", d.ritchieHome, repoDir)
 	repoFile := fmt.Sprintf("%s%s", dirRepo, repoFile)
 
-	if err := fileutil.CreateDirIfNotExists(d.ritchieHome, 0755); err != nil {
-		return err
+	if err := fileutil.CreateDirIfNotExists(d.ritchieHome, 0755); err != nil || !fileutil.IsWritable(d.ritchieHome) {
+		return ReadOnlyHomeError{Path: d.ritchieHome}
 	}
 
 	if err := fileutil.CreateDirIfNotExists(dirRepo, 0755); err != nil {
@@ -44,23 +38,16 @@ func (d DefaultChecker) Check() error {
 		return nil
 	}
 
-	lock := flock.New(strings.Replace(repoFile, filepath.Ext(repoFile), ".lock", 1))
-	lockCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	locked, err := lock.TryLockContext(lockCtx, time.Second)
-	if locked {
-		defer func() {
-			err := lock.Unlock()
-			if err != nil {
-				fmt.Sprintln("Error in Unlock")
-				return
-			}
-		}()
-	}
-
+	unlock, err := stream.Lock(repoFile)
 	if err != nil {
 		return err
 	}
+	defer unlock()
+
+	// another rit may have created it while this one waited for the lock
+	if fileutil.Exists(repoFile) {
+		return nil
+	}
 
 	b, err := json.Marshal(formula.RepositoryFile{})
 	if err != nil {
@@ -2,6 +2,7 @@ package workspace
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"testing"
 )
@@ -21,3 +22,35 @@ func TestCheckWorkingDir(t *testing.T) {
 		t.Errorf("Check got %v, want %v", err, nil)
 	}
 }
+
+func TestCheckReadOnlyHome(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root ignores the directory permissions")
+	}
+
+	roHome := fmt.Sprintf("%s/rit-read-only", os.TempDir())
+	if err := os.MkdirAll(roHome, 0555); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Chmod(roHome, 0555)
+	defer os.RemoveAll(roHome)
+	defer os.Chmod(roHome, 0755)
+
+	err := NewChecker(roHome).Check()
+	if _, ok := err.(ReadOnlyHomeError); !ok {
+		t.Errorf("Check got %v, want %v", err, ReadOnlyHomeError{Path: roHome})
+	}
+}
+
+func TestCheckHomeNotADir(t *testing.T) {
+	file := fmt.Sprintf("%s/rit-home-file", os.TempDir())
+	if err := ioutil.WriteFile(file, []byte{}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file)
+
+	err := NewChecker(file).Check()
+	if _, ok := err.(ReadOnlyHomeError); !ok {
+		t.Errorf("Check got %v, want %v", err, ReadOnlyHomeError{Path: file})
+	}
+}
@@ -1,5 +1,19 @@
 package workspace
 
+import "fmt"
+
+const msgReadOnlyHome = "the rit home %s is not writable, use --home or RITCHIE_HOME to point rit to a writable dir"
+
+// ReadOnlyHomeError is returned when files can't be written in the ritchie home,
+// e.g. on locked-down machines where the user home is read-only
+type ReadOnlyHomeError struct {
+	Path string
+}
+
+func (e ReadOnlyHomeError) Error() string {
+	return fmt.Sprintf(msgReadOnlyHome, e.Path)
+}
+
 type Checker interface {
 	Check() error
 }